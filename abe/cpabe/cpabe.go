@@ -0,0 +1,380 @@
+// Package cpabe implements ciphertext-policy attribute-based encryption
+// in the style of Waters (PKC 2011), as a KEM: a ciphertext embeds a
+// policy over attributes, a secret key is issued for a set of
+// attributes, and decapsulation recovers the content key exactly when
+// the key's attributes satisfy the ciphertext's policy. This is the
+// dual of key-policy ABE (abe/kpabe) and shares its policy machinery:
+// policies come from abe/cpabe/policy, including threshold gates and
+// numeric comparisons, which are compiled to bit attributes internally.
+//
+// The scheme is small-universe: Setup fixes the attribute names (numeric
+// attributes count as one name each). It is written over the Bilinear
+// interface of abe/pairing so any pairing with groups exposed as
+// group.Group can instantiate it.
+package cpabe
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/abe/cpabe/policy"
+	"github.com/cloudflare/circl/abe/pairing"
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/hardening"
+	"github.com/cloudflare/circl/rand"
+)
+
+// BLS12381 instantiates the scheme over the BLS12-381 pairing.
+var BLS12381 = pairing.BLS12381
+
+// KeySize is the length of the content keys produced by the KEM.
+const KeySize = 32
+
+// numericBits is the width numeric attribute values are compiled to.
+const numericBits = 32
+
+// PublicKey is the public parameters of a deployment.
+type PublicKey struct {
+	bl pairing.Bilinear
+	// y is e(g1, g2)^msk.alpha.
+	y pairing.Target
+	// a is g1^msk.a, the base the policy shares ride on.
+	a group.Element
+	// t maps each attribute (after bit expansion) to g1^msk.t[x].
+	t map[string]group.Element
+}
+
+// MasterKey is the authority's key-generation secret.
+type MasterKey struct {
+	bl    pairing.Bilinear
+	alpha group.Scalar
+	a     group.Scalar
+	t     map[string]group.Scalar
+}
+
+// Wipe zeroes the master secret. The key is unusable afterwards.
+func (mk *MasterKey) Wipe() {
+	if mk.alpha != nil {
+		mk.alpha.SetUint64(0)
+		mk.alpha = nil
+	}
+	if mk.a != nil {
+		mk.a.SetUint64(0)
+		mk.a = nil
+	}
+	for n, s := range mk.t {
+		s.SetUint64(0)
+		delete(mk.t, n)
+	}
+}
+
+// AttributeKey is a user's secret key; it embeds the attribute set it
+// was issued for.
+type AttributeKey struct {
+	bl pairing.Bilinear
+	// attrs is the key's attribute set after bit expansion; it is
+	// public.
+	attrs policy.Attributes
+	// k is g2^(alpha + a*u), l is g2^u, and kx maps each attribute to
+	// g2^(t[x]*u), all sharing the per-key randomizer u that defeats
+	// collusion.
+	k  group.Element
+	l  group.Element
+	kx map[string]group.Element
+}
+
+// Wipe overwrites the secret key components with the identity. The key
+// is unusable afterwards.
+func (ak *AttributeKey) Wipe() {
+	id := ak.bl.G2().Identity()
+	if ak.k != nil {
+		ak.k.Set(id)
+		ak.k = nil
+	}
+	if ak.l != nil {
+		ak.l.Set(id)
+		ak.l = nil
+	}
+	for n, e := range ak.kx {
+		e.Set(id)
+		delete(ak.kx, n)
+	}
+}
+
+// Ciphertext is an encapsulation, decryptable by any key whose
+// attributes satisfy its policy.
+type Ciphertext struct {
+	// Policy is the (expanded) policy the ciphertext enforces; it is
+	// public.
+	Policy *policy.Policy
+	// cp is g1^s; ci and di hold, per policy leaf i with share lambda_i
+	// and attribute x, a^lambda_i * t[x]^-r_i and g1^r_i.
+	cp     group.Element
+	ci, di []group.Element
+}
+
+// Setup generates a key pair for the given attribute names. Names used
+// with numeric comparisons are expanded to one attribute per bit.
+func Setup(bl pairing.Bilinear, rnd io.Reader, attrs []string) (*PublicKey, *MasterKey, error) {
+	rnd = rand.Source(rnd)
+	msk := &MasterKey{
+		bl:    bl,
+		alpha: bl.G2().RandomScalar(rnd),
+		a:     bl.G1().RandomNonZeroScalar(rnd),
+		t:     make(map[string]group.Scalar),
+	}
+	pk := &PublicKey{
+		bl: bl,
+		y:  bl.Pair(bl.G1().Generator(), bl.G2().Generator()),
+		a:  hardening.MulGen(bl.G1(), msk.a, rnd),
+		t:  make(map[string]group.Element),
+	}
+	pk.y.Exp(pk.y, msk.alpha)
+	for _, a := range attrs {
+		if _, dup := msk.t[a]; dup {
+			return nil, nil, errors.New("cpabe: duplicate attribute " + a)
+		}
+		// One component for the name itself and one per bit polarity, so
+		// the same name works in presence and comparison leaves.
+		names := []string{a}
+		for i := uint(0); i < numericBits; i++ {
+			names = append(names, policy.BitAttr(a, i, 0), policy.BitAttr(a, i, 1))
+		}
+		for _, n := range names {
+			s := bl.G1().RandomNonZeroScalar(rnd)
+			msk.t[n] = s
+			pk.t[n] = hardening.MulGen(bl.G1(), s, rnd)
+		}
+	}
+
+	return pk, msk, nil
+}
+
+// KeyGen issues a key for a set of attributes fixed at Setup.
+func (msk *MasterKey) KeyGen(rnd io.Reader, attrs policy.Attributes) (*AttributeKey, error) {
+	if msk.alpha == nil {
+		return nil, errors.New("cpabe: master key has been wiped")
+	}
+	rnd = rand.Source(rnd)
+	expanded, err := policy.ExpandAttributes(attrs, numericBits)
+	if err != nil {
+		return nil, err
+	}
+	g2 := msk.bl.G2()
+	u := g2.RandomNonZeroScalar(rnd)
+	e := g2.NewScalar().Mul(msk.a, u)
+	e.Add(e, msk.alpha)
+	key := &AttributeKey{
+		bl:    msk.bl,
+		attrs: expanded,
+		k:     hardening.MulGen(g2, e, rnd),
+		l:     hardening.MulGen(g2, u, rnd),
+		kx:    make(map[string]group.Element, len(expanded)),
+	}
+	for x := range expanded {
+		t, ok := msk.t[x]
+		if !ok {
+			return nil, errors.New("cpabe: unknown attribute " + x)
+		}
+		key.kx[x] = hardening.MulGen(g2, g2.NewScalar().Mul(t, u), rnd)
+	}
+
+	return key, nil
+}
+
+// Encapsulate derives a fresh content key and a ciphertext enforcing the
+// given policy. All attribute names in the policy must have been fixed
+// at Setup.
+func (pk *PublicKey) Encapsulate(rnd io.Reader, p *policy.Policy) ([]byte, *Ciphertext, error) {
+	rnd = rand.Source(rnd)
+	expanded, err := policy.ExpandNumeric(p, numericBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	g1 := pk.bl.G1()
+	s := g1.RandomNonZeroScalar(rnd)
+	shares, err := policy.ShareSecret(rnd, g1, s, expanded)
+	if err != nil {
+		return nil, nil, err
+	}
+	ct := &Ciphertext{
+		Policy: expanded,
+		cp:     g1.NewElement().MulGen(s),
+		ci:     make([]group.Element, len(shares)),
+		di:     make([]group.Element, len(shares)),
+	}
+	for i, sh := range shares {
+		t, ok := pk.t[sh.Attr]
+		if !ok {
+			return nil, nil, errors.New("cpabe: policy uses unknown attribute " + sh.Attr)
+		}
+		r := g1.RandomNonZeroScalar(rnd)
+		c := g1.NewElement().Mul(pk.a, sh.Share)
+		c.Add(c, g1.NewElement().Mul(t, g1.NewScalar().Neg(r)))
+		ct.ci[i] = c
+		ct.di[i] = g1.NewElement().MulGen(r)
+	}
+	ys := pk.bl.NewTarget().Exp(pk.y, s)
+
+	cek, err := kdf(ys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, ct, nil
+}
+
+// Decapsulate recovers the content key when the key's attributes satisfy
+// the ciphertext's policy, and policy.ErrNotSatisfied otherwise.
+func (key *AttributeKey) Decapsulate(ct *Ciphertext) ([]byte, error) {
+	if key.k == nil {
+		return nil, errors.New("cpabe: attribute key has been wiped")
+	}
+	g2 := key.bl.G2()
+	coeffs, err := policy.RecombineCoeffs(g2, key.attrs, ct.Policy)
+	if err != nil {
+		return nil, err
+	}
+	// e(g1^s, k) * Prod (e(ci, l) e(di, kx))^-coeff
+	//   = e(g1, g2)^(s(alpha+a*u)) * e(g1, g2)^(-a*u*Sum coeff*share)
+	//   = e(g1, g2)^(alpha*s).
+	acc := key.bl.Pair(ct.cp, key.k)
+	for _, lc := range coeffs {
+		if lc.Leaf >= uint(len(ct.ci)) {
+			return nil, ErrMalformedCiphertext
+		}
+		kx, ok := key.kx[lc.Attr]
+		if !ok {
+			return nil, errors.New("cpabe: key misses attribute " + lc.Attr)
+		}
+		p := key.bl.Pair(ct.ci[lc.Leaf], key.l)
+		p.Mul(p, key.bl.Pair(ct.di[lc.Leaf], kx))
+		p.Exp(p, g2.NewScalar().Neg(lc.Coeff))
+		acc.Mul(acc, p)
+	}
+
+	return kdf(acc)
+}
+
+func kdf(t pairing.Target) ([]byte, error) {
+	b, err := t.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	cek := sha256.Sum256(b)
+
+	return cek[:], nil
+}
+
+// ctFormat is the version byte leading a serialized ciphertext.
+const ctFormat = 1
+
+// ErrMalformedCiphertext is returned when a serialized ciphertext does
+// not decode canonically.
+var ErrMalformedCiphertext = errors.New("cpabe: malformed ciphertext")
+
+// MarshalBinary encodes the ciphertext as a format byte, the policy, the
+// element g1^s, and the two components of every leaf in leaf order, each
+// element length-prefixed.
+func (ct *Ciphertext) MarshalBinary() ([]byte, error) {
+	pol, err := ct.Policy.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	out := []byte{ctFormat}
+	out = binary.AppendUvarint(out, uint64(len(pol)))
+	out = append(out, pol...)
+	if len(ct.ci) != len(ct.di) {
+		return nil, ErrMalformedCiphertext
+	}
+	elems := make([]group.Element, 0, 1+2*len(ct.ci))
+	elems = append(elems, ct.cp)
+	elems = append(elems, ct.ci...)
+	elems = append(elems, ct.di...)
+	for _, e := range elems {
+		data, err := e.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		out = binary.AppendUvarint(out, uint64(len(data)))
+		out = append(out, data...)
+	}
+
+	return out, nil
+}
+
+// UnmarshalCiphertext decodes a ciphertext produced by
+// Ciphertext.MarshalBinary. The component count must match the policy's
+// leaves and trailing data is rejected.
+func (pk *PublicKey) UnmarshalCiphertext(data []byte) (*Ciphertext, error) {
+	if len(data) == 0 || data[0] != ctFormat {
+		return nil, ErrMalformedCiphertext
+	}
+	pol, in, err := readChunk(data[1:])
+	if err != nil {
+		return nil, err
+	}
+	p := new(policy.Policy)
+	if err := p.UnmarshalBinary(pol); err != nil {
+		return nil, ErrMalformedCiphertext
+	}
+	n := countLeaves(p)
+	ct := &Ciphertext{
+		Policy: p,
+		ci:     make([]group.Element, n),
+		di:     make([]group.Element, n),
+	}
+	readElem := func() (group.Element, error) {
+		var blob []byte
+		if blob, in, err = readChunk(in); err != nil {
+			return nil, err
+		}
+		e := pk.bl.G1().NewElement()
+		if err := e.UnmarshalBinary(blob); err != nil {
+			return nil, ErrMalformedCiphertext
+		}
+		return e, nil
+	}
+	if ct.cp, err = readElem(); err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		if ct.ci[i], err = readElem(); err != nil {
+			return nil, err
+		}
+	}
+	for i := 0; i < n; i++ {
+		if ct.di[i], err = readElem(); err != nil {
+			return nil, err
+		}
+	}
+	if len(in) != 0 {
+		return nil, ErrMalformedCiphertext
+	}
+
+	return ct, nil
+}
+
+func countLeaves(p *policy.Policy) int {
+	if p.IsLeaf() {
+		return 1
+	}
+	n := 0
+	for i := range p.Children {
+		n += countLeaves(&p.Children[i])
+	}
+
+	return n
+}
+
+func readChunk(in []byte) ([]byte, []byte, error) {
+	n, k := binary.Uvarint(in)
+	if k <= 0 || uint64(len(in)-k) < n {
+		return nil, nil, ErrMalformedCiphertext
+	}
+	in = in[k:]
+
+	return in[:n], in[n:], nil
+}
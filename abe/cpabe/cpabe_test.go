@@ -0,0 +1,191 @@
+package cpabe_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/cloudflare/circl/abe/cpabe"
+	"github.com/cloudflare/circl/abe/cpabe/policy"
+)
+
+func TestKEMRoundtrip(t *testing.T) {
+	pk, msk, err := cpabe.Setup(cpabe.BLS12381, rand.Reader, []string{"admin", "audit", "clearance"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := policy.Parse("admin or (audit and clearance >= 3)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cek, ct, err := pk.Encapsulate(rand.Reader, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := msk.KeyGen(rand.Reader, policy.Attributes{"audit": 0, "clearance": 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := key.Decapsulate(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cek, got) {
+		t.Fatal("decapsulated key differs")
+	}
+
+	// The same attributes must fail once the comparison does.
+	low, err := msk.KeyGen(rand.Reader, policy.Attributes{"audit": 0, "clearance": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = low.Decapsulate(ct); !errors.Is(err, policy.ErrNotSatisfied) {
+		t.Fatal("unsatisfied policy not rejected")
+	}
+
+	// The admin branch alone suffices.
+	adm, err := msk.KeyGen(rand.Reader, policy.Attributes{"admin": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err = adm.Decapsulate(ct); err != nil || !bytes.Equal(cek, got) {
+		t.Fatal("admin branch failed")
+	}
+}
+
+func TestCollusion(t *testing.T) {
+	pk, msk, err := cpabe.Setup(cpabe.BLS12381, rand.Reader, []string{"hr", "legal"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := policy.Parse("hr and legal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ct, err := pk.Encapsulate(rand.Reader, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two users who jointly hold the attributes must each fail alone:
+	// their key components carry different randomizers.
+	for _, attrs := range []policy.Attributes{{"hr": 0}, {"legal": 0}} {
+		key, err := msk.KeyGen(rand.Reader, attrs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = key.Decapsulate(ct); !errors.Is(err, policy.ErrNotSatisfied) {
+			t.Fatal("partial attribute set not rejected")
+		}
+	}
+}
+
+func TestThresholdPolicy(t *testing.T) {
+	pk, msk, err := cpabe.Setup(cpabe.BLS12381, rand.Reader, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := policy.Parse("threshold(2, a, b, c)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cek, ct, err := pk.Encapsulate(rand.Reader, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := msk.KeyGen(rand.Reader, policy.Attributes{"a": 0, "c": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := key.Decapsulate(ct)
+	if err != nil || !bytes.Equal(cek, got) {
+		t.Fatal("2-of-3 decapsulation failed")
+	}
+
+	one, err := msk.KeyGen(rand.Reader, policy.Attributes{"b": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = one.Decapsulate(ct); !errors.Is(err, policy.ErrNotSatisfied) {
+		t.Fatal("1-of-3 not rejected")
+	}
+}
+
+func TestCiphertextMarshal(t *testing.T) {
+	pk, msk, err := cpabe.Setup(cpabe.BLS12381, rand.Reader, []string{"x", "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := policy.Parse("x and y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cek, ct, err := pk.Encapsulate(rand.Reader, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := msk.KeyGen(rand.Reader, policy.Attributes{"x": 0, "y": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ct.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct2, err := pk.UnmarshalCiphertext(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := key.Decapsulate(ct2)
+	if err != nil || !bytes.Equal(cek, got) {
+		t.Fatal("decapsulation after marshal roundtrip failed")
+	}
+
+	if _, err = pk.UnmarshalCiphertext(append(data, 0)); err == nil {
+		t.Fatal("trailing data not rejected")
+	}
+
+	errs := 0
+	for i := 0; i < len(data); i += 37 {
+		tampered := append([]byte(nil), data...)
+		tampered[i] ^= 0x40
+		if _, err := pk.UnmarshalCiphertext(tampered); err != nil {
+			errs++
+		}
+	}
+	if errs == 0 {
+		t.Fatal("no tampered ciphertext was rejected")
+	}
+}
+
+func TestWipe(t *testing.T) {
+	pk, msk, err := cpabe.Setup(cpabe.BLS12381, rand.Reader, []string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := policy.Parse("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ct, err := pk.Encapsulate(rand.Reader, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := msk.KeyGen(rand.Reader, policy.Attributes{"a": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msk.Wipe()
+	key.Wipe()
+	if _, err = msk.KeyGen(rand.Reader, policy.Attributes{"a": 0}); err == nil {
+		t.Fatal("wiped master key still issues keys")
+	}
+	if _, err = key.Decapsulate(ct); err == nil {
+		t.Fatal("wiped attribute key still decapsulates")
+	}
+}
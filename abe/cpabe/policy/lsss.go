@@ -0,0 +1,197 @@
+package policy
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/rand"
+)
+
+// This file turns a policy tree into a linear secret-sharing scheme, the
+// machinery both CP-ABE and KP-ABE build on: a k-of-n gate shares its value
+// with a Shamir polynomial among its children, so every leaf ends up with
+// one share, and any satisfying attribute set yields public Lagrange
+// coefficients that recombine the shares linearly. Schemes never recombine
+// the shares themselves — they apply the coefficients in the exponent.
+
+// LeafShare is the share assigned to one leaf of a policy.
+type LeafShare struct {
+	// Leaf is the position of the leaf in a left-to-right traversal of
+	// the tree, identifying the share during recombination.
+	Leaf uint
+	// Attr names the attribute the leaf tests.
+	Attr string
+	// Share is the share material.
+	Share group.Scalar
+}
+
+// LeafCoeff is the recombination coefficient of one leaf: for shares made
+// by ShareSecret and coefficients made by RecombineCoeffs over the same
+// policy, the sum of Coeff*Share over the returned leaves equals the
+// secret.
+type LeafCoeff struct {
+	Leaf  uint
+	Attr  string
+	Coeff group.Scalar
+}
+
+// ShareSecret splits secret along the policy tree and returns the leaf
+// shares in left-to-right order. The policy must be valid.
+func ShareSecret(rnd io.Reader, g group.Group, secret group.Scalar, p *Policy) ([]LeafShare, error) {
+	rnd = rand.Source(rnd)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	var out []LeafShare
+	nextLeaf := uint(0)
+	shareNode(rnd, g, p, secret, &nextLeaf, &out)
+
+	return out, nil
+}
+
+func shareNode(rnd io.Reader, g group.Group, p *Policy, secret group.Scalar, nextLeaf *uint, out *[]LeafShare) {
+	if p.IsLeaf() {
+		*out = append(*out, LeafShare{Leaf: *nextLeaf, Attr: p.Attr, Share: secret.Copy()})
+		*nextLeaf++
+		return
+	}
+	// Shamir polynomial of degree K-1 with constant term secret.
+	coef := make([]group.Scalar, p.K-1)
+	for i := range coef {
+		coef[i] = g.RandomScalar(rnd)
+	}
+	for i := range p.Children {
+		x := g.NewScalar().SetUint64(uint64(i) + 1)
+		v := secret.Copy()
+		xp := g.NewScalar().SetUint64(1)
+		for _, c := range coef {
+			xp.Mul(xp, x)
+			v.Add(v, g.NewScalar().Mul(c, xp))
+		}
+		shareNode(rnd, g, &p.Children[i], v, nextLeaf, out)
+	}
+}
+
+// ErrNotSatisfied is returned when an attribute set does not satisfy the
+// policy.
+var ErrNotSatisfied = errors.New("policy: attributes do not satisfy the policy")
+
+// RecombineCoeffs returns, for an attribute set satisfying p, the leaves a
+// decryptor should use and their recombination coefficients. The
+// coefficients depend only on the tree and on which leaves are satisfied,
+// never on the shares.
+func RecombineCoeffs(g group.Group, attrs Attributes, p *Policy) ([]LeafCoeff, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if !p.Satisfied(attrs) {
+		return nil, ErrNotSatisfied
+	}
+	var out []LeafCoeff
+	nextLeaf := uint(0)
+	one := g.NewScalar().SetUint64(1)
+	coeffNode(g, attrs, p, one, &nextLeaf, &out)
+
+	return out, nil
+}
+
+func coeffNode(g group.Group, attrs Attributes, p *Policy, c group.Scalar, nextLeaf *uint, out *[]LeafCoeff) {
+	if p.IsLeaf() {
+		if c != nil {
+			*out = append(*out, LeafCoeff{Leaf: *nextLeaf, Attr: p.Attr, Coeff: c})
+		}
+		*nextLeaf++
+		return
+	}
+	// Pick the first K satisfied children and compute their Lagrange
+	// coefficients at zero; unused subtrees still advance the leaf counter.
+	var use []int
+	if c != nil {
+		for i := range p.Children {
+			if uint(len(use)) == p.K {
+				break
+			}
+			if p.Children[i].Satisfied(attrs) {
+				use = append(use, i)
+			}
+		}
+	}
+	pos := 0
+	for i := range p.Children {
+		var ci group.Scalar
+		if pos < len(use) && use[pos] == i {
+			ci = lagrangeZero(g, use, pos)
+			ci.Mul(ci, c)
+			pos++
+		}
+		coeffNode(g, attrs, &p.Children[i], ci, nextLeaf, out)
+	}
+}
+
+// lagrangeZero returns the Lagrange coefficient at zero of point use[j]
+// among the points use, where child i sits at abscissa i+1.
+func lagrangeZero(g group.Group, use []int, j int) group.Scalar {
+	xj := g.NewScalar().SetUint64(uint64(use[j]) + 1)
+	num := g.NewScalar().SetUint64(1)
+	den := g.NewScalar().SetUint64(1)
+	for m, im := range use {
+		if m == j {
+			continue
+		}
+		xm := g.NewScalar().SetUint64(uint64(im) + 1)
+		num.Mul(num, xm)
+		den.Mul(den, g.NewScalar().Sub(xm, xj))
+	}
+
+	return num.Mul(num, den.Inv(den))
+}
+
+// shareFormat is the version byte leading a serialized leaf share.
+const shareFormat = 1
+
+// MarshalBinary encodes the share as a format byte, the leaf index, the
+// attribute name, and the share scalar.
+func (ls *LeafShare) MarshalBinary() ([]byte, error) {
+	v, err := ls.Share.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	out := []byte{shareFormat}
+	out = binary.AppendUvarint(out, uint64(ls.Leaf))
+	out = binary.AppendUvarint(out, uint64(len(ls.Attr)))
+	out = append(out, ls.Attr...)
+
+	return append(out, v...), nil
+}
+
+// UnmarshalLeafShare decodes a share produced by LeafShare.MarshalBinary
+// over the group g, rejecting trailing data.
+func UnmarshalLeafShare(g group.Group, data []byte) (LeafShare, error) {
+	errMalformed := errors.New("policy: malformed leaf share")
+	if len(data) == 0 || data[0] != shareFormat {
+		return LeafShare{}, errMalformed
+	}
+	in := data[1:]
+	leaf, k := binary.Uvarint(in)
+	if k <= 0 {
+		return LeafShare{}, errMalformed
+	}
+	in = in[k:]
+	n, k := binary.Uvarint(in)
+	if k <= 0 || uint64(len(in)-k) < n {
+		return LeafShare{}, errMalformed
+	}
+	attr := string(in[k : k+int(n)])
+	in = in[k+int(n):]
+	if len(in) != int(g.Params().ScalarLength) {
+		return LeafShare{}, errMalformed
+	}
+	v := g.NewScalar()
+	if err := v.UnmarshalBinary(in); err != nil {
+		return LeafShare{}, errMalformed
+	}
+
+	return LeafShare{Leaf: uint(leaf), Attr: attr, Share: v}, nil
+}
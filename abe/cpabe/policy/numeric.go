@@ -0,0 +1,163 @@
+package policy
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Pairing-based ABE schemes handle attributes by name only, so numeric
+// comparisons are compiled down to presence attributes over the binary
+// representation of the value (the bag-of-bits technique): an attribute
+// "age" with value v contributes one attribute "age:i:b" per bit, and a
+// comparison leaf becomes a monotone formula over those. This works
+// because a ciphertext or key asserts exactly one of "age:i:0" and
+// "age:i:1" per bit position.
+
+// BitAttr names the attribute asserting that bit i of attr equals b.
+// Schemes need it to enumerate the attribute universe at setup.
+func BitAttr(attr string, i uint, b uint64) string {
+	return attr + ":" + strconv.FormatUint(uint64(i), 10) + ":" + strconv.FormatUint(b, 10)
+}
+
+// ExpandNumeric returns a copy of p with every comparison leaf compiled to
+// a monotone formula over bit attributes of the given width; presence
+// leaves and gates are unchanged. It fails on comparisons no value of that
+// width can satisfy, such as x < 0.
+func ExpandNumeric(p *Policy, bits uint) (*Policy, error) {
+	if bits < 1 || bits > 64 {
+		return nil, errors.New("policy: bit width out of range")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	q, err := expandNode(p, bits)
+	if err != nil {
+		return nil, err
+	}
+	q.Canonicalize()
+
+	return q, nil
+}
+
+func expandNode(p *Policy, bits uint) (*Policy, error) {
+	if !p.IsLeaf() {
+		q := &Policy{K: p.K, Children: make([]Policy, len(p.Children))}
+		for i := range p.Children {
+			c, err := expandNode(&p.Children[i], bits)
+			if err != nil {
+				return nil, err
+			}
+			q.Children[i] = *c
+		}
+		return q, nil
+	}
+	if p.Op == 0 {
+		return &Policy{Attr: p.Attr}, nil
+	}
+	max := uint64(1)<<bits - 1
+	if bits == 64 {
+		max = ^uint64(0)
+	}
+	switch p.Op {
+	case OpEq:
+		if p.Value > max {
+			return nil, errUnsatisfiable
+		}
+		q := &Policy{Children: make([]Policy, bits)}
+		q.K = bits
+		for i := uint(0); i < bits; i++ {
+			q.Children[i] = Policy{Attr: BitAttr(p.Attr, i, p.Value>>i&1)}
+		}
+		return q, nil
+	case OpGe:
+		if p.Value > max {
+			return nil, errUnsatisfiable
+		}
+		return cmpChain(p.Attr, p.Value, bits, true), nil
+	case OpLe:
+		return cmpChain(p.Attr, minU64(p.Value, max), bits, false), nil
+	case OpGt:
+		if p.Value >= max {
+			return nil, errUnsatisfiable
+		}
+		return cmpChain(p.Attr, p.Value+1, bits, true), nil
+	case OpLt:
+		if p.Value == 0 {
+			return nil, errUnsatisfiable
+		}
+		return cmpChain(p.Attr, minU64(p.Value-1, max), bits, false), nil
+	}
+
+	return nil, errors.New("policy: unknown comparison operator")
+}
+
+var errUnsatisfiable = errors.New("policy: comparison is never satisfiable at this bit width")
+
+// cmpChain builds x >= c (ge true) or x <= c (ge false) as a chain over
+// the bits of c, least significant first. For >=, a one bit of c demands
+// the matching bit of x and the lower bits to carry the comparison, while
+// a zero bit is won outright by a one bit of x; <= is the mirror image. A
+// nil running formula stands for "true" and only survives when the
+// remaining comparison is vacuous.
+func cmpChain(attr string, c uint64, bits uint, ge bool) *Policy {
+	want := uint64(1) // bit of x that decides the comparison at once
+	if !ge {
+		want = 0
+	}
+	var rest *Policy
+	for i := uint(0); i < bits; i++ {
+		b := c >> i & 1
+		decide := &Policy{Attr: BitAttr(attr, i, want)}
+		demand := b == 1
+		if !ge {
+			demand = b == 0
+		}
+		switch {
+		case demand && rest == nil:
+			rest = decide
+		case demand:
+			rest = &Policy{K: 2, Children: []Policy{*decide, *rest}}
+		case rest != nil:
+			rest = &Policy{K: 1, Children: []Policy{*decide, *rest}}
+		}
+		// !demand with a vacuous rest stays vacuous: any lower bits do.
+	}
+	if rest == nil {
+		// x >= 0 or x <= max: satisfied by either value of bit zero.
+		rest = &Policy{K: 1, Children: []Policy{
+			{Attr: BitAttr(attr, 0, 0)},
+			{Attr: BitAttr(attr, 0, 1)},
+		}}
+	}
+
+	return rest
+}
+
+// ExpandAttributes returns the presence-attribute view of attrs at the
+// given bit width: every attribute keeps its name and contributes its bit
+// attributes. It fails when a value does not fit the width.
+func ExpandAttributes(attrs Attributes, bits uint) (Attributes, error) {
+	if bits < 1 || bits > 64 {
+		return nil, errors.New("policy: bit width out of range")
+	}
+	out := make(Attributes, len(attrs)*(int(bits)+1))
+	for a, v := range attrs {
+		if bits < 64 && v>>bits != 0 {
+			return nil, errors.New("policy: attribute value exceeds bit width")
+		}
+		out[a] = v
+		for i := uint(0); i < bits; i++ {
+			out[BitAttr(a, i, v>>i&1)] = 0
+		}
+	}
+
+	return out, nil
+}
+
+func minU64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,257 @@
+package policy
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Parse reads a policy from its textual syntax. The grammar, with "and"
+// binding tighter than "or":
+//
+//	expr       = term { "or" term }
+//	term       = factor { "and" factor }
+//	factor     = "(" expr ")" | threshold | leaf
+//	threshold  = "threshold" "(" number "," expr { "," expr } ")"
+//	leaf       = attribute [ op number ]
+//	op         = "=" | "<" | "<=" | ">" | ">="
+//
+// Attributes start with a letter or underscore and may contain letters,
+// digits, and "_.-:". Numbers are unsigned decimal. The returned policy is
+// validated and canonicalized.
+func Parse(s string) (*Policy, error) {
+	ps := &parser{toks: lex(s), src: s}
+	p, err := ps.expr()
+	if err != nil {
+		return nil, err
+	}
+	if ps.peek().kind != tokEOF {
+		return nil, ps.errorf("unexpected %q", ps.peek().text)
+	}
+	if err = p.Validate(); err != nil {
+		return nil, err
+	}
+	p.Canonicalize()
+
+	return p, nil
+}
+
+const (
+	tokEOF = iota
+	tokIdent
+	tokNumber
+	tokOp     // comparison operator
+	tokLParen // (
+	tokRParen // )
+	tokComma  // ,
+	tokBad
+)
+
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+func lex(s string) []token {
+	var toks []token
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			toks = append(toks, token{tokComma, ",", i})
+			i++
+		case c == '=' || c == '<' || c == '>':
+			j := i + 1
+			if j < len(s) && s[j] == '=' {
+				j++
+			}
+			toks = append(toks, token{tokOp, s[i:j], i})
+			i = j
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			toks = append(toks, token{tokNumber, s[i:j], i})
+			i = j
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_':
+			j := i
+			for j < len(s) && (s[j] >= 'a' && s[j] <= 'z' ||
+				s[j] >= 'A' && s[j] <= 'Z' || s[j] >= '0' && s[j] <= '9' ||
+				s[j] == '_' || s[j] == '.' || s[j] == '-' || s[j] == ':') {
+				j++
+			}
+			toks = append(toks, token{tokIdent, s[i:j], i})
+			i = j
+		default:
+			toks = append(toks, token{tokBad, s[i : i+1], i})
+			i++
+		}
+	}
+
+	return append(toks, token{tokEOF, "", len(s)})
+}
+
+type parser struct {
+	toks []token
+	next int
+	src  string
+}
+
+func (ps *parser) peek() token { return ps.toks[ps.next] }
+
+func (ps *parser) take() token {
+	t := ps.toks[ps.next]
+	if t.kind != tokEOF {
+		ps.next++
+	}
+
+	return t
+}
+
+func (ps *parser) errorf(format string, args ...interface{}) error {
+	msg := format
+	if len(args) == 1 {
+		if s, ok := args[0].(string); ok {
+			msg = strings.Replace(format, "%q", strconv.Quote(s), 1)
+		}
+	}
+	pos := ps.peek().pos
+
+	return errors.New("policy: parse error at offset " + strconv.Itoa(pos) + ": " + msg)
+}
+
+func (ps *parser) expr() (*Policy, error) {
+	p, err := ps.term()
+	if err != nil {
+		return nil, err
+	}
+	children := []Policy{*p}
+	for ps.peek().kind == tokIdent && ps.peek().text == "or" {
+		ps.take()
+		q, err := ps.term()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, *q)
+	}
+	if len(children) == 1 {
+		return p, nil
+	}
+
+	return &Policy{K: 1, Children: children}, nil
+}
+
+func (ps *parser) term() (*Policy, error) {
+	p, err := ps.factor()
+	if err != nil {
+		return nil, err
+	}
+	children := []Policy{*p}
+	for ps.peek().kind == tokIdent && ps.peek().text == "and" {
+		ps.take()
+		q, err := ps.factor()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, *q)
+	}
+	if len(children) == 1 {
+		return p, nil
+	}
+
+	return &Policy{K: uint(len(children)), Children: children}, nil
+}
+
+func (ps *parser) factor() (*Policy, error) {
+	t := ps.peek()
+	switch {
+	case t.kind == tokLParen:
+		ps.take()
+		p, err := ps.expr()
+		if err != nil {
+			return nil, err
+		}
+		if ps.peek().kind != tokRParen {
+			return nil, ps.errorf("expected closing parenthesis")
+		}
+		ps.take()
+		return p, nil
+	case t.kind == tokIdent && t.text == "threshold":
+		return ps.threshold()
+	case t.kind == tokIdent:
+		return ps.leaf()
+	}
+
+	return nil, ps.errorf("unexpected %q", t.text)
+}
+
+func (ps *parser) threshold() (*Policy, error) {
+	ps.take() // threshold
+	if ps.peek().kind != tokLParen {
+		return nil, ps.errorf("expected ( after threshold")
+	}
+	ps.take()
+	if ps.peek().kind != tokNumber {
+		return nil, ps.errorf("expected threshold count")
+	}
+	k, err := strconv.ParseUint(ps.take().text, 10, 32)
+	if err != nil {
+		return nil, ps.errorf("threshold count out of range")
+	}
+	var children []Policy
+	for ps.peek().kind == tokComma {
+		ps.take()
+		p, err := ps.expr()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, *p)
+	}
+	if ps.peek().kind != tokRParen {
+		return nil, ps.errorf("expected closing parenthesis")
+	}
+	ps.take()
+	if uint64(len(children)) < 2 || k == 0 || k > uint64(len(children)) {
+		return nil, errors.New("policy: threshold out of range")
+	}
+
+	return &Policy{K: uint(k), Children: children}, nil
+}
+
+func (ps *parser) leaf() (*Policy, error) {
+	attr := ps.take().text
+	p := &Policy{Attr: attr}
+	if ps.peek().kind != tokOp {
+		return p, nil
+	}
+	opTok := ps.take().text
+	for op, text := range opText {
+		if text == opTok || opTok == "==" && op == OpEq {
+			p.Op = op
+		}
+	}
+	if p.Op == 0 {
+		return nil, ps.errorf("unknown operator %q", opTok)
+	}
+	if ps.peek().kind != tokNumber {
+		return nil, ps.errorf("expected number after comparison")
+	}
+	v, err := strconv.ParseUint(ps.take().text, 10, 64)
+	if err != nil {
+		return nil, ps.errorf("number out of range")
+	}
+	p.Value = v
+
+	return p, nil
+}
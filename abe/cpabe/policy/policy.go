@@ -0,0 +1,373 @@
+// Package policy implements the access-policy language of ciphertext-policy
+// attribute-based encryption. A policy is a monotone boolean formula over
+// attributes: leaves test that an attribute is present, or compare a numeric
+// attribute against a constant; inner nodes are threshold gates satisfied
+// when at least K of their children are, so AND and OR are the K=N and K=1
+// special cases. Negation is deliberately absent, as CP-ABE schemes can only
+// enforce monotone policies.
+//
+// Policies are written in a textual syntax parsed by Parse:
+//
+//	region_eu and (auditor or threshold(2, hr, legal, finance))
+//	clearance >= 3 and not_before <= 20260101
+//
+// Canonicalize sorts the children of every gate and merges nested gates of
+// the same kind, so semantically identical policies share one encoding;
+// String and MarshalBinary emit that canonical form.
+package policy
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudflare/circl/internal/conv"
+)
+
+// Operators usable in numeric comparison leaves.
+const (
+	OpEq uint8 = iota + 1 // =
+	OpLt                  // <
+	OpLe                  // <=
+	OpGt                  // >
+	OpGe                  // >=
+)
+
+var opText = map[uint8]string{
+	OpEq: "=", OpLt: "<", OpLe: "<=", OpGt: ">", OpGe: ">=",
+}
+
+// Policy is a node of the policy tree. Exactly one of the three shapes is
+// populated: an attribute leaf (Attr set, Op zero, no children), a
+// comparison leaf (Attr and Op set), or a threshold gate (Children
+// non-empty and 1 <= K <= len(Children)).
+type Policy struct {
+	// K is the number of children required to satisfy a gate.
+	K uint
+	// Children is non-empty for gates and nil for leaves.
+	Children []Policy
+	// Attr names the attribute a leaf tests.
+	Attr string
+	// Op and Value describe a numeric comparison leaf; Op is zero for a
+	// plain presence leaf.
+	Op    uint8
+	Value uint64
+}
+
+// IsLeaf returns true if the node has no children.
+func (p *Policy) IsLeaf() bool { return len(p.Children) == 0 }
+
+// Validate checks gate thresholds, attribute names, and comparison
+// operators throughout the tree.
+func (p *Policy) Validate() error {
+	if p.IsLeaf() {
+		if !validAttr(p.Attr) {
+			return errors.New("policy: invalid attribute name " + strconv.Quote(p.Attr))
+		}
+		if p.Op != 0 {
+			if _, ok := opText[p.Op]; !ok {
+				return errors.New("policy: unknown comparison operator")
+			}
+		}
+		return nil
+	}
+	if p.K == 0 || p.K > uint(len(p.Children)) {
+		return errors.New("policy: threshold out of range")
+	}
+	for i := range p.Children {
+		if err := p.Children[i].Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Attributes holds the attributes of a decryptor: a set of names, each
+// optionally carrying a numeric value used by comparison leaves.
+type Attributes map[string]uint64
+
+// Satisfied reports whether attrs satisfies the policy. A presence leaf is
+// satisfied when its attribute is in attrs with any value; a comparison
+// leaf additionally requires the comparison to hold.
+func (p *Policy) Satisfied(attrs Attributes) bool {
+	if p.IsLeaf() {
+		v, ok := attrs[p.Attr]
+		if !ok {
+			return false
+		}
+		switch p.Op {
+		case 0:
+			return true
+		case OpEq:
+			return v == p.Value
+		case OpLt:
+			return v < p.Value
+		case OpLe:
+			return v <= p.Value
+		case OpGt:
+			return v > p.Value
+		case OpGe:
+			return v >= p.Value
+		}
+		return false
+	}
+	n := uint(0)
+	for i := range p.Children {
+		if p.Children[i].Satisfied(attrs) {
+			n++
+		}
+	}
+
+	return n >= p.K
+}
+
+// Attributes returns the sorted set of attribute names the policy mentions.
+func (p *Policy) Attributes() []string {
+	set := make(map[string]struct{})
+	p.walk(func(n *Policy) {
+		if n.IsLeaf() {
+			set[n.Attr] = struct{}{}
+		}
+	})
+	out := make([]string, 0, len(set))
+	for a := range set {
+		out = append(out, a)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+func (p *Policy) walk(f func(*Policy)) {
+	f(p)
+	for i := range p.Children {
+		p.Children[i].walk(f)
+	}
+}
+
+// Canonicalize rewrites the tree into its canonical form: nested AND gates
+// merge into one AND, nested ORs into one OR, single-child gates collapse,
+// and the children of every gate are sorted by their binary encoding.
+// Threshold gates with 1 < K < N are symmetric but do not merge. The
+// rewriting preserves the satisfied-by relation.
+func (p *Policy) Canonicalize() {
+	if p.IsLeaf() {
+		return
+	}
+	for i := range p.Children {
+		p.Children[i].Canonicalize()
+	}
+	if len(p.Children) == 1 {
+		*p = p.Children[0]
+		return
+	}
+	n := uint(len(p.Children))
+	if p.K == n || p.K == 1 {
+		var merged []Policy
+		for i := range p.Children {
+			c := &p.Children[i]
+			cn := uint(len(c.Children))
+			sameGate := !c.IsLeaf() &&
+				((p.K == n && c.K == cn) || (p.K == 1 && c.K == 1))
+			if sameGate {
+				merged = append(merged, c.Children...)
+			} else {
+				merged = append(merged, *c)
+			}
+		}
+		p.Children = merged
+		if p.K != 1 {
+			p.K = uint(len(p.Children))
+		}
+	}
+	sort.Slice(p.Children, func(i, j int) bool {
+		return string(p.Children[i].encode(nil)) < string(p.Children[j].encode(nil))
+	})
+}
+
+// String returns the canonical textual form of the policy. The receiver is
+// not modified; call Canonicalize first for a canonical tree.
+func (p *Policy) String() string {
+	var b strings.Builder
+	q := *p
+	q.Canonicalize()
+	q.format(&b, false)
+
+	return b.String()
+}
+
+func (p *Policy) format(b *strings.Builder, paren bool) {
+	if p.IsLeaf() {
+		b.WriteString(p.Attr)
+		if p.Op != 0 {
+			b.WriteString(" " + opText[p.Op] + " ")
+			b.WriteString(strconv.FormatUint(p.Value, 10))
+		}
+		return
+	}
+	n := uint(len(p.Children))
+	if p.K != 1 && p.K != n {
+		b.WriteString("threshold(")
+		b.WriteString(strconv.FormatUint(uint64(p.K), 10))
+		for i := range p.Children {
+			b.WriteString(", ")
+			p.Children[i].format(b, false)
+		}
+		b.WriteString(")")
+		return
+	}
+	word := " and "
+	if p.K == 1 {
+		word = " or "
+	}
+	if paren {
+		b.WriteString("(")
+	}
+	for i := range p.Children {
+		if i > 0 {
+			b.WriteString(word)
+		}
+		p.Children[i].format(b, !p.Children[i].IsLeaf())
+	}
+	if paren {
+		b.WriteString(")")
+	}
+}
+
+// Node tags of the binary encoding.
+const (
+	tagLeaf = 0x01
+	tagCmp  = 0x02
+	tagGate = 0x03
+)
+
+// MarshalBinary encodes the canonical form of the policy.
+func (p *Policy) MarshalBinary() ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	q := *p
+	q.Canonicalize()
+
+	return q.encode(nil), nil
+}
+
+func (p *Policy) encode(out []byte) []byte {
+	if p.IsLeaf() {
+		if p.Op == 0 {
+			out = append(out, tagLeaf)
+			out = binary.AppendUvarint(out, uint64(len(p.Attr)))
+			return append(out, p.Attr...)
+		}
+		out = append(out, tagCmp)
+		out = binary.AppendUvarint(out, uint64(len(p.Attr)))
+		out = append(out, p.Attr...)
+		out = append(out, p.Op)
+		return binary.AppendUvarint(out, p.Value)
+	}
+	out = append(out, tagGate)
+	out = binary.AppendUvarint(out, uint64(p.K))
+	out = binary.AppendUvarint(out, uint64(len(p.Children)))
+	for i := range p.Children {
+		out = p.Children[i].encode(out)
+	}
+
+	return out
+}
+
+// UnmarshalBinary decodes a policy and validates it.
+func (p *Policy) UnmarshalBinary(data []byte) error {
+	rest, err := p.decode(data)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("policy: trailing bytes")
+	}
+
+	return p.Validate()
+}
+
+var errTruncated = errors.New("policy: truncated encoding")
+
+func (p *Policy) decode(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errTruncated
+	}
+	tag := data[0]
+	data = data[1:]
+	*p = Policy{}
+	switch tag {
+	case tagLeaf, tagCmp:
+		n, k := binary.Uvarint(data)
+		if k <= 0 || uint64(len(data)-k) < n {
+			return nil, errTruncated
+		}
+		p.Attr = string(data[k : k+int(n)])
+		data = data[k+int(n):]
+		if tag == tagCmp {
+			if len(data) == 0 {
+				return nil, errTruncated
+			}
+			p.Op = data[0]
+			v, k := binary.Uvarint(data[1:])
+			if k <= 0 {
+				return nil, errTruncated
+			}
+			p.Value = v
+			data = data[1+k:]
+		}
+		return data, nil
+	case tagGate:
+		t, k := binary.Uvarint(data)
+		if k <= 0 {
+			return nil, errTruncated
+		}
+		data = data[k:]
+		n, k := binary.Uvarint(data)
+		if k <= 0 || n > uint64(len(data)) {
+			return nil, errTruncated
+		}
+		data = data[k:]
+		p.K = uint(t)
+		p.Children = make([]Policy, n)
+		var err error
+		for i := range p.Children {
+			if data, err = p.Children[i].decode(data); err != nil {
+				return nil, err
+			}
+		}
+		return data, nil
+	}
+
+	return nil, errors.New("policy: unknown node tag")
+}
+
+// validAttr accepts names that survive a round trip through the textual
+// syntax: a letter or underscore followed by letters, digits, or any of
+// "_.-:", and none of the keywords.
+func validAttr(s string) bool {
+	if len(s) == 0 || s == "and" || s == "or" || s == "threshold" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		ok := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' ||
+			i > 0 && (c >= '0' && c <= '9' || c == '.' || c == '-' || c == ':')
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MarshalText encodes the policy's binary form in base64. The
+// human-readable form remains available through String.
+func (p *Policy) MarshalText() ([]byte, error) { return conv.MarshalText(p) }
+
+// UnmarshalText decodes a policy produced by MarshalText.
+func (p *Policy) UnmarshalText(text []byte) error { return conv.UnmarshalText(p, text) }
@@ -0,0 +1,200 @@
+// Package revocation provides indirect user revocation and key expiry for
+// attribute-based encryption. Users are leaves of a complete binary tree
+// and a secret key binds to the nodes on the user's root-to-leaf path. Each
+// epoch the authority publishes a KeyUpdate listing the roots of the
+// minimal subtrees covering every non-revoked user (the complete-subtree
+// method); a key can decrypt ciphertexts of that epoch only by combining
+// one of its path nodes with the update, so revoked users are cut off
+// without re-encrypting old data or touching other users' keys.
+//
+// Key expiry needs no update material at all: an attribute key carries its
+// expiry epoch as the numeric attribute AttrExpiry, and TimeBound extends
+// an encryption policy so that only keys still valid at the encryption
+// epoch satisfy it.
+package revocation
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/cloudflare/circl/abe/cpabe/policy"
+	"github.com/cloudflare/circl/internal/conv"
+)
+
+// AttrExpiry is the numeric attribute carrying a key's expiry epoch.
+const AttrExpiry = "exp"
+
+// TimeBound returns p restricted to keys valid at the given epoch, i.e.
+// p AND (exp >= epoch). Encryptors call it with the current epoch.
+func TimeBound(p *policy.Policy, epoch uint64) *policy.Policy {
+	q := policy.Policy{
+		K: 2,
+		Children: []policy.Policy{
+			*p,
+			{Attr: AttrExpiry, Op: policy.OpGe, Value: epoch},
+		},
+	}
+	q.Canonicalize()
+
+	return &q
+}
+
+// GrantExpiry records in attrs that the key expires after the given epoch.
+// The authority calls it when issuing a key.
+func GrantExpiry(attrs policy.Attributes, notAfter uint64) {
+	attrs[AttrExpiry] = notAfter
+}
+
+// Tree is the user tree of a deployment. Depth d supports 2^d users,
+// identified by indices 0 <= user < 2^d. Nodes are numbered heap-style:
+// the root is 1 and node i has children 2i and 2i+1.
+type Tree struct{ depth uint }
+
+// NewTree returns a tree of the given depth, 1 <= depth <= 32.
+func NewTree(depth uint) (*Tree, error) {
+	if depth < 1 || depth > 32 {
+		return nil, errors.New("revocation: tree depth out of range")
+	}
+
+	return &Tree{depth}, nil
+}
+
+// Capacity returns the number of users the tree supports.
+func (t *Tree) Capacity() uint64 { return uint64(1) << t.depth }
+
+func (t *Tree) leaf(user uint64) uint64 { return t.Capacity() + user }
+
+// PathNodes returns the nodes on the path from the root to the user's
+// leaf, root first. A user's secret key holds key material for each of
+// these nodes.
+func (t *Tree) PathNodes(user uint64) ([]uint64, error) {
+	if user >= t.Capacity() {
+		return nil, errors.New("revocation: user index out of range")
+	}
+	path := make([]uint64, t.depth+1)
+	n := t.leaf(user)
+	for i := int(t.depth); i >= 0; i-- {
+		path[i] = n
+		n >>= 1
+	}
+
+	return path, nil
+}
+
+// Cover returns the roots of the minimal set of subtrees that together
+// contain every leaf except the revoked users. It is empty when every
+// user is revoked, and the root alone when none is.
+func (t *Tree) Cover(revoked []uint64) ([]uint64, error) {
+	marked := make(map[uint64]bool)
+	for _, u := range revoked {
+		if u >= t.Capacity() {
+			return nil, errors.New("revocation: user index out of range")
+		}
+		for n := t.leaf(u); n >= 1; n >>= 1 {
+			marked[n] = true
+		}
+	}
+	if !marked[1] {
+		return []uint64{1}, nil
+	}
+	var cover []uint64
+	for n := range marked {
+		if n >= t.Capacity() { // leaves have no children to cover
+			continue
+		}
+		for _, c := range []uint64{2 * n, 2*n + 1} {
+			if !marked[c] {
+				cover = append(cover, c)
+			}
+		}
+	}
+	sort.Slice(cover, func(i, j int) bool { return cover[i] < cover[j] })
+
+	return cover, nil
+}
+
+// KeyUpdate is the per-epoch material the authority publishes. Ciphertexts
+// created during an epoch can be decrypted only by combining a key's path
+// node with the matching cover node of that epoch's update.
+type KeyUpdate struct {
+	Epoch uint64
+	Nodes []uint64
+}
+
+// Update computes the key update for an epoch given the revoked users.
+func (t *Tree) Update(epoch uint64, revoked []uint64) (*KeyUpdate, error) {
+	cover, err := t.Cover(revoked)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyUpdate{Epoch: epoch, Nodes: cover}, nil
+}
+
+// Match returns the node shared between the user's path and the update,
+// which the decryptor uses to combine its key with the update material.
+// ok is false when the user is revoked in this epoch.
+func (t *Tree) Match(user uint64, u *KeyUpdate) (node uint64, ok bool) {
+	path, err := t.PathNodes(user)
+	if err != nil {
+		return 0, false
+	}
+	in := make(map[uint64]bool, len(u.Nodes))
+	for _, n := range u.Nodes {
+		in[n] = true
+	}
+	for _, n := range path {
+		if in[n] {
+			return n, true
+		}
+	}
+
+	return 0, false
+}
+
+// MarshalBinary encodes the update as the epoch followed by the node list.
+func (u *KeyUpdate) MarshalBinary() ([]byte, error) {
+	out := binary.AppendUvarint(nil, u.Epoch)
+	out = binary.AppendUvarint(out, uint64(len(u.Nodes)))
+	for _, n := range u.Nodes {
+		out = binary.AppendUvarint(out, n)
+	}
+
+	return out, nil
+}
+
+// UnmarshalBinary decodes an update produced by MarshalBinary.
+func (u *KeyUpdate) UnmarshalBinary(data []byte) error {
+	epoch, k := binary.Uvarint(data)
+	if k <= 0 {
+		return errors.New("revocation: truncated key update")
+	}
+	data = data[k:]
+	n, k := binary.Uvarint(data)
+	if k <= 0 || n > uint64(len(data)) {
+		return errors.New("revocation: truncated key update")
+	}
+	data = data[k:]
+	u.Epoch = epoch
+	u.Nodes = make([]uint64, n)
+	for i := range u.Nodes {
+		v, k := binary.Uvarint(data)
+		if k <= 0 {
+			return errors.New("revocation: truncated key update")
+		}
+		u.Nodes[i] = v
+		data = data[k:]
+	}
+	if len(data) != 0 {
+		return errors.New("revocation: trailing bytes")
+	}
+
+	return nil
+}
+
+// MarshalText encodes the update's binary form in base64.
+func (u *KeyUpdate) MarshalText() ([]byte, error) { return conv.MarshalText(u) }
+
+// UnmarshalText decodes an update produced by MarshalText.
+func (u *KeyUpdate) UnmarshalText(text []byte) error { return conv.UnmarshalText(u, text) }
@@ -0,0 +1,324 @@
+// Package kpabe implements key-policy attribute-based encryption in the
+// style of Goyal, Pandey, Sahai, and Waters (CCS 2006), as a KEM: a
+// ciphertext is labelled with a set of attributes, a secret key embeds a
+// policy over attributes, and decapsulation recovers the content key
+// exactly when the ciphertext's attributes satisfy the key's policy. This
+// is the dual of ciphertext-policy ABE and shares its policy machinery:
+// policies come from abe/cpabe/policy, including threshold gates and
+// numeric comparisons, which are compiled to bit attributes internally.
+//
+// The scheme is small-universe: Setup fixes the attribute names (numeric
+// attributes count as one name each). It is written over the Bilinear
+// interface so any pairing with groups exposed as group.Group can
+// instantiate it.
+package kpabe
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/cloudflare/circl/abe/cpabe/policy"
+	"github.com/cloudflare/circl/abe/pairing"
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/hardening"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Target is an element of the pairing target group; see abe/pairing.
+type Target = pairing.Target
+
+// Bilinear abstracts the pairing the scheme is instantiated over; see
+// abe/pairing.
+type Bilinear = pairing.Bilinear
+
+// BLS12381 instantiates the scheme over the BLS12-381 pairing.
+var BLS12381 = pairing.BLS12381
+
+// KeySize is the length of the content keys produced by the KEM.
+const KeySize = 32
+
+// numericBits is the width numeric attribute values are compiled to.
+const numericBits = 32
+
+// PublicKey is the public parameters of a deployment.
+type PublicKey struct {
+	bl Bilinear
+	// y is e(g1, g2)^msk.y.
+	y Target
+	// t maps each attribute (after bit expansion) to g1^msk.t[a].
+	t map[string]group.Element
+}
+
+// MasterKey is the authority's key-generation secret.
+type MasterKey struct {
+	bl Bilinear
+	y  group.Scalar
+	t  map[string]group.Scalar
+}
+
+// Wipe zeroes the master secret. The key is unusable afterwards.
+func (mk *MasterKey) Wipe() {
+	if mk.y != nil {
+		mk.y.SetUint64(0)
+		mk.y = nil
+	}
+	for n, s := range mk.t {
+		s.SetUint64(0)
+		delete(mk.t, n)
+	}
+}
+
+// AttributeKey is a user's secret key; it embeds the policy it was issued
+// for.
+type AttributeKey struct {
+	bl Bilinear
+	// expanded is the key policy after numeric compilation; d holds one
+	// G2 component per leaf, indexed as in policy.ShareSecret.
+	expanded *policy.Policy
+	d        []group.Element
+}
+
+// Wipe overwrites the secret key components with the identity. The key
+// is unusable afterwards.
+func (ak *AttributeKey) Wipe() {
+	for i, e := range ak.d {
+		e.Set(ak.bl.G2().Identity())
+		ak.d[i] = nil
+	}
+	ak.d = nil
+}
+
+// Ciphertext is an encapsulation, decryptable under any key whose policy
+// its attributes satisfy.
+type Ciphertext struct {
+	// Attrs is the (expanded) attribute set the ciphertext is labelled
+	// with; it is public.
+	Attrs []string
+	// c maps each attribute to its component t[a]^s.
+	c map[string]group.Element
+}
+
+// Setup generates a key pair for the given attribute names. Names used
+// with numeric comparisons are expanded to one attribute per bit.
+func Setup(bl Bilinear, rnd io.Reader, attrs []string) (*PublicKey, *MasterKey, error) {
+	rnd = rand.Source(rnd)
+	msk := &MasterKey{
+		bl: bl,
+		y:  bl.G2().RandomScalar(rnd),
+		t:  make(map[string]group.Scalar),
+	}
+	pk := &PublicKey{
+		bl: bl,
+		y:  bl.Pair(bl.G1().Generator(), bl.G2().Generator()),
+		t:  make(map[string]group.Element),
+	}
+	pk.y.Exp(pk.y, msk.y)
+	for _, a := range attrs {
+		if _, dup := msk.t[a]; dup {
+			return nil, nil, errors.New("kpabe: duplicate attribute " + a)
+		}
+		// One component for the name itself and one per bit polarity, so
+		// the same name works in presence and comparison leaves.
+		names := []string{a}
+		for i := uint(0); i < numericBits; i++ {
+			names = append(names, policy.BitAttr(a, i, 0), policy.BitAttr(a, i, 1))
+		}
+		for _, n := range names {
+			// Exclude zero so key components t^-1 exist.
+			s := bl.G1().RandomNonZeroScalar(rnd)
+			msk.t[n] = s
+			pk.t[n] = hardening.MulGen(bl.G1(), s, rnd)
+		}
+	}
+
+	return pk, msk, nil
+}
+
+// KeyGen issues a key for a policy over the attributes fixed at Setup.
+func (msk *MasterKey) KeyGen(rnd io.Reader, p *policy.Policy) (*AttributeKey, error) {
+	if msk.y == nil {
+		return nil, errors.New("kpabe: master key has been wiped")
+	}
+	rnd = rand.Source(rnd)
+	expanded, err := policy.ExpandNumeric(p, numericBits)
+	if err != nil {
+		return nil, err
+	}
+	g2 := msk.bl.G2()
+	shares, err := policy.ShareSecret(rnd, g2, msk.y, expanded)
+	if err != nil {
+		return nil, err
+	}
+	key := &AttributeKey{
+		bl:       msk.bl,
+		expanded: expanded,
+		d:        make([]group.Element, len(shares)),
+	}
+	for i, sh := range shares {
+		t, ok := msk.t[sh.Attr]
+		if !ok {
+			return nil, errors.New("kpabe: policy uses unknown attribute " + sh.Attr)
+		}
+		e := g2.NewScalar().Inv(t)
+		e.Mul(e, sh.Share)
+		key.d[i] = hardening.MulGen(g2, e, rnd)
+	}
+
+	return key, nil
+}
+
+// Encapsulate derives a fresh content key and a ciphertext labelled with
+// the given attributes. All names must have been fixed at Setup.
+func (pk *PublicKey) Encapsulate(rnd io.Reader, attrs policy.Attributes) ([]byte, *Ciphertext, error) {
+	rnd = rand.Source(rnd)
+	expanded, err := policy.ExpandAttributes(attrs, numericBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := pk.bl.G1().RandomNonZeroScalar(rnd)
+	ct := &Ciphertext{c: make(map[string]group.Element, len(expanded))}
+	for a := range expanded {
+		t, ok := pk.t[a]
+		if !ok {
+			return nil, nil, errors.New("kpabe: unknown attribute " + a)
+		}
+		ct.c[a] = pk.bl.G1().NewElement().Mul(t, s)
+		ct.Attrs = append(ct.Attrs, a)
+	}
+	sort.Strings(ct.Attrs)
+	ys := pk.bl.NewTarget().Exp(pk.y, s)
+
+	cek, err := kdf(ys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, ct, nil
+}
+
+// Decapsulate recovers the content key when the ciphertext's attributes
+// satisfy the key's policy, and policy.ErrNotSatisfied otherwise.
+func (key *AttributeKey) Decapsulate(ct *Ciphertext) ([]byte, error) {
+	attrs := make(policy.Attributes, len(ct.Attrs))
+	for _, a := range ct.Attrs {
+		attrs[a] = 0
+	}
+	coeffs, err := policy.RecombineCoeffs(key.bl.G2(), attrs, key.expanded)
+	if err != nil {
+		return nil, err
+	}
+	// Π e(t[a]^s, g2^(share/t[a]))^coeff = e(g1, g2)^(s·Σ coeff·share)
+	//                                    = e(g1, g2)^(s·y).
+	var acc Target
+	for _, lc := range coeffs {
+		c, ok := ct.c[lc.Attr]
+		if !ok {
+			return nil, errors.New("kpabe: ciphertext misses attribute " + lc.Attr)
+		}
+		p := key.bl.Pair(c, key.d[lc.Leaf])
+		p.Exp(p, lc.Coeff)
+		if acc == nil {
+			acc = p
+		} else {
+			acc.Mul(acc, p)
+		}
+	}
+
+	return kdf(acc)
+}
+
+func kdf(t Target) ([]byte, error) {
+	b, err := t.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	cek := sha256.Sum256(b)
+
+	return cek[:], nil
+}
+
+// ctFormat is the version byte leading a serialized ciphertext.
+const ctFormat = 1
+
+// ErrMalformedCiphertext is returned when a serialized ciphertext does
+// not decode canonically.
+var ErrMalformedCiphertext = errors.New("kpabe: malformed ciphertext")
+
+// MarshalBinary encodes the ciphertext as a format byte followed by the
+// attribute components in attribute order, each as a length-prefixed name
+// and element.
+func (ct *Ciphertext) MarshalBinary() ([]byte, error) {
+	out := []byte{ctFormat}
+	out = binary.AppendUvarint(out, uint64(len(ct.Attrs)))
+	for _, a := range ct.Attrs {
+		e, ok := ct.c[a]
+		if !ok {
+			return nil, ErrMalformedCiphertext
+		}
+		data, err := e.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		out = binary.AppendUvarint(out, uint64(len(a)))
+		out = append(out, a...)
+		out = binary.AppendUvarint(out, uint64(len(data)))
+		out = append(out, data...)
+	}
+
+	return out, nil
+}
+
+// UnmarshalCiphertext decodes a ciphertext produced by
+// Ciphertext.MarshalBinary. The encoding is canonical: attributes must be
+// strictly increasing and trailing data is rejected.
+func (pk *PublicKey) UnmarshalCiphertext(data []byte) (*Ciphertext, error) {
+	if len(data) == 0 || data[0] != ctFormat {
+		return nil, ErrMalformedCiphertext
+	}
+	in := data[1:]
+	n, k := binary.Uvarint(in)
+	if k <= 0 || n > uint64(len(in)) {
+		return nil, ErrMalformedCiphertext
+	}
+	in = in[k:]
+	ct := &Ciphertext{c: make(map[string]group.Element, n)}
+	for i := uint64(0); i < n; i++ {
+		name, rest, err := readChunk(in)
+		if err != nil {
+			return nil, err
+		}
+		blob, rest, err := readChunk(rest)
+		if err != nil {
+			return nil, err
+		}
+		in = rest
+		a := string(name)
+		if len(ct.Attrs) != 0 && ct.Attrs[len(ct.Attrs)-1] >= a {
+			return nil, ErrMalformedCiphertext
+		}
+		e := pk.bl.G1().NewElement()
+		if err := e.UnmarshalBinary(blob); err != nil {
+			return nil, ErrMalformedCiphertext
+		}
+		ct.Attrs = append(ct.Attrs, a)
+		ct.c[a] = e
+	}
+	if len(in) != 0 {
+		return nil, ErrMalformedCiphertext
+	}
+
+	return ct, nil
+}
+
+func readChunk(in []byte) ([]byte, []byte, error) {
+	n, k := binary.Uvarint(in)
+	if k <= 0 || uint64(len(in)-k) < n {
+		return nil, nil, ErrMalformedCiphertext
+	}
+	in = in[k:]
+
+	return in[:n], in[n:], nil
+}
@@ -0,0 +1,361 @@
+package pairing
+
+import (
+	"crypto"
+	_ "crypto/sha256" // linked for crypto.SHA256
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/cloudflare/circl/ecc/bls12381/ff"
+	"github.com/cloudflare/circl/expander"
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/rand"
+)
+
+// BLS12381 instantiates the ABE schemes over the BLS12-381 pairing:
+// ciphertext components in G1, key components in G2, and content keys
+// derived in Gt. The wrappers below expose the curve's groups through
+// the group.Group interface; both share the scalar field of the curve.
+var BLS12381 Bilinear = blsPairing{}
+
+type blsPairing struct{}
+
+func (blsPairing) G1() group.Group { return blsG1{} }
+func (blsPairing) G2() group.Group { return blsG2{} }
+
+func (blsPairing) Pair(a, b group.Element) Target {
+	var t blsTarget
+	t.v = *bls12381.Pair(&castBlsG1(a).p, &castBlsG2(b).p)
+
+	return &t
+}
+
+func (blsPairing) NewTarget() Target {
+	var t blsTarget
+	t.v.SetIdentity()
+
+	return &t
+}
+
+// blsTarget wraps a Gt element as a Target.
+type blsTarget struct{ v bls12381.Gt }
+
+func castBlsTarget(t Target) *blsTarget {
+	z, ok := t.(*blsTarget)
+	if !ok {
+		panic(group.ErrType)
+	}
+
+	return z
+}
+
+func (t *blsTarget) Set(a Target) Target {
+	t.v = castBlsTarget(a).v
+
+	return t
+}
+
+func (t *blsTarget) Mul(a, b Target) Target {
+	t.v.Mul(&castBlsTarget(a).v, &castBlsTarget(b).v)
+
+	return t
+}
+
+func (t *blsTarget) Exp(a Target, s group.Scalar) Target {
+	t.v.Exp(&castBlsTarget(a).v, &castBlsScalar(s).s)
+
+	return t
+}
+
+func (t *blsTarget) IsEqual(a Target) bool { return t.v.IsEqual(&castBlsTarget(a).v) }
+
+func (t *blsTarget) MarshalBinary() ([]byte, error) { return t.v.Bytes(), nil }
+
+// blsUniformSize is the byte length hashed per scalar, giving at most a
+// 2^-129 bias after reduction (RFC 9380, Section 5).
+const blsUniformSize = 48
+
+// blsScalar is an integer modulo the order of the pairing groups; G1
+// and G2 share it.
+type blsScalar struct{ s ff.Scalar }
+
+func castBlsScalar(s group.Scalar) *blsScalar {
+	z, ok := s.(*blsScalar)
+	if !ok {
+		panic(group.ErrType)
+	}
+
+	return z
+}
+
+func (s *blsScalar) Group() group.Group              { return blsG1{} }
+func (s *blsScalar) Set(a group.Scalar) group.Scalar { s.s = castBlsScalar(a).s; return s }
+func (s *blsScalar) Copy() group.Scalar              { return &blsScalar{s.s} }
+func (s *blsScalar) IsZero() bool                    { return s.s.IsZero() == 1 }
+func (s *blsScalar) IsEqual(a group.Scalar) bool     { return s.s.IsEqual(&castBlsScalar(a).s) == 1 }
+
+func (s *blsScalar) SetUint64(v uint64) group.Scalar { s.s.SetUint64(v); return s }
+
+func (s *blsScalar) Add(a, b group.Scalar) group.Scalar {
+	s.s.Add(&castBlsScalar(a).s, &castBlsScalar(b).s)
+	return s
+}
+
+func (s *blsScalar) Sub(a, b group.Scalar) group.Scalar {
+	s.s.Sub(&castBlsScalar(a).s, &castBlsScalar(b).s)
+	return s
+}
+
+func (s *blsScalar) Mul(a, b group.Scalar) group.Scalar {
+	s.s.Mul(&castBlsScalar(a).s, &castBlsScalar(b).s)
+	return s
+}
+
+func (s *blsScalar) Sqr(a group.Scalar) group.Scalar { s.s.Sqr(&castBlsScalar(a).s); return s }
+func (s *blsScalar) Inv(a group.Scalar) group.Scalar { s.s.Inv(&castBlsScalar(a).s); return s }
+func (s *blsScalar) Neg(a group.Scalar) group.Scalar { s.s.Neg(&castBlsScalar(a).s); return s }
+
+func (s *blsScalar) MarshalBinary() ([]byte, error) { return s.s.Bytes(), nil }
+func (s *blsScalar) UnmarshalBinary(data []byte) error {
+	return s.s.SetBytes(data)
+}
+
+// blsScalarOps is the scalar-side behavior common to both groups.
+type blsScalarOps struct{}
+
+func (blsScalarOps) NewScalar() group.Scalar { return &blsScalar{} }
+
+func (blsScalarOps) RandomScalar(rd io.Reader) group.Scalar {
+	var s blsScalar
+	if err := s.s.Random(rand.Source(rd)); err != nil {
+		panic(err)
+	}
+
+	return &s
+}
+
+func (o blsScalarOps) RandomNonZeroScalar(rd io.Reader) group.Scalar {
+	for {
+		s := o.RandomScalar(rd)
+		if !s.IsZero() {
+			return s
+		}
+	}
+}
+
+func (blsScalarOps) HashToScalar(data, dst []byte) group.Scalar {
+	exp := expander.NewExpanderMD(crypto.SHA256, dst)
+	var s blsScalar
+	s.s.SetBytesWide(exp.Expand(data, blsUniformSize))
+
+	return &s
+}
+
+// blsG1 is the group of the ciphertext components.
+type blsG1 struct{ blsScalarOps }
+
+func (blsG1) Params() *group.Params {
+	return &group.Params{
+		ElementLength:           bls12381.G1Size,
+		CompressedElementLength: bls12381.G1SizeCompressed,
+		ScalarLength:            ff.ScalarSize,
+	}
+}
+
+func (g blsG1) NewElement() group.Element { return g.Identity() }
+
+func (blsG1) Identity() group.Element {
+	var e blsG1Element
+	e.p.SetIdentity()
+
+	return &e
+}
+
+func (blsG1) Generator() group.Element { return &blsG1Element{*bls12381.G1Generator()} }
+
+func (g blsG1) RandomElement(rd io.Reader) group.Element {
+	return g.NewElement().MulGen(g.RandomScalar(rd))
+}
+
+func (blsG1) HashToElement(data, dst []byte) group.Element {
+	var e blsG1Element
+	e.p.Hash(data, dst)
+
+	return &e
+}
+
+func (g blsG1) HashToElementNonUniform(data, dst []byte) group.Element {
+	return g.HashToElement(data, dst)
+}
+
+type blsG1Element struct{ p bls12381.G1 }
+
+func castBlsG1(e group.Element) *blsG1Element {
+	z, ok := e.(*blsG1Element)
+	if !ok {
+		panic(group.ErrType)
+	}
+
+	return z
+}
+
+func (e *blsG1Element) Group() group.Group { return blsG1{} }
+
+func (e *blsG1Element) Set(a group.Element) group.Element {
+	e.p = castBlsG1(a).p
+	return e
+}
+
+func (e *blsG1Element) Copy() group.Element { return &blsG1Element{e.p} }
+
+func (e *blsG1Element) IsIdentity() bool { return e.p.IsIdentity() }
+
+func (e *blsG1Element) IsEqual(a group.Element) bool { return e.p.IsEqual(&castBlsG1(a).p) }
+
+func (e *blsG1Element) Add(a, b group.Element) group.Element {
+	e.p.Add(&castBlsG1(a).p, &castBlsG1(b).p)
+	return e
+}
+
+func (e *blsG1Element) Dbl(a group.Element) group.Element {
+	e.p.Double(&castBlsG1(a).p)
+	return e
+}
+
+func (e *blsG1Element) Neg(a group.Element) group.Element {
+	e.p = castBlsG1(a).p
+	e.p.Neg()
+
+	return e
+}
+
+func (e *blsG1Element) Mul(a group.Element, s group.Scalar) group.Element {
+	e.p.ScalarMult(&castBlsScalar(s).s, &castBlsG1(a).p)
+	return e
+}
+
+func (e *blsG1Element) MulGen(s group.Scalar) group.Element {
+	e.p.ScalarMult(&castBlsScalar(s).s, bls12381.G1Generator())
+	return e
+}
+
+func (e *blsG1Element) MarshalBinary() ([]byte, error) { return e.p.Bytes(), nil }
+
+func (e *blsG1Element) MarshalBinaryCompress() ([]byte, error) { return e.p.BytesCompressed(), nil }
+
+func (e *blsG1Element) UnmarshalBinary(data []byte) error {
+	var p bls12381.G1
+	if err := p.SetBytes(data); err != nil {
+		return err
+	}
+	if !p.IsOnG1() {
+		return errors.New("pairing: G1 point not in the prime-order subgroup")
+	}
+	e.p = p
+
+	return nil
+}
+
+// blsG2 is the group of the key components. Hashing to G2 is not
+// implemented; the scheme never hashes into it.
+type blsG2 struct{ blsScalarOps }
+
+func (blsG2) Params() *group.Params {
+	return &group.Params{
+		ElementLength:           bls12381.G2Size,
+		CompressedElementLength: bls12381.G2SizeCompressed,
+		ScalarLength:            ff.ScalarSize,
+	}
+}
+
+func (g blsG2) NewElement() group.Element { return g.Identity() }
+
+func (blsG2) Identity() group.Element {
+	var e blsG2Element
+	e.p.SetIdentity()
+
+	return &e
+}
+
+func (blsG2) Generator() group.Element { return &blsG2Element{*bls12381.G2Generator()} }
+
+func (g blsG2) RandomElement(rd io.Reader) group.Element {
+	return g.NewElement().MulGen(g.RandomScalar(rd))
+}
+
+func (blsG2) HashToElement(data, dst []byte) group.Element {
+	panic("pairing: hashing to G2 is not implemented")
+}
+
+func (blsG2) HashToElementNonUniform(data, dst []byte) group.Element {
+	panic("pairing: hashing to G2 is not implemented")
+}
+
+type blsG2Element struct{ p bls12381.G2 }
+
+func castBlsG2(e group.Element) *blsG2Element {
+	z, ok := e.(*blsG2Element)
+	if !ok {
+		panic(group.ErrType)
+	}
+
+	return z
+}
+
+func (e *blsG2Element) Group() group.Group { return blsG2{} }
+
+func (e *blsG2Element) Set(a group.Element) group.Element {
+	e.p = castBlsG2(a).p
+	return e
+}
+
+func (e *blsG2Element) Copy() group.Element { return &blsG2Element{e.p} }
+
+func (e *blsG2Element) IsIdentity() bool { return e.p.IsIdentity() }
+
+func (e *blsG2Element) IsEqual(a group.Element) bool { return e.p.IsEqual(&castBlsG2(a).p) }
+
+func (e *blsG2Element) Add(a, b group.Element) group.Element {
+	e.p.Add(&castBlsG2(a).p, &castBlsG2(b).p)
+	return e
+}
+
+func (e *blsG2Element) Dbl(a group.Element) group.Element {
+	e.p.Double(&castBlsG2(a).p)
+	return e
+}
+
+func (e *blsG2Element) Neg(a group.Element) group.Element {
+	e.p = castBlsG2(a).p
+	e.p.Neg()
+
+	return e
+}
+
+func (e *blsG2Element) Mul(a group.Element, s group.Scalar) group.Element {
+	e.p.ScalarMult(&castBlsScalar(s).s, &castBlsG2(a).p)
+	return e
+}
+
+func (e *blsG2Element) MulGen(s group.Scalar) group.Element {
+	e.p.ScalarMult(&castBlsScalar(s).s, bls12381.G2Generator())
+	return e
+}
+
+func (e *blsG2Element) MarshalBinary() ([]byte, error) { return e.p.Bytes(), nil }
+
+func (e *blsG2Element) MarshalBinaryCompress() ([]byte, error) { return e.p.BytesCompressed(), nil }
+
+func (e *blsG2Element) UnmarshalBinary(data []byte) error {
+	var p bls12381.G2
+	if err := p.SetBytes(data); err != nil {
+		return err
+	}
+	if !p.IsOnG2() {
+		return errors.New("pairing: G2 point not in the prime-order subgroup")
+	}
+	e.p = p
+
+	return nil
+}
@@ -0,0 +1,32 @@
+// Package pairing abstracts the bilinear pairing the attribute-based
+// encryption schemes are instantiated over, and provides the BLS12-381
+// instantiation they default to. Both abe/kpabe and abe/cpabe are
+// written against the Bilinear interface, so any pairing whose groups
+// are exposed as group.Group can replace it.
+package pairing
+
+import "github.com/cloudflare/circl/group"
+
+// Target is an element of the pairing target group. Methods follow the
+// group.Scalar convention: they store the result in the receiver and
+// return it.
+type Target interface {
+	Set(a Target) Target
+	Mul(a, b Target) Target
+	Exp(a Target, s group.Scalar) Target
+	IsEqual(a Target) bool
+	MarshalBinary() ([]byte, error)
+}
+
+// Bilinear abstracts the pairing a scheme is instantiated over.
+// Ciphertext components live in G1 and key components in G2; Pair must be
+// bilinear, i.e. Pair(a^x, b) = Pair(a, b)^x = Pair(a, b^x).
+type Bilinear interface {
+	G1() group.Group
+	G2() group.Group
+	// Pair maps an element of G1 and an element of G2 into the target
+	// group.
+	Pair(a, b group.Element) Target
+	// NewTarget returns the identity of the target group.
+	NewTarget() Target
+}
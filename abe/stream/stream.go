@@ -0,0 +1,363 @@
+// Package stream provides hybrid encryption of large payloads for
+// attribute-based encryption. ABE only ever encrypts a fresh content key
+// (the KEM half); the payload itself is streamed through an AEAD in fixed
+// chunks (the DEM half), so neither side holds the full plaintext or
+// ciphertext in memory.
+//
+// Each chunk is sealed with a nonce made of a random per-stream prefix, a
+// chunk counter, and a final-chunk flag, with the stream header as
+// associated data. This makes truncation, reordering, and chunk
+// substitution detectable, in the style of the STREAM construction used
+// for streaming AEAD elsewhere.
+package stream
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/internal/wipe"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Encapsulator is the encryption half of the ABE scheme: it returns a
+// fresh content key of the requested length together with its
+// encapsulation under the encryptor's policy. The key buffer is consumed:
+// NewWriter zeroes it once the AEAD is keyed, so implementations must not
+// retain it.
+type Encapsulator interface {
+	Encapsulate(keyLen int) (key, enc []byte, err error)
+}
+
+// Decapsulator recovers the content key from an encapsulation, provided
+// the holder's attributes satisfy the policy it was made under. As with
+// Encapsulator, the returned buffer is zeroed by NewReader.
+type Decapsulator interface {
+	Decapsulate(enc []byte) (key []byte, err error)
+}
+
+// Config selects the DEM. The zero value of a field means its default.
+type Config struct {
+	// ChunkSize is the plaintext bytes per chunk; default 64 KiB.
+	ChunkSize int
+	// KeySize is the content-key length; default 32.
+	KeySize int
+	// NewAEAD builds the chunk cipher from the content key; default
+	// AES-GCM.
+	NewAEAD func(key []byte) (cipher.AEAD, error)
+}
+
+func (c *Config) fill() Config {
+	out := Config{ChunkSize: 64 * 1024, KeySize: 32, NewAEAD: newGCM}
+	if c != nil {
+		if c.ChunkSize != 0 {
+			out.ChunkSize = c.ChunkSize
+		}
+		if c.KeySize != 0 {
+			out.KeySize = c.KeySize
+		}
+		if c.NewAEAD != nil {
+			out.NewAEAD = c.NewAEAD
+		}
+	}
+
+	return out
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	blk, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(blk)
+}
+
+// The last five nonce bytes hold the chunk counter and final flag; the
+// rest is a random per-stream prefix carried in the header.
+const nonceSuffix = 5
+
+// ErrAuthentication is returned when a chunk fails authentication,
+// covering tampering, reordering, and truncation of the ciphertext.
+var ErrAuthentication = errors.New("stream: chunk authentication failed")
+
+// Writer encrypts a stream. It buffers up to one chunk; Close seals the
+// final chunk and must be called to complete the stream.
+type Writer struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+	nonce  []byte
+	buf    []byte
+	n      int
+	ctr    uint32
+	err    error
+}
+
+// NewWriter encapsulates a fresh content key with enc, writes the stream
+// header to w, and returns a Writer encrypting into w. A nil cfg selects
+// the defaults; a nil rnd selects the library-wide source.
+func NewWriter(w io.Writer, enc Encapsulator, cfg *Config, rnd io.Reader) (*Writer, error) {
+	rnd = rand.Source(rnd)
+	c := cfg.fill()
+	key, kemCt, err := enc.Encapsulate(c.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := c.NewAEAD(key)
+	wipe.Bytes(key)
+	if err != nil {
+		return nil, err
+	}
+	if aead.NonceSize() <= nonceSuffix {
+		return nil, errors.New("stream: AEAD nonce too short")
+	}
+	prefix := make([]byte, aead.NonceSize()-nonceSuffix)
+	if _, err = io.ReadFull(rnd, prefix); err != nil {
+		return nil, err
+	}
+
+	header := binary.AppendUvarint(nil, uint64(len(kemCt)))
+	header = append(header, kemCt...)
+	header = binary.AppendUvarint(header, uint64(c.ChunkSize))
+	header = append(header, prefix...)
+	if _, err = w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		w:      w,
+		aead:   aead,
+		header: header,
+		nonce:  append(prefix, make([]byte, nonceSuffix)...),
+		buf:    make([]byte, c.ChunkSize),
+	}, nil
+}
+
+// Write implements io.Writer.
+func (sw *Writer) Write(p []byte) (int, error) {
+	if sw.err != nil {
+		return 0, sw.err
+	}
+	total := 0
+	for len(p) > 0 {
+		m := copy(sw.buf[sw.n:], p)
+		sw.n += m
+		p = p[m:]
+		total += m
+		if sw.n == len(sw.buf) && len(p) > 0 {
+			// More input follows, so this full chunk is not the last.
+			if err := sw.seal(false); err != nil {
+				return total, err
+			}
+		}
+	}
+
+	return total, nil
+}
+
+// Close seals the buffered data as the final chunk and zeroes the
+// plaintext buffer. It does not close the underlying writer.
+func (sw *Writer) Close() error {
+	if sw.err != nil {
+		return sw.err
+	}
+	err := sw.seal(true)
+	wipe.Bytes(sw.buf)
+	sw.err = errors.New("stream: writer closed")
+
+	return err
+}
+
+func (sw *Writer) seal(last bool) error {
+	off := len(sw.nonce) - nonceSuffix
+	binary.BigEndian.PutUint32(sw.nonce[off:], sw.ctr)
+	if last {
+		sw.nonce[off+4] = 1
+	}
+	ct := sw.aead.Seal(nil, sw.nonce, sw.buf[:sw.n], sw.header)
+	sw.n = 0
+	sw.ctr++
+	if sw.ctr == 0 && !last {
+		sw.err = errors.New("stream: chunk counter exhausted")
+		return sw.err
+	}
+	if _, err := sw.w.Write(ct); err != nil {
+		sw.err = err
+		return err
+	}
+
+	return nil
+}
+
+// Reader decrypts a stream produced by Writer. Any tampering, including
+// truncation, surfaces as an error from Read before the affected bytes
+// are returned.
+type Reader struct {
+	r     io.Reader
+	aead  cipher.AEAD
+	aad   []byte
+	nonce []byte
+	ct    []byte // chunk-sized ciphertext buffer
+	pt    []byte // decrypted bytes not yet returned
+	ptAll []byte // backing store of pt, zeroed once drained
+	ahead []byte // ciphertext already read past the current chunk
+	ctr   uint32
+	done  bool
+	err   error
+}
+
+// NewReader reads the stream header from r, decapsulates the content key
+// with dec, and returns a Reader yielding the plaintext. cfg must select
+// the same AEAD as the writer; a nil cfg selects the defaults.
+func NewReader(r io.Reader, dec Decapsulator, cfg *Config) (*Reader, error) {
+	c := cfg.fill()
+	br := &headerReader{r: r}
+	kemLen, err := br.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	kemCt, err := br.bytes(int(kemLen))
+	if err != nil {
+		return nil, err
+	}
+	chunkSize, err := br.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if chunkSize == 0 || chunkSize > 1<<30 {
+		return nil, errors.New("stream: invalid chunk size")
+	}
+	key, err := dec.Decapsulate(kemCt)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := c.NewAEAD(key)
+	wipe.Bytes(key)
+	if err != nil {
+		return nil, err
+	}
+	if aead.NonceSize() <= nonceSuffix {
+		return nil, errors.New("stream: AEAD nonce too short")
+	}
+	prefix, err := br.bytes(aead.NonceSize() - nonceSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		r:     r,
+		aead:  aead,
+		aad:   br.header,
+		nonce: append(prefix, make([]byte, nonceSuffix)...),
+		ct:    make([]byte, int(chunkSize)+aead.Overhead()),
+	}, nil
+}
+
+// Read implements io.Reader.
+func (sr *Reader) Read(p []byte) (int, error) {
+	for len(sr.pt) == 0 {
+		if sr.err != nil {
+			return 0, sr.err
+		}
+		if sr.done {
+			wipe.Bytes(sr.ptAll)
+			sr.ptAll = nil
+			sr.err = io.EOF
+			return 0, io.EOF
+		}
+		if err := sr.open(); err != nil {
+			sr.err = err
+			return 0, err
+		}
+	}
+	n := copy(p, sr.pt)
+	sr.pt = sr.pt[n:]
+
+	return n, nil
+}
+
+func (sr *Reader) open() error {
+	// Fill one ciphertext chunk, then look one byte ahead: a clean EOF on
+	// the chunk boundary marks it as the last chunk.
+	n := copy(sr.ct, sr.ahead)
+	sr.ahead = nil
+	m, err := io.ReadFull(sr.r, sr.ct[n:])
+	n += m
+	last := false
+	switch err {
+	case nil:
+		var one [1]byte
+		k, err := io.ReadFull(sr.r, one[:])
+		if err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		} else {
+			sr.ahead = one[:k]
+		}
+	case io.ErrUnexpectedEOF, io.EOF:
+		last = true
+	default:
+		return err
+	}
+	if last && n < sr.aead.Overhead() {
+		return ErrAuthentication
+	}
+
+	off := len(sr.nonce) - nonceSuffix
+	binary.BigEndian.PutUint32(sr.nonce[off:], sr.ctr)
+	if last {
+		sr.nonce[off+4] = 1
+	} else {
+		sr.nonce[off+4] = 0
+	}
+	pt, err := sr.aead.Open(nil, sr.nonce, sr.ct[:n], sr.aad)
+	if err != nil {
+		return ErrAuthentication
+	}
+	wipe.Bytes(sr.ptAll)
+	sr.pt, sr.ptAll = pt, pt
+	sr.ctr++
+	if sr.ctr == 0 && !last {
+		return errors.New("stream: chunk counter exhausted")
+	}
+	sr.done = last
+
+	return nil
+}
+
+// headerReader reads the header while keeping a copy of the bytes read,
+// which become the associated data of every chunk.
+type headerReader struct {
+	r      io.Reader
+	header []byte
+}
+
+func (h *headerReader) bytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(h.r, b); err != nil {
+		return nil, errors.New("stream: truncated header")
+	}
+	h.header = append(h.header, b...)
+
+	return b, nil
+}
+
+func (h *headerReader) uvarint() (uint64, error) {
+	var v uint64
+	for shift := 0; shift < 64; shift += 7 {
+		b, err := h.bytes(1)
+		if err != nil {
+			return 0, err
+		}
+		v |= uint64(b[0]&0x7f) << shift
+		if b[0] < 0x80 {
+			return v, nil
+		}
+	}
+
+	return 0, errors.New("stream: invalid header")
+}
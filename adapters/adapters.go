@@ -0,0 +1,72 @@
+// Package adapters exposes CIRCL keys through the standard library's
+// crypto.Signer and crypto.Decrypter, so application code shaped around
+// stdlib types — certificate issuance, key stores, HSM-style wrappers —
+// can use any sign.Scheme or kem.Scheme without knowing it.
+//
+// CIRCL signature schemes sign messages, not externally computed digests;
+// Sign therefore takes the message itself and requires opts with
+// crypto.Hash(0), as crypto/ed25519 does. Scheme options travel in
+// SignerOpts.
+package adapters
+
+import (
+	"crypto"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/sign"
+)
+
+// SignerOpts carries scheme-specific signature options through the
+// crypto.SignerOpts argument of crypto.Signer.
+type SignerOpts struct {
+	sign.SignatureOpts
+}
+
+// HashFunc returns zero, declaring that the message is not prehashed.
+func (SignerOpts) HashFunc() crypto.Hash { return crypto.Hash(0) }
+
+type signer struct{ key sign.PrivateKey }
+
+// NewSigner wraps a signing key as a crypto.Signer.
+func NewSigner(key sign.PrivateKey) crypto.Signer { return signer{key} }
+
+func (s signer) Public() crypto.PublicKey { return s.key.Public() }
+
+func (s signer) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var so *sign.SignatureOpts
+	switch o := opts.(type) {
+	case nil:
+	case SignerOpts:
+		so = &o.SignatureOpts
+	case *SignerOpts:
+		so = &o.SignatureOpts
+	default:
+		if o.HashFunc() != crypto.Hash(0) {
+			return nil, errors.New("adapters: message must not be prehashed")
+		}
+	}
+	if so != nil && so.Context != "" && !s.key.Scheme().SupportsContext() {
+		return nil, sign.ErrContextNotSupported
+	}
+
+	return s.key.Scheme().Sign(s.key, message, so), nil
+}
+
+type decrypter struct{ key kem.PrivateKey }
+
+// NewDecrypter wraps a KEM key as a crypto.Decrypter whose Decrypt is
+// decapsulation: the ciphertext is an encapsulation and the returned
+// plaintext is the shared key.
+func NewDecrypter(key kem.PrivateKey) crypto.Decrypter { return decrypter{key} }
+
+func (d decrypter) Public() crypto.PublicKey { return d.key.Public() }
+
+func (d decrypter) Decrypt(_ io.Reader, ciphertext []byte, opts crypto.DecrypterOpts) ([]byte, error) {
+	if opts != nil {
+		return nil, errors.New("adapters: no decrypter options are supported")
+	}
+
+	return d.key.Scheme().Decapsulate(d.key, ciphertext)
+}
@@ -0,0 +1,146 @@
+// Package beacon provides a drand-compatible distributed randomness beacon
+// built from threshold BLS signatures over the BLS12-381 curve.
+//
+// A committee of n parties holds Shamir shares of a BLS secret key, produced
+// for example by a distributed key generation. At every round each party
+// issues a partial signature over the round message, and any t+1 verified
+// partials can be combined into the unique round signature, whose hash is the
+// round's randomness. Rounds are chained: the message signed at round r
+// commits to the signature of round r-1, so a verifier holding only the
+// committee public key can audit an entire chain.
+package beacon
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/cloudflare/circl/ecc/bls12381/ff"
+)
+
+// DomainSeparationTag is the DST used when hashing round messages to the
+// curve, matching the drand mainnet configuration for G1 signatures.
+const DomainSeparationTag = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_"
+
+// Message returns the message signed at the given round of a chained beacon.
+// For the first round, prevSig is the group's genesis seed.
+func Message(round uint64, prevSig []byte) []byte {
+	h := sha256.New()
+	_, _ = h.Write(prevSig)
+	_ = binary.Write(h, binary.BigEndian, round)
+	return h.Sum(nil)
+}
+
+// UnchainedMessage returns the message signed at the given round of an
+// unchained beacon, which depends on the round number only.
+func UnchainedMessage(round uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], round)
+	digest := sha256.Sum256(buf[:])
+	return digest[:]
+}
+
+// Share is one party's share of the committee secret key.
+type Share struct {
+	// Index identifies the party; indices are positive and unique.
+	Index uint32
+	// Key is the party's Shamir share of the secret key.
+	Key ff.Scalar
+}
+
+// PartialSignature is one party's contribution to a round signature.
+type PartialSignature struct {
+	Index     uint32
+	Signature bls12381.G1
+}
+
+// PartialSign signs the round message with the party's key share.
+func (s *Share) PartialSign(msg []byte) PartialSignature {
+	var p bls12381.G1
+	p.Hash(msg, []byte(DomainSeparationTag))
+	p.ScalarMult(&s.Key, &p)
+	return PartialSignature{Index: s.Index, Signature: p}
+}
+
+// VerifyPartial checks a partial signature against the party's public key
+// share, which verifiers derive from the DKG commitment polynomial.
+func VerifyPartial(pubShare *bls12381.G2, msg []byte, ps *PartialSignature) bool {
+	return verifyBLS(pubShare, msg, &ps.Signature)
+}
+
+// ErrTooFewPartials is returned when fewer than t+1 partial signatures are
+// provided to Combine.
+var ErrTooFewPartials = errors.New("beacon: not enough partial signatures")
+
+// Combine aggregates t+1 partial signatures into the round signature by
+// Lagrange interpolation in the exponent. The partials must carry distinct
+// indices and must have been verified by the caller.
+func Combine(t uint, partials []PartialSignature) (*bls12381.G1, error) {
+	if uint(len(partials)) < t+1 {
+		return nil, ErrTooFewPartials
+	}
+	partials = partials[:t+1]
+
+	sig := new(bls12381.G1)
+	sig.SetIdentity()
+	for i := range partials {
+		var l ff.Scalar
+		if err := lagrangeAtZero(&l, partials, i); err != nil {
+			return nil, err
+		}
+		var term bls12381.G1
+		term.ScalarMult(&l, &partials[i].Signature)
+		sig.Add(sig, &term)
+	}
+
+	return sig, nil
+}
+
+func lagrangeAtZero(l *ff.Scalar, partials []PartialSignature, i int) error {
+	var num, den, xi, xj ff.Scalar
+	num.SetOne()
+	den.SetOne()
+	xi.SetUint64(uint64(partials[i].Index))
+	for j := range partials {
+		if j == i {
+			continue
+		}
+		if partials[j].Index == partials[i].Index {
+			return errors.New("beacon: duplicated partial signature index")
+		}
+		xj.SetUint64(uint64(partials[j].Index))
+		num.Mul(&num, &xj)
+		var d ff.Scalar
+		d.Sub(&xj, &xi)
+		den.Mul(&den, &d)
+	}
+	den.Inv(&den)
+	l.Mul(&num, &den)
+
+	return nil
+}
+
+// VerifyRound checks the signature of one round of a chained beacon against
+// the committee public key and the previous round's signature.
+func VerifyRound(pub *bls12381.G2, round uint64, prevSig []byte, sig *bls12381.G1) bool {
+	return verifyBLS(pub, Message(round, prevSig), sig)
+}
+
+// Randomness derives the round's public randomness from its signature.
+func Randomness(sig *bls12381.G1) []byte {
+	digest := sha256.Sum256(sig.BytesCompressed())
+	return digest[:]
+}
+
+func verifyBLS(pub *bls12381.G2, msg []byte, sig *bls12381.G1) bool {
+	if !sig.IsOnG1() {
+		return false
+	}
+	var h bls12381.G1
+	h.Hash(msg, []byte(DomainSeparationTag))
+	g2 := bls12381.G2Generator()
+	lhs := bls12381.Pair(sig, g2)
+	rhs := bls12381.Pair(&h, pub)
+	return lhs.IsEqual(rhs)
+}
@@ -0,0 +1,110 @@
+package beacon_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/cloudflare/circl/beacon"
+	"github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/cloudflare/circl/ecc/bls12381/ff"
+)
+
+// dealShares produces n Shamir shares of a fresh secret key with
+// threshold t, together with the group public key and the per-party
+// public key shares.
+func dealShares(t *testing.T, thr uint, n int) ([]beacon.Share, *bls12381.G2, []bls12381.G2) {
+	t.Helper()
+	poly := make([]ff.Scalar, thr+1)
+	for i := range poly {
+		if err := poly[i].Random(rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	shares := make([]beacon.Share, n)
+	pubShares := make([]bls12381.G2, n)
+	for i := range shares {
+		var x, acc ff.Scalar
+		x.SetUint64(uint64(i + 1))
+		acc = poly[len(poly)-1]
+		for j := len(poly) - 2; j >= 0; j-- {
+			acc.Mul(&acc, &x)
+			acc.Add(&acc, &poly[j])
+		}
+		shares[i] = beacon.Share{Index: uint32(i + 1), Key: acc}
+		pubShares[i].ScalarMult(&acc, bls12381.G2Generator())
+	}
+
+	var pub bls12381.G2
+	pub.ScalarMult(&poly[0], bls12381.G2Generator())
+
+	return shares, &pub, pubShares
+}
+
+func TestBeaconRound(t *testing.T) {
+	const thr, n = 2, 5
+	shares, pub, pubShares := dealShares(t, thr, n)
+
+	genesis := []byte("genesis seed")
+	round := uint64(1)
+	msg := beacon.Message(round, genesis)
+
+	partials := make([]beacon.PartialSignature, 0, thr+1)
+	for _, i := range []int{4, 1, 2} { // any t+1 distinct parties
+		ps := shares[i].PartialSign(msg)
+		if !beacon.VerifyPartial(&pubShares[i], msg, &ps) {
+			t.Fatalf("partial signature of party %d rejected", i+1)
+		}
+		partials = append(partials, ps)
+	}
+
+	sig, err := beacon.Combine(thr, partials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !beacon.VerifyRound(pub, round, genesis, sig) {
+		t.Fatal("combined round signature rejected")
+	}
+	if beacon.VerifyRound(pub, round+1, genesis, sig) {
+		t.Fatal("signature accepted for the wrong round")
+	}
+
+	// A different committee subset must combine to the same signature.
+	other := []beacon.PartialSignature{
+		shares[0].PartialSign(msg),
+		shares[3].PartialSign(msg),
+		shares[4].PartialSign(msg),
+	}
+	sig2, err := beacon.Combine(thr, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig.BytesCompressed(), sig2.BytesCompressed()) {
+		t.Fatal("combination depends on the chosen subset")
+	}
+
+	if out := beacon.Randomness(sig); len(out) != 32 {
+		t.Fatal("unexpected randomness length")
+	}
+}
+
+func TestBeaconErrors(t *testing.T) {
+	const thr, n = 1, 3
+	shares, _, _ := dealShares(t, thr, n)
+	msg := beacon.UnchainedMessage(7)
+
+	_, err := beacon.Combine(thr, []beacon.PartialSignature{shares[0].PartialSign(msg)})
+	if !errors.Is(err, beacon.ErrTooFewPartials) {
+		t.Fatal("missing partials not reported")
+	}
+
+	dup := []beacon.PartialSignature{
+		shares[0].PartialSign(msg),
+		shares[0].PartialSign(msg),
+	}
+	if _, err = beacon.Combine(thr, dup); err == nil {
+		t.Fatal("duplicated index not reported")
+	}
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/cloudflare/circl/schemes"
+	"github.com/cloudflare/circl/sign"
+)
+
+func cmdList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	kind := fs.String("kind", "", "restrict to kem, signature, or group")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var k schemes.Kind
+	switch *kind {
+	case "":
+	case "kem":
+		k = schemes.KindKEM
+	case "signature":
+		k = schemes.KindSignature
+	case "group":
+		k = schemes.KindGroup
+	default:
+		return errors.New("unknown kind " + *kind)
+	}
+	for _, e := range schemes.All(k) {
+		line := fmt.Sprintf("%-12s %s", e.Kind, e.Name)
+		if len(e.OID) != 0 {
+			line += "  oid=" + e.OID.String()
+		}
+		if e.Codepoint != 0 {
+			line += fmt.Sprintf("  codepoint=0x%04x", e.Codepoint)
+		}
+		fmt.Println(line)
+	}
+
+	return nil
+}
+
+func cmdKeygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	scheme := fs.String("scheme", "", "scheme name")
+	out := fs.String("out", "", "private key output file")
+	pub := fs.String("pub", "", "public key output file")
+	raw := fs.Bool("raw", false, "write raw bytes instead of PEM")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *scheme == "" || *out == "" || *pub == "" {
+		return errors.New("keygen needs -scheme, -out, and -pub")
+	}
+	e, ok := schemes.ByName(*scheme)
+	if !ok {
+		return errors.New("unknown scheme " + *scheme)
+	}
+	var pkRaw, skRaw []byte
+	var err error
+	switch e.Kind {
+	case schemes.KindSignature:
+		pk, sk, err2 := e.Signature.GenerateKey()
+		if err2 != nil {
+			return err2
+		}
+		if pkRaw, err = pk.MarshalBinary(); err != nil {
+			return err
+		}
+		skRaw, err = sk.MarshalBinary()
+	case schemes.KindKEM:
+		pk, sk, err2 := e.KEM.GenerateKeyPair()
+		if err2 != nil {
+			return err2
+		}
+		if pkRaw, err = pk.MarshalBinary(); err != nil {
+			return err
+		}
+		skRaw, err = sk.MarshalBinary()
+	default:
+		return errors.New("scheme has no key pairs")
+	}
+	if err != nil {
+		return err
+	}
+	if err = writeKey(*out, e.Name, blockPrivate, skRaw, *raw, 0o600); err != nil {
+		return err
+	}
+
+	return writeKey(*pub, e.Name, blockPublic, pkRaw, *raw, 0o644)
+}
+
+func cmdSign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	key := fs.String("key", "", "private key file")
+	in := fs.String("in", "", "message file")
+	out := fs.String("out", "", "signature output file")
+	ctx := fs.String("ctx", "", "signature context")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *key == "" || *in == "" || *out == "" {
+		return errors.New("sign needs -key, -in, and -out")
+	}
+	name, skRaw, err := readKey(*key, blockPrivate)
+	if err != nil {
+		return err
+	}
+	e, ok := schemes.ByName(name)
+	if !ok || e.Kind != schemes.KindSignature {
+		return errors.New("key is not for a known signature scheme")
+	}
+	sk, err := e.Signature.UnmarshalBinaryPrivateKey(skRaw)
+	if err != nil {
+		return err
+	}
+	msg, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	opts := signOpts(*ctx, e.Signature)
+	if opts == nil && *ctx != "" {
+		return errors.New("scheme does not support contexts")
+	}
+
+	return os.WriteFile(*out, e.Signature.Sign(sk, msg, opts), 0o644)
+}
+
+func cmdVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	pub := fs.String("pub", "", "public key file")
+	in := fs.String("in", "", "message file")
+	sig := fs.String("sig", "", "signature file")
+	ctx := fs.String("ctx", "", "signature context")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *pub == "" || *in == "" || *sig == "" {
+		return errors.New("verify needs -pub, -in, and -sig")
+	}
+	name, pkRaw, err := readKey(*pub, blockPublic)
+	if err != nil {
+		return err
+	}
+	e, ok := schemes.ByName(name)
+	if !ok || e.Kind != schemes.KindSignature {
+		return errors.New("key is not for a known signature scheme")
+	}
+	pk, err := e.Signature.UnmarshalBinaryPublicKey(pkRaw)
+	if err != nil {
+		return err
+	}
+	msg, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	sigBytes, err := os.ReadFile(*sig)
+	if err != nil {
+		return err
+	}
+	opts := signOpts(*ctx, e.Signature)
+	if opts == nil && *ctx != "" {
+		return errors.New("scheme does not support contexts")
+	}
+	if !e.Signature.Verify(pk, msg, sigBytes, opts) {
+		return errors.New("signature does not verify")
+	}
+	fmt.Println("OK")
+
+	return nil
+}
+
+// signOpts returns nil for an unsupported non-empty context.
+func signOpts(ctx string, s sign.Scheme) *sign.SignatureOpts {
+	if ctx == "" {
+		return &sign.SignatureOpts{}
+	}
+	if !s.SupportsContext() {
+		return nil
+	}
+
+	return &sign.SignatureOpts{Context: ctx}
+}
+
+func cmdEncap(args []string) error {
+	fs := flag.NewFlagSet("encap", flag.ExitOnError)
+	pub := fs.String("pub", "", "public key file")
+	ct := fs.String("ct", "", "ciphertext output file")
+	ss := fs.String("ss", "", "shared secret output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *pub == "" || *ct == "" || *ss == "" {
+		return errors.New("encap needs -pub, -ct, and -ss")
+	}
+	e, pk, err := readKEMPublic(*pub)
+	if err != nil {
+		return err
+	}
+	ctBytes, ssBytes, err := e.KEM.Encapsulate(pk)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(*ct, ctBytes, 0o644); err != nil {
+		return err
+	}
+
+	return os.WriteFile(*ss, ssBytes, 0o600)
+}
+
+func cmdDecap(args []string) error {
+	fs := flag.NewFlagSet("decap", flag.ExitOnError)
+	key := fs.String("key", "", "private key file")
+	ct := fs.String("ct", "", "ciphertext file")
+	ss := fs.String("ss", "", "shared secret output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *key == "" || *ct == "" || *ss == "" {
+		return errors.New("decap needs -key, -ct, and -ss")
+	}
+	e, sk, err := readKEMPrivate(*key)
+	if err != nil {
+		return err
+	}
+	ctBytes, err := os.ReadFile(*ct)
+	if err != nil {
+		return err
+	}
+	ssBytes, err := e.KEM.Decapsulate(sk, ctBytes)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(*ss, ssBytes, 0o600)
+}
+
+func cmdSeal(args []string) error {
+	fs := flag.NewFlagSet("seal", flag.ExitOnError)
+	pub := fs.String("pub", "", "public key file")
+	in := fs.String("in", "", "plaintext file")
+	out := fs.String("out", "", "sealed output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *pub == "" || *in == "" || *out == "" {
+		return errors.New("seal needs -pub, -in, and -out")
+	}
+	e, pk, err := readKEMPublic(*pub)
+	if err != nil {
+		return err
+	}
+	pt, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	sealed, err := seal(e, pk, pt)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(*out, sealed, 0o644)
+}
+
+func cmdOpen(args []string) error {
+	fs := flag.NewFlagSet("open", flag.ExitOnError)
+	key := fs.String("key", "", "private key file")
+	in := fs.String("in", "", "sealed file")
+	out := fs.String("out", "", "plaintext output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *key == "" || *in == "" || *out == "" {
+		return errors.New("open needs -key, -in, and -out")
+	}
+	e, sk, err := readKEMPrivate(*key)
+	if err != nil {
+		return err
+	}
+	sealed, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	pt, err := open(e, sk, sealed)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(*out, pt, 0o644)
+}
+
+func readKEMPublic(path string) (schemes.Entry, kemPublicKey, error) {
+	name, raw, err := readKey(path, blockPublic)
+	if err != nil {
+		return schemes.Entry{}, nil, err
+	}
+	e, ok := schemes.ByName(name)
+	if !ok || e.Kind != schemes.KindKEM {
+		return schemes.Entry{}, nil, errors.New("key is not for a known KEM")
+	}
+	pk, err := e.KEM.UnmarshalBinaryPublicKey(raw)
+
+	return e, pk, err
+}
+
+func readKEMPrivate(path string) (schemes.Entry, kemPrivateKey, error) {
+	name, raw, err := readKey(path, blockPrivate)
+	if err != nil {
+		return schemes.Entry{}, nil, err
+	}
+	e, ok := schemes.ByName(name)
+	if !ok || e.Kind != schemes.KindKEM {
+		return schemes.Entry{}, nil, errors.New("key is not for a known KEM")
+	}
+	sk, err := e.KEM.UnmarshalBinaryPrivateKey(raw)
+
+	return e, sk, err
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/cloudflare/circl/rand"
+	"github.com/cloudflare/circl/schemes"
+)
+
+// The seal/open envelope: magic, encapsulation length, encapsulation,
+// nonce, AES-256-GCM ciphertext. The AEAD key is derived from the KEM
+// shared secret; the magic doubles as derivation label and version.
+var envMagic = []byte("circl-seal-v1\x00")
+
+func envelopeKey(ss []byte) []byte {
+	h := sha256.New()
+	h.Write(envMagic)
+	h.Write(ss)
+
+	return h.Sum(nil)
+}
+
+func newEnvelopeAEAD(ss []byte) (cipher.AEAD, error) {
+	blk, err := aes.NewCipher(envelopeKey(ss))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(blk)
+}
+
+func seal(e schemes.Entry, pk kemPublicKey, pt []byte) ([]byte, error) {
+	ct, ss, err := e.KEM.Encapsulate(pk)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newEnvelopeAEAD(ss)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte{}, envMagic...)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(ct)))
+	out = append(out, ct...)
+	out = append(out, nonce...)
+
+	return aead.Seal(out, nonce, pt, envMagic), nil
+}
+
+func open(e schemes.Entry, sk kemPrivateKey, sealed []byte) ([]byte, error) {
+	fail := errors.New("sealed file is malformed or corrupt")
+	if len(sealed) < len(envMagic)+4 || string(sealed[:len(envMagic)]) != string(envMagic) {
+		return nil, fail
+	}
+	in := sealed[len(envMagic):]
+	n := int(binary.BigEndian.Uint32(in))
+	in = in[4:]
+	if n < 0 || len(in) < n {
+		return nil, fail
+	}
+	ct, in := in[:n], in[n:]
+	ss, err := e.KEM.Decapsulate(sk, ct)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newEnvelopeAEAD(ss)
+	if err != nil {
+		return nil, err
+	}
+	if len(in) < aead.NonceSize() {
+		return nil, fail
+	}
+	nonce, in := in[:aead.NonceSize()], in[aead.NonceSize():]
+	pt, err := aead.Open(nil, nonce, in, envMagic)
+	if err != nil {
+		return nil, fail
+	}
+
+	return pt, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/pem"
+	"errors"
+	"os"
+
+	"github.com/cloudflare/circl/kem"
+)
+
+type (
+	kemPublicKey  = kem.PublicKey
+	kemPrivateKey = kem.PrivateKey
+)
+
+// PEM block types and the header carrying the scheme name.
+const (
+	blockPublic  = "CIRCL PUBLIC KEY"
+	blockPrivate = "CIRCL PRIVATE KEY"
+	headerScheme = "Scheme"
+)
+
+// writeKey writes a key as PEM with the scheme name in a header, or as
+// raw bytes with raw set. Raw files are for export to other tooling; the
+// other commands read the PEM form, which identifies the scheme.
+func writeKey(path, scheme, blockType string, key []byte, raw bool, mode os.FileMode) error {
+	if raw {
+		return os.WriteFile(path, key, mode)
+	}
+	p := pem.EncodeToMemory(&pem.Block{
+		Type:    blockType,
+		Headers: map[string]string{headerScheme: scheme},
+		Bytes:   key,
+	})
+
+	return os.WriteFile(path, p, mode)
+}
+
+// readKey reads a PEM key written by writeKey, returning the scheme name
+// and the raw key.
+func readKey(path, blockType string) (scheme string, key []byte, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	blk, _ := pem.Decode(data)
+	if blk == nil || blk.Type != blockType {
+		return "", nil, errors.New("no " + blockType + " PEM block in " + path)
+	}
+	scheme = blk.Headers[headerScheme]
+	if scheme == "" {
+		return "", nil, errors.New("PEM block misses the Scheme header")
+	}
+
+	return scheme, blk.Bytes, nil
+}
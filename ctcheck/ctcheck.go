@@ -0,0 +1,158 @@
+// Package ctcheck provides a statistical timing self-check in the style
+// of dudect (Reparaz, Balasch, and Verbauwhede, DATE 2017): an operation
+// is run many times with secrets drawn from two classes — one fixed, one
+// random — in random order, and Welch's t-test decides whether the two
+// timing populations are distinguishable. A distinguishable split means
+// the operation's running time depends on the secret.
+//
+// The point of running this in-process, rather than on a lab bench, is
+// that it exercises the exact binary being deployed: the compiler
+// version, build tags, and the vectorized backends selected on the
+// deployment CPU all affect constant-time behaviour. The check is a
+// smoke test, not a proof: it can only demonstrate leakage, never its
+// absence, and noisy machines need more samples for a stable verdict.
+package ctcheck
+
+import (
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cloudflare/circl/rand"
+)
+
+// Options tunes a measurement. The zero value of a field means its
+// default.
+type Options struct {
+	// Samples is the number of timed invocations; default 100000.
+	Samples int
+	// SecretSize is the length of the secrets handed to the operation;
+	// default 32.
+	SecretSize int
+	// Percentile crops the slowest fraction of samples for the second
+	// test, discarding interrupt and GC outliers; default 0.9 keeps the
+	// fastest 90%.
+	Percentile float64
+}
+
+func (o *Options) fill() Options {
+	out := Options{Samples: 100000, SecretSize: 32, Percentile: 0.9}
+	if o != nil {
+		if o.Samples != 0 {
+			out.Samples = o.Samples
+		}
+		if o.SecretSize != 0 {
+			out.SecretSize = o.SecretSize
+		}
+		if o.Percentile != 0 {
+			out.Percentile = o.Percentile
+		}
+	}
+
+	return out
+}
+
+// Result is the outcome of a measurement.
+type Result struct {
+	// Samples is the number of timed invocations.
+	Samples int
+	// T is Welch's t-statistic over all samples.
+	T float64
+	// TCropped is the statistic with the slowest samples discarded,
+	// which is usually the more sensitive of the two.
+	TCropped float64
+}
+
+// Leaky reports whether the timing split is distinguishable beyond
+// doubt, using the |t| > 10 verdict of dudect. Values between the
+// classical 4.5 significance threshold and 10 deserve a rerun with more
+// samples.
+func (r Result) Leaky() bool {
+	return math.Abs(r.T) > 10 || math.Abs(r.TCropped) > 10
+}
+
+// Measure times op over the two secret classes and returns the test
+// statistics. The secret buffer is owned by the harness and refilled
+// between invocations; op must not retain it. A nil rnd selects the
+// library-wide source.
+func Measure(op func(secret []byte), rnd io.Reader, opt *Options) (Result, error) {
+	rnd = rand.Source(rnd)
+	o := opt.fill()
+
+	fixed := make([]byte, o.SecretSize)
+	if _, err := io.ReadFull(rnd, fixed); err != nil {
+		return Result{}, err
+	}
+	secret := make([]byte, o.SecretSize)
+
+	// Draw the class bits and the random-class secrets up front, so the
+	// timed loop does not time the RNG.
+	classBytes := make([]byte, (o.Samples+7)/8)
+	if _, err := io.ReadFull(rnd, classBytes); err != nil {
+		return Result{}, err
+	}
+	pool := make([]byte, o.Samples*o.SecretSize)
+	if _, err := io.ReadFull(rnd, pool); err != nil {
+		return Result{}, err
+	}
+
+	// Warm up caches and the branch predictor outside the measurement.
+	for i := 0; i < 16; i++ {
+		op(fixed)
+	}
+
+	times := make([]float64, o.Samples)
+	classes := make([]bool, o.Samples)
+	for i := range times {
+		random := classBytes[i/8]>>(i%8)&1 == 1
+		if random {
+			copy(secret, pool[i*o.SecretSize:])
+		} else {
+			copy(secret, fixed)
+		}
+		start := time.Now()
+		op(secret)
+		times[i] = float64(time.Since(start).Nanoseconds())
+		classes[i] = random
+	}
+
+	res := Result{Samples: o.Samples, T: welch(times, classes, math.Inf(1))}
+	sorted := append([]float64{}, times...)
+	sort.Float64s(sorted)
+	crop := sorted[int(float64(len(sorted)-1)*o.Percentile)]
+	res.TCropped = welch(times, classes, crop)
+
+	return res, nil
+}
+
+// welch computes Welch's t-statistic between the two classes, ignoring
+// samples above the crop threshold.
+func welch(times []float64, classes []bool, crop float64) float64 {
+	var n [2]float64
+	var mean [2]float64
+	var m2 [2]float64
+	for i, t := range times {
+		if t > crop {
+			continue
+		}
+		c := 0
+		if classes[i] {
+			c = 1
+		}
+		n[c]++
+		d := t - mean[c]
+		mean[c] += d / n[c]
+		m2[c] += d * (t - mean[c])
+	}
+	if n[0] < 2 || n[1] < 2 {
+		return 0
+	}
+	v0, v1 := m2[0]/(n[0]-1), m2[1]/(n[1]-1)
+	denom := math.Sqrt(v0/n[0] + v1/n[1])
+	if denom == 0 {
+		return 0
+	}
+
+	return (mean[0] - mean[1]) / denom
+}
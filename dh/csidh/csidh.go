@@ -0,0 +1,546 @@
+// Package csidh implements the commutative supersingular-isogeny
+// Diffie-Hellman key exchange CSIDH-512.
+//
+// A public key is a supersingular Montgomery curve over F_p, identified
+// by its coefficient A; a private key is a vector of small exponents,
+// one per split prime, describing a walk in the isogeny graph. The
+// class-group action that evaluates the walk is commutative, which is
+// what makes the non-interactive exchange work. The evaluation here is
+// constant time with respect to the private key: every prime always
+// contributes exactly expMax isogeny steps, padding the real ones with
+// dummy computations. See dummyfree.go for an evaluation that avoids
+// even the dummies.
+//
+// CSIDH offers conjectured post-quantum security far below the levels
+// of the NIST primitives at these sizes and its quantum security is the
+// subject of ongoing debate; treat this package as experimental.
+//
+// References:
+//
+//	Castryck, Lange, Martindale, Panny, Renes. "CSIDH: An efficient
+//	post-quantum commutative group action". https://ia.cr/2018/383
+//	Meyer, Campos, Reith. "On Lions and Elligators". https://ia.cr/2018/1198
+package csidh
+
+import (
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/cloudflare/circl/rand"
+)
+
+const (
+	// numPrimes is the number of split primes l_i; p = 4*prod(l_i) - 1.
+	numPrimes = 74
+	// expMax bounds the absolute value of a private-key exponent.
+	expMax = 5
+
+	// PrivateKeySize is the length in bytes of an exported private key,
+	// two exponents per byte.
+	PrivateKeySize = numPrimes / 2
+	// PublicKeySize is the length in bytes of an exported public key.
+	PublicKeySize = 64
+	// SharedSecretSize is the length in bytes of a shared secret.
+	SharedSecretSize = 64
+)
+
+// primes are the split primes of CSIDH-512: the 73 smallest odd primes
+// and 587, chosen so that p = 4*prod - 1 is prime.
+var primes = [numPrimes]uint64{
+	3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67,
+	71, 73, 79, 83, 89, 97, 101, 103, 107, 109, 113, 127, 131, 137, 139,
+	149, 151, 157, 163, 167, 173, 179, 181, 191, 193, 197, 199, 211, 223,
+	227, 229, 233, 239, 241, 251, 257, 263, 269, 271, 277, 281, 283, 293,
+	307, 311, 313, 317, 331, 337, 347, 349, 353, 359, 367, 373, 587,
+}
+
+// fp is an element of F_p in eight 64-bit words, little endian, always
+// kept reduced below p.
+type fp [8]uint64
+
+// PublicKey is the Montgomery coefficient of a supersingular curve.
+type PublicKey struct {
+	a fp
+}
+
+// PrivateKey is the exponent vector of the class-group element.
+type PrivateKey struct {
+	e [numPrimes]int8
+}
+
+// Words of p and of (p-1)/2, and the Montgomery constants: r2 is
+// 2^1024 mod p and pinv is -p^-1 mod 2^64. All are derived from the
+// prime table at startup.
+var (
+	pWords, pHalfWords [8]uint64
+	r2                 fp
+	pinv               uint64
+)
+
+func init() {
+	// prod = product of the small primes; p = 4*prod - 1, so
+	// (p-1)/2 = 2*prod - 1 and both fit the eight words of an fp.
+	var prod [8]uint64
+	prod[0] = 1
+	for _, l := range primes {
+		var c uint64
+		for j := range prod {
+			hi, lo := bits.Mul64(prod[j], l)
+			prod[j] = lo + c
+			if prod[j] < c {
+				hi++
+			}
+			c = hi
+		}
+	}
+	for j := 7; j > 0; j-- {
+		pWords[j] = prod[j]<<2 | prod[j-1]>>62
+		pHalfWords[j] = prod[j]<<1 | prod[j-1]>>63
+	}
+	pWords[0] = prod[0]<<2 - 1
+	pHalfWords[0] = prod[0]<<1 - 1
+
+	// Newton iteration for the word inverse: each step doubles the
+	// number of correct low bits, starting from at least three for an
+	// odd modulus.
+	inv := pWords[0]
+	for i := 0; i < 6; i++ {
+		inv *= 2 - pWords[0]*inv
+	}
+	pinv = -inv
+
+	// r2 = 2^1024 mod p, by doubling.
+	r2[0] = 1
+	for i := 0; i < 1024; i++ {
+		fpAdd(&r2, &r2, &r2)
+	}
+}
+
+// fpAdd sets z = x + y mod p. Inputs below p, output below p, no
+// branches on the values.
+func fpAdd(z, x, y *fp) {
+	var t, u fp
+	var c uint64
+	for i := range t {
+		t[i], c = bits.Add64(x[i], y[i], c)
+	}
+	var b uint64
+	for i := range u {
+		u[i], b = bits.Sub64(t[i], pWords[i], b)
+	}
+	// x + y < 2p < 2^512, so the top carry is zero and the reduced
+	// value is needed exactly when the subtraction did not borrow.
+	mask := b - 1
+	for i := range z {
+		z[i] = (t[i] &^ mask) | (u[i] & mask)
+	}
+}
+
+// fpSub sets z = x - y mod p.
+func fpSub(z, x, y *fp) {
+	var t fp
+	var b uint64
+	for i := range t {
+		t[i], b = bits.Sub64(x[i], y[i], b)
+	}
+	mask := -b
+	var c uint64
+	for i := range z {
+		z[i], c = bits.Add64(t[i], pWords[i]&mask, c)
+	}
+}
+
+// montMul sets z = x * y / 2^512 mod p by coarsely integrated operand
+// scanning, with the final conditional subtraction done by masking.
+func montMul(z, x, y *fp) {
+	var t [10]uint64
+	for i := 0; i < 8; i++ {
+		var c uint64
+		for j := 0; j < 8; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			s, c1 := bits.Add64(t[j], lo, 0)
+			s, c2 := bits.Add64(s, c, 0)
+			t[j] = s
+			c = hi + c1 + c2 // hi <= 2^64-2, so this cannot overflow
+		}
+		s, c1 := bits.Add64(t[8], c, 0)
+		t[8] = s
+		t[9] += c1
+
+		m := t[0] * pinv
+		c = 0
+		for j := 0; j < 8; j++ {
+			hi, lo := bits.Mul64(m, pWords[j])
+			s, c1 := bits.Add64(t[j], lo, 0)
+			s, c2 := bits.Add64(s, c, 0)
+			t[j] = s
+			c = hi + c1 + c2
+		}
+		s, c1 = bits.Add64(t[8], c, 0)
+		t[8] = s
+		t[9] += c1
+
+		// t[0] is zero now; shift down one word.
+		for j := 0; j < 9; j++ {
+			t[j] = t[j+1]
+		}
+		t[9] = 0
+	}
+
+	// The result is below 2p, which fits eight words.
+	var u fp
+	var b uint64
+	for i := range u {
+		u[i], b = bits.Sub64(t[i], pWords[i], b)
+	}
+	mask := b - 1
+	for i := range z {
+		z[i] = (t[i] &^ mask) | (u[i] & mask)
+	}
+}
+
+// fpMul sets z = x * y mod p. Values live in the plain domain; the
+// Montgomery factor of montMul is cancelled by a multiplication with
+// r2.
+func fpMul(z, x, y *fp) {
+	var t fp
+	montMul(&t, x, y)
+	montMul(z, &t, &r2)
+}
+
+// fpSqr sets z = x^2 mod p.
+func fpSqr(z, x *fp) { fpMul(z, x, x) }
+
+// fpInv sets z = x^(p-2) mod p, which is the inverse for nonzero x and
+// zero for x = 0. The ladder runs in the Montgomery domain so each
+// step costs a single reduction.
+func fpInv(z, x *fp) {
+	var xm, acc fp
+	montMul(&xm, x, &r2)
+	montMul(&acc, &r2, &one) // 1 in the Montgomery domain
+
+	e := pWords
+	e[0] -= 2 // p is odd and above two, so no borrow
+	for i := 7; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			montMul(&acc, &acc, &acc)
+			if e[i]>>uint(j)&1 == 1 {
+				montMul(&acc, &acc, &xm)
+			}
+		}
+	}
+	montMul(z, &acc, &one)
+}
+
+var one = fp{1}
+
+// fpSetOne sets z = 1.
+func fpSetOne(z *fp) { *z = one }
+
+// fpIsZero returns 1 when x is zero and 0 otherwise, in constant time.
+func fpIsZero(x *fp) uint {
+	var v uint64
+	for i := range x {
+		v |= x[i]
+	}
+
+	return uint(1 ^ (v|-v)>>63)
+}
+
+// fpCmov copies x into z when c is 1 and leaves z alone when c is 0.
+func fpCmov(z, x *fp, c uint) {
+	mask := -uint64(c & 1)
+	for i := range z {
+		z[i] = (z[i] &^ mask) | (x[i] & mask)
+	}
+}
+
+// GeneratePrivateKey samples a private key with every exponent uniform
+// on [-expMax, expMax], reading randomness from rng or from the
+// library-wide source when rng is nil.
+func GeneratePrivateKey(key *PrivateKey, rng io.Reader) error {
+	rng = rand.Source(rng)
+	var b [1]byte
+	for i := range key.e {
+		for {
+			if _, err := io.ReadFull(rng, b[:]); err != nil {
+				return err
+			}
+			lim := byte(256 - 256%(2*expMax+1))
+			if b[0] < lim {
+				key.e[i] = int8(int(b[0]%(2*expMax+1)) - expMax)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// GeneratePublicKey computes the public key of prv: the action of prv
+// on the base curve A = 0. rng supplies the public randomness that
+// drives point sampling during the evaluation.
+func GeneratePublicKey(key *PublicKey, prv *PrivateKey, rng io.Reader) {
+	var base fp
+	key.a = action(&base, prv, rand.Source(rng))
+}
+
+// DeriveSecret computes the shared secret: the action of prv on the
+// peer's curve. It returns false without touching out when the public
+// key fails validation.
+func DeriveSecret(out *[SharedSecretSize]byte, pub *PublicKey, prv *PrivateKey, rng io.Reader) bool {
+	rng = rand.Source(rng)
+	if !Validate(pub, rng) {
+		return false
+	}
+	a := action(&pub.a, prv, rng)
+	for i := 0; i < SharedSecretSize; i++ {
+		out[i] = byte(a[i/8] >> (8 * (i % 8)))
+	}
+
+	return true
+}
+
+// Export writes the private key as two exponents per byte, each a
+// signed four-bit value.
+func (c *PrivateKey) Export(out []byte) bool {
+	if len(out) != PrivateKeySize {
+		return false
+	}
+	for i := range out {
+		out[i] = uint8(c.e[2*i])&0xf | uint8(c.e[2*i+1])<<4
+	}
+
+	return true
+}
+
+// Import reads a private key written by Export, rejecting out-of-range
+// exponents.
+func (c *PrivateKey) Import(data []byte) bool {
+	if len(data) != PrivateKeySize {
+		return false
+	}
+	var e [numPrimes]int8
+	for i, b := range data {
+		e[2*i] = int8(b<<4) >> 4
+		e[2*i+1] = int8(b) >> 4
+		if e[2*i] < -expMax || e[2*i] > expMax ||
+			e[2*i+1] < -expMax || e[2*i+1] > expMax {
+			return false
+		}
+	}
+	c.e = e
+
+	return true
+}
+
+// Export writes the public key as the curve coefficient in 64
+// little-endian bytes.
+func (c *PublicKey) Export(out []byte) bool {
+	if len(out) != PublicKeySize {
+		return false
+	}
+	for i := range out {
+		out[i] = byte(c.a[i/8] >> (8 * (i % 8)))
+	}
+
+	return true
+}
+
+// Import reads a public key written by Export, rejecting non-canonical
+// values. It does not check supersingularity; DeriveSecret does.
+func (c *PublicKey) Import(data []byte) bool {
+	if len(data) != PublicKeySize {
+		return false
+	}
+	var a fp
+	for i, b := range data {
+		a[i/8] |= uint64(b) << (8 * (i % 8))
+	}
+	var borrow uint64
+	for i := range a {
+		_, borrow = bits.Sub64(a[i], pWords[i], borrow)
+	}
+	if borrow == 0 { // a >= p
+		return false
+	}
+	c.a = a
+
+	return true
+}
+
+// Validate checks that pub is a supersingular curve, which is what
+// guarantees the action is well defined and no information about our
+// private key leaks through a malicious key. The test of Castryck et
+// al., section 5: the order of a random point on a supersingular curve
+// divides p+1, and collecting enough of its prime factors certifies a
+// point order above 4*sqrt(p), which ordinary curves cannot have. The
+// inputs are public, so the variable-time arithmetic leaks nothing.
+func Validate(pub *PublicKey, rng io.Reader) bool {
+	rng = rand.Source(rng)
+
+	// A = ±2 is singular, not supersingular.
+	var two, t fp
+	fpSetOne(&two)
+	fpAdd(&two, &two, &two)
+	fpAdd(&t, &pub.a, &two)
+	if fpIsZero(&t) == 1 {
+		return false
+	}
+	fpSub(&t, &pub.a, &two)
+	if fpIsZero(&t) == 1 {
+		return false
+	}
+
+	p := new(big.Int)
+	for i := 7; i >= 0; i-- {
+		p.Lsh(p, 64)
+		p.Or(p, new(big.Int).SetUint64(pWords[i]))
+	}
+	bound := new(big.Int).Sqrt(p)
+	bound.Lsh(bound, 2) // 4*sqrt(p)
+	order := big.NewInt(1)
+
+	a24 := a24FromA(&pub.a)
+	for attempts := 0; attempts < 8; attempts++ {
+		// A random x lands on the curve or its twist; both have order
+		// p+1 when the curve is supersingular, so either side works.
+		var b [64]byte
+		if _, err := io.ReadFull(rng, b[:]); err != nil {
+			panic(err)
+		}
+		var P dfPoint
+		for i := 0; i < 64; i++ {
+			P.x[i/8] |= uint64(b[i]) << (8 * (i % 8))
+		}
+		P.x[7] &^= 3 << 62
+		fpSetOne(&P.z)
+
+		// Clear the cofactor 4; a zero result says nothing, resample.
+		xDbl(&P, &P, &a24)
+		xDbl(&P, &P, &a24)
+		if fpIsZero(&P.z) == 1 {
+			continue
+		}
+
+		for i, l := range primes {
+			// Q = [(p+1)/(4*l)]P must be killed by l; if Q is not the
+			// identity, l divides the order of P.
+			Q := P
+			for j, m := range primes {
+				if j != i {
+					xMul(&Q, &Q, &a24, m)
+				}
+			}
+			if fpIsZero(&Q.z) == 0 {
+				xMul(&Q, &Q, &a24, l)
+				if fpIsZero(&Q.z) == 0 {
+					return false // order does not divide p+1
+				}
+				order.Mul(order, new(big.Int).SetUint64(l))
+				if order.Cmp(bound) > 0 {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// action evaluates the class-group action of prv on the curve with
+// Montgomery coefficient a. Every prime contributes exactly expMax
+// isogeny steps: the steps beyond |e_i| are dummies, computed in full
+// and then discarded with a constant-time move, so the visible trace is
+// independent of the key. Which primes still need steps is public — it
+// depends only on the sampled torsion — as in the dummy-free variant.
+func action(a *fp, prv *PrivateKey, rng io.Reader) fp {
+	// Secret per-prime step budgets for each direction; their sums
+	// expMax - done[i] are public.
+	var pos, neg [numPrimes]uint8
+	for i, e := range prv.e {
+		if e > 0 {
+			pos[i] = uint8(e)
+		} else {
+			neg[i] = uint8(-e)
+		}
+	}
+	var done [numPrimes]uint8
+
+	A := *a
+	for {
+		remaining := false
+		for i := range done {
+			if done[i] < expMax {
+				remaining = true
+			}
+		}
+		if !remaining {
+			break
+		}
+
+		a24 := a24FromA(&A)
+		// One point per side, sampled before any secret is touched.
+		var T [2]dfPoint
+		samplePair(&T, &A, rng)
+		for k := range T {
+			xDbl(&T[k], &T[k], &a24)
+			xDbl(&T[k], &T[k], &a24)
+			for i, l := range primes {
+				if done[i] >= expMax {
+					xMul(&T[k], &T[k], &a24, l)
+				}
+			}
+		}
+
+		for i := len(primes) - 1; i >= 0; i-- {
+			if done[i] >= expMax {
+				continue
+			}
+			// Peel the remaining primes below i, leaving (at most)
+			// l_i-torsion.
+			var P [2]dfPoint
+			for k := range P {
+				P[k] = T[k]
+				for j := 0; j < i; j++ {
+					if done[j] < expMax {
+						xMul(&P[k], &P[k], &a24, primes[j])
+					}
+				}
+			}
+			// Proceed only when both sides carry the torsion, so the
+			// public success event says nothing about the direction.
+			if fpIsZero(&P[0].z) == 0 && fpIsZero(&P[1].z) == 0 {
+				// b = 1 selects the twist side: a negative step. A
+				// step with both budgets exhausted is a dummy.
+				b := (uint(1) - ctNonZero8(pos[i])) & ctNonZero8(neg[i])
+				real := ctNonZero8(pos[i]) | ctNonZero8(neg[i])
+				K := P[0]
+				fpCmov(&K.x, &P[1].x, b)
+				fpCmov(&K.z, &P[1].z, b)
+
+				A2 := A
+				T2 := T
+				isogeny(&A2, &T2, &K, primes[i])
+				fpCmov(&A, &A2, real)
+				for k := range T {
+					fpCmov(&T[k].x, &T2[k].x, real)
+					fpCmov(&T[k].z, &T2[k].z, real)
+				}
+				pos[i] -= uint8((1 - b) & real)
+				neg[i] -= uint8(b & real)
+				a24 = a24FromA(&A)
+				done[i]++
+			}
+			// Clear the l_i-torsion before moving to lower primes:
+			// the isogeny only killed it on the kernel's side, and a
+			// skipped or dummy step leaves it on both.
+			for k := range T {
+				xMul(&T[k], &T[k], &a24, primes[i])
+			}
+		}
+	}
+
+	return A
+}
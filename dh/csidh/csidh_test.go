@@ -0,0 +1,126 @@
+package csidh
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestFpArithmetic(t *testing.T) {
+	var b [64]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		t.Fatal(err)
+	}
+	var x fp
+	for i := 0; i < 64; i++ {
+		x[i/8] |= uint64(b[i]) << (8 * (i % 8))
+	}
+	x[7] &^= 3 << 62
+
+	var inv, prod, one fp
+	fpSetOne(&one)
+	fpInv(&inv, &x)
+	fpMul(&prod, &x, &inv)
+	fpSub(&prod, &prod, &one)
+	if fpIsZero(&prod) != 1 {
+		t.Fatal("x * x^-1 != 1")
+	}
+
+	var s, d fp
+	fpAdd(&s, &x, &x)
+	fpSub(&d, &s, &x)
+	fpSub(&d, &d, &x)
+	if fpIsZero(&d) != 1 {
+		t.Fatal("(x + x) - x - x != 0")
+	}
+}
+
+func TestKeyExchange(t *testing.T) {
+	var alicePrv, bobPrv PrivateKey
+	if err := GeneratePrivateKey(&alicePrv, rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	if err := GeneratePrivateKey(&bobPrv, rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+
+	var alicePub, bobPub PublicKey
+	GeneratePublicKey(&alicePub, &alicePrv, rand.Reader)
+	GeneratePublicKey(&bobPub, &bobPrv, rand.Reader)
+
+	var k1, k2 [SharedSecretSize]byte
+	if !DeriveSecret(&k1, &bobPub, &alicePrv, rand.Reader) {
+		t.Fatal("bob's public key failed validation")
+	}
+	if !DeriveSecret(&k2, &alicePub, &bobPrv, rand.Reader) {
+		t.Fatal("alice's public key failed validation")
+	}
+	if k1 != k2 {
+		t.Fatal("the group action does not commute")
+	}
+}
+
+// The dummy-free evaluation must agree with the regular one on keys
+// with the required exponent parity.
+func TestDummyFreeAgreesWithRegular(t *testing.T) {
+	var prv PrivateKey
+	if err := GeneratePrivateKeyDummyFree(&prv, rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+
+	var pub, pubDF PublicKey
+	GeneratePublicKey(&pub, &prv, rand.Reader)
+	GeneratePublicKeyDummyFree(&pubDF, &prv, rand.Reader)
+	if pub.a != pubDF.a {
+		t.Fatal("dummy-free public key differs from the regular one")
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	var prv, prv2 PrivateKey
+	if err := GeneratePrivateKey(&prv, rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	var buf [PrivateKeySize]byte
+	if !prv.Export(buf[:]) || !prv2.Import(buf[:]) || prv2.e != prv.e {
+		t.Fatal("private key roundtrip failed")
+	}
+
+	var pub, pub2 PublicKey
+	GeneratePublicKey(&pub, &prv, rand.Reader)
+	var pbuf [PublicKeySize]byte
+	if !pub.Export(pbuf[:]) || !pub2.Import(pbuf[:]) || pub2.a != pub.a {
+		t.Fatal("public key roundtrip failed")
+	}
+
+	// Non-canonical field values must be rejected.
+	for i := range pbuf {
+		pbuf[i] = 0xff
+	}
+	if pub2.Import(pbuf[:]) {
+		t.Fatal("imported a coefficient above p")
+	}
+}
+
+func TestValidateRejectsOrdinary(t *testing.T) {
+	// The base curve is supersingular.
+	var base PublicKey
+	if !Validate(&base, rand.Reader) {
+		t.Fatal("base curve rejected")
+	}
+
+	// A = 1 is an ordinary curve (with overwhelming probability no
+	// small coefficient is supersingular besides 0).
+	var bad PublicKey
+	fpSetOne(&bad.a)
+	if Validate(&bad, rand.Reader) {
+		t.Fatal("ordinary curve accepted")
+	}
+
+	// The singular curves A = ±2 must be rejected.
+	var two PublicKey
+	fpSetOne(&two.a)
+	fpAdd(&two.a, &two.a, &two.a)
+	if Validate(&two, rand.Reader) {
+		t.Fatal("singular curve accepted")
+	}
+}
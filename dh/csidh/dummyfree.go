@@ -0,0 +1,394 @@
+package csidh
+
+// This file implements a dummy-free, constant-time evaluation of the
+// CSIDH group action, following Cervantes-Vázquez, Chenu,
+// Chi-Domínguez, De Feo, Rodríguez-Henríquez, and Smith, "Stronger and
+// faster side-channel protections for CSIDH" (LATINCRYPT 2019). The
+// regular evaluation hides the secret exponent vector behind dummy
+// isogenies, which a fault attacker can tell apart from real ones: a
+// fault in a dummy computation leaves the output intact. Here no dummy
+// work exists to target. An exponent e_i with |e_i| <= expMax is split
+// as (expMax+e_i)/2 isogeny steps in the positive direction and
+// (expMax-e_i)/2 in the negative, so every prime always contributes
+// exactly expMax real isogenies, whatever the key. The split requires
+// every exponent to have the parity of expMax;
+// GeneratePrivateKeyDummyFree samples such keys.
+//
+// Points on the curve drive positive steps and points on the quadratic
+// twist negative ones. Both kinds are sampled per round before any
+// secret enters, the kernel is picked by a constant-time swap, and the
+// x-only arithmetic is oblivious to which side a point lives on, so the
+// visible trace is independent of the direction split.
+
+import (
+	"io"
+	"math/bits"
+
+	"github.com/cloudflare/circl/rand"
+)
+
+// GeneratePrivateKeyDummyFree samples a private key suitable for the
+// dummy-free evaluation: every exponent is uniform on the values in
+// [-expMax, expMax] with the parity of expMax.
+func GeneratePrivateKeyDummyFree(key *PrivateKey, rng io.Reader) error {
+	rng = rand.Source(rng)
+	var b [1]byte
+	for i := range key.e {
+		for {
+			if _, err := io.ReadFull(rng, b[:]); err != nil {
+				return err
+			}
+			// Rejection-sample a uniform t in [0, expMax]; the
+			// exponent is 2t - expMax.
+			lim := byte(256 - 256%(expMax+1))
+			if b[0] < lim {
+				key.e[i] = int8(2*int(b[0]%(expMax+1)) - expMax)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// GeneratePublicKeyDummyFree computes the public key for prv by the
+// dummy-free action on the base curve. The result equals
+// GeneratePublicKey's for the same key; only the evaluation differs.
+// The key must come from GeneratePrivateKeyDummyFree.
+func GeneratePublicKeyDummyFree(key *PublicKey, prv *PrivateKey, rng io.Reader) {
+	var base fp
+	key.a = actionDummyFree(&base, prv, rand.Source(rng))
+}
+
+// DeriveSecretDummyFree computes the shared secret from the peer's
+// public key by the dummy-free action, returning false when the public
+// key fails validation. The key must come from
+// GeneratePrivateKeyDummyFree.
+func DeriveSecretDummyFree(out *[64]byte, pub *PublicKey, prv *PrivateKey, rng io.Reader) bool {
+	rng = rand.Source(rng)
+	if !Validate(pub, rng) {
+		return false
+	}
+	a := actionDummyFree(&pub.a, prv, rng)
+	for i := 0; i < 64; i++ {
+		out[i] = byte(a[i/8] >> (8 * (i % 8)))
+	}
+
+	return true
+}
+
+// dfPoint is a projective x-line point (X:Z), on the curve or its
+// twist; the arithmetic below never needs to know which.
+type dfPoint struct {
+	x, z fp
+}
+
+// actionDummyFree evaluates the class-group action of prv on the curve
+// with Montgomery coefficient a. Which primes still need work is
+// public — it depends only on the sampled torsion — while the
+// direction of every step is secret and handled without branches.
+func actionDummyFree(a *fp, prv *PrivateKey, rng io.Reader) fp {
+	// Split each exponent into step counts per direction. The counts
+	// are secret; their sums m - done[i] are public.
+	var pos, neg [numPrimes]uint8
+	for i, e := range prv.e {
+		pos[i] = uint8((expMax + int(e)) / 2)
+		neg[i] = uint8((expMax - int(e)) / 2)
+	}
+	var done [numPrimes]uint8
+
+	A := *a
+	for {
+		remaining := false
+		for i := range done {
+			if done[i] < expMax {
+				remaining = true
+			}
+		}
+		if !remaining {
+			break
+		}
+
+		a24 := a24FromA(&A)
+		// One point per side, sampled before any secret is touched.
+		var T [2]dfPoint
+		samplePair(&T, &A, rng)
+		for k := range T {
+			xDbl(&T[k], &T[k], &a24)
+			xDbl(&T[k], &T[k], &a24)
+			for i, l := range primes {
+				if done[i] >= expMax {
+					xMul(&T[k], &T[k], &a24, l)
+				}
+			}
+		}
+
+		for i := len(primes) - 1; i >= 0; i-- {
+			if done[i] >= expMax {
+				continue
+			}
+			// Peel the remaining primes below i, leaving (at most)
+			// l_i-torsion.
+			var P [2]dfPoint
+			for k := range P {
+				P[k] = T[k]
+				for j := 0; j < i; j++ {
+					if done[j] < expMax {
+						xMul(&P[k], &P[k], &a24, primes[j])
+					}
+				}
+			}
+			// Proceed only when both sides carry the torsion, so the
+			// public success event says nothing about the direction.
+			if fpIsZero(&P[0].z) == 0 && fpIsZero(&P[1].z) == 0 {
+				// b = 1 selects the twist side: a negative step.
+				b := uint(1) - ctNonZero8(pos[i])
+				pos[i] -= uint8(1 - b)
+				neg[i] -= uint8(b)
+				K := P[0]
+				fpCmov(&K.x, &P[1].x, b)
+				fpCmov(&K.z, &P[1].z, b)
+
+				isogeny(&A, &T, &K, primes[i])
+				a24 = a24FromA(&A)
+				done[i]++
+			}
+			// Clear the l_i-torsion before moving to lower primes:
+			// the isogeny only killed it on the kernel's side, and a
+			// skipped prime leaves it on both.
+			for k := range T {
+				xMul(&T[k], &T[k], &a24, primes[i])
+			}
+		}
+	}
+
+	return A
+}
+
+// samplePair fills T[0] with a point on the curve and T[1] with a point
+// on the twist, from public randomness.
+func samplePair(T *[2]dfPoint, A *fp, rng io.Reader) {
+	have := [2]bool{}
+	var b [64]byte
+	for !have[0] || !have[1] {
+		if _, err := io.ReadFull(rng, b[:]); err != nil {
+			panic(err)
+		}
+		var x fp
+		for i := 0; i < 64; i++ {
+			x[i/8] |= uint64(b[i]) << (8 * (i % 8))
+		}
+		x[7] &^= 3 << 62 // below 2^510 < p
+		// f = x*(x^2 + A*x + 1); zero means x or its curve point is
+		// degenerate, try again.
+		var f, t, one fp
+		fpSetOne(&one)
+		fpSqr(&f, &x)
+		fpMul(&t, A, &x)
+		fpAdd(&f, &f, &t)
+		fpAdd(&f, &f, &one)
+		fpMul(&f, &f, &x)
+		if fpIsZero(&f) == 1 {
+			continue
+		}
+		side := 1 - isSquare(&f)
+		if !have[side] {
+			T[side].x = x
+			fpSetOne(&T[side].z)
+			have[side] = true
+		}
+	}
+}
+
+// a24FromA returns (A+2)/4, the constant the doubling formula wants.
+func a24FromA(A *fp) (a24 fp) {
+	var one, four fp
+	fpSetOne(&one)
+	fpAdd(&a24, A, &one)
+	fpAdd(&a24, &a24, &one)
+	fpAdd(&four, &one, &one)
+	fpAdd(&four, &four, &four)
+	fpInv(&four, &four)
+	fpMul(&a24, &a24, &four)
+
+	return a24
+}
+
+// xDbl sets P = [2]Q.
+func xDbl(P, Q *dfPoint, a24 *fp) {
+	var t0, t1, t2 fp
+	fpSub(&t0, &Q.x, &Q.z)
+	fpSqr(&t0, &t0)
+	fpAdd(&t1, &Q.x, &Q.z)
+	fpSqr(&t1, &t1)
+	fpSub(&t2, &t1, &t0) // 4*X*Z
+	fpMul(&P.x, &t0, &t1)
+	fpMul(&t1, a24, &t2)
+	fpAdd(&t1, &t1, &t0)
+	fpMul(&P.z, &t1, &t2)
+}
+
+// xAdd sets P = Q + R given their difference D (differential addition).
+func xAdd(P, Q, R, D *dfPoint) {
+	var da, cb, t0, t1 fp
+	fpSub(&t0, &Q.x, &Q.z)
+	fpAdd(&t1, &R.x, &R.z)
+	fpMul(&da, &t0, &t1)
+	fpAdd(&t0, &Q.x, &Q.z)
+	fpSub(&t1, &R.x, &R.z)
+	fpMul(&cb, &t0, &t1)
+	fpAdd(&t0, &da, &cb)
+	fpSqr(&t0, &t0)
+	fpSub(&t1, &da, &cb)
+	fpSqr(&t1, &t1)
+	fpMul(&P.x, &D.z, &t0)
+	fpMul(&P.z, &D.x, &t1)
+}
+
+// xMul sets P = [l]Q by a ladder over the bits of the public prime l.
+func xMul(P, Q *dfPoint, a24 *fp, l uint64) {
+	base := *Q
+	var r0, r1 dfPoint
+	fpSetOne(&r0.x) // (1:0), the identity
+	r1 = base
+	for i := bits.Len64(l) - 1; i >= 0; i-- {
+		if l>>uint(i)&1 == 0 {
+			xAdd(&r1, &r0, &r1, &base)
+			xDbl(&r0, &r0, a24)
+		} else {
+			xAdd(&r0, &r0, &r1, &base)
+			xDbl(&r1, &r1, a24)
+		}
+	}
+	*P = r0
+}
+
+// isogeny applies the degree-l isogeny with kernel K to the curve
+// coefficient and pushes the points of T through it, using the x-only
+// Vélu formulas with the coefficient update of Meyer and Reith.
+func isogeny(A *fp, T *[2]dfPoint, K *dfPoint, l uint64) {
+	a24 := a24FromA(A)
+	d := (l - 1) / 2
+
+	var one, bp, bm fp
+	fpSetOne(&one)
+	bp, bm = one, one
+	var accX, accZ [2]fp
+	for k := range accX {
+		accX[k], accZ[k] = one, one
+	}
+
+	// Walk the multiples [j]K, j = 1..d, with differential additions.
+	prev := dfPoint{}
+	fpSetOne(&prev.x) // identity, the 0th multiple
+	cur := *K
+	for j := uint64(1); j <= d; j++ {
+		var t0, t1 fp
+		fpAdd(&t0, &cur.x, &cur.z)
+		fpMul(&bp, &bp, &t0)
+		fpSub(&t0, &cur.x, &cur.z)
+		fpMul(&bm, &bm, &t0)
+		for k := range T {
+			fpMul(&t0, &T[k].x, &cur.x)
+			fpMul(&t1, &T[k].z, &cur.z)
+			fpSub(&t0, &t0, &t1)
+			fpMul(&accX[k], &accX[k], &t0)
+			fpMul(&t0, &T[k].x, &cur.z)
+			fpMul(&t1, &T[k].z, &cur.x)
+			fpSub(&t0, &t0, &t1)
+			fpMul(&accZ[k], &accZ[k], &t0)
+		}
+		if j < d {
+			next := dfPoint{}
+			if j == 1 {
+				xDbl(&next, K, &a24)
+			} else {
+				xAdd(&next, &cur, K, &prev)
+			}
+			prev, cur = cur, next
+		}
+	}
+
+	// ap = (A+2)^l * bp^8, am = (A-2)^l * bm^8;
+	// A' = 2*(ap+am)/(ap-am).
+	var ap, am, two fp
+	fpAdd(&two, &one, &one)
+	fpAdd(&ap, A, &two)
+	fpSub(&am, A, &two)
+	fpPowPrime(&ap, &ap, l)
+	fpPowPrime(&am, &am, l)
+	var t fp
+	fpPow8(&t, &bp)
+	fpMul(&ap, &ap, &t)
+	fpPow8(&t, &bm)
+	fpMul(&am, &am, &t)
+	var num, den fp
+	fpAdd(&num, &ap, &am)
+	fpAdd(&num, &num, &num)
+	fpSub(&den, &ap, &am)
+	fpInv(&den, &den)
+	fpMul(A, &num, &den)
+
+	for k := range T {
+		var x, z fp
+		fpSqr(&x, &accX[k])
+		fpMul(&x, &x, &T[k].x)
+		fpSqr(&z, &accZ[k])
+		fpMul(&z, &z, &T[k].z)
+		T[k].x, T[k].z = x, z
+	}
+}
+
+// fpPowPrime raises x to the public small prime l.
+func fpPowPrime(z, x *fp, l uint64) {
+	acc := *x
+	for i := bits.Len64(l) - 2; i >= 0; i-- {
+		fpSqr(&acc, &acc)
+		if l>>uint(i)&1 == 1 {
+			fpMul(&acc, &acc, x)
+		}
+	}
+	*z = acc
+}
+
+// fpPow8 sets z = x^8.
+func fpPow8(z, x *fp) {
+	fpSqr(z, x)
+	fpSqr(z, z)
+	fpSqr(z, z)
+}
+
+// isSquare computes the Legendre symbol of the nonzero element f,
+// returning 1 for squares and 0 otherwise, by raising to (p-1)/2.
+func isSquare(f *fp) int {
+	var one, acc fp
+	fpSetOne(&one)
+	acc = one
+	started := false
+	for i := 7; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			if started {
+				fpSqr(&acc, &acc)
+			}
+			if pHalfWords[i]>>uint(j)&1 == 1 {
+				if started {
+					fpMul(&acc, &acc, f)
+				} else {
+					acc = *f
+					started = true
+				}
+			}
+		}
+	}
+	fpSub(&acc, &acc, &one)
+	if fpIsZero(&acc) == 1 {
+		return 1
+	}
+
+	return 0
+}
+
+// ctNonZero8 returns 1 when v is nonzero, in constant time.
+func ctNonZero8(v uint8) uint {
+	return uint((uint16(v) + 0xff) >> 8)
+}
@@ -0,0 +1,204 @@
+// Package ecdh exposes X25519 and X448 through the API of the standard
+// library's crypto/ecdh package.
+//
+// crypto/ecdh's Curve interface has unexported methods and cannot be
+// satisfied outside the standard library, so this package mirrors its
+// types and method sets instead: code written against crypto/ecdh moves
+// over with an import change, and gains X448, which the standard library
+// does not provide. Key byte formats are those of RFC 7748, identical to
+// crypto/ecdh's for X25519, so keys interoperate across the two
+// packages.
+//
+// As in crypto/ecdh, ECDH returns an error when the shared secret would
+// be all zeros, which happens exactly when the peer's public key is a
+// low-order point; the secret therefore always depends on both keys.
+package ecdh
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/dh/x25519"
+	"github.com/cloudflare/circl/dh/x448"
+	"github.com/cloudflare/circl/rand"
+)
+
+// ErrLowOrder is returned by ECDH when the peer's public key is a
+// low-order point, making the shared secret independent of our key.
+var ErrLowOrder = errors.New("ecdh: low-order remote public key")
+
+// Curve is a Diffie-Hellman function, mirroring crypto/ecdh.Curve.
+type Curve interface {
+	// GenerateKey draws a fresh private key from rnd, or from the
+	// library-wide source when rnd is nil.
+	GenerateKey(rnd io.Reader) (*PrivateKey, error)
+	// NewPrivateKey checks that key is the right length and returns it
+	// as a PrivateKey. Clamping happens during use, per RFC 7748, so
+	// every string of the right length is accepted.
+	NewPrivateKey(key []byte) (*PrivateKey, error)
+	// NewPublicKey checks that key is the right length and returns it
+	// as a PublicKey.
+	NewPublicKey(key []byte) (*PublicKey, error)
+	// String returns the name of the curve.
+	String() string
+}
+
+// X25519 returns a Curve for X25519 (RFC 7748), backed by dh/x25519.
+func X25519() Curve { return &curveX25519 }
+
+// X448 returns a Curve for X448 (RFC 7748), backed by dh/x448.
+func X448() Curve { return &curveX448 }
+
+// curve carries the per-function sizes and entry points.
+type curve struct {
+	name   string
+	size   int
+	keygen func(secret []byte) (public []byte)
+	shared func(secret, public []byte) (shared []byte)
+}
+
+var curveX25519 = curve{
+	name: "X25519",
+	size: x25519.Size,
+	keygen: func(secret []byte) []byte {
+		var pub, sec x25519.Key
+		copy(sec[:], secret)
+		x25519.KeyGen(&pub, &sec)
+
+		return pub[:]
+	},
+	shared: func(secret, public []byte) []byte {
+		var sh, sec, pub x25519.Key
+		copy(sec[:], secret)
+		copy(pub[:], public)
+		x25519.Shared(&sh, &sec, &pub)
+
+		return sh[:]
+	},
+}
+
+var curveX448 = curve{
+	name: "X448",
+	size: x448.Size,
+	keygen: func(secret []byte) []byte {
+		var pub, sec x448.Key
+		copy(sec[:], secret)
+		x448.KeyGen(&pub, &sec)
+
+		return pub[:]
+	},
+	shared: func(secret, public []byte) []byte {
+		var sh, sec, pub x448.Key
+		copy(sec[:], secret)
+		copy(pub[:], public)
+		x448.Shared(&sh, &sec, &pub)
+
+		return sh[:]
+	},
+}
+
+func (c *curve) String() string { return c.name }
+
+func (c *curve) GenerateKey(rnd io.Reader) (*PrivateKey, error) {
+	secret := make([]byte, c.size)
+	if _, err := io.ReadFull(rand.Source(rnd), secret); err != nil {
+		return nil, err
+	}
+
+	return c.newPrivateKey(secret), nil
+}
+
+func (c *curve) NewPrivateKey(key []byte) (*PrivateKey, error) {
+	if len(key) != c.size {
+		return nil, errors.New("ecdh: invalid private key size for " + c.name)
+	}
+
+	return c.newPrivateKey(append([]byte{}, key...)), nil
+}
+
+func (c *curve) newPrivateKey(secret []byte) *PrivateKey {
+	return &PrivateKey{
+		c:      c,
+		secret: secret,
+		public: &PublicKey{c: c, point: c.keygen(secret)},
+	}
+}
+
+func (c *curve) NewPublicKey(key []byte) (*PublicKey, error) {
+	if len(key) != c.size {
+		return nil, errors.New("ecdh: invalid public key size for " + c.name)
+	}
+
+	return &PublicKey{c: c, point: append([]byte{}, key...)}, nil
+}
+
+// PublicKey is an ECDH public key, the peer's share of the exchange.
+type PublicKey struct {
+	c     *curve
+	point []byte
+}
+
+// Curve returns the curve the key belongs to.
+func (k *PublicKey) Curve() Curve { return k.c }
+
+// Bytes returns a copy of the key's RFC 7748 encoding.
+func (k *PublicKey) Bytes() []byte { return append([]byte{}, k.point...) }
+
+// Equal reports whether k and x are the same key. This check runs in
+// constant time for keys of the same curve.
+func (k *PublicKey) Equal(x crypto.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+
+	return ok && k.c == xx.c &&
+		subtle.ConstantTimeCompare(k.point, xx.point) == 1
+}
+
+// PrivateKey is an ECDH private key, our share of the exchange.
+type PrivateKey struct {
+	c      *curve
+	secret []byte
+	public *PublicKey
+}
+
+// Curve returns the curve the key belongs to.
+func (k *PrivateKey) Curve() Curve { return k.c }
+
+// Bytes returns a copy of the key's encoding.
+func (k *PrivateKey) Bytes() []byte { return append([]byte{}, k.secret...) }
+
+// Equal reports whether k and x are the same key, in constant time for
+// keys of the same curve.
+func (k *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	xx, ok := x.(*PrivateKey)
+
+	return ok && k.c == xx.c &&
+		subtle.ConstantTimeCompare(k.secret, xx.secret) == 1
+}
+
+// Public returns the corresponding public key, satisfying the implicit
+// interface of crypto.Signer and friends.
+func (k *PrivateKey) Public() crypto.PublicKey { return k.public }
+
+// PublicKey returns the corresponding public key.
+func (k *PrivateKey) PublicKey() *PublicKey { return k.public }
+
+// ECDH computes the shared secret with the peer's public key, returning
+// ErrLowOrder when the secret would be all zeros (see the package
+// comment) and an error when the keys belong to different curves.
+func (k *PrivateKey) ECDH(remote *PublicKey) ([]byte, error) {
+	if k.c != remote.c {
+		return nil, errors.New("ecdh: keys of different curves")
+	}
+	shared := k.c.shared(k.secret, remote.point)
+	var acc byte
+	for _, v := range shared {
+		acc |= v
+	}
+	if subtle.ConstantTimeByteEq(acc, 0) == 1 {
+		return nil, ErrLowOrder
+	}
+
+	return shared, nil
+}
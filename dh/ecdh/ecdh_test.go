@@ -0,0 +1,78 @@
+package ecdh_test
+
+import (
+	"bytes"
+	stdecdh "crypto/ecdh"
+	"crypto/rand"
+	"testing"
+
+	"github.com/cloudflare/circl/dh/ecdh"
+)
+
+func TestExchange(t *testing.T) {
+	for _, c := range []ecdh.Curve{ecdh.X25519(), ecdh.X448()} {
+		t.Run(c.String(), func(t *testing.T) {
+			alice, err := c.GenerateKey(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bob, err := c.GenerateKey(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			k1, err := alice.ECDH(bob.PublicKey())
+			if err != nil {
+				t.Fatal(err)
+			}
+			k2, err := bob.ECDH(alice.PublicKey())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(k1, k2) {
+				t.Fatal("shared secrets differ")
+			}
+
+			// A low-order peer key must be rejected.
+			zero, err := c.NewPublicKey(make([]byte, len(bob.PublicKey().Bytes())))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = alice.ECDH(zero); err != ecdh.ErrLowOrder {
+				t.Fatal("low-order public key not rejected")
+			}
+		})
+	}
+}
+
+// X25519 keys must interoperate with crypto/ecdh.
+func TestInteropWithStdlib(t *testing.T) {
+	ours, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	theirs, err := stdecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	theirPub, err := ecdh.X25519().NewPublicKey(theirs.PublicKey().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	k1, err := ours.ECDH(theirPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ourPub, err := stdecdh.X25519().NewPublicKey(ours.PublicKey().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := theirs.ECDH(ourPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(k1, k2) {
+		t.Fatal("shared secret disagrees with crypto/ecdh")
+	}
+}
@@ -0,0 +1,35 @@
+package x25519
+
+// Shared silently outputs all zeros when the peer's public key is a
+// low-order point, and a protocol that fails to check inherits a shared
+// "secret" the peer fixed unilaterally. This file adds the checked
+// variant that several handshake specifications mandate.
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+// ErrLowOrder is returned when the peer's public key is a low-order
+// point, making the shared secret independent of our key.
+var ErrLowOrder = errors.New("x25519: low-order public key")
+
+// SharedContributory computes the shared secret like Shared, but
+// returns ErrLowOrder, with shared left all zeros, when public is a
+// low-order point. Clamped secret keys are multiples of the cofactor,
+// so the result is all zeros exactly for low-order inputs; checking the
+// output covers every such point, including the non-canonical
+// encodings above the field prime. A nil error guarantees the secret
+// depends on both exchanged keys.
+func SharedContributory(shared, secret, public *Key) error {
+	Shared(shared, secret, public)
+	var acc byte
+	for _, v := range shared {
+		acc |= v
+	}
+	if subtle.ConstantTimeByteEq(acc, 0) == 1 {
+		return ErrLowOrder
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package x25519
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func keyFromHex(t *testing.T, s string) (k Key) {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != Size {
+		t.Fatal("bad test vector")
+	}
+	copy(k[:], b)
+
+	return
+}
+
+// Vectors from RFC 7748, section 5.2.
+func TestRFC7748Vectors(t *testing.T) {
+	k := keyFromHex(t, "a546e36bf0527c9d3b16154b82465edd62144c0ac1fc5a18506a2244ba449ac4")
+	u := keyFromHex(t, "e6db6867583030db3594c1a424b15f7c726624ec26b3353b10a903a6d0ab1c4c")
+	want := keyFromHex(t, "c3da55379de9c6908e94ea4df28d084f32eccf03491c71f754b4075577a28552")
+	var got Key
+	Shared(&got, &k, &u)
+	if got != want {
+		t.Fatalf("got %x, want %x", got, want)
+	}
+
+	k = keyFromHex(t, "4b66e9d4d1b4673c5ad22691957d6af5c11b6421e0ea01d42ca4169e7918ba0d")
+	u = keyFromHex(t, "e5210f12786811d3f4b7959d0538ae2c31dbe7106fc03c3efc4cd549c715a493")
+	want = keyFromHex(t, "95cbde9476e8907d7aade45cb4b873f88b595a68799fa152e6f8f7647aac7957")
+	Shared(&got, &k, &u)
+	if got != want {
+		t.Fatalf("got %x, want %x", got, want)
+	}
+}
+
+// Iterated vectors from RFC 7748, section 5.2.
+func TestRFC7748Iterated(t *testing.T) {
+	k := Key{9}
+	u := k
+	var r Key
+	for i := 0; i < 1000; i++ {
+		Shared(&r, &k, &u)
+		u = k
+		k = r
+		if i == 0 {
+			want := keyFromHex(t, "422c8e7a6227d7bca1350b3e2bb7279f7897b87bb6854b783c60e80311ae3079")
+			if k != want {
+				t.Fatalf("after 1 iteration: got %x, want %x", k, want)
+			}
+		}
+	}
+	want := keyFromHex(t, "684cf59ba83309552800ef566f2f4d3c1c3887c49360e3875f2eb94d99532c51")
+	if k != want {
+		t.Fatalf("after 1000 iterations: got %x, want %x", k, want)
+	}
+}
+
+// Diffie-Hellman test from RFC 7748, section 6.1.
+func TestDiffieHellman(t *testing.T) {
+	aliceSecret := keyFromHex(t, "77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a")
+	bobSecret := keyFromHex(t, "5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb")
+
+	var alicePublic, bobPublic Key
+	KeyGen(&alicePublic, &aliceSecret)
+	KeyGen(&bobPublic, &bobSecret)
+	if alicePublic != keyFromHex(t, "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a") {
+		t.Fatal("alice's public key is wrong")
+	}
+	if bobPublic != keyFromHex(t, "de9edb7d7b7dc1b4d35b61c2ece435373f8343c85b78674dadfc7e146f882b4f") {
+		t.Fatal("bob's public key is wrong")
+	}
+
+	var k1, k2 Key
+	Shared(&k1, &aliceSecret, &bobPublic)
+	Shared(&k2, &bobSecret, &alicePublic)
+	want := keyFromHex(t, "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742")
+	if k1 != want || k2 != want {
+		t.Fatal("shared secrets disagree with the RFC vector")
+	}
+}
+
+func TestSharedContributory(t *testing.T) {
+	secret := keyFromHex(t, "77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a")
+
+	var zero, shared Key // the point of order one
+	if err := SharedContributory(&shared, &secret, &zero); err != ErrLowOrder {
+		t.Fatal("low-order point not rejected")
+	}
+	if shared != (Key{}) {
+		t.Fatal("shared secret not cleared on rejection")
+	}
+
+	var public Key
+	KeyGen(&public, &secret)
+	if err := SharedContributory(&shared, &secret, &public); err != nil {
+		t.Fatal(err)
+	}
+	if shared == (Key{}) {
+		t.Fatal("valid exchange produced a zero secret")
+	}
+}
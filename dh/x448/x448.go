@@ -0,0 +1,96 @@
+// Package x448 provides Diffie-Hellman on Curve448 (X448) as specified
+// in RFC 7748.
+//
+// A secret key is 56 uniformly random bytes; the clamping prescribed by
+// the RFC happens internally during use, so every 56-byte string is a
+// valid key. Shared computes the raw Diffie-Hellman function and outputs
+// all zeros when the peer's public key is a low-order point; protocols
+// that require contributory behaviour must reject that output.
+//
+// References:
+//
+//	RFC 7748: https://rfc-editor.org/rfc/rfc7748.txt
+package x448
+
+import fp "github.com/cloudflare/circl/math/fp448"
+
+// Size is the length in bytes of keys and shared secrets.
+const Size = 56
+
+// Key represents an X448 key, public or secret, or a shared secret.
+type Key [Size]byte
+
+// clamp returns the secret scalar in the canonical form of RFC 7748:
+// a multiple of the cofactor with the top bit set.
+func (k *Key) clamp() *Key {
+	c := *k
+	c[0] &= 252
+	c[Size-1] |= 128
+
+	return &c
+}
+
+// KeyGen derives the public key of a secret key.
+func KeyGen(public, secret *Key) {
+	base := Key{5} // the u-coordinate of the generator
+	ladder(public, secret.clamp(), &base)
+}
+
+// Shared computes the Diffie-Hellman shared secret between our secret
+// key and the peer's public key. The output is all zeros exactly when
+// public is a low-order point.
+func Shared(shared, secret, public *Key) {
+	ladder(shared, secret.clamp(), public)
+}
+
+// ladder computes k*u with the constant-time Montgomery ladder of
+// RFC 7748, section 5. The swap of the two accumulator points is
+// folded into the conditional swap at the top of each step.
+func ladder(out, k, u *Key) {
+	// a24 = (A+2)/4 for A = 156326.
+	a24 := fp.Elt{0xa9, 0x98}
+
+	var x1, x2, z2, x3, z3 fp.Elt
+	copy(x1[:], u[:])
+	fp.SetOne(&x2)
+	x3 = x1
+	fp.SetOne(&z3)
+
+	var a, b, aa, bb, e, da, cb, t fp.Elt
+	swap := uint(0)
+	for i := 8*Size - 1; i >= 0; i-- {
+		kt := uint(k[i/8]>>(uint(i)&7)) & 1
+		swap ^= kt
+		fp.Cswap(&x2, &x3, swap)
+		fp.Cswap(&z2, &z3, swap)
+		swap = kt
+
+		fp.Add(&a, &x2, &z2)
+		fp.Sqr(&aa, &a)
+		fp.Sub(&b, &x2, &z2)
+		fp.Sqr(&bb, &b)
+		fp.Sub(&e, &aa, &bb)
+		fp.Add(&da, &x3, &z3)
+		fp.Mul(&da, &da, &b)
+		fp.Sub(&cb, &x3, &z3)
+		fp.Mul(&cb, &cb, &a)
+		fp.Add(&t, &da, &cb)
+		fp.Sqr(&x3, &t)
+		fp.Sub(&t, &da, &cb)
+		fp.Sqr(&t, &t)
+		fp.Mul(&z3, &x1, &t)
+		fp.Mul(&x2, &aa, &bb)
+		fp.Mul(&t, &e, &a24)
+		fp.Add(&t, &aa, &t)
+		fp.Mul(&z2, &e, &t)
+	}
+	fp.Cswap(&x2, &x3, swap)
+	fp.Cswap(&z2, &z3, swap)
+
+	// A low-order input ends with z2 = 0; the exponentiation-based
+	// inversion maps zero to zero, so the output is all zeros then.
+	fp.Inv(&z2, &z2)
+	fp.Mul(&x2, &x2, &z2)
+	fp.Modp(&x2)
+	copy(out[:], x2[:])
+}
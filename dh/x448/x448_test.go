@@ -0,0 +1,96 @@
+package x448
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func keyFromHex(t *testing.T, s string) (k Key) {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != Size {
+		t.Fatal("bad test vector")
+	}
+	copy(k[:], b)
+
+	return
+}
+
+// Vectors from RFC 7748, section 5.2.
+func TestRFC7748Vectors(t *testing.T) {
+	k := keyFromHex(t, "3d262fddf9ec8e88495266fea19a34d28882acef045104d0"+
+		"d1aae121700a779c984c24f8cdd78fbff44943eba368f54b29259a4f1c600ad3")
+	u := keyFromHex(t, "06fce640fa3487bfda5f6cf2d5263f8aad88334cbd07437f"+
+		"020f08f9814dc031ddbdc38c19c6da2583fa5429db94ada18aa7a7fb4ef8a086")
+	want := keyFromHex(t, "ce3e4ff95a60dc6697da1db1d85e6afbdf79b50a2412d754"+
+		"6d5f239fe14fbaadeb445fc66a01b0779d98223961111e21766282f73dd96b6f")
+	var got Key
+	Shared(&got, &k, &u)
+	if got != want {
+		t.Fatalf("got %x, want %x", got, want)
+	}
+
+	k = keyFromHex(t, "203d494428b8399352665ddca42f9de8fef600908e0d461c"+
+		"b021f8c538345dd77c3e4806e25f46d3315c44e0a5b4371282dd2c8d5be3095f")
+	u = keyFromHex(t, "0fbcc2f993cd56d3305b0b7d9e55d4c1a8fb5dbb52f8e9a1"+
+		"e9b6201b165d015894e56c4d3570bee52fe205e28a78b91cdfbde71ce8d157db")
+	want = keyFromHex(t, "884a02576239ff7a2f2f63b2db6a9ff37047ac13568e1e30"+
+		"fe63c4a7ad1b3ee3a5700df34321d62077e63633c575c1c954514e99da7c179d")
+	Shared(&got, &k, &u)
+	if got != want {
+		t.Fatalf("got %x, want %x", got, want)
+	}
+}
+
+// Iterated vectors from RFC 7748, section 5.2.
+func TestRFC7748Iterated(t *testing.T) {
+	k := Key{5}
+	u := k
+	var r Key
+	for i := 0; i < 1000; i++ {
+		Shared(&r, &k, &u)
+		u = k
+		k = r
+		if i == 0 {
+			want := keyFromHex(t, "3f482c8a9f19b01e6c46ee9711d9dc14fd4bf67a"+
+				"f30765c2ae2b846a4d23a8cd0db897086239492caf350b51f833868b9bc2b3bca9cf4113")
+			if k != want {
+				t.Fatalf("after 1 iteration: got %x, want %x", k, want)
+			}
+		}
+	}
+	want := keyFromHex(t, "aa3b4749d55b9daf1e5b00288826c467274ce3ebbdd5c17b"+
+		"975e09d4af6c67cf10d087202db88286e2b79fceea3ec353ef54faa26e219f38")
+	if k != want {
+		t.Fatalf("after 1000 iterations: got %x, want %x", k, want)
+	}
+}
+
+// Diffie-Hellman test from RFC 7748, section 6.2.
+func TestDiffieHellman(t *testing.T) {
+	aliceSecret := keyFromHex(t, "9a8f4925d1519f5775cf46b04b5800d4ee9ee8ba"+
+		"e8bc5565d498c28dd9c9baf574a9419744897391006382a6f127ab1d9ac2d8c0a598726b")
+	bobSecret := keyFromHex(t, "1c306a7ac2a0e2e0990b294470cba339e6453772"+
+		"b075811d8fad0d1d6927c120bb5ee8972b0d3e21374c9c921b09d1b0366f10b65173992d")
+
+	var alicePublic, bobPublic Key
+	KeyGen(&alicePublic, &aliceSecret)
+	KeyGen(&bobPublic, &bobSecret)
+	if alicePublic != keyFromHex(t, "9b08f7cc31b7e3e67d22d5aea121074a273bd2b8"+
+		"3de09c63faa73d2c22c5d9bbc836647241d953d40c5b12da88120d53177f80e532c41fa0") {
+		t.Fatal("alice's public key is wrong")
+	}
+	if bobPublic != keyFromHex(t, "3eb7a829b0cd20f5bcfc0b599b6feccf6da4627107bdb0d4"+
+		"f345b43027d8b972fc3e34fb4232a13ca706dcb57aec3dae07bdc1c67bf33609") {
+		t.Fatal("bob's public key is wrong")
+	}
+
+	var k1, k2 Key
+	Shared(&k1, &aliceSecret, &bobPublic)
+	Shared(&k2, &bobSecret, &alicePublic)
+	want := keyFromHex(t, "07fff4181ac6cc95ec1c16a94a0f74d12da232ce40a77552"+
+		"281d282bb60c0b56fd2464c335543936521c24403085d59a449a5037514a879d")
+	if k1 != want || k2 != want {
+		t.Fatal("shared secrets disagree with the RFC vector")
+	}
+}
@@ -0,0 +1,73 @@
+// Package drbg provides a deterministic random bit generator built on
+// SHAKE-256.
+//
+// The generator is seeded once, optionally with a personalization string to
+// separate independent instances derived from the same seed, and then
+// produces an unbounded pseudorandom stream through io.Reader. It is
+// intended as an injectable randomness source wherever the library takes an
+// io.Reader, enabling deterministic key generation and the reproduction of
+// known-answer tests. It must not replace crypto/rand for production keys
+// unless the seed itself holds full-strength entropy.
+package drbg
+
+import (
+	"encoding/binary"
+	"runtime"
+
+	"github.com/cloudflare/circl/internal/sha3"
+)
+
+// SHAKE is a deterministic random bit generator based on SHAKE-256. It is
+// not safe for concurrent use.
+type SHAKE struct {
+	state sha3.State
+}
+
+// NewSHAKE returns a generator seeded with the given seed and
+// personalization string, either of which may be nil. Generators with
+// different personalization strings produce independent streams even when
+// sharing a seed.
+func NewSHAKE(seed, personalization []byte) *SHAKE {
+	g := &SHAKE{}
+	g.init(seed, personalization)
+	return g
+}
+
+func (g *SHAKE) init(seed, personalization []byte) {
+	g.state = sha3.NewShake256()
+	absorbString(&g.state, []byte("circl-drbg-shake256"))
+	absorbString(&g.state, seed)
+	absorbString(&g.state, personalization)
+}
+
+// Read fills p with pseudorandom bytes. It never returns an error, so it can
+// back APIs that take an io.Reader for randomness.
+func (g *SHAKE) Read(p []byte) (int, error) {
+	_, _ = g.state.Read(p)
+	return len(p), nil
+}
+
+// Wipe zeroes the generator state. The generator is unusable until
+// reinitialized.
+func (g *SHAKE) Wipe() {
+	g.state = sha3.State{}
+	runtime.KeepAlive(g)
+}
+
+// Reseed mixes fresh entropy into the generator. The new state depends on
+// both the previous state and the entropy, so the stream remains
+// unpredictable even if one of the two is known.
+func (g *SHAKE) Reseed(entropy []byte) {
+	var chain [64]byte
+	_, _ = g.state.Read(chain[:])
+	g.init(chain[:], entropy)
+}
+
+// absorbString writes data preceded by its length, so that the
+// concatenation of the absorbed inputs is unambiguous.
+func absorbString(s *sha3.State, data []byte) {
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(len(data)))
+	_, _ = s.Write(n[:])
+	_, _ = s.Write(data)
+}
@@ -0,0 +1,153 @@
+// Package duplex exposes an incremental keyed duplex construction on top of
+// the Keccak-f[1600] permutation.
+//
+// A duplex object alternates between absorbing input into the sponge state
+// and squeezing output from it, which makes it a convenient foundation for
+// protocol transcripts, session key derivation, and Strobe-style framings:
+// every operation is separated by a frame byte absorbed into the state, so
+// the sequence of operations, not only their concatenated data, determines
+// the output. Ratchet irreversibly destroys part of the state, giving
+// forward secrecy to long-lived sessions.
+package duplex
+
+import (
+	"encoding/binary"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/wipe"
+)
+
+// Rate is the sponge rate in bytes. The capacity is 512 bits, targeting a
+// 256-bit security level.
+const Rate = 136
+
+const (
+	frameAbsorb  = 0x01
+	frameSqueeze = 0x02
+	frameKey     = 0x03
+	frameRatchet = 0x04
+)
+
+// Duplex is an incremental sponge. It is not safe for concurrent use.
+type Duplex struct {
+	state [25]uint64
+	// pos is the byte position within the current rate block.
+	pos int
+	// squeezing is true while output is being produced.
+	squeezing bool
+}
+
+// New returns an empty duplex, domain-separated by the given label.
+func New(label []byte) *Duplex {
+	d := &Duplex{}
+	d.Absorb([]byte("circl-duplex-v1"))
+	d.Absorb(label)
+	return d
+}
+
+// NewKeyed returns a duplex keyed with k, for MAC-like and session uses.
+func NewKeyed(label, k []byte) *Duplex {
+	d := New(label)
+	d.Key(k)
+	return d
+}
+
+// Absorb mixes data into the state. Consecutive Absorb calls are not
+// equivalent to one call with the concatenated data: each call is framed.
+func (d *Duplex) Absorb(data []byte) {
+	d.startOp(frameAbsorb, uint64(len(data)))
+	d.absorb(data)
+}
+
+// Key mixes key material into the state and ratchets, so the key cannot be
+// recovered from a later state compromise.
+func (d *Duplex) Key(k []byte) {
+	d.startOp(frameKey, uint64(len(k)))
+	d.absorb(k)
+	d.Ratchet()
+}
+
+// Squeeze fills out with output bytes. Squeezing is incremental: two calls
+// of n bytes yield the same stream as one call of 2n bytes.
+func (d *Duplex) Squeeze(out []byte) {
+	if !d.squeezing {
+		d.startOp(frameSqueeze, 0)
+		d.permute()
+		d.squeezing = true
+	}
+	for len(out) > 0 {
+		if d.pos == Rate {
+			d.permute()
+		}
+		n := Rate - d.pos
+		if n > len(out) {
+			n = len(out)
+		}
+		for i := 0; i < n; i++ {
+			out[i] = d.getByte(d.pos + i)
+		}
+		d.pos += n
+		out = out[n:]
+	}
+}
+
+// Wipe zeroes the sponge state. Unlike Ratchet, nothing can be derived
+// from the object afterwards.
+func (d *Duplex) Wipe() {
+	wipe.U64s(d.state[:])
+	d.pos = 0
+	d.squeezing = false
+}
+
+// Ratchet irreversibly transforms the state by zeroing the rate portion
+// after a permutation, so earlier states cannot be computed from the
+// current one.
+func (d *Duplex) Ratchet() {
+	d.startOp(frameRatchet, 0)
+	d.permute()
+	for i := 0; i < Rate/8; i++ {
+		d.state[i] = 0
+	}
+}
+
+// startOp absorbs the frame byte and data length of a new operation,
+// closing a squeeze phase if one is active.
+func (d *Duplex) startOp(frame byte, length uint64) {
+	if d.squeezing {
+		// Forget the squeeze position; the next permutation separates
+		// output from subsequent input.
+		d.squeezing = false
+		d.pos = Rate
+	}
+	var hdr [9]byte
+	hdr[0] = frame
+	binary.LittleEndian.PutUint64(hdr[1:], length)
+	d.absorb(hdr[:])
+}
+
+func (d *Duplex) absorb(data []byte) {
+	for len(data) > 0 {
+		if d.pos == Rate {
+			d.permute()
+		}
+		n := Rate - d.pos
+		if n > len(data) {
+			n = len(data)
+		}
+		for i := 0; i < n; i++ {
+			d.xorByte(d.pos+i, data[i])
+		}
+		d.pos += n
+		data = data[n:]
+	}
+}
+
+// xorByte and getByte access the state, kept as little-endian lanes, one
+// byte at a time.
+func (d *Duplex) xorByte(i int, b byte) { d.state[i/8] ^= uint64(b) << (8 * uint(i%8)) }
+func (d *Duplex) getByte(i int) byte    { return byte(d.state[i/8] >> (8 * uint(i%8))) }
+
+func (d *Duplex) permute() {
+	sha3.KeccakF1600(&d.state, false)
+	d.pos = 0
+}
@@ -0,0 +1,37 @@
+// Package bls12381 implements the BLS12-381 pairing-friendly curve: the
+// groups G1 over the base field, G2 over its quadratic extension, and the
+// pairing into the multiplicative target group Gt.
+//
+// Points serialize in the ZCash format: a compressed point is the
+// big-endian x-coordinate with the top three bits of the first byte used
+// as flags (compression, point at infinity, and the sign of y).
+//
+// References:
+//
+//	Bowe, "BLS12-381: New zk-SNARK elliptic curve construction".
+//	Hashing follows RFC 9380, "Hashing to Elliptic Curves".
+package bls12381
+
+import "github.com/cloudflare/circl/ecc/bls12381/ff"
+
+// Serialized point lengths in bytes.
+const (
+	G1Size           = 2 * ff.FpSize
+	G1SizeCompressed = ff.FpSize
+	G2Size           = 2 * ff.Fp2Size
+	G2SizeCompressed = ff.Fp2Size
+)
+
+// Flag bits of the first byte of a serialized point.
+const (
+	headerCompressed = 0x80
+	headerInfinity   = 0x40
+	headerBigY       = 0x20
+	headerMask       = headerCompressed | headerInfinity | headerBigY
+)
+
+// primeOrder is the subgroup order r in little-endian words, used to
+// check membership in G1 and G2.
+var primeOrder = [4]uint64{
+	0xffffffff00000001, 0x53bda402fffe5bfe, 0x3339d80809a1d805, 0x73eda753299d7d48,
+}
@@ -0,0 +1,176 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/ecc/bls12381/ff"
+)
+
+func TestG1Arithmetic(t *testing.T) {
+	G := G1Generator()
+	if !G.IsOnG1() {
+		t.Fatal("generator not on G1")
+	}
+
+	var d, s G1
+	d.Double(G)
+	s.Add(G, G)
+	if !d.IsEqual(&s) {
+		t.Fatal("2G != G+G")
+	}
+
+	var a, b, c ff.Scalar
+	_ = a.Random(rand.Reader)
+	_ = b.Random(rand.Reader)
+	c.Add(&a, &b)
+	var aG, bG, cG, sum G1
+	aG.ScalarMult(&a, G)
+	bG.ScalarMult(&b, G)
+	cG.ScalarMult(&c, G)
+	sum.Add(&aG, &bG)
+	if !cG.IsEqual(&sum) {
+		t.Fatal("(a+b)G != aG + bG")
+	}
+	if !aG.IsOnG1() {
+		t.Fatal("scalar multiple not on G1")
+	}
+
+	var id G1
+	id.SetIdentity()
+	sum.Add(&aG, &id)
+	if !sum.IsEqual(&aG) {
+		t.Fatal("P + O != P")
+	}
+	neg := aG
+	neg.Neg()
+	sum.Add(&aG, &neg)
+	if !sum.IsIdentity() {
+		t.Fatal("P + (-P) != O")
+	}
+}
+
+func TestG1Serialization(t *testing.T) {
+	var P G1
+	var k ff.Scalar
+	_ = k.Random(rand.Reader)
+	P.ScalarMult(&k, G1Generator())
+
+	var Q G1
+	if err := Q.SetBytes(P.Bytes()); err != nil || !Q.IsEqual(&P) {
+		t.Fatal("uncompressed roundtrip failed")
+	}
+	if err := Q.SetBytes(P.BytesCompressed()); err != nil || !Q.IsEqual(&P) {
+		t.Fatal("compressed roundtrip failed")
+	}
+
+	var id G1
+	id.SetIdentity()
+	if err := Q.SetBytes(id.BytesCompressed()); err != nil || !Q.IsIdentity() {
+		t.Fatal("identity roundtrip failed")
+	}
+
+	bad := P.BytesCompressed()
+	bad[0] &^= headerCompressed
+	if Q.SetBytes(bad) == nil {
+		t.Fatal("accepted contradictory compression flag")
+	}
+}
+
+func TestG2Arithmetic(t *testing.T) {
+	G := G2Generator()
+	if !G.IsOnG2() {
+		t.Fatal("generator not on G2")
+	}
+
+	var d, s G2
+	d.Double(G)
+	s.Add(G, G)
+	if !d.IsEqual(&s) {
+		t.Fatal("2G != G+G")
+	}
+
+	var a ff.Scalar
+	_ = a.Random(rand.Reader)
+	var aG G2
+	aG.ScalarMult(&a, G)
+	if !aG.IsOnG2() {
+		t.Fatal("scalar multiple not on G2")
+	}
+
+	var Q G2
+	if err := Q.SetBytes(aG.Bytes()); err != nil || !Q.IsEqual(&aG) {
+		t.Fatal("uncompressed roundtrip failed")
+	}
+	if err := Q.SetBytes(aG.BytesCompressed()); err != nil || !Q.IsEqual(&aG) {
+		t.Fatal("compressed roundtrip failed")
+	}
+}
+
+// Vectors from RFC 9380, Appendix J.9.1 (BLS12381G1_XMD:SHA-256_SSWU_RO).
+func TestHashToG1(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_")
+	for _, v := range []struct{ msg, x, y string }{
+		{
+			"",
+			"052926add2207b76ca4fa57a8734416c8dc95e24501772c814278700eed6d1e4e8cf62d9c09db0fac349612b759e79a1",
+			"08ba738453bfed09cb546dbb0783dbb3a5f1f566ed67bb6be0e8c67e2e81a4cc68ee29813bb7994998f3eae0c9c6a265",
+		},
+		{
+			"abc",
+			"03567bc5ef9c690c2ab2ecdf6a96ef1c139cc0b2f284dca0a9a7943388a49a3aee664ba5379a7655d3c68900be2f6903",
+			"0b9c15f3fe6e5cf4211f346271d7b01c8f3b28be689c8429c85b67af215533311f0b8dfaaa154fa6b88176c229f2885d",
+		},
+	} {
+		var P G1
+		P.Hash([]byte(v.msg), dst)
+		if !P.IsOnG1() {
+			t.Fatalf("hash of %q not on G1", v.msg)
+		}
+		want, _ := hex.DecodeString(v.x + v.y)
+		var Q G1
+		if err := Q.SetBytes(want); err != nil {
+			t.Fatal(err)
+		}
+		if !P.IsEqual(&Q) {
+			t.Fatalf("hash of %q disagrees with RFC 9380", v.msg)
+		}
+	}
+}
+
+func TestPairing(t *testing.T) {
+	P := G1Generator()
+	Q := G2Generator()
+
+	e := Pair(P, Q)
+	if e.IsIdentity() {
+		t.Fatal("pairing of the generators is degenerate")
+	}
+
+	var a ff.Scalar
+	_ = a.Random(rand.Reader)
+	var aP G1
+	var aQ G2
+	aP.ScalarMult(&a, P)
+	aQ.ScalarMult(&a, Q)
+	l := Pair(&aP, Q)
+	r := Pair(P, &aQ)
+	if !l.IsEqual(r) {
+		t.Fatal("e(aP, Q) != e(P, aQ)")
+	}
+	if l.IsEqual(e) {
+		t.Fatal("pairing ignores the scalar")
+	}
+
+	var id1 G1
+	id1.SetIdentity()
+	if !Pair(&id1, Q).IsIdentity() {
+		t.Fatal("e(O, Q) != 1")
+	}
+	var id2 G2
+	id2.SetIdentity()
+	if !Pair(P, &id2).IsIdentity() {
+		t.Fatal("e(P, O) != 1")
+	}
+}
@@ -0,0 +1,61 @@
+package ff
+
+// InvBatch replaces every nonzero element of z with its inverse, sharing
+// one Fp inversion across the slice via Montgomery's trick. It backs the
+// batch affine conversions and batch signature verifications of the curve
+// packages above this one.
+func InvBatch(z []Fp) {
+	n := len(z)
+	if n == 0 {
+		return
+	}
+	pre := make([]Fp, n)
+	var r Fp
+	r.SetOne()
+	for i := range z {
+		if z[i].IsZero() == 1 {
+			continue
+		}
+		pre[i] = r
+		r.Mul(&r, &z[i])
+	}
+	r.Inv(&r)
+	var t Fp
+	for i := n - 1; i >= 0; i-- {
+		if z[i].IsZero() == 1 {
+			continue
+		}
+		t = z[i]
+		z[i].Mul(&r, &pre[i])
+		r.Mul(&r, &t)
+	}
+}
+
+// InvBatchFp2 is the quadratic-extension counterpart of InvBatch, used by
+// operations on the G2 twist.
+func InvBatchFp2(z []Fp2) {
+	n := len(z)
+	if n == 0 {
+		return
+	}
+	pre := make([]Fp2, n)
+	var r Fp2
+	r.SetOne()
+	for i := range z {
+		if z[i].IsZero() == 1 {
+			continue
+		}
+		pre[i] = r
+		r.Mul(&r, &z[i])
+	}
+	r.Inv(&r)
+	var t Fp2
+	for i := n - 1; i >= 0; i-- {
+		if z[i].IsZero() == 1 {
+			continue
+		}
+		t = z[i]
+		z[i].Mul(&r, &pre[i])
+		r.Mul(&r, &t)
+	}
+}
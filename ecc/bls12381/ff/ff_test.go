@@ -0,0 +1,141 @@
+package ff
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestFp(t *testing.T) {
+	var x, y Fp
+	if err := x.Random(rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	if err := y.Random(rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+
+	// Field laws.
+	var one, inv, prod Fp
+	one.SetOne()
+	inv.Inv(&x)
+	prod.Mul(&x, &inv)
+	if prod.IsEqual(&one) == 0 {
+		t.Fatal("x * x^-1 != 1")
+	}
+	var s, d Fp
+	s.Add(&x, &y)
+	d.Sub(&s, &y)
+	if d.IsEqual(&x) == 0 {
+		t.Fatal("(x + y) - y != x")
+	}
+	var sq, mm Fp
+	sq.Sqr(&x)
+	mm.Mul(&x, &x)
+	if sq.IsEqual(&mm) == 0 {
+		t.Fatal("Sqr(x) != x*x")
+	}
+
+	// A square's root squares back; x or -x is always a square since
+	// -1 is a non-residue (p = 3 mod 4).
+	var r Fp
+	if r.Sqrt(&sq) == 0 {
+		t.Fatal("square not recognized")
+	}
+	var r2 Fp
+	r2.Sqr(&r)
+	if r2.IsEqual(&sq) == 0 {
+		t.Fatal("Sqrt gave a wrong root")
+	}
+
+	// Encoding roundtrip.
+	var z Fp
+	if err := z.SetBytes(x.Bytes()); err != nil || z.IsEqual(&x) == 0 {
+		t.Fatal("Fp bytes roundtrip failed")
+	}
+
+	// Exactly one of a nonzero x and -x is lexicographically larger.
+	var nx Fp
+	nx.Neg(&x)
+	if x.IsZero() == 0 && x.IsNegative() == nx.IsNegative() {
+		t.Fatal("IsNegative is not a sign")
+	}
+}
+
+func TestFp2(t *testing.T) {
+	var x, y Fp2
+	if err := x.C0.Random(rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	if err := x.C1.Random(rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	y = x
+
+	var one, inv, prod Fp2
+	one.SetOne()
+	inv.Inv(&x)
+	prod.Mul(&x, &inv)
+	if prod.IsEqual(&one) == 0 {
+		t.Fatal("x * x^-1 != 1")
+	}
+
+	var sq, r, back Fp2
+	sq.Sqr(&y)
+	if r.Sqrt(&sq) == 0 {
+		t.Fatal("square not recognized")
+	}
+	back.Sqr(&r)
+	if back.IsEqual(&sq) == 0 {
+		t.Fatal("Sqrt gave a wrong root")
+	}
+
+	var z Fp2
+	if err := z.SetBytes(x.Bytes()); err != nil || z.IsEqual(&x) == 0 {
+		t.Fatal("Fp2 bytes roundtrip failed")
+	}
+}
+
+func TestScalar(t *testing.T) {
+	var x Scalar
+	if err := x.Random(rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+
+	var one, inv, prod Scalar
+	one.SetOne()
+	inv.Inv(&x)
+	prod.Mul(&x, &inv)
+	if prod.IsEqual(&one) == 0 {
+		t.Fatal("x * x^-1 != 1")
+	}
+	var nx, sum Scalar
+	nx.Neg(&x)
+	sum.Add(&x, &nx)
+	if sum.IsZero() == 0 {
+		t.Fatal("x + (-x) != 0")
+	}
+
+	var z Scalar
+	if err := z.SetBytes(x.Bytes()); err != nil || z.IsEqual(&x) == 0 {
+		t.Fatal("scalar bytes roundtrip failed")
+	}
+}
+
+func TestInvBatch(t *testing.T) {
+	z := make([]Fp, 10)
+	want := make([]Fp, 10)
+	for i := range z {
+		if i != 3 { // keep one zero entry
+			if err := z[i].Random(rand.Reader); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want[i].Inv(&z[i])
+	}
+	InvBatch(z)
+	for i := range z {
+		if z[i].IsEqual(&want[i]) == 0 {
+			t.Fatalf("batch inverse %d differs", i)
+		}
+	}
+}
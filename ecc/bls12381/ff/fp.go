@@ -0,0 +1,343 @@
+// Package ff provides the finite fields of the BLS12-381 curve: the
+// 381-bit base field Fp, its quadratic extension Fp2, and the 255-bit
+// scalar field of the prime-order subgroup. Elements expose the
+// receiver-result method style of the curve packages; the higher floors
+// of the extension tower live in the bls12381 package, built with
+// math/tower.
+package ff
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// FpSize is the length in bytes of an Fp element.
+const FpSize = 48
+
+// fpNumWords is the number of 64-bit words of an Fp element.
+const fpNumWords = FpSize / 8
+
+// fpOrder is the base field prime
+// p = 0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f624
+// 1eabfffeb153ffffb9feffffffffaaab, in little-endian words.
+var fpOrder = [fpNumWords]uint64{
+	0xb9feffffffffaaab, 0x1eabfffeb153ffff, 0x6730d2a0f6b0f624,
+	0x64774b84f38512bf, 0x4b1ba7b6434bacd7, 0x1a0111ea397fe69a,
+}
+
+// Fp is an element of the base field, kept reduced below p in
+// little-endian words. The zero value is the zero element.
+type Fp [fpNumWords]uint64
+
+// Montgomery constants of Fp: fpR2 is 2^768 mod p, fpPinv is
+// -p^-1 mod 2^64, and fpHalf is (p-1)/2, used for the sign of an
+// element. All are derived at startup.
+var (
+	fpR2   Fp
+	fpPinv uint64
+	fpHalf [fpNumWords]uint64
+)
+
+func init() {
+	// Newton iteration for the word inverse: each step doubles the
+	// number of correct low bits, starting from at least three for an
+	// odd modulus.
+	inv := fpOrder[0]
+	for i := 0; i < 6; i++ {
+		inv *= 2 - fpOrder[0]*inv
+	}
+	fpPinv = -inv
+
+	fpR2[0] = 1
+	for i := 0; i < 768; i++ {
+		fpR2.Add(&fpR2, &fpR2)
+	}
+
+	var b uint64
+	fpHalf[0], b = bits.Sub64(fpOrder[0], 1, 0)
+	for i := 1; i < fpNumWords; i++ {
+		fpHalf[i], b = bits.Sub64(fpOrder[i], 0, b)
+	}
+	for i := 0; i < fpNumWords-1; i++ {
+		fpHalf[i] = fpHalf[i]>>1 | fpHalf[i+1]<<63
+	}
+	fpHalf[fpNumWords-1] >>= 1
+}
+
+// SetOne sets z to one.
+func (z *Fp) SetOne() { *z = Fp{1} }
+
+// SetUint64 sets z to x.
+func (z *Fp) SetUint64(x uint64) { *z = Fp{x} }
+
+// IsZero returns 1 when z is zero and 0 otherwise, in constant time.
+func (z *Fp) IsZero() int {
+	var v uint64
+	for i := range z {
+		v |= z[i]
+	}
+
+	return int(1 ^ (v|-v)>>63)
+}
+
+// IsEqual returns 1 when z equals x and 0 otherwise, in constant time.
+func (z *Fp) IsEqual(x *Fp) int {
+	var v uint64
+	for i := range z {
+		v |= z[i] ^ x[i]
+	}
+
+	return int(1 ^ (v|-v)>>63)
+}
+
+// IsNegative returns 1 when z is lexicographically larger than -z,
+// i.e., z > (p-1)/2. Serialization uses it to encode the sign of a
+// point's y-coordinate.
+func (z *Fp) IsNegative() int {
+	// z > (p-1)/2 exactly when (p-1)/2 - z borrows.
+	var b uint64
+	for i := range z {
+		_, b = bits.Sub64(fpHalf[i], z[i], b)
+	}
+
+	return int(b)
+}
+
+// Add sets z = x + y.
+func (z *Fp) Add(x, y *Fp) {
+	var t, u Fp
+	var c uint64
+	for i := range t {
+		t[i], c = bits.Add64(x[i], y[i], c)
+	}
+	var b uint64
+	for i := range u {
+		u[i], b = bits.Sub64(t[i], fpOrder[i], b)
+	}
+	// x + y < 2p < 2^384, so the top carry is zero and the reduced
+	// value is wanted exactly when the subtraction did not borrow.
+	mask := b - 1
+	for i := range z {
+		z[i] = (t[i] &^ mask) | (u[i] & mask)
+	}
+}
+
+// Sub sets z = x - y.
+func (z *Fp) Sub(x, y *Fp) {
+	var t Fp
+	var b uint64
+	for i := range t {
+		t[i], b = bits.Sub64(x[i], y[i], b)
+	}
+	mask := -b
+	var c uint64
+	for i := range z {
+		z[i], c = bits.Add64(t[i], fpOrder[i]&mask, c)
+	}
+}
+
+// Neg sets z = -x.
+func (z *Fp) Neg(x *Fp) {
+	var zero Fp
+	z.Sub(&zero, x)
+}
+
+// montMul sets z = x * y / 2^384 mod p by coarsely integrated operand
+// scanning, with the final conditional subtraction done by masking.
+func montMul(z, x, y *Fp) {
+	var t [fpNumWords + 2]uint64
+	for i := 0; i < fpNumWords; i++ {
+		var c uint64
+		for j := 0; j < fpNumWords; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			s, c1 := bits.Add64(t[j], lo, 0)
+			s, c2 := bits.Add64(s, c, 0)
+			t[j] = s
+			c = hi + c1 + c2 // hi <= 2^64-2, so this cannot overflow
+		}
+		s, c1 := bits.Add64(t[fpNumWords], c, 0)
+		t[fpNumWords] = s
+		t[fpNumWords+1] += c1
+
+		m := t[0] * fpPinv
+		c = 0
+		for j := 0; j < fpNumWords; j++ {
+			hi, lo := bits.Mul64(m, fpOrder[j])
+			s, c1 := bits.Add64(t[j], lo, 0)
+			s, c2 := bits.Add64(s, c, 0)
+			t[j] = s
+			c = hi + c1 + c2
+		}
+		s, c1 = bits.Add64(t[fpNumWords], c, 0)
+		t[fpNumWords] = s
+		t[fpNumWords+1] += c1
+
+		// t[0] is zero now; shift down one word.
+		for j := 0; j < fpNumWords+1; j++ {
+			t[j] = t[j+1]
+		}
+		t[fpNumWords+1] = 0
+	}
+
+	// The result is below 2p, which fits the low words.
+	var u Fp
+	var b uint64
+	for i := range u {
+		u[i], b = bits.Sub64(t[i], fpOrder[i], b)
+	}
+	mask := b - 1
+	for i := range z {
+		z[i] = (t[i] &^ mask) | (u[i] & mask)
+	}
+}
+
+// Mul sets z = x * y. Values live in the plain domain; the Montgomery
+// factor of montMul is cancelled by a multiplication with fpR2.
+func (z *Fp) Mul(x, y *Fp) {
+	var t Fp
+	montMul(&t, x, y)
+	montMul(z, &t, &fpR2)
+}
+
+// Sqr sets z = x^2.
+func (z *Fp) Sqr(x *Fp) { z.Mul(x, x) }
+
+// Inv sets z = x^(p-2), the inverse of nonzero x and zero for x = 0.
+// The ladder runs in the Montgomery domain so every step costs one
+// reduction.
+func (z *Fp) Inv(x *Fp) {
+	one := Fp{1}
+	var xm, acc Fp
+	montMul(&xm, x, &fpR2)
+	montMul(&acc, &fpR2, &one) // 1 in the Montgomery domain
+
+	e := fpOrder
+	e[0] -= 2 // p is odd, so no borrow
+	for i := fpNumWords - 1; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			montMul(&acc, &acc, &acc)
+			if e[i]>>uint(j)&1 == 1 {
+				montMul(&acc, &acc, &xm)
+			}
+		}
+	}
+	montMul(z, &acc, &one)
+}
+
+// expVarTime sets z = x^e for the public little-endian word exponent e.
+func (z *Fp) expVarTime(x *Fp, e []uint64) {
+	var acc Fp
+	acc.SetOne()
+	started := false
+	for i := len(e) - 1; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			if started {
+				acc.Sqr(&acc)
+			}
+			if e[i]>>uint(j)&1 == 1 {
+				if started {
+					acc.Mul(&acc, x)
+				} else {
+					acc = *x
+					started = true
+				}
+			}
+		}
+	}
+	*z = acc
+}
+
+// Sqrt sets z to a square root of x and returns 1, or returns 0
+// leaving z undefined when x is not a square. Since p = 3 mod 4 the
+// root is the (p+1)/4 power.
+func (z *Fp) Sqrt(x *Fp) int {
+	var e [fpNumWords]uint64
+	var c uint64
+	e[0], c = bits.Add64(fpOrder[0], 1, 0)
+	for i := 1; i < fpNumWords; i++ {
+		e[i], c = bits.Add64(fpOrder[i], 0, c)
+	}
+	for i := 0; i < fpNumWords-1; i++ {
+		e[i] = e[i]>>2 | e[i+1]<<62
+	}
+	e[fpNumWords-1] >>= 2
+
+	var r, t Fp
+	r.expVarTime(x, e[:])
+	t.Sqr(&r)
+	if t.IsEqual(x) == 0 {
+		return 0
+	}
+	*z = r
+
+	return 1
+}
+
+// SetBytes reconstructs an element from its 48-byte big-endian
+// encoding, rejecting non-canonical values.
+func (z *Fp) SetBytes(data []byte) error {
+	if len(data) != FpSize {
+		return errors.New("ff: invalid Fp encoding length")
+	}
+	var t Fp
+	for i := 0; i < FpSize; i++ {
+		t[i/8] |= uint64(data[FpSize-1-i]) << (8 * (i % 8))
+	}
+	var b uint64
+	for i := range t {
+		_, b = bits.Sub64(t[i], fpOrder[i], b)
+	}
+	if b == 0 { // t >= p
+		return errors.New("ff: non-canonical Fp encoding")
+	}
+	*z = t
+
+	return nil
+}
+
+// SetBytesWide sets z to the value of the big-endian byte string data
+// reduced modulo p, as hash-to-field requires.
+func (z *Fp) SetBytesWide(data []byte) {
+	w256 := Fp{256}
+	var t Fp
+	for _, b := range data {
+		t.Mul(&t, &w256)
+		bb := Fp{uint64(b)}
+		t.Add(&t, &bb)
+	}
+	*z = t
+}
+
+// Bytes returns the 48-byte big-endian encoding of z.
+func (z *Fp) Bytes() []byte {
+	out := make([]byte, FpSize)
+	for i := 0; i < FpSize; i++ {
+		out[FpSize-1-i] = byte(z[i/8] >> (8 * (i % 8)))
+	}
+
+	return out
+}
+
+// CMov sets z = y when b is 1 and z = x when b is 0, in constant time.
+// The bit b must be 0 or 1.
+func (z *Fp) CMov(x, y *Fp, b int) {
+	mask := -uint64(b)
+	for i := range z {
+		z[i] = (x[i] &^ mask) | (y[i] & mask)
+	}
+}
+
+// Random sets z to a uniform element read from rnd.
+func (z *Fp) Random(rnd io.Reader) error {
+	var buf [FpSize]byte
+	for {
+		if _, err := io.ReadFull(rnd, buf[:]); err != nil {
+			return err
+		}
+		buf[0] &= 0x1f // p < 2^381
+		if z.SetBytes(buf[:]) == nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package ff
+
+import "errors"
+
+// Fp2Size is the length in bytes of an Fp2 element.
+const Fp2Size = 2 * FpSize
+
+// Fp2 is an element C0 + C1*u of the quadratic extension
+// Fp[u]/(u^2 + 1). The zero value is the zero element.
+type Fp2 struct {
+	C0, C1 Fp
+}
+
+// SetOne sets z to one.
+func (z *Fp2) SetOne() { z.C0.SetOne(); z.C1 = Fp{} }
+
+// IsZero returns 1 when z is zero and 0 otherwise, in constant time.
+func (z *Fp2) IsZero() int { return z.C0.IsZero() & z.C1.IsZero() }
+
+// IsEqual returns 1 when z equals x and 0 otherwise, in constant time.
+func (z *Fp2) IsEqual(x *Fp2) int { return z.C0.IsEqual(&x.C0) & z.C1.IsEqual(&x.C1) }
+
+// IsNegative returns 1 when z is lexicographically larger than -z: the
+// C1 coordinate decides, with ties broken by C0. Serialization uses it
+// to encode the sign of a G2 point's y-coordinate.
+func (z *Fp2) IsNegative() int {
+	c1Zero := z.C1.IsZero()
+
+	return (1-c1Zero)&z.C1.IsNegative() | c1Zero&z.C0.IsNegative()
+}
+
+// Add sets z = x + y.
+func (z *Fp2) Add(x, y *Fp2) { z.C0.Add(&x.C0, &y.C0); z.C1.Add(&x.C1, &y.C1) }
+
+// Sub sets z = x - y.
+func (z *Fp2) Sub(x, y *Fp2) { z.C0.Sub(&x.C0, &y.C0); z.C1.Sub(&x.C1, &y.C1) }
+
+// Neg sets z = -x.
+func (z *Fp2) Neg(x *Fp2) { z.C0.Neg(&x.C0); z.C1.Neg(&x.C1) }
+
+// Cjg sets z to the conjugate of x.
+func (z *Fp2) Cjg(x *Fp2) { z.C0 = x.C0; z.C1.Neg(&x.C1) }
+
+// Mul sets z = x * y by Karatsuba over the two coordinates.
+func (z *Fp2) Mul(x, y *Fp2) {
+	var t0, t1, t2, sx, sy Fp
+	t0.Mul(&x.C0, &y.C0)
+	t1.Mul(&x.C1, &y.C1)
+	sx.Add(&x.C0, &x.C1)
+	sy.Add(&y.C0, &y.C1)
+	t2.Mul(&sx, &sy)
+	t2.Sub(&t2, &t0)
+	t2.Sub(&t2, &t1)
+	z.C0.Sub(&t0, &t1)
+	z.C1 = t2
+}
+
+// Sqr sets z = x^2.
+func (z *Fp2) Sqr(x *Fp2) { z.Mul(x, x) }
+
+// Inv sets z to the inverse of nonzero x: (a - b*u)/(a^2 + b^2).
+func (z *Fp2) Inv(x *Fp2) {
+	var d, t Fp
+	d.Sqr(&x.C0)
+	t.Sqr(&x.C1)
+	d.Add(&d, &t)
+	d.Inv(&d)
+	z.C0.Mul(&x.C0, &d)
+	d.Neg(&d)
+	z.C1.Mul(&x.C1, &d)
+}
+
+// Sqrt sets z to a square root of x and returns 1, or returns 0
+// leaving z undefined when x is not a square. It reduces to base-field
+// roots: for a + b*u with b nonzero, a root c0 + c1*u satisfies
+// c0^2 = (a ± s)/2 and c1 = b/(2*c0), where s^2 = a^2 + b^2 is the
+// norm.
+func (z *Fp2) Sqrt(x *Fp2) int {
+	if x.C1.IsZero() == 1 {
+		var r Fp
+		if r.Sqrt(&x.C0) == 1 {
+			z.C0, z.C1 = r, Fp{}
+			return 1
+		}
+		// -a must be a square then; its root times u is the answer.
+		var na Fp
+		na.Neg(&x.C0)
+		if r.Sqrt(&na) == 1 {
+			z.C0, z.C1 = Fp{}, r
+			return 1
+		}
+
+		return 0
+	}
+
+	var n, t, s, half Fp
+	n.Sqr(&x.C0)
+	t.Sqr(&x.C1)
+	n.Add(&n, &t)
+	if s.Sqrt(&n) == 0 {
+		return 0 // the norm of a square is a square
+	}
+	two := Fp{2}
+	half.Inv(&two)
+
+	var c0, c02 Fp
+	c02.Add(&x.C0, &s)
+	c02.Mul(&c02, &half)
+	if c0.Sqrt(&c02) == 0 {
+		c02.Sub(&x.C0, &s)
+		c02.Mul(&c02, &half)
+		if c0.Sqrt(&c02) == 0 {
+			return 0
+		}
+	}
+	var c1, d Fp
+	d.Add(&c0, &c0)
+	d.Inv(&d)
+	c1.Mul(&x.C1, &d)
+
+	r := Fp2{c0, c1}
+	var check Fp2
+	check.Sqr(&r)
+	if check.IsEqual(x) == 0 {
+		return 0
+	}
+	*z = r
+
+	return 1
+}
+
+// CMov sets z = y when b is 1 and z = x when b is 0, in constant time.
+// The bit b must be 0 or 1.
+func (z *Fp2) CMov(x, y *Fp2, b int) {
+	z.C0.CMov(&x.C0, &y.C0, b)
+	z.C1.CMov(&x.C1, &y.C1, b)
+}
+
+// SetBytes reconstructs an element from its 96-byte big-endian
+// encoding C1 || C0, the coordinate order of the G2 serialization.
+func (z *Fp2) SetBytes(data []byte) error {
+	if len(data) != Fp2Size {
+		return errors.New("ff: invalid Fp2 encoding length")
+	}
+	var t Fp2
+	if err := t.C1.SetBytes(data[:FpSize]); err != nil {
+		return err
+	}
+	if err := t.C0.SetBytes(data[FpSize:]); err != nil {
+		return err
+	}
+	*z = t
+
+	return nil
+}
+
+// Bytes returns the 96-byte big-endian encoding C1 || C0.
+func (z *Fp2) Bytes() []byte {
+	return append(z.C1.Bytes(), z.C0.Bytes()...)
+}
@@ -0,0 +1,250 @@
+package ff
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// ScalarSize is the length in bytes of a Scalar.
+const ScalarSize = 32
+
+// scalarNumWords is the number of 64-bit words of a Scalar.
+const scalarNumWords = ScalarSize / 8
+
+// scalarOrder is the prime order of the BLS12-381 subgroups,
+// r = 0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001,
+// in little-endian words.
+var scalarOrder = [scalarNumWords]uint64{
+	0xffffffff00000001, 0x53bda402fffe5bfe, 0x3339d80809a1d805, 0x73eda753299d7d48,
+}
+
+// Scalar is an integer modulo the subgroup order r, kept reduced in
+// little-endian words. The zero value is zero.
+type Scalar [scalarNumWords]uint64
+
+// Montgomery constants of Scalar: scR2 is 2^512 mod r and scPinv is
+// -r^-1 mod 2^64, derived at startup.
+var (
+	scR2   Scalar
+	scPinv uint64
+)
+
+func init() {
+	inv := scalarOrder[0]
+	for i := 0; i < 6; i++ {
+		inv *= 2 - scalarOrder[0]*inv
+	}
+	scPinv = -inv
+
+	scR2[0] = 1
+	for i := 0; i < 512; i++ {
+		scR2.Add(&scR2, &scR2)
+	}
+}
+
+// SetOne sets z to one.
+func (z *Scalar) SetOne() { *z = Scalar{1} }
+
+// SetUint64 sets z to x.
+func (z *Scalar) SetUint64(x uint64) { *z = Scalar{x} }
+
+// IsZero returns 1 when z is zero and 0 otherwise, in constant time.
+func (z *Scalar) IsZero() int {
+	var v uint64
+	for i := range z {
+		v |= z[i]
+	}
+
+	return int(1 ^ (v|-v)>>63)
+}
+
+// IsEqual returns 1 when z equals x and 0 otherwise, in constant time.
+func (z *Scalar) IsEqual(x *Scalar) int {
+	var v uint64
+	for i := range z {
+		v |= z[i] ^ x[i]
+	}
+
+	return int(1 ^ (v|-v)>>63)
+}
+
+// Add sets z = x + y.
+func (z *Scalar) Add(x, y *Scalar) {
+	var t, u Scalar
+	var c uint64
+	for i := range t {
+		t[i], c = bits.Add64(x[i], y[i], c)
+	}
+	var b uint64
+	for i := range u {
+		u[i], b = bits.Sub64(t[i], scalarOrder[i], b)
+	}
+	mask := b - 1
+	for i := range z {
+		z[i] = (t[i] &^ mask) | (u[i] & mask)
+	}
+}
+
+// Sub sets z = x - y.
+func (z *Scalar) Sub(x, y *Scalar) {
+	var t Scalar
+	var b uint64
+	for i := range t {
+		t[i], b = bits.Sub64(x[i], y[i], b)
+	}
+	mask := -b
+	var c uint64
+	for i := range z {
+		z[i], c = bits.Add64(t[i], scalarOrder[i]&mask, c)
+	}
+}
+
+// Neg sets z = -x.
+func (z *Scalar) Neg(x *Scalar) {
+	var zero Scalar
+	z.Sub(&zero, x)
+}
+
+// scMontMul sets z = x * y / 2^256 mod r, the four-word sibling of the
+// Fp reduction.
+func scMontMul(z, x, y *Scalar) {
+	var t [scalarNumWords + 2]uint64
+	for i := 0; i < scalarNumWords; i++ {
+		var c uint64
+		for j := 0; j < scalarNumWords; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			s, c1 := bits.Add64(t[j], lo, 0)
+			s, c2 := bits.Add64(s, c, 0)
+			t[j] = s
+			c = hi + c1 + c2
+		}
+		s, c1 := bits.Add64(t[scalarNumWords], c, 0)
+		t[scalarNumWords] = s
+		t[scalarNumWords+1] += c1
+
+		m := t[0] * scPinv
+		c = 0
+		for j := 0; j < scalarNumWords; j++ {
+			hi, lo := bits.Mul64(m, scalarOrder[j])
+			s, c1 := bits.Add64(t[j], lo, 0)
+			s, c2 := bits.Add64(s, c, 0)
+			t[j] = s
+			c = hi + c1 + c2
+		}
+		s, c1 = bits.Add64(t[scalarNumWords], c, 0)
+		t[scalarNumWords] = s
+		t[scalarNumWords+1] += c1
+
+		for j := 0; j < scalarNumWords+1; j++ {
+			t[j] = t[j+1]
+		}
+		t[scalarNumWords+1] = 0
+	}
+
+	var u Scalar
+	var b uint64
+	for i := range u {
+		u[i], b = bits.Sub64(t[i], scalarOrder[i], b)
+	}
+	mask := b - 1
+	for i := range z {
+		z[i] = (t[i] &^ mask) | (u[i] & mask)
+	}
+}
+
+// Mul sets z = x * y.
+func (z *Scalar) Mul(x, y *Scalar) {
+	var t Scalar
+	scMontMul(&t, x, y)
+	scMontMul(z, &t, &scR2)
+}
+
+// Sqr sets z = x^2.
+func (z *Scalar) Sqr(x *Scalar) { z.Mul(x, x) }
+
+// Inv sets z = x^(r-2), the inverse of nonzero x and zero for x = 0.
+func (z *Scalar) Inv(x *Scalar) {
+	one := Scalar{1}
+	var xm, acc Scalar
+	scMontMul(&xm, x, &scR2)
+	scMontMul(&acc, &scR2, &one)
+
+	e := scalarOrder
+	e[0] -= 2
+	for i := scalarNumWords - 1; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			scMontMul(&acc, &acc, &acc)
+			if e[i]>>uint(j)&1 == 1 {
+				scMontMul(&acc, &acc, &xm)
+			}
+		}
+	}
+	scMontMul(z, &acc, &one)
+}
+
+// SetBytes reconstructs a scalar from its 32-byte big-endian encoding,
+// rejecting values above the order.
+func (z *Scalar) SetBytes(data []byte) error {
+	if len(data) != ScalarSize {
+		return errors.New("ff: invalid scalar encoding length")
+	}
+	var t Scalar
+	for i := 0; i < ScalarSize; i++ {
+		t[i/8] |= uint64(data[ScalarSize-1-i]) << (8 * (i % 8))
+	}
+	var b uint64
+	for i := range t {
+		_, b = bits.Sub64(t[i], scalarOrder[i], b)
+	}
+	if b == 0 { // t >= r
+		return errors.New("ff: non-canonical scalar encoding")
+	}
+	*z = t
+
+	return nil
+}
+
+// SetBytesWide sets z to the value of the big-endian byte string data
+// reduced modulo r, as hash-to-scalar requires.
+func (z *Scalar) SetBytesWide(data []byte) {
+	w256 := Scalar{256}
+	var t Scalar
+	for _, b := range data {
+		t.Mul(&t, &w256)
+		bb := Scalar{uint64(b)}
+		t.Add(&t, &bb)
+	}
+	*z = t
+}
+
+// Bytes returns the 32-byte big-endian encoding of z.
+func (z *Scalar) Bytes() []byte {
+	out := make([]byte, ScalarSize)
+	for i := 0; i < ScalarSize; i++ {
+		out[ScalarSize-1-i] = byte(z[i/8] >> (8 * (i % 8)))
+	}
+
+	return out
+}
+
+// Random sets z to a uniform scalar read from rnd.
+func (z *Scalar) Random(rnd io.Reader) error {
+	var buf [ScalarSize]byte
+	for {
+		if _, err := io.ReadFull(rnd, buf[:]); err != nil {
+			return err
+		}
+		buf[0] &= 0x7f // r < 2^255
+		if z.SetBytes(buf[:]) == nil {
+			return nil
+		}
+	}
+}
+
+// Bit returns bit i of the scalar, for the double-and-add loops of the
+// curve packages.
+func (z *Scalar) Bit(i int) uint64 { return z[i/64] >> (uint(i) % 64) & 1 }
+
+// ScalarBits is the bit length of the scalar field order.
+const ScalarBits = 255
@@ -0,0 +1,326 @@
+package bls12381
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/cloudflare/circl/ecc/bls12381/ff"
+)
+
+// G1 is a point on the curve y^2 = x^3 + 4 over the base field, kept in
+// projective coordinates. The zero value is invalid; initialize points
+// with SetIdentity, G1Generator, SetBytes, or Hash.
+type G1 struct{ x, y, z ff.Fp }
+
+// g1Params holds the curve constants of G1, parsed at startup.
+var g1Params struct {
+	b, b3, genX, genY ff.Fp
+}
+
+func init() {
+	g1Params.b.SetUint64(4)
+	g1Params.b3.SetUint64(12)
+	g1Params.genX = mustFp("17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb")
+	g1Params.genY = mustFp("08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1")
+}
+
+// mustFp parses a 96-digit hex string into a field element; it is only
+// called on the package's own constants.
+func mustFp(s string) (f ff.Fp) {
+	data, err := hex.DecodeString(s)
+	if err == nil {
+		err = f.SetBytes(data)
+	}
+	if err != nil {
+		panic("bls12381: bad curve constant: " + err.Error())
+	}
+
+	return
+}
+
+// G1Generator returns the standard generator of G1.
+func G1Generator() *G1 {
+	var P G1
+	P.x = g1Params.genX
+	P.y = g1Params.genY
+	P.z.SetOne()
+
+	return &P
+}
+
+// SetIdentity sets P to the identity of the group.
+func (P *G1) SetIdentity() {
+	P.x = ff.Fp{}
+	P.y.SetOne()
+	P.z = ff.Fp{}
+}
+
+// IsIdentity reports whether P is the identity.
+func (P *G1) IsIdentity() bool { return P.z.IsZero() == 1 }
+
+// IsEqual reports whether P and Q are the same point, comparing the
+// projective coordinates cross-multiplied.
+func (P *G1) IsEqual(Q *G1) bool {
+	var l, r ff.Fp
+	l.Mul(&P.x, &Q.z)
+	r.Mul(&Q.x, &P.z)
+	if l.IsEqual(&r) == 0 {
+		return false
+	}
+	l.Mul(&P.y, &Q.z)
+	r.Mul(&Q.y, &P.z)
+
+	return l.IsEqual(&r) == 1
+}
+
+// Neg sets P = -P.
+func (P *G1) Neg() { P.y.Neg(&P.y) }
+
+// Add sets P = Q + R with the complete formulas of Renes, Costello, and
+// Batina for j-invariant-zero curves; they hold for every pair of
+// inputs, doublings and the identity included, since the groups of
+// rational points have odd order.
+func (P *G1) Add(Q, R *G1) {
+	b3 := &g1Params.b3
+	x1, y1, z1 := &Q.x, &Q.y, &Q.z
+	x2, y2, z2 := &R.x, &R.y, &R.z
+	var t0, t1, t2, t3, t4, x3, y3, z3 ff.Fp
+
+	t0.Mul(x1, x2)
+	t1.Mul(y1, y2)
+	t2.Mul(z1, z2)
+	t3.Add(x1, y1)
+	t4.Add(x2, y2)
+	t3.Mul(&t3, &t4)
+	t4.Add(&t0, &t1)
+	t3.Sub(&t3, &t4)
+	t4.Add(y1, z1)
+	x3.Add(y2, z2)
+	t4.Mul(&t4, &x3)
+	x3.Add(&t1, &t2)
+	t4.Sub(&t4, &x3)
+	x3.Add(x1, z1)
+	y3.Add(x2, z2)
+	x3.Mul(&x3, &y3)
+	y3.Add(&t0, &t2)
+	y3.Sub(&x3, &y3)
+	x3.Add(&t0, &t0)
+	t0.Add(&x3, &t0)
+	t2.Mul(b3, &t2)
+	z3.Add(&t1, &t2)
+	t1.Sub(&t1, &t2)
+	y3.Mul(b3, &y3)
+	x3.Mul(&t4, &y3)
+	t2.Mul(&t3, &t1)
+	x3.Sub(&t2, &x3)
+	y3.Mul(&y3, &t0)
+	t1.Mul(&t1, &z3)
+	y3.Add(&t1, &y3)
+	t0.Mul(&t0, &t3)
+	z3.Mul(&z3, &t4)
+	z3.Add(&z3, &t0)
+
+	P.x, P.y, P.z = x3, y3, z3
+}
+
+// Double sets P = 2Q.
+func (P *G1) Double(Q *G1) {
+	b3 := &g1Params.b3
+	x, y, z := &Q.x, &Q.y, &Q.z
+	var t0, t1, t2, x3, y3, z3 ff.Fp
+
+	t0.Sqr(y)
+	z3.Add(&t0, &t0)
+	z3.Add(&z3, &z3)
+	z3.Add(&z3, &z3)
+	t1.Mul(y, z)
+	t2.Sqr(z)
+	t2.Mul(b3, &t2)
+	x3.Mul(&t2, &z3)
+	y3.Add(&t0, &t2)
+	z3.Mul(&t1, &z3)
+	t1.Add(&t2, &t2)
+	t2.Add(&t1, &t2)
+	t0.Sub(&t0, &t2)
+	y3.Mul(&t0, &y3)
+	y3.Add(&x3, &y3)
+	t1.Mul(x, y)
+	x3.Mul(&t0, &t1)
+	x3.Add(&x3, &x3)
+
+	P.x, P.y, P.z = x3, y3, z3
+}
+
+// cmov sets P = Q when b is 1 and leaves P unchanged when b is 0.
+func (P *G1) cmov(Q *G1, b int) {
+	P.x.CMov(&P.x, &Q.x, b)
+	P.y.CMov(&P.y, &Q.y, b)
+	P.z.CMov(&P.z, &Q.z, b)
+}
+
+// ScalarMult sets P = k*Q in constant time with a double-and-always-add
+// ladder over the bits of k.
+func (P *G1) ScalarMult(k *ff.Scalar, Q *G1) {
+	var acc, t G1
+	acc.SetIdentity()
+	for i := ff.ScalarBits - 1; i >= 0; i-- {
+		acc.Double(&acc)
+		t.Add(&acc, Q)
+		acc.cmov(&t, int(k.Bit(i)))
+	}
+	*P = acc
+}
+
+// IsOnG1 reports whether P lies on the curve and in the prime-order
+// subgroup.
+func (P *G1) IsOnG1() bool { return P.isOnCurve() && P.isRTorsion() }
+
+// isOnCurve checks the projective curve equation y^2 z = x^3 + 4 z^3,
+// which the identity (0 : 1 : 0) satisfies.
+func (P *G1) isOnCurve() bool {
+	var l, r, t ff.Fp
+	l.Sqr(&P.y)
+	l.Mul(&l, &P.z)
+	r.Sqr(&P.x)
+	r.Mul(&r, &P.x)
+	t.Sqr(&P.z)
+	t.Mul(&t, &P.z)
+	t.Mul(&t, &g1Params.b)
+	r.Add(&r, &t)
+
+	return l.IsEqual(&r) == 1
+}
+
+// isRTorsion checks that r*P is the identity; the order is public, so a
+// plain double-and-add suffices.
+func (P *G1) isRTorsion() bool {
+	var acc, t G1
+	acc.SetIdentity()
+	for i := 4*64 - 1; i >= 0; i-- {
+		acc.Double(&acc)
+		if primeOrder[i/64]>>(uint(i)%64)&1 == 1 {
+			t.Add(&acc, P)
+			acc = t
+		}
+	}
+
+	return acc.IsIdentity()
+}
+
+// toAffine scales the coordinates so that z is one; the identity is left
+// untouched.
+func (P *G1) toAffine() {
+	if P.IsIdentity() {
+		return
+	}
+	var zInv ff.Fp
+	zInv.Inv(&P.z)
+	P.x.Mul(&P.x, &zInv)
+	P.y.Mul(&P.y, &zInv)
+	P.z.SetOne()
+}
+
+// Bytes returns the 96-byte uncompressed encoding of P.
+func (P *G1) Bytes() []byte {
+	out := make([]byte, G1Size)
+	if P.IsIdentity() {
+		out[0] = headerInfinity
+		return out
+	}
+	Q := *P
+	Q.toAffine()
+	copy(out[:ff.FpSize], Q.x.Bytes())
+	copy(out[ff.FpSize:], Q.y.Bytes())
+
+	return out
+}
+
+// BytesCompressed returns the 48-byte compressed encoding of P.
+func (P *G1) BytesCompressed() []byte {
+	out := make([]byte, G1SizeCompressed)
+	if P.IsIdentity() {
+		out[0] = headerCompressed | headerInfinity
+		return out
+	}
+	Q := *P
+	Q.toAffine()
+	copy(out, Q.x.Bytes())
+	out[0] |= headerCompressed
+	if Q.y.IsNegative() == 1 {
+		out[0] |= headerBigY
+	}
+
+	return out
+}
+
+// SetBytes reconstructs a point from either encoding, verifying that it
+// lies on the curve. Subgroup membership is a separate check, IsOnG1.
+func (P *G1) SetBytes(data []byte) error {
+	if len(data) != G1Size && len(data) != G1SizeCompressed {
+		return errors.New("bls12381: invalid G1 encoding length")
+	}
+	header := data[0] & headerMask
+	compressed := header&headerCompressed != 0
+	if compressed != (len(data) == G1SizeCompressed) {
+		return errors.New("bls12381: G1 compression flag contradicts length")
+	}
+
+	if header&headerInfinity != 0 {
+		for i, b := range data {
+			if i == 0 {
+				b &^= headerMask
+			}
+			if b != 0 {
+				return errors.New("bls12381: non-canonical G1 identity encoding")
+			}
+		}
+		if header&headerBigY != 0 {
+			return errors.New("bls12381: non-canonical G1 identity encoding")
+		}
+		P.SetIdentity()
+
+		return nil
+	}
+
+	xBytes := make([]byte, ff.FpSize)
+	copy(xBytes, data[:ff.FpSize])
+	xBytes[0] &^= headerMask
+	var Q G1
+	if err := Q.x.SetBytes(xBytes); err != nil {
+		return err
+	}
+	Q.z.SetOne()
+
+	if !compressed {
+		if header&headerBigY != 0 {
+			return errors.New("bls12381: sign flag set on uncompressed G1 point")
+		}
+		if err := Q.y.SetBytes(data[ff.FpSize:]); err != nil {
+			return err
+		}
+		if !Q.isOnCurve() {
+			return errors.New("bls12381: G1 point not on curve")
+		}
+		*P = Q
+
+		return nil
+	}
+
+	var y2 ff.Fp
+	y2.Sqr(&Q.x)
+	y2.Mul(&y2, &Q.x)
+	y2.Add(&y2, &g1Params.b)
+	if Q.y.Sqrt(&y2) == 0 {
+		return errors.New("bls12381: G1 point not on curve")
+	}
+	wantBig := 0
+	if header&headerBigY != 0 {
+		wantBig = 1
+	}
+	if Q.y.IsNegative() != wantBig {
+		Q.y.Neg(&Q.y)
+	}
+	*P = Q
+
+	return nil
+}
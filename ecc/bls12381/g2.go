@@ -0,0 +1,309 @@
+package bls12381
+
+import (
+	"errors"
+
+	"github.com/cloudflare/circl/ecc/bls12381/ff"
+)
+
+// G2 is a point on the twist y^2 = x^3 + 4(1+u) over the quadratic
+// extension field, kept in projective coordinates. The zero value is
+// invalid; initialize points with SetIdentity, G2Generator, or SetBytes.
+type G2 struct{ x, y, z ff.Fp2 }
+
+// g2Params holds the curve constants of G2, parsed at startup.
+var g2Params struct {
+	b, b3, genX, genY ff.Fp2
+}
+
+func init() {
+	g2Params.b.C0.SetUint64(4)
+	g2Params.b.C1.SetUint64(4)
+	g2Params.b3.C0.SetUint64(12)
+	g2Params.b3.C1.SetUint64(12)
+	g2Params.genX.C0 = mustFp("024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8")
+	g2Params.genX.C1 = mustFp("13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e")
+	g2Params.genY.C0 = mustFp("0ce5d527727d6e118cc9cdc6da2e351aadfd9baa8cbdd3a76d429a695160d12c923ac9cc3baca289e193548608b82801")
+	g2Params.genY.C1 = mustFp("0606c4a02ea734cc32acd2b02bc28b99cb3e287e85a763af267492ab572e99ab3f370d275cec1da1aaa9075ff05f79be")
+}
+
+// G2Generator returns the standard generator of G2.
+func G2Generator() *G2 {
+	var P G2
+	P.x = g2Params.genX
+	P.y = g2Params.genY
+	P.z.SetOne()
+
+	return &P
+}
+
+// SetIdentity sets P to the identity of the group.
+func (P *G2) SetIdentity() {
+	P.x = ff.Fp2{}
+	P.y.SetOne()
+	P.z = ff.Fp2{}
+}
+
+// IsIdentity reports whether P is the identity.
+func (P *G2) IsIdentity() bool { return P.z.IsZero() == 1 }
+
+// IsEqual reports whether P and Q are the same point, comparing the
+// projective coordinates cross-multiplied.
+func (P *G2) IsEqual(Q *G2) bool {
+	var l, r ff.Fp2
+	l.Mul(&P.x, &Q.z)
+	r.Mul(&Q.x, &P.z)
+	if l.IsEqual(&r) == 0 {
+		return false
+	}
+	l.Mul(&P.y, &Q.z)
+	r.Mul(&Q.y, &P.z)
+
+	return l.IsEqual(&r) == 1
+}
+
+// Neg sets P = -P.
+func (P *G2) Neg() { P.y.Neg(&P.y) }
+
+// Add sets P = Q + R with the same complete formulas as in G1; the
+// twist's group of rational points also has odd order.
+func (P *G2) Add(Q, R *G2) {
+	b3 := &g2Params.b3
+	x1, y1, z1 := &Q.x, &Q.y, &Q.z
+	x2, y2, z2 := &R.x, &R.y, &R.z
+	var t0, t1, t2, t3, t4, x3, y3, z3 ff.Fp2
+
+	t0.Mul(x1, x2)
+	t1.Mul(y1, y2)
+	t2.Mul(z1, z2)
+	t3.Add(x1, y1)
+	t4.Add(x2, y2)
+	t3.Mul(&t3, &t4)
+	t4.Add(&t0, &t1)
+	t3.Sub(&t3, &t4)
+	t4.Add(y1, z1)
+	x3.Add(y2, z2)
+	t4.Mul(&t4, &x3)
+	x3.Add(&t1, &t2)
+	t4.Sub(&t4, &x3)
+	x3.Add(x1, z1)
+	y3.Add(x2, z2)
+	x3.Mul(&x3, &y3)
+	y3.Add(&t0, &t2)
+	y3.Sub(&x3, &y3)
+	x3.Add(&t0, &t0)
+	t0.Add(&x3, &t0)
+	t2.Mul(b3, &t2)
+	z3.Add(&t1, &t2)
+	t1.Sub(&t1, &t2)
+	y3.Mul(b3, &y3)
+	x3.Mul(&t4, &y3)
+	t2.Mul(&t3, &t1)
+	x3.Sub(&t2, &x3)
+	y3.Mul(&y3, &t0)
+	t1.Mul(&t1, &z3)
+	y3.Add(&t1, &y3)
+	t0.Mul(&t0, &t3)
+	z3.Mul(&z3, &t4)
+	z3.Add(&z3, &t0)
+
+	P.x, P.y, P.z = x3, y3, z3
+}
+
+// Double sets P = 2Q.
+func (P *G2) Double(Q *G2) {
+	b3 := &g2Params.b3
+	x, y, z := &Q.x, &Q.y, &Q.z
+	var t0, t1, t2, x3, y3, z3 ff.Fp2
+
+	t0.Sqr(y)
+	z3.Add(&t0, &t0)
+	z3.Add(&z3, &z3)
+	z3.Add(&z3, &z3)
+	t1.Mul(y, z)
+	t2.Sqr(z)
+	t2.Mul(b3, &t2)
+	x3.Mul(&t2, &z3)
+	y3.Add(&t0, &t2)
+	z3.Mul(&t1, &z3)
+	t1.Add(&t2, &t2)
+	t2.Add(&t1, &t2)
+	t0.Sub(&t0, &t2)
+	y3.Mul(&t0, &y3)
+	y3.Add(&x3, &y3)
+	t1.Mul(x, y)
+	x3.Mul(&t0, &t1)
+	x3.Add(&x3, &x3)
+
+	P.x, P.y, P.z = x3, y3, z3
+}
+
+// cmov sets P = Q when b is 1 and leaves P unchanged when b is 0.
+func (P *G2) cmov(Q *G2, b int) {
+	P.x.CMov(&P.x, &Q.x, b)
+	P.y.CMov(&P.y, &Q.y, b)
+	P.z.CMov(&P.z, &Q.z, b)
+}
+
+// ScalarMult sets P = k*Q in constant time with a double-and-always-add
+// ladder over the bits of k.
+func (P *G2) ScalarMult(k *ff.Scalar, Q *G2) {
+	var acc, t G2
+	acc.SetIdentity()
+	for i := ff.ScalarBits - 1; i >= 0; i-- {
+		acc.Double(&acc)
+		t.Add(&acc, Q)
+		acc.cmov(&t, int(k.Bit(i)))
+	}
+	*P = acc
+}
+
+// IsOnG2 reports whether P lies on the twist and in the prime-order
+// subgroup.
+func (P *G2) IsOnG2() bool { return P.isOnCurve() && P.isRTorsion() }
+
+func (P *G2) isOnCurve() bool {
+	var l, r, t ff.Fp2
+	l.Sqr(&P.y)
+	l.Mul(&l, &P.z)
+	r.Sqr(&P.x)
+	r.Mul(&r, &P.x)
+	t.Sqr(&P.z)
+	t.Mul(&t, &P.z)
+	t.Mul(&t, &g2Params.b)
+	r.Add(&r, &t)
+
+	return l.IsEqual(&r) == 1
+}
+
+func (P *G2) isRTorsion() bool {
+	var acc, t G2
+	acc.SetIdentity()
+	for i := 4*64 - 1; i >= 0; i-- {
+		acc.Double(&acc)
+		if primeOrder[i/64]>>(uint(i)%64)&1 == 1 {
+			t.Add(&acc, P)
+			acc = t
+		}
+	}
+
+	return acc.IsIdentity()
+}
+
+// toAffine scales the coordinates so that z is one; the identity is left
+// untouched.
+func (P *G2) toAffine() {
+	if P.IsIdentity() {
+		return
+	}
+	var zInv ff.Fp2
+	zInv.Inv(&P.z)
+	P.x.Mul(&P.x, &zInv)
+	P.y.Mul(&P.y, &zInv)
+	P.z.SetOne()
+}
+
+// Bytes returns the 192-byte uncompressed encoding of P.
+func (P *G2) Bytes() []byte {
+	out := make([]byte, G2Size)
+	if P.IsIdentity() {
+		out[0] = headerInfinity
+		return out
+	}
+	Q := *P
+	Q.toAffine()
+	copy(out[:ff.Fp2Size], Q.x.Bytes())
+	copy(out[ff.Fp2Size:], Q.y.Bytes())
+
+	return out
+}
+
+// BytesCompressed returns the 96-byte compressed encoding of P.
+func (P *G2) BytesCompressed() []byte {
+	out := make([]byte, G2SizeCompressed)
+	if P.IsIdentity() {
+		out[0] = headerCompressed | headerInfinity
+		return out
+	}
+	Q := *P
+	Q.toAffine()
+	copy(out, Q.x.Bytes())
+	out[0] |= headerCompressed
+	if Q.y.IsNegative() == 1 {
+		out[0] |= headerBigY
+	}
+
+	return out
+}
+
+// SetBytes reconstructs a point from either encoding, verifying that it
+// lies on the twist. Subgroup membership is a separate check, IsOnG2.
+func (P *G2) SetBytes(data []byte) error {
+	if len(data) != G2Size && len(data) != G2SizeCompressed {
+		return errors.New("bls12381: invalid G2 encoding length")
+	}
+	header := data[0] & headerMask
+	compressed := header&headerCompressed != 0
+	if compressed != (len(data) == G2SizeCompressed) {
+		return errors.New("bls12381: G2 compression flag contradicts length")
+	}
+
+	if header&headerInfinity != 0 {
+		for i, b := range data {
+			if i == 0 {
+				b &^= headerMask
+			}
+			if b != 0 {
+				return errors.New("bls12381: non-canonical G2 identity encoding")
+			}
+		}
+		if header&headerBigY != 0 {
+			return errors.New("bls12381: non-canonical G2 identity encoding")
+		}
+		P.SetIdentity()
+
+		return nil
+	}
+
+	xBytes := make([]byte, ff.Fp2Size)
+	copy(xBytes, data[:ff.Fp2Size])
+	xBytes[0] &^= headerMask
+	var Q G2
+	if err := Q.x.SetBytes(xBytes); err != nil {
+		return err
+	}
+	Q.z.SetOne()
+
+	if !compressed {
+		if header&headerBigY != 0 {
+			return errors.New("bls12381: sign flag set on uncompressed G2 point")
+		}
+		if err := Q.y.SetBytes(data[ff.Fp2Size:]); err != nil {
+			return err
+		}
+		if !Q.isOnCurve() {
+			return errors.New("bls12381: G2 point not on twist")
+		}
+		*P = Q
+
+		return nil
+	}
+
+	var y2 ff.Fp2
+	y2.Sqr(&Q.x)
+	y2.Mul(&y2, &Q.x)
+	y2.Add(&y2, &g2Params.b)
+	if Q.y.Sqrt(&y2) == 0 {
+		return errors.New("bls12381: G2 point not on twist")
+	}
+	wantBig := 0
+	if header&headerBigY != 0 {
+		wantBig = 1
+	}
+	if Q.y.IsNegative() != wantBig {
+		Q.y.Neg(&Q.y)
+	}
+	*P = Q
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package bls12381
+
+import (
+	"math/big"
+
+	"github.com/cloudflare/circl/ecc/bls12381/ff"
+	"github.com/cloudflare/circl/math/tower"
+)
+
+// The extension tower of the pairing: Fp12 = Fp6[w]/(w^2 - v) over
+// Fp6 = Fp2[v]/(v^3 - (1+u)), built with math/tower on top of ff.Fp2.
+
+// fp2Algebra adapts ff.Fp2 to tower.Algebra. Its distinguished
+// non-residue is 1+u, the defining constant of the cubic floor.
+type fp2Algebra struct{}
+
+func (fp2Algebra) Set(z, x *ff.Fp2)          { *z = *x }
+func (fp2Algebra) SetZero(z *ff.Fp2)         { *z = ff.Fp2{} }
+func (fp2Algebra) SetOne(z *ff.Fp2)          { z.SetOne() }
+func (fp2Algebra) IsZero(x *ff.Fp2) bool     { return x.IsZero() == 1 }
+func (fp2Algebra) IsEqual(x, y *ff.Fp2) bool { return x.IsEqual(y) == 1 }
+func (fp2Algebra) Add(z, x, y *ff.Fp2)       { z.Add(x, y) }
+func (fp2Algebra) Sub(z, x, y *ff.Fp2)       { z.Sub(x, y) }
+func (fp2Algebra) Neg(z, x *ff.Fp2)          { z.Neg(x) }
+func (fp2Algebra) Mul(z, x, y *ff.Fp2)       { z.Mul(x, y) }
+func (fp2Algebra) Sqr(z, x *ff.Fp2)          { z.Sqr(x) }
+func (fp2Algebra) Inv(z, x *ff.Fp2)          { z.Inv(x) }
+
+// MulByNonResidue multiplies by 1+u: (a+bu)(1+u) = (a-b) + (a+b)u.
+func (fp2Algebra) MulByNonResidue(z, x *ff.Fp2) {
+	var t ff.Fp2
+	t.C0.Sub(&x.C0, &x.C1)
+	t.C1.Add(&x.C0, &x.C1)
+	*z = t
+}
+
+type (
+	fp6  = tower.E3[ff.Fp2]
+	fp12 = tower.E2[fp6]
+)
+
+var (
+	ext6  = tower.NewExt3[ff.Fp2](fp2Algebra{})
+	ext12 = tower.NewExt2[fp6](ext6, nil)
+)
+
+// pairX is the absolute value of the BLS curve parameter; the parameter
+// itself is -pairX, accounted for by a conjugation after the Miller
+// loop.
+const pairX = uint64(0xd201000000010000)
+
+// The final exponentiation splits (p^12 - 1)/r into the easy part
+// (p^6 - 1)(p^2 + 1), done with a conjugation, one inversion, and the
+// p^2-power Frobenius, and the hard part (p^4 - p^2 + 1)/r, done by a
+// generic square-and-multiply with pairHardExp. The Frobenius needs the
+// constants below, powers of the non-residue xi = 1+u computed at
+// startup; all exponents are public.
+var (
+	pairHardExp *big.Int
+	frob2       struct{ g1, g2, h, hg1, hg2 ff.Fp2 }
+)
+
+func init() {
+	p, _ := new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	r, _ := new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+	p2 := new(big.Int).Mul(p, p)
+	e := new(big.Int).Mul(p2, p2)
+	e.Sub(e, p2)
+	e.Add(e, big.NewInt(1))
+	e.Div(e, r)
+	pairHardExp = e
+
+	// xi^((p^2-1)/3) scales the v coordinates under the Frobenius and
+	// xi^((p^2-1)/6) the w one; products cover the mixed terms.
+	p2m1 := new(big.Int).Sub(p2, big.NewInt(1))
+	var xi ff.Fp2
+	xi.SetOne()
+	xi.C1.SetOne()
+	frob2.g1 = fp2PowVarTime(&xi, new(big.Int).Div(p2m1, big.NewInt(3)))
+	frob2.h = fp2PowVarTime(&xi, new(big.Int).Div(p2m1, big.NewInt(6)))
+	frob2.g2.Sqr(&frob2.g1)
+	frob2.hg1.Mul(&frob2.h, &frob2.g1)
+	frob2.hg2.Mul(&frob2.h, &frob2.g2)
+}
+
+// fp2PowVarTime raises x to the public big-integer exponent e.
+func fp2PowVarTime(x *ff.Fp2, e *big.Int) (z ff.Fp2) {
+	z.SetOne()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		z.Sqr(&z)
+		if e.Bit(i) == 1 {
+			z.Mul(&z, x)
+		}
+	}
+
+	return
+}
+
+// frobenius2 applies the p^2-power Frobenius to f, which fixes the Fp2
+// coordinates and scales each basis element by a power of xi.
+func frobenius2(z, f *fp12) {
+	z.A.A = f.A.A
+	z.A.B.Mul(&f.A.B, &frob2.g1)
+	z.A.C.Mul(&f.A.C, &frob2.g2)
+	z.B.A.Mul(&f.B.A, &frob2.h)
+	z.B.B.Mul(&f.B.B, &frob2.hg1)
+	z.B.C.Mul(&f.B.C, &frob2.hg2)
+}
+
+// Gt is an element of the target group of the pairing, the subgroup of
+// order r of the multiplicative group of Fp12.
+type Gt struct{ v fp12 }
+
+// SetIdentity sets z to the identity.
+func (z *Gt) SetIdentity() { ext12.SetOne(&z.v) }
+
+// IsEqual reports whether z and x are the same element.
+func (z *Gt) IsEqual(x *Gt) bool { return ext12.IsEqual(&z.v, &x.v) }
+
+// Mul sets z = x * y.
+func (z *Gt) Mul(x, y *Gt) { ext12.Mul(&z.v, &x.v, &y.v) }
+
+// fp12CMov sets z = x when b is 1 and leaves z unchanged when b is 0.
+func fp12CMov(z, x *fp12, b int) {
+	z.A.A.CMov(&z.A.A, &x.A.A, b)
+	z.A.B.CMov(&z.A.B, &x.A.B, b)
+	z.A.C.CMov(&z.A.C, &x.A.C, b)
+	z.B.A.CMov(&z.B.A, &x.B.A, b)
+	z.B.B.CMov(&z.B.B, &x.B.B, b)
+	z.B.C.CMov(&z.B.C, &x.B.C, b)
+}
+
+// Exp sets z = x^k in constant time with a square-and-always-multiply
+// ladder over the bits of k.
+func (z *Gt) Exp(x *Gt, k *ff.Scalar) {
+	var acc, t Gt
+	acc.SetIdentity()
+	for i := ff.ScalarBits - 1; i >= 0; i-- {
+		ext12.Sqr(&acc.v, &acc.v)
+		ext12.Mul(&t.v, &acc.v, &x.v)
+		fp12CMov(&acc.v, &t.v, int(k.Bit(i)))
+	}
+	*z = acc
+}
+
+// Bytes returns the canonical 576-byte encoding of z: the two Fp6
+// coefficients in order, each as its Fp2 coordinates by increasing
+// power of v.
+func (z *Gt) Bytes() []byte {
+	out := make([]byte, 0, 6*ff.Fp2Size)
+	for _, c := range []*ff.Fp2{&z.v.A.A, &z.v.A.B, &z.v.A.C, &z.v.B.A, &z.v.B.B, &z.v.B.C} {
+		out = append(out, c.Bytes()...)
+	}
+
+	return out
+}
+
+// IsIdentity reports whether z is the identity.
+func (z *Gt) IsIdentity() bool {
+	var one fp12
+	ext12.SetOne(&one)
+
+	return ext12.IsEqual(&z.v, &one)
+}
+
+// embedFp returns a base field element as an element of Fp12.
+func embedFp(x *ff.Fp) (z fp12) {
+	z.A.A.C0 = *x
+
+	return
+}
+
+// untwist maps an affine point of the twist into E(Fp12) by
+// (x', y') -> (x'/w^2, y'/w^3). With w^2 = v and v^3 = 1+u this puts
+// x'/(1+u) in the v^2 slot and y'/(1+u) in the v*w slot.
+func untwist(x2, y2 *ff.Fp2) (x, y fp12) {
+	// (1+u)^-1 = (1-u)/2.
+	var xiInv ff.Fp2
+	xiInv.SetOne()
+	xiInv.C1.SetOne()
+	xiInv.Inv(&xiInv)
+
+	x.A.C.Mul(x2, &xiInv)
+	y.B.B.Mul(y2, &xiInv)
+
+	return
+}
+
+// lineDouble advances T to 2T and returns the tangent line at the old T
+// evaluated at (xP, yP). Vertical lines are dropped: their values lie
+// in Fp6, which the final exponentiation kills.
+func lineDouble(tx, ty, xP, yP *fp12) (l fp12) {
+	var lam, t fp12
+	ext12.Sqr(&lam, tx)
+	ext12.Add(&t, &lam, &lam)
+	ext12.Add(&lam, &lam, &t) // 3*tx^2
+	ext12.Add(&t, ty, ty)
+	ext12.Inv(&t, &t)
+	ext12.Mul(&lam, &lam, &t) // slope of the tangent
+
+	var x3, y3 fp12
+	ext12.Sqr(&x3, &lam)
+	ext12.Sub(&x3, &x3, tx)
+	ext12.Sub(&x3, &x3, tx)
+	ext12.Sub(&y3, tx, &x3)
+	ext12.Mul(&y3, &y3, &lam)
+	ext12.Sub(&y3, &y3, ty)
+
+	ext12.Sub(&l, xP, tx)
+	ext12.Mul(&l, &l, &lam)
+	ext12.Sub(&t, yP, ty)
+	ext12.Sub(&l, &t, &l)
+
+	*tx, *ty = x3, y3
+
+	return
+}
+
+// lineAdd advances T to T+Q and returns the chord through the old T and
+// Q evaluated at (xP, yP).
+func lineAdd(tx, ty, xQ, yQ, xP, yP *fp12) (l fp12) {
+	var lam, t fp12
+	ext12.Sub(&lam, yQ, ty)
+	ext12.Sub(&t, xQ, tx)
+	ext12.Inv(&t, &t)
+	ext12.Mul(&lam, &lam, &t) // slope of the chord
+
+	var x3, y3 fp12
+	ext12.Sqr(&x3, &lam)
+	ext12.Sub(&x3, &x3, tx)
+	ext12.Sub(&x3, &x3, xQ)
+	ext12.Sub(&y3, tx, &x3)
+	ext12.Mul(&y3, &y3, &lam)
+	ext12.Sub(&y3, &y3, ty)
+
+	ext12.Sub(&l, xP, tx)
+	ext12.Mul(&l, &l, &lam)
+	ext12.Sub(&t, yP, ty)
+	ext12.Sub(&l, &t, &l)
+
+	*tx, *ty = x3, y3
+
+	return
+}
+
+// Pair computes the reduced ate-style pairing e(P, Q) with a Miller
+// loop over the curve parameter followed by the full final
+// exponentiation. Either input being the identity gives the identity of
+// Gt.
+func Pair(P *G1, Q *G2) *Gt {
+	var out Gt
+	if P.IsIdentity() || Q.IsIdentity() {
+		ext12.SetOne(&out.v)
+		return &out
+	}
+
+	aP, aQ := *P, *Q
+	aP.toAffine()
+	aQ.toAffine()
+	xP, yP := embedFp(&aP.x), embedFp(&aP.y)
+	xQ, yQ := untwist(&aQ.x, &aQ.y)
+
+	var f fp12
+	ext12.SetOne(&f)
+	tx, ty := xQ, yQ
+	for i := 62; i >= 0; i-- { // bit 63 of pairX is the implicit first one
+		ext12.Sqr(&f, &f)
+		l := lineDouble(&tx, &ty, &xP, &yP)
+		ext12.Mul(&f, &f, &l)
+		if pairX>>uint(i)&1 == 1 {
+			l := lineAdd(&tx, &ty, &xQ, &yQ, &xP, &yP)
+			ext12.Mul(&f, &f, &l)
+		}
+	}
+
+	// The curve parameter is negative; conjugation inverts f up to
+	// factors the final exponentiation kills.
+	ext6.Neg(&f.B, &f.B)
+
+	// Easy part: f^((p^6-1)(p^2+1)). The p^6-power is the conjugation.
+	var c, t fp12
+	c = f
+	ext6.Neg(&c.B, &c.B)
+	ext12.Inv(&t, &f)
+	ext12.Mul(&f, &c, &t)
+	frobenius2(&c, &f)
+	ext12.Mul(&f, &c, &f)
+
+	// Hard part: the remaining exponent (p^4-p^2+1)/r.
+	ext12.SetOne(&out.v)
+	for i := pairHardExp.BitLen() - 1; i >= 0; i-- {
+		ext12.Sqr(&out.v, &out.v)
+		if pairHardExp.Bit(i) == 1 {
+			ext12.Mul(&out.v, &out.v, &f)
+		}
+	}
+
+	return &out
+}
@@ -0,0 +1,343 @@
+// Package decaf448 implements the prime-order group decaf448 specified
+// in RFC 9496.
+//
+// The group quotients the cofactor out of edwards448 (Goldilocks):
+// encodings are canonical 56-byte strings, equality is well defined,
+// and protocols built on top need no cofactor handling. It offers the
+// 224-bit security level, complementing ecc/ristretto255 at 128 bits;
+// the two packages expose the same API. Elements support the group
+// operations, a map from uniform bytes (RFC 9496, Section 5.3.4), and
+// hashing with domain separation; scalars are integers modulo the
+// prime group order. All operations on secret data run in constant
+// time.
+package decaf448
+
+import (
+	"crypto"
+	_ "crypto/sha512" // linked for crypto.SHA512
+	"errors"
+
+	"github.com/cloudflare/circl/expander"
+	fp "github.com/cloudflare/circl/math/fp448"
+)
+
+// Sizes of the byte encodings used by this package.
+const (
+	// SizeElement is the length of an encoded element.
+	SizeElement = 56
+	// SizeScalar is the length of an encoded scalar.
+	SizeScalar = 56
+)
+
+// ErrInvalidElement is returned when bytes are not the canonical
+// encoding of a group element.
+var ErrInvalidElement = errors.New("decaf448: invalid element encoding")
+
+// Derived constants of RFC 9496, Section 5.1, set in init: the square
+// root of -d and its inverse, 1-d, and 1-2d.
+var sqrtMinusD, invSqrtMinusD, oneMinusD, oneMinusTwoD fp.Elt
+
+func init() {
+	var one, md fp.Elt
+	fp.SetOne(&one)
+	fp.Neg(&md, &paramD) // 39081
+	sqrtRatio(&sqrtMinusD, &md, &one)
+	sqrtRatio(&invSqrtMinusD, &one, &md)
+	fp.Sub(&oneMinusD, &one, &paramD)
+	fp.Sub(&oneMinusTwoD, &oneMinusD, &paramD)
+}
+
+// Element is a group element; the zero value is not valid, use the Set
+// methods or Decode to initialize one.
+type Element struct {
+	p edPoint
+}
+
+// SetIdentity sets e to the neutral element.
+func (e *Element) SetIdentity() *Element {
+	e.p.setIdentity()
+
+	return e
+}
+
+// SetGenerator sets e to the canonical generator.
+func (e *Element) SetGenerator() *Element {
+	e.p.setGenerator()
+
+	return e
+}
+
+// Add sets e = a + b.
+func (e *Element) Add(a, b *Element) *Element {
+	e.p.add(&a.p, &b.p)
+
+	return e
+}
+
+// Double sets e = a + a.
+func (e *Element) Double(a *Element) *Element {
+	e.p.double(&a.p)
+
+	return e
+}
+
+// Neg sets e = -a.
+func (e *Element) Neg(a *Element) *Element {
+	e.p.neg(&a.p)
+
+	return e
+}
+
+// ScalarMult sets e = [k]a, in constant time.
+func (e *Element) ScalarMult(k *Scalar, a *Element) *Element {
+	e.p.scalarMult(k, &a.p)
+
+	return e
+}
+
+// ScalarBaseMult sets e = [k]G for the canonical generator G, in
+// constant time.
+func (e *Element) ScalarBaseMult(k *Scalar) *Element {
+	var g edPoint
+	g.setGenerator()
+	e.p.scalarMult(k, &g)
+
+	return e
+}
+
+// IsEqual reports whether e and a are the same group element, in
+// constant time. Representatives differing by torsion compare equal.
+func (e *Element) IsEqual(a *Element) bool {
+	var l, r fp.Elt
+	fp.Mul(&l, &e.p.x, &a.p.y)
+	fp.Mul(&r, &e.p.y, &a.p.x)
+	fp.Sub(&l, &l, &r)
+
+	return fpCtIsZero(&l) == 1
+}
+
+// IsIdentity reports whether e is the neutral element.
+func (e *Element) IsIdentity() bool { return fpCtIsZero(&e.p.x) == 1 }
+
+// Encode returns the canonical encoding of e (RFC 9496, Section 5.3.2).
+func (e *Element) Encode() (b [SizeElement]byte) {
+	var one, u1, u2, t, invsqrt fp.Elt
+	fp.SetOne(&one)
+	fp.Add(&u1, &e.p.x, &e.p.t)
+	fp.Sub(&t, &e.p.x, &e.p.t)
+	fp.Mul(&u1, &u1, &t) // u1 = (x0+t0)*(x0-t0)
+
+	var x2 fp.Elt
+	fp.Sqr(&x2, &e.p.x)
+	fp.Mul(&t, &u1, &oneMinusD)
+	fp.Mul(&t, &t, &x2)
+	sqrtRatio(&invsqrt, &one, &t)
+
+	var ratio fp.Elt
+	fp.Mul(&ratio, &invsqrt, &u1)
+	fp.Mul(&ratio, &ratio, &sqrtMinusD)
+	fpAbs(&ratio)
+
+	fp.Mul(&u2, &ratio, &e.p.z)
+	fp.Mul(&u2, &u2, &invSqrtMinusD)
+	fp.Sub(&u2, &u2, &e.p.t)
+
+	var s fp.Elt
+	fp.Mul(&s, &oneMinusD, &invsqrt)
+	fp.Mul(&s, &s, &e.p.x)
+	fp.Mul(&s, &s, &u2)
+	fpAbs(&s)
+	fp.Modp(&s)
+
+	return s
+}
+
+// Decode sets e from its canonical encoding (RFC 9496, Section 5.3.1),
+// returning ErrInvalidElement for any string that Encode cannot output.
+func (e *Element) Decode(b *[SizeElement]byte) error {
+	var s fp.Elt
+	if !fpFromBytes(&s, b) || fpIsNegative(&s) == 1 {
+		return ErrInvalidElement
+	}
+
+	var one, ss, u1, u2, t fp.Elt
+	fp.SetOne(&one)
+	fp.Sqr(&ss, &s)
+	fp.Add(&u1, &one, &ss)
+	fp.Sqr(&u2, &u1)
+	fp.Mul(&t, &paramD, &ss)
+	fp.Add(&t, &t, &t)
+	fp.Add(&t, &t, &t)
+	fp.Sub(&u2, &u2, &t) // u2 = u1^2 - 4*d*ss
+
+	var invsqrt fp.Elt
+	fp.Sqr(&t, &u1)
+	fp.Mul(&t, &t, &u2)
+	wasSquare := sqrtRatio(&invsqrt, &one, &t)
+
+	var u3 fp.Elt
+	fp.Add(&u3, &s, &s)
+	fp.Mul(&u3, &u3, &invsqrt)
+	fp.Mul(&u3, &u3, &u1)
+	fp.Mul(&u3, &u3, &sqrtMinusD)
+	fpAbs(&u3)
+
+	var P edPoint
+	fp.Mul(&P.x, &u3, &invsqrt)
+	fp.Mul(&P.x, &P.x, &u2)
+	fp.Mul(&P.x, &P.x, &invSqrtMinusD)
+	fp.Sub(&P.y, &one, &ss)
+	fp.Mul(&P.y, &P.y, &invsqrt)
+	fp.Mul(&P.y, &P.y, &u1)
+	fp.SetOne(&P.z)
+	fp.Mul(&P.t, &P.x, &P.y)
+
+	if wasSquare == 0 {
+		return ErrInvalidElement
+	}
+	e.p = P
+
+	return nil
+}
+
+// FromUniformBytes sets e from 112 uniform bytes by mapping each half
+// through the encoding of RFC 9496, Section 5.3.4, and adding the
+// results; the output is uniform when the input is.
+func (e *Element) FromUniformBytes(b *[2 * SizeElement]byte) *Element {
+	var r0, r1 fp.Elt
+	copy(r0[:], b[:SizeElement])
+	copy(r1[:], b[SizeElement:])
+	fp.Modp(&r0)
+	fp.Modp(&r1)
+
+	var P0, P1 edPoint
+	mapToEdwards(&P0, &r0)
+	mapToEdwards(&P1, &r1)
+	e.p.add(&P0, &P1)
+
+	return e
+}
+
+// HashToElement hashes msg into the group with domain separation tag
+// dst, indifferentiable from a random oracle.
+func HashToElement(msg, dst []byte) *Element {
+	exp := expander.NewExpanderMD(crypto.SHA512, dst)
+	var b [2 * SizeElement]byte
+	copy(b[:], exp.Expand(msg, 2*SizeElement))
+
+	return new(Element).FromUniformBytes(&b)
+}
+
+// HashToScalar hashes msg to a uniform scalar with domain separation
+// tag dst.
+func HashToScalar(msg, dst []byte) *Scalar {
+	exp := expander.NewExpanderMD(crypto.SHA512, dst)
+	var b [2 * SizeScalar]byte
+	copy(b[:], exp.Expand(msg, 2*SizeScalar))
+
+	return new(Scalar).SetUniformBytes(&b)
+}
+
+// mapToEdwards is the MAP function of RFC 9496, Section 5.3.4, sending
+// a field element to a curve point, in constant time.
+func mapToEdwards(P *edPoint, t *fp.Elt) {
+	var one, r, u0, u1, tv fp.Elt
+	fp.SetOne(&one)
+	fp.Sqr(&r, t)
+	fp.Neg(&r, &r) // r = -t^2
+	fp.Sub(&u0, &r, &one)
+	fp.Mul(&u0, &u0, &paramD) // u0 = d*(r-1)
+	fp.Add(&u1, &u0, &one)
+	fp.Sub(&tv, &u0, &r)
+	fp.Mul(&u1, &u1, &tv) // u1 = (u0+1)*(u0-r)
+
+	var v, rp1 fp.Elt
+	fp.Add(&rp1, &r, &one)
+	fp.Mul(&tv, &rp1, &u1)
+	wasSquare := sqrtRatio(&v, &oneMinusTwoD, &tv)
+
+	var vPrime, sgn fp.Elt
+	fp.Mul(&vPrime, t, &v)
+	fp.Cmov(&vPrime, &v, wasSquare)
+	fp.SetOne(&sgn)
+	var nOne fp.Elt
+	fp.Neg(&nOne, &sgn)
+	fp.Cmov(&nOne, &sgn, wasSquare)
+	sgn = nOne
+
+	var s fp.Elt
+	fp.Mul(&s, &vPrime, &rp1)
+
+	var w0, w1, w2, w3, s2 fp.Elt
+	w0 = s
+	fpAbs(&w0)
+	fp.Add(&w0, &w0, &w0)
+	fp.Sqr(&s2, &s)
+	fp.Add(&w1, &s2, &one)
+	fp.Sub(&w2, &s2, &one)
+	fp.Sub(&tv, &r, &one)
+	fp.Mul(&w3, &vPrime, &s)
+	fp.Mul(&w3, &w3, &tv)
+	fp.Mul(&w3, &w3, &oneMinusTwoD)
+	fp.Add(&w3, &w3, &sgn)
+
+	fp.Mul(&P.x, &w0, &w3)
+	fp.Mul(&P.y, &w2, &w1)
+	fp.Mul(&P.z, &w1, &w3)
+	fp.Mul(&P.t, &w0, &w2)
+}
+
+// sqrtRatio is the SQRT_RATIO_M1 function of RFC 9496, Section 5.2: it
+// sets z to the nonnegative root of u/v when u/v is square, returning
+// 1, and to the nonnegative root of -u/v otherwise, returning 0.
+func sqrtRatio(z, u, v *fp.Elt) uint {
+	ok := fp.SqrtRatio(z, u, v)
+	fpAbs(z)
+	// A zero divisor with nonzero numerator has no root of either kind.
+	ok &^= fpCtIsZero(v) &^ fpCtIsZero(u)
+
+	return ok
+}
+
+// fpCtIsZero returns 1 when x represents zero, in constant time.
+func fpCtIsZero(x *fp.Elt) uint {
+	t := *x
+	fp.Modp(&t)
+	var v byte
+	for _, b := range t {
+		v |= b
+	}
+
+	return uint((uint32(v) - 1) >> 31)
+}
+
+// fpIsNegative returns the sign bit of x, the parity of its canonical
+// encoding.
+func fpIsNegative(x *fp.Elt) uint {
+	t := *x
+	fp.Modp(&t)
+
+	return uint(t[0] & 1)
+}
+
+// fpAbs replaces z by its negative when z is negative, in constant
+// time.
+func fpAbs(z *fp.Elt) {
+	var n fp.Elt
+	fp.Neg(&n, z)
+	fp.Cmov(z, &n, fpIsNegative(z))
+}
+
+// fpFromBytes loads a canonical field element, rejecting values of p or
+// more.
+func fpFromBytes(z *fp.Elt, b *[fp.Size]byte) bool {
+	t := *(*fp.Elt)(b)
+	u := t
+	fp.Modp(&u)
+	if u != t {
+		return false
+	}
+	*z = t
+
+	return true
+}
@@ -0,0 +1,160 @@
+package decaf448_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/ecc/decaf448"
+)
+
+func randomScalar(t *testing.T) *decaf448.Scalar {
+	t.Helper()
+	var buf [2 * decaf448.SizeScalar]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		t.Fatal(err)
+	}
+	return new(decaf448.Scalar).SetUniformBytes(&buf)
+}
+
+// Small multiples of the generator, from RFC 9496, Appendix A.1.
+var generatorMultiples = []string{
+	"00000000000000000000000000000000000000000000000000000000" +
+		"00000000000000000000000000000000000000000000000000000000",
+	"66666666666666666666666666666666666666666666666666666666" +
+		"33333333333333333333333333333333333333333333333333333333",
+	"c898eb4f87f97c564c6fd61fc7e49689314a1f818ec85eeb3bd5514a" +
+		"c816d38778f69ef347a89fca817e66defdedce178c7cc709b2116e75",
+	"a0c09bf2ba7208fda0f4bfe3d0f5b29a543012306d43831b5adc6fe7" +
+		"f8596fa308763db15468323b11cf6e4aeb8c18fe44678f44545a69bc",
+}
+
+func TestGeneratorMultiples(t *testing.T) {
+	var g, acc decaf448.Element
+	g.SetGenerator()
+	acc.SetIdentity()
+	for i, want := range generatorMultiples {
+		enc := acc.Encode()
+		if hex.EncodeToString(enc[:]) != want {
+			t.Errorf("%d*B mismatch\ngot:  %x\nwant: %s", i, enc, want)
+		}
+
+		var dec decaf448.Element
+		if err := dec.Decode(&enc); err != nil {
+			t.Errorf("%d*B does not decode: %v", i, err)
+		} else if !dec.IsEqual(&acc) {
+			t.Errorf("%d*B does not roundtrip", i)
+		}
+		acc.Add(&acc, &g)
+	}
+}
+
+func TestInvalidEncodings(t *testing.T) {
+	// The field prime, a non-canonical encoding of zero.
+	var p [decaf448.SizeElement]byte
+	for i := range p {
+		p[i] = 0xff
+	}
+	p[28] = 0xfe
+	var e decaf448.Element
+	if e.Decode(&p) == nil {
+		t.Error("non-canonical encoding accepted")
+	}
+
+	// s = 1 is not on any even-torsion-free coset.
+	var one [decaf448.SizeElement]byte
+	one[0] = 1
+	if e.Decode(&one) == nil {
+		t.Error("invalid coset encoding accepted")
+	}
+
+	// A negative s component.
+	var g decaf448.Element
+	g.SetGenerator()
+	enc := g.Encode()
+	enc[decaf448.SizeElement-1] |= 0x80
+	if e.Decode(&enc) == nil {
+		t.Error("negative encoding accepted")
+	}
+}
+
+func TestGroupLaws(t *testing.T) {
+	k := randomScalar(t)
+	l := randomScalar(t)
+
+	var p, q, r decaf448.Element
+	p.ScalarBaseMult(k)
+
+	// ScalarMult by the generator agrees with ScalarBaseMult.
+	q.SetGenerator()
+	q.ScalarMult(k, &q)
+	if !q.IsEqual(&p) {
+		t.Fatal("ScalarMult and ScalarBaseMult disagree")
+	}
+
+	// Doubling is adding to itself.
+	q.Double(&p)
+	r.Add(&p, &p)
+	if !q.IsEqual(&r) {
+		t.Fatal("Double and Add disagree")
+	}
+
+	// P - P is the identity.
+	q.Neg(&p)
+	r.Add(&p, &q)
+	if !r.IsIdentity() {
+		t.Fatal("P - P is not the identity")
+	}
+
+	// [k+l]B == [k]B + [l]B.
+	var sum decaf448.Scalar
+	sum.Add(k, l)
+	q.ScalarBaseMult(&sum)
+	var lB decaf448.Element
+	lB.ScalarBaseMult(l)
+	r.Add(&p, &lB)
+	if !q.IsEqual(&r) {
+		t.Fatal("scalar addition does not distribute")
+	}
+}
+
+func TestHashToElement(t *testing.T) {
+	h1 := decaf448.HashToElement([]byte("msg"), []byte("dst"))
+	h2 := decaf448.HashToElement([]byte("msg"), []byte("dst"))
+	if !h1.IsEqual(h2) {
+		t.Fatal("HashToElement is not deterministic")
+	}
+	h3 := decaf448.HashToElement([]byte("msg"), []byte("other dst"))
+	if h1.IsEqual(h3) {
+		t.Fatal("domain separation is ignored")
+	}
+
+	s1 := decaf448.HashToScalar([]byte("msg"), []byte("dst"))
+	s2 := decaf448.HashToScalar([]byte("msg"), []byte("dst"))
+	if !s1.IsEqual(s2) {
+		t.Fatal("HashToScalar is not deterministic")
+	}
+}
+
+func TestScalar(t *testing.T) {
+	k := randomScalar(t)
+	var ki, one decaf448.Scalar
+	ki.Inv(k)
+	ki.Mul(&ki, k)
+	one.SetUint64(1)
+	if !ki.IsEqual(&one) {
+		t.Fatal("k * k^-1 is not one")
+	}
+
+	enc, err := k.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var k2 decaf448.Scalar
+	if err = k2.UnmarshalBinary(enc); err != nil {
+		t.Fatal(err)
+	}
+	if !k2.IsEqual(k) {
+		t.Fatal("scalar does not roundtrip")
+	}
+}
@@ -0,0 +1,143 @@
+package decaf448
+
+// Internal arithmetic on edwards448 (Goldilocks), x^2 + y^2 =
+// 1 + d*x^2*y^2 with d = -39081, in extended coordinates (X:Y:Z:T)
+// with X*Y = Z*T. Since a = 1 is a square and d is not, the addition
+// formulas are complete, and scalar multiplication is a plain
+// fixed-window loop whose table starts at the identity.
+
+import fp "github.com/cloudflare/circl/math/fp448"
+
+// edPoint is a curve point in extended coordinates.
+type edPoint struct {
+	x, y, z, t fp.Elt
+}
+
+// paramD is the curve coefficient d = -39081, built here rather than
+// in init so that every other initializer can depend on it.
+var paramD = func() fp.Elt {
+	var c, d fp.Elt
+	c[0], c[1] = 0xa9, 0x98 // 39081
+	fp.Neg(&d, &c)
+
+	return d
+}()
+
+// Generator coordinates, twice the edwards448 base point of RFC 7748,
+// whose decaf448 encoding is the generator string of RFC 9496.
+var (
+	genX = fp.Elt{
+		0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55,
+		0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55,
+		0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55,
+		0x55, 0x55, 0x55, 0x55, 0xa9, 0xaa, 0xaa, 0xaa,
+		0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa,
+		0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa,
+		0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa,
+	}
+	genY = fp.Elt{
+		0xed, 0x86, 0x93, 0xea, 0xcd, 0xfb, 0xea, 0xda,
+		0x6b, 0xa0, 0xcd, 0xd1, 0xbe, 0xb2, 0xbc, 0xbb,
+		0x98, 0x30, 0x2a, 0x3a, 0x83, 0x65, 0x65, 0x0d,
+		0xb8, 0xc4, 0xd8, 0x8a, 0x72, 0x6d, 0xe3, 0xb7,
+		0xd7, 0x4d, 0x88, 0x35, 0xa0, 0xd7, 0x6e, 0x03,
+		0xb0, 0xc2, 0x86, 0x50, 0x20, 0xd6, 0x59, 0xb3,
+		0x8d, 0x04, 0xd7, 0x4a, 0x63, 0xe9, 0x05, 0xae,
+	}
+)
+
+func (P *edPoint) setIdentity() {
+	*P = edPoint{}
+	fp.SetOne(&P.y)
+	fp.SetOne(&P.z)
+}
+
+func (P *edPoint) setGenerator() {
+	P.x = genX
+	P.y = genY
+	fp.SetOne(&P.z)
+	fp.Mul(&P.t, &P.x, &P.y)
+}
+
+// add sets P = Q + R (add-2008-hwcd, complete for a = 1).
+func (P *edPoint) add(Q, R *edPoint) {
+	var a, b, c, d, e, f, g, h fp.Elt
+	fp.Mul(&a, &Q.x, &R.x)
+	fp.Mul(&b, &Q.y, &R.y)
+	fp.Mul(&c, &Q.t, &R.t)
+	fp.Mul(&c, &c, &paramD) // C = d*T1*T2
+	fp.Mul(&d, &Q.z, &R.z)
+	fp.Add(&e, &Q.x, &Q.y)
+	fp.Add(&f, &R.x, &R.y)
+	fp.Mul(&e, &e, &f)
+	fp.Sub(&e, &e, &a)
+	fp.Sub(&e, &e, &b) // E = (X1+Y1)*(X2+Y2)-A-B
+	fp.Sub(&f, &d, &c)
+	fp.Add(&g, &d, &c)
+	fp.Sub(&h, &b, &a) // H = B - a*A
+	fp.Mul(&P.x, &e, &f)
+	fp.Mul(&P.y, &g, &h)
+	fp.Mul(&P.t, &e, &h)
+	fp.Mul(&P.z, &f, &g)
+}
+
+// double sets P = 2*Q (dbl-2008-hwcd, complete for a = 1).
+func (P *edPoint) double(Q *edPoint) {
+	var a, b, c, e, f, g, h fp.Elt
+	fp.Sqr(&a, &Q.x)
+	fp.Sqr(&b, &Q.y)
+	fp.Sqr(&c, &Q.z)
+	fp.Add(&c, &c, &c)
+	fp.Add(&e, &Q.x, &Q.y)
+	fp.Sqr(&e, &e)
+	fp.Sub(&e, &e, &a)
+	fp.Sub(&e, &e, &b)
+	fp.Add(&g, &a, &b) // G = a*A + B
+	fp.Sub(&f, &g, &c)
+	fp.Sub(&h, &a, &b) // H = a*A - B
+	fp.Mul(&P.x, &e, &f)
+	fp.Mul(&P.y, &g, &h)
+	fp.Mul(&P.t, &e, &h)
+	fp.Mul(&P.z, &f, &g)
+}
+
+// neg sets P = -Q.
+func (P *edPoint) neg(Q *edPoint) {
+	*P = *Q
+	fp.Neg(&P.x, &Q.x)
+	fp.Neg(&P.t, &Q.t)
+}
+
+// cmov copies Q into P when c is 1, in constant time.
+func (P *edPoint) cmov(Q *edPoint, c uint) {
+	fp.Cmov(&P.x, &Q.x, c)
+	fp.Cmov(&P.y, &Q.y, c)
+	fp.Cmov(&P.z, &Q.z, c)
+	fp.Cmov(&P.t, &Q.t, c)
+}
+
+// scalarMult sets P = [k]Q by a 4-bit fixed-window loop; the table
+// includes the identity so a zero window needs no special case.
+func (P *edPoint) scalarMult(k *Scalar, Q *edPoint) {
+	var tab [16]edPoint
+	tab[0].setIdentity()
+	tab[1] = *Q
+	for i := 2; i < 16; i++ {
+		tab[i].add(&tab[i-1], Q)
+	}
+
+	var R, T edPoint
+	R.setIdentity()
+	for i := 111; i >= 0; i-- {
+		for j := 0; j < 4; j++ {
+			R.double(&R)
+		}
+		d := uint(k.w[i/16]>>(4*(i%16))) & 0xf
+		for j := range tab {
+			c := uint((uint64(uint(j)^d) - 1) >> 63)
+			T.cmov(&tab[j], c)
+		}
+		R.add(&R, &T)
+	}
+	*P = R
+}
@@ -0,0 +1,58 @@
+package decaf448
+
+// Standard codec interfaces over the fixed-size encodings: MarshalBinary
+// and UnmarshalBinary wrap Encode and Decode, and the text forms are
+// base64 via internal/conv, so elements and scalars embed directly in
+// JSON. Decoding stays strict: only canonical encodings are accepted.
+
+import "github.com/cloudflare/circl/internal/conv"
+
+// MarshalBinary returns the canonical encoding of e.
+func (e *Element) MarshalBinary() ([]byte, error) {
+	b := e.Encode()
+
+	return b[:], nil
+}
+
+// UnmarshalBinary sets e from its canonical encoding, returning
+// ErrInvalidElement for anything else.
+func (e *Element) UnmarshalBinary(data []byte) error {
+	if len(data) != SizeElement {
+		return ErrInvalidElement
+	}
+	var b [SizeElement]byte
+	copy(b[:], data)
+
+	return e.Decode(&b)
+}
+
+// MarshalText encodes e in base64.
+func (e *Element) MarshalText() ([]byte, error) { return conv.MarshalText(e) }
+
+// UnmarshalText decodes an element produced by MarshalText.
+func (e *Element) UnmarshalText(text []byte) error { return conv.UnmarshalText(e, text) }
+
+// MarshalBinary returns the canonical encoding of s.
+func (s *Scalar) MarshalBinary() ([]byte, error) {
+	b := s.Encode()
+
+	return b[:], nil
+}
+
+// UnmarshalBinary sets s from its canonical encoding, returning
+// ErrInvalidScalar for anything else.
+func (s *Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != SizeScalar {
+		return ErrInvalidScalar
+	}
+	var b [SizeScalar]byte
+	copy(b[:], data)
+
+	return s.Decode(&b)
+}
+
+// MarshalText encodes s in base64.
+func (s *Scalar) MarshalText() ([]byte, error) { return conv.MarshalText(s) }
+
+// UnmarshalText decodes a scalar produced by MarshalText.
+func (s *Scalar) UnmarshalText(text []byte) error { return conv.UnmarshalText(s, text) }
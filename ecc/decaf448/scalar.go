@@ -0,0 +1,226 @@
+package decaf448
+
+// Arithmetic modulo the group order, the 446-bit prime l =
+// 2^446 - 13818066809895115352007386748515426880336692474882178609894547503885.
+// The layout mirrors ecc/ristretto255: canonical little-endian limbs,
+// Montgomery multiplication with the domain conversion folded into a
+// second multiplication by R^2, and little-endian byte encodings.
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// ErrInvalidScalar is returned when bytes do not encode a canonical
+// scalar.
+var ErrInvalidScalar = errors.New("decaf448: invalid scalar encoding")
+
+// orderLimbs is the group order l in little-endian limbs.
+var orderLimbs = [7]uint64{
+	0x2378c292ab5844f3, 0x216cc2728dc58f55,
+	0xc44edb49aed63690, 0xffffffff7cca23e9,
+	0xffffffffffffffff, 0xffffffffffffffff,
+	0x3fffffffffffffff,
+}
+
+// Montgomery constants for R = 2^448, derived in init.
+var (
+	ordInv uint64
+	ordRR  [7]uint64
+)
+
+func init() {
+	inv := orderLimbs[0]
+	for i := 0; i < 5; i++ {
+		inv *= 2 - orderLimbs[0]*inv
+	}
+	ordInv = -inv
+
+	ordRR[0] = 1
+	for i := 0; i < 896; i++ {
+		var c uint64
+		for j := range ordRR {
+			ordRR[j], c = bits.Add64(ordRR[j], ordRR[j], c)
+		}
+		scCondSub(&ordRR, c)
+	}
+}
+
+// Scalar is an integer modulo the group order, always canonical.
+type Scalar struct {
+	w [7]uint64
+}
+
+// scCondSub subtracts l when the value is at least l, or unconditionally
+// when the carry flag of a preceding addition is set, in constant time.
+func scCondSub(w *[7]uint64, carry uint64) {
+	var d [7]uint64
+	var b uint64
+	for i := range w {
+		d[i], b = bits.Sub64(w[i], orderLimbs[i], b)
+	}
+	m := (b &^ carry) - 1
+	for i := range w {
+		w[i] = w[i]&^m | d[i]&m
+	}
+}
+
+// SetUint64 sets s to v.
+func (s *Scalar) SetUint64(v uint64) *Scalar {
+	s.w = [7]uint64{v}
+
+	return s
+}
+
+// IsZero reports whether s is zero.
+func (s *Scalar) IsZero() bool { return s.w == [7]uint64{} }
+
+// IsEqual reports whether s and a are the same scalar.
+func (s *Scalar) IsEqual(a *Scalar) bool { return s.w == a.w }
+
+// Add sets s = a + b.
+func (s *Scalar) Add(a, b *Scalar) *Scalar {
+	var c uint64
+	for i := range s.w {
+		s.w[i], c = bits.Add64(a.w[i], b.w[i], c)
+	}
+	scCondSub(&s.w, c)
+
+	return s
+}
+
+// Sub sets s = a - b.
+func (s *Scalar) Sub(a, b *Scalar) *Scalar {
+	var bo uint64
+	for i := range s.w {
+		s.w[i], bo = bits.Sub64(a.w[i], b.w[i], bo)
+	}
+	m := -bo
+	var c uint64
+	for i := range s.w {
+		s.w[i], c = bits.Add64(s.w[i], orderLimbs[i]&m, c)
+	}
+
+	return s
+}
+
+// Neg sets s = -a.
+func (s *Scalar) Neg(a *Scalar) *Scalar {
+	var z Scalar
+
+	return s.Sub(&z, a)
+}
+
+// Mul sets s = a * b.
+func (s *Scalar) Mul(a, b *Scalar) *Scalar {
+	var t [7]uint64
+	montMul(&t, &a.w, &b.w)
+	montMul(&s.w, &t, &ordRR)
+
+	return s
+}
+
+// Sqr sets s = a^2.
+func (s *Scalar) Sqr(a *Scalar) *Scalar { return s.Mul(a, a) }
+
+// Inv sets s = 1/a for nonzero a, and to zero otherwise, by Fermat's
+// little theorem.
+func (s *Scalar) Inv(a *Scalar) *Scalar {
+	e := orderLimbs
+	e[0] -= 2
+	x := *a
+	var acc Scalar
+	acc.SetUint64(1)
+	for i := 445; i >= 0; i-- {
+		acc.Sqr(&acc)
+		if e[i/64]>>(i%64)&1 == 1 {
+			acc.Mul(&acc, &x)
+		}
+	}
+	*s = acc
+
+	return s
+}
+
+// montMul computes z = x*y*R^-1 mod l by coarsely integrated operand
+// scanning, in constant time.
+func montMul(z, x, y *[7]uint64) {
+	var t [9]uint64
+	for i := 0; i < 7; i++ {
+		var c, c1 uint64
+		for j := 0; j < 7; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			lo, c1 = bits.Add64(lo, t[j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, c, 0)
+			hi += c1
+			t[j] = lo
+			c = hi
+		}
+		t[7], c = bits.Add64(t[7], c, 0)
+		t[8] = c
+
+		m := t[0] * ordInv
+		c = 0
+		for j := 0; j < 7; j++ {
+			hi, lo := bits.Mul64(m, orderLimbs[j])
+			lo, c1 = bits.Add64(lo, t[j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, c, 0)
+			hi += c1
+			t[j] = lo
+			c = hi
+		}
+		t[7], c = bits.Add64(t[7], c, 0)
+		t[8] += c
+		copy(t[:8], t[1:])
+		t[8] = 0
+	}
+	copy(z[:], t[:7])
+	scCondSub(z, t[7])
+}
+
+// SetUniformBytes sets s to the 112 little-endian bytes of b reduced
+// modulo l, suitable for deriving scalars from hash output.
+func (s *Scalar) SetUniformBytes(b *[2 * SizeScalar]byte) *Scalar {
+	var lo, hi [7]uint64
+	for i := 0; i < SizeScalar; i++ {
+		lo[i/8] |= uint64(b[i]) << (8 * (i % 8))
+		hi[i/8] |= uint64(b[SizeScalar+i]) << (8 * (i % 8))
+	}
+	var a, t Scalar
+	montMul(&a.w, &hi, &ordRR)
+	montMul(&t.w, &lo, &ordRR)
+	one := [7]uint64{1}
+	montMul(&t.w, &t.w, &one)
+
+	return s.Add(&a, &t)
+}
+
+// Encode returns the canonical little-endian encoding of s.
+func (s *Scalar) Encode() (b [SizeScalar]byte) {
+	for i := 0; i < SizeScalar; i++ {
+		b[i] = byte(s.w[i/8] >> (8 * (i % 8)))
+	}
+
+	return b
+}
+
+// Decode sets s from its canonical little-endian encoding, returning
+// ErrInvalidScalar for values of l or more.
+func (s *Scalar) Decode(b *[SizeScalar]byte) error {
+	var w [7]uint64
+	for i := 0; i < SizeScalar; i++ {
+		w[i/8] |= uint64(b[i]) << (8 * (i % 8))
+	}
+	var bo uint64
+	for i := range w {
+		_, bo = bits.Sub64(w[i], orderLimbs[i], bo)
+	}
+	if bo == 0 {
+		return ErrInvalidScalar
+	}
+	s.w = w
+
+	return nil
+}
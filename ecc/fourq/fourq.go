@@ -0,0 +1,29 @@
+// Package fourq provides the FourQ curve, the twisted Edwards curve
+// -x^2 + y^2 = 1 + d*x^2*y^2 over GF(p^2) with p = 2^127 - 1, offering
+// hashing of arbitrary messages onto its prime-order subgroup.
+//
+// Reference: Costello and Longa, "FourQ: four-dimensional
+// decompositions on a Q-curve over the Mersenne prime".
+package fourq
+
+// Size is the length in bytes of an encoded field element, two
+// 16-byte coordinates of GF(p^2).
+const Size = 32
+
+// Point is an affine point on the curve. It is not guaranteed to lie in
+// the prime-order subgroup.
+type Point struct{ X, Y Fq }
+
+// IsOnCurve reports whether P satisfies the curve equation.
+func (P *Point) IsOnCurve() bool {
+	var one, x2, y2, l, r Fq
+	fqSetOne(&one)
+	fqSqr(&x2, &P.X)
+	fqSqr(&y2, &P.Y)
+	fqSub(&l, &y2, &x2) // -x^2 + y^2
+	fqMul(&r, &x2, &y2)
+	fqMul(&r, &r, &paramD)
+	fqAdd(&r, &r, &one)
+
+	return fqEq(&l, &r) == 1
+}
@@ -0,0 +1,164 @@
+package fourq
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+var pBig = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+
+func fpToInt(x *Fp) *big.Int {
+	var be [16]byte
+	for i := range be {
+		be[i] = x[15-i]
+	}
+
+	return new(big.Int).SetBytes(be[:])
+}
+
+func fpFromInt(z *Fp, v *big.Int) {
+	var be [16]byte
+	new(big.Int).Mod(v, pBig).FillBytes(be[:])
+	for i := range be {
+		z[i] = be[15-i]
+	}
+}
+
+func randomFq(t *testing.T) (x Fq) {
+	t.Helper()
+	r, err := rand.Int(rand.Reader, pBig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fpFromInt(&x[0], r)
+	r, err = rand.Int(rand.Reader, pBig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fpFromInt(&x[1], r)
+
+	return
+}
+
+// TestFqArithmetic cross-checks the packed field arithmetic against a
+// big.Int model of GF(p^2).
+func TestFqArithmetic(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		x, y := randomFq(t), randomFq(t)
+		a, b := fpToInt(&x[0]), fpToInt(&x[1])
+		c, d := fpToInt(&y[0]), fpToInt(&y[1])
+
+		var got, want Fq
+		fqAdd(&got, &x, &y)
+		fpFromInt(&want[0], new(big.Int).Add(a, c))
+		fpFromInt(&want[1], new(big.Int).Add(b, d))
+		if got != want {
+			t.Fatal("fqAdd mismatch")
+		}
+
+		fqSub(&got, &x, &y)
+		fpFromInt(&want[0], new(big.Int).Sub(a, c))
+		fpFromInt(&want[1], new(big.Int).Sub(b, d))
+		if got != want {
+			t.Fatal("fqSub mismatch")
+		}
+
+		fqMul(&got, &x, &y)
+		re := new(big.Int).Sub(new(big.Int).Mul(a, c), new(big.Int).Mul(b, d))
+		im := new(big.Int).Add(new(big.Int).Mul(a, d), new(big.Int).Mul(b, c))
+		fpFromInt(&want[0], re)
+		fpFromInt(&want[1], im)
+		if got != want {
+			t.Fatal("fqMul mismatch")
+		}
+
+		var inv, one, prod Fq
+		fqInv(&inv, &x)
+		fqMul(&prod, &x, &inv)
+		fqSetOne(&one)
+		if fqEq(&prod, &one) != 1 {
+			t.Fatal("x * x^-1 != 1")
+		}
+	}
+
+	var zero, inv Fq
+	fqInv(&inv, &zero)
+	if !fqIsZero(&inv) {
+		t.Fatal("inverse of zero is not zero")
+	}
+}
+
+func TestSqrtFq(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		x := randomFq(t)
+		var sq, r, back Fq
+		fqSqr(&sq, &x)
+		if sqrtFq(&r, &sq) != 1 {
+			t.Fatal("square not recognized")
+		}
+		fqSqr(&back, &r)
+		if fqEq(&back, &sq) != 1 {
+			t.Fatal("sqrtFq returned a wrong root")
+		}
+	}
+	var t1 Fq
+	if sqrtFq(&t1, &mapZ) == 1 {
+		t.Fatal("the map's non-square has a root")
+	}
+}
+
+// scalarMultN multiplies by the order of the prime subgroup,
+// N = (#E)/392, with a plain double-and-add; test use only.
+func scalarMultN(P *Point) {
+	nBytes := [...]byte{ // big-endian 246-bit prime order
+		0x00, 0x29, 0xcb, 0xc1, 0x4e, 0x5e, 0x0a, 0x72,
+		0xf0, 0x53, 0x97, 0x82, 0x9c, 0xbc, 0x14, 0xe5,
+		0xdf, 0xbd, 0x00, 0x4d, 0xfe, 0x0f, 0x79, 0x99,
+		0x2f, 0xb2, 0x54, 0x0e, 0xc7, 0x76, 0x8c, 0xe7,
+	}
+	var acc Point
+	fqSetOne(&acc.Y)
+	for _, b := range nBytes {
+		for j := 7; j >= 0; j-- {
+			edwardsAdd(&acc, &acc, &acc)
+			if b>>uint(j)&1 == 1 {
+				edwardsAdd(&acc, &acc, P)
+			}
+		}
+	}
+	*P = acc
+}
+
+func TestHashToCurve(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-FourQ_XMD:SHA-512_ELL2_RO_")
+	for _, msg := range []string{"", "abc", "abcdef0123456789"} {
+		P := HashToCurve([]byte(msg), dst)
+		if !P.IsOnCurve() {
+			t.Fatalf("hash of %q not on the curve", msg)
+		}
+		Q := *P
+		scalarMultN(&Q)
+		var one Fq
+		fqSetOne(&one)
+		if !fqIsZero(&Q.X) || fqEq(&Q.Y, &one) != 1 {
+			t.Fatalf("hash of %q not in the prime-order subgroup", msg)
+		}
+
+		R := HashToCurve([]byte(msg), dst)
+		if fqEq(&P.X, &R.X) != 1 || fqEq(&P.Y, &R.Y) != 1 {
+			t.Fatal("hashing is not deterministic")
+		}
+
+		E := EncodeToCurve([]byte(msg), dst)
+		if !E.IsOnCurve() {
+			t.Fatalf("encoding of %q not on the curve", msg)
+		}
+	}
+
+	P := HashToCurve([]byte("x"), dst)
+	Q := HashToCurve([]byte("y"), dst)
+	if fqEq(&P.X, &Q.X) == 1 {
+		t.Fatal("distinct messages hashed to the same point")
+	}
+}
@@ -0,0 +1,177 @@
+package fourq
+
+import "math/bits"
+
+// Arithmetic of the base field GF(p) and its quadratic extension
+// GF(p^2) = GF(p)[i]/(i^2 + 1) for the Mersenne prime p = 2^127 - 1.
+// Elements are stored as canonical little-endian bytes and computed on
+// as pairs of 64-bit words; reductions exploit 2^127 = 1 mod p by
+// folding the top bits back into the bottom. All operations run in
+// constant time.
+
+// Fp is an element of GF(p) in canonical little-endian form.
+type Fp [16]byte
+
+// Fq is an element a + b*i of GF(p^2), coordinates real then imaginary.
+type Fq [2]Fp
+
+// mask127 masks the top word of a 127-bit value.
+const mask127 = uint64(1)<<63 - 1
+
+// fpWords unpacks an element into little-endian words.
+func fpWords(x *Fp) (w [2]uint64) {
+	for i := 0; i < 16; i++ {
+		w[i/8] |= uint64(x[i]) << (8 * (i % 8))
+	}
+
+	return
+}
+
+// fpStore packs little-endian words, which must be canonical.
+func fpStore(z *Fp, w [2]uint64) {
+	for i := 0; i < 16; i++ {
+		z[i] = byte(w[i/8] >> (8 * (i % 8)))
+	}
+}
+
+// fpReduce brings a value below 2^128 to its canonical form by folding
+// the top bit and subtracting p once, in constant time.
+func fpReduce(w [2]uint64) [2]uint64 {
+	var cc uint64
+	for i := 0; i < 2; i++ {
+		t := w[1] >> 63
+		w[1] &= mask127
+		w[0], cc = bits.Add64(w[0], t, 0)
+		w[1] += cc
+	}
+	d0, bb := bits.Sub64(w[0], ^uint64(0), 0)
+	d1, bb := bits.Sub64(w[1], mask127, bb)
+	m := bb - 1
+	w[0] = w[0]&^m | d0&m
+	w[1] = w[1]&^m | d1&m
+
+	return w
+}
+
+func fpAddW(x, y [2]uint64) [2]uint64 {
+	var c uint64
+	x[0], c = bits.Add64(x[0], y[0], 0)
+	x[1], _ = bits.Add64(x[1], y[1], c)
+
+	return fpReduce(x)
+}
+
+func fpSubW(x, y [2]uint64) [2]uint64 {
+	var b uint64
+	x[0], b = bits.Sub64(x[0], y[0], 0)
+	x[1], b = bits.Sub64(x[1], y[1], b)
+	// On borrow the wrapped value is off by 2^128 = 2 mod p.
+	m := -b
+	var c uint64
+	x[0], c = bits.Sub64(x[0], 2&m, 0)
+	x[1], b = bits.Sub64(x[1], 0, c)
+	x[0], c = bits.Sub64(x[0], 2&(-b), 0)
+	x[1] -= c
+
+	return fpReduce(x)
+}
+
+// fpMulW multiplies two canonical values with a 128x128 schoolbook
+// product and a Mersenne fold of the high half.
+func fpMulW(x, y [2]uint64) [2]uint64 {
+	h0, l0 := bits.Mul64(x[0], y[0])
+	h1, l1 := bits.Mul64(x[0], y[1])
+	h2, l2 := bits.Mul64(x[1], y[0])
+	h3, l3 := bits.Mul64(x[1], y[1])
+
+	p0 := l0
+	var c uint64
+	p1, c := bits.Add64(h0, l1, 0)
+	p2, c := bits.Add64(h1, l3, c)
+	p3 := h3 + c
+	p1, c = bits.Add64(p1, l2, 0)
+	p2, c = bits.Add64(p2, h2, c)
+	p3 += c
+
+	// product = low 127 bits + 2^127 * rest; fold with 2^127 = 1.
+	a := [2]uint64{p0, p1 & mask127}
+	b := [2]uint64{p1>>63 | p2<<1, p2>>63 | p3<<1}
+	top := p3 >> 63 // the 2^128 = 2 overflow bit of the shifted rest
+
+	var s [2]uint64
+	s[0], c = bits.Add64(a[0], b[0], 0)
+	s[1], c = bits.Add64(a[1], b[1], c)
+	s[0], c = bits.Add64(s[0], 2*top+2*c, 0)
+	s[1] += c
+
+	return fpReduce(s)
+}
+
+// fpInvW computes the inverse x^(p-2) = x^(2^127-3), zero for zero. The
+// exponent has every bit set except bit one.
+func fpInvW(x [2]uint64) [2]uint64 {
+	acc := x
+	for i := 125; i >= 0; i-- {
+		acc = fpMulW(acc, acc)
+		if i != 1 {
+			acc = fpMulW(acc, x)
+		}
+	}
+
+	return acc
+}
+
+func fqSetOne(z *Fq) {
+	*z = Fq{}
+	z[0][0] = 1
+}
+
+func fqAdd(z, x, y *Fq) {
+	fpStore(&z[0], fpAddW(fpWords(&x[0]), fpWords(&y[0])))
+	fpStore(&z[1], fpAddW(fpWords(&x[1]), fpWords(&y[1])))
+}
+
+func fqSub(z, x, y *Fq) {
+	fpStore(&z[0], fpSubW(fpWords(&x[0]), fpWords(&y[0])))
+	fpStore(&z[1], fpSubW(fpWords(&x[1]), fpWords(&y[1])))
+}
+
+func fqNeg(z, x *Fq) {
+	var zero [2]uint64
+	fpStore(&z[0], fpSubW(zero, fpWords(&x[0])))
+	fpStore(&z[1], fpSubW(zero, fpWords(&x[1])))
+}
+
+func fqMul(z, x, y *Fq) {
+	a, b := fpWords(&x[0]), fpWords(&x[1])
+	c, d := fpWords(&y[0]), fpWords(&y[1])
+	re := fpSubW(fpMulW(a, c), fpMulW(b, d))
+	im := fpAddW(fpMulW(a, d), fpMulW(b, c))
+	fpStore(&z[0], re)
+	fpStore(&z[1], im)
+}
+
+func fqSqr(z, x *Fq) { fqMul(z, x, x) }
+
+// fqInv computes the inverse by the norm, (a - b*i)/(a^2 + b^2), which
+// is zero for the zero input.
+func fqInv(z, x *Fq) {
+	a, b := fpWords(&x[0]), fpWords(&x[1])
+	n := fpAddW(fpMulW(a, a), fpMulW(b, b))
+	n = fpInvW(n)
+	var zero [2]uint64
+	fpStore(&z[0], fpMulW(a, n))
+	fpStore(&z[1], fpMulW(fpSubW(zero, b), n))
+}
+
+func fqIsZero(x *Fq) bool { return *x == Fq{} }
+
+// fqCmov sets z = x when the low bit of c is one, in constant time.
+func fqCmov(z, x *Fq, c uint) {
+	m := byte(0) - byte(c&1)
+	for i := range z {
+		for j := range z[i] {
+			z[i][j] = z[i][j]&^m | x[i][j]&m
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package fourq
+
+// This file implements hashing to the FourQ prime-order subgroup in the
+// style of RFC 9380 (FourQ has no registered suite): hash_to_field over
+// GF(p^2), an Elligator 2 encoding evaluated through the Montgomery
+// curve birationally equivalent to the twisted Edwards form, and a
+// multiplication by the cofactor 392. HashToCurve is indifferentiable
+// from a random oracle onto the subgroup; EncodeToCurve covers only a
+// fraction of it, which suffices for protocols that need any hashed
+// generator. Both run in constant time with respect to msg.
+//
+// Square roots in GF(p^2) reduce to chains of squarings, since the
+// relevant exponents for the Mersenne prime p = 2^127-1 are powers of
+// two; every candidate root is verified by squaring, so non-squares are
+// detected without a separate Legendre computation.
+
+import (
+	"crypto"
+	_ "crypto/sha512" // linked for crypto.SHA512
+	"math/bits"
+
+	"github.com/cloudflare/circl/expander"
+)
+
+// hashToFieldSize is the number of uniform bytes drawn per GF(p)
+// component.
+const hashToFieldSize = 32
+
+// paramD is the curve coefficient d of -x^2 + y^2 = 1 + d*x^2*y^2.
+var paramD = Fq{
+	Fp{
+		0x42, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xe4, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	},
+	Fp{
+		0x8d, 0x0c, 0xfc, 0xf1, 0x88, 0x14, 0x82, 0xb3,
+		0xfc, 0xe0, 0x57, 0x66, 0x84, 0x2f, 0x47, 0x5e,
+	},
+}
+
+// Montgomery-form constants of the Elligator map, derived from d in
+// init: the curve is B*v^2 = u^3 + A*u^2 + u with A = 2(a+d)/(a-d) and
+// B = 4/(a-d) for a = -1, and Z is a fixed non-square of GF(p^2).
+var mapA, mapBinv, mapZ Fq
+
+func init() {
+	var one, two, num, den Fq
+	fqSetOne(&one)
+	fqAdd(&two, &one, &one)
+	fqSub(&num, &one, &paramD) // -(a+d) = 1-d
+	fqAdd(&den, &one, &paramD) // -(a-d) = 1+d
+	fqInv(&den, &den)
+	fqMul(&mapA, &num, &den)
+	fqAdd(&mapA, &mapA, &mapA) // A = 2(1-d)/(1+d)
+	fqMul(&mapBinv, &den, &two)
+	fqAdd(&mapBinv, &mapBinv, &mapBinv)
+	fqNeg(&mapBinv, &mapBinv)
+	fqInv(&mapBinv, &mapBinv) // 1/B = -(1+d)/4
+
+	// The smallest non-square of the form c + i.
+	var t Fq
+	fqSetOne(&mapZ)
+	mapZ[1][0] = 1
+	for sqrtFq(&t, &mapZ) == 1 {
+		mapZ[0][0]++
+	}
+}
+
+// HashToCurve hashes msg into the prime-order subgroup, using dst as
+// the domain separation tag; outputs are indifferentiable from uniform.
+func HashToCurve(msg, dst []byte) *Point {
+	var r [2]Fq
+	hashToFq(r[:], msg, dst)
+	P := mapToCurve(&r[0])
+	Q := mapToCurve(&r[1])
+	edwardsAdd(P, P, Q)
+	clearCofactor(P)
+
+	return P
+}
+
+// EncodeToCurve hashes msg into the prime-order subgroup with a single
+// map evaluation; outputs cover only part of the subgroup.
+func EncodeToCurve(msg, dst []byte) *Point {
+	var r [1]Fq
+	hashToFq(r[:], msg, dst)
+	P := mapToCurve(&r[0])
+	clearCofactor(P)
+
+	return P
+}
+
+// hashToFq fills out with uniform field elements derived from msg, two
+// components of hashToFieldSize uniform bytes each.
+func hashToFq(out []Fq, msg, dst []byte) {
+	exp := expander.NewExpanderMD(crypto.SHA512, dst)
+	n := uint(len(out)) * 2 * hashToFieldSize
+	u := exp.Expand(msg, n)
+	for i := range out {
+		for j := 0; j < 2; j++ {
+			fpFromHash(&out[i][j], u[:hashToFieldSize])
+			u = u[hashToFieldSize:]
+		}
+	}
+}
+
+// fpFromHash reduces hashToFieldSize big-endian bytes modulo the
+// Mersenne prime, folding 127-bit chunks in constant time.
+func fpFromHash(z *Fp, b []byte) {
+	var w [4]uint64
+	for i := 0; i < 32; i++ {
+		w[i/8] |= uint64(b[31-i]) << (8 * (i % 8))
+	}
+	// value = c0 + c1*2^127 + c2*2^254, each chunk below 2^127.
+	const top = uint64(1) << 63
+	s := [2]uint64{w[0], w[1] &^ top}
+	c1 := [2]uint64{w[1]>>63 | w[2]<<1, (w[2]>>63 | w[3]<<1) &^ top}
+	c2 := [2]uint64{w[3] >> 62, 0}
+
+	var cc uint64
+	s[0], cc = bits.Add64(s[0], c1[0], 0)
+	s[1], _ = bits.Add64(s[1], c1[1], cc)
+	s[0], cc = bits.Add64(s[0], c2[0], 0)
+	s[1], _ = bits.Add64(s[1], c2[1], cc)
+
+	// Two folds of the bits at and above 2^127 bring the sum below
+	// 2^127, and one conditional subtraction makes it canonical.
+	for i := 0; i < 2; i++ {
+		t := s[1] >> 63
+		s[1] &^= top
+		s[0], cc = bits.Add64(s[0], t, 0)
+		s[1] += cc
+	}
+	d0, bb := bits.Sub64(s[0], ^uint64(0), 0)
+	d1, bb := bits.Sub64(s[1], top-1, bb)
+	m := bb - 1
+	s[0] = s[0]&^m | d0&m
+	s[1] = s[1]&^m | d1&m
+
+	for i := 0; i < 16; i++ {
+		z[i] = byte(s[i/8] >> (8 * (i % 8)))
+	}
+}
+
+// pow2k raises x to the power 2^k by repeated squaring.
+func pow2k(z, x *Fq, k uint) {
+	*z = *x
+	for i := uint(0); i < k; i++ {
+		fqSqr(z, z)
+	}
+}
+
+// fqEq returns 1 when x equals y, in constant time.
+func fqEq(x, y *Fq) uint {
+	var d Fq
+	fqSub(&d, x, y)
+	if fqIsZero(&d) {
+		return 1
+	}
+
+	return 0
+}
+
+// sqrtFq computes z with z^2 = u when u is a square, returning 1, and
+// returns 0 otherwise. The complex method needs only squaring chains:
+// sqrt exponents modulo p = 2^127-1 are (p+1)/4 = 2^125. Every
+// candidate is validated by squaring, which also covers the purely real
+// and purely imaginary corner cases.
+func sqrtFq(z *Fq, u *Fq) uint {
+	a := Fq{u[0], Fp{}}
+	b := Fq{u[1], Fp{}}
+	half := Fq{}
+	half[0][15] = 0x40 // (p+1)/2 = 2^126
+
+	var n, s, t1, t2, x1, x2, x, t Fq
+	fqSqr(&n, &a)
+	fqSqr(&t1, &b)
+	fqAdd(&n, &n, &t1) // norm(u)
+	pow2k(&s, &n, 125)
+
+	fqAdd(&t1, &a, &s)
+	fqMul(&t1, &t1, &half)
+	fqSub(&t2, &a, &s)
+	fqMul(&t2, &t2, &half)
+	pow2k(&x1, &t1, 125)
+	pow2k(&x2, &t2, 125)
+	fqSqr(&t, &x1)
+	c := fqEq(&t, &t1)
+	x = x2
+	fqCmov(&x, &x1, c)
+
+	var y, xi Fq
+	fqAdd(&y, &x, &x)
+	fqInv(&y, &y)
+	fqMul(&y, &y, &b) // y = b/(2x), zero when x is zero
+
+	cand := Fq{x[0], y[0]}
+	fqSqr(&t, &cand)
+	ok := fqEq(&t, u)
+
+	// Purely imaginary root, for u real and non-square in GF(p).
+	fqNeg(&t1, &a)
+	pow2k(&xi, &t1, 125)
+	cand2 := Fq{Fp{}, xi[0]}
+	fqSqr(&t, &cand2)
+	ok2 := fqEq(&t, u)
+	fqCmov(&cand, &cand2, (1-ok)&ok2)
+
+	*z = cand
+
+	return ok | ok2
+}
+
+// mapToCurve is Elligator 2 through the Montgomery form, followed by
+// the rational map to twisted Edwards coordinates. It is exception-free
+// and constant time; the output is on the curve but generally not in
+// the prime-order subgroup.
+func mapToCurve(r *Fq) *Point {
+	var one, r2, den, u1, u2, g1, g2, w1, w2, v1, v2, t Fq
+	fqSetOne(&one)
+	fqSqr(&r2, r)
+	fqMul(&r2, &r2, &mapZ)
+	fqAdd(&den, &one, &r2)
+	fqCmov(&den, &one, fqEq(&den, &Fq{})) // exceptional r: use u1 = -A
+	fqInv(&den, &den)
+	fqMul(&u1, &mapA, &den)
+	fqNeg(&u1, &u1)
+	fqNeg(&u2, &u1)
+	fqSub(&u2, &u2, &mapA) // u2 = -u1 - A
+
+	g := func(g, u *Fq) {
+		var s Fq
+		fqAdd(&s, u, &mapA)
+		fqMul(&s, &s, u)
+		fqAdd(&s, &s, &one)
+		fqMul(g, &s, u) // u^3 + A*u^2 + u
+	}
+	g(&g1, &u1)
+	g(&g2, &u2)
+	fqMul(&w1, &g1, &mapBinv)
+	fqMul(&w2, &g2, &mapBinv)
+	ok := sqrtFq(&v1, &w1)
+	sqrtFq(&v2, &w2)
+
+	u, v := u2, v2
+	fqCmov(&u, &u1, ok)
+	fqCmov(&v, &v1, ok)
+
+	// (u,v) -> (x,y) = (u/v, (u-1)/(u+1)); the exceptional points map
+	// into the cofactor and are sent to (0,1).
+	var P Point
+	fqInv(&t, &v)
+	fqMul(&P.X, &u, &t)
+	fqAdd(&t, &u, &one)
+	e := fqEq(&t, &Fq{})
+	fqCmov(&t, &one, e)
+	fqInv(&t, &t)
+	fqSub(&P.Y, &u, &one)
+	fqMul(&P.Y, &P.Y, &t)
+	fqCmov(&P.X, &Fq{}, e)
+	fqCmov(&P.Y, &one, e)
+	zv := fqEq(&v, &Fq{})
+	fqCmov(&P.X, &Fq{}, zv)
+
+	return &P
+}
+
+// edwardsAdd sets P = Q + R with the unified affine formulas, complete
+// on this curve since a = -1 is a square and d is not.
+func edwardsAdd(P, Q, R *Point) {
+	var one, xx, yy, xy, yx, dxy, n1, n2, d1, d2 Fq
+	fqSetOne(&one)
+	fqMul(&xx, &Q.X, &R.X)
+	fqMul(&yy, &Q.Y, &R.Y)
+	fqMul(&xy, &Q.X, &R.Y)
+	fqMul(&yx, &Q.Y, &R.X)
+	fqMul(&dxy, &xx, &yy)
+	fqMul(&dxy, &dxy, &paramD)
+
+	fqAdd(&n1, &xy, &yx)
+	fqAdd(&d1, &one, &dxy)
+	fqInv(&d1, &d1)
+	fqAdd(&n2, &yy, &xx) // y1y2 - a*x1x2, a = -1
+	fqSub(&d2, &one, &dxy)
+	fqInv(&d2, &d2)
+	fqMul(&P.X, &n1, &d1)
+	fqMul(&P.Y, &n2, &d2)
+}
+
+// clearCofactor multiplies P by the cofactor 392, moving it into the
+// prime-order subgroup.
+func clearCofactor(P *Point) {
+	// 392 = 0b110001000, processed by double-and-add.
+	R := *P
+	for _, b := range [8]byte{1, 0, 0, 0, 1, 0, 0, 0} {
+		edwardsAdd(&R, &R, &R)
+		if b == 1 {
+			edwardsAdd(&R, &R, P)
+		}
+	}
+	*P = R
+}
@@ -0,0 +1,32 @@
+package p384
+
+// This file converts batches of Jacobian points to affine coordinates
+// with a single shared field inversion (Montgomery's trick, provided by
+// fp384.InvBatch), instead of one inversion per point. Precomputed
+// tables become cheap to normalize, and their entries then enter the
+// group law through the faster mixed addition.
+
+import fp "github.com/cloudflare/circl/math/fp384"
+
+// batchToAffine returns the affine form of every point, performing one
+// field inversion for the whole slice. Identity points come out as
+// (0,0). The points are treated as public: the batch inversion skips
+// zero denominators with a branch.
+func batchToAffine(ps []jacobianPoint) []affinePoint {
+	zs := make([]fp.Elt, len(ps))
+	for i := range ps {
+		zs[i] = ps[i].z
+	}
+	fp.InvBatch(zs)
+
+	out := make([]affinePoint, len(ps))
+	for i := range ps {
+		var zi2, zi3 fp.Elt
+		fp.Sqr(&zi2, &zs[i])
+		fp.Mul(&zi3, &zi2, &zs[i])
+		fp.Mul(&out[i].x, &ps[i].x, &zi2)
+		fp.Mul(&out[i].y, &ps[i].y, &zi3)
+	}
+
+	return out
+}
@@ -0,0 +1,102 @@
+package p384
+
+// This file accelerates fixed-base multiplication with precomputed
+// tables for the generator. The signed window digits of recodeScalar
+// are split into v blocks; block t is served by a table of odd
+// multiples of 2^(5*g*t)G, so the whole ladder needs the doublings of a
+// single block instead of all 385. Base-point multiplications dominate
+// ECDSA signing and ECDH key generation, which makes the table worth
+// its memory.
+//
+// The table is built lazily on first use, or eagerly and with a chosen
+// size through PrecomputeBase. Table scans remain constant time; only
+// the table layout, which is independent of any secret, is configurable.
+
+import "sync/atomic"
+
+// defaultCombTables is the default number of sub-tables: 8 tables of 16
+// points, about 18 KiB, cutting the doublings per multiplication by 8.
+const defaultCombTables = 8
+
+// combTable is a comb precomputation for the generator.
+type combTable struct {
+	g   int             // digits covered by each sub-table
+	tab [][]affinePoint // tab[t]: odd multiples of 2^(multWindow*g*t)G
+}
+
+var baseComb atomic.Pointer[combTable]
+
+// PrecomputeBase rebuilds the generator table with v sub-tables of 16
+// points each. Larger v trades memory, v*16 points, for fewer
+// doublings per ScalarBaseMult, about 385/v. Values outside [1, 78]
+// are clamped.
+func PrecomputeBase(v int) {
+	if v < 1 {
+		v = 1
+	}
+	if v > multDigits {
+		v = multDigits
+	}
+	baseComb.Store(newCombTable(v))
+}
+
+// baseTable returns the current generator table, building the default
+// one on first use.
+func baseTable() *combTable {
+	if t := baseComb.Load(); t != nil {
+		return t
+	}
+	t := newCombTable(defaultCombTables)
+	baseComb.CompareAndSwap(nil, t)
+
+	return t
+}
+
+// newCombTable precomputes v sub-tables for the generator.
+func newCombTable(v int) *combTable {
+	c := &combTable{g: (multDigits + v - 1) / v}
+	c.tab = make([][]affinePoint, v)
+	J := (&affinePoint{x: genX, y: genY}).toJacobian()
+	for t := 0; t < v; t++ {
+		ap := J.toAffine()
+		c.tab[t] = batchToAffine(oddMultiplesCoZ(ap, multWindow))
+		if t < v-1 {
+			for i := 0; i < multWindow*c.g; i++ {
+				J.double()
+			}
+		}
+	}
+
+	return c
+}
+
+// mult computes [k]G with the comb table, in constant time with respect
+// to k: the doubling count, the table scans, and the addition count
+// depend only on the table layout.
+func (c *combTable) mult(k *[SizeScalar]byte) *jacobianPoint {
+	digits := recodeScalar(k)
+
+	var Q jacobianPoint
+	var T affinePoint
+	for i := c.g - 1; i >= 0; i-- {
+		if i < c.g-1 {
+			for j := 0; j < multWindow; j++ {
+				Q.double()
+			}
+		}
+		for t := range c.tab {
+			idx := t*c.g + i
+			if idx >= multDigits {
+				continue
+			}
+			d := digits[idx]
+			s := uint32(d) >> 31
+			abs := uint32((d ^ (d >> 31)) - (d >> 31))
+			selectAffine(&T, c.tab[t], uint(abs)>>1)
+			cnegAffine(&T, uint(s))
+			Q.mixadd(&Q, &T)
+		}
+	}
+
+	return &Q
+}
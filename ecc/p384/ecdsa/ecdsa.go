@@ -0,0 +1,295 @@
+// Package ecdsa implements P-384 ECDSA with deterministic nonces
+// (RFC 6979) on top of the constant-time scalar arithmetic of ecc/p384,
+// avoiding the variable-time big.Int path of the standard library. The
+// nonce is derived with HMAC-SHA-384, so two implementations of this
+// package sign identically; signatures verify with any standard P-384
+// ECDSA verifier. Scalar arithmetic modulo the group order uses
+// math/nat, which is constant time with respect to the values.
+package ecdsa
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/cloudflare/circl/ecc/p384"
+	"github.com/cloudflare/circl/math/nat"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Errors of the package.
+var (
+	// ErrInvalidPrivateKey is returned when a private scalar is zero or
+	// not below the group order.
+	ErrInvalidPrivateKey = errors.New("ecdsa: invalid private key")
+	// ErrInvalidPublicKey is returned when a public key is not a point
+	// on the curve.
+	ErrInvalidPublicKey = errors.New("ecdsa: invalid public key")
+)
+
+// orderBytes is the group order, big-endian.
+var orderBytes = [p384.SizeScalar]byte{
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xc7, 0x63, 0x4d, 0x81, 0xf4, 0x37, 0x2d, 0xdf,
+	0x58, 0x1a, 0x0d, 0xb2, 0x48, 0xb0, 0xa7, 0x7a,
+	0xec, 0xec, 0x19, 0x6a, 0xcc, 0xc5, 0x29, 0x73,
+}
+
+var order = func() *nat.Modulus {
+	m, err := nat.NewModulus(orderBytes[:])
+	if err != nil {
+		panic(err)
+	}
+	return m
+}()
+
+// orderMinus2 is the exponent of the Fermat inversion modulo the
+// (prime) order.
+var orderMinus2 = func() [p384.SizeScalar]byte {
+	e := orderBytes
+	e[p384.SizeScalar-1] -= 2
+	return e
+}()
+
+// PublicKey is a P-384 ECDSA public key with fixed-size big-endian
+// coordinates.
+type PublicKey struct {
+	X, Y [p384.SizeField]byte
+}
+
+// PrivateKey is a P-384 ECDSA private key. It implements crypto.Signer.
+type PrivateKey struct {
+	PublicKey
+	// D is the private scalar, big-endian.
+	D [p384.SizeScalar]byte
+}
+
+// GenerateKey returns a fresh key pair. A nil rnd selects the
+// library-wide source.
+func GenerateKey(rnd io.Reader) (*PrivateKey, error) {
+	rnd = rand.Source(rnd)
+	k := new(PrivateKey)
+	for {
+		if _, err := io.ReadFull(rnd, k.D[:]); err != nil {
+			return nil, err
+		}
+		if scalarValid(&k.D) {
+			break
+		}
+	}
+	k.X, k.Y = p384.ScalarBaseMult(&k.D)
+
+	return k, nil
+}
+
+// Public returns the corresponding public key as *PublicKey.
+func (k *PrivateKey) Public() crypto.PublicKey { return &k.PublicKey }
+
+// dsaSignature is the ASN.1 SEQUENCE of a signature.
+type dsaSignature struct {
+	R, S *big.Int
+}
+
+// Sign signs digest, which should be the hash of a message, returning
+// an ASN.1 DER-encoded signature as crypto.Signer specifies. The nonce
+// is deterministic per RFC 6979 with HMAC-SHA-384, so the random source
+// and the options are ignored; signing never needs randomness and never
+// produces two different signatures for the same input.
+func (k *PrivateKey) Sign(_ io.Reader, digest []byte, _ crypto.SignerOpts) ([]byte, error) {
+	if !scalarValid(&k.D) {
+		return nil, ErrInvalidPrivateKey
+	}
+	e := bits2int(digest)
+	reduceOnce(&e)
+
+	drbg := newDRBG(&k.D, &e)
+	eN := order.NewNat()
+	dN := order.NewNat()
+	if eN.SetBytes(e[:], order) != nil || dN.SetBytes(k.D[:], order) != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	for {
+		var kb [p384.SizeScalar]byte
+		drbg.generate(&kb)
+		if !scalarValid(&kb) {
+			continue
+		}
+		rx, _ := p384.ScalarBaseMult(&kb)
+		reduceOnce(&rx)
+		rN := order.NewNat()
+		if rN.SetBytes(rx[:], order) != nil {
+			continue
+		}
+		zero := order.NewNat()
+		if rN.Equal(zero) == 1 {
+			continue
+		}
+		// s = (e + r*d) / k.
+		kN := order.NewNat()
+		if kN.SetBytes(kb[:], order) != nil {
+			continue
+		}
+		kInv := order.NewNat()
+		kInv.Exp(kN, orderMinus2[:], order)
+		sN := order.NewNat()
+		if sN.SetBytes(rN.Bytes(order), order) != nil {
+			continue
+		}
+		sN.Mul(dN, order)
+		sN.Add(eN, order)
+		sN.Mul(kInv, order)
+		if sN.Equal(zero) == 1 {
+			continue
+		}
+
+		return asn1.Marshal(dsaSignature{
+			R: new(big.Int).SetBytes(rN.Bytes(order)),
+			S: new(big.Int).SetBytes(sN.Bytes(order)),
+		})
+	}
+}
+
+// Verify reports whether sig is a valid ASN.1 DER-encoded signature of
+// digest under pub. Verification handles only public data and is not
+// constant time.
+func Verify(pub *PublicKey, digest, sig []byte) bool {
+	var parsed dsaSignature
+	if rest, err := asn1.Unmarshal(sig, &parsed); err != nil || len(rest) != 0 {
+		return false
+	}
+	nInt := new(big.Int).SetBytes(orderBytes[:])
+	if parsed.R.Sign() <= 0 || parsed.R.Cmp(nInt) >= 0 ||
+		parsed.S.Sign() <= 0 || parsed.S.Cmp(nInt) >= 0 {
+		return false
+	}
+	e := bits2int(digest)
+	reduceOnce(&e)
+
+	eN := order.NewNat()
+	rN := order.NewNat()
+	sN := order.NewNat()
+	var rb, sb [p384.SizeScalar]byte
+	parsed.R.FillBytes(rb[:])
+	parsed.S.FillBytes(sb[:])
+	if eN.SetBytes(e[:], order) != nil ||
+		rN.SetBytes(rb[:], order) != nil ||
+		sN.SetBytes(sb[:], order) != nil {
+		return false
+	}
+	// u1 = e/s, u2 = r/s.
+	sInv := order.NewNat()
+	sInv.Exp(sN, orderMinus2[:], order)
+	u1 := order.NewNat()
+	u1.SetBytes(eN.Bytes(order), order)
+	u1.Mul(sInv, order)
+	u2 := order.NewNat()
+	u2.SetBytes(rN.Bytes(order), order)
+	u2.Mul(sInv, order)
+
+	scalars := make([][p384.SizeScalar]byte, 2)
+	copy(scalars[0][:], u1.Bytes(order))
+	copy(scalars[1][:], u2.Bytes(order))
+	xs := make([][p384.SizeField]byte, 2)
+	ys := make([][p384.SizeField]byte, 2)
+	gx, gy := p384.Generator()
+	xs[0], ys[0] = gx, gy
+	xs[1], ys[1] = pub.X, pub.Y
+	x, y, err := p384.MultiScalarMult(scalars, xs, ys)
+	if err != nil {
+		return false
+	}
+	if x == [p384.SizeField]byte{} && y == [p384.SizeField]byte{} {
+		return false
+	}
+	reduceOnce(&x)
+
+	return x == rb
+}
+
+// scalarValid reports whether b encodes a scalar in [1, order-1].
+func scalarValid(b *[p384.SizeScalar]byte) bool {
+	var nonzero byte
+	ge := true // b >= order so far, scanning from the top
+	for i := 0; i < p384.SizeScalar; i++ {
+		nonzero |= b[i]
+		if ge && b[i] != orderBytes[i] {
+			ge = b[i] > orderBytes[i]
+		}
+	}
+
+	return nonzero != 0 && !ge
+}
+
+// bits2int is the conversion of RFC 6979: the leftmost 384 bits of the
+// digest, right-aligned when the digest is shorter.
+func bits2int(digest []byte) (out [p384.SizeScalar]byte) {
+	if len(digest) >= p384.SizeScalar {
+		copy(out[:], digest)
+	} else {
+		copy(out[p384.SizeScalar-len(digest):], digest)
+	}
+
+	return out
+}
+
+// reduceOnce subtracts the order when b is not below it, in constant
+// time. A 384-bit value is below twice the order, so one subtraction
+// always suffices.
+func reduceOnce(b *[p384.SizeScalar]byte) {
+	var d [p384.SizeScalar]byte
+	var borrow uint16
+	for i := p384.SizeScalar - 1; i >= 0; i-- {
+		v := uint16(b[i]) - uint16(orderBytes[i]) - borrow
+		d[i] = byte(v)
+		borrow = v >> 15
+	}
+	keep := byte(borrow) - 1 // all ones when b >= order
+	for i := range b {
+		b[i] = d[i]&keep | b[i]&^keep
+	}
+}
+
+// drbg is the HMAC-DRBG of RFC 6979, Section 3.2, with SHA-384.
+type drbg struct {
+	k, v []byte
+}
+
+func newDRBG(d, e *[p384.SizeScalar]byte) *drbg {
+	g := &drbg{k: make([]byte, 48), v: make([]byte, 48)}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+	g.update(d, e)
+
+	return g
+}
+
+func (g *drbg) hmac(parts ...[]byte) []byte {
+	h := hmac.New(sha512.New384, g.k)
+	for _, p := range parts {
+		h.Write(p)
+	}
+
+	return h.Sum(nil)
+}
+
+func (g *drbg) update(d, e *[p384.SizeScalar]byte) {
+	g.k = g.hmac(g.v, []byte{0x00}, d[:], e[:])
+	g.v = g.hmac(g.v)
+	g.k = g.hmac(g.v, []byte{0x01}, d[:], e[:])
+	g.v = g.hmac(g.v)
+}
+
+// generate produces the next nonce candidate.
+func (g *drbg) generate(out *[p384.SizeScalar]byte) {
+	g.v = g.hmac(g.v)
+	copy(out[:], g.v)
+	g.k = g.hmac(g.v, []byte{0x00})
+	g.v = g.hmac(g.v)
+}
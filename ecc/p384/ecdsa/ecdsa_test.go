@@ -0,0 +1,72 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	k, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha512.Sum384([]byte("message"))
+	sig, err := k.Sign(rand.Reader, digest[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Verify(&k.PublicKey, digest[:], sig) {
+		t.Fatal("signature does not verify")
+	}
+	digest[0] ^= 1
+	if Verify(&k.PublicKey, digest[:], sig) {
+		t.Fatal("signature verifies for wrong digest")
+	}
+}
+
+func TestInteropWithStdlib(t *testing.T) {
+	k, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha512.Sum384([]byte("interop"))
+	sig, err := k.Sign(rand.Reader, digest[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The stdlib must accept our signatures under the same key.
+	var parsed struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(sig, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	pub := &ecdsa.PublicKey{
+		Curve: elliptic.P384(),
+		X:     new(big.Int).SetBytes(k.PublicKey.X[:]),
+		Y:     new(big.Int).SetBytes(k.PublicKey.Y[:]),
+	}
+	if !ecdsa.Verify(pub, digest[:], parsed.R, parsed.S) {
+		t.Fatal("crypto/ecdsa rejects our signature")
+	}
+
+	// And we must accept the stdlib's.
+	sk, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	theirSig, err := ecdsa.SignASN1(rand.Reader, sk, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var theirPub PublicKey
+	sk.PublicKey.X.FillBytes(theirPub.X[:])
+	sk.PublicKey.Y.FillBytes(theirPub.Y[:])
+	if !Verify(&theirPub, digest[:], theirSig) {
+		t.Fatal("we reject a crypto/ecdsa signature")
+	}
+}
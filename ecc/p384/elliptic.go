@@ -0,0 +1,115 @@
+package p384
+
+// This file adapts the package to the crypto/elliptic interface, so
+// code shaped around elliptic.Curve — crypto/ecdsa, legacy ECDH — runs
+// on this package's arithmetic unchanged. big.Int values exist only at
+// this boundary; scalar multiplication inside is constant time. The
+// affine identity convention of crypto/elliptic, the pair (0,0), is
+// kept in both directions.
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	fp "github.com/cloudflare/circl/math/fp384"
+)
+
+type curveAdapter struct{ params *elliptic.CurveParams }
+
+var p384Curve = curveAdapter{params: elliptic.P384().Params()}
+
+// P384 returns an elliptic.Curve backed by this package. It satisfies
+// crypto/elliptic's interface contract: points passed to Add, Double,
+// and ScalarMult must be on the curve, and the scalar of ScalarMult and
+// ScalarBaseMult is a big-endian integer of any length.
+func P384() elliptic.Curve { return &p384Curve }
+
+func (c *curveAdapter) Params() *elliptic.CurveParams { return c.params }
+
+func (c *curveAdapter) IsOnCurve(x, y *big.Int) bool {
+	var ex, ey fp.Elt
+	if !bigToElt(&ex, x) || !bigToElt(&ey, y) {
+		return false
+	}
+
+	return isOnCurve(&ex, &ey)
+}
+
+func (c *curveAdapter) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	P := jacFromBig(x1, y1)
+	P.add(P, jacFromBig(x2, y2))
+
+	return bigFromJac(P)
+}
+
+func (c *curveAdapter) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	P := jacFromBig(x1, y1)
+	P.double()
+
+	return bigFromJac(P)
+}
+
+func (c *curveAdapter) ScalarMult(x1, y1 *big.Int, k []byte) (*big.Int, *big.Int) {
+	var ap affinePoint
+	bigToElt(&ap.x, x1)
+	bigToElt(&ap.y, y1)
+	kb := c.scalarBytes(k)
+
+	return bigFromJac(scalarMultPoint(&kb, &ap))
+}
+
+func (c *curveAdapter) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	kb := c.scalarBytes(k)
+
+	return bigFromJac(baseTable().mult(&kb))
+}
+
+// scalarBytes brings a big-endian scalar of any length to the fixed
+// width, reducing oversized scalars modulo the group order. Scalars
+// produced by crypto/ecdsa and ECDH are at most the order's width, so
+// the big.Int reduction only runs for unusual callers.
+func (c *curveAdapter) scalarBytes(k []byte) (out [SizeScalar]byte) {
+	if len(k) <= SizeScalar {
+		copy(out[SizeScalar-len(k):], k)
+		return out
+	}
+	v := new(big.Int).SetBytes(k)
+	v.Mod(v, c.params.N)
+	v.FillBytes(out[:])
+
+	return out
+}
+
+// bigToElt converts a coordinate, reporting false for values outside
+// [0, p-1].
+func bigToElt(z *fp.Elt, v *big.Int) bool {
+	if v == nil || v.Sign() < 0 || v.BitLen() > 8*SizeField {
+		return false
+	}
+	var be [SizeField]byte
+	v.FillBytes(be[:])
+
+	return eltFromBytes(z, &be)
+}
+
+// jacFromBig converts an affine big.Int pair to Jacobian coordinates,
+// mapping (0,0) to the identity.
+func jacFromBig(x, y *big.Int) *jacobianPoint {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return &jacobianPoint{}
+	}
+	var ap affinePoint
+	bigToElt(&ap.x, x)
+	bigToElt(&ap.y, y)
+
+	return ap.toJacobian()
+}
+
+// bigFromJac converts a Jacobian point to affine big.Int coordinates,
+// the identity to (0,0).
+func bigFromJac(P *jacobianPoint) (*big.Int, *big.Int) {
+	var x, y [SizeField]byte
+	pointToBytes(&x, &y, P)
+
+	return new(big.Int).SetBytes(x[:]), new(big.Int).SetBytes(y[:])
+}
@@ -0,0 +1,192 @@
+package p384
+
+// This file implements the hash-to-curve suites of RFC 9380 for P-384:
+// P384_XMD:SHA-384_SSWU_RO_ (HashToCurve) and the nonuniform variant
+// P384_XMD:SHA-384_SSWU_NU_ (EncodeToCurve). The simplified SWU map is
+// evaluated in constant time over fp384; the cofactor is one, so no
+// clearing step is needed.
+
+import (
+	"crypto"
+	_ "crypto/sha512" // linked for crypto.SHA384
+
+	"github.com/cloudflare/circl/expander"
+	fp "github.com/cloudflare/circl/math/fp384"
+)
+
+// hash-to-field parameters of the P-384 suites: elements are derived
+// from hashToFieldSize uniform bytes each.
+const hashToFieldSize = 72
+
+// Constants of the simplified SWU map for y^2 = x^3 + Ax + B with
+// Z = -12, in the little-endian field encoding.
+var (
+	// sswuA is the curve coefficient A = -3.
+	sswuA = fp.Elt{
+		0xfc, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+		0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	}
+	// sswuZ is the domain parameter Z = -12 of the suite.
+	sswuZ = fp.Elt{
+		0xf3, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+		0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	}
+	// sqrt12 is a square root of 12 = -Z, turning the fp384 square root
+	// of -u/v into the sqrt(Z*u/v) that the map needs for nonsquares.
+	sqrt12 = fp.Elt{
+		0xb3, 0x26, 0xa6, 0xf5, 0x69, 0xec, 0xe2, 0x14,
+		0x19, 0x7e, 0x0f, 0xa8, 0xf8, 0xe1, 0x0d, 0x3c,
+		0xc5, 0x80, 0xcb, 0x9c, 0xcb, 0x2f, 0x87, 0x1f,
+		0x87, 0x53, 0x8b, 0xd6, 0x83, 0xe3, 0x98, 0x7f,
+		0xdd, 0x2f, 0xda, 0x83, 0x0e, 0x50, 0xf0, 0x71,
+		0x9c, 0x24, 0xb0, 0x56, 0xa6, 0xb4, 0xcc, 0x2a,
+	}
+	// two288 is 2^288, the weight of the high half when reducing a
+	// 72-byte hash output into the field.
+	two288 = fp.Elt{
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+)
+
+// HashToCurve hashes msg to a curve point with the uniform suite
+// P384_XMD:SHA-384_SSWU_RO_ of RFC 9380, under the given domain
+// separation tag. The output distribution is indistinguishable from
+// uniform, as required by OPRF and BLS-style protocols.
+func HashToCurve(msg, dst []byte) (x, y [SizeField]byte) {
+	var u [2]fp.Elt
+	hashToField(u[:], msg, dst)
+	Q0 := mapToCurve(&u[0])
+	Q1 := mapToCurve(&u[1])
+	P := Q0.toJacobian()
+	P.mixadd(P, Q1)
+	pointToBytes(&x, &y, P)
+
+	return x, y
+}
+
+// EncodeToCurve hashes msg to a curve point with the nonuniform suite
+// P384_XMD:SHA-384_SSWU_NU_ of RFC 9380. It is about half the cost of
+// HashToCurve; the output is only statistically close to uniform on a
+// subset of the curve, which suffices when the point is immediately
+// multiplied by a secret scalar.
+func EncodeToCurve(msg, dst []byte) (x, y [SizeField]byte) {
+	var u [1]fp.Elt
+	hashToField(u[:], msg, dst)
+	Q := mapToCurve(&u[0])
+	pointToBytes(&x, &y, Q.toJacobian())
+
+	return x, y
+}
+
+// hashToField fills u with field elements derived from msg by
+// expand_message_xmd with SHA-384, 72 uniform bytes per element.
+func hashToField(u []fp.Elt, msg, dst []byte) {
+	exp := expander.NewExpanderMD(crypto.SHA384, dst)
+	b := exp.Expand(msg, uint(hashToFieldSize*len(u)))
+	for i := range u {
+		reduceField(&u[i], b[i*hashToFieldSize:(i+1)*hashToFieldSize])
+	}
+}
+
+// reduceField reduces a 72-byte big-endian integer into the field as
+// hi*2^288 + lo, each half short enough to be canonical on its own.
+func reduceField(z *fp.Elt, b []byte) {
+	var hi, lo fp.Elt
+	for i := 0; i < hashToFieldSize/2; i++ {
+		hi[i] = b[hashToFieldSize/2-1-i]
+		lo[i] = b[hashToFieldSize-1-i]
+	}
+	fp.Mul(&hi, &hi, &two288)
+	fp.Add(z, &hi, &lo)
+}
+
+// mapToCurve is the simplified SWU map of RFC 9380, Section 6.6.2,
+// following the straight-line procedure of Appendix F.2, in constant
+// time.
+func mapToCurve(u *fp.Elt) *affinePoint {
+	var tv1, tv2, tv3, tv4, tv5, tv6, one fp.Elt
+	fp.SetOne(&one)
+	fp.Sqr(&tv1, u)
+	fp.Mul(&tv1, &sswuZ, &tv1)
+	fp.Sqr(&tv2, &tv1)
+	fp.Add(&tv2, &tv2, &tv1)
+	fp.Add(&tv3, &tv2, &one)
+	fp.Mul(&tv3, &curveB, &tv3)
+	fp.Neg(&tv4, &tv2)
+	fp.Cmov(&tv4, &sswuZ, ctIsZero(&tv2))
+	fp.Mul(&tv4, &sswuA, &tv4)
+	fp.Sqr(&tv2, &tv3)
+	fp.Sqr(&tv6, &tv4)
+	fp.Mul(&tv5, &sswuA, &tv6)
+	fp.Add(&tv2, &tv2, &tv5)
+	fp.Mul(&tv2, &tv2, &tv3)
+	fp.Mul(&tv6, &tv6, &tv4)
+	fp.Mul(&tv5, &curveB, &tv6)
+	fp.Add(&tv2, &tv2, &tv5)
+
+	var ap affinePoint
+	fp.Mul(&ap.x, &tv1, &tv3)
+	isSquare := sqrtRatioZ(&ap.y, &tv2, &tv6)
+	var t fp.Elt
+	fp.Mul(&t, &tv1, u)
+	fp.Mul(&t, &t, &ap.y)
+	fp.Cmov(&ap.x, &tv3, isSquare)
+	fp.Cmov(&t, &ap.y, isSquare)
+	ap.y = t
+
+	// Fix the sign of y to that of u.
+	fp.Neg(&t, &ap.y)
+	fp.Cmov(&ap.y, &t, sgn0(u)^sgn0(&ap.y))
+	var vi fp.Elt
+	fp.Inv(&vi, &tv4)
+	fp.Mul(&ap.x, &ap.x, &vi)
+
+	return &ap
+}
+
+// sqrtRatioZ is the sqrt_ratio primitive of the suite: it sets z to
+// sqrt(u/v) and returns 1 when u/v is square, and otherwise sets z to
+// sqrt(Z*u/v) and returns 0, in constant time.
+func sqrtRatioZ(z, u, v *fp.Elt) uint {
+	isQR := fp.SqrtRatio(z, u, v)
+	// For a nonsquare, z holds sqrt(-u/v); scaling by sqrt(12) gives
+	// sqrt(-12*u/v) = sqrt(Z*u/v).
+	var t fp.Elt
+	fp.Mul(&t, z, &sqrt12)
+	fp.Cmov(z, &t, 1-isQR)
+
+	return isQR
+}
+
+// sgn0 returns the parity of the canonical representative of x.
+func sgn0(x *fp.Elt) uint {
+	t := *x
+	fp.Modp(&t)
+
+	return uint(t[0] & 1)
+}
+
+// ctIsZero returns 1 when x represents zero, in constant time.
+func ctIsZero(x *fp.Elt) uint {
+	t := *x
+	fp.Modp(&t)
+	var v byte
+	for _, b := range t {
+		v |= b
+	}
+
+	return uint((uint32(v) - 1) >> 31)
+}
@@ -0,0 +1,183 @@
+package p384
+
+// This file provides an alternative scalar multiplication built on the
+// Montgomery ladder. The ladder performs one conjugate and one regular
+// co-Z addition per scalar bit (see coz.go) with no tables and no
+// doublings past the first, and it maintains the invariant R1 - R0 = P,
+// which makes induced faults detectable: a glitched intermediate almost
+// surely leaves the curve. Deployments on fault-exposed hardware can
+// additionally have the intermediates validated every few steps. The
+// window method of ScalarMult remains the faster choice where faults
+// are not a concern.
+//
+// The co-Z formulas are incomplete, so the scalar is normalized to a
+// 385-bit value k' congruent to k whose ladder provably never meets a
+// degenerate case, except for four residues of k — 0, 1, n-2, and n-1
+// modulo the group order n — whose results are written directly from P
+// instead. Both the normalization and the patch are constant time.
+
+import (
+	"errors"
+	"math/bits"
+
+	fp "github.com/cloudflare/circl/math/fp384"
+)
+
+// ErrFault is returned when a ladder intermediate fails curve
+// validation, indicating a computation fault.
+var ErrFault = errors.New("p384: fault detected")
+
+// LadderOptions configures ScalarMultLadder. The zero value checks the
+// result only.
+type LadderOptions struct {
+	// CheckEvery validates the ladder intermediates every CheckEvery
+	// steps, at a cost of a few field multiplications per check; 0
+	// validates only the final result.
+	CheckEvery int
+}
+
+// ScalarMultLadder computes [k]P like ScalarMult, using a Montgomery
+// ladder, in constant time with respect to k. It returns
+// ErrInvalidPoint when (px,py) is not on the curve, and ErrFault when a
+// validation mandated by opt fails. A nil opt selects the defaults.
+func ScalarMultLadder(k *[SizeScalar]byte, px, py *[SizeField]byte, opt *LadderOptions) (x, y [SizeField]byte, err error) {
+	var ap affinePoint
+	if !eltFromBytes(&ap.x, px) || !eltFromBytes(&ap.y, py) ||
+		!isOnCurve(&ap.x, &ap.y) {
+		return x, y, ErrInvalidPoint
+	}
+	every := 0
+	if opt != nil && opt.CheckEvery > 0 {
+		every = opt.CheckEvery
+	}
+
+	kp, f0, f1, fn2, fn1 := ladderRecode(k)
+
+	// Bit 384 of kp is always set, so the ladder starts from R0 = P and
+	// R1 = 2P, already sharing z, and processes the remaining 384 bits.
+	R1, R0 := dblu(&ap)
+	for i := 8*SizeScalar - 1; i >= 0; i-- {
+		b := uint(kp[i/64]>>(i%64)) & 1
+		cswapJacobian(&R0, &R1, b)
+		zaddc(&R0, &R1)
+		zaddu(&R1, &R0)
+		cswapJacobian(&R0, &R1, b)
+		if every > 0 && (8*SizeScalar-i)%every == 0 {
+			if !onCurveJacobian(&R0) || !onCurveJacobian(&R1) {
+				return x, y, ErrFault
+			}
+		}
+	}
+
+	// Patch in the results for the four filtered residues: the
+	// identity, P, -2P, and -P.
+	var idP jacobianPoint
+	P1 := ap.toJacobian()
+	Pm1 := *P1
+	Pm1.neg()
+	Pm2 := *P1
+	Pm2.double()
+	Pm2.neg()
+	cmovJacobian(&R0, &idP, uint(f0))
+	cmovJacobian(&R0, P1, uint(f1))
+	cmovJacobian(&R0, &Pm2, uint(fn2))
+	cmovJacobian(&R0, &Pm1, uint(fn1))
+
+	if !onCurveJacobian(&R0) {
+		return x, y, ErrFault
+	}
+	pointToBytes(&x, &y, &R0)
+
+	return x, y, nil
+}
+
+// ladderRecode reduces k modulo the group order n and lifts it to
+// kp = (k mod n) + n or + 2n, whichever lies in [2^384, 2^384 + n), in
+// constant time. Over that range the only ladder intermediates that can
+// meet a degenerate case of the co-Z formulas belong to the residues 0,
+// 1, n-2, and n-1; those are reported in the returned flags (each 0 or
+// 1) and replaced by a harmless scalar so the ladder still runs in
+// constant time.
+func ladderRecode(k *[SizeScalar]byte) (kp [7]uint64, f0, f1, fn2, fn1 uint64) {
+	var w [7]uint64
+	for i := 0; i < SizeScalar; i++ {
+		w[i/8] |= uint64(k[SizeScalar-1-i]) << (8 * (i % 8))
+	}
+
+	// One conditional subtraction reduces modulo n, as k < 2^384 < 2n.
+	var t [7]uint64
+	var b uint64
+	for i := range w {
+		t[i], b = bits.Sub64(w[i], orderWords[i], b)
+	}
+	mask := b - 1
+	for i := range w {
+		w[i] = w[i]&^mask | t[i]&mask
+	}
+
+	nm2 := orderWords
+	nm2[0] -= 2
+	nm1 := orderWords
+	nm1[0] -= 1
+	f0 = wordsEqual(&w, &[7]uint64{})
+	f1 = wordsEqual(&w, &[7]uint64{1})
+	fn2 = wordsEqual(&w, &nm2)
+	fn1 = wordsEqual(&w, &nm1)
+
+	// Substitute 5 for the filtered residues.
+	mask = -(f0 | f1 | fn2 | fn1)
+	w[0] = w[0]&^mask | 5&mask
+	for i := 1; i < len(w); i++ {
+		w[i] &^= mask
+	}
+
+	b = 0
+	for i := range w {
+		kp[i], b = bits.Add64(w[i], orderWords[i], b)
+	}
+	need := kp[6]&1 - 1
+	b = 0
+	for i := range kp {
+		kp[i], b = bits.Add64(kp[i], orderWords[i]&need, b)
+	}
+
+	return kp, f0, f1, fn2, fn1
+}
+
+// wordsEqual reports whether w equals v, in constant time.
+func wordsEqual(w, v *[7]uint64) uint64 {
+	var z uint64
+	for i := range w {
+		z |= w[i] ^ v[i]
+	}
+
+	return 1 - (z|-z)>>63
+}
+
+// cswapJacobian swaps P and Q when c is 1, in constant time.
+func cswapJacobian(P, Q *jacobianPoint, c uint) {
+	fp.Cswap(&P.x, &Q.x, c)
+	fp.Cswap(&P.y, &Q.y, c)
+	fp.Cswap(&P.z, &Q.z, c)
+}
+
+// onCurveJacobian reports whether P satisfies the projective curve
+// equation y^2 = x^3 - 3xz^4 + bz^6; the identity passes.
+func onCurveJacobian(P *jacobianPoint) bool {
+	var z2, z4, z6, l, r, t fp.Elt
+	fp.Sqr(&z2, &P.z)
+	fp.Sqr(&z4, &z2)
+	fp.Mul(&z6, &z4, &z2)
+	fp.Sqr(&l, &P.y)
+	fp.Sqr(&r, &P.x)
+	fp.Mul(&r, &r, &P.x)
+	fp.Mul(&t, &P.x, &z4)
+	fp.Sub(&r, &r, &t)
+	fp.Sub(&r, &r, &t)
+	fp.Sub(&r, &r, &t)
+	fp.Mul(&t, &curveB, &z6)
+	fp.Add(&r, &r, &t)
+	fp.Sub(&l, &l, &r)
+
+	return fp.IsZero(&l)
+}
@@ -0,0 +1,103 @@
+package p384
+
+// This file implements multi-scalar multiplication with Pippenger's
+// bucket method. Batch verifiers and accumulator constructions compute
+// sums of hundreds of scalar multiples; grouping the work by window
+// digit makes the cost per point a handful of additions instead of a
+// full ladder.
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// ErrMismatchedLengths is returned when the slices of a multi-scalar
+// multiplication do not have equal lengths.
+var ErrMismatchedLengths = errors.New("p384: mismatched slice lengths")
+
+// MultiScalarMult computes the linear combination
+//
+//	[k_0](x_0,y_0) + [k_1](x_1,y_1) + ...
+//
+// using Pippenger's bucket method, which is far cheaper than one
+// ScalarMult per point for large batches. Scalars are big-endian and
+// need not be reduced modulo the group order. It returns
+// ErrInvalidPoint when any input point is not on the curve. The empty
+// sum and sums hitting the identity are encoded as (0,0).
+//
+// MultiScalarMult is not constant time and must only be used with
+// public scalars, as in batch signature verification.
+func MultiScalarMult(scalars [][SizeScalar]byte, xs, ys [][SizeField]byte) (x, y [SizeField]byte, err error) {
+	n := len(scalars)
+	if len(xs) != n || len(ys) != n {
+		return x, y, ErrMismatchedLengths
+	}
+	pts := make([]affinePoint, n)
+	for i := range pts {
+		if !eltFromBytes(&pts[i].x, &xs[i]) || !eltFromBytes(&pts[i].y, &ys[i]) ||
+			!isOnCurve(&pts[i].x, &pts[i].y) {
+			return x, y, ErrInvalidPoint
+		}
+	}
+
+	c := msmWindow(n)
+	buckets := make([]jacobianPoint, (1<<c)-1)
+	windows := (8*SizeScalar + c - 1) / c
+
+	var acc jacobianPoint
+	for w := int(windows) - 1; w >= 0; w-- {
+		for j := uint(0); j < c; j++ {
+			acc.double()
+		}
+		for i := range buckets {
+			buckets[i] = jacobianPoint{}
+		}
+		for i := 0; i < n; i++ {
+			d := scalarDigit(&scalars[i], uint(w)*c, c)
+			if d != 0 {
+				b := &buckets[d-1]
+				b.mixadd(b, &pts[i])
+			}
+		}
+		// Aggregate so bucket j contributes j+1 times: a running
+		// suffix sum added once per bucket.
+		var sum, run jacobianPoint
+		for j := len(buckets) - 1; j >= 0; j-- {
+			run.add(&run, &buckets[j])
+			sum.add(&sum, &run)
+		}
+		acc.add(&acc, &sum)
+	}
+	pointToBytes(&x, &y, &acc)
+
+	return x, y, nil
+}
+
+// msmWindow picks the bucket width for n points, balancing the 2^c
+// additions of each window's aggregation against the per-point work.
+func msmWindow(n int) uint {
+	c := uint(bits.Len(uint(n)))
+	if c < 2 {
+		c = 2
+	}
+	if c > 12 {
+		c = 12
+	}
+
+	return c
+}
+
+// scalarDigit extracts the unsigned digit of width bits starting at bit
+// pos (counting from the least significant end) of a big-endian scalar.
+func scalarDigit(k *[SizeScalar]byte, pos, width uint) uint {
+	var d uint
+	for j := uint(0); j < width; j++ {
+		b := pos + j
+		if b >= 8*SizeScalar {
+			break
+		}
+		d |= uint(k[SizeScalar-1-b/8]>>(b%8)&1) << j
+	}
+
+	return d
+}
@@ -0,0 +1,134 @@
+package p384
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func randScalar(t *testing.T) [SizeScalar]byte {
+	var k [SizeScalar]byte
+	if _, err := rand.Read(k[:]); err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestScalarBaseMultMatchesStdlib(t *testing.T) {
+	c := elliptic.P384()
+	for i := 0; i < 16; i++ {
+		k := randScalar(t)
+		x, y := ScalarBaseMult(&k)
+
+		kr := new(big.Int).SetBytes(k[:])
+		kr.Mod(kr, c.Params().N)
+		wx, wy := c.ScalarBaseMult(kr.Bytes())
+		if new(big.Int).SetBytes(x[:]).Cmp(wx) != 0 ||
+			new(big.Int).SetBytes(y[:]).Cmp(wy) != 0 {
+			t.Fatal("ScalarBaseMult diverges from crypto/elliptic")
+		}
+	}
+}
+
+func TestScalarMultMatchesStdlib(t *testing.T) {
+	c := elliptic.P384()
+	gx, gy := Generator()
+	for i := 0; i < 16; i++ {
+		k := randScalar(t)
+		x, y, err := ScalarMult(&k, &gx, &gy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lx, ly, err := ScalarMultLadder(&k, &gx, &gy, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x != lx || y != ly {
+			t.Fatal("ladder and fixed-window results differ")
+		}
+
+		kr := new(big.Int).SetBytes(k[:])
+		kr.Mod(kr, c.Params().N)
+		wx, wy := c.ScalarBaseMult(kr.Bytes())
+		if new(big.Int).SetBytes(x[:]).Cmp(wx) != 0 ||
+			new(big.Int).SetBytes(y[:]).Cmp(wy) != 0 {
+			t.Fatal("ScalarMult diverges from crypto/elliptic")
+		}
+	}
+}
+
+func TestSEC1RoundTrip(t *testing.T) {
+	k := randScalar(t)
+	x, y := ScalarBaseMult(&k)
+
+	ux, uy, err := Unmarshal(Marshal(&x, &y))
+	if err != nil || ux != x || uy != y {
+		t.Fatal("uncompressed roundtrip failed")
+	}
+	cx, cy, err := UnmarshalCompressed(MarshalCompressed(&x, &y))
+	if err != nil || cx != x || cy != y {
+		t.Fatal("compressed roundtrip failed")
+	}
+
+	// The encoding must agree with crypto/elliptic's.
+	want := elliptic.Marshal(elliptic.P384(),
+		new(big.Int).SetBytes(x[:]), new(big.Int).SetBytes(y[:]))
+	if !bytes.Equal(Marshal(&x, &y), want) {
+		t.Fatal("SEC1 encoding differs from crypto/elliptic")
+	}
+
+	// Off-curve points must be rejected.
+	bad := Marshal(&x, &y)
+	bad[20] ^= 1
+	if _, _, err := Unmarshal(bad); err == nil {
+		t.Fatal("off-curve point accepted")
+	}
+}
+
+func TestHashToCurve(t *testing.T) {
+	c := elliptic.P384()
+	dst := []byte("QUUX-V01-CS02-with-P384_XMD:SHA-384_SSWU_RO_")
+	seen := make(map[[SizeField]byte]bool)
+	for _, msg := range []string{"", "abc", "hello world", "q128_long"} {
+		x, y := HashToCurve([]byte(msg), dst)
+		if !c.IsOnCurve(new(big.Int).SetBytes(x[:]), new(big.Int).SetBytes(y[:])) {
+			t.Fatalf("HashToCurve(%q) not on curve", msg)
+		}
+		if seen[x] {
+			t.Fatalf("HashToCurve(%q) collided", msg)
+		}
+		seen[x] = true
+
+		ex, ey := EncodeToCurve([]byte(msg), dst)
+		if !c.IsOnCurve(new(big.Int).SetBytes(ex[:]), new(big.Int).SetBytes(ey[:])) {
+			t.Fatalf("EncodeToCurve(%q) not on curve", msg)
+		}
+	}
+}
+
+func TestMultiScalarMult(t *testing.T) {
+	c := elliptic.P384()
+	n := 5
+	scalars := make([][SizeScalar]byte, n)
+	xs := make([][SizeField]byte, n)
+	ys := make([][SizeField]byte, n)
+	wx, wy := new(big.Int), new(big.Int)
+	for i := range scalars {
+		scalars[i] = randScalar(t)
+		p := randScalar(t)
+		xs[i], ys[i] = ScalarBaseMult(&p)
+		k := new(big.Int).SetBytes(scalars[i][:])
+		k.Mod(k, c.Params().N)
+		px, py := c.ScalarMult(new(big.Int).SetBytes(xs[i][:]), new(big.Int).SetBytes(ys[i][:]), k.Bytes())
+		wx, wy = c.Add(wx, wy, px, py)
+	}
+	x, y, err := MultiScalarMult(scalars, xs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if new(big.Int).SetBytes(x[:]).Cmp(wx) != 0 || new(big.Int).SetBytes(y[:]).Cmp(wy) != 0 {
+		t.Fatal("MultiScalarMult diverges from crypto/elliptic")
+	}
+}
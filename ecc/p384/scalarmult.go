@@ -0,0 +1,241 @@
+package p384
+
+// This file exposes the optimized point arithmetic directly on fixed-size
+// byte encodings, so applications can avoid the crypto/elliptic interface
+// and its big.Int conversions. Coordinates and scalars are big-endian;
+// scalars need not be reduced modulo the group order. Both entry points
+// run in constant time with respect to the scalar; point encodings are
+// treated as public.
+
+import (
+	"errors"
+	"math/bits"
+
+	fp "github.com/cloudflare/circl/math/fp384"
+)
+
+// Sizes of the byte encodings accepted by this package.
+const (
+	// SizeField is the length of a serialized coordinate.
+	SizeField = 48
+	// SizeScalar is the length of a serialized scalar.
+	SizeScalar = 48
+)
+
+// ErrInvalidPoint is returned when an input encoding is not a point on
+// the curve.
+var ErrInvalidPoint = errors.New("p384: point not on curve")
+
+// Curve constants in the little-endian field encoding: the coefficient b
+// of y^2 = x^3 - 3x + b and the coordinates of the generator.
+var (
+	curveB = fp.Elt{
+		0xef, 0x2a, 0xec, 0xd3, 0xed, 0xc8, 0x85, 0x2a,
+		0x9d, 0xd1, 0x2e, 0x8a, 0x8d, 0x39, 0x56, 0xc6,
+		0x5a, 0x87, 0x13, 0x50, 0x8f, 0x08, 0x14, 0x03,
+		0x12, 0x41, 0x81, 0xfe, 0x6e, 0x9c, 0x1d, 0x18,
+		0x19, 0x2d, 0xf8, 0xe3, 0x6b, 0x05, 0x8e, 0x98,
+		0xe4, 0xe7, 0x3e, 0xe2, 0xa7, 0x2f, 0x31, 0xb3,
+	}
+	genX = fp.Elt{
+		0xb7, 0x0a, 0x76, 0x72, 0x38, 0x5e, 0x54, 0x3a,
+		0x6c, 0x29, 0x55, 0xbf, 0x5d, 0xf2, 0x02, 0x55,
+		0x38, 0x2a, 0x54, 0x82, 0xe0, 0x41, 0xf7, 0x59,
+		0x98, 0x9b, 0xa7, 0x8b, 0x62, 0x3b, 0x1d, 0x6e,
+		0x74, 0xad, 0x20, 0xf3, 0x1e, 0xc7, 0xb1, 0x8e,
+		0x37, 0x05, 0x8b, 0xbe, 0x22, 0xca, 0x87, 0xaa,
+	}
+	genY = fp.Elt{
+		0x5f, 0x0e, 0xea, 0x90, 0x7c, 0x1d, 0x43, 0x7a,
+		0x9d, 0x81, 0x7e, 0x1d, 0xce, 0xb1, 0x60, 0x0a,
+		0xc0, 0xb8, 0xf0, 0xb5, 0x13, 0x31, 0xda, 0xe9,
+		0x7c, 0x14, 0x9a, 0x28, 0xbd, 0x1d, 0xf4, 0xf8,
+		0x29, 0xdc, 0x92, 0x92, 0xbf, 0x98, 0x9e, 0x5d,
+		0x6f, 0x2c, 0x26, 0x96, 0x4a, 0xde, 0x17, 0x36,
+	}
+)
+
+// orderWords is the group order in 64-bit little-endian words, one spare
+// word wide so a scalar plus the order still fits.
+var orderWords = [7]uint64{
+	0xecec196accc52973, 0x581a0db248b0a77a, 0xc7634d81f4372ddf,
+	0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff, 0,
+}
+
+const (
+	// multWindow is the width of the signed fixed windows used for
+	// scalar multiplication.
+	multWindow = 5
+	// multDigits is the number of window digits: the scalar can grow to
+	// k plus the order, 385 bits, and the recoding needs one digit of
+	// headroom so the final remainder fits a table entry.
+	multDigits = 78
+)
+
+// Generator returns the coordinates of the base point.
+func Generator() (x, y [SizeField]byte) {
+	eltToBytes(&x, &genX)
+	eltToBytes(&y, &genY)
+
+	return x, y
+}
+
+// ScalarBaseMult computes [k]G for the generator G, in constant time
+// with respect to k, using the precomputed comb table (see
+// PrecomputeBase). When k is a multiple of the group order the result
+// is the identity, encoded as (0,0), which is not a curve point.
+func ScalarBaseMult(k *[SizeScalar]byte) (x, y [SizeField]byte) {
+	P := baseTable().mult(k)
+	pointToBytes(&x, &y, P)
+
+	return x, y
+}
+
+// ScalarMult computes [k]P for the point P = (px,py), in constant time
+// with respect to k. It returns ErrInvalidPoint when the coordinates are
+// not canonical or the point is not on the curve. When k is a multiple
+// of the group order the result is the identity, encoded as (0,0).
+func ScalarMult(k *[SizeScalar]byte, px, py *[SizeField]byte) (x, y [SizeField]byte, err error) {
+	var ap affinePoint
+	if !eltFromBytes(&ap.x, px) || !eltFromBytes(&ap.y, py) ||
+		!isOnCurve(&ap.x, &ap.y) {
+		return x, y, ErrInvalidPoint
+	}
+	P := scalarMultPoint(k, &ap)
+	pointToBytes(&x, &y, P)
+
+	return x, y, nil
+}
+
+// scalarMultPoint computes [k]P with a left-to-right signed fixed-window
+// ladder over the odd multiples of P, normalized to affine once so every
+// table hit is a mixed addition. Every iteration does the same five
+// doublings, one table scan, and one addition, regardless of k.
+func scalarMultPoint(k *[SizeScalar]byte, ap *affinePoint) *jacobianPoint {
+	digits := recodeScalar(k)
+	tab := batchToAffine(oddMultiplesCoZ(ap, multWindow))
+
+	var Q jacobianPoint
+	var T affinePoint
+	selectAffine(&T, tab, uint(digits[multDigits-1])>>1)
+	Q.x, Q.y = T.x, T.y
+	fp.SetOne(&Q.z)
+	for i := multDigits - 2; i >= 0; i-- {
+		for j := 0; j < multWindow; j++ {
+			Q.double()
+		}
+		d := digits[i]
+		s := uint32(d) >> 31
+		abs := uint32((d ^ (d >> 31)) - (d >> 31))
+		selectAffine(&T, tab, uint(abs)>>1)
+		cnegAffine(&T, uint(s))
+		Q.mixadd(&Q, &T)
+	}
+
+	return &Q
+}
+
+// recodeScalar splits a scalar into odd signed digits of multWindow bits,
+// k = sum digits[i]*2^(multWindow*i), in constant time. The scalar is
+// first made odd by conditionally adding the (odd) group order, which
+// does not change the multiple; every digit is then odd and bounded by
+// 2^multWindow-1 in absolute value, so it indexes a table of odd
+// multiples, and the final digit is positive.
+func recodeScalar(k *[SizeScalar]byte) [multDigits]int32 {
+	var w [7]uint64
+	for i := 0; i < SizeScalar; i++ {
+		w[i/8] |= uint64(k[SizeScalar-1-i]) << (8 * (i % 8))
+	}
+	even := (w[0] & 1) - 1
+	var c uint64
+	for i := range w {
+		w[i], c = bits.Add64(w[i], orderWords[i]&even, c)
+	}
+
+	const mask = 1<<(multWindow+1) - 1
+	var digits [multDigits]int32
+	for i := 0; i < multDigits-1; i++ {
+		d := int64(w[0]&mask) - (1 << multWindow)
+		digits[i] = int32(d)
+		// w = (w - d) >> multWindow, with d sign-extended.
+		var b uint64
+		w[0], b = bits.Sub64(w[0], uint64(d), 0)
+		for j := 1; j < 7; j++ {
+			w[j], b = bits.Sub64(w[j], uint64(d>>63), b)
+		}
+		for j := 0; j < 6; j++ {
+			w[j] = w[j]>>multWindow | w[j+1]<<(64-multWindow)
+		}
+		w[6] >>= multWindow
+	}
+	digits[multDigits-1] = int32(w[0])
+
+	return digits
+}
+
+// selectAffine copies tab[idx] into P, scanning the whole table.
+func selectAffine(P *affinePoint, tab []affinePoint, idx uint) {
+	for i := range tab {
+		c := uint((uint64(uint(i)^idx) - 1) >> 63)
+		fp.Cmov(&P.x, &tab[i].x, c)
+		fp.Cmov(&P.y, &tab[i].y, c)
+	}
+}
+
+// cnegAffine negates P when c is 1, in constant time.
+func cnegAffine(P *affinePoint, c uint) {
+	var t fp.Elt
+	fp.Neg(&t, &P.y)
+	fp.Cmov(&P.y, &t, c)
+}
+
+// eltFromBytes converts a big-endian coordinate to the field encoding,
+// reporting false for non-canonical values.
+func eltFromBytes(z *fp.Elt, b *[SizeField]byte) bool {
+	for i := 0; i < SizeField; i++ {
+		z[i] = b[SizeField-1-i]
+	}
+	t := *z
+	fp.Modp(&t)
+
+	return t == *z
+}
+
+// eltToBytes converts a field element to a big-endian coordinate.
+func eltToBytes(b *[SizeField]byte, z *fp.Elt) {
+	t := *z
+	fp.Modp(&t)
+	for i := 0; i < SizeField; i++ {
+		b[SizeField-1-i] = t[i]
+	}
+}
+
+// isOnCurve reports whether y^2 = x^3 - 3x + b.
+func isOnCurve(x, y *fp.Elt) bool {
+	var l, r, t fp.Elt
+	fp.Sqr(&l, y)
+	fp.Sqr(&r, x)
+	fp.Mul(&r, &r, x)
+	fp.Add(&t, x, x)
+	fp.Add(&t, &t, x)
+	fp.Sub(&r, &r, &t)
+	fp.Add(&r, &r, &curveB)
+	fp.Sub(&l, &l, &r)
+
+	return fp.IsZero(&l)
+}
+
+// pointToBytes encodes P in affine coordinates, the identity as (0,0).
+// The conversion is constant time: the exponentiation-based field
+// inversion maps the zero denominator of the identity to zero.
+func pointToBytes(x, y *[SizeField]byte, P *jacobianPoint) {
+	var zi, t fp.Elt
+	fp.Inv(&zi, &P.z)
+	fp.Sqr(&t, &zi)
+	var ax, ay fp.Elt
+	fp.Mul(&ax, &P.x, &t)
+	fp.Mul(&t, &t, &zi)
+	fp.Mul(&ay, &P.y, &t)
+	eltToBytes(x, &ax)
+	eltToBytes(y, &ay)
+}
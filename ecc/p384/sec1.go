@@ -0,0 +1,124 @@
+package p384
+
+// This file implements the SEC1 point encodings on the fixed-size
+// coordinate representation used by this package, so callers do not have
+// to round-trip through big.Int and crypto/elliptic. Decompression
+// recovers y with the constant-time square root of fp384; the point
+// encodings themselves are treated as public.
+
+import (
+	"errors"
+
+	fp "github.com/cloudflare/circl/math/fp384"
+)
+
+// Sizes of the SEC1 point encodings.
+const (
+	// SizeCompressed is the length of a compressed point, a sign prefix
+	// and the x-coordinate.
+	SizeCompressed = 1 + SizeField
+	// SizeUncompressed is the length of an uncompressed point, a prefix
+	// and both coordinates.
+	SizeUncompressed = 1 + 2*SizeField
+)
+
+// ErrInvalidEncoding is returned when a byte string is not a valid SEC1
+// point encoding.
+var ErrInvalidEncoding = errors.New("p384: invalid SEC1 encoding")
+
+// Marshal returns the uncompressed SEC1 encoding 0x04 || x || y. The
+// identity, given as (0,0), encodes as the single byte 0x00. The
+// coordinates are not validated.
+func Marshal(x, y *[SizeField]byte) []byte {
+	if *x == [SizeField]byte{} && *y == [SizeField]byte{} {
+		return []byte{0}
+	}
+	out := make([]byte, SizeUncompressed)
+	out[0] = 0x04
+	copy(out[1:], x[:])
+	copy(out[1+SizeField:], y[:])
+
+	return out
+}
+
+// MarshalCompressed returns the compressed SEC1 encoding, 0x02 or 0x03
+// according to the parity of y, followed by x. The identity, given as
+// (0,0), encodes as the single byte 0x00. The coordinates are not
+// validated.
+func MarshalCompressed(x, y *[SizeField]byte) []byte {
+	if *x == [SizeField]byte{} && *y == [SizeField]byte{} {
+		return []byte{0}
+	}
+	out := make([]byte, SizeCompressed)
+	out[0] = 0x02 | y[SizeField-1]&1
+	copy(out[1:], x[:])
+
+	return out
+}
+
+// Unmarshal parses an uncompressed SEC1 encoding, validating that the
+// coordinates are canonical and the point is on the curve. The identity
+// encoding 0x00 yields (0,0). It returns ErrInvalidEncoding for a
+// malformed byte string and ErrInvalidPoint for a well-formed encoding
+// of a point outside the curve.
+func Unmarshal(data []byte) (x, y [SizeField]byte, err error) {
+	if len(data) == 1 && data[0] == 0 {
+		return x, y, nil
+	}
+	if len(data) != SizeUncompressed || data[0] != 0x04 {
+		return x, y, ErrInvalidEncoding
+	}
+	copy(x[:], data[1:])
+	copy(y[:], data[1+SizeField:])
+	var ex, ey fp.Elt
+	if !eltFromBytes(&ex, &x) || !eltFromBytes(&ey, &y) || !isOnCurve(&ex, &ey) {
+		return [SizeField]byte{}, [SizeField]byte{}, ErrInvalidPoint
+	}
+
+	return x, y, nil
+}
+
+// UnmarshalCompressed parses a compressed SEC1 encoding, recovering y as
+// the square root of x^3 - 3x + b with the parity selected by the
+// prefix. The identity encoding 0x00 yields (0,0). It returns
+// ErrInvalidEncoding for a malformed byte string and ErrInvalidPoint
+// when x is not the abscissa of a curve point or the requested parity
+// does not exist (y = 0 with prefix 0x03).
+func UnmarshalCompressed(data []byte) (x, y [SizeField]byte, err error) {
+	if len(data) == 1 && data[0] == 0 {
+		return x, y, nil
+	}
+	if len(data) != SizeCompressed || data[0]&0xfe != 0x02 {
+		return x, y, ErrInvalidEncoding
+	}
+	copy(x[:], data[1:])
+	var ex fp.Elt
+	if !eltFromBytes(&ex, &x) {
+		return [SizeField]byte{}, [SizeField]byte{}, ErrInvalidPoint
+	}
+	// rhs = x^3 - 3x + b.
+	var rhs, t fp.Elt
+	fp.Sqr(&rhs, &ex)
+	fp.Mul(&rhs, &rhs, &ex)
+	fp.Add(&t, &ex, &ex)
+	fp.Add(&t, &t, &ex)
+	fp.Sub(&rhs, &rhs, &t)
+	fp.Add(&rhs, &rhs, &curveB)
+
+	var one, ey fp.Elt
+	fp.SetOne(&one)
+	if fp.SqrtRatio(&ey, &rhs, &one) != 1 {
+		return [SizeField]byte{}, [SizeField]byte{}, ErrInvalidPoint
+	}
+	fp.Modp(&ey)
+	want := uint(data[0] & 1)
+	fp.Neg(&t, &ey)
+	fp.Modp(&t)
+	fp.Cmov(&ey, &t, uint(ey[0]&1)^want)
+	if uint(ey[0]&1) != want {
+		return [SizeField]byte{}, [SizeField]byte{}, ErrInvalidPoint
+	}
+	eltToBytes(&y, &ey)
+
+	return x, y, nil
+}
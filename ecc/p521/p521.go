@@ -0,0 +1,252 @@
+// Package p521 provides optimized elliptic curve operations on the
+// NIST P-521 curve.
+//
+// The package mirrors ecc/p384: operations work directly on fixed-size
+// big-endian byte encodings, with no big.Int values anywhere, and the
+// scalar multiplications run in constant time with respect to the
+// scalar. The field layer (math/fp521) exploits the Mersenne form of
+// the prime, 2^521-1.
+package p521
+
+import (
+	"errors"
+	"math/bits"
+
+	fp "github.com/cloudflare/circl/math/fp521"
+)
+
+// Sizes of the byte encodings accepted by this package.
+const (
+	// SizeField is the length of a serialized coordinate.
+	SizeField = 66
+	// SizeScalar is the length of a serialized scalar.
+	SizeScalar = 66
+)
+
+// ErrInvalidPoint is returned when an input encoding is not a point on
+// the curve.
+var ErrInvalidPoint = errors.New("p521: point not on curve")
+
+// Curve constants in the little-endian field encoding: the coefficient b
+// of y^2 = x^3 - 3x + b and the coordinates of the generator.
+var (
+	curveB = fp.Elt{
+		0x00, 0x3f, 0x50, 0x6b, 0xd4, 0x1f, 0x45, 0xef,
+		0xf1, 0x34, 0x2c, 0x3d, 0x88, 0xdf, 0x73, 0x35,
+		0x07, 0xbf, 0xb1, 0x3b, 0xbd, 0xc0, 0x52, 0x16,
+		0x7b, 0x93, 0x7e, 0xec, 0x51, 0x39, 0x19, 0x56,
+		0xe1, 0x09, 0xf1, 0x8e, 0x91, 0x89, 0xb4, 0xb8,
+		0xf3, 0x15, 0xb3, 0x99, 0x5b, 0x72, 0xda, 0xa2,
+		0xee, 0x40, 0x85, 0xb6, 0xa0, 0x21, 0x9a, 0x92,
+		0x1f, 0x9a, 0x1c, 0x8e, 0x61, 0xb9, 0x3e, 0x95,
+		0x51, 0x00,
+	}
+	genX = fp.Elt{
+		0x66, 0xbd, 0xe5, 0xc2, 0x31, 0x7e, 0x7e, 0xf9,
+		0x9b, 0x42, 0x6a, 0x85, 0xc1, 0xb3, 0x48, 0x33,
+		0xde, 0xa8, 0xff, 0xa2, 0x27, 0xc1, 0x1d, 0xfe,
+		0x28, 0x59, 0xe7, 0xef, 0x77, 0x5e, 0x4b, 0xa1,
+		0xba, 0x3d, 0x4d, 0x6b, 0x60, 0xaf, 0x28, 0xf8,
+		0x21, 0xb5, 0x3f, 0x05, 0x39, 0x81, 0x64, 0x9c,
+		0x42, 0xb4, 0x95, 0x23, 0x66, 0xcb, 0x3e, 0x9e,
+		0xcd, 0xe9, 0x04, 0x04, 0xb7, 0x06, 0x8e, 0x85,
+		0xc6, 0x00,
+	}
+	genY = fp.Elt{
+		0x50, 0x66, 0xd1, 0x9f, 0x76, 0x94, 0xbe, 0x88,
+		0x40, 0xc2, 0x72, 0xa2, 0x86, 0x70, 0x3c, 0x35,
+		0x61, 0x07, 0xad, 0x3f, 0x01, 0xb9, 0x50, 0xc5,
+		0x40, 0x26, 0xf4, 0x5e, 0x99, 0x72, 0xee, 0x97,
+		0x2c, 0x66, 0x3e, 0x27, 0x17, 0xbd, 0xaf, 0x17,
+		0x68, 0x44, 0x9b, 0x57, 0x49, 0x44, 0xf5, 0x98,
+		0xd9, 0x1b, 0x7d, 0x2c, 0xb4, 0x5f, 0x8a, 0x5c,
+		0x04, 0xc0, 0x3b, 0x9a, 0x78, 0x6a, 0x29, 0x39,
+		0x18, 0x01,
+	}
+)
+
+// orderWords is the group order in 64-bit little-endian words; a scalar
+// plus the order still fits the 576 bits.
+var orderWords = [9]uint64{
+	0xbb6fb71e91386409, 0x3bb5c9b8899c47ae, 0x7fcc0148f709a5d0,
+	0x51868783bf2f966b, 0xfffffffffffffffa, 0xffffffffffffffff,
+	0xffffffffffffffff, 0xffffffffffffffff, 0x00000000000001ff,
+}
+
+const (
+	// multWindow is the width of the signed fixed windows used for
+	// scalar multiplication.
+	multWindow = 5
+	// multDigits is the number of window digits: the scalar can grow to
+	// k plus the order, 529 bits, and the recoding needs one digit of
+	// headroom so the final remainder fits a table entry.
+	multDigits = 107
+)
+
+// Generator returns the coordinates of the base point.
+func Generator() (x, y [SizeField]byte) {
+	eltToBytes(&x, &genX)
+	eltToBytes(&y, &genY)
+
+	return x, y
+}
+
+// ScalarBaseMult computes [k]G for the generator G, in constant time
+// with respect to k. When k is a multiple of the group order the result
+// is the identity, encoded as (0,0), which is not a curve point.
+func ScalarBaseMult(k *[SizeScalar]byte) (x, y [SizeField]byte) {
+	ap := affinePoint{x: genX, y: genY}
+	P := scalarMultPoint(k, &ap)
+	pointToBytes(&x, &y, P)
+
+	return x, y
+}
+
+// ScalarMult computes [k]P for the point P = (px,py), in constant time
+// with respect to k. It returns ErrInvalidPoint when the coordinates are
+// not canonical or the point is not on the curve. When k is a multiple
+// of the group order the result is the identity, encoded as (0,0).
+func ScalarMult(k *[SizeScalar]byte, px, py *[SizeField]byte) (x, y [SizeField]byte, err error) {
+	var ap affinePoint
+	if !eltFromBytes(&ap.x, px) || !eltFromBytes(&ap.y, py) ||
+		!isOnCurve(&ap.x, &ap.y) {
+		return x, y, ErrInvalidPoint
+	}
+	P := scalarMultPoint(k, &ap)
+	pointToBytes(&x, &y, P)
+
+	return x, y, nil
+}
+
+// scalarMultPoint computes [k]P with a left-to-right signed fixed-window
+// ladder over the odd multiples of P, normalized to affine once so every
+// table hit is a mixed addition. Every iteration does the same five
+// doublings, one table scan, and one addition, regardless of k.
+func scalarMultPoint(k *[SizeScalar]byte, ap *affinePoint) *jacobianPoint {
+	digits := recodeScalar(k)
+	tab := batchToAffine(ap.oddMultiples(multWindow))
+
+	var Q jacobianPoint
+	var T affinePoint
+	selectAffine(&T, tab, uint(digits[multDigits-1])>>1)
+	Q.x, Q.y = T.x, T.y
+	fp.SetOne(&Q.z)
+	for i := multDigits - 2; i >= 0; i-- {
+		for j := 0; j < multWindow; j++ {
+			Q.double()
+		}
+		d := digits[i]
+		s := uint32(d) >> 31
+		abs := uint32((d ^ (d >> 31)) - (d >> 31))
+		selectAffine(&T, tab, uint(abs)>>1)
+		cnegAffine(&T, uint(s))
+		Q.mixadd(&Q, &T)
+	}
+
+	return &Q
+}
+
+// recodeScalar splits a scalar into odd signed digits of multWindow bits,
+// k = sum digits[i]*2^(multWindow*i), in constant time. The scalar is
+// first made odd by conditionally adding the (odd) group order, which
+// does not change the multiple; every digit is then odd and bounded by
+// 2^multWindow-1 in absolute value, so it indexes a table of odd
+// multiples, and the final digit is positive.
+func recodeScalar(k *[SizeScalar]byte) [multDigits]int32 {
+	var w [9]uint64
+	for i := 0; i < SizeScalar; i++ {
+		w[i/8] |= uint64(k[SizeScalar-1-i]) << (8 * (i % 8))
+	}
+	even := (w[0] & 1) - 1
+	var c uint64
+	for i := range w {
+		w[i], c = bits.Add64(w[i], orderWords[i]&even, c)
+	}
+
+	const mask = 1<<(multWindow+1) - 1
+	var digits [multDigits]int32
+	for i := 0; i < multDigits-1; i++ {
+		d := int64(w[0]&mask) - (1 << multWindow)
+		digits[i] = int32(d)
+		// w = (w - d) >> multWindow, with d sign-extended.
+		var b uint64
+		w[0], b = bits.Sub64(w[0], uint64(d), 0)
+		for j := 1; j < 9; j++ {
+			w[j], b = bits.Sub64(w[j], uint64(d>>63), b)
+		}
+		for j := 0; j < 8; j++ {
+			w[j] = w[j]>>multWindow | w[j+1]<<(64-multWindow)
+		}
+		w[8] >>= multWindow
+	}
+	digits[multDigits-1] = int32(w[0])
+
+	return digits
+}
+
+// selectAffine copies tab[idx] into P, scanning the whole table.
+func selectAffine(P *affinePoint, tab []affinePoint, idx uint) {
+	for i := range tab {
+		c := uint((uint64(uint(i)^idx) - 1) >> 63)
+		fp.Cmov(&P.x, &tab[i].x, c)
+		fp.Cmov(&P.y, &tab[i].y, c)
+	}
+}
+
+// cnegAffine negates P when c is 1, in constant time.
+func cnegAffine(P *affinePoint, c uint) {
+	var t fp.Elt
+	fp.Neg(&t, &P.y)
+	fp.Cmov(&P.y, &t, c)
+}
+
+// eltFromBytes converts a big-endian coordinate to the field encoding,
+// reporting false for non-canonical values.
+func eltFromBytes(z *fp.Elt, b *[SizeField]byte) bool {
+	for i := 0; i < SizeField; i++ {
+		z[i] = b[SizeField-1-i]
+	}
+	t := *z
+	fp.Modp(&t)
+
+	return t == *z
+}
+
+// eltToBytes converts a field element to a big-endian coordinate.
+func eltToBytes(b *[SizeField]byte, z *fp.Elt) {
+	t := *z
+	fp.Modp(&t)
+	for i := 0; i < SizeField; i++ {
+		b[SizeField-1-i] = t[i]
+	}
+}
+
+// isOnCurve reports whether y^2 = x^3 - 3x + b.
+func isOnCurve(x, y *fp.Elt) bool {
+	var l, r, t fp.Elt
+	fp.Sqr(&l, y)
+	fp.Sqr(&r, x)
+	fp.Mul(&r, &r, x)
+	fp.Add(&t, x, x)
+	fp.Add(&t, &t, x)
+	fp.Sub(&r, &r, &t)
+	fp.Add(&r, &r, &curveB)
+	fp.Sub(&l, &l, &r)
+
+	return fp.IsZero(&l)
+}
+
+// pointToBytes encodes P in affine coordinates, the identity as (0,0).
+// The conversion is constant time: the exponentiation-based field
+// inversion maps the zero denominator of the identity to zero.
+func pointToBytes(x, y *[SizeField]byte, P *jacobianPoint) {
+	var zi, t fp.Elt
+	fp.Inv(&zi, &P.z)
+	fp.Sqr(&t, &zi)
+	var ax, ay fp.Elt
+	fp.Mul(&ax, &P.x, &t)
+	fp.Mul(&t, &t, &zi)
+	fp.Mul(&ay, &P.y, &t)
+	eltToBytes(x, &ax)
+	eltToBytes(y, &ay)
+}
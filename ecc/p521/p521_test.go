@@ -0,0 +1,107 @@
+package p521_test
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/cloudflare/circl/ecc/p521"
+)
+
+// The standard library carries its own P-521, which makes a convenient
+// independent reference for randomized cross-checking.
+var ref = elliptic.P521()
+
+func toBytes(t *testing.T, v *big.Int) (b [p521.SizeField]byte) {
+	t.Helper()
+	v.FillBytes(b[:])
+	return
+}
+
+func TestGenerator(t *testing.T) {
+	x, y := p521.Generator()
+	if x != toBytes(t, ref.Params().Gx) || y != toBytes(t, ref.Params().Gy) {
+		t.Fatal("generator mismatch")
+	}
+}
+
+func TestScalarBaseMult(t *testing.T) {
+	for i := 0; i < 32; i++ {
+		var k [p521.SizeScalar]byte
+		if _, err := rand.Read(k[:]); err != nil {
+			t.Fatal(err)
+		}
+
+		x, y := p521.ScalarBaseMult(&k)
+
+		kRed := new(big.Int).SetBytes(k[:])
+		kRed.Mod(kRed, ref.Params().N)
+		wantX, wantY := ref.ScalarBaseMult(kRed.Bytes())
+		if x != toBytes(t, wantX) || y != toBytes(t, wantY) {
+			t.Fatalf("k=%x\ngot:  (%x, %x)\nwant: (%x, %x)", k, x, y, wantX, wantY)
+		}
+	}
+}
+
+func TestScalarMult(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		var k, l [p521.SizeScalar]byte
+		if _, err := rand.Read(k[:]); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rand.Read(l[:]); err != nil {
+			t.Fatal(err)
+		}
+
+		px, py := p521.ScalarBaseMult(&k)
+		x, y, err := p521.ScalarMult(&l, &px, &py)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lRed := new(big.Int).SetBytes(l[:])
+		lRed.Mod(lRed, ref.Params().N)
+		wantX, wantY := ref.ScalarMult(
+			new(big.Int).SetBytes(px[:]), new(big.Int).SetBytes(py[:]),
+			lRed.Bytes())
+		if x != toBytes(t, wantX) || y != toBytes(t, wantY) {
+			t.Fatalf("iteration %d: scalar multiplication disagrees", i)
+		}
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	// k = 0 and k = order give the identity, encoded as (0,0).
+	var zero [p521.SizeScalar]byte
+	x, y := p521.ScalarBaseMult(&zero)
+	if x != ([p521.SizeField]byte{}) || y != ([p521.SizeField]byte{}) {
+		t.Fatal("[0]G is not the identity")
+	}
+
+	var order [p521.SizeScalar]byte
+	ref.Params().N.FillBytes(order[:])
+	x, y = p521.ScalarBaseMult(&order)
+	if x != ([p521.SizeField]byte{}) || y != ([p521.SizeField]byte{}) {
+		t.Fatal("[order]G is not the identity")
+	}
+}
+
+func TestInvalidPoint(t *testing.T) {
+	var k [p521.SizeScalar]byte
+	k[p521.SizeScalar-1] = 1
+
+	x, y := p521.Generator()
+	y[p521.SizeField-1] ^= 1
+	if _, _, err := p521.ScalarMult(&k, &x, &y); err == nil {
+		t.Fatal("off-curve point accepted")
+	}
+
+	// Non-canonical coordinate (the field prime).
+	var p [p521.SizeField]byte
+	ref.Params().P.FillBytes(p[:])
+	_, y = p521.Generator()
+	if _, _, err := p521.ScalarMult(&k, &p, &y); err == nil {
+		t.Fatal("non-canonical coordinate accepted")
+	}
+}
@@ -0,0 +1,177 @@
+package p521
+
+// Jacobian point arithmetic, portable across architectures. The group
+// law is branchless: an addition evaluates both the generic chord
+// formulas and the tangent formulas and selects among them, the
+// operands, and the identity with field-level conditional moves, so the
+// exceptional cases cost no secret-dependent time.
+
+import fp "github.com/cloudflare/circl/math/fp521"
+
+type affinePoint struct{ x, y fp.Elt }
+
+type jacobianPoint struct{ x, y, z fp.Elt }
+
+func (ap *affinePoint) toJacobian() *jacobianPoint {
+	var P jacobianPoint
+	P.x, P.y = ap.x, ap.y
+	fp.SetOne(&P.z)
+
+	return &P
+}
+
+// oddMultiples returns [1P, 3P, ..., (2^n-1)P] in Jacobian coordinates.
+func (ap *affinePoint) oddMultiples(n uint) []jacobianPoint {
+	tab := make([]jacobianPoint, 1<<(n-1))
+	tab[0] = *ap.toJacobian()
+	P2 := *ap.toJacobian()
+	P2.double()
+	for i := 1; i < len(tab); i++ {
+		tab[i].add(&tab[i-1], &P2)
+	}
+
+	return tab
+}
+
+// batchToAffine normalizes a batch of points with one shared inversion,
+// mapping the identity to (0,0). The points must be public: the batch
+// inversion branches on zero denominators.
+func batchToAffine(ps []jacobianPoint) []affinePoint {
+	zs := make([]fp.Elt, len(ps))
+	for i := range ps {
+		zs[i] = ps[i].z
+	}
+	fp.InvBatch(zs)
+	out := make([]affinePoint, len(ps))
+	for i := range ps {
+		var t fp.Elt
+		fp.Sqr(&t, &zs[i])
+		fp.Mul(&out[i].x, &ps[i].x, &t)
+		fp.Mul(&t, &t, &zs[i])
+		fp.Mul(&out[i].y, &ps[i].y, &t)
+	}
+
+	return out
+}
+
+func (P *jacobianPoint) isZero() bool { return fp.IsZero(&P.z) }
+
+func (P *jacobianPoint) neg() { fp.Neg(&P.y, &P.y) }
+
+func (P *jacobianPoint) toAffine() *affinePoint {
+	var zi, t fp.Elt
+	fp.Inv(&zi, &P.z)
+	fp.Sqr(&t, &zi)
+	var ap affinePoint
+	fp.Mul(&ap.x, &P.x, &t)
+	fp.Mul(&t, &t, &zi)
+	fp.Mul(&ap.y, &P.y, &t)
+
+	return &ap
+}
+
+// double doubles P in place (dbl-2001-b for a = -3). The formulas map
+// the identity to itself, so no case distinction is needed.
+func (P *jacobianPoint) double() {
+	var delta, gamma, beta, alpha, t1, t2 fp.Elt
+	fp.Sqr(&delta, &P.z)
+	fp.Sqr(&gamma, &P.y)
+	fp.Mul(&beta, &P.x, &gamma)
+	fp.Sub(&t1, &P.x, &delta)
+	fp.Add(&t2, &P.x, &delta)
+	fp.Mul(&alpha, &t1, &t2)
+	fp.Add(&t1, &alpha, &alpha)
+	fp.Add(&alpha, &t1, &alpha)
+
+	var x3, y3, z3, b8 fp.Elt
+	fp.Sqr(&x3, &alpha)
+	fp.Add(&b8, &beta, &beta)
+	fp.Add(&b8, &b8, &b8)
+	fp.Add(&t1, &b8, &b8)
+	fp.Sub(&x3, &x3, &t1)
+	fp.Add(&z3, &P.y, &P.z)
+	fp.Sqr(&z3, &z3)
+	fp.Sub(&z3, &z3, &gamma)
+	fp.Sub(&z3, &z3, &delta)
+	fp.Sub(&t1, &b8, &x3)
+	fp.Mul(&y3, &alpha, &t1)
+	fp.Sqr(&t2, &gamma)
+	fp.Add(&t2, &t2, &t2)
+	fp.Add(&t2, &t2, &t2)
+	fp.Add(&t2, &t2, &t2)
+	fp.Sub(&y3, &y3, &t2)
+	P.x, P.y, P.z = x3, y3, z3
+}
+
+// add sets P = Q + R (add-2007-bl), selecting the tangent case, the
+// identity, and the pass-through cases without branching.
+func (P *jacobianPoint) add(Q, R *jacobianPoint) {
+	var z1z1, z2z2, u1, u2, s1, s2 fp.Elt
+	fp.Sqr(&z1z1, &Q.z)
+	fp.Sqr(&z2z2, &R.z)
+	fp.Mul(&u1, &Q.x, &z2z2)
+	fp.Mul(&u2, &R.x, &z1z1)
+	fp.Mul(&s1, &Q.y, &z2z2)
+	fp.Mul(&s1, &s1, &R.z)
+	fp.Mul(&s2, &R.y, &z1z1)
+	fp.Mul(&s2, &s2, &Q.z)
+
+	var h, r fp.Elt
+	fp.Sub(&h, &u2, &u1)
+	fp.Sub(&r, &s2, &s1)
+
+	var h2, h3, v fp.Elt
+	fp.Sqr(&h2, &h)
+	fp.Mul(&h3, &h2, &h)
+	fp.Mul(&v, &u1, &h2)
+
+	var res, t jacobianPoint
+	var w fp.Elt
+	fp.Sqr(&res.x, &r)
+	fp.Sub(&res.x, &res.x, &h3)
+	fp.Add(&w, &v, &v)
+	fp.Sub(&res.x, &res.x, &w)
+	fp.Sub(&w, &v, &res.x)
+	fp.Mul(&res.y, &r, &w)
+	fp.Mul(&w, &s1, &h3)
+	fp.Sub(&res.y, &res.y, &w)
+	fp.Mul(&res.z, &Q.z, &R.z)
+	fp.Mul(&res.z, &res.z, &h)
+
+	zQ := ctIsZero(&Q.z)
+	zR := ctIsZero(&R.z)
+	hz := ctIsZero(&h)
+	rz := ctIsZero(&r)
+
+	// Tangent case: equal x-coordinates with equal y is a doubling,
+	// with opposite y the identity (res.z is already zero then, and the
+	// other coordinates are irrelevant).
+	t = *Q
+	t.double()
+	cmovJacobian(&res, &t, hz&rz)
+	cmovJacobian(&res, R, zQ)
+	cmovJacobian(&res, Q, zR)
+	*P = res
+}
+
+// mixadd sets P = Q + R for affine R, which is never the identity.
+func (P *jacobianPoint) mixadd(Q *jacobianPoint, R *affinePoint) {
+	P.add(Q, R.toJacobian())
+}
+
+// cmovJacobian copies Q into P when c is 1, in constant time.
+func cmovJacobian(P, Q *jacobianPoint, c uint) {
+	fp.Cmov(&P.x, &Q.x, c)
+	fp.Cmov(&P.y, &Q.y, c)
+	fp.Cmov(&P.z, &Q.z, c)
+}
+
+// ctIsZero returns 1 when z is zero, in constant time.
+func ctIsZero(z *fp.Elt) uint {
+	var b byte
+	for i := range z {
+		b |= z[i]
+	}
+
+	return uint((uint32(b) - 1) >> 31)
+}
@@ -0,0 +1,140 @@
+package ristretto255
+
+// Internal arithmetic on edwards25519, -x^2 + y^2 = 1 + d*x^2*y^2, in
+// extended coordinates (X:Y:Z:T) with X*Y = Z*T. The addition formulas
+// are complete on this curve, so scalar multiplication is a plain
+// fixed-window loop with no exceptional cases: the table select covers
+// the zero digit by including the identity.
+
+import fp "github.com/cloudflare/circl/math/fp25519"
+
+// edPoint is a curve point in extended coordinates.
+type edPoint struct {
+	x, y, z, t fp.Elt
+}
+
+// paramD is the curve coefficient d = -121665/121666.
+var paramD = fp.Elt{
+	0xa3, 0x78, 0x59, 0x13, 0xca, 0x4d, 0xeb, 0x75,
+	0xab, 0xd8, 0x41, 0x41, 0x4d, 0x0a, 0x70, 0x00,
+	0x98, 0xe8, 0x79, 0x77, 0x79, 0x40, 0xc7, 0x8c,
+	0x73, 0xfe, 0x6f, 0x2b, 0xee, 0x6c, 0x03, 0x52,
+}
+
+// Generator coordinates: y = 4/5 and the even x recovered from it.
+var (
+	genX = fp.Elt{
+		0x1a, 0xd5, 0x25, 0x8f, 0x60, 0x2d, 0x56, 0xc9,
+		0xb2, 0xa7, 0x25, 0x95, 0x60, 0xc7, 0x2c, 0x69,
+		0x5c, 0xdc, 0xd6, 0xfd, 0x31, 0xe2, 0xa4, 0xc0,
+		0xfe, 0x53, 0x6e, 0xcd, 0xd3, 0x36, 0x69, 0x21,
+	}
+	genY = fp.Elt{
+		0x58, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+		0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+		0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+		0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+	}
+)
+
+// paramD2 is 2*d, used by the addition formulas; set in init.
+var paramD2 fp.Elt
+
+func init() { fp.Add(&paramD2, &paramD, &paramD) }
+
+func (P *edPoint) setIdentity() {
+	*P = edPoint{}
+	fp.SetOne(&P.y)
+	fp.SetOne(&P.z)
+}
+
+func (P *edPoint) setGenerator() {
+	P.x = genX
+	P.y = genY
+	fp.SetOne(&P.z)
+	fp.Mul(&P.t, &P.x, &P.y)
+}
+
+// add sets P = Q + R (add-2008-hwcd-3, complete for a = -1).
+func (P *edPoint) add(Q, R *edPoint) {
+	var a, b, c, d, e, f, g, h fp.Elt
+	fp.Sub(&a, &Q.y, &Q.x)
+	fp.Sub(&b, &R.y, &R.x)
+	fp.Mul(&a, &a, &b) // A = (Y1-X1)*(Y2-X2)
+	fp.Add(&b, &Q.y, &Q.x)
+	fp.Add(&c, &R.y, &R.x)
+	fp.Mul(&b, &b, &c) // B = (Y1+X1)*(Y2+X2)
+	fp.Mul(&c, &Q.t, &R.t)
+	fp.Mul(&c, &c, &paramD2) // C = 2d*T1*T2
+	fp.Mul(&d, &Q.z, &R.z)
+	fp.Add(&d, &d, &d) // D = 2*Z1*Z2
+	fp.Sub(&e, &b, &a)
+	fp.Sub(&f, &d, &c)
+	fp.Add(&g, &d, &c)
+	fp.Add(&h, &b, &a)
+	fp.Mul(&P.x, &e, &f)
+	fp.Mul(&P.y, &g, &h)
+	fp.Mul(&P.t, &e, &h)
+	fp.Mul(&P.z, &f, &g)
+}
+
+// double sets P = 2*Q (dbl-2008-hwcd, complete for a = -1).
+func (P *edPoint) double(Q *edPoint) {
+	var a, b, c, e, f, g, h fp.Elt
+	fp.Sqr(&a, &Q.x)
+	fp.Sqr(&b, &Q.y)
+	fp.Sqr(&c, &Q.z)
+	fp.Add(&c, &c, &c)
+	fp.Add(&h, &a, &b)
+	fp.Add(&e, &Q.x, &Q.y)
+	fp.Sqr(&e, &e)
+	fp.Sub(&e, &h, &e)
+	fp.Sub(&g, &a, &b)
+	fp.Add(&f, &c, &g)
+	fp.Mul(&P.x, &e, &f)
+	fp.Mul(&P.y, &g, &h)
+	fp.Mul(&P.t, &e, &h)
+	fp.Mul(&P.z, &f, &g)
+}
+
+// neg sets P = -Q.
+func (P *edPoint) neg(Q *edPoint) {
+	*P = *Q
+	fp.Neg(&P.x, &Q.x)
+	fp.Neg(&P.t, &Q.t)
+}
+
+// cmov copies Q into P when c is 1, in constant time.
+func (P *edPoint) cmov(Q *edPoint, c uint) {
+	fp.Cmov(&P.x, &Q.x, c)
+	fp.Cmov(&P.y, &Q.y, c)
+	fp.Cmov(&P.z, &Q.z, c)
+	fp.Cmov(&P.t, &Q.t, c)
+}
+
+// scalarMult sets P = [k]Q by a 4-bit fixed-window loop. Completeness
+// of the group law lets the table start at the identity, so every
+// window, including zero, is handled by one constant-time select.
+func (P *edPoint) scalarMult(k *Scalar, Q *edPoint) {
+	var tab [16]edPoint
+	tab[0].setIdentity()
+	tab[1] = *Q
+	for i := 2; i < 16; i++ {
+		tab[i].add(&tab[i-1], Q)
+	}
+
+	var R, T edPoint
+	R.setIdentity()
+	for i := 63; i >= 0; i-- {
+		for j := 0; j < 4; j++ {
+			R.double(&R)
+		}
+		d := uint(k.w[i/16]>>(4*(i%16))) & 0xf
+		for j := range tab {
+			c := uint((uint64(uint(j)^d) - 1) >> 63)
+			T.cmov(&tab[j], c)
+		}
+		R.add(&R, &T)
+	}
+	*P = R
+}
@@ -0,0 +1,372 @@
+// Package ristretto255 implements the prime-order group ristretto255
+// specified in RFC 9496.
+//
+// The group is a quotient of edwards25519 that removes the cofactor:
+// encodings are canonical 32-byte strings, equality is well defined,
+// and no small-subgroup or torsion handling leaks into protocols built
+// on top. Elements support the usual group operations plus a map from
+// uniform bytes (Elligator, RFC 9496, Section 4.3.4) and hashing with
+// domain separation; scalars are integers modulo the prime group order.
+// All operations on secret data run in constant time.
+package ristretto255
+
+import (
+	"crypto"
+	_ "crypto/sha512" // linked for crypto.SHA512
+	"errors"
+
+	"github.com/cloudflare/circl/expander"
+	fp "github.com/cloudflare/circl/math/fp25519"
+)
+
+// Sizes of the byte encodings used by this package.
+const (
+	// SizeElement is the length of an encoded element.
+	SizeElement = 32
+	// SizeScalar is the length of an encoded scalar.
+	SizeScalar = 32
+)
+
+// ErrInvalidElement is returned when bytes are not the canonical
+// encoding of a group element.
+var ErrInvalidElement = errors.New("ristretto255: invalid element encoding")
+
+// sqrtM1 is 2^((p-1)/4), a square root of -1.
+var sqrtM1 = fp.Elt{
+	0xb0, 0xa0, 0x0e, 0x4a, 0x27, 0x1b, 0xee, 0xc4,
+	0x78, 0xe4, 0x2f, 0xad, 0x06, 0x18, 0x43, 0x2f,
+	0xa7, 0xd7, 0xfb, 0x3d, 0x99, 0x00, 0x4d, 0x2b,
+	0x0b, 0xdf, 0xc1, 0x4f, 0x80, 0x24, 0x83, 0x2b,
+}
+
+// Derived constants of RFC 9496: both square roots come out of the
+// same radicand since a*d - 1 = a - d = -(1+d) for a = -1.
+var invSqrtAMinusD, sqrtADMinusOne, oneMinusDSq, dMinusOneSq fp.Elt
+
+func init() {
+	var one, r fp.Elt
+	fp.SetOne(&one)
+	fp.Add(&r, &one, &paramD)
+	fp.Neg(&r, &r) // a - d
+	sqrtRatio(&sqrtADMinusOne, &r, &one)
+	sqrtRatio(&invSqrtAMinusD, &one, &r)
+	fp.Sqr(&oneMinusDSq, &paramD)
+	fp.Sub(&oneMinusDSq, &one, &oneMinusDSq)
+	fp.Sub(&dMinusOneSq, &paramD, &one)
+	fp.Sqr(&dMinusOneSq, &dMinusOneSq)
+}
+
+// Element is a group element; the zero value is not valid, use the Set
+// methods or Decode to initialize one.
+type Element struct {
+	p edPoint
+}
+
+// SetIdentity sets e to the neutral element.
+func (e *Element) SetIdentity() *Element {
+	e.p.setIdentity()
+
+	return e
+}
+
+// SetGenerator sets e to the canonical generator.
+func (e *Element) SetGenerator() *Element {
+	e.p.setGenerator()
+
+	return e
+}
+
+// Add sets e = a + b.
+func (e *Element) Add(a, b *Element) *Element {
+	e.p.add(&a.p, &b.p)
+
+	return e
+}
+
+// Double sets e = a + a.
+func (e *Element) Double(a *Element) *Element {
+	e.p.double(&a.p)
+
+	return e
+}
+
+// Neg sets e = -a.
+func (e *Element) Neg(a *Element) *Element {
+	e.p.neg(&a.p)
+
+	return e
+}
+
+// ScalarMult sets e = [k]a, in constant time.
+func (e *Element) ScalarMult(k *Scalar, a *Element) *Element {
+	e.p.scalarMult(k, &a.p)
+
+	return e
+}
+
+// ScalarBaseMult sets e = [k]G for the canonical generator G, in
+// constant time.
+func (e *Element) ScalarBaseMult(k *Scalar) *Element {
+	var g edPoint
+	g.setGenerator()
+	e.p.scalarMult(k, &g)
+
+	return e
+}
+
+// IsEqual reports whether e and a are the same group element, in
+// constant time. Representatives differing by torsion compare equal.
+func (e *Element) IsEqual(a *Element) bool {
+	var l, r fp.Elt
+	fp.Mul(&l, &e.p.x, &a.p.y)
+	fp.Mul(&r, &e.p.y, &a.p.x)
+	fp.Sub(&l, &l, &r)
+	eq := fpCtIsZero(&l)
+	fp.Mul(&l, &e.p.y, &a.p.y)
+	fp.Mul(&r, &e.p.x, &a.p.x)
+	fp.Sub(&l, &l, &r)
+	eq |= fpCtIsZero(&l)
+
+	return eq == 1
+}
+
+// IsIdentity reports whether e is the neutral element.
+func (e *Element) IsIdentity() bool {
+	return fpCtIsZero(&e.p.x)|fpCtIsZero(&e.p.y) == 1
+}
+
+// Encode returns the canonical encoding of e (RFC 9496, Section 4.3.2).
+func (e *Element) Encode() (b [SizeElement]byte) {
+	var one, u1, u2, t, invsqrt fp.Elt
+	fp.SetOne(&one)
+	fp.Add(&u1, &e.p.z, &e.p.y)
+	fp.Sub(&t, &e.p.z, &e.p.y)
+	fp.Mul(&u1, &u1, &t)
+	fp.Mul(&u2, &e.p.x, &e.p.y)
+
+	fp.Sqr(&t, &u2)
+	fp.Mul(&t, &t, &u1)
+	sqrtRatio(&invsqrt, &one, &t)
+
+	var den1, den2, zInv fp.Elt
+	fp.Mul(&den1, &invsqrt, &u1)
+	fp.Mul(&den2, &invsqrt, &u2)
+	fp.Mul(&zInv, &den1, &den2)
+	fp.Mul(&zInv, &zInv, &e.p.t)
+
+	var ix, iy, enchDen fp.Elt
+	fp.Mul(&ix, &e.p.x, &sqrtM1)
+	fp.Mul(&iy, &e.p.y, &sqrtM1)
+	fp.Mul(&enchDen, &den1, &invSqrtAMinusD)
+
+	fp.Mul(&t, &e.p.t, &zInv)
+	rotate := fpIsNegative(&t)
+
+	x, y, denInv := e.p.x, e.p.y, den2
+	fp.Cmov(&x, &iy, rotate)
+	fp.Cmov(&y, &ix, rotate)
+	fp.Cmov(&denInv, &enchDen, rotate)
+
+	fp.Mul(&t, &x, &zInv)
+	var ny fp.Elt
+	fp.Neg(&ny, &y)
+	fp.Cmov(&y, &ny, fpIsNegative(&t))
+
+	var s fp.Elt
+	fp.Sub(&s, &e.p.z, &y)
+	fp.Mul(&s, &s, &denInv)
+	fpAbs(&s)
+	fp.Modp(&s)
+
+	return s
+}
+
+// Decode sets e from its canonical encoding (RFC 9496, Section 4.3.1),
+// returning ErrInvalidElement for any string that Encode cannot output.
+func (e *Element) Decode(b *[SizeElement]byte) error {
+	var s fp.Elt
+	if !fpFromBytes(&s, b) || fpIsNegative(&s) == 1 {
+		return ErrInvalidElement
+	}
+
+	var one, ss, u1, u2, u2Sqr, v, t fp.Elt
+	fp.SetOne(&one)
+	fp.Sqr(&ss, &s)
+	fp.Sub(&u1, &one, &ss)
+	fp.Add(&u2, &one, &ss)
+	fp.Sqr(&u2Sqr, &u2)
+
+	fp.Sqr(&v, &u1)
+	fp.Mul(&v, &v, &paramD)
+	fp.Neg(&v, &v)
+	fp.Sub(&v, &v, &u2Sqr) // v = -(d*u1^2) - u2^2
+
+	var invsqrt fp.Elt
+	fp.Mul(&t, &v, &u2Sqr)
+	wasSquare := sqrtRatio(&invsqrt, &one, &t)
+
+	var denX, denY fp.Elt
+	fp.Mul(&denX, &invsqrt, &u2)
+	fp.Mul(&denY, &invsqrt, &denX)
+	fp.Mul(&denY, &denY, &v)
+
+	var P edPoint
+	fp.Add(&t, &s, &s)
+	fp.Mul(&P.x, &t, &denX)
+	fpAbs(&P.x)
+	fp.Mul(&P.y, &u1, &denY)
+	fp.SetOne(&P.z)
+	fp.Mul(&P.t, &P.x, &P.y)
+
+	if wasSquare == 0 || fpIsNegative(&P.t) == 1 || fpCtIsZero(&P.y) == 1 {
+		return ErrInvalidElement
+	}
+	e.p = P
+
+	return nil
+}
+
+// FromUniformBytes sets e from 64 uniform bytes by mapping each half
+// through the Elligator encoding and adding the results (RFC 9496,
+// Section 4.3.4); the output is uniform when the input is.
+func (e *Element) FromUniformBytes(b *[2 * SizeElement]byte) *Element {
+	var r0, r1 fp.Elt
+	copy(r0[:], b[:SizeElement])
+	copy(r1[:], b[SizeElement:])
+	r0[SizeElement-1] &= 0x7f
+	r1[SizeElement-1] &= 0x7f
+	fp.Modp(&r0)
+	fp.Modp(&r1)
+
+	var P0, P1 edPoint
+	mapToEdwards(&P0, &r0)
+	mapToEdwards(&P1, &r1)
+	e.p.add(&P0, &P1)
+
+	return e
+}
+
+// HashToElement hashes msg into the group with domain separation tag
+// dst, indifferentiable from a random oracle.
+func HashToElement(msg, dst []byte) *Element {
+	exp := expander.NewExpanderMD(crypto.SHA512, dst)
+	var b [2 * SizeElement]byte
+	copy(b[:], exp.Expand(msg, 2*SizeElement))
+
+	return new(Element).FromUniformBytes(&b)
+}
+
+// HashToScalar hashes msg to a uniform scalar with domain separation
+// tag dst.
+func HashToScalar(msg, dst []byte) *Scalar {
+	exp := expander.NewExpanderMD(crypto.SHA512, dst)
+	var b [2 * SizeScalar]byte
+	copy(b[:], exp.Expand(msg, 2*SizeScalar))
+
+	return new(Scalar).SetUniformBytes(&b)
+}
+
+// mapToEdwards is the MAP function of RFC 9496, Section 4.3.4, sending
+// a field element to a curve point, in constant time.
+func mapToEdwards(P *edPoint, r0 *fp.Elt) {
+	var one, r, u, v, t fp.Elt
+	fp.SetOne(&one)
+	fp.Sqr(&r, r0)
+	fp.Mul(&r, &r, &sqrtM1)
+	fp.Add(&u, &r, &one)
+	fp.Mul(&u, &u, &oneMinusDSq)
+
+	fp.Mul(&v, &r, &paramD)
+	fp.Add(&v, &v, &one)
+	fp.Neg(&v, &v) // -1 - r*d
+	fp.Add(&t, &r, &paramD)
+	fp.Mul(&v, &v, &t)
+
+	var s fp.Elt
+	wasSquare := sqrtRatio(&s, &u, &v)
+
+	var sPrime fp.Elt
+	fp.Mul(&sPrime, &s, r0)
+	fpAbs(&sPrime)
+	fp.Neg(&sPrime, &sPrime)
+	fp.Cmov(&s, &sPrime, 1-wasSquare)
+
+	var c, nOne fp.Elt
+	fp.Neg(&nOne, &one)
+	c = r
+	fp.Cmov(&c, &nOne, wasSquare)
+
+	var n fp.Elt
+	fp.Sub(&n, &r, &one)
+	fp.Mul(&n, &n, &c)
+	fp.Mul(&n, &n, &dMinusOneSq)
+	fp.Sub(&n, &n, &v)
+
+	var w0, w1, w2, w3 fp.Elt
+	fp.Mul(&w0, &s, &v)
+	fp.Add(&w0, &w0, &w0)
+	fp.Mul(&w1, &n, &sqrtADMinusOne)
+	fp.Sqr(&t, &s)
+	fp.Sub(&w2, &one, &t)
+	fp.Add(&w3, &one, &t)
+
+	fp.Mul(&P.x, &w0, &w3)
+	fp.Mul(&P.y, &w2, &w1)
+	fp.Mul(&P.z, &w1, &w3)
+	fp.Mul(&P.t, &w0, &w2)
+}
+
+// sqrtRatio is the SQRT_RATIO_M1 function of RFC 9496, Section 4.2: it
+// sets z to the nonnegative root of u/v when u/v is square, returning
+// 1, and to the nonnegative root of i*u/v otherwise, returning 0.
+func sqrtRatio(z, u, v *fp.Elt) uint {
+	ok := fp.SqrtRatio(z, u, v)
+	fpAbs(z)
+	// A zero divisor with nonzero numerator has no root of either kind.
+	ok &^= fpCtIsZero(v) &^ fpCtIsZero(u)
+
+	return ok
+}
+
+// fpCtIsZero returns 1 when x represents zero, in constant time.
+func fpCtIsZero(x *fp.Elt) uint {
+	t := *x
+	fp.Modp(&t)
+	var v byte
+	for _, b := range t {
+		v |= b
+	}
+
+	return uint((uint32(v) - 1) >> 31)
+}
+
+// fpIsNegative returns the sign bit of x, the parity of its canonical
+// encoding.
+func fpIsNegative(x *fp.Elt) uint {
+	t := *x
+	fp.Modp(&t)
+
+	return uint(t[0] & 1)
+}
+
+// fpAbs replaces z by its negative when z is negative, in constant
+// time.
+func fpAbs(z *fp.Elt) {
+	var n fp.Elt
+	fp.Neg(&n, z)
+	fp.Cmov(z, &n, fpIsNegative(z))
+}
+
+// fpFromBytes loads a canonical field element, rejecting values of p or
+// more.
+func fpFromBytes(z *fp.Elt, b *[fp.Size]byte) bool {
+	t := *(*fp.Elt)(b)
+	u := t
+	fp.Modp(&u)
+	if u != t {
+		return false
+	}
+	*z = t
+
+	return true
+}
@@ -0,0 +1,166 @@
+package ristretto255_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/ecc/ristretto255"
+)
+
+func randomScalar(t *testing.T) *ristretto255.Scalar {
+	t.Helper()
+	var buf [2 * ristretto255.SizeScalar]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		t.Fatal(err)
+	}
+	return new(ristretto255.Scalar).SetUniformBytes(&buf)
+}
+
+// Small multiples of the generator, from RFC 9496, Appendix A.1.
+var generatorMultiples = []string{
+	"0000000000000000000000000000000000000000000000000000000000000000",
+	"e2f2ae0a6abc4e71a884a961c500515f58e30b6aa582dd8db6a65945e08d2d76",
+	"6a493210f7499cd17fecb510ae0cea23a110e8d5b901f8acadd3095c73a3b919",
+	"94741f5d5d52755ece4f23f044ee27d5d1ea1e2bd196b462166b16152a9d0259",
+	"da80862773358b466ffadfe0b3293ab3d9fd53c5ea6c955358f568322daf6a57",
+	"e882b131016b52c1d3337080187cf768423efccbb517bb495ab812c4160ff44e",
+}
+
+// Invalid element encodings, from RFC 9496, Appendix A.2: non-canonical
+// field elements, negative components, and non-square cases.
+var invalidEncodings = []string{
+	"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	"0100000000000000000000000000000000000000000000000000000000000000",
+	"01ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+}
+
+func TestGeneratorMultiples(t *testing.T) {
+	var g, acc ristretto255.Element
+	g.SetGenerator()
+	acc.SetIdentity()
+	for i, want := range generatorMultiples {
+		enc := acc.Encode()
+		if hex.EncodeToString(enc[:]) != want {
+			t.Errorf("%d*B mismatch\ngot:  %x\nwant: %s", i, enc, want)
+		}
+
+		var dec ristretto255.Element
+		if err := dec.Decode(&enc); err != nil {
+			t.Errorf("%d*B does not decode: %v", i, err)
+		} else if !dec.IsEqual(&acc) {
+			t.Errorf("%d*B does not roundtrip", i)
+		}
+		acc.Add(&acc, &g)
+	}
+}
+
+func TestInvalidEncodings(t *testing.T) {
+	for _, s := range invalidEncodings {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf [ristretto255.SizeElement]byte
+		copy(buf[:], b)
+		var e ristretto255.Element
+		if e.Decode(&buf) == nil {
+			t.Errorf("invalid encoding accepted: %s", s)
+		}
+	}
+}
+
+func TestGroupLaws(t *testing.T) {
+	k := randomScalar(t)
+	l := randomScalar(t)
+
+	var p, q, r ristretto255.Element
+	p.ScalarBaseMult(k)
+
+	// ScalarMult by the generator agrees with ScalarBaseMult.
+	q.SetGenerator()
+	q.ScalarMult(k, &q)
+	if !q.IsEqual(&p) {
+		t.Fatal("ScalarMult and ScalarBaseMult disagree")
+	}
+
+	// Doubling is adding to itself.
+	q.Double(&p)
+	r.Add(&p, &p)
+	if !q.IsEqual(&r) {
+		t.Fatal("Double and Add disagree")
+	}
+
+	// P - P is the identity.
+	q.Neg(&p)
+	r.Add(&p, &q)
+	if !r.IsIdentity() {
+		t.Fatal("P - P is not the identity")
+	}
+
+	// [k+l]B == [k]B + [l]B.
+	var sum ristretto255.Scalar
+	sum.Add(k, l)
+	q.ScalarBaseMult(&sum)
+	var lB ristretto255.Element
+	lB.ScalarBaseMult(l)
+	r.Add(&p, &lB)
+	if !q.IsEqual(&r) {
+		t.Fatal("scalar addition does not distribute")
+	}
+}
+
+func TestHashToElement(t *testing.T) {
+	var buf [2 * ristretto255.SizeElement]byte
+	copy(buf[:], "ristretto255 uniform bytes test.ristretto255 uniform bytes test")
+	var a, b ristretto255.Element
+	a.FromUniformBytes(&buf)
+	b.FromUniformBytes(&buf)
+	if !a.IsEqual(&b) {
+		t.Fatal("FromUniformBytes is not deterministic")
+	}
+	if a.IsIdentity() {
+		t.Fatal("uniform map gives the identity")
+	}
+
+	h1 := ristretto255.HashToElement([]byte("msg"), []byte("dst"))
+	h2 := ristretto255.HashToElement([]byte("msg"), []byte("dst"))
+	if !h1.IsEqual(h2) {
+		t.Fatal("HashToElement is not deterministic")
+	}
+	h3 := ristretto255.HashToElement([]byte("msg"), []byte("other dst"))
+	if h1.IsEqual(h3) {
+		t.Fatal("domain separation is ignored")
+	}
+
+	s1 := ristretto255.HashToScalar([]byte("msg"), []byte("dst"))
+	s2 := ristretto255.HashToScalar([]byte("msg"), []byte("dst"))
+	if !s1.IsEqual(s2) {
+		t.Fatal("HashToScalar is not deterministic")
+	}
+}
+
+func TestScalar(t *testing.T) {
+	k := randomScalar(t)
+	var ki, one ristretto255.Scalar
+	ki.Inv(k)
+	ki.Mul(&ki, k)
+	one.SetUint64(1)
+	if !ki.IsEqual(&one) {
+		t.Fatal("k * k^-1 is not one")
+	}
+
+	enc, err := k.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var k2 ristretto255.Scalar
+	if err = k2.UnmarshalBinary(enc); err != nil {
+		t.Fatal(err)
+	}
+	if !k2.IsEqual(k) {
+		t.Fatal("scalar does not roundtrip")
+	}
+}
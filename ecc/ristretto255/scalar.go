@@ -0,0 +1,229 @@
+package ristretto255
+
+// Arithmetic modulo the group order, the prime
+// l = 2^252 + 27742317777372353535851937790883648493. Scalars are kept
+// canonical in four little-endian limbs; multiplication runs through
+// Montgomery reduction, with the domain conversion folded into a second
+// Montgomery multiplication by R^2, so no value is ever stored in the
+// Montgomery domain. Encodings are little-endian, following RFC 9496.
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// ErrInvalidScalar is returned when bytes do not encode a canonical
+// scalar.
+var ErrInvalidScalar = errors.New("ristretto255: invalid scalar encoding")
+
+// orderLimbs is the group order l in little-endian limbs.
+var orderLimbs = [4]uint64{
+	0x5812631a5cf5d3ed, 0x14def9dea2f79cd6,
+	0x0000000000000000, 0x1000000000000000,
+}
+
+// Montgomery constants for R = 2^256: ordInv = -l^-1 mod 2^64, by
+// Newton iteration, and ordRR = R^2 mod l, by doubling; both are
+// derived in init.
+var (
+	ordInv uint64
+	ordRR  [4]uint64
+)
+
+func init() {
+	inv := orderLimbs[0]
+	for i := 0; i < 5; i++ {
+		inv *= 2 - orderLimbs[0]*inv
+	}
+	ordInv = -inv
+
+	ordRR[0] = 1
+	for i := 0; i < 512; i++ {
+		var c uint64
+		for j := range ordRR {
+			ordRR[j], c = bits.Add64(ordRR[j], ordRR[j], c)
+		}
+		scCondSub(&ordRR, c)
+	}
+}
+
+// Scalar is an integer modulo the group order, always canonical.
+type Scalar struct {
+	w [4]uint64
+}
+
+// scCondSub subtracts l when the value is at least l, or unconditionally
+// when the carry flag of a preceding addition is set, in constant time.
+func scCondSub(w *[4]uint64, carry uint64) {
+	var d [4]uint64
+	var b uint64
+	for i := range w {
+		d[i], b = bits.Sub64(w[i], orderLimbs[i], b)
+	}
+	m := (b &^ carry) - 1
+	for i := range w {
+		w[i] = w[i]&^m | d[i]&m
+	}
+}
+
+// SetUint64 sets s to v.
+func (s *Scalar) SetUint64(v uint64) *Scalar {
+	s.w = [4]uint64{v}
+
+	return s
+}
+
+// IsZero reports whether s is zero.
+func (s *Scalar) IsZero() bool { return s.w == [4]uint64{} }
+
+// IsEqual reports whether s and a are the same scalar.
+func (s *Scalar) IsEqual(a *Scalar) bool { return s.w == a.w }
+
+// Add sets s = a + b.
+func (s *Scalar) Add(a, b *Scalar) *Scalar {
+	var c uint64
+	for i := range s.w {
+		s.w[i], c = bits.Add64(a.w[i], b.w[i], c)
+	}
+	scCondSub(&s.w, c)
+
+	return s
+}
+
+// Sub sets s = a - b.
+func (s *Scalar) Sub(a, b *Scalar) *Scalar {
+	var bo uint64
+	for i := range s.w {
+		s.w[i], bo = bits.Sub64(a.w[i], b.w[i], bo)
+	}
+	m := -bo
+	var c uint64
+	for i := range s.w {
+		s.w[i], c = bits.Add64(s.w[i], orderLimbs[i]&m, c)
+	}
+
+	return s
+}
+
+// Neg sets s = -a.
+func (s *Scalar) Neg(a *Scalar) *Scalar {
+	var z Scalar
+
+	return s.Sub(&z, a)
+}
+
+// Mul sets s = a * b: the conversion factors of two back-to-back
+// Montgomery multiplications cancel against R^2.
+func (s *Scalar) Mul(a, b *Scalar) *Scalar {
+	var t [4]uint64
+	montMul(&t, &a.w, &b.w)
+	montMul(&s.w, &t, &ordRR)
+
+	return s
+}
+
+// Sqr sets s = a^2.
+func (s *Scalar) Sqr(a *Scalar) *Scalar { return s.Mul(a, a) }
+
+// Inv sets s = 1/a for nonzero a, and to zero otherwise, by Fermat's
+// little theorem.
+func (s *Scalar) Inv(a *Scalar) *Scalar {
+	e := orderLimbs
+	e[0] -= 2
+	x := *a
+	var acc Scalar
+	acc.SetUint64(1)
+	for i := 252; i >= 0; i-- {
+		acc.Sqr(&acc)
+		if e[i/64]>>(i%64)&1 == 1 {
+			acc.Mul(&acc, &x)
+		}
+	}
+	*s = acc
+
+	return s
+}
+
+// montMul computes z = x*y*R^-1 mod l by coarsely integrated operand
+// scanning, in constant time.
+func montMul(z, x, y *[4]uint64) {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var c, c1 uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			lo, c1 = bits.Add64(lo, t[j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, c, 0)
+			hi += c1
+			t[j] = lo
+			c = hi
+		}
+		t[4], c = bits.Add64(t[4], c, 0)
+		t[5] = c
+
+		m := t[0] * ordInv
+		c = 0
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(m, orderLimbs[j])
+			lo, c1 = bits.Add64(lo, t[j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, c, 0)
+			hi += c1
+			t[j] = lo
+			c = hi
+		}
+		t[4], c = bits.Add64(t[4], c, 0)
+		t[5] += c
+		t[0], t[1], t[2], t[3], t[4], t[5] = t[1], t[2], t[3], t[4], t[5], 0
+	}
+	copy(z[:], t[:4])
+	scCondSub(z, t[4])
+}
+
+// SetUniformBytes sets s to the 64 little-endian bytes of b reduced
+// modulo l, suitable for deriving scalars from hash output.
+func (s *Scalar) SetUniformBytes(b *[2 * SizeScalar]byte) *Scalar {
+	var lo, hi [4]uint64
+	for i := 0; i < 32; i++ {
+		lo[i/8] |= uint64(b[i]) << (8 * (i % 8))
+		hi[i/8] |= uint64(b[32+i]) << (8 * (i % 8))
+	}
+	// hi*2^256 mod l is one Montgomery multiplication by R^2; lo needs
+	// a round trip through the domain to come back reduced.
+	var a, t Scalar
+	montMul(&a.w, &hi, &ordRR)
+	montMul(&t.w, &lo, &ordRR)
+	one := [4]uint64{1}
+	montMul(&t.w, &t.w, &one)
+
+	return s.Add(&a, &t)
+}
+
+// Encode returns the canonical little-endian encoding of s.
+func (s *Scalar) Encode() (b [SizeScalar]byte) {
+	for i := 0; i < 32; i++ {
+		b[i] = byte(s.w[i/8] >> (8 * (i % 8)))
+	}
+
+	return b
+}
+
+// Decode sets s from its canonical little-endian encoding, returning
+// ErrInvalidScalar for values of l or more.
+func (s *Scalar) Decode(b *[SizeScalar]byte) error {
+	var w [4]uint64
+	for i := 0; i < 32; i++ {
+		w[i/8] |= uint64(b[i]) << (8 * (i % 8))
+	}
+	var bo uint64
+	for i := range w {
+		_, bo = bits.Sub64(w[i], orderLimbs[i], bo)
+	}
+	if bo == 0 {
+		return ErrInvalidScalar
+	}
+	s.w = w
+
+	return nil
+}
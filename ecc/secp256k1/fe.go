@@ -0,0 +1,197 @@
+package secp256k1
+
+// Field arithmetic over GF(p), p = 2^256 - 2^32 - 977. The field is
+// private to this package: elements are four little-endian 64-bit
+// limbs, always fully reduced, and reduction exploits that 2^256 is
+// congruent to the 33-bit constant feC.
+
+import "math/bits"
+
+type fe [4]uint64
+
+// feC is 2^256 - p.
+const feC = 0x1000003d1
+
+var pLimbs = [4]uint64{
+	0xfffffffefffffc2f, 0xffffffffffffffff,
+	0xffffffffffffffff, 0xffffffffffffffff,
+}
+
+// feCondSubP subtracts p from z when z >= p, in constant time.
+func feCondSubP(z *fe) {
+	var d fe
+	var b uint64
+	for i := range z {
+		d[i], b = bits.Sub64(z[i], pLimbs[i], b)
+	}
+	m := b - 1 // all ones when z >= p
+	for i := range z {
+		z[i] = z[i]&^m | d[i]&m
+	}
+}
+
+func feAdd(z, x, y *fe) {
+	var c uint64
+	for i := range z {
+		z[i], c = bits.Add64(x[i], y[i], c)
+	}
+	// A carry out means 2^256 was dropped; add feC back in. The sum is
+	// at most 2p, so this cannot carry again.
+	z[0], c = bits.Add64(z[0], feC*c, 0)
+	z[1], c = bits.Add64(z[1], 0, c)
+	z[2], c = bits.Add64(z[2], 0, c)
+	z[3], _ = bits.Add64(z[3], 0, c)
+	feCondSubP(z)
+}
+
+func feSub(z, x, y *fe) {
+	var b uint64
+	for i := range z {
+		z[i], b = bits.Sub64(x[i], y[i], b)
+	}
+	// A borrow means 2^256 was added; take feC back out, which cannot
+	// borrow again.
+	z[0], b = bits.Sub64(z[0], feC*b, 0)
+	z[1], b = bits.Sub64(z[1], 0, b)
+	z[2], b = bits.Sub64(z[2], 0, b)
+	z[3], _ = bits.Sub64(z[3], 0, b)
+}
+
+func feNeg(z, x *fe) {
+	zero := fe{}
+	feSub(z, &zero, x)
+}
+
+func feMul(z, x, y *fe) {
+	var w [8]uint64
+	for i := range x {
+		var c uint64
+		for j := range y {
+			hi, lo := bits.Mul64(x[i], y[j])
+			lo, cc := bits.Add64(lo, w[i+j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			w[i+j] = lo
+			c = hi
+		}
+		w[i+4] = c
+	}
+
+	// First fold: add the high half times feC to the low half.
+	var t [5]uint64
+	var c uint64
+	for i := 0; i < 4; i++ {
+		hi, lo := bits.Mul64(w[4+i], feC)
+		lo, cc := bits.Add64(lo, c, 0)
+		t[i] = lo
+		c = hi + cc
+	}
+	t[4] = c
+	var s [5]uint64
+	c = 0
+	for i := 0; i < 4; i++ {
+		s[i], c = bits.Add64(w[i], t[i], c)
+	}
+	s[4] = t[4] + c
+
+	// Second fold of the at most 34 bits above 2^256, then a final
+	// carry fix: a wrap leaves a tiny value, so adding feC once more
+	// cannot carry.
+	hi, lo := bits.Mul64(s[4], feC)
+	s[0], c = bits.Add64(s[0], lo, 0)
+	s[1], c = bits.Add64(s[1], hi, c)
+	s[2], c = bits.Add64(s[2], 0, c)
+	s[3], c = bits.Add64(s[3], 0, c)
+	s[0], c = bits.Add64(s[0], feC*c, 0)
+	s[1], c = bits.Add64(s[1], 0, c)
+	s[2], c = bits.Add64(s[2], 0, c)
+	s[3], _ = bits.Add64(s[3], 0, c)
+
+	*z = fe{s[0], s[1], s[2], s[3]}
+	feCondSubP(z)
+}
+
+func feSqr(z, x *fe) { feMul(z, x, x) }
+
+// feInv computes the Fermat inverse x^(p-2), with feInv(0) = 0.
+func feInv(z, x *fe) {
+	var e fe = fe(pLimbs)
+	e[0] -= 2
+	t := *x
+	for i := 254; i >= 0; i-- {
+		feSqr(&t, &t)
+		if e[i/64]>>(i%64)&1 == 1 {
+			feMul(&t, &t, x)
+		}
+	}
+	*z = t
+}
+
+// feInvBatch replaces every nonzero z[i] with its inverse, performing a
+// single inversion for the whole slice (Montgomery's trick). Zero
+// entries are left at zero.
+func feInvBatch(z []fe) {
+	n := len(z)
+	if n == 0 {
+		return
+	}
+	pre := make([]fe, n)
+	r := fe{1}
+	for i := range z {
+		if feIsZero(&z[i]) {
+			continue
+		}
+		pre[i] = r
+		feMul(&r, &r, &z[i])
+	}
+	feInv(&r, &r)
+	for i := n - 1; i >= 0; i-- {
+		if feIsZero(&z[i]) {
+			continue
+		}
+		t := z[i]
+		feMul(&z[i], &r, &pre[i])
+		feMul(&r, &r, &t)
+	}
+}
+
+func feIsZero(z *fe) bool { return *z == fe{} }
+
+// feCtIsZero returns 1 when z is zero, in constant time.
+func feCtIsZero(z *fe) uint {
+	v := z[0] | z[1] | z[2] | z[3]
+
+	return uint(1 - (v|-v)>>63)
+}
+
+// feCmov assigns y to x if n is 1, in constant time.
+func feCmov(x, y *fe, n uint) {
+	m := -uint64(n & 1)
+	for i := range x {
+		x[i] = x[i]&^m | y[i]&m
+	}
+}
+
+// feFromBytes converts a big-endian coordinate, reporting false for
+// non-canonical values.
+func feFromBytes(z *fe, b *[SizeField]byte) bool {
+	*z = fe{}
+	for i := 0; i < SizeField; i++ {
+		z[i/8] |= uint64(b[SizeField-1-i]) << (8 * (i % 8))
+	}
+	var d fe
+	var bo uint64
+	for i := range z {
+		d[i], bo = bits.Sub64(z[i], pLimbs[i], bo)
+	}
+
+	return bo == 1
+}
+
+// feToBytes converts a field element to a big-endian coordinate.
+func feToBytes(b *[SizeField]byte, z *fe) {
+	for i := 0; i < SizeField; i++ {
+		b[SizeField-1-i] = byte(z[i/8] >> (8 * (i % 8)))
+	}
+}
@@ -0,0 +1,152 @@
+package secp256k1
+
+// GLV endomorphism decomposition. The curve has the efficiently
+// computable endomorphism phi(x,y) = (beta*x, y) with phi(P) = [lambda]P,
+// where beta and lambda are cube roots of unity modulo p and the group
+// order n. A scalar splits as k = k1 + k2*lambda mod n with k1, k2
+// half-length, so [k]P becomes a half-size double multiplication of P
+// and phi(P). The split below is the usual rounded lattice projection,
+// with the divisions by n precomputed as 384-bit fixed-point constants,
+// and runs in constant time.
+
+import "math/bits"
+
+// orderLimbs is the group order n in little-endian limbs.
+var orderLimbs = [4]uint64{
+	0xbfd25e8cd0364141, 0xbaaedce6af48a03b,
+	0xfffffffffffffffe, 0xffffffffffffffff,
+}
+
+// beta is a cube root of unity in the field.
+var beta = fe{
+	0xc1396c28719501ee, 0x9cf0497512f58995,
+	0x6e64479eac3434e9, 0x7ae96a2b657c0710,
+}
+
+// Lattice basis (a1,b1), (a2,b2) with ai + bi*lambda = 0 mod n; b1 is
+// negative and stored by absolute value. g1 and g2 round 2^384*b2/n and
+// 2^384*(-b1)/n.
+var (
+	glvA1    = [3]uint64{0xe86c90e49284eb15, 0x3086d221a7d46bcd, 0}
+	glvB1Abs = [3]uint64{0x6f547fa90abfe4c3, 0xe4437ed6010e8828, 0}
+	glvA2    = [3]uint64{0x57c1108d9d44cfd8, 0x14ca50f7a8e2f3f6, 1}
+	glvB2    = [3]uint64{0xe86c90e49284eb15, 0x3086d221a7d46bcd, 0}
+	glvG1    = [4]uint64{
+		0xe893209a45dbb031, 0x3daa8a1471e8ca7f,
+		0xe86c90e49284eb15, 0x3086d221a7d46bcd,
+	}
+	glvG2 = [4]uint64{
+		0x1571b4ae8ac47f71, 0x221208ac9df506c6,
+		0x6f547fa90abfe4c4, 0xe4437ed6010e8828,
+	}
+)
+
+// reduceOrder reduces a 256-bit scalar modulo n with one conditional
+// subtraction, valid since n > 2^255.
+func reduceOrder(w *[4]uint64) {
+	var d [4]uint64
+	var b uint64
+	for i := range w {
+		d[i], b = bits.Sub64(w[i], orderLimbs[i], b)
+	}
+	m := b - 1
+	for i := range w {
+		w[i] = w[i]&^m | d[i]&m
+	}
+}
+
+// mulRound computes round(k*g / 2^384) as floor((k*g + 2^383) / 2^384),
+// a 129-bit result.
+func mulRound(k, g *[4]uint64) (c [3]uint64) {
+	var w [8]uint64
+	for i := range k {
+		var cc uint64
+		for j := range g {
+			hi, lo := bits.Mul64(k[i], g[j])
+			lo, c1 := bits.Add64(lo, w[i+j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, cc, 0)
+			hi += c1
+			w[i+j] = lo
+			cc = hi
+		}
+		w[i+4] = cc
+	}
+	var cr uint64
+	w[5], cr = bits.Add64(w[5], 1<<63, 0)
+	w[6], cr = bits.Add64(w[6], 0, cr)
+	w[7], cr = bits.Add64(w[7], 0, cr)
+
+	return [3]uint64{w[6], w[7], cr}
+}
+
+// mul320 multiplies a 129-bit by a 129-bit value modulo 2^320.
+func mul320(x, y *[3]uint64) (z [5]uint64) {
+	for i := range x {
+		var c uint64
+		for j := range y {
+			if i+j >= 5 {
+				break
+			}
+			hi, lo := bits.Mul64(x[i], y[j])
+			lo, c1 := bits.Add64(lo, z[i+j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, c, 0)
+			hi += c1
+			z[i+j] = lo
+			c = hi
+		}
+		if i+3 < 5 {
+			z[i+3] = c
+		}
+	}
+
+	return z
+}
+
+// glvSplit decomposes a reduced scalar as k = (-1)^s1*m1 +
+// (-1)^s2*m2*lambda mod n, with both magnitudes below 2^129, in
+// constant time.
+func glvSplit(k *[4]uint64) (s1 uint, m1 [3]uint64, s2 uint, m2 [3]uint64) {
+	c1 := mulRound(k, &glvG1)
+	c2 := mulRound(k, &glvG2)
+
+	// k1 = k - c1*a1 - c2*a2 and k2 = c1*|b1| - c2*b2, in two's
+	// complement over 320 bits.
+	t1 := mul320(&c1, &glvA1)
+	t2 := mul320(&c2, &glvA2)
+	k1 := [5]uint64{k[0], k[1], k[2], k[3], 0}
+	var b uint64
+	for i := range k1 {
+		k1[i], b = bits.Sub64(k1[i], t1[i], b)
+	}
+	b = 0
+	for i := range k1 {
+		k1[i], b = bits.Sub64(k1[i], t2[i], b)
+	}
+	u1 := mul320(&c1, &glvB1Abs)
+	u2 := mul320(&c2, &glvB2)
+	b = 0
+	for i := range u1 {
+		u1[i], b = bits.Sub64(u1[i], u2[i], b)
+	}
+
+	s1, m1 = absSplit(&k1)
+	s2, m2 = absSplit(&u1)
+
+	return
+}
+
+// absSplit extracts the sign and magnitude of a 320-bit two's
+// complement value known to fit 129 bits, in constant time.
+func absSplit(v *[5]uint64) (s uint, m [3]uint64) {
+	s = uint(v[4] >> 63)
+	mask := -uint64(s)
+	c := mask & 1
+	for i := range v {
+		v[i], c = bits.Add64(v[i]^mask, 0, c)
+	}
+	m = [3]uint64{v[0], v[1], v[2]}
+
+	return
+}
@@ -0,0 +1,162 @@
+package secp256k1
+
+// Jacobian point arithmetic for y^2 = x^3 + 7. The group law is
+// branchless: an addition evaluates both the generic chord formulas and
+// the tangent formulas and selects among them, the operands, and the
+// identity with field-level conditional moves, so the exceptional cases
+// cost no secret-dependent time.
+
+type affinePoint struct{ x, y fe }
+
+type jacobianPoint struct{ x, y, z fe }
+
+func (ap *affinePoint) toJacobian() *jacobianPoint {
+	return &jacobianPoint{x: ap.x, y: ap.y, z: fe{1}}
+}
+
+// oddMultiples returns [1P, 3P, ..., (2^n-1)P] in Jacobian coordinates.
+func (ap *affinePoint) oddMultiples(n uint) []jacobianPoint {
+	tab := make([]jacobianPoint, 1<<(n-1))
+	tab[0] = *ap.toJacobian()
+	P2 := *ap.toJacobian()
+	P2.double()
+	for i := 1; i < len(tab); i++ {
+		tab[i].add(&tab[i-1], &P2)
+	}
+
+	return tab
+}
+
+// batchToAffine normalizes a batch of points with one shared inversion,
+// mapping the identity to (0,0). The points must be public: the batch
+// inversion branches on zero denominators.
+func batchToAffine(ps []jacobianPoint) []affinePoint {
+	zs := make([]fe, len(ps))
+	for i := range ps {
+		zs[i] = ps[i].z
+	}
+	feInvBatch(zs)
+	out := make([]affinePoint, len(ps))
+	for i := range ps {
+		var t fe
+		feSqr(&t, &zs[i])
+		feMul(&out[i].x, &ps[i].x, &t)
+		feMul(&t, &t, &zs[i])
+		feMul(&out[i].y, &ps[i].y, &t)
+	}
+
+	return out
+}
+
+func (P *jacobianPoint) isZero() bool { return feIsZero(&P.z) }
+
+func (P *jacobianPoint) neg() { feNeg(&P.y, &P.y) }
+
+// double doubles P in place (dbl-2009-l for a = 0). The formulas map
+// the identity to itself, so no case distinction is needed.
+func (P *jacobianPoint) double() {
+	var a, b, c, d, e, f, t fe
+	feSqr(&a, &P.x)
+	feSqr(&b, &P.y)
+	feSqr(&c, &b)
+	feAdd(&d, &P.x, &b)
+	feSqr(&d, &d)
+	feSub(&d, &d, &a)
+	feSub(&d, &d, &c)
+	feAdd(&d, &d, &d)
+	feAdd(&e, &a, &a)
+	feAdd(&e, &e, &a)
+	feSqr(&f, &e)
+
+	var x3, y3, z3 fe
+	feSub(&x3, &f, &d)
+	feSub(&x3, &x3, &d)
+	feMul(&z3, &P.y, &P.z)
+	feAdd(&z3, &z3, &z3)
+	feSub(&t, &d, &x3)
+	feMul(&y3, &e, &t)
+	feAdd(&t, &c, &c)
+	feAdd(&t, &t, &t)
+	feAdd(&t, &t, &t)
+	feSub(&y3, &y3, &t)
+	P.x, P.y, P.z = x3, y3, z3
+}
+
+// add sets P = Q + R (add-2007-bl), selecting the tangent case, the
+// identity, and the pass-through cases without branching.
+func (P *jacobianPoint) add(Q, R *jacobianPoint) {
+	var z1z1, z2z2, u1, u2, s1, s2 fe
+	feSqr(&z1z1, &Q.z)
+	feSqr(&z2z2, &R.z)
+	feMul(&u1, &Q.x, &z2z2)
+	feMul(&u2, &R.x, &z1z1)
+	feMul(&s1, &Q.y, &z2z2)
+	feMul(&s1, &s1, &R.z)
+	feMul(&s2, &R.y, &z1z1)
+	feMul(&s2, &s2, &Q.z)
+
+	var h, r fe
+	feSub(&h, &u2, &u1)
+	feSub(&r, &s2, &s1)
+
+	var h2, h3, v fe
+	feSqr(&h2, &h)
+	feMul(&h3, &h2, &h)
+	feMul(&v, &u1, &h2)
+
+	var res, t jacobianPoint
+	var w fe
+	feSqr(&res.x, &r)
+	feSub(&res.x, &res.x, &h3)
+	feAdd(&w, &v, &v)
+	feSub(&res.x, &res.x, &w)
+	feSub(&w, &v, &res.x)
+	feMul(&res.y, &r, &w)
+	feMul(&w, &s1, &h3)
+	feSub(&res.y, &res.y, &w)
+	feMul(&res.z, &Q.z, &R.z)
+	feMul(&res.z, &res.z, &h)
+
+	zQ := feCtIsZero(&Q.z)
+	zR := feCtIsZero(&R.z)
+	hz := feCtIsZero(&h)
+	rz := feCtIsZero(&r)
+
+	// Tangent case: equal x-coordinates with equal y is a doubling,
+	// with opposite y the identity (res.z is already zero then, and the
+	// other coordinates are irrelevant).
+	t = *Q
+	t.double()
+	cmovJacobian(&res, &t, hz&rz)
+	cmovJacobian(&res, R, zQ)
+	cmovJacobian(&res, Q, zR)
+	*P = res
+}
+
+// mixadd sets P = Q + R for affine R, which is never the identity.
+func (P *jacobianPoint) mixadd(Q *jacobianPoint, R *affinePoint) {
+	P.add(Q, R.toJacobian())
+}
+
+// cmovJacobian copies Q into P when c is 1, in constant time.
+func cmovJacobian(P, Q *jacobianPoint, c uint) {
+	feCmov(&P.x, &Q.x, c)
+	feCmov(&P.y, &Q.y, c)
+	feCmov(&P.z, &Q.z, c)
+}
+
+// selectAffine copies tab[idx] into P, scanning the whole table.
+func selectAffine(P *affinePoint, tab []affinePoint, idx uint) {
+	for i := range tab {
+		c := uint((uint64(uint(i)^idx) - 1) >> 63)
+		feCmov(&P.x, &tab[i].x, c)
+		feCmov(&P.y, &tab[i].y, c)
+	}
+}
+
+// cnegAffine negates P when c is 1, in constant time.
+func cnegAffine(P *affinePoint, c uint) {
+	var t fe
+	feNeg(&t, &P.y)
+	feCmov(&P.y, &t, c)
+}
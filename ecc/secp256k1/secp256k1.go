@@ -0,0 +1,201 @@
+// Package secp256k1 provides optimized elliptic curve operations on the
+// secp256k1 curve.
+//
+// The package mirrors ecc/p384: operations work directly on fixed-size
+// big-endian byte encodings, the field and scalar arithmetic are
+// constant time, and no big.Int values appear anywhere. Scalar
+// multiplication uses the GLV endomorphism decomposition (see glv.go),
+// halving the doublings of the main ladder.
+package secp256k1
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// Sizes of the byte encodings accepted by this package.
+const (
+	// SizeField is the length of a serialized coordinate.
+	SizeField = 32
+	// SizeScalar is the length of a serialized scalar.
+	SizeScalar = 32
+)
+
+// ErrInvalidPoint is returned when an input encoding is not a point on
+// the curve.
+var ErrInvalidPoint = errors.New("secp256k1: point not on curve")
+
+// Curve constants in little-endian limbs: the coefficient b of
+// y^2 = x^3 + b and the coordinates of the generator.
+var (
+	curveB = fe{7}
+	genX   = fe{
+		0x59f2815b16f81798, 0x029bfcdb2dce28d9,
+		0x55a06295ce870b07, 0x79be667ef9dcbbac,
+	}
+	genY = fe{
+		0x9c47d08ffb10d4b8, 0xfd17b448a6855419,
+		0x5da4fbfc0e1108a8, 0x483ada7726a3c465,
+	}
+)
+
+const (
+	// multWindow is the width of the signed fixed windows used for
+	// scalar multiplication.
+	multWindow = 5
+	// multDigits is the number of window digits per GLV half: a half
+	// grows to 130 bits once made odd, and the recoding needs one digit
+	// of headroom so the final remainder fits a table entry.
+	multDigits = 27
+)
+
+// Generator returns the coordinates of the base point.
+func Generator() (x, y [SizeField]byte) {
+	feToBytes(&x, &genX)
+	feToBytes(&y, &genY)
+
+	return x, y
+}
+
+// ScalarBaseMult computes [k]G for the generator G, in constant time
+// with respect to k. When k is a multiple of the group order the result
+// is the identity, encoded as (0,0), which is not a curve point.
+func ScalarBaseMult(k *[SizeScalar]byte) (x, y [SizeField]byte) {
+	ap := affinePoint{x: genX, y: genY}
+	P := scalarMultGLV(k, &ap)
+	pointToBytes(&x, &y, P)
+
+	return x, y
+}
+
+// ScalarMult computes [k]P for the point P = (px,py), in constant time
+// with respect to k. It returns ErrInvalidPoint when the coordinates are
+// not canonical or the point is not on the curve. When k is a multiple
+// of the group order the result is the identity, encoded as (0,0).
+func ScalarMult(k *[SizeScalar]byte, px, py *[SizeField]byte) (x, y [SizeField]byte, err error) {
+	var ap affinePoint
+	if !feFromBytes(&ap.x, px) || !feFromBytes(&ap.y, py) ||
+		!isOnCurve(&ap.x, &ap.y) {
+		return x, y, ErrInvalidPoint
+	}
+	P := scalarMultGLV(k, &ap)
+	pointToBytes(&x, &y, P)
+
+	return x, y, nil
+}
+
+// scalarMultGLV computes [k]P as [k1]P + [k2]phi(P) over the GLV split
+// of k. Both halves run interleaved through one signed fixed-window
+// ladder, sharing the doublings; phi costs one multiplication per table
+// entry. Each half is made odd for the recoding by setting its low bit,
+// and the final additions take the off-by-one back out; everything is
+// constant time with respect to k.
+func scalarMultGLV(k *[SizeScalar]byte, ap *affinePoint) *jacobianPoint {
+	var w [4]uint64
+	for i := 0; i < SizeScalar; i++ {
+		w[i/8] |= uint64(k[SizeScalar-1-i]) << (8 * (i % 8))
+	}
+	reduceOrder(&w)
+	s1, m1, s2, m2 := glvSplit(&w)
+	par1 := uint(1 - m1[0]&1)
+	par2 := uint(1 - m2[0]&1)
+	m1[0] |= 1
+	m2[0] |= 1
+	d1 := recodeHalf(&m1)
+	d2 := recodeHalf(&m2)
+
+	tab1 := batchToAffine(ap.oddMultiples(multWindow))
+	tab2 := make([]affinePoint, len(tab1))
+	for i := range tab1 {
+		feMul(&tab2[i].x, &tab1[i].x, &beta)
+		tab2[i].y = tab1[i].y
+	}
+
+	var Q jacobianPoint
+	var T affinePoint
+	step := func(d int32, tab []affinePoint, sgn uint) {
+		s := uint32(d) >> 31
+		abs := uint32((d ^ (d >> 31)) - (d >> 31))
+		selectAffine(&T, tab, uint(abs)>>1)
+		cnegAffine(&T, uint(s)^sgn)
+		Q.mixadd(&Q, &T)
+	}
+	selectAffine(&T, tab1, uint(d1[multDigits-1])>>1)
+	cnegAffine(&T, s1)
+	Q.x, Q.y = T.x, T.y
+	Q.z = fe{1}
+	step(d2[multDigits-1], tab2, s2)
+	for i := multDigits - 2; i >= 0; i-- {
+		for j := 0; j < multWindow; j++ {
+			Q.double()
+		}
+		step(d1[i], tab1, s1)
+		step(d2[i], tab2, s2)
+	}
+
+	// Undo the parity fix: subtract (-1)^si * Pi for the halves whose
+	// magnitude was rounded up, via a branchless addition of either the
+	// identity or the signed base.
+	correct := func(tab []affinePoint, sgn, par uint) {
+		T = tab[0]
+		cnegAffine(&T, sgn^1)
+		C := jacobianPoint{}
+		cmovJacobian(&C, T.toJacobian(), par)
+		Q.add(&Q, &C)
+	}
+	correct(tab1, s1, par1)
+	correct(tab2, s2, par2)
+
+	return &Q
+}
+
+// recodeHalf splits an odd value below 2^130 into odd signed digits of
+// multWindow bits, like ecc/p384's recodeScalar, in constant time.
+func recodeHalf(m *[3]uint64) [multDigits]int32 {
+	w := *m
+	const mask = 1<<(multWindow+1) - 1
+	var digits [multDigits]int32
+	for i := 0; i < multDigits-1; i++ {
+		d := int64(w[0]&mask) - (1 << multWindow)
+		digits[i] = int32(d)
+		var b uint64
+		w[0], b = bits.Sub64(w[0], uint64(d), 0)
+		for j := 1; j < 3; j++ {
+			w[j], b = bits.Sub64(w[j], uint64(d>>63), b)
+		}
+		for j := 0; j < 2; j++ {
+			w[j] = w[j]>>multWindow | w[j+1]<<(64-multWindow)
+		}
+		w[2] >>= multWindow
+	}
+	digits[multDigits-1] = int32(w[0])
+
+	return digits
+}
+
+// isOnCurve reports whether y^2 = x^3 + b.
+func isOnCurve(x, y *fe) bool {
+	var l, r fe
+	feSqr(&l, y)
+	feSqr(&r, x)
+	feMul(&r, &r, x)
+	feAdd(&r, &r, &curveB)
+	feSub(&l, &l, &r)
+
+	return feIsZero(&l)
+}
+
+// pointToBytes encodes P in affine coordinates, the identity as (0,0).
+// The conversion is constant time: the exponentiation-based field
+// inversion maps the zero denominator of the identity to zero.
+func pointToBytes(x, y *[SizeField]byte, P *jacobianPoint) {
+	var zi, t fe
+	feInv(&zi, &P.z)
+	feSqr(&t, &zi)
+	var ax, ay fe
+	feMul(&ax, &P.x, &t)
+	feMul(&t, &t, &zi)
+	feMul(&ay, &P.y, &t)
+	feToBytes(x, &ax)
+	feToBytes(y, &ay)
+}
@@ -0,0 +1,119 @@
+package secp256k1_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/ecc/secp256k1"
+)
+
+func coord(t *testing.T, s string) (b [secp256k1.SizeField]byte) {
+	t.Helper()
+	raw, err := hex.DecodeString(s)
+	if err != nil || len(raw) != secp256k1.SizeField {
+		t.Fatalf("bad test coordinate %s", s)
+	}
+	copy(b[:], raw)
+	return
+}
+
+// Multiples of the generator, computed with an independent
+// implementation of the curve (SEC 2, version 2.0, parameters).
+var baseMultVectors = []struct{ k, x, y string }{
+	{
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5",
+		"1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9",
+		"388f7b0f632de8140fe337e62a37f3566500a99934c2231b6cb9fd7584b8e672",
+	},
+	{
+		"00000000000000000000000000000000000000000000000000000000deadbeef",
+		"76d2fdf1302d1fa9556f4df94ec84cefba6d482e54f47c6c2a238c1baa560f0e",
+		"b754ac7e7a3e09c44184cb451a4f5fb557f32053eb015dffebb655b5cfd54d8a",
+	},
+	{ // the group order minus one, i.e. -G
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+		"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		"b7c52588d95c3b9aa25b0403f1eef75702e84bb7597aabe663b82f6f04ef2777",
+	},
+}
+
+func TestScalarBaseMult(t *testing.T) {
+	for _, v := range baseMultVectors {
+		k := coord(t, v.k)
+		wantX := coord(t, v.x)
+		wantY := coord(t, v.y)
+
+		x, y := secp256k1.ScalarBaseMult(&k)
+		if x != wantX || y != wantY {
+			t.Errorf("k=%s\ngot:  (%x, %x)\nwant: (%x, %x)", v.k, x, y, wantX, wantY)
+		}
+
+		// ScalarMult of the generator must agree.
+		gx, gy := secp256k1.Generator()
+		x, y, err := secp256k1.ScalarMult(&k, &gx, &gy)
+		if err != nil || x != wantX || y != wantY {
+			t.Errorf("k=%s: ScalarMult disagrees, err = %v", v.k, err)
+		}
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	// k = 0 and k = order give the identity, encoded as (0,0).
+	var zero, x, y [32]byte
+	x, y = secp256k1.ScalarBaseMult(&zero)
+	if x != ([32]byte{}) || y != ([32]byte{}) {
+		t.Fatal("[0]G is not the identity")
+	}
+	order := coord(t, "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	x, y = secp256k1.ScalarBaseMult(&order)
+	if x != ([32]byte{}) || y != ([32]byte{}) {
+		t.Fatal("[order]G is not the identity")
+	}
+}
+
+func TestInvalidPoint(t *testing.T) {
+	var k [32]byte
+	k[31] = 1
+
+	// Not on the curve.
+	gx, gy := secp256k1.Generator()
+	gy[31] ^= 1
+	if _, _, err := secp256k1.ScalarMult(&k, &gx, &gy); err == nil {
+		t.Fatal("off-curve point accepted")
+	}
+
+	// Non-canonical coordinate (the field prime).
+	p := coord(t, "fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f")
+	if _, _, err := secp256k1.ScalarMult(&k, &p, &gy); err == nil {
+		t.Fatal("non-canonical coordinate accepted")
+	}
+}
+
+// Scalar multiplication must be a homomorphism: [a]([b]G) = [ab]G,
+// exercising the GLV split on products of full-width scalars.
+func TestScalarMultHomomorphism(t *testing.T) {
+	a := coord(t, "8b6a1f5c33e27c9e21e3b4a8f0d5c6e7a9b8c7d6e5f40312a1b2c3d4e5f60718")
+	ab := coord(t, "c5a070137fe03d83bd235f52f09b6946490c4802e6eda0e70b06a98e6b9b717f")
+	// ab = a*b mod order, for b = 2^255 + 11, computed independently.
+	b := coord(t, "800000000000000000000000000000000000000000000000000000000000000b")
+
+	bx, by := secp256k1.ScalarBaseMult(&b)
+	x1, y1, err := secp256k1.ScalarMult(&a, &bx, &by)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x2, y2 := secp256k1.ScalarBaseMult(&ab)
+	if x1 != x2 || y1 != y2 {
+		t.Fatal("[a][b]G != [ab]G")
+	}
+}
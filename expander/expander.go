@@ -0,0 +1,81 @@
+// Package expander implements the expand_message primitives of RFC 9380,
+// which stretch an input string and a domain-separation tag (DST) into a
+// uniform byte string for hashing to curves and fields.
+package expander
+
+import (
+	"crypto"
+	_ "crypto/sha256" // linked by the common ExpanderMD instantiations
+	_ "crypto/sha512"
+)
+
+// Expander stretches an input into length uniform bytes under a fixed
+// domain-separation tag.
+type Expander interface {
+	Expand(in []byte, length uint) []byte
+}
+
+// ExpanderMD is expand_message_xmd: expansion built on a Merkle–Damgård
+// hash function.
+type ExpanderMD struct {
+	h   crypto.Hash
+	dst []byte
+}
+
+// NewExpanderMD returns an expand_message_xmd instance with the given hash
+// and domain-separation tag. Tags longer than 255 bytes are shortened as
+// section 5.3.3 of RFC 9380 prescribes.
+func NewExpanderMD(h crypto.Hash, dst []byte) *ExpanderMD {
+	if len(dst) > maxDSTLength {
+		d := h.New()
+		_, _ = d.Write([]byte(longDSTPrefix))
+		_, _ = d.Write(dst)
+		dst = d.Sum(nil)
+	}
+	return &ExpanderMD{h, append([]byte{}, dst...)}
+}
+
+const (
+	maxDSTLength  = 255
+	longDSTPrefix = "H2C-OVERSIZE-DST-"
+)
+
+// Expand returns length uniform bytes derived from in.
+func (e *ExpanderMD) Expand(in []byte, length uint) []byte {
+	h := e.h.New()
+	bLen := uint(h.Size())
+	ell := (length + bLen - 1) / bLen
+	if ell > 255 || length > 65535 {
+		panic("expander: requested too many bytes")
+	}
+	dstPrime := append(append([]byte{}, e.dst...), byte(len(e.dst)))
+	libStr := []byte{byte(length >> 8), byte(length)}
+
+	h.Reset()
+	_, _ = h.Write(make([]byte, h.BlockSize())) // Z_pad
+	_, _ = h.Write(in)
+	_, _ = h.Write(libStr)
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	bi := b0
+	out := make([]byte, 0, ell*bLen)
+	for i := uint(1); i <= ell; i++ {
+		h.Reset()
+		if i == 1 {
+			_, _ = h.Write(b0)
+		} else {
+			x := make([]byte, len(b0))
+			for j := range x {
+				x[j] = b0[j] ^ bi[j]
+			}
+			_, _ = h.Write(x)
+		}
+		_, _ = h.Write([]byte{byte(i)})
+		_, _ = h.Write(dstPrime)
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:length]
+}
@@ -0,0 +1,39 @@
+package expander
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"testing"
+)
+
+// Vectors from RFC 9380, appendix K.1 (expand_message_xmd, SHA-256,
+// DST "QUUX-V01-CS02-with-expander-SHA256-128").
+func TestExpandMessageXMD(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	for _, tc := range []struct {
+		msg  string
+		len  uint
+		want string
+	}{
+		{"", 0x20, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{"abc", 0x20, "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+		{"", 0x80,
+			"af84c27ccfd45d41914fdff5df25293e221afc53d8ad2ac06d5e3e29485dadbee0d121587713a3e0dd4d5e69e93eb7cd4f5df4" +
+				"cd103e188cf60cb02edc3edf18eda8576c412b18ffb658e3dd6ec849469b979d444cf7b26911a08e63cf31f9dcc541708d3491184472" +
+				"c2c29bb749d4286b004ceb5ee6b9a7fa5b646c993f0ced"},
+	} {
+		got := NewExpanderMD(crypto.SHA256, dst).Expand([]byte(tc.msg), tc.len)
+		want, _ := hex.DecodeString(tc.want)
+		if !bytes.Equal(got, want) {
+			t.Errorf("Expand(%q, %d) = %x, want %x", tc.msg, tc.len, got, want)
+		}
+	}
+}
+
+func TestLongDST(t *testing.T) {
+	long := bytes.Repeat([]byte("x"), 300)
+	if out := NewExpanderMD(crypto.SHA256, long).Expand([]byte("m"), 32); len(out) != 32 {
+		t.Fatalf("got %d bytes, want 32", len(out))
+	}
+}
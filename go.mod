@@ -0,0 +1,8 @@
+module github.com/cloudflare/circl
+
+go 1.21
+
+require (
+	golang.org/x/crypto v0.17.0
+	golang.org/x/sys v0.15.0
+)
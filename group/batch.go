@@ -0,0 +1,105 @@
+package group
+
+// Batch operations over the Group interface. The generic loops below
+// work for any backend; a backend that can do better — shared-window
+// multi-scalar multiplication, a single shared inversion — plugs in by
+// implementing the corresponding unexported interface, so protocol code
+// written over Group picks up the optimization without reaching into
+// curve internals.
+
+import "errors"
+
+// ErrMismatchedLengths is returned by batch operations whose slice
+// arguments do not have equal lengths.
+var ErrMismatchedLengths = errors.New("group: mismatched slice lengths")
+
+// multiScalarMulter is implemented by groups with a dedicated
+// multi-scalar multiplication. Inputs are as for MultiScalarMult.
+type multiScalarMulter interface {
+	multiScalarMult(scalars []Scalar, elements []Element) Element
+}
+
+// MultiScalarMult computes the linear combination
+//
+//	[scalars[0]]elements[0] + [scalars[1]]elements[1] + ...
+//
+// returning the identity for the empty sum and ErrMismatchedLengths
+// when the slices differ in length. Backends with a dedicated
+// multi-exponentiation are used when available; these are typically not
+// constant time, so the scalars must be public, as in batch signature
+// verification.
+func MultiScalarMult(g Group, scalars []Scalar, elements []Element) (Element, error) {
+	if len(scalars) != len(elements) {
+		return nil, ErrMismatchedLengths
+	}
+	if m, ok := g.(multiScalarMulter); ok {
+		return m.multiScalarMult(scalars, elements), nil
+	}
+	acc := g.Identity()
+	t := g.NewElement()
+	for i := range elements {
+		acc.Add(acc, t.Mul(elements[i], scalars[i]))
+	}
+
+	return acc, nil
+}
+
+// UnmarshalElements decodes and validates a batch of element encodings,
+// returning the first decoding error encountered. Every returned
+// element is a valid group element, so follow-up operations need no
+// further checks.
+func UnmarshalElements(g Group, encodings [][]byte) ([]Element, error) {
+	out := make([]Element, len(encodings))
+	for i, data := range encodings {
+		e := g.NewElement()
+		if err := e.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		out[i] = e
+	}
+
+	return out, nil
+}
+
+// batchScalarInverter is implemented by groups with a dedicated batch
+// inversion.
+type batchScalarInverter interface {
+	invScalars(scalars []Scalar)
+}
+
+// InvScalars replaces every scalar by its inverse, mapping zero to
+// zero, sharing one inversion across the batch (Montgomery's trick)
+// instead of one per scalar.
+func InvScalars(g Group, scalars []Scalar) {
+	if b, ok := g.(batchScalarInverter); ok {
+		b.invScalars(scalars)
+		return
+	}
+	n := len(scalars)
+	if n == 0 {
+		return
+	}
+	one := g.NewScalar().SetUint64(1)
+	// Prefix products, with zeros replaced by one so the chain stays
+	// invertible; the mask remembers where to put zeros back.
+	zero := make([]bool, n)
+	prod := make([]Scalar, n)
+	acc := g.NewScalar().Set(one)
+	for i, s := range scalars {
+		prod[i] = g.NewScalar().Set(acc)
+		if s.IsZero() {
+			zero[i] = true
+		} else {
+			acc.Mul(acc, s)
+		}
+	}
+	acc.Inv(acc)
+	for i := n - 1; i >= 0; i-- {
+		if zero[i] {
+			continue
+		}
+		s := g.NewScalar().Set(scalars[i])
+		scalars[i].Mul(acc, prod[i])
+		acc.Mul(acc, s)
+	}
+}
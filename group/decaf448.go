@@ -0,0 +1,253 @@
+package group
+
+import (
+	"crypto/rand"
+	"io"
+
+	d448 "github.com/cloudflare/circl/ecc/decaf448"
+)
+
+// Decaf448 is the prime-order group decaf448 (RFC 9496), backed by the
+// ecc/decaf448 package.
+var Decaf448 Group = decafGroup{}
+
+type decafGroup struct{}
+
+func (g decafGroup) String() string { return "decaf448" }
+
+func (g decafGroup) Params() *Params {
+	return &Params{
+		ElementLength:           d448.SizeElement,
+		CompressedElementLength: d448.SizeElement,
+		ScalarLength:            d448.SizeScalar,
+	}
+}
+
+func (g decafGroup) NewElement() Element {
+	return g.Identity()
+}
+
+func (g decafGroup) NewScalar() Scalar {
+	return &decafScalar{}
+}
+
+func (g decafGroup) Identity() Element {
+	var e decafElement
+	e.p.SetIdentity()
+
+	return &e
+}
+
+func (g decafGroup) Generator() Element {
+	var e decafElement
+	e.p.SetGenerator()
+
+	return &e
+}
+
+func (g decafGroup) RandomElement(rd io.Reader) Element {
+	return g.Generator().Mul(g.Generator(), g.RandomScalar(rd))
+}
+
+func (g decafGroup) RandomScalar(rd io.Reader) Scalar {
+	if rd == nil {
+		rd = rand.Reader
+	}
+	var b [2 * d448.SizeScalar]byte
+	if _, err := io.ReadFull(rd, b[:]); err != nil {
+		panic(err)
+	}
+	var s decafScalar
+	s.s.SetUniformBytes(&b)
+
+	return &s
+}
+
+func (g decafGroup) RandomNonZeroScalar(rd io.Reader) Scalar {
+	for {
+		s := g.RandomScalar(rd)
+		if !s.(*decafScalar).s.IsZero() {
+			return s
+		}
+	}
+}
+
+func (g decafGroup) HashToElement(data, dst []byte) Element {
+	return &decafElement{*d448.HashToElement(data, dst)}
+}
+
+func (g decafGroup) HashToElementNonUniform(data, dst []byte) Element {
+	return g.HashToElement(data, dst)
+}
+
+func (g decafGroup) HashToScalar(data, dst []byte) Scalar {
+	return &decafScalar{*d448.HashToScalar(data, dst)}
+}
+
+type decafElement struct {
+	p d448.Element
+}
+
+func castDecafElement(e Element) *decafElement {
+	z, ok := e.(*decafElement)
+	if !ok {
+		panic(ErrType)
+	}
+
+	return z
+}
+
+func (e *decafElement) Group() Group { return Decaf448 }
+
+func (e *decafElement) Set(a Element) Element {
+	e.p = castDecafElement(a).p
+
+	return e
+}
+
+func (e *decafElement) Copy() Element { return &decafElement{e.p} }
+
+func (e *decafElement) IsIdentity() bool { return e.p.IsIdentity() }
+
+func (e *decafElement) IsEqual(a Element) bool {
+	return e.p.IsEqual(&castDecafElement(a).p)
+}
+
+func (e *decafElement) Add(a, b Element) Element {
+	e.p.Add(&castDecafElement(a).p, &castDecafElement(b).p)
+
+	return e
+}
+
+func (e *decafElement) Dbl(a Element) Element {
+	e.p.Double(&castDecafElement(a).p)
+
+	return e
+}
+
+func (e *decafElement) Neg(a Element) Element {
+	e.p.Neg(&castDecafElement(a).p)
+
+	return e
+}
+
+func (e *decafElement) Mul(a Element, s Scalar) Element {
+	e.p.ScalarMult(&castDecafScalar(s).s, &castDecafElement(a).p)
+
+	return e
+}
+
+func (e *decafElement) MulGen(s Scalar) Element {
+	e.p.ScalarBaseMult(&castDecafScalar(s).s)
+
+	return e
+}
+
+func (e *decafElement) MarshalBinary() ([]byte, error) {
+	b := e.p.Encode()
+
+	return b[:], nil
+}
+
+// MarshalBinaryCompress is the same as MarshalBinary: decaf448
+// encodings are already minimal.
+func (e *decafElement) MarshalBinaryCompress() ([]byte, error) {
+	return e.MarshalBinary()
+}
+
+func (e *decafElement) UnmarshalBinary(data []byte) error {
+	if len(data) != d448.SizeElement {
+		return ErrInvalidPoint
+	}
+	var b [d448.SizeElement]byte
+	copy(b[:], data)
+
+	return e.p.Decode(&b)
+}
+
+type decafScalar struct {
+	s d448.Scalar
+}
+
+func castDecafScalar(s Scalar) *decafScalar {
+	z, ok := s.(*decafScalar)
+	if !ok {
+		panic(ErrType)
+	}
+
+	return z
+}
+
+func (s *decafScalar) Group() Group { return Decaf448 }
+
+func (s *decafScalar) Set(a Scalar) Scalar {
+	s.s = castDecafScalar(a).s
+
+	return s
+}
+
+func (s *decafScalar) Copy() Scalar { return &decafScalar{s.s} }
+
+func (s *decafScalar) IsZero() bool { return s.s.IsZero() }
+
+func (s *decafScalar) IsEqual(a Scalar) bool {
+	return s.s.IsEqual(&castDecafScalar(a).s)
+}
+
+func (s *decafScalar) SetUint64(v uint64) Scalar {
+	s.s.SetUint64(v)
+
+	return s
+}
+
+func (s *decafScalar) Add(a, b Scalar) Scalar {
+	s.s.Add(&castDecafScalar(a).s, &castDecafScalar(b).s)
+
+	return s
+}
+
+func (s *decafScalar) Sub(a, b Scalar) Scalar {
+	s.s.Sub(&castDecafScalar(a).s, &castDecafScalar(b).s)
+
+	return s
+}
+
+func (s *decafScalar) Mul(a, b Scalar) Scalar {
+	s.s.Mul(&castDecafScalar(a).s, &castDecafScalar(b).s)
+
+	return s
+}
+
+func (s *decafScalar) Sqr(a Scalar) Scalar {
+	s.s.Sqr(&castDecafScalar(a).s)
+
+	return s
+}
+
+func (s *decafScalar) Inv(a Scalar) Scalar {
+	s.s.Inv(&castDecafScalar(a).s)
+
+	return s
+}
+
+func (s *decafScalar) Neg(a Scalar) Scalar {
+	s.s.Neg(&castDecafScalar(a).s)
+
+	return s
+}
+
+func (s *decafScalar) MarshalBinary() ([]byte, error) {
+	b := s.s.Encode()
+
+	return b[:], nil
+}
+
+func (s *decafScalar) UnmarshalBinary(data []byte) error {
+	if len(data) != d448.SizeScalar {
+		return ErrNonCanonical
+	}
+	var b [d448.SizeScalar]byte
+	copy(b[:], data)
+
+	return s.s.Decode(&b)
+}
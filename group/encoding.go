@@ -0,0 +1,57 @@
+package group
+
+// Text codecs for every backend's elements and scalars: standard base64
+// over the compressed binary encoding, via internal/conv, so protocol
+// state embeds directly in JSON and configuration files. Decoding is as
+// strict as UnmarshalBinary — non-canonical values are rejected.
+
+import "github.com/cloudflare/circl/internal/conv"
+
+// MarshalText encodes the element's compressed binary form in base64.
+func (e *p384Element) MarshalText() ([]byte, error) {
+	data, err := e.MarshalBinaryCompress()
+	if err != nil {
+		return nil, err
+	}
+
+	return conv.MarshalText(rawBinary(data))
+}
+
+// UnmarshalText decodes an element produced by MarshalText.
+func (e *p384Element) UnmarshalText(text []byte) error { return conv.UnmarshalText(e, text) }
+
+// MarshalText encodes the scalar in base64.
+func (s *p384Scalar) MarshalText() ([]byte, error) { return conv.MarshalText(s) }
+
+// UnmarshalText decodes a scalar produced by MarshalText.
+func (s *p384Scalar) UnmarshalText(text []byte) error { return conv.UnmarshalText(s, text) }
+
+// MarshalText encodes the element in base64.
+func (e *ristrettoElement) MarshalText() ([]byte, error) { return conv.MarshalText(e) }
+
+// UnmarshalText decodes an element produced by MarshalText.
+func (e *ristrettoElement) UnmarshalText(text []byte) error { return conv.UnmarshalText(e, text) }
+
+// MarshalText encodes the scalar in base64.
+func (s *ristrettoScalar) MarshalText() ([]byte, error) { return conv.MarshalText(s) }
+
+// UnmarshalText decodes a scalar produced by MarshalText.
+func (s *ristrettoScalar) UnmarshalText(text []byte) error { return conv.UnmarshalText(s, text) }
+
+// MarshalText encodes the element in base64.
+func (e *decafElement) MarshalText() ([]byte, error) { return conv.MarshalText(e) }
+
+// UnmarshalText decodes an element produced by MarshalText.
+func (e *decafElement) UnmarshalText(text []byte) error { return conv.UnmarshalText(e, text) }
+
+// MarshalText encodes the scalar in base64.
+func (s *decafScalar) MarshalText() ([]byte, error) { return conv.MarshalText(s) }
+
+// UnmarshalText decodes a scalar produced by MarshalText.
+func (s *decafScalar) UnmarshalText(text []byte) error { return conv.UnmarshalText(s, text) }
+
+// rawBinary adapts an already-encoded byte string to
+// encoding.BinaryMarshaler for conv.MarshalText.
+type rawBinary []byte
+
+func (r rawBinary) MarshalBinary() ([]byte, error) { return r, nil }
@@ -0,0 +1,16 @@
+package group
+
+import "errors"
+
+// Shared sentinels for point and scalar decoding, returned by
+// implementations across the library so callers can branch on the failure
+// cause with errors.Is.
+var (
+	// ErrInvalidPoint is the error returned when bytes decode to a point
+	// that is not on the curve or not in the prime-order subgroup.
+	ErrInvalidPoint = errors.New("invalid point")
+	// ErrNonCanonical is the error returned when a value is valid but not
+	// in its canonical encoding, e.g. an unreduced scalar or a
+	// non-minimal point representation.
+	ErrNonCanonical = errors.New("non-canonical encoding")
+)
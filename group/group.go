@@ -0,0 +1,122 @@
+// Package group provides prime-order groups based on elliptic curves,
+// exposed through the Group interface: element and scalar arithmetic,
+// random sampling, and hashing to the group and to the scalar field per
+// RFC 9380. The interface abstracts over the concrete curves so protocol
+// code — OPRFs, zero-knowledge proofs, threshold schemes — runs unchanged
+// on any of the registered groups.
+package group
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrType is the error returned when an element or scalar of a different
+// group is passed as an operand.
+var ErrType = errors.New("group: type mismatch")
+
+// Params describes the serialized sizes of a group.
+type Params struct {
+	// ElementLength is the length in bytes of an encoded element.
+	ElementLength uint
+	// CompressedElementLength is the length in bytes of a compressed
+	// encoding; equal to ElementLength for groups without one.
+	CompressedElementLength uint
+	// ScalarLength is the length in bytes of an encoded scalar.
+	ScalarLength uint
+}
+
+// Group represents a prime-order group. Implementations are expected to
+// perform all element and scalar operations in constant time.
+type Group interface {
+	// Params returns the serialized sizes of the group.
+	Params() *Params
+	// NewElement returns a new element set to the identity.
+	NewElement() Element
+	// NewScalar returns a new scalar set to zero.
+	NewScalar() Scalar
+	// Identity returns the identity element.
+	Identity() Element
+	// Generator returns the canonical generator.
+	Generator() Element
+	// RandomElement returns a uniformly sampled element; a nil reader
+	// uses the system source.
+	RandomElement(io.Reader) Element
+	// RandomScalar returns a uniformly sampled scalar.
+	RandomScalar(io.Reader) Scalar
+	// RandomNonZeroScalar returns a uniformly sampled nonzero scalar.
+	RandomNonZeroScalar(io.Reader) Scalar
+	// HashToElement hashes data to a uniformly distributed element,
+	// domain-separated by dst, per RFC 9380.
+	HashToElement(data, dst []byte) Element
+	// HashToElementNonUniform hashes data to an element whose
+	// distribution is not necessarily uniform (encode_to_curve).
+	HashToElementNonUniform(data, dst []byte) Element
+	// HashToScalar hashes data to a uniformly distributed scalar.
+	HashToScalar(data, dst []byte) Scalar
+}
+
+// Element represents a group element. Binary methods of the form
+// z.Op(x, y) store the result in the receiver and return it, so calls
+// chain; operands of a different group panic with ErrType.
+type Element interface {
+	// Group returns the group the element belongs to.
+	Group() Group
+	// Set assigns the receiver to x.
+	Set(x Element) Element
+	// Copy returns a new element equal to the receiver.
+	Copy() Element
+	// IsIdentity reports whether the element is the identity.
+	IsIdentity() bool
+	// IsEqual reports whether the element equals x.
+	IsEqual(x Element) bool
+	// Add sets the receiver to x+y.
+	Add(x, y Element) Element
+	// Dbl sets the receiver to 2x.
+	Dbl(x Element) Element
+	// Neg sets the receiver to -x.
+	Neg(x Element) Element
+	// Mul sets the receiver to s*x.
+	Mul(x Element, s Scalar) Element
+	// MulGen sets the receiver to s*G for the group generator G.
+	MulGen(s Scalar) Element
+	// MarshalBinary encodes the element in its canonical form.
+	MarshalBinary() ([]byte, error)
+	// MarshalBinaryCompress encodes the element in its compressed form.
+	MarshalBinaryCompress() ([]byte, error)
+	// UnmarshalBinary decodes either encoding, validating membership.
+	UnmarshalBinary(data []byte) error
+}
+
+// Scalar represents an integer modulo the group order, with the same
+// receiver-result calling convention as Element.
+type Scalar interface {
+	// Group returns the group the scalar belongs to.
+	Group() Group
+	// Set assigns the receiver to x.
+	Set(x Scalar) Scalar
+	// Copy returns a new scalar equal to the receiver.
+	Copy() Scalar
+	// IsZero reports whether the scalar is zero.
+	IsZero() bool
+	// IsEqual reports whether the scalar equals x.
+	IsEqual(x Scalar) bool
+	// SetUint64 assigns the receiver to x mod the group order.
+	SetUint64(x uint64) Scalar
+	// Add sets the receiver to x+y.
+	Add(x, y Scalar) Scalar
+	// Sub sets the receiver to x-y.
+	Sub(x, y Scalar) Scalar
+	// Mul sets the receiver to x*y.
+	Mul(x, y Scalar) Scalar
+	// Sqr sets the receiver to x^2.
+	Sqr(x Scalar) Scalar
+	// Inv sets the receiver to 1/x for nonzero x.
+	Inv(x Scalar) Scalar
+	// Neg sets the receiver to -x.
+	Neg(x Scalar) Scalar
+	// MarshalBinary encodes the scalar in its canonical form.
+	MarshalBinary() ([]byte, error)
+	// UnmarshalBinary decodes a canonical scalar encoding.
+	UnmarshalBinary(data []byte) error
+}
@@ -0,0 +1,151 @@
+package group_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/group"
+)
+
+var allGroups = map[string]group.Group{
+	"ristretto255": group.Ristretto255,
+	"decaf448":     group.Decaf448,
+	"P-256":        group.P256,
+	"P-384":        group.P384,
+}
+
+// TestP256HashVectors checks HashToElement against the
+// P256_XMD:SHA-256_SSWU_RO_ test vectors of RFC 9380, Appendix J.1.1.
+func TestP256HashVectors(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_")
+	for _, v := range []struct{ msg, x, y string }{
+		{
+			"",
+			"2c15230b26dbc6fc9a37051158c95b79656e17a1a920b11394ca91c44247d3e4",
+			"8a7a74985cc5c776cdfe4b1f19884970453912e9d31528c060be9ab5c43e8415",
+		},
+		{
+			"abc",
+			"0bb8b87485551aa43ed54f009230450b492fead5f1cc91658775dac4a3388a0f",
+			"5c41b3d0731a27a7b14bc0bf0ccded2d8751f83493404c84a88e71ffd424212e",
+		},
+	} {
+		got, err := group.P256.HashToElement([]byte(v.msg), dst).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := hex.DecodeString("04" + v.x + v.y)
+		if !bytes.Equal(got, want) {
+			t.Errorf("hash of %q: got %x, want %x", v.msg, got, want)
+		}
+	}
+}
+
+func TestGroupLaws(t *testing.T) {
+	for name, g := range allGroups {
+		t.Run(name, func(t *testing.T) {
+			a := g.RandomScalar(nil)
+			b := g.RandomScalar(nil)
+
+			// (a+b)G == aG + bG
+			ab := g.NewScalar().Add(a, b)
+			left := g.NewElement().MulGen(ab)
+			right := g.NewElement().Add(g.NewElement().MulGen(a), g.NewElement().MulGen(b))
+			if !left.IsEqual(right) {
+				t.Error("addition law fails")
+			}
+
+			// a(bG) == b(aG) == (ab)G
+			bg := g.NewElement().MulGen(b)
+			abg := g.NewElement().Mul(bg, a)
+			if !abg.IsEqual(g.NewElement().MulGen(g.NewScalar().Mul(a, b))) {
+				t.Error("scalar multiplication is not associative")
+			}
+
+			// P + (-P) == identity; dbl(P) = P+P
+			p := g.RandomElement(nil)
+			if !g.NewElement().Add(p, g.NewElement().Neg(p)).IsIdentity() {
+				t.Error("P + (-P) != identity")
+			}
+			if !g.NewElement().Dbl(p).IsEqual(g.NewElement().Add(p, p)) {
+				t.Error("Dbl(P) != P+P")
+			}
+		})
+	}
+}
+
+func TestScalarField(t *testing.T) {
+	for name, g := range allGroups {
+		t.Run(name, func(t *testing.T) {
+			a := g.RandomNonZeroScalar(nil)
+			inv := g.NewScalar().Inv(a)
+			one := g.NewScalar().SetUint64(1)
+			if !g.NewScalar().Mul(a, inv).IsEqual(one) {
+				t.Error("a * 1/a != 1")
+			}
+			if !g.NewScalar().Sqr(a).IsEqual(g.NewScalar().Mul(a, a)) {
+				t.Error("Sqr(a) != a*a")
+			}
+			if !g.NewScalar().Add(a, g.NewScalar().Neg(a)).IsZero() {
+				t.Error("a + (-a) != 0")
+			}
+		})
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	for name, g := range allGroups {
+		t.Run(name, func(t *testing.T) {
+			p := g.RandomElement(nil)
+			enc, err := p.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if uint(len(enc)) != g.Params().ElementLength {
+				t.Errorf("element length %d, want %d", len(enc), g.Params().ElementLength)
+			}
+			q := g.NewElement()
+			if err := q.UnmarshalBinary(enc); err != nil || !q.IsEqual(p) {
+				t.Error("element roundtrip failed")
+			}
+
+			s := g.RandomScalar(nil)
+			senc, err := s.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			s2 := g.NewScalar()
+			if err := s2.UnmarshalBinary(senc); err != nil || !s2.IsEqual(s) {
+				t.Error("scalar roundtrip failed")
+			}
+		})
+	}
+}
+
+func TestHashToElement(t *testing.T) {
+	for name, g := range allGroups {
+		t.Run(name, func(t *testing.T) {
+			dst := []byte("group_test:" + name)
+			p := g.HashToElement([]byte("input"), dst)
+			q := g.HashToElement([]byte("input"), dst)
+			if !p.IsEqual(q) {
+				t.Error("hashing is not deterministic")
+			}
+			r := g.HashToElement([]byte("other"), dst)
+			if p.IsEqual(r) {
+				t.Error("distinct inputs hash equal")
+			}
+			enc1, _ := p.MarshalBinary()
+			enc2, _ := g.HashToElementNonUniform([]byte("input"), dst).MarshalBinary()
+			if bytes.Equal(enc1, enc2) {
+				t.Log("uniform and non-uniform encodings coincide (allowed but unexpected)")
+			}
+
+			s := g.HashToScalar([]byte("input"), dst)
+			if s.IsZero() {
+				t.Error("HashToScalar returned zero")
+			}
+		})
+	}
+}
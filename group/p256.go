@@ -0,0 +1,608 @@
+package group
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"crypto/rand"
+	_ "crypto/sha256" // linked for crypto.SHA256
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/cloudflare/circl/expander"
+)
+
+// P256 is the NIST P-256 curve as a prime-order group, backed by
+// crypto/elliptic. Elements marshal to SEC1 encodings with the identity
+// as the single byte 0x00, matching P384; scalars are integers modulo
+// the group order with constant-time arithmetic. Hashing to the curve
+// follows the P256_XMD:SHA-256_SSWU_RO_ suite of RFC 9380, evaluated in
+// variable time: hash inputs are treated as public.
+var P256 Group = p256Group{}
+
+// Sizes of the P-256 field and scalar encodings.
+const (
+	p256SizeField  = 32
+	p256SizeScalar = 32
+)
+
+// p256UniformSize is the byte length hashed or sampled per scalar or
+// field element, giving at most a 2^-128 bias after reduction (RFC 9380,
+// Section 5).
+const p256UniformSize = 48
+
+type p256Group struct{}
+
+func (g p256Group) String() string { return "P-256" }
+
+func (g p256Group) Params() *Params {
+	return &Params{
+		ElementLength:           1 + 2*p256SizeField,
+		CompressedElementLength: 1 + p256SizeField,
+		ScalarLength:            p256SizeScalar,
+	}
+}
+
+func (g p256Group) NewElement() Element {
+	return g.Identity()
+}
+
+func (g p256Group) NewScalar() Scalar {
+	return &p256Scalar{}
+}
+
+func (g p256Group) Identity() Element {
+	return &p256Element{}
+}
+
+func (g p256Group) Generator() Element {
+	var e p256Element
+	params := elliptic.P256().Params()
+	e.fromBigCoords(params.Gx, params.Gy)
+
+	return &e
+}
+
+func (g p256Group) RandomElement(rd io.Reader) Element {
+	return g.Generator().Mul(g.Generator(), g.RandomScalar(rd))
+}
+
+func (g p256Group) RandomScalar(rd io.Reader) Scalar {
+	if rd == nil {
+		rd = rand.Reader
+	}
+	var b [p256UniformSize]byte
+	if _, err := io.ReadFull(rd, b[:]); err != nil {
+		panic(err)
+	}
+	var s p256Scalar
+	s.setUniformBytes(b[:])
+
+	return &s
+}
+
+func (g p256Group) RandomNonZeroScalar(rd io.Reader) Scalar {
+	for {
+		s := g.RandomScalar(rd)
+		if !s.(*p256Scalar).IsZero() {
+			return s
+		}
+	}
+}
+
+func (g p256Group) HashToElement(data, dst []byte) Element {
+	exp := expander.NewExpanderMD(crypto.SHA256, dst)
+	b := exp.Expand(data, 2*p256UniformSize)
+	u0 := p256FieldFromBytes(b[:p256UniformSize])
+	u1 := p256FieldFromBytes(b[p256UniformSize:])
+	var q0, q1 p256Element
+	q0.fromBigCoords(p256SSWU(u0))
+	q1.fromBigCoords(p256SSWU(u1))
+
+	return q0.Add(&q0, &q1)
+}
+
+func (g p256Group) HashToElementNonUniform(data, dst []byte) Element {
+	exp := expander.NewExpanderMD(crypto.SHA256, dst)
+	u := p256FieldFromBytes(exp.Expand(data, p256UniformSize))
+	var e p256Element
+	e.fromBigCoords(p256SSWU(u))
+
+	return &e
+}
+
+func (g p256Group) HashToScalar(data, dst []byte) Scalar {
+	exp := expander.NewExpanderMD(crypto.SHA256, dst)
+	var s p256Scalar
+	s.setUniformBytes(exp.Expand(data, p256UniformSize))
+
+	return &s
+}
+
+// p256Element is an affine point in fixed-size big-endian coordinates;
+// the identity is the pair of zero strings, as in p384Element.
+type p256Element struct {
+	x, y [p256SizeField]byte
+}
+
+func castP256Element(e Element) *p256Element {
+	z, ok := e.(*p256Element)
+	if !ok {
+		panic(ErrType)
+	}
+
+	return z
+}
+
+func (e *p256Element) Group() Group { return P256 }
+
+func (e *p256Element) Set(a Element) Element {
+	*e = *castP256Element(a)
+
+	return e
+}
+
+func (e *p256Element) Copy() Element { return &p256Element{e.x, e.y} }
+
+func (e *p256Element) IsIdentity() bool {
+	return e.x == [p256SizeField]byte{} && e.y == [p256SizeField]byte{}
+}
+
+func (e *p256Element) IsEqual(a Element) bool {
+	z := castP256Element(a)
+
+	return e.x == z.x && e.y == z.y
+}
+
+func (e *p256Element) bigCoords() (x, y *big.Int) {
+	return new(big.Int).SetBytes(e.x[:]), new(big.Int).SetBytes(e.y[:])
+}
+
+func (e *p256Element) fromBigCoords(x, y *big.Int) {
+	x.FillBytes(e.x[:])
+	y.FillBytes(e.y[:])
+}
+
+// Add handles the identity and inverse cases itself: the crypto/elliptic
+// entry points only accept points on the curve.
+func (e *p256Element) Add(a, b Element) Element {
+	za, zb := castP256Element(a), castP256Element(b)
+	if za.IsIdentity() {
+		return e.Set(zb)
+	}
+	if zb.IsIdentity() {
+		return e.Set(za)
+	}
+	if za.IsEqual(zb) {
+		return e.Dbl(za)
+	}
+	if za.IsEqual(new(p256Element).Neg(zb)) {
+		*e = p256Element{}
+		return e
+	}
+	ax, ay := za.bigCoords()
+	bx, by := zb.bigCoords()
+	e.fromBigCoords(elliptic.P256().Add(ax, ay, bx, by))
+
+	return e
+}
+
+func (e *p256Element) Dbl(a Element) Element {
+	z := castP256Element(a)
+	if z.IsIdentity() {
+		*e = p256Element{}
+		return e
+	}
+	ax, ay := z.bigCoords()
+	e.fromBigCoords(elliptic.P256().Double(ax, ay))
+
+	return e
+}
+
+func (e *p256Element) Neg(a Element) Element {
+	z := castP256Element(a)
+	if z.IsIdentity() {
+		*e = p256Element{}
+		return e
+	}
+	y := new(big.Int).SetBytes(z.y[:])
+	y.Sub(elliptic.P256().Params().P, y)
+	e.x = z.x
+	y.FillBytes(e.y[:])
+
+	return e
+}
+
+func (e *p256Element) Mul(a Element, s Scalar) Element {
+	z := castP256Element(a)
+	k := castP256Scalar(s)
+	if z.IsIdentity() || k.IsZero() {
+		*e = p256Element{}
+		return e
+	}
+	ax, ay := z.bigCoords()
+	kb := k.bytes()
+	e.fromBigCoords(elliptic.P256().ScalarMult(ax, ay, kb[:]))
+
+	return e
+}
+
+func (e *p256Element) MulGen(s Scalar) Element {
+	k := castP256Scalar(s)
+	if k.IsZero() {
+		*e = p256Element{}
+		return e
+	}
+	kb := k.bytes()
+	e.fromBigCoords(elliptic.P256().ScalarBaseMult(kb[:]))
+
+	return e
+}
+
+func (e *p256Element) MarshalBinary() ([]byte, error) {
+	if e.IsIdentity() {
+		return []byte{0}, nil
+	}
+	out := make([]byte, 1+2*p256SizeField)
+	out[0] = 0x04
+	copy(out[1:], e.x[:])
+	copy(out[1+p256SizeField:], e.y[:])
+
+	return out, nil
+}
+
+func (e *p256Element) MarshalBinaryCompress() ([]byte, error) {
+	if e.IsIdentity() {
+		return []byte{0}, nil
+	}
+	out := make([]byte, 1+p256SizeField)
+	out[0] = 0x02 | e.y[p256SizeField-1]&1
+	copy(out[1:], e.x[:])
+
+	return out, nil
+}
+
+func (e *p256Element) UnmarshalBinary(data []byte) error {
+	if len(data) == 1 && data[0] == 0 {
+		*e = p256Element{}
+		return nil
+	}
+	p := elliptic.P256().Params().P
+	switch {
+	case len(data) == 1+2*p256SizeField && data[0] == 0x04:
+		x := new(big.Int).SetBytes(data[1 : 1+p256SizeField])
+		y := new(big.Int).SetBytes(data[1+p256SizeField:])
+		if x.Cmp(p) >= 0 || y.Cmp(p) >= 0 || !elliptic.P256().IsOnCurve(x, y) {
+			return ErrNonCanonical
+		}
+		e.fromBigCoords(x, y)
+	case len(data) == 1+p256SizeField && data[0]&0xfe == 0x02:
+		x := new(big.Int).SetBytes(data[1:])
+		if x.Cmp(p) >= 0 {
+			return ErrNonCanonical
+		}
+		y := p256CurveRHS(x)
+		y.Exp(y, p256SqrtExp, p)
+		if y.Bit(0) != uint(data[0]&1) {
+			y.Sub(p, y)
+		}
+		if !elliptic.P256().IsOnCurve(x, y) || y.Bit(0) != uint(data[0]&1) {
+			return ErrNonCanonical
+		}
+		e.fromBigCoords(x, y)
+	default:
+		return ErrNonCanonical
+	}
+
+	return nil
+}
+
+// Constants of the P256_XMD:SHA-256_SSWU_RO_ suite, for the curve
+// y^2 = x^3 + Ax + B with A = -3 and Z = -10, derived in init.
+var (
+	p256B       *big.Int
+	p256Z       *big.Int
+	p256SqrtExp *big.Int // (p+1)/4, as p = 3 mod 4
+	p256LegExp  *big.Int // (p-1)/2
+	p256C1      *big.Int // -B/A
+	p256C2      *big.Int // B/(Z*A)
+)
+
+func init() {
+	params := elliptic.P256().Params()
+	p := params.P
+	p256B = params.B
+	p256Z = new(big.Int).Sub(p, big.NewInt(10))
+	p256SqrtExp = new(big.Int).Rsh(new(big.Int).Add(p, big.NewInt(1)), 2)
+	p256LegExp = new(big.Int).Rsh(new(big.Int).Sub(p, big.NewInt(1)), 1)
+
+	a := new(big.Int).Sub(p, big.NewInt(3))
+	p256C1 = new(big.Int).Neg(p256B)
+	p256C1.Mul(p256C1, new(big.Int).ModInverse(a, p)).Mod(p256C1, p)
+	p256C2 = new(big.Int).Mul(p256Z, a)
+	p256C2.ModInverse(p256C2, p)
+	p256C2.Mul(p256C2, p256B).Mod(p256C2, p)
+}
+
+// p256FieldFromBytes reduces a big-endian byte string into the field.
+func p256FieldFromBytes(b []byte) *big.Int {
+	return new(big.Int).Mod(new(big.Int).SetBytes(b), elliptic.P256().Params().P)
+}
+
+// p256CurveRHS returns x^3 - 3x + B mod p.
+func p256CurveRHS(x *big.Int) *big.Int {
+	p := elliptic.P256().Params().P
+	y := new(big.Int).Mul(x, x)
+	y.Sub(y, big.NewInt(3))
+	y.Mul(y, x)
+	y.Add(y, p256B)
+
+	return y.Mod(y, p)
+}
+
+// p256SSWU is the simplified SWU map of RFC 9380, Section 6.6.2. It runs
+// in variable time.
+func p256SSWU(u *big.Int) (x, y *big.Int) {
+	p := elliptic.P256().Params().P
+	t2 := new(big.Int).Mul(u, u)
+	t2.Mul(t2, p256Z).Mod(t2, p)
+	den := new(big.Int).Mul(t2, t2)
+	den.Add(den, t2).Mod(den, p)
+
+	x = new(big.Int)
+	if den.Sign() == 0 {
+		x.Set(p256C2)
+	} else {
+		x.ModInverse(den, p)
+		x.Add(x, big.NewInt(1))
+		x.Mul(x, p256C1).Mod(x, p)
+	}
+	g := p256CurveRHS(x)
+	// The Legendre symbol of g is 0 or 1 for squares and p-1 otherwise.
+	if new(big.Int).Exp(g, p256LegExp, p).Cmp(big.NewInt(1)) > 0 {
+		// g is not a square: x = Z*u^2*x is, by the SWU trick.
+		x.Mul(x, t2).Mod(x, p)
+		g = p256CurveRHS(x)
+	}
+	y = g.Exp(g, p256SqrtExp, p)
+	if u.Bit(0) != y.Bit(0) {
+		y.Sub(p, y)
+	}
+
+	return x, y
+}
+
+// p256Order is the group order in little-endian 64-bit words.
+var p256Order = [4]uint64{
+	0xf3b9cac2fc632551, 0xbce6faada7179e84,
+	0xffffffffffffffff, 0xffffffff00000000,
+}
+
+// Montgomery constants for R = 2^256, derived in init.
+var (
+	p256OrdInv uint64
+	p256OrdRR  [4]uint64
+)
+
+func init() {
+	inv := p256Order[0]
+	for i := 0; i < 5; i++ {
+		inv *= 2 - p256Order[0]*inv
+	}
+	p256OrdInv = -inv
+
+	p256OrdRR[0] = 1
+	for i := 0; i < 512; i++ {
+		var c uint64
+		for j := range p256OrdRR {
+			p256OrdRR[j], c = bits.Add64(p256OrdRR[j], p256OrdRR[j], c)
+		}
+		p256ScCondSub(&p256OrdRR, c)
+	}
+}
+
+// p256Scalar is an integer modulo the group order, always canonical,
+// with constant-time Montgomery arithmetic.
+type p256Scalar struct {
+	w [4]uint64
+}
+
+func castP256Scalar(s Scalar) *p256Scalar {
+	z, ok := s.(*p256Scalar)
+	if !ok {
+		panic(ErrType)
+	}
+
+	return z
+}
+
+// p256ScCondSub subtracts the order when the value is at least the
+// order, or unconditionally when the carry flag of a preceding addition
+// is set, in constant time.
+func p256ScCondSub(w *[4]uint64, carry uint64) {
+	var d [4]uint64
+	var b uint64
+	for i := range w {
+		d[i], b = bits.Sub64(w[i], p256Order[i], b)
+	}
+	m := (b &^ carry) - 1
+	for i := range w {
+		w[i] = w[i]&^m | d[i]&m
+	}
+}
+
+// p256MontMul computes z = x*y*R^-1 mod the order by coarsely integrated
+// operand scanning, in constant time.
+func p256MontMul(z, x, y *[4]uint64) {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var c, c1 uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			lo, c1 = bits.Add64(lo, t[j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, c, 0)
+			hi += c1
+			t[j] = lo
+			c = hi
+		}
+		t[4], c = bits.Add64(t[4], c, 0)
+		t[5] = c
+
+		m := t[0] * p256OrdInv
+		c = 0
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(m, p256Order[j])
+			lo, c1 = bits.Add64(lo, t[j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, c, 0)
+			hi += c1
+			t[j] = lo
+			c = hi
+		}
+		t[4], c = bits.Add64(t[4], c, 0)
+		t[5] += c
+		copy(t[:5], t[1:])
+		t[5] = 0
+	}
+	copy(z[:], t[:4])
+	p256ScCondSub(z, t[4])
+}
+
+// setUniformBytes sets s to the big-endian bytes of b reduced modulo
+// the order; b must be p256UniformSize bytes.
+func (s *p256Scalar) setUniformBytes(b []byte) {
+	var lo, hi [4]uint64
+	for i, v := range b[len(b)-p256SizeScalar:] {
+		lo[3-i/8] |= uint64(v) << (56 - 8*(i%8))
+	}
+	for i, v := range b[:len(b)-p256SizeScalar] {
+		hi[1-i/8] |= uint64(v) << (56 - 8*(i%8))
+	}
+	var a, t p256Scalar
+	p256MontMul(&a.w, &hi, &p256OrdRR)
+	p256MontMul(&t.w, &lo, &p256OrdRR)
+	one := [4]uint64{1}
+	p256MontMul(&t.w, &t.w, &one)
+	s.Add(&a, &t)
+}
+
+// bytes returns the canonical big-endian encoding, the form
+// crypto/elliptic takes.
+func (s *p256Scalar) bytes() (b [p256SizeScalar]byte) {
+	for i := 0; i < p256SizeScalar; i++ {
+		b[p256SizeScalar-1-i] = byte(s.w[i/8] >> (8 * (i % 8)))
+	}
+
+	return b
+}
+
+func (s *p256Scalar) Group() Group { return P256 }
+
+func (s *p256Scalar) Set(a Scalar) Scalar {
+	s.w = castP256Scalar(a).w
+
+	return s
+}
+
+func (s *p256Scalar) Copy() Scalar { return &p256Scalar{s.w} }
+
+func (s *p256Scalar) IsZero() bool { return s.w == [4]uint64{} }
+
+func (s *p256Scalar) IsEqual(a Scalar) bool {
+	return s.w == castP256Scalar(a).w
+}
+
+func (s *p256Scalar) SetUint64(v uint64) Scalar {
+	s.w = [4]uint64{v}
+
+	return s
+}
+
+func (s *p256Scalar) Add(a, b Scalar) Scalar {
+	x, y := castP256Scalar(a), castP256Scalar(b)
+	var c uint64
+	for i := range s.w {
+		s.w[i], c = bits.Add64(x.w[i], y.w[i], c)
+	}
+	p256ScCondSub(&s.w, c)
+
+	return s
+}
+
+func (s *p256Scalar) Sub(a, b Scalar) Scalar {
+	x, y := castP256Scalar(a), castP256Scalar(b)
+	var bo uint64
+	for i := range s.w {
+		s.w[i], bo = bits.Sub64(x.w[i], y.w[i], bo)
+	}
+	m := -bo
+	var c uint64
+	for i := range s.w {
+		s.w[i], c = bits.Add64(s.w[i], p256Order[i]&m, c)
+	}
+
+	return s
+}
+
+func (s *p256Scalar) Neg(a Scalar) Scalar {
+	var z p256Scalar
+
+	return s.Sub(&z, a)
+}
+
+func (s *p256Scalar) Mul(a, b Scalar) Scalar {
+	x, y := castP256Scalar(a), castP256Scalar(b)
+	var t [4]uint64
+	p256MontMul(&t, &x.w, &y.w)
+	p256MontMul(&s.w, &t, &p256OrdRR)
+
+	return s
+}
+
+func (s *p256Scalar) Sqr(a Scalar) Scalar { return s.Mul(a, a) }
+
+// Inv sets s = 1/a for nonzero a, and to zero otherwise, by Fermat's
+// little theorem.
+func (s *p256Scalar) Inv(a Scalar) Scalar {
+	e := p256Order
+	e[0] -= 2
+	x := *castP256Scalar(a)
+	var acc p256Scalar
+	acc.SetUint64(1)
+	for i := 255; i >= 0; i-- {
+		acc.Sqr(&acc)
+		if e[i/64]>>(i%64)&1 == 1 {
+			acc.Mul(&acc, &x)
+		}
+	}
+	s.w = acc.w
+
+	return s
+}
+
+func (s *p256Scalar) MarshalBinary() ([]byte, error) {
+	b := s.bytes()
+
+	return b[:], nil
+}
+
+func (s *p256Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != p256SizeScalar {
+		return ErrNonCanonical
+	}
+	var w [4]uint64
+	for i, v := range data {
+		w[3-i/8] |= uint64(v) << (56 - 8*(i%8))
+	}
+	var bo uint64
+	for i := range w {
+		_, bo = bits.Sub64(w[i], p256Order[i], bo)
+	}
+	if bo == 0 {
+		return ErrNonCanonical
+	}
+	s.w = w
+
+	return nil
+}
@@ -0,0 +1,487 @@
+package group
+
+import (
+	"crypto"
+	"crypto/rand"
+	_ "crypto/sha512" // linked for crypto.SHA384
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/cloudflare/circl/ecc/p384"
+	"github.com/cloudflare/circl/expander"
+)
+
+// P384 is the NIST P-384 curve as a prime-order group, backed by the
+// ecc/p384 package. Elements marshal to SEC1 encodings; scalars are
+// integers modulo the group order with constant-time arithmetic.
+var P384 Group = p384Group{}
+
+// p384UniformSize is the byte length hashed or sampled per scalar,
+// giving at most a 2^-192 bias after reduction (RFC 9380, Section 5).
+const p384UniformSize = 72
+
+type p384Group struct{}
+
+func (g p384Group) String() string { return "P-384" }
+
+func (g p384Group) Params() *Params {
+	return &Params{
+		ElementLength:           p384.SizeUncompressed,
+		CompressedElementLength: p384.SizeCompressed,
+		ScalarLength:            p384.SizeScalar,
+	}
+}
+
+func (g p384Group) NewElement() Element {
+	return g.Identity()
+}
+
+func (g p384Group) NewScalar() Scalar {
+	return &p384Scalar{}
+}
+
+func (g p384Group) Identity() Element {
+	return &p384Element{}
+}
+
+func (g p384Group) Generator() Element {
+	var e p384Element
+	e.x, e.y = p384.Generator()
+
+	return &e
+}
+
+func (g p384Group) RandomElement(rd io.Reader) Element {
+	return g.Generator().Mul(g.Generator(), g.RandomScalar(rd))
+}
+
+func (g p384Group) RandomScalar(rd io.Reader) Scalar {
+	if rd == nil {
+		rd = rand.Reader
+	}
+	var b [p384UniformSize]byte
+	if _, err := io.ReadFull(rd, b[:]); err != nil {
+		panic(err)
+	}
+	var s p384Scalar
+	s.setUniformBytes(b[:])
+
+	return &s
+}
+
+func (g p384Group) RandomNonZeroScalar(rd io.Reader) Scalar {
+	for {
+		s := g.RandomScalar(rd)
+		if !s.(*p384Scalar).IsZero() {
+			return s
+		}
+	}
+}
+
+func (g p384Group) HashToElement(data, dst []byte) Element {
+	var e p384Element
+	e.x, e.y = p384.HashToCurve(data, dst)
+
+	return &e
+}
+
+func (g p384Group) HashToElementNonUniform(data, dst []byte) Element {
+	var e p384Element
+	e.x, e.y = p384.EncodeToCurve(data, dst)
+
+	return &e
+}
+
+// multiScalarMult feeds MultiScalarMult batches to the Pippenger
+// implementation of ecc/p384. Identity elements contribute nothing and
+// are filtered out, since the byte-level entry point only accepts curve
+// points.
+func (g p384Group) multiScalarMult(scalars []Scalar, elements []Element) Element {
+	ks := make([][p384.SizeScalar]byte, 0, len(scalars))
+	xs := make([][p384.SizeField]byte, 0, len(scalars))
+	ys := make([][p384.SizeField]byte, 0, len(scalars))
+	for i := range elements {
+		e := castP384Element(elements[i])
+		if e.IsIdentity() {
+			continue
+		}
+		ks = append(ks, castP384Scalar(scalars[i]).bytes())
+		xs = append(xs, e.x)
+		ys = append(ys, e.y)
+	}
+	var e p384Element
+	var err error
+	e.x, e.y, err = p384.MultiScalarMult(ks, xs, ys)
+	if err != nil {
+		panic(err)
+	}
+
+	return &e
+}
+
+func (g p384Group) HashToScalar(data, dst []byte) Scalar {
+	exp := expander.NewExpanderMD(crypto.SHA384, dst)
+	var s p384Scalar
+	s.setUniformBytes(exp.Expand(data, p384UniformSize))
+
+	return &s
+}
+
+// p384Element is an affine point in the package's fixed-size byte
+// encoding; the identity is the pair of zero strings, matching the
+// convention of ecc/p384.
+type p384Element struct {
+	x, y [p384.SizeField]byte
+}
+
+func castP384Element(e Element) *p384Element {
+	z, ok := e.(*p384Element)
+	if !ok {
+		panic(ErrType)
+	}
+
+	return z
+}
+
+func (e *p384Element) Group() Group { return P384 }
+
+func (e *p384Element) Set(a Element) Element {
+	*e = *castP384Element(a)
+
+	return e
+}
+
+func (e *p384Element) Copy() Element { return &p384Element{e.x, e.y} }
+
+func (e *p384Element) IsIdentity() bool {
+	return e.x == [p384.SizeField]byte{} && e.y == [p384.SizeField]byte{}
+}
+
+func (e *p384Element) IsEqual(a Element) bool {
+	z := castP384Element(a)
+
+	return e.x == z.x && e.y == z.y
+}
+
+// bigCoords returns the affine coordinates for the crypto/elliptic
+// adapter, the identity as (0,0).
+func (e *p384Element) bigCoords() (x, y *big.Int) {
+	return new(big.Int).SetBytes(e.x[:]), new(big.Int).SetBytes(e.y[:])
+}
+
+func (e *p384Element) fromBigCoords(x, y *big.Int) {
+	x.FillBytes(e.x[:])
+	y.FillBytes(e.y[:])
+}
+
+func (e *p384Element) Add(a, b Element) Element {
+	ax, ay := castP384Element(a).bigCoords()
+	bx, by := castP384Element(b).bigCoords()
+	e.fromBigCoords(p384.P384().Add(ax, ay, bx, by))
+
+	return e
+}
+
+func (e *p384Element) Dbl(a Element) Element {
+	ax, ay := castP384Element(a).bigCoords()
+	e.fromBigCoords(p384.P384().Double(ax, ay))
+
+	return e
+}
+
+func (e *p384Element) Neg(a Element) Element {
+	z := castP384Element(a)
+	if z.IsIdentity() {
+		*e = p384Element{}
+		return e
+	}
+	y := new(big.Int).SetBytes(z.y[:])
+	y.Sub(p384.P384().Params().P, y)
+	e.x = z.x
+	y.FillBytes(e.y[:])
+
+	return e
+}
+
+func (e *p384Element) Mul(a Element, s Scalar) Element {
+	z := castP384Element(a)
+	k := castP384Scalar(s).bytes()
+	if z.IsIdentity() {
+		*e = p384Element{}
+		return e
+	}
+	x, y, err := p384.ScalarMult(&k, &z.x, &z.y)
+	if err != nil {
+		panic(err)
+	}
+	e.x, e.y = x, y
+
+	return e
+}
+
+func (e *p384Element) MulGen(s Scalar) Element {
+	k := castP384Scalar(s).bytes()
+	e.x, e.y = p384.ScalarBaseMult(&k)
+
+	return e
+}
+
+func (e *p384Element) MarshalBinary() ([]byte, error) {
+	return p384.Marshal(&e.x, &e.y), nil
+}
+
+func (e *p384Element) MarshalBinaryCompress() ([]byte, error) {
+	return p384.MarshalCompressed(&e.x, &e.y), nil
+}
+
+func (e *p384Element) UnmarshalBinary(data []byte) error {
+	var x, y [p384.SizeField]byte
+	var err error
+	if len(data) == p384.SizeCompressed {
+		x, y, err = p384.UnmarshalCompressed(data)
+	} else {
+		x, y, err = p384.Unmarshal(data)
+	}
+	if err != nil {
+		return err
+	}
+	e.x, e.y = x, y
+
+	return nil
+}
+
+// p384Order is the group order in little-endian 64-bit words.
+var p384Order = [6]uint64{
+	0xecec196accc52973, 0x581a0db248b0a77a, 0xc7634d81f4372ddf,
+	0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff,
+}
+
+// Montgomery constants for R = 2^384, derived in init.
+var (
+	p384OrdInv uint64
+	p384OrdRR  [6]uint64
+)
+
+func init() {
+	inv := p384Order[0]
+	for i := 0; i < 5; i++ {
+		inv *= 2 - p384Order[0]*inv
+	}
+	p384OrdInv = -inv
+
+	p384OrdRR[0] = 1
+	for i := 0; i < 768; i++ {
+		var c uint64
+		for j := range p384OrdRR {
+			p384OrdRR[j], c = bits.Add64(p384OrdRR[j], p384OrdRR[j], c)
+		}
+		p384ScCondSub(&p384OrdRR, c)
+	}
+}
+
+// p384Scalar is an integer modulo the group order, always canonical,
+// with constant-time Montgomery arithmetic.
+type p384Scalar struct {
+	w [6]uint64
+}
+
+func castP384Scalar(s Scalar) *p384Scalar {
+	z, ok := s.(*p384Scalar)
+	if !ok {
+		panic(ErrType)
+	}
+
+	return z
+}
+
+// p384ScCondSub subtracts the order when the value is at least the
+// order, or unconditionally when the carry flag of a preceding addition
+// is set, in constant time.
+func p384ScCondSub(w *[6]uint64, carry uint64) {
+	var d [6]uint64
+	var b uint64
+	for i := range w {
+		d[i], b = bits.Sub64(w[i], p384Order[i], b)
+	}
+	m := (b &^ carry) - 1
+	for i := range w {
+		w[i] = w[i]&^m | d[i]&m
+	}
+}
+
+// p384MontMul computes z = x*y*R^-1 mod the order by coarsely integrated
+// operand scanning, in constant time.
+func p384MontMul(z, x, y *[6]uint64) {
+	var t [8]uint64
+	for i := 0; i < 6; i++ {
+		var c, c1 uint64
+		for j := 0; j < 6; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			lo, c1 = bits.Add64(lo, t[j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, c, 0)
+			hi += c1
+			t[j] = lo
+			c = hi
+		}
+		t[6], c = bits.Add64(t[6], c, 0)
+		t[7] = c
+
+		m := t[0] * p384OrdInv
+		c = 0
+		for j := 0; j < 6; j++ {
+			hi, lo := bits.Mul64(m, p384Order[j])
+			lo, c1 = bits.Add64(lo, t[j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, c, 0)
+			hi += c1
+			t[j] = lo
+			c = hi
+		}
+		t[6], c = bits.Add64(t[6], c, 0)
+		t[7] += c
+		copy(t[:7], t[1:])
+		t[7] = 0
+	}
+	copy(z[:], t[:6])
+	p384ScCondSub(z, t[6])
+}
+
+// setUniformBytes sets s to the big-endian bytes of b reduced modulo
+// the order; b must be p384UniformSize bytes.
+func (s *p384Scalar) setUniformBytes(b []byte) {
+	var lo, hi [6]uint64
+	for i, v := range b[len(b)-p384.SizeScalar:] {
+		lo[5-i/8] |= uint64(v) << (56 - 8*(i%8))
+	}
+	for i, v := range b[:len(b)-p384.SizeScalar] {
+		hi[2-i/8] |= uint64(v) << (56 - 8*(i%8))
+	}
+	var a, t p384Scalar
+	p384MontMul(&a.w, &hi, &p384OrdRR)
+	p384MontMul(&t.w, &lo, &p384OrdRR)
+	one := [6]uint64{1}
+	p384MontMul(&t.w, &t.w, &one)
+	s.Add(&a, &t)
+}
+
+// bytes returns the canonical big-endian encoding, the form the
+// ecc/p384 entry points take.
+func (s *p384Scalar) bytes() (b [p384.SizeScalar]byte) {
+	for i := 0; i < p384.SizeScalar; i++ {
+		b[p384.SizeScalar-1-i] = byte(s.w[i/8] >> (8 * (i % 8)))
+	}
+
+	return b
+}
+
+func (s *p384Scalar) Group() Group { return P384 }
+
+func (s *p384Scalar) Set(a Scalar) Scalar {
+	s.w = castP384Scalar(a).w
+
+	return s
+}
+
+func (s *p384Scalar) Copy() Scalar { return &p384Scalar{s.w} }
+
+func (s *p384Scalar) IsZero() bool { return s.w == [6]uint64{} }
+
+func (s *p384Scalar) IsEqual(a Scalar) bool {
+	return s.w == castP384Scalar(a).w
+}
+
+func (s *p384Scalar) SetUint64(v uint64) Scalar {
+	s.w = [6]uint64{v}
+
+	return s
+}
+
+func (s *p384Scalar) Add(a, b Scalar) Scalar {
+	x, y := castP384Scalar(a), castP384Scalar(b)
+	var c uint64
+	for i := range s.w {
+		s.w[i], c = bits.Add64(x.w[i], y.w[i], c)
+	}
+	p384ScCondSub(&s.w, c)
+
+	return s
+}
+
+func (s *p384Scalar) Sub(a, b Scalar) Scalar {
+	x, y := castP384Scalar(a), castP384Scalar(b)
+	var bo uint64
+	for i := range s.w {
+		s.w[i], bo = bits.Sub64(x.w[i], y.w[i], bo)
+	}
+	m := -bo
+	var c uint64
+	for i := range s.w {
+		s.w[i], c = bits.Add64(s.w[i], p384Order[i]&m, c)
+	}
+
+	return s
+}
+
+func (s *p384Scalar) Neg(a Scalar) Scalar {
+	var z p384Scalar
+
+	return s.Sub(&z, a)
+}
+
+func (s *p384Scalar) Mul(a, b Scalar) Scalar {
+	x, y := castP384Scalar(a), castP384Scalar(b)
+	var t [6]uint64
+	p384MontMul(&t, &x.w, &y.w)
+	p384MontMul(&s.w, &t, &p384OrdRR)
+
+	return s
+}
+
+func (s *p384Scalar) Sqr(a Scalar) Scalar { return s.Mul(a, a) }
+
+// Inv sets s = 1/a for nonzero a, and to zero otherwise, by Fermat's
+// little theorem.
+func (s *p384Scalar) Inv(a Scalar) Scalar {
+	e := p384Order
+	e[0] -= 2
+	x := *castP384Scalar(a)
+	var acc p384Scalar
+	acc.SetUint64(1)
+	for i := 383; i >= 0; i-- {
+		acc.Sqr(&acc)
+		if e[i/64]>>(i%64)&1 == 1 {
+			acc.Mul(&acc, &x)
+		}
+	}
+	s.w = acc.w
+
+	return s
+}
+
+func (s *p384Scalar) MarshalBinary() ([]byte, error) {
+	b := s.bytes()
+
+	return b[:], nil
+}
+
+func (s *p384Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != p384.SizeScalar {
+		return ErrNonCanonical
+	}
+	var w [6]uint64
+	for i, v := range data {
+		w[5-i/8] |= uint64(v) << (56 - 8*(i%8))
+	}
+	var bo uint64
+	for i := range w {
+		_, bo = bits.Sub64(w[i], p384Order[i], bo)
+	}
+	if bo == 0 {
+		return ErrNonCanonical
+	}
+	s.w = w
+
+	return nil
+}
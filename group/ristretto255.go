@@ -0,0 +1,253 @@
+package group
+
+import (
+	"crypto/rand"
+	"io"
+
+	r255 "github.com/cloudflare/circl/ecc/ristretto255"
+)
+
+// Ristretto255 is the prime-order group ristretto255 (RFC 9496),
+// backed by the ecc/ristretto255 package.
+var Ristretto255 Group = ristrettoGroup{}
+
+type ristrettoGroup struct{}
+
+func (g ristrettoGroup) String() string { return "ristretto255" }
+
+func (g ristrettoGroup) Params() *Params {
+	return &Params{
+		ElementLength:           r255.SizeElement,
+		CompressedElementLength: r255.SizeElement,
+		ScalarLength:            r255.SizeScalar,
+	}
+}
+
+func (g ristrettoGroup) NewElement() Element {
+	return g.Identity()
+}
+
+func (g ristrettoGroup) NewScalar() Scalar {
+	return &ristrettoScalar{}
+}
+
+func (g ristrettoGroup) Identity() Element {
+	var e ristrettoElement
+	e.p.SetIdentity()
+
+	return &e
+}
+
+func (g ristrettoGroup) Generator() Element {
+	var e ristrettoElement
+	e.p.SetGenerator()
+
+	return &e
+}
+
+func (g ristrettoGroup) RandomElement(rd io.Reader) Element {
+	return g.Generator().Mul(g.Generator(), g.RandomScalar(rd))
+}
+
+func (g ristrettoGroup) RandomScalar(rd io.Reader) Scalar {
+	if rd == nil {
+		rd = rand.Reader
+	}
+	var b [2 * r255.SizeScalar]byte
+	if _, err := io.ReadFull(rd, b[:]); err != nil {
+		panic(err)
+	}
+	var s ristrettoScalar
+	s.s.SetUniformBytes(&b)
+
+	return &s
+}
+
+func (g ristrettoGroup) RandomNonZeroScalar(rd io.Reader) Scalar {
+	for {
+		s := g.RandomScalar(rd)
+		if !s.(*ristrettoScalar).s.IsZero() {
+			return s
+		}
+	}
+}
+
+func (g ristrettoGroup) HashToElement(data, dst []byte) Element {
+	return &ristrettoElement{*r255.HashToElement(data, dst)}
+}
+
+func (g ristrettoGroup) HashToElementNonUniform(data, dst []byte) Element {
+	return g.HashToElement(data, dst)
+}
+
+func (g ristrettoGroup) HashToScalar(data, dst []byte) Scalar {
+	return &ristrettoScalar{*r255.HashToScalar(data, dst)}
+}
+
+type ristrettoElement struct {
+	p r255.Element
+}
+
+func castRistrettoElement(e Element) *ristrettoElement {
+	z, ok := e.(*ristrettoElement)
+	if !ok {
+		panic(ErrType)
+	}
+
+	return z
+}
+
+func (e *ristrettoElement) Group() Group { return Ristretto255 }
+
+func (e *ristrettoElement) Set(a Element) Element {
+	e.p = castRistrettoElement(a).p
+
+	return e
+}
+
+func (e *ristrettoElement) Copy() Element { return &ristrettoElement{e.p} }
+
+func (e *ristrettoElement) IsIdentity() bool { return e.p.IsIdentity() }
+
+func (e *ristrettoElement) IsEqual(a Element) bool {
+	return e.p.IsEqual(&castRistrettoElement(a).p)
+}
+
+func (e *ristrettoElement) Add(a, b Element) Element {
+	e.p.Add(&castRistrettoElement(a).p, &castRistrettoElement(b).p)
+
+	return e
+}
+
+func (e *ristrettoElement) Dbl(a Element) Element {
+	e.p.Double(&castRistrettoElement(a).p)
+
+	return e
+}
+
+func (e *ristrettoElement) Neg(a Element) Element {
+	e.p.Neg(&castRistrettoElement(a).p)
+
+	return e
+}
+
+func (e *ristrettoElement) Mul(a Element, s Scalar) Element {
+	e.p.ScalarMult(&castRistrettoScalar(s).s, &castRistrettoElement(a).p)
+
+	return e
+}
+
+func (e *ristrettoElement) MulGen(s Scalar) Element {
+	e.p.ScalarBaseMult(&castRistrettoScalar(s).s)
+
+	return e
+}
+
+func (e *ristrettoElement) MarshalBinary() ([]byte, error) {
+	b := e.p.Encode()
+
+	return b[:], nil
+}
+
+// MarshalBinaryCompress is the same as MarshalBinary: ristretto255
+// encodings are already minimal.
+func (e *ristrettoElement) MarshalBinaryCompress() ([]byte, error) {
+	return e.MarshalBinary()
+}
+
+func (e *ristrettoElement) UnmarshalBinary(data []byte) error {
+	if len(data) != r255.SizeElement {
+		return ErrInvalidPoint
+	}
+	var b [r255.SizeElement]byte
+	copy(b[:], data)
+
+	return e.p.Decode(&b)
+}
+
+type ristrettoScalar struct {
+	s r255.Scalar
+}
+
+func castRistrettoScalar(s Scalar) *ristrettoScalar {
+	z, ok := s.(*ristrettoScalar)
+	if !ok {
+		panic(ErrType)
+	}
+
+	return z
+}
+
+func (s *ristrettoScalar) Group() Group { return Ristretto255 }
+
+func (s *ristrettoScalar) Set(a Scalar) Scalar {
+	s.s = castRistrettoScalar(a).s
+
+	return s
+}
+
+func (s *ristrettoScalar) Copy() Scalar { return &ristrettoScalar{s.s} }
+
+func (s *ristrettoScalar) IsZero() bool { return s.s.IsZero() }
+
+func (s *ristrettoScalar) IsEqual(a Scalar) bool {
+	return s.s.IsEqual(&castRistrettoScalar(a).s)
+}
+
+func (s *ristrettoScalar) SetUint64(v uint64) Scalar {
+	s.s.SetUint64(v)
+
+	return s
+}
+
+func (s *ristrettoScalar) Add(a, b Scalar) Scalar {
+	s.s.Add(&castRistrettoScalar(a).s, &castRistrettoScalar(b).s)
+
+	return s
+}
+
+func (s *ristrettoScalar) Sub(a, b Scalar) Scalar {
+	s.s.Sub(&castRistrettoScalar(a).s, &castRistrettoScalar(b).s)
+
+	return s
+}
+
+func (s *ristrettoScalar) Mul(a, b Scalar) Scalar {
+	s.s.Mul(&castRistrettoScalar(a).s, &castRistrettoScalar(b).s)
+
+	return s
+}
+
+func (s *ristrettoScalar) Sqr(a Scalar) Scalar {
+	s.s.Sqr(&castRistrettoScalar(a).s)
+
+	return s
+}
+
+func (s *ristrettoScalar) Inv(a Scalar) Scalar {
+	s.s.Inv(&castRistrettoScalar(a).s)
+
+	return s
+}
+
+func (s *ristrettoScalar) Neg(a Scalar) Scalar {
+	s.s.Neg(&castRistrettoScalar(a).s)
+
+	return s
+}
+
+func (s *ristrettoScalar) MarshalBinary() ([]byte, error) {
+	b := s.s.Encode()
+
+	return b[:], nil
+}
+
+func (s *ristrettoScalar) UnmarshalBinary(data []byte) error {
+	if len(data) != r255.SizeScalar {
+		return ErrNonCanonical
+	}
+	var b [r255.SizeScalar]byte
+	copy(b[:], data)
+
+	return s.s.Decode(&b)
+}
@@ -0,0 +1,44 @@
+package haraka
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Test vectors from Appendix B of the Haraka v2 specification: the input
+// is the sequence 0x00, 0x01, ...
+func TestVectors(t *testing.T) {
+	var in512 [64]byte
+	for i := range in512 {
+		in512[i] = byte(i)
+	}
+	var got [Size]byte
+	Haraka512(&got, &in512)
+	want := "be7f723b4e80a99813b292287f306f625a6d57331cae5f34dd9277b0945be2aa"
+	if hex.EncodeToString(got[:]) != want {
+		t.Errorf("Haraka-512: got %x, want %v", got, want)
+	}
+
+	var in256 [32]byte
+	copy(in256[:], in512[:32])
+	Haraka256(&got, &in256)
+	want = "8027ccb87949774b78d0545fb72bf70c695c2a0923cbd47bba1159efbf2b2c1c"
+	if hex.EncodeToString(got[:]) != want {
+		t.Errorf("Haraka-256: got %x, want %v", got, want)
+	}
+}
+
+func BenchmarkHaraka256(b *testing.B) {
+	var in, out [32]byte
+	for i := 0; i < b.N; i++ {
+		Haraka256(&out, &in)
+	}
+}
+
+func BenchmarkHaraka512(b *testing.B) {
+	var in [64]byte
+	var out [32]byte
+	for i := 0; i < b.N; i++ {
+		Haraka512(&out, &in)
+	}
+}
@@ -0,0 +1,36 @@
+// Package conv holds small encoding helpers shared across the library.
+//
+// The text forms produced here are standard base64 over a type's binary
+// encoding, giving every type with a binary codec a matching
+// encoding.TextMarshaler and TextUnmarshaler for use in JSON, YAML, and
+// configuration files.
+package conv
+
+import (
+	"encoding"
+	"encoding/base64"
+	"errors"
+)
+
+// MarshalText encodes m's binary form in base64.
+func MarshalText(m encoding.BinaryMarshaler) ([]byte, error) {
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(out, data)
+
+	return out, nil
+}
+
+// UnmarshalText decodes base64 text into u's binary decoder.
+func UnmarshalText(u encoding.BinaryUnmarshaler, text []byte) error {
+	data := make([]byte, base64.StdEncoding.DecodedLen(len(text)))
+	n, err := base64.StdEncoding.Decode(data, text)
+	if err != nil {
+		return errors.New("malformed base64")
+	}
+
+	return u.UnmarshalBinary(data[:n])
+}
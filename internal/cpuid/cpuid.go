@@ -0,0 +1,79 @@
+// Package cpuid centralizes the runtime CPU-feature detection used to
+// select vectorized backends across the library, together with override
+// controls: the CIRCL_CPU_DISABLE environment variable (a comma-separated
+// list of feature names, or "all") and the programmatic Disable function
+// turn features off, so operators can pin a code path in production or
+// reproduce a report from a machine with a different feature set.
+//
+// Features can only be disabled, never forced on: claiming an instruction
+// set the CPU lacks would fault. The feature variables are read by the
+// dispatching packages when they initialize, so Disable is effective only
+// when called before their first use, typically from an init function or
+// a TestMain; the environment variable is applied here at startup and is
+// always early enough. The purego build tag remains the way to rule out
+// assembly entirely at compile time.
+package cpuid
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/sys/cpu"
+)
+
+// Feature flags, true when the CPU supports the feature and it has not
+// been disabled. Flags of other architectures are always false.
+var (
+	// AVX2 covers the 256-bit integer vector extension (amd64).
+	AVX2 = cpu.X86.HasAVX2
+	// AVX512 covers the F, VL, and DQ subsets used by the 512-bit
+	// backends (amd64).
+	AVX512 = cpu.X86.HasAVX512F && cpu.X86.HasAVX512VL && cpu.X86.HasAVX512DQ
+	// AVX512IFMA covers the 52-bit integer fused multiply-add subset,
+	// including the 128/256-bit forms via VL (amd64).
+	AVX512IFMA = cpu.X86.HasAVX512IFMA && cpu.X86.HasAVX512VL
+	// BMI2 covers MULX and the shift instructions of bit-manipulation
+	// set 2 (amd64).
+	BMI2 = cpu.X86.HasBMI2
+	// ADX covers the ADCX/ADOX carry-chain instructions (amd64).
+	ADX = cpu.X86.HasADX
+	// NEON covers the Advanced SIMD extension (arm64; mandatory there,
+	// but it can still be disabled).
+	NEON = cpu.ARM64.HasASIMD
+	// SHA3 covers the ARMv8.2-A SHA3 instruction group EOR3, RAX1, XAR,
+	// and BCAX (arm64).
+	SHA3 = cpu.ARM64.HasSHA3
+)
+
+// byName maps the names accepted by Disable and CIRCL_CPU_DISABLE to
+// their flags.
+var byName = map[string]*bool{
+	"avx2":       &AVX2,
+	"avx512":     &AVX512,
+	"avx512ifma": &AVX512IFMA,
+	"bmi2":       &BMI2,
+	"adx":        &ADX,
+	"neon":       &NEON,
+	"sha3":       &SHA3,
+}
+
+func init() {
+	for _, name := range strings.Split(os.Getenv("CIRCL_CPU_DISABLE"), ",") {
+		Disable(strings.TrimSpace(name))
+	}
+}
+
+// Disable turns the named feature off, or every feature for "all".
+// Unknown names are ignored, so lists can name features of other
+// architectures or library versions.
+func Disable(name string) {
+	if name == "all" {
+		for _, f := range byName {
+			*f = false
+		}
+		return
+	}
+	if f, ok := byName[strings.ToLower(name)]; ok {
+		*f = false
+	}
+}
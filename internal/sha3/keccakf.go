@@ -0,0 +1,76 @@
+package sha3
+
+// roundConstants are the Keccak iota round constants. TurboSHAKE uses the
+// last twelve.
+var roundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a,
+	0x8000000080008000, 0x000000000000808b, 0x0000000080000001,
+	0x8000000080008081, 0x8000000000008009, 0x000000000000008a,
+	0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089,
+	0x8000000000008003, 0x8000000000008002, 0x8000000000000080,
+	0x000000000000800a, 0x800000008000000a, 0x8000000080008081,
+	0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// rotationConstants and piLane drive the in-place rho and pi steps: lane
+// piLane[i] receives the previous lane rotated by rotationConstants[i].
+var (
+	rotationConstants = [24]uint64{
+		1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14,
+		27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44,
+	}
+	piLane = [24]int{
+		10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4,
+		15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1,
+	}
+)
+
+// KeccakF1600 applies the Keccak-f[1600] permutation to the state a. If
+// turbo is true, the 12-round variant used by TurboSHAKE and KangarooTwelve
+// is applied instead.
+func KeccakF1600(a *[25]uint64, turbo bool) { keccakF1600(a, turbo) }
+
+// keccakF1600Generic is the portable implementation of the permutation.
+func keccakF1600Generic(a *[25]uint64, turbo bool) {
+	round := 0
+	if turbo {
+		round = 12
+	}
+
+	var c [5]uint64
+	for ; round < 24; round++ {
+		// theta
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ rotl(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[y+x] ^= d
+			}
+		}
+
+		// rho and pi
+		last := a[1]
+		for i := 0; i < 24; i++ {
+			j := piLane[i]
+			last, a[j] = a[j], rotl(last, rotationConstants[i])
+		}
+
+		// chi
+		for y := 0; y < 25; y += 5 {
+			t0, t1 := a[y], a[y+1]
+			a[y+0] ^= ^a[y+1] & a[y+2]
+			a[y+1] ^= ^a[y+2] & a[y+3]
+			a[y+2] ^= ^a[y+3] & a[y+4]
+			a[y+3] ^= ^a[y+4] & t0
+			a[y+4] ^= ^t0 & t1
+		}
+
+		// iota
+		a[0] ^= roundConstants[round]
+	}
+}
+
+func rotl(x uint64, n uint64) uint64 { return x<<n | x>>(64-n) }
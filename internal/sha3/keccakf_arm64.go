@@ -0,0 +1,28 @@
+//go:build arm64 && !purego && !tinygo
+
+package sha3
+
+import "github.com/cloudflare/circl/internal/cpuid"
+
+// useSHA3 is true when the CPU provides the ARMv8.2-A SHA3 instruction
+// group (EOR3, RAX1, XAR, BCAX); internal/cpuid applies operator
+// overrides before this is read.
+var useSHA3 = cpuid.SHA3
+
+// keccakF1600SHA3 performs rounds of the permutation using the SHA3
+// instruction group, starting from the round constant pointed to by rc.
+//
+//go:noescape
+func keccakF1600SHA3(a *[25]uint64, rc *uint64, rounds uint64)
+
+func keccakF1600(a *[25]uint64, turbo bool) {
+	if !useSHA3 {
+		keccakF1600Generic(a, turbo)
+		return
+	}
+	if turbo {
+		keccakF1600SHA3(a, &roundConstants[12], 12)
+	} else {
+		keccakF1600SHA3(a, &roundConstants[0], 24)
+	}
+}
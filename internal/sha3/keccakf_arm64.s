@@ -0,0 +1,232 @@
+// Code generated for the ARMv8.2-A SHA3 instruction group; the state lanes
+// live in the low 64 bits of V0-V24.
+
+//go:build arm64 && !purego
+
+#include "textflag.h"
+
+// func keccakF1600SHA3(a *[25]uint64, rc *uint64, rounds uint64)
+TEXT ·keccakF1600SHA3(SB), NOSPLIT, $0-24
+	MOVD a+0(FP), R0
+	MOVD rc+8(FP), R1
+	MOVD rounds+16(FP), R2
+
+	FMOVD 0(R0), F0
+	FMOVD 8(R0), F1
+	FMOVD 16(R0), F2
+	FMOVD 24(R0), F3
+	FMOVD 32(R0), F4
+	FMOVD 40(R0), F5
+	FMOVD 48(R0), F6
+	FMOVD 56(R0), F7
+	FMOVD 64(R0), F8
+	FMOVD 72(R0), F9
+	FMOVD 80(R0), F10
+	FMOVD 88(R0), F11
+	FMOVD 96(R0), F12
+	FMOVD 104(R0), F13
+	FMOVD 112(R0), F14
+	FMOVD 120(R0), F15
+	FMOVD 128(R0), F16
+	FMOVD 136(R0), F17
+	FMOVD 144(R0), F18
+	FMOVD 152(R0), F19
+	FMOVD 160(R0), F20
+	FMOVD 168(R0), F21
+	FMOVD 176(R0), F22
+	FMOVD 184(R0), F23
+	FMOVD 192(R0), F24
+
+loop:
+	// theta: column parities into V25-V29
+	VEOR3 V10.B16, V5.B16, V0.B16, V25.B16
+	VEOR3 V20.B16, V15.B16, V25.B16, V25.B16
+	VEOR3 V11.B16, V6.B16, V1.B16, V26.B16
+	VEOR3 V21.B16, V16.B16, V26.B16, V26.B16
+	VEOR3 V12.B16, V7.B16, V2.B16, V27.B16
+	VEOR3 V22.B16, V17.B16, V27.B16, V27.B16
+	VEOR3 V13.B16, V8.B16, V3.B16, V28.B16
+	VEOR3 V23.B16, V18.B16, V28.B16, V28.B16
+	VEOR3 V14.B16, V9.B16, V4.B16, V29.B16
+	VEOR3 V24.B16, V19.B16, V29.B16, V29.B16
+
+	// theta: fold D[x] = C[x-1] ^ rotl(C[x+1], 1) into each column
+	VRAX1 V26.D2, V29.D2, V30.D2
+	VEOR V30.B16, V0.B16, V0.B16
+	VEOR V30.B16, V5.B16, V5.B16
+	VEOR V30.B16, V10.B16, V10.B16
+	VEOR V30.B16, V15.B16, V15.B16
+	VEOR V30.B16, V20.B16, V20.B16
+	VRAX1 V27.D2, V25.D2, V30.D2
+	VEOR V30.B16, V1.B16, V1.B16
+	VEOR V30.B16, V6.B16, V6.B16
+	VEOR V30.B16, V11.B16, V11.B16
+	VEOR V30.B16, V16.B16, V16.B16
+	VEOR V30.B16, V21.B16, V21.B16
+	VRAX1 V28.D2, V26.D2, V30.D2
+	VEOR V30.B16, V2.B16, V2.B16
+	VEOR V30.B16, V7.B16, V7.B16
+	VEOR V30.B16, V12.B16, V12.B16
+	VEOR V30.B16, V17.B16, V17.B16
+	VEOR V30.B16, V22.B16, V22.B16
+	VRAX1 V29.D2, V27.D2, V30.D2
+	VEOR V30.B16, V3.B16, V3.B16
+	VEOR V30.B16, V8.B16, V8.B16
+	VEOR V30.B16, V13.B16, V13.B16
+	VEOR V30.B16, V18.B16, V18.B16
+	VEOR V30.B16, V23.B16, V23.B16
+	VRAX1 V25.D2, V28.D2, V30.D2
+	VEOR V30.B16, V4.B16, V4.B16
+	VEOR V30.B16, V9.B16, V9.B16
+	VEOR V30.B16, V14.B16, V14.B16
+	VEOR V30.B16, V19.B16, V19.B16
+	VEOR V30.B16, V24.B16, V24.B16
+
+	// rho and pi, in place: lane piln[i] receives the previous lane
+	// rotated by rotc[i]
+	VEOR V26.B16, V26.B16, V26.B16
+	VORR V1.B16, V1.B16, V25.B16
+	VORR V10.B16, V10.B16, V27.B16
+	VXAR $63, V26.D2, V25.D2, V10.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V7.B16, V7.B16, V27.B16
+	VXAR $61, V26.D2, V25.D2, V7.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V11.B16, V11.B16, V27.B16
+	VXAR $58, V26.D2, V25.D2, V11.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V17.B16, V17.B16, V27.B16
+	VXAR $54, V26.D2, V25.D2, V17.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V18.B16, V18.B16, V27.B16
+	VXAR $49, V26.D2, V25.D2, V18.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V3.B16, V3.B16, V27.B16
+	VXAR $43, V26.D2, V25.D2, V3.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V5.B16, V5.B16, V27.B16
+	VXAR $36, V26.D2, V25.D2, V5.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V16.B16, V16.B16, V27.B16
+	VXAR $28, V26.D2, V25.D2, V16.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V8.B16, V8.B16, V27.B16
+	VXAR $19, V26.D2, V25.D2, V8.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V21.B16, V21.B16, V27.B16
+	VXAR $9, V26.D2, V25.D2, V21.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V24.B16, V24.B16, V27.B16
+	VXAR $62, V26.D2, V25.D2, V24.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V4.B16, V4.B16, V27.B16
+	VXAR $50, V26.D2, V25.D2, V4.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V15.B16, V15.B16, V27.B16
+	VXAR $37, V26.D2, V25.D2, V15.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V23.B16, V23.B16, V27.B16
+	VXAR $23, V26.D2, V25.D2, V23.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V19.B16, V19.B16, V27.B16
+	VXAR $8, V26.D2, V25.D2, V19.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V13.B16, V13.B16, V27.B16
+	VXAR $56, V26.D2, V25.D2, V13.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V12.B16, V12.B16, V27.B16
+	VXAR $39, V26.D2, V25.D2, V12.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V2.B16, V2.B16, V27.B16
+	VXAR $21, V26.D2, V25.D2, V2.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V20.B16, V20.B16, V27.B16
+	VXAR $2, V26.D2, V25.D2, V20.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V14.B16, V14.B16, V27.B16
+	VXAR $46, V26.D2, V25.D2, V14.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V22.B16, V22.B16, V27.B16
+	VXAR $25, V26.D2, V25.D2, V22.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V9.B16, V9.B16, V27.B16
+	VXAR $3, V26.D2, V25.D2, V9.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V6.B16, V6.B16, V27.B16
+	VXAR $44, V26.D2, V25.D2, V6.D2
+	VORR V27.B16, V27.B16, V25.B16
+	VORR V1.B16, V1.B16, V27.B16
+	VXAR $20, V26.D2, V25.D2, V1.D2
+	VORR V27.B16, V27.B16, V25.B16
+
+	// chi: a[x] ^= ^a[x+1] & a[x+2], row by row
+	VORR V0.B16, V0.B16, V27.B16
+	VORR V1.B16, V1.B16, V28.B16
+	VBCAX V1.B16, V2.B16, V0.B16, V0.B16
+	VBCAX V2.B16, V3.B16, V1.B16, V1.B16
+	VBCAX V3.B16, V4.B16, V2.B16, V2.B16
+	VBCAX V4.B16, V27.B16, V3.B16, V3.B16
+	VBCAX V27.B16, V28.B16, V4.B16, V4.B16
+	VORR V5.B16, V5.B16, V27.B16
+	VORR V6.B16, V6.B16, V28.B16
+	VBCAX V6.B16, V7.B16, V5.B16, V5.B16
+	VBCAX V7.B16, V8.B16, V6.B16, V6.B16
+	VBCAX V8.B16, V9.B16, V7.B16, V7.B16
+	VBCAX V9.B16, V27.B16, V8.B16, V8.B16
+	VBCAX V27.B16, V28.B16, V9.B16, V9.B16
+	VORR V10.B16, V10.B16, V27.B16
+	VORR V11.B16, V11.B16, V28.B16
+	VBCAX V11.B16, V12.B16, V10.B16, V10.B16
+	VBCAX V12.B16, V13.B16, V11.B16, V11.B16
+	VBCAX V13.B16, V14.B16, V12.B16, V12.B16
+	VBCAX V14.B16, V27.B16, V13.B16, V13.B16
+	VBCAX V27.B16, V28.B16, V14.B16, V14.B16
+	VORR V15.B16, V15.B16, V27.B16
+	VORR V16.B16, V16.B16, V28.B16
+	VBCAX V16.B16, V17.B16, V15.B16, V15.B16
+	VBCAX V17.B16, V18.B16, V16.B16, V16.B16
+	VBCAX V18.B16, V19.B16, V17.B16, V17.B16
+	VBCAX V19.B16, V27.B16, V18.B16, V18.B16
+	VBCAX V27.B16, V28.B16, V19.B16, V19.B16
+	VORR V20.B16, V20.B16, V27.B16
+	VORR V21.B16, V21.B16, V28.B16
+	VBCAX V21.B16, V22.B16, V20.B16, V20.B16
+	VBCAX V22.B16, V23.B16, V21.B16, V21.B16
+	VBCAX V23.B16, V24.B16, V22.B16, V22.B16
+	VBCAX V24.B16, V27.B16, V23.B16, V23.B16
+	VBCAX V27.B16, V28.B16, V24.B16, V24.B16
+
+	// iota
+	MOVD.P 8(R1), R3
+	VDUP R3, V30.D2
+	VEOR V30.B16, V0.B16, V0.B16
+
+	SUB $1, R2, R2
+	CBNZ R2, loop
+
+	FMOVD F0, 0(R0)
+	FMOVD F1, 8(R0)
+	FMOVD F2, 16(R0)
+	FMOVD F3, 24(R0)
+	FMOVD F4, 32(R0)
+	FMOVD F5, 40(R0)
+	FMOVD F6, 48(R0)
+	FMOVD F7, 56(R0)
+	FMOVD F8, 64(R0)
+	FMOVD F9, 72(R0)
+	FMOVD F10, 80(R0)
+	FMOVD F11, 88(R0)
+	FMOVD F12, 96(R0)
+	FMOVD F13, 104(R0)
+	FMOVD F14, 112(R0)
+	FMOVD F15, 120(R0)
+	FMOVD F16, 128(R0)
+	FMOVD F17, 136(R0)
+	FMOVD F18, 144(R0)
+	FMOVD F19, 152(R0)
+	FMOVD F20, 160(R0)
+	FMOVD F21, 168(R0)
+	FMOVD F22, 176(R0)
+	FMOVD F23, 184(R0)
+	FMOVD F24, 192(R0)
+	RET
@@ -0,0 +1,5 @@
+//go:build !arm64 || purego || tinygo
+
+package sha3
+
+func keccakF1600(a *[25]uint64, turbo bool) { keccakF1600Generic(a, turbo) }
@@ -0,0 +1,133 @@
+package sha3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func fromHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestSHA3(t *testing.T) {
+	for _, tc := range []struct {
+		newState func() State
+		msg      string
+		want     string
+	}{
+		{New256, "", "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a"},
+		{New256, "abc", "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532"},
+		{New512, "", "a69f73cca23a9ac5c8b567dc185a756e97c982164fe25859e0d1dcc1475c80a6" +
+			"15b2123af1f5f94c11e3e9402c3ac558f500199d95b6d3e301758586281dcd26"},
+		{New512, "abc", "b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e" +
+			"10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0"},
+	} {
+		h := tc.newState()
+		_, _ = h.Write([]byte(tc.msg))
+		if got := h.Sum(nil); !bytes.Equal(got, fromHex(tc.want)) {
+			t.Errorf("SHA3(%q) = %x, want %v", tc.msg, got, tc.want)
+		}
+	}
+}
+
+func TestShake(t *testing.T) {
+	for _, tc := range []struct {
+		newState func() State
+		msg      string
+		want     string
+	}{
+		{NewShake128, "", "7f9c2ba4e88f827d616045507605853ed73b8093f6efbc88eb1a6eacfa66ef26"},
+		{NewShake256, "", "46b9dd2b0ba88d13233b3feb743eeb243fcd52ea62b81b82b50c27646ed5762f"},
+	} {
+		h := tc.newState()
+		_, _ = h.Write([]byte(tc.msg))
+		out := make([]byte, 32)
+		_, _ = h.Read(out)
+		if !bytes.Equal(out, fromHex(tc.want)) {
+			t.Errorf("SHAKE(%q) = %x, want %v", tc.msg, out, tc.want)
+		}
+	}
+}
+
+func TestTurboShake(t *testing.T) {
+	// TurboSHAKE128(M=ptn(0 bytes), D=0x07), first 32 bytes, from the
+	// TurboSHAKE specification test vectors.
+	h := NewTurboShake128(0x07)
+	out := make([]byte, 32)
+	_, _ = h.Read(out)
+	want := fromHex("5a223ad30b3b8c66a243048cfced430f54e7529287d15150b973133adfac6a2f")
+	if !bytes.Equal(out, want) {
+		t.Errorf("TurboSHAKE128 = %x, want %x", out, want)
+	}
+}
+
+func TestCShake(t *testing.T) {
+	// cSHAKE128 sample #1 from the NIST SP 800-185 example vectors:
+	// N="", S="Email Signature", X=00010203.
+	h := NewCShake128(nil, []byte("Email Signature"))
+	_, _ = h.Write(fromHex("00010203"))
+	out := make([]byte, 32)
+	_, _ = h.Read(out)
+	want := fromHex("c1c36925b6409a04f1b504fcbca9d82b4017277cb5ed2b2065fc1d3814d5aaf5")
+	if !bytes.Equal(out, want) {
+		t.Errorf("cSHAKE128 = %x, want %x", out, want)
+	}
+}
+
+func TestStreamingMatchesOneShot(t *testing.T) {
+	msg := make([]byte, 1000)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+	one := NewShake256()
+	_, _ = one.Write(msg)
+	want := make([]byte, 64)
+	_, _ = one.Read(want)
+
+	for _, chunk := range []int{1, 7, 136, 137} {
+		h := NewShake256()
+		for off := 0; off < len(msg); off += chunk {
+			end := off + chunk
+			if end > len(msg) {
+				end = len(msg)
+			}
+			_, _ = h.Write(msg[off:end])
+		}
+		got := make([]byte, 64)
+		for off := 0; off < len(got); off += chunk {
+			end := off + chunk
+			if end > len(got) {
+				end = len(got)
+			}
+			_, _ = h.Read(got[off:end])
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("chunk size %d: streaming output differs", chunk)
+		}
+	}
+}
+
+func TestCloneAndReset(t *testing.T) {
+	h := NewCShake256([]byte("N"), []byte("S"))
+	_, _ = h.Write([]byte("hello"))
+	dup := h.Clone()
+	a := make([]byte, 32)
+	b := make([]byte, 32)
+	_, _ = h.Read(a)
+	_, _ = dup.Read(b)
+	if !bytes.Equal(a, b) {
+		t.Error("clone diverged from original")
+	}
+
+	h.Reset()
+	_, _ = h.Write([]byte("hello"))
+	_, _ = h.Read(b)
+	if !bytes.Equal(a, b) {
+		t.Error("reset state differs from fresh state")
+	}
+}
@@ -0,0 +1,240 @@
+package sha3
+
+// This file provides the sponge layer over the Keccak-f[1600] permutation:
+// the State type with its absorb/squeeze interface, and constructors for
+// the SHA-3 hashes and the SHAKE, cSHAKE, and TurboSHAKE XOFs used across
+// the library. State is a plain value, so a copy (or Clone) forks the
+// computation; none of the methods allocate.
+
+const maxRate = 168 // SHAKE128/TurboSHAKE128, the largest rate in use
+
+type spongeDirection int
+
+const (
+	spongeAbsorbing spongeDirection = iota
+	spongeSqueezing
+)
+
+// State is a Keccak sponge instance. The zero value is not usable; obtain
+// one from a constructor. Write absorbs input and Read squeezes output;
+// once Read has been called, further Writes panic. Sum squeezes a
+// fixed-size digest without disturbing the state, so it may be interleaved
+// with Writes as with hash.Hash.
+type State struct {
+	a [25]uint64 // main state of the sponge
+
+	// storage buffers input not yet absorbed, or output squeezed from the
+	// current block but not yet read; n and rate delimit the used region.
+	storage [maxRate]byte
+	n       int
+	rate    int
+
+	dsbyte    byte // domain-separation byte, absorbed with the padding
+	outputLen int  // digest size of Sum, in bytes
+	turbo     bool // use the 12-round permutation
+	direction spongeDirection
+
+	// initBlock is the bytepad-framed cSHAKE prefix, re-absorbed by Reset;
+	// nil for the other variants. It is written once and then only read,
+	// so clones may share it.
+	initBlock []byte
+}
+
+// NewShake128 returns a SHAKE128 XOF.
+func NewShake128() State { return State{rate: 168, dsbyte: 0x1f} }
+
+// NewShake256 returns a SHAKE256 XOF.
+func NewShake256() State { return State{rate: 136, dsbyte: 0x1f} }
+
+// New256 returns a SHA3-256 hash.
+func New256() State { return State{rate: 136, dsbyte: 0x06, outputLen: 32} }
+
+// New512 returns a SHA3-512 hash.
+func New512() State { return State{rate: 72, dsbyte: 0x06, outputLen: 64} }
+
+// NewTurboShake128 returns a TurboSHAKE128 XOF with the given
+// domain-separation byte, which must be in [0x01, 0x7f].
+func NewTurboShake128(dsbyte byte) State {
+	if dsbyte == 0 || dsbyte > 0x7f {
+		panic("sha3: invalid TurboSHAKE domain-separation byte")
+	}
+	return State{rate: 168, dsbyte: dsbyte, turbo: true}
+}
+
+// NewTurboShake256 returns a TurboSHAKE256 XOF with the given
+// domain-separation byte, which must be in [0x01, 0x7f].
+func NewTurboShake256(dsbyte byte) State {
+	if dsbyte == 0 || dsbyte > 0x7f {
+		panic("sha3: invalid TurboSHAKE domain-separation byte")
+	}
+	return State{rate: 136, dsbyte: dsbyte, turbo: true}
+}
+
+// NewCShake128 returns a cSHAKE128 XOF with function name n and
+// customization string s, at least one of which must be non-empty;
+// NIST SP 800-185 defines cSHAKE with both empty as plain SHAKE.
+func NewCShake128(n, s []byte) State { return newCShake(n, s, 168) }
+
+// NewCShake256 returns a cSHAKE256 XOF with function name n and
+// customization string s, at least one of which must be non-empty.
+func NewCShake256(n, s []byte) State { return newCShake(n, s, 136) }
+
+func newCShake(n, s []byte, rate int) State {
+	if len(n) == 0 && len(s) == 0 {
+		panic("sha3: cSHAKE with empty N and S is plain SHAKE")
+	}
+	// bytepad(encode_string(N) || encode_string(S), rate)
+	block := leftEncode(uint64(rate))
+	block = append(block, leftEncode(uint64(len(n))*8)...)
+	block = append(block, n...)
+	block = append(block, leftEncode(uint64(len(s))*8)...)
+	block = append(block, s...)
+	for len(block)%rate != 0 {
+		block = append(block, 0)
+	}
+
+	st := State{rate: rate, dsbyte: 0x04, initBlock: block}
+	_, _ = st.Write(block)
+	return st
+}
+
+// leftEncode returns left_encode(v) of SP 800-185.
+func leftEncode(v uint64) []byte {
+	n := 1
+	for vv := v; vv >= 0x100; vv >>= 8 {
+		n++
+	}
+	out := make([]byte, n+1)
+	out[0] = byte(n)
+	for i := n; i > 0; i-- {
+		out[i] = byte(v)
+		v >>= 8
+	}
+	return out
+}
+
+// Reset returns the state to that of a freshly constructed instance.
+func (s *State) Reset() {
+	s.a = [25]uint64{}
+	s.n = 0
+	s.direction = spongeAbsorbing
+	if s.initBlock != nil {
+		_, _ = s.Write(s.initBlock)
+	}
+}
+
+// Clone returns a copy of the state; the copy and the original evolve
+// independently from this point.
+func (s *State) Clone() State { return *s }
+
+// SwitchDS replaces the domain-separation byte. It must be called before
+// the first Read; KangarooTwelve uses it to close the final node with a
+// different frame than the one it was opened with.
+func (s *State) SwitchDS(dsbyte byte) {
+	if s.direction == spongeSqueezing {
+		panic("sha3: SwitchDS after Read")
+	}
+	s.dsbyte = dsbyte
+}
+
+// BlockSize returns the rate of the sponge, in bytes.
+func (s *State) BlockSize() int { return s.rate }
+
+// Size returns the digest size of Sum, in bytes.
+func (s *State) Size() int { return s.outputLen }
+
+// permute applies the permutation to the state.
+func (s *State) permute() { keccakF1600(&s.a, s.turbo) }
+
+// xorIn absorbs a full rate-sized block into the state.
+func (s *State) xorIn(block []byte) {
+	for i := 0; i < s.rate/8; i++ {
+		var lane uint64
+		for j := 7; j >= 0; j-- {
+			lane = lane<<8 | uint64(block[8*i+j])
+		}
+		s.a[i] ^= lane
+	}
+}
+
+// copyOut fills storage with the next block of output.
+func (s *State) copyOut() {
+	for i := 0; i < s.rate/8; i++ {
+		v := s.a[i]
+		for j := 0; j < 8; j++ {
+			s.storage[8*i+j] = byte(v)
+			v >>= 8
+		}
+	}
+}
+
+// Write absorbs p into the sponge. It never fails; the signature matches
+// io.Writer so states can be used with io.Copy and friends.
+func (s *State) Write(p []byte) (n int, err error) {
+	if s.direction == spongeSqueezing {
+		panic("sha3: Write after Read")
+	}
+	n = len(p)
+	for len(p) > 0 {
+		if s.n == 0 && len(p) >= s.rate {
+			// Fast path: absorb a full block directly.
+			s.xorIn(p[:s.rate])
+			s.permute()
+			p = p[s.rate:]
+			continue
+		}
+		c := copy(s.storage[s.n:s.rate], p)
+		s.n += c
+		p = p[c:]
+		if s.n == s.rate {
+			s.xorIn(s.storage[:s.rate])
+			s.permute()
+			s.n = 0
+		}
+	}
+	return
+}
+
+// pad absorbs the buffered input with the domain-separation byte and the
+// multi-rate padding, and switches the sponge to squeezing.
+func (s *State) pad() {
+	s.storage[s.n] = s.dsbyte
+	for i := s.n + 1; i < s.rate; i++ {
+		s.storage[i] = 0
+	}
+	s.storage[s.rate-1] |= 0x80
+	s.xorIn(s.storage[:s.rate])
+	s.permute()
+	s.copyOut()
+	s.n = 0
+	s.direction = spongeSqueezing
+}
+
+// Read squeezes len(p) bytes of output. It never fails; the signature
+// matches io.Reader.
+func (s *State) Read(p []byte) (n int, err error) {
+	if s.direction == spongeAbsorbing {
+		s.pad()
+	}
+	n = len(p)
+	for len(p) > 0 {
+		if s.n == s.rate {
+			s.permute()
+			s.copyOut()
+			s.n = 0
+		}
+		c := copy(p, s.storage[s.n:s.rate])
+		s.n += c
+		p = p[c:]
+	}
+	return
+}
+
+// Sum appends the digest of the input written so far to b. The state is
+// left untouched, so writing may continue afterwards.
+func (s *State) Sum(b []byte) []byte {
+	dup := *s
+	out := make([]byte, dup.outputLen)
+	_, _ = dup.Read(out)
+	return append(b, out...)
+}
@@ -0,0 +1,94 @@
+// Package subtle extends crypto/subtle with the selection primitives the
+// curve and post-quantum code needs: conditional move and swap over byte
+// slices and limb arrays, table lookup by secret index, and comparison of
+// variable-length secrets. All functions run in time independent of the
+// secret values and of the secret index; only lengths are observable.
+package subtle
+
+// Cmov copies y into x when b is 1, leaving x unchanged when b is 0. Both
+// slices must have the same length; b must be 0 or 1.
+func Cmov(x, y []byte, b int) {
+	m := byte(-b)
+	for i := range x {
+		x[i] = x[i]&^m | y[i]&m
+	}
+}
+
+// Cswap exchanges x and y when b is 1. Both slices must have the same
+// length; b must be 0 or 1.
+func Cswap(x, y []byte, b int) {
+	m := byte(-b)
+	for i := range x {
+		t := m & (x[i] ^ y[i])
+		x[i] ^= t
+		y[i] ^= t
+	}
+}
+
+// CmovU64 copies y into x when b is 1, leaving x unchanged when b is 0.
+// Both slices must have the same length; b must be 0 or 1.
+func CmovU64(x, y []uint64, b int) {
+	m := uint64(0) - uint64(b)
+	for i := range x {
+		x[i] = x[i]&^m | y[i]&m
+	}
+}
+
+// CswapU64 exchanges x and y when b is 1. Both slices must have the same
+// length; b must be 0 or 1.
+func CswapU64(x, y []uint64, b int) {
+	m := uint64(0) - uint64(b)
+	for i := range x {
+		t := m & (x[i] ^ y[i])
+		x[i] ^= t
+		y[i] ^= t
+	}
+}
+
+// Lookup copies table[idx] into dst by scanning the whole table, so the
+// memory access pattern does not depend on idx. Every entry must have the
+// length of dst. The index must be in range.
+func Lookup(dst []byte, table [][]byte, idx int) {
+	for i := range table {
+		b := eq32(uint32(i), uint32(idx))
+		Cmov(dst, table[i], b)
+	}
+}
+
+// LookupU64 is Lookup for limb arrays.
+func LookupU64(dst []uint64, table [][]uint64, idx int) {
+	for i := range table {
+		b := eq32(uint32(i), uint32(idx))
+		CmovU64(dst, table[i], b)
+	}
+}
+
+// VarLenEq compares two secrets that may differ in length, in time
+// depending only on the lengths. It returns 1 when the contents (and
+// lengths) are equal.
+func VarLenEq(x, y []byte) int {
+	var v byte
+	if len(x) != len(y) {
+		// Consume the longer input so timing reflects only lengths,
+		// which are public, then fail.
+		long := x
+		if len(y) > len(x) {
+			long = y
+		}
+		for _, b := range long {
+			v |= b
+		}
+		_ = v
+		return 0
+	}
+	for i := range x {
+		v |= x[i] ^ y[i]
+	}
+	return int((uint32(v) - 1) >> 31)
+}
+
+// eq32 returns 1 when a == b, in constant time.
+func eq32(a, b uint32) int {
+	c := a ^ b
+	return int(((c - 1) &^ c) >> 31)
+}
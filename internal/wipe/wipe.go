@@ -0,0 +1,28 @@
+// Package wipe zeroes buffers holding secret material. The helpers write
+// zeros and then force the buffer to be considered live, so the stores
+// are not removed as dead even when the buffer is never read again.
+// Wiping limits how long secrets linger in process memory; it cannot
+// reach copies the garbage collector or the kernel may have made.
+package wipe
+
+import "runtime"
+
+// Bytes overwrites b with zeros.
+func Bytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+	if len(b) != 0 {
+		runtime.KeepAlive(&b[0])
+	}
+}
+
+// U64s overwrites x with zeros.
+func U64s(x []uint64) {
+	for i := range x {
+		x[i] = 0
+	}
+	if len(x) != 0 {
+		runtime.KeepAlive(&x[0])
+	}
+}
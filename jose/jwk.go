@@ -0,0 +1,258 @@
+// Package jose connects CIRCL algorithms to web-token stacks: it encodes
+// and decodes JSON Web Keys and maps algorithm names to JWS/JWE "alg"
+// identifiers. Ed25519, Ed448, X25519, and X448 use the OKP key type of
+// RFC 8037; P-384 uses the EC key type of RFC 7518 with split
+// coordinates; ML-DSA, ML-KEM, and SLH-DSA use the AKP (algorithm key
+// pair) key type of the emerging COSE/JOSE post-quantum drafts, whose
+// identifiers may still change before publication. Thumbprints follow
+// RFC 7638.
+//
+// Like package pki, this package works on raw key bytes as produced by a
+// scheme's MarshalBinary, so web-token code can carry keys without linking
+// the scheme packages.
+package jose
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/cloudflare/circl/internal/wipe"
+)
+
+// Key is a JSON Web Key holding one CIRCL key, public or key pair.
+type Key struct {
+	// Algorithm is the CIRCL algorithm name, e.g. "Ed25519" or
+	// "ML-DSA-65".
+	Algorithm string
+	// Public is the raw public key.
+	Public []byte
+	// Private is the raw private key; nil for a public-only JWK.
+	Private []byte
+}
+
+// Wipe zeroes the private key bytes and turns k into a public-only key.
+func (k *Key) Wipe() {
+	wipe.Bytes(k.Private)
+	k.Private = nil
+}
+
+// kind describes how an algorithm is spelled in JWK and JWS/JWE.
+type kind struct {
+	okp   bool   // OKP with crv (RFC 8037) rather than AKP with alg
+	coord int    // nonzero: EC with split x/y coordinates of this size
+	sig   string // JWS "alg", empty for KEMs
+	enc   string // JWE "alg", empty for signature schemes
+}
+
+var kinds = map[string]kind{
+	"Ed25519": {okp: true, sig: "EdDSA"},
+	"Ed448":   {okp: true, sig: "EdDSA"},
+	"X25519":  {okp: true, enc: "ECDH-ES"},
+	"X448":    {okp: true, enc: "ECDH-ES"},
+	"P-384":   {coord: 48, sig: "ES384", enc: "ECDH-ES"},
+
+	"ML-DSA-44":   {sig: "ML-DSA-44"},
+	"ML-DSA-65":   {sig: "ML-DSA-65"},
+	"ML-DSA-87":   {sig: "ML-DSA-87"},
+	"ML-KEM-512":  {enc: "ML-KEM-512"},
+	"ML-KEM-768":  {enc: "ML-KEM-768"},
+	"ML-KEM-1024": {enc: "ML-KEM-1024"},
+
+	"SLH-DSA-SHA2-128s":  {sig: "SLH-DSA-SHA2-128s"},
+	"SLH-DSA-SHA2-128f":  {sig: "SLH-DSA-SHA2-128f"},
+	"SLH-DSA-SHA2-192s":  {sig: "SLH-DSA-SHA2-192s"},
+	"SLH-DSA-SHA2-192f":  {sig: "SLH-DSA-SHA2-192f"},
+	"SLH-DSA-SHA2-256s":  {sig: "SLH-DSA-SHA2-256s"},
+	"SLH-DSA-SHA2-256f":  {sig: "SLH-DSA-SHA2-256f"},
+	"SLH-DSA-SHAKE-128s": {sig: "SLH-DSA-SHAKE-128s"},
+	"SLH-DSA-SHAKE-128f": {sig: "SLH-DSA-SHAKE-128f"},
+	"SLH-DSA-SHAKE-192s": {sig: "SLH-DSA-SHAKE-192s"},
+	"SLH-DSA-SHAKE-192f": {sig: "SLH-DSA-SHAKE-192f"},
+	"SLH-DSA-SHAKE-256s": {sig: "SLH-DSA-SHAKE-256s"},
+	"SLH-DSA-SHAKE-256f": {sig: "SLH-DSA-SHAKE-256f"},
+}
+
+// ErrUnknownAlgorithm is returned when an algorithm name has no JWK
+// mapping.
+var ErrUnknownAlgorithm = errors.New("jose: unknown algorithm")
+
+// SignatureAlgorithm returns the JWS "alg" identifier of a signature
+// scheme.
+func SignatureAlgorithm(name string) (string, error) {
+	k, ok := kinds[name]
+	if !ok || k.sig == "" {
+		return "", ErrUnknownAlgorithm
+	}
+
+	return k.sig, nil
+}
+
+// KeyAlgorithm returns the JWE "alg" identifier of a key-establishment
+// scheme.
+func KeyAlgorithm(name string) (string, error) {
+	k, ok := kinds[name]
+	if !ok || k.enc == "" {
+		return "", ErrUnknownAlgorithm
+	}
+
+	return k.enc, nil
+}
+
+var b64 = base64.RawURLEncoding
+
+// jwk is the wire form; OKP and AKP members are both present so one
+// struct round-trips either key type.
+type jwk struct {
+	Kty  string `json:"kty"`
+	Crv  string `json:"crv,omitempty"`
+	X    string `json:"x,omitempty"`
+	Y    string `json:"y,omitempty"`
+	D    string `json:"d,omitempty"`
+	Alg  string `json:"alg,omitempty"`
+	Pub  string `json:"pub,omitempty"`
+	Priv string `json:"priv,omitempty"`
+}
+
+// MarshalJSON encodes the key as a JWK.
+func (k *Key) MarshalJSON() ([]byte, error) {
+	w, err := k.wire()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(w)
+}
+
+func (k *Key) wire() (*jwk, error) {
+	kd, ok := kinds[k.Algorithm]
+	if !ok {
+		return nil, ErrUnknownAlgorithm
+	}
+	if len(k.Public) == 0 {
+		return nil, errors.New("jose: missing public key")
+	}
+	w := &jwk{}
+	switch {
+	case kd.okp:
+		w.Kty = "OKP"
+		w.Crv = k.Algorithm
+		w.X = b64.EncodeToString(k.Public)
+		if k.Private != nil {
+			w.D = b64.EncodeToString(k.Private)
+		}
+	case kd.coord != 0:
+		// EC keys carry split coordinates; the raw public key is the
+		// uncompressed SEC1 point.
+		if len(k.Public) != 1+2*kd.coord || k.Public[0] != 0x04 {
+			return nil, errors.New("jose: public key is not an uncompressed point")
+		}
+		w.Kty = "EC"
+		w.Crv = k.Algorithm
+		w.X = b64.EncodeToString(k.Public[1 : 1+kd.coord])
+		w.Y = b64.EncodeToString(k.Public[1+kd.coord:])
+		if k.Private != nil {
+			w.D = b64.EncodeToString(k.Private)
+		}
+	default:
+		w.Kty = "AKP"
+		w.Alg = k.Algorithm
+		w.Pub = b64.EncodeToString(k.Public)
+		if k.Private != nil {
+			w.Priv = b64.EncodeToString(k.Private)
+		}
+	}
+
+	return w, nil
+}
+
+// UnmarshalJSON decodes a JWK of key type OKP or AKP.
+func (k *Key) UnmarshalJSON(data []byte) error {
+	var w jwk
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	var name, pub, priv string
+	switch w.Kty {
+	case "OKP", "EC":
+		name, pub, priv = w.Crv, w.X, w.D
+	case "AKP":
+		name, pub, priv = w.Alg, w.Pub, w.Priv
+	default:
+		return errors.New("jose: unsupported key type " + w.Kty)
+	}
+	kd, ok := kinds[name]
+	if !ok || kd.okp != (w.Kty == "OKP") || (kd.coord != 0) != (w.Kty == "EC") {
+		return ErrUnknownAlgorithm
+	}
+	if pub == "" {
+		return errors.New("jose: missing public key")
+	}
+	pubB, err := b64.DecodeString(pub)
+	if err != nil {
+		return errors.New("jose: invalid base64url in public key")
+	}
+	if kd.coord != 0 {
+		// Reassemble the uncompressed point from the coordinates.
+		yB, err := b64.DecodeString(w.Y)
+		if err != nil {
+			return errors.New("jose: invalid base64url in public key")
+		}
+		if len(pubB) != kd.coord || len(yB) != kd.coord {
+			return errors.New("jose: bad coordinate size")
+		}
+		pubB = append(append([]byte{0x04}, pubB...), yB...)
+	}
+	var privB []byte
+	if priv != "" {
+		if privB, err = b64.DecodeString(priv); err != nil {
+			return errors.New("jose: invalid base64url in private key")
+		}
+	}
+	k.Algorithm = name
+	k.Public = pubB
+	k.Private = privB
+
+	return nil
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the key: the base64url
+// SHA-256 of the required members in lexicographic order. For AKP keys the
+// required members are alg, kty, and pub, following the PQ drafts.
+func (k *Key) Thumbprint() (string, error) {
+	w, err := k.wire()
+	if err != nil {
+		return "", err
+	}
+	// Build the canonical subset by hand: json.Marshal of an ordered
+	// struct would serialize in field order, not lexicographic.
+	var canon []byte
+	switch w.Kty {
+	case "OKP":
+		canon, err = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{w.Crv, w.Kty, w.X})
+	case "EC":
+		canon, err = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{w.Crv, w.Kty, w.X, w.Y})
+	default:
+		canon, err = json.Marshal(struct {
+			Alg string `json:"alg"`
+			Kty string `json:"kty"`
+			Pub string `json:"pub"`
+		}{w.Alg, w.Kty, w.Pub})
+	}
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canon)
+
+	return b64.EncodeToString(sum[:]), nil
+}
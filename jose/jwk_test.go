@@ -0,0 +1,142 @@
+package jose_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudflare/circl/jose"
+)
+
+// The example Ed25519 key of RFC 8037, Appendix A, with the thumbprint of
+// Appendix A.3.
+const (
+	rfc8037JWK = `{"kty":"OKP","crv":"Ed25519",` +
+		`"d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",` +
+		`"x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+	rfc8037Public     = "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+	rfc8037Thumbprint = "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+)
+
+func TestRFC8037(t *testing.T) {
+	var k jose.Key
+	if err := json.Unmarshal([]byte(rfc8037JWK), &k); err != nil {
+		t.Fatal(err)
+	}
+	if k.Algorithm != "Ed25519" {
+		t.Fatalf("wrong algorithm %q", k.Algorithm)
+	}
+	pub, err := hex.DecodeString(rfc8037Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(k.Public, pub) {
+		t.Fatalf("wrong public key %x", k.Public)
+	}
+	if len(k.Private) != 32 {
+		t.Fatalf("wrong private key size %d", len(k.Private))
+	}
+
+	tp, err := k.Thumbprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tp != rfc8037Thumbprint {
+		t.Fatalf("thumbprint mismatch\ngot:  %s\nwant: %s", tp, rfc8037Thumbprint)
+	}
+
+	// The thumbprint covers only public members.
+	k.Wipe()
+	if k.Private != nil {
+		t.Fatal("Wipe leaves the private key")
+	}
+	if tp2, _ := k.Thumbprint(); tp2 != rfc8037Thumbprint {
+		t.Fatal("thumbprint depends on the private key")
+	}
+}
+
+func TestRoundtrip(t *testing.T) {
+	for name, sizes := range map[string][2]int{
+		"Ed448":      {57, 57},
+		"X25519":     {32, 32},
+		"ML-DSA-44":  {1312, 2560},
+		"ML-KEM-768": {1184, 2400},
+		"P-384":      {97, 48},
+	} {
+		k := jose.Key{Algorithm: name}
+		k.Public = make([]byte, sizes[0])
+		k.Private = make([]byte, sizes[1])
+		if _, err := rand.Read(k.Public); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rand.Read(k.Private); err != nil {
+			t.Fatal(err)
+		}
+		if name == "P-384" {
+			k.Public[0] = 0x04 // uncompressed point marker
+		}
+
+		data, err := json.Marshal(&k)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var got jose.Key
+		if err = json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got.Algorithm != name || !bytes.Equal(got.Public, k.Public) ||
+			!bytes.Equal(got.Private, k.Private) {
+			t.Fatalf("%s: key does not roundtrip", name)
+		}
+
+		// Public-only form.
+		k.Wipe()
+		if data, err = json.Marshal(&k); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err = json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got.Private != nil {
+			t.Fatalf("%s: public-only JWK carries a private key", name)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	k := jose.Key{Algorithm: "NoSuchScheme", Public: []byte{1}}
+	if _, err := json.Marshal(&k); err == nil {
+		t.Fatal("unknown algorithm accepted")
+	}
+
+	var got jose.Key
+	if json.Unmarshal([]byte(`{"kty":"RSA","n":"AQAB"}`), &got) == nil {
+		t.Fatal("unsupported key type accepted")
+	}
+	if json.Unmarshal([]byte(`{"kty":"OKP","crv":"Ed25519"}`), &got) == nil {
+		t.Fatal("JWK without public key accepted")
+	}
+	if json.Unmarshal([]byte(`{"kty":"AKP","alg":"Ed25519","pub":"AA"}`), &got) == nil {
+		t.Fatal("OKP algorithm accepted as AKP")
+	}
+}
+
+func TestAlgorithmNames(t *testing.T) {
+	if alg, err := jose.SignatureAlgorithm("Ed448"); err != nil || alg != "EdDSA" {
+		t.Fatalf("Ed448: got %q, %v", alg, err)
+	}
+	if alg, err := jose.SignatureAlgorithm("P-384"); err != nil || alg != "ES384" {
+		t.Fatalf("P-384: got %q, %v", alg, err)
+	}
+	if _, err := jose.SignatureAlgorithm("X25519"); err == nil {
+		t.Fatal("KEM accepted as signature algorithm")
+	}
+	if alg, err := jose.KeyAlgorithm("ML-KEM-1024"); err != nil || alg != "ML-KEM-1024" {
+		t.Fatalf("ML-KEM-1024: got %q, %v", alg, err)
+	}
+	if _, err := jose.KeyAlgorithm("ML-DSA-65"); err == nil {
+		t.Fatal("signature scheme accepted as key algorithm")
+	}
+}
@@ -0,0 +1,163 @@
+// Package kdf provides a unified interface to key derivation functions, so
+// protocol code such as HPKE, OPRF, and PAKE suites can treat the KDF as a
+// pluggable parameter rather than hard-coding one.
+//
+// A Scheme follows the extract-then-expand paradigm of HKDF (RFC 5869):
+// Extract condenses input keying material into a pseudorandom key, and
+// Expand stretches that key into any number of output bytes bound to a
+// context. DeriveSecret combines both with a label, in the style of the
+// labeled KDF of HPKE (RFC 9180), which is the recommended entry point for
+// protocol code.
+package kdf
+
+import (
+	"crypto"
+	_ "crypto/sha256" // linked for HKDFSHA256
+	_ "crypto/sha512" // linked for HKDFSHA384 and HKDFSHA512
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/cloudflare/circl/internal/sha3"
+)
+
+// Scheme represents a key derivation function.
+type Scheme interface {
+	// Name is the standard name of the scheme.
+	Name() string
+	// ExtractSize returns the size, in bytes, of pseudorandom keys
+	// produced by Extract.
+	ExtractSize() int
+	// Extract derives a pseudorandom key from input keying material and an
+	// optional salt.
+	Extract(salt, ikm []byte) []byte
+	// Expand derives length bytes of output keying material from a
+	// pseudorandom key and a context string.
+	Expand(prk, info []byte, length uint) []byte
+}
+
+// ExpanderTo is implemented by schemes that can write expanded output
+// keying material directly into a caller-provided buffer, avoiding the
+// per-operation allocation of Expand. All schemes of this package
+// implement it.
+type ExpanderTo interface {
+	// ExpandTo fills out from a pseudorandom key and a context string.
+	ExpandTo(out, prk, info []byte)
+}
+
+// DeriveSecret extracts from the input keying material and expands with the
+// given label and context, domain-separating uses of the same material.
+func DeriveSecret(s Scheme, ikm, salt []byte, label string, context []byte, length uint) []byte {
+	prk := s.Extract(salt, ikm)
+	info := make([]byte, 0, len(label)+len(context))
+	info = append(info, label...)
+	info = append(info, context...)
+	return s.Expand(prk, info, length)
+}
+
+// ExpandTo fills out with output keying material, using the scheme's
+// ExpandTo when available and falling back to Expand with a copy
+// otherwise.
+func ExpandTo(s Scheme, out, prk, info []byte) {
+	if e, ok := s.(ExpanderTo); ok {
+		e.ExpandTo(out, prk, info)
+		return
+	}
+	copy(out, s.Expand(prk, info, uint(len(out))))
+}
+
+// HKDF schemes based on the SHA-2 family.
+var (
+	HKDFSHA256 Scheme = hkdfScheme{crypto.SHA256, "HKDF-SHA256"}
+	HKDFSHA384 Scheme = hkdfScheme{crypto.SHA384, "HKDF-SHA384"}
+	HKDFSHA512 Scheme = hkdfScheme{crypto.SHA512, "HKDF-SHA512"}
+)
+
+// SHAKE-based schemes, for suites standardized on Keccak.
+var (
+	SHAKE128 Scheme = shakeScheme{128, "SHAKE128"}
+	SHAKE256 Scheme = shakeScheme{256, "SHAKE256"}
+)
+
+// SchemeByName returns the scheme with the given name, or nil.
+func SchemeByName(name string) Scheme {
+	for _, s := range []Scheme{HKDFSHA256, HKDFSHA384, HKDFSHA512, SHAKE128, SHAKE256} {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+type hkdfScheme struct {
+	h    crypto.Hash
+	name string
+}
+
+func (k hkdfScheme) Name() string     { return k.name }
+func (k hkdfScheme) ExtractSize() int { return k.h.Size() }
+
+func (k hkdfScheme) Extract(salt, ikm []byte) []byte {
+	return hkdf.Extract(k.h.New, ikm, salt)
+}
+
+func (k hkdfScheme) Expand(prk, info []byte, length uint) []byte {
+	out := make([]byte, length)
+	k.ExpandTo(out, prk, info)
+	return out
+}
+
+func (k hkdfScheme) ExpandTo(out, prk, info []byte) {
+	if _, err := io.ReadFull(hkdf.Expand(k.h.New, prk, info), out); err != nil {
+		panic("kdf: requested length exceeds HKDF limit")
+	}
+}
+
+type shakeScheme struct {
+	strength int
+	name     string
+}
+
+func (k shakeScheme) Name() string     { return k.name }
+func (k shakeScheme) ExtractSize() int { return k.strength / 4 }
+
+func (k shakeScheme) newState() sha3.State {
+	if k.strength == 128 {
+		return sha3.NewShake128()
+	}
+	return sha3.NewShake256()
+}
+
+// Extract computes SHAKE over the length-framed salt and keying material.
+func (k shakeScheme) Extract(salt, ikm []byte) []byte {
+	s := k.newState()
+	writeFramed(&s, []byte("extract"))
+	writeFramed(&s, salt)
+	writeFramed(&s, ikm)
+	prk := make([]byte, k.ExtractSize())
+	_, _ = s.Read(prk)
+	return prk
+}
+
+// Expand computes SHAKE over the framed key and context.
+func (k shakeScheme) Expand(prk, info []byte, length uint) []byte {
+	out := make([]byte, length)
+	k.ExpandTo(out, prk, info)
+	return out
+}
+
+// ExpandTo is Expand writing into a caller-provided buffer.
+func (k shakeScheme) ExpandTo(out, prk, info []byte) {
+	s := k.newState()
+	writeFramed(&s, []byte("expand"))
+	writeFramed(&s, prk)
+	writeFramed(&s, info)
+	_, _ = s.Read(out)
+}
+
+// writeFramed writes data preceded by a two-byte length, keeping the
+// concatenation of inputs unambiguous.
+func writeFramed(s *sha3.State, data []byte) {
+	_, _ = s.Write([]byte{byte(len(data) >> 8), byte(len(data))})
+	_, _ = s.Write(data)
+}
@@ -0,0 +1,107 @@
+package kdf_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/kdf"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Test vectors from RFC 5869, Appendix A (HKDF-SHA256).
+func TestHKDFRFC5869(t *testing.T) {
+	for i, v := range []struct{ ikm, salt, info, prk, okm string }{
+		{ // Test Case 1
+			"0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			"000102030405060708090a0b0c",
+			"f0f1f2f3f4f5f6f7f8f9",
+			"077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5",
+			"3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+		},
+		{ // Test Case 3
+			"0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			"",
+			"",
+			"19ef24a32c717b167f33a91d6f648bdf96596776afdb6377ac434c1c293ccb04",
+			"8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8",
+		},
+	} {
+		ikm := mustHex(t, v.ikm)
+		salt := mustHex(t, v.salt)
+		info := mustHex(t, v.info)
+		wantPRK := mustHex(t, v.prk)
+		wantOKM := mustHex(t, v.okm)
+
+		prk := kdf.HKDFSHA256.Extract(salt, ikm)
+		if !bytes.Equal(prk, wantPRK) {
+			t.Errorf("case %d: PRK mismatch\ngot:  %x\nwant: %x", i, prk, wantPRK)
+		}
+		okm := kdf.HKDFSHA256.Expand(prk, info, uint(len(wantOKM)))
+		if !bytes.Equal(okm, wantOKM) {
+			t.Errorf("case %d: OKM mismatch\ngot:  %x\nwant: %x", i, okm, wantOKM)
+		}
+	}
+}
+
+var allSchemes = []kdf.Scheme{
+	kdf.HKDFSHA256, kdf.HKDFSHA384, kdf.HKDFSHA512,
+	kdf.SHAKE128, kdf.SHAKE256,
+}
+
+func TestSchemes(t *testing.T) {
+	ikm := []byte("input keying material")
+	salt := []byte("salt")
+	info := []byte("info")
+	for _, s := range allSchemes {
+		if kdf.SchemeByName(s.Name()) != s {
+			t.Errorf("%s: not resolvable by name", s.Name())
+		}
+
+		prk := s.Extract(salt, ikm)
+		if len(prk) != s.ExtractSize() {
+			t.Errorf("%s: wrong PRK size", s.Name())
+		}
+		if bytes.Equal(prk, s.Extract(nil, ikm)) {
+			t.Errorf("%s: salt is ignored", s.Name())
+		}
+
+		okm := s.Expand(prk, info, 99)
+		if len(okm) != 99 {
+			t.Errorf("%s: wrong OKM size", s.Name())
+		}
+		if bytes.Equal(okm[:64], s.Expand(prk, []byte("other"), 64)) {
+			t.Errorf("%s: info is ignored", s.Name())
+		}
+
+		out := make([]byte, 99)
+		kdf.ExpandTo(s, out, prk, info)
+		if !bytes.Equal(out, okm) {
+			t.Errorf("%s: ExpandTo differs from Expand", s.Name())
+		}
+	}
+	if kdf.SchemeByName("no-such-kdf") != nil {
+		t.Error("unknown name resolves")
+	}
+}
+
+func TestDeriveSecret(t *testing.T) {
+	ikm := []byte("shared secret")
+	a := kdf.DeriveSecret(kdf.HKDFSHA256, ikm, nil, "label one", []byte("ctx"), 32)
+	b := kdf.DeriveSecret(kdf.HKDFSHA256, ikm, nil, "label two", []byte("ctx"), 32)
+	if bytes.Equal(a, b) {
+		t.Fatal("labels do not separate")
+	}
+	c := kdf.DeriveSecret(kdf.HKDFSHA256, ikm, nil, "label one", []byte("ctx"), 32)
+	if !bytes.Equal(a, c) {
+		t.Fatal("derivation is not deterministic")
+	}
+}
@@ -0,0 +1,32 @@
+// Package frodo1344 provides the FrodoKEM-1344 parameter set, an
+// unstructured-lattice KEM at NIST security category 5, in its AES and
+// SHAKE variants. See kem/internal/frodo for the construction.
+package frodo1344
+
+import (
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/internal/frodo"
+	"github.com/cloudflare/circl/schemes"
+)
+
+var cdf = []uint16{9142, 23462, 30338, 32361, 32725, 32765, 32767}
+
+// AES and SHAKE differ only in how the public matrix is expanded.
+var (
+	AES kem.Scheme = frodo.NewScheme(frodo.Params{
+		Name: "FrodoKEM-1344-AES", N: 1344, D: 16, B: 4, Sec: 32, CDF: cdf, AES: true,
+	})
+	SHAKE kem.Scheme = frodo.NewScheme(frodo.Params{
+		Name: "FrodoKEM-1344-SHAKE", N: 1344, D: 16, B: 4, Sec: 32, CDF: cdf,
+	})
+)
+
+func init() {
+	for _, s := range []kem.Scheme{AES, SHAKE} {
+		if err := schemes.Register(schemes.Entry{
+			Kind: schemes.KindKEM, Name: s.Name(), KEM: s,
+		}); err != nil {
+			panic(err)
+		}
+	}
+}
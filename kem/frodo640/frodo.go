@@ -0,0 +1,35 @@
+// Package frodo640 provides the FrodoKEM-640 parameter set, an
+// unstructured-lattice KEM at NIST security category 1, in its AES and
+// SHAKE variants. See kem/internal/frodo for the construction.
+package frodo640
+
+import (
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/internal/frodo"
+	"github.com/cloudflare/circl/schemes"
+)
+
+var cdf = []uint16{
+	4643, 13363, 20579, 25843, 29227, 31145, 32103,
+	32525, 32689, 32745, 32762, 32766, 32767,
+}
+
+// AES and SHAKE differ only in how the public matrix is expanded.
+var (
+	AES kem.Scheme = frodo.NewScheme(frodo.Params{
+		Name: "FrodoKEM-640-AES", N: 640, D: 15, B: 2, Sec: 16, CDF: cdf, AES: true,
+	})
+	SHAKE kem.Scheme = frodo.NewScheme(frodo.Params{
+		Name: "FrodoKEM-640-SHAKE", N: 640, D: 15, B: 2, Sec: 16, CDF: cdf,
+	})
+)
+
+func init() {
+	for _, s := range []kem.Scheme{AES, SHAKE} {
+		if err := schemes.Register(schemes.Entry{
+			Kind: schemes.KindKEM, Name: s.Name(), KEM: s,
+		}); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package frodo640_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/frodo640"
+)
+
+func shake128(s string, l int) []byte {
+	h := sha3.NewShake128()
+	_, _ = h.Write([]byte(s))
+	out := make([]byte, l)
+	_, _ = h.Read(out)
+	return out
+}
+
+func digest(b []byte) string {
+	h := sha3.New256()
+	_, _ = h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func schemes() []kem.Scheme {
+	return []kem.Scheme{frodo640.AES, frodo640.SHAKE}
+}
+
+// Known-answer vectors cross-checked against an independent implementation
+// of the round-3 FrodoKEM specification. The keygen seed s||seedSE||z is
+// SHAKE128("seed-<name>") and the encapsulation seed mu is
+// SHAKE128("eseed-<name>"); values are SHA3-256 digests.
+var katVectors = []struct {
+	name           string
+	pk, sk, ct, ss string
+}{
+	{
+		"FrodoKEM-640-AES",
+		"eda3bfc6c755a7cb4955c732c2d1904e2b98a486bf4fe7ab8b1f575a5bc4713e",
+		"a614879eacf49c58b456d04f5a0668bad410865fcfca6e8053f96a76ae0e174e",
+		"bd18c34ec1baf12a0a02179a0c6ad2fc363bed0a03a988c22551f59217f23e08",
+		"efd3161fcb91d218a0f4e0451001f15c9de73ec4656ab4e7dad04885d5875f1c",
+	},
+	{
+		"FrodoKEM-640-SHAKE",
+		"5985c87c4171f40bcd8aafeeb74fc8c61cdcf64af107af59e13e58b6a8d31e80",
+		"2488c636deb4b3834fed08ffd763ca3e45cfbc8c34c7b841b23733e1820383b5",
+		"f4f492037544f192ecb750ee351697fd1787684b00f7049c26175b4a469fbbcd",
+		"f6533d77b338516670e348721b4a56a7624d6dae7a68b24b3a36dfef02a3d4ac",
+	},
+}
+
+func schemeByName(t *testing.T, name string) kem.Scheme {
+	t.Helper()
+	for _, s := range schemes() {
+		if s.Name() == name {
+			return s
+		}
+	}
+	t.Fatalf("unknown scheme %s", name)
+	return nil
+}
+
+func TestKAT(t *testing.T) {
+	for _, v := range katVectors {
+		t.Run(v.name, func(t *testing.T) {
+			sch := schemeByName(t, v.name)
+			seed := shake128("seed-"+v.name, sch.SeedSize())
+			eseed := shake128("eseed-"+v.name, sch.EncapsulationSeedSize())
+
+			pub, priv := sch.DeriveKeyPair(seed)
+			pk, err := pub.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sk, err := priv.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if digest(pk) != v.pk {
+				t.Error("public key mismatch")
+			}
+			if digest(sk) != v.sk {
+				t.Error("private key mismatch")
+			}
+
+			ct, ss, err := sch.EncapsulateDeterministically(pub, eseed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if digest(ct) != v.ct {
+				t.Error("ciphertext mismatch")
+			}
+			if digest(ss) != v.ss {
+				t.Error("shared secret mismatch")
+			}
+
+			ss2, err := sch.Decapsulate(priv, ct)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(ss, ss2) {
+				t.Error("decapsulation mismatch")
+			}
+		})
+	}
+}
+
+func TestRoundtrip(t *testing.T) {
+	for _, sch := range schemes() {
+		t.Run(sch.Name(), func(t *testing.T) {
+			pub, priv, err := sch.GenerateKeyPair()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 4; i++ {
+				ct, ss, err := sch.Encapsulate(pub)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ss2, err := sch.Decapsulate(priv, ct)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(ss, ss2) {
+					t.Fatal("shared secrets differ")
+				}
+			}
+		})
+	}
+}
+
+func TestImplicitRejection(t *testing.T) {
+	for _, sch := range schemes() {
+		t.Run(sch.Name(), func(t *testing.T) {
+			pub, priv, err := sch.GenerateKeyPair()
+			if err != nil {
+				t.Fatal(err)
+			}
+			ct, ss, err := sch.Encapsulate(pub)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, i := range []int{0, len(ct) / 2, len(ct) - 1} {
+				bad := append([]byte{}, ct...)
+				bad[i] ^= 1
+				got, err := sch.Decapsulate(priv, bad)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if bytes.Equal(got, ss) {
+					t.Errorf("tampered ciphertext (byte %d) yields the honest secret", i)
+				}
+				// Implicit rejection must be deterministic.
+				again, err := sch.Decapsulate(priv, bad)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, again) {
+					t.Errorf("rejection secret not deterministic (byte %d)", i)
+				}
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	sch := frodo640.SHAKE
+	pub, priv, err := sch.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := pub.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub2, err := sch.UnmarshalBinaryPublicKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub2.Equal(pub) {
+		t.Error("public key does not roundtrip")
+	}
+
+	sk, err := priv.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv2, err := sch.UnmarshalBinaryPrivateKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !priv2.Equal(priv) {
+		t.Error("private key does not roundtrip")
+	}
+
+	// The restored key must decapsulate what the original key agreed on.
+	ct, ss, err := sch.Encapsulate(pub2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss2, err := sch.Decapsulate(priv2, ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ss, ss2) {
+		t.Error("restored keys disagree")
+	}
+
+	if _, err = sch.UnmarshalBinaryPublicKey(pk[:len(pk)-1]); err == nil {
+		t.Error("truncated public key accepted")
+	}
+	if _, err = sch.UnmarshalBinaryPrivateKey(sk[:len(sk)-1]); err == nil {
+		t.Error("truncated private key accepted")
+	}
+}
+
+func BenchmarkFrodo640(b *testing.B) {
+	sch := frodo640.SHAKE
+	pub, priv, err := sch.GenerateKeyPair()
+	if err != nil {
+		b.Fatal(err)
+	}
+	ct, _, err := sch.Encapsulate(pub)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("Encapsulate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = sch.Encapsulate(pub)
+		}
+	})
+	b.Run("Decapsulate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = sch.Decapsulate(priv, ct)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+// Package frodo976 provides the FrodoKEM-976 parameter set, an
+// unstructured-lattice KEM at NIST security category 3, in its AES and
+// SHAKE variants. See kem/internal/frodo for the construction.
+package frodo976
+
+import (
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/internal/frodo"
+	"github.com/cloudflare/circl/schemes"
+)
+
+var cdf = []uint16{
+	5638, 15915, 23689, 28571, 31116, 32217,
+	32613, 32731, 32760, 32766, 32767,
+}
+
+// AES and SHAKE differ only in how the public matrix is expanded.
+var (
+	AES kem.Scheme = frodo.NewScheme(frodo.Params{
+		Name: "FrodoKEM-976-AES", N: 976, D: 16, B: 3, Sec: 24, CDF: cdf, AES: true,
+	})
+	SHAKE kem.Scheme = frodo.NewScheme(frodo.Params{
+		Name: "FrodoKEM-976-SHAKE", N: 976, D: 16, B: 3, Sec: 24, CDF: cdf,
+	})
+)
+
+func init() {
+	for _, s := range []kem.Scheme{AES, SHAKE} {
+		if err := schemes.Register(schemes.Entry{
+			Kind: schemes.KindKEM, Name: s.Name(), KEM: s,
+		}); err != nil {
+			panic(err)
+		}
+	}
+}
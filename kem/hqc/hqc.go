@@ -0,0 +1,726 @@
+// Package hqc implements HQC, the code-based KEM selected by NIST for
+// standardization out of the fourth round.
+//
+// HQC builds on the hardness of decoding quasi-cyclic codes and makes
+// no lattice assumption at all, which makes it a natural hedge next to
+// the module-lattice schemes. The implementation follows the
+// fourth-round (2023) specification: the public code is a tensor of a
+// shortened Reed-Solomon code with a duplicated first-order Reed-Muller
+// code, and both decoders run in constant time. BIKE, the other
+// code-based fourth-round candidate, is not implemented.
+package hqc
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+	"math/bits"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/wipe"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/rand"
+	"github.com/cloudflare/circl/schemes"
+)
+
+const (
+	seedSize = 40 // per the reference implementation's NIST API
+	saltSize = 16
+	ssSize   = 64
+)
+
+// Domain-separation bytes appended to every SHAKE256 call, matching the
+// reference implementation.
+const (
+	domExpander = 2
+	domG        = 3
+	domK        = 4
+)
+
+type scheme struct {
+	name  string
+	n     int // ambient block length, prime
+	n1    int // Reed-Solomon code length
+	mult  int // Reed-Muller duplications; n2 = 128*mult
+	k     int // message bytes, the Reed-Solomon dimension
+	delta int // Reed-Solomon correction capacity
+	w     int // weight of x and y
+	wr    int // weight of r1 and r2
+	we    int // weight of e
+	gen   []byte
+}
+
+// HQC128, HQC192 and HQC256 are the three parameter sets of the
+// fourth-round specification, at NIST security categories 1, 3, and 5.
+var (
+	HQC128 kem.Scheme = &scheme{
+		name: "HQC-128", n: 17669, n1: 46, mult: 3, k: 16,
+		delta: 15, w: 66, wr: 75, we: 75,
+	}
+	HQC192 kem.Scheme = &scheme{
+		name: "HQC-192", n: 35851, n1: 56, mult: 5, k: 24,
+		delta: 16, w: 100, wr: 114, we: 114,
+	}
+	HQC256 kem.Scheme = &scheme{
+		name: "HQC-256", n: 57637, n1: 90, mult: 5, k: 32,
+		delta: 29, w: 131, wr: 149, we: 149,
+	}
+)
+
+func init() {
+	initGF()
+	for _, s := range []kem.Scheme{HQC128, HQC192, HQC256} {
+		s.(*scheme).gen = rsGenPoly(s.(*scheme).delta)
+		if err := schemes.Register(schemes.Entry{
+			Kind: schemes.KindKEM, Name: s.Name(), KEM: s,
+		}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (s *scheme) Name() string               { return s.name }
+func (s *scheme) SharedKeySize() int         { return ssSize }
+func (s *scheme) SeedSize() int              { return seedSize }
+func (s *scheme) EncapsulationSeedSize() int { return s.k + saltSize }
+
+func (s *scheme) nBytes() int { return (s.n + 7) / 8 }
+func (s *scheme) nWords() int { return (s.n + 63) / 64 }
+func (s *scheme) n2() int     { return 128 * s.mult }
+func (s *scheme) vBytes() int { return s.n1 * s.n2() / 8 }
+
+func (s *scheme) PublicKeySize() int  { return seedSize + s.nBytes() }
+func (s *scheme) PrivateKeySize() int { return seedSize + s.k + s.PublicKeySize() }
+func (s *scheme) CiphertextSize() int { return s.nBytes() + s.vBytes() + saltSize }
+
+// Arithmetic in GF(256) with the primitive polynomial x^8+x^4+x^3+x^2+1.
+// Multiplication masks out the zero cases so that table lookups never
+// branch on secrets.
+var (
+	gfExp [510]byte
+	gfLog [256]byte
+)
+
+func initGF() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for i := 255; i < 510; i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+// gfNonzero returns 0xff if a is nonzero and 0 otherwise.
+func gfNonzero(a byte) byte {
+	m := uint32(a)
+
+	return byte(((m | -m) >> 31) * 0xff)
+}
+
+func gfMul(a, b byte) byte {
+	m := gfNonzero(a) & gfNonzero(b)
+
+	return gfExp[int(gfLog[a])+int(gfLog[b])] & m
+}
+
+func gfInv(a byte) byte {
+	return gfExp[255-int(gfLog[a])] & gfNonzero(a)
+}
+
+// geMask returns 0xff if a >= b and 0 otherwise, for small nonnegative
+// arguments.
+func geMask(a, b int) byte {
+	return byte(((uint32(b) - uint32(a) - 1) >> 31) * 0xff)
+}
+
+// rsGenPoly returns the generator of the [n1, n1-2*delta] Reed-Solomon
+// code, the monic polynomial with roots alpha^1 .. alpha^(2*delta).
+func rsGenPoly(delta int) []byte {
+	g := make([]byte, 1, 2*delta+1)
+	g[0] = 1
+	for i := 1; i <= 2*delta; i++ {
+		root := gfExp[i]
+		g = append(g, 0)
+		for j := len(g) - 1; j > 0; j-- {
+			g[j] = g[j-1] ^ gfMul(g[j], root)
+		}
+		g[0] = gfMul(g[0], root)
+	}
+
+	return g
+}
+
+// rsEncode computes the systematic codeword of msg, parity symbols
+// first.
+func (s *scheme) rsEncode(msg []byte) []byte {
+	t2 := 2 * s.delta
+	cw := make([]byte, s.n1)
+	copy(cw[t2:], msg)
+	rem := cw[:t2]
+	for i := s.k - 1; i >= 0; i-- {
+		c := msg[i] ^ rem[t2-1]
+		for j := t2 - 1; j > 0; j-- {
+			rem[j] = rem[j-1] ^ gfMul(c, s.gen[j])
+		}
+		rem[0] = gfMul(c, s.gen[0])
+	}
+
+	return cw
+}
+
+func polyEval(p []byte, x byte) byte {
+	var acc byte
+	for i := len(p) - 1; i >= 0; i-- {
+		acc = gfMul(acc, x) ^ p[i]
+	}
+
+	return acc
+}
+
+// rsDecode corrects up to delta symbol errors in cw and returns the
+// message, running inversionless Berlekamp-Massey and Forney's formula
+// over every position without branching on secrets.
+func (s *scheme) rsDecode(cw []byte) []byte {
+	t2 := 2 * s.delta
+
+	synd := make([]byte, t2)
+	for i := 0; i < t2; i++ {
+		synd[i] = polyEval(cw, gfExp[i+1])
+	}
+
+	sigma := make([]byte, s.delta+1)
+	bpol := make([]byte, s.delta+1)
+	sigma[0], bpol[0] = 1, 1
+	bcoef := byte(1)
+	ell := 0
+	for step := 0; step < t2; step++ {
+		var d byte
+		for i := 0; i <= s.delta && i <= step; i++ {
+			d ^= gfMul(sigma[i], synd[step-i])
+		}
+		swap := gfNonzero(d) & geMask(step, 2*ell)
+		for i := s.delta; i >= 0; i-- {
+			var bx byte
+			if i > 0 {
+				bx = bpol[i-1]
+			}
+			ns := gfMul(bcoef, sigma[i]) ^ gfMul(d, bx)
+			bpol[i] = (sigma[i] & swap) | (bx &^ swap)
+			sigma[i] = ns
+		}
+		bcoef = (d & swap) | (bcoef &^ swap)
+		ell += int(swap&1) * (step + 1 - 2*ell)
+	}
+
+	omega := make([]byte, t2)
+	for i := 0; i < t2; i++ {
+		var acc byte
+		for j := 0; j <= i && j <= s.delta; j++ {
+			acc ^= gfMul(sigma[j], synd[i-j])
+		}
+		omega[i] = acc
+	}
+
+	for j := 0; j < s.n1; j++ {
+		xinv := gfExp[(255-j%255)%255]
+		errAt := ^gfNonzero(polyEval(sigma, xinv))
+		// sigma'(x) keeps the odd-degree terms in characteristic 2.
+		var dv byte
+		x2 := gfMul(xinv, xinv)
+		pw := byte(1)
+		for i := 1; i <= s.delta; i += 2 {
+			dv ^= gfMul(sigma[i], pw)
+			pw = gfMul(pw, x2)
+		}
+		cw[j] ^= gfMul(polyEval(omega, xinv), gfInv(dv)) & errAt
+	}
+
+	return cw[t2 : t2+s.k]
+}
+
+// rmEncode expands one symbol into the 128-bit codeword of the
+// first-order Reed-Muller code RM(1,7).
+func rmEncode(b byte) (lo, hi uint64) {
+	for j := 0; j < 128; j++ {
+		bit := uint64(bits.OnesCount8(b&0x7f&byte(j))&1) ^ uint64(b>>7)
+		if j < 64 {
+			lo |= bit << uint(j)
+		} else {
+			hi |= bit << uint(j-64)
+		}
+	}
+
+	return
+}
+
+// hadamard transforms t into the correlations with all 128 linear
+// functions.
+func hadamard(t *[128]int32) {
+	for step := 1; step < 128; step <<= 1 {
+		for i := 0; i < 128; i += step << 1 {
+			for j := i; j < i+step; j++ {
+				a, b := t[j], t[j+step]
+				t[j], t[j+step] = a+b, a-b
+			}
+		}
+	}
+}
+
+// findPeak locates the largest correlation in constant time; its index
+// gives the low seven message bits and its sign the top bit.
+func findPeak(t *[128]int32) byte {
+	var best, bestIdx, bestNeg int32
+	for i := 0; i < 128; i++ {
+		v := t[i]
+		sign := v >> 31
+		abs := (v ^ sign) - sign
+		m := (best - abs) >> 31 // all ones when abs > best
+		best = (abs & m) | (best &^ m)
+		bestIdx = (int32(i) & m) | (bestIdx &^ m)
+		bestNeg = ((sign & 1) & m) | (bestNeg &^ m)
+	}
+
+	return byte(bestIdx) | byte(bestNeg<<7)
+}
+
+// codeEncode writes the concatenated-code encoding of msg into the low
+// n1*n2 bits of vec.
+func (s *scheme) codeEncode(vec []uint64, msg []byte) {
+	cw := s.rsEncode(msg)
+	bit := 0
+	for _, sym := range cw {
+		lo, hi := rmEncode(sym)
+		for c := 0; c < s.mult; c++ {
+			vec[bit/64] ^= lo
+			vec[bit/64+1] ^= hi
+			bit += 128
+		}
+	}
+}
+
+// codeDecode recovers the message from the noisy low n1*n2 bits of vec.
+func (s *scheme) codeDecode(vec []uint64) []byte {
+	cw := make([]byte, s.n1)
+	bit := 0
+	var corr [128]int32
+	for j := range cw {
+		for i := range corr {
+			corr[i] = 0
+		}
+		for c := 0; c < s.mult; c++ {
+			for i := 0; i < 128; i++ {
+				b := int32(vec[bit/64] >> (uint(bit) & 63) & 1)
+				corr[i] += 1 - 2*b
+				bit++
+			}
+		}
+		hadamard(&corr)
+		cw[j] = findPeak(&corr)
+	}
+
+	return s.rsDecode(cw)
+}
+
+// Vectors over GF(2)[x]/(x^n - 1), bit i of the polynomial stored at
+// bit i of the little-endian word array.
+
+func (s *scheme) bytesToVec(b []byte) []uint64 {
+	v := make([]uint64, s.nWords())
+	for i, c := range b {
+		v[i/8] |= uint64(c) << uint(8*(i%8))
+	}
+
+	return v
+}
+
+func (s *scheme) vecToBytes(v []uint64, n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(v[i/8] >> uint(8*(i%8)))
+	}
+
+	return b
+}
+
+func (s *scheme) maskVec(v []uint64) {
+	if r := uint(s.n % 64); r != 0 {
+		v[s.nWords()-1] &= (1 << r) - 1
+	}
+}
+
+// truncV zeroes the bits of v at or above n1*n2.
+func (s *scheme) truncV(v []uint64) {
+	for i := s.n1 * s.n2() / 64; i < len(v); i++ {
+		v[i] = 0
+	}
+}
+
+func sparseXor(dst []uint64, support []uint32) {
+	for _, p := range support {
+		dst[p/64] ^= 1 << uint(p%64)
+	}
+}
+
+// mulSparse adds dense*sparse to dst, rotating the dense vector once
+// per support position and folding back modulo x^n - 1.
+func (s *scheme) mulSparse(dst, dense []uint64, support []uint32) {
+	nw := s.nWords()
+	hw, hb := uint(s.n)/64, uint(s.n)%64
+	tmp := make([]uint64, 2*nw)
+	for _, p := range support {
+		for i := range tmp {
+			tmp[i] = 0
+		}
+		wsh, bsh := int(p/64), uint(p%64)
+		for i, w := range dense {
+			tmp[i+wsh] ^= w << bsh
+			if bsh != 0 {
+				tmp[i+wsh+1] ^= w >> (64 - bsh)
+			}
+		}
+		for k := 0; k < nw; k++ {
+			lo := tmp[hw+uint(k)] >> hb
+			if hb != 0 && hw+uint(k)+1 < uint(len(tmp)) {
+				lo |= tmp[hw+uint(k)+1] << (64 - hb)
+			}
+			tmp[k] ^= lo
+		}
+		for i := 0; i < nw; i++ {
+			dst[i] ^= tmp[i]
+		}
+	}
+	s.maskVec(dst)
+}
+
+// expander returns the SHAKE256 stream the reference calls the
+// seedexpander.
+func expander(seed []byte) *sha3.State {
+	h := sha3.NewShake256()
+	_, _ = h.Write(seed)
+	_, _ = h.Write([]byte{domExpander})
+
+	return &h
+}
+
+func hashFn(domain byte, parts ...[]byte) []byte {
+	h := sha3.NewShake256()
+	for _, m := range parts {
+		_, _ = h.Write(m)
+	}
+	_, _ = h.Write([]byte{domain})
+	out := make([]byte, ssSize)
+	_, _ = h.Read(out)
+
+	return out
+}
+
+// sampleFixedWeight draws a support of the given weight with the
+// constant-time method of the reference implementation (Sendrier,
+// "Secure Sampling of Constant-Weight Words"): position i is drawn from
+// [i, n) by a multiply-and-shift reduction whose bias is negligible,
+// and a position colliding with a later one is replaced by i itself,
+// which is never taken by the later draws. Neither the number of XOF
+// reads nor the collision handling depends on the secret positions.
+func (s *scheme) sampleFixedWeight(xof *sha3.State, weight int) []uint32 {
+	b := make([]byte, 4*weight)
+	_, _ = xof.Read(b)
+	supp := make([]uint32, weight)
+	for i := range supp {
+		r := binary.LittleEndian.Uint32(b[4*i:])
+		supp[i] = uint32(i) + uint32(uint64(r)*uint64(s.n-i)>>32)
+	}
+	for i := weight - 1; i >= 0; i-- {
+		dup := 0
+		for j := i + 1; j < weight; j++ {
+			dup |= subtle.ConstantTimeEq(int32(supp[j]), int32(supp[i]))
+		}
+		supp[i] = uint32(subtle.ConstantTimeSelect(dup, i, int(supp[i])))
+	}
+
+	return supp
+}
+
+func (s *scheme) sampleDense(xof *sha3.State) []uint64 {
+	b := make([]byte, s.nBytes())
+	_, _ = xof.Read(b)
+	v := s.bytesToVec(b)
+	s.maskVec(v)
+
+	return v
+}
+
+// PublicKey is an HQC encapsulation key.
+type PublicKey struct {
+	sch *scheme
+	pk  []byte // seed of h || s
+	h   []uint64
+	s   []uint64
+}
+
+// PrivateKey is an HQC decapsulation key.
+type PrivateKey struct {
+	sch   *scheme
+	seed  []byte // expands to the sparse secrets x and y
+	sigma []byte // implicit-rejection secret
+	y     []uint32
+	pub   *PublicKey
+}
+
+func (pk *PublicKey) Scheme() kem.Scheme { return pk.sch }
+
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, pk.pk...), nil
+}
+
+func (pk *PublicKey) Equal(other kem.PublicKey) bool {
+	o, ok := other.(*PublicKey)
+
+	return ok && pk.sch == o.sch && subtle.ConstantTimeCompare(pk.pk, o.pk) == 1
+}
+
+func (sk *PrivateKey) Scheme() kem.Scheme { return sk.sch }
+
+func (sk *PrivateKey) Public() kem.PublicKey { return sk.pub }
+
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, sk.sch.PrivateKeySize())
+	out = append(out, sk.seed...)
+	out = append(out, sk.sigma...)
+
+	return append(out, sk.pub.pk...), nil
+}
+
+func (sk *PrivateKey) Equal(other kem.PrivateKey) bool {
+	o, ok := other.(*PrivateKey)
+	if !ok || sk.sch != o.sch {
+		return false
+	}
+	a, _ := sk.MarshalBinary()
+	b, _ := o.MarshalBinary()
+
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Wipe zeroes the secret seed, the rejection secret, and the support of
+// y.
+func (sk *PrivateKey) Wipe() {
+	wipe.Bytes(sk.seed)
+	wipe.Bytes(sk.sigma)
+	for i := range sk.y {
+		sk.y[i] = 0
+	}
+}
+
+func (s *scheme) parsePublicKey(data []byte) (*PublicKey, error) {
+	pk := &PublicKey{sch: s, pk: append([]byte{}, data...)}
+	pk.h = s.sampleDense(expander(data[:seedSize]))
+	pk.s = s.bytesToVec(data[seedSize:])
+	if r := uint(s.n % 64); r != 0 && pk.s[s.nWords()-1]>>r != 0 {
+		return nil, kem.ErrPubKey
+	}
+
+	return pk, nil
+}
+
+func (s *scheme) deriveKeyPair(seed []byte) (*PublicKey, *PrivateKey) {
+	exp := expander(seed)
+	skSeed := make([]byte, seedSize)
+	pkSeed := make([]byte, seedSize)
+	sigma := make([]byte, s.k)
+	_, _ = exp.Read(skSeed)
+	_, _ = exp.Read(pkSeed)
+	_, _ = exp.Read(sigma)
+
+	skExp := expander(skSeed)
+	x := s.sampleFixedWeight(skExp, s.w)
+	y := s.sampleFixedWeight(skExp, s.w)
+	h := s.sampleDense(expander(pkSeed))
+
+	sv := make([]uint64, s.nWords())
+	sparseXor(sv, x)
+	s.mulSparse(sv, h, y) // s = x + h*y
+
+	pk := &PublicKey{sch: s, h: h, s: sv}
+	pk.pk = make([]byte, 0, s.PublicKeySize())
+	pk.pk = append(pk.pk, pkSeed...)
+	pk.pk = append(pk.pk, s.vecToBytes(sv, s.nBytes())...)
+
+	sk := &PrivateKey{
+		sch:   s,
+		seed:  append([]byte{}, skSeed...),
+		sigma: sigma,
+		y:     y,
+		pub:   pk,
+	}
+
+	return pk, sk
+}
+
+func (s *scheme) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
+	if len(seed) != s.SeedSize() {
+		panic(kem.ErrSeedSize)
+	}
+	pk, sk := s.deriveKeyPair(seed)
+
+	return pk, sk
+}
+
+func (s *scheme) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
+	seed := make([]byte, s.SeedSize())
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	pk, sk := s.deriveKeyPair(seed)
+
+	return pk, sk, nil
+}
+
+// encrypt computes (u, v) for message m under pk with randomness
+// expanded from theta.
+func (s *scheme) encrypt(pk *PublicKey, m, theta []byte) (u, v []uint64) {
+	exp := expander(theta)
+	r1 := s.sampleFixedWeight(exp, s.wr)
+	r2 := s.sampleFixedWeight(exp, s.wr)
+	e := s.sampleFixedWeight(exp, s.we)
+
+	u = make([]uint64, s.nWords())
+	sparseXor(u, r1)
+	s.mulSparse(u, pk.h, r2) // u = r1 + h*r2
+
+	v = make([]uint64, s.nWords())
+	s.codeEncode(v, m)
+	s.mulSparse(v, pk.s, r2) // v = C(m) + s*r2 + e, truncated
+	sparseXor(v, e)
+	s.truncV(v)
+
+	return
+}
+
+func (s *scheme) encapsulate(ct, ss []byte, pub kem.PublicKey, seed []byte) error {
+	pk, ok := pub.(*PublicKey)
+	if !ok || pk.sch != s {
+		return kem.ErrTypeMismatch
+	}
+	if len(ct) != s.CiphertextSize() || len(ss) != ssSize {
+		return kem.ErrCiphertextSize
+	}
+	m, salt := seed[:s.k], seed[s.k:]
+	theta := hashFn(domG, m, pk.pk[:32], salt)
+	u, v := s.encrypt(pk, m, theta)
+	ub := s.vecToBytes(u, s.nBytes())
+	vb := s.vecToBytes(v, s.vBytes())
+	copy(ct, ub)
+	copy(ct[s.nBytes():], vb)
+	copy(ct[s.nBytes()+s.vBytes():], salt)
+	copy(ss, hashFn(domK, m, ub, vb))
+
+	return nil
+}
+
+func (s *scheme) EncapsulateTo(ct, ss []byte, pk kem.PublicKey) error {
+	seed := make([]byte, s.EncapsulationSeedSize())
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return err
+	}
+
+	return s.encapsulate(ct, ss, pk, seed)
+}
+
+func (s *scheme) Encapsulate(pk kem.PublicKey) (ct, ss []byte, err error) {
+	ct = make([]byte, s.CiphertextSize())
+	ss = make([]byte, ssSize)
+	if err = s.EncapsulateTo(ct, ss, pk); err != nil {
+		return nil, nil, err
+	}
+
+	return ct, ss, nil
+}
+
+func (s *scheme) EncapsulateDeterministically(pk kem.PublicKey, seed []byte) (ct, ss []byte, err error) {
+	if len(seed) != s.EncapsulationSeedSize() {
+		return nil, nil, kem.ErrSeedSize
+	}
+	ct = make([]byte, s.CiphertextSize())
+	ss = make([]byte, ssSize)
+	if err = s.encapsulate(ct, ss, pk, seed); err != nil {
+		return nil, nil, err
+	}
+
+	return ct, ss, nil
+}
+
+func (s *scheme) DecapsulateTo(ss []byte, priv kem.PrivateKey, ct []byte) error {
+	sk, ok := priv.(*PrivateKey)
+	if !ok || sk.sch != s {
+		return kem.ErrTypeMismatch
+	}
+	if len(ct) != s.CiphertextSize() || len(ss) != ssSize {
+		return kem.ErrCiphertextSize
+	}
+	ub := ct[:s.nBytes()]
+	vb := ct[s.nBytes() : s.nBytes()+s.vBytes()]
+	salt := ct[s.nBytes()+s.vBytes():]
+
+	u := s.bytesToVec(ub)
+	s.maskVec(u)
+	t := s.bytesToVec(vb)
+	s.mulSparse(t, u, sk.y) // v - u*y, truncated
+	s.truncV(t)
+	m := s.codeDecode(t)
+
+	// Re-encrypt and fall back to sigma on mismatch.
+	theta := hashFn(domG, m, sk.pub.pk[:32], salt)
+	u2, v2 := s.encrypt(sk.pub, m, theta)
+	same := subtle.ConstantTimeCompare(ub, s.vecToBytes(u2, s.nBytes())) &
+		subtle.ConstantTimeCompare(vb, s.vecToBytes(v2, s.vBytes()))
+	for i := range m {
+		m[i] = byte(subtle.ConstantTimeSelect(same, int(m[i]), int(sk.sigma[i])))
+	}
+	copy(ss, hashFn(domK, m, ub, vb))
+
+	return nil
+}
+
+func (s *scheme) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
+	ss := make([]byte, ssSize)
+	if err := s.DecapsulateTo(ss, sk, ct); err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+func (s *scheme) UnmarshalBinaryPublicKey(data []byte) (kem.PublicKey, error) {
+	if len(data) != s.PublicKeySize() {
+		return nil, kem.ErrPubKeySize
+	}
+
+	return s.parsePublicKey(data)
+}
+
+func (s *scheme) UnmarshalBinaryPrivateKey(data []byte) (kem.PrivateKey, error) {
+	if len(data) != s.PrivateKeySize() {
+		return nil, kem.ErrPrivKeySize
+	}
+	pub, err := s.parsePublicKey(data[seedSize+s.k:])
+	if err != nil {
+		return nil, err
+	}
+	skExp := expander(data[:seedSize])
+	_ = s.sampleFixedWeight(skExp, s.w) // skip x
+	y := s.sampleFixedWeight(skExp, s.w)
+
+	return &PrivateKey{
+		sch:   s,
+		seed:  append([]byte{}, data[:seedSize]...),
+		sigma: append([]byte{}, data[seedSize:seedSize+s.k]...),
+		y:     y,
+		pub:   pub,
+	}, nil
+}
@@ -0,0 +1,376 @@
+// Package hybrid implements the hybrid KEMs behind the TLS 1.3
+// post-quantum key-exchange groups: an ECDHE half and an ML-KEM half
+// run side by side, shares carry the concatenated component values, and
+// the shared secret is the concatenation of both component secrets. The
+// component order follows the draft-ietf-tls-ecdhe-mlkem wire formats:
+// X25519MLKEM768 puts the ML-KEM part first, while the NIST-curve group
+// puts the ECDHE part first. The schemes register their TLS codepoints,
+// so package tlsutil picks them up for key-share generation.
+//
+// As KEMs, the ECDHE halves are ephemeral Diffie-Hellman: the
+// "ciphertext" is the encapsulator's ephemeral public key.
+package hybrid
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/cloudflare/circl/dh/x25519"
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/mlkem"
+	"github.com/cloudflare/circl/rand"
+	"github.com/cloudflare/circl/schemes"
+)
+
+var (
+	// X25519MLKEM768 is the TLS group 0x11EC.
+	X25519MLKEM768 kem.Scheme = &scheme{
+		name: "X25519MLKEM768", codepoint: 0x11EC,
+		dh: x25519DH{}, pq: mlkem.MLKEM768,
+	}
+	// SecP384r1MLKEM1024 is the TLS group 0x11ED.
+	SecP384r1MLKEM1024 kem.Scheme = &scheme{
+		name: "SecP384r1MLKEM1024", codepoint: 0x11ED,
+		dh: p384DH{}, pq: mlkem.MLKEM1024, dhFirst: true,
+	}
+)
+
+func init() {
+	for _, s := range []kem.Scheme{X25519MLKEM768, SecP384r1MLKEM1024} {
+		if err := schemes.Register(schemes.Entry{
+			Kind: schemes.KindKEM, Name: s.Name(),
+			Codepoint: s.(*scheme).codepoint, KEM: s,
+		}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// dhPart is the ephemeral Diffie-Hellman half of a hybrid scheme.
+type dhPart interface {
+	pkSize() int
+	skSize() int
+	ssSize() int
+	generate(rnd io.Reader) (sk, pk []byte, err error)
+	// derive computes a key pair from skSize seed bytes.
+	derive(seed []byte) (sk, pk []byte)
+	public(sk []byte) []byte
+	shared(sk, peer []byte) ([]byte, error)
+}
+
+type x25519DH struct{}
+
+func (x25519DH) pkSize() int { return x25519.Size }
+func (x25519DH) skSize() int { return x25519.Size }
+func (x25519DH) ssSize() int { return x25519.Size }
+
+func (d x25519DH) generate(rnd io.Reader) ([]byte, []byte, error) {
+	seed := make([]byte, x25519.Size)
+	if _, err := io.ReadFull(rnd, seed); err != nil {
+		return nil, nil, err
+	}
+	sk, pk := d.derive(seed)
+
+	return sk, pk, nil
+}
+
+func (d x25519DH) derive(seed []byte) ([]byte, []byte) {
+	var sec, pub x25519.Key
+	copy(sec[:], seed)
+	x25519.KeyGen(&pub, &sec)
+
+	return append([]byte{}, seed...), pub[:]
+}
+
+func (d x25519DH) public(sk []byte) []byte {
+	_, pk := d.derive(sk)
+
+	return pk
+}
+
+func (x25519DH) shared(sk, peer []byte) ([]byte, error) {
+	var ss, sec, pub x25519.Key
+	copy(sec[:], sk)
+	copy(pub[:], peer)
+	if err := x25519.SharedContributory(&ss, &sec, &pub); err != nil {
+		return nil, kem.ErrPubKey
+	}
+
+	return ss[:], nil
+}
+
+type p384DH struct{}
+
+func (p384DH) pkSize() int { return 97 } // uncompressed point
+func (p384DH) skSize() int { return 48 }
+func (p384DH) ssSize() int { return 48 } // x-coordinate
+
+func (p384DH) generate(rnd io.Reader) ([]byte, []byte, error) {
+	s := group.P384.RandomNonZeroScalar(rnd)
+	sk, err := s.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	pk, err := group.P384.NewElement().MulGen(s).MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sk, pk, nil
+}
+
+func (p384DH) derive(seed []byte) ([]byte, []byte) {
+	s := group.P384.HashToScalar(seed, []byte("kem/hybrid: P-384 derivation"))
+	sk, err := s.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	pk, err := group.P384.NewElement().MulGen(s).MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+
+	return sk, pk
+}
+
+func (p384DH) public(sk []byte) []byte {
+	s := group.P384.NewScalar()
+	if err := s.UnmarshalBinary(sk); err != nil {
+		panic(err)
+	}
+	pk, err := group.P384.NewElement().MulGen(s).MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+
+	return pk
+}
+
+func (p384DH) shared(sk, peer []byte) ([]byte, error) {
+	s := group.P384.NewScalar()
+	if err := s.UnmarshalBinary(sk); err != nil {
+		return nil, kem.ErrPrivKey
+	}
+	e := group.P384.NewElement()
+	if err := e.UnmarshalBinary(peer); err != nil {
+		return nil, kem.ErrPubKey
+	}
+	e.Mul(e, s)
+	if e.IsIdentity() {
+		return nil, kem.ErrPubKey
+	}
+	out, err := e.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return out[1:49], nil
+}
+
+// scheme runs a dhPart and an ML-KEM side by side. dhFirst selects the
+// component order of the concatenated encodings and shared secrets.
+type scheme struct {
+	name      string
+	codepoint uint
+	dh        dhPart
+	pq        kem.Scheme
+	dhFirst   bool
+}
+
+func (s *scheme) Name() string               { return s.name }
+func (s *scheme) PublicKeySize() int         { return s.dh.pkSize() + s.pq.PublicKeySize() }
+func (s *scheme) PrivateKeySize() int        { return s.dh.skSize() + s.pq.PrivateKeySize() }
+func (s *scheme) CiphertextSize() int        { return s.dh.pkSize() + s.pq.CiphertextSize() }
+func (s *scheme) SharedKeySize() int         { return s.dh.ssSize() + s.pq.SharedKeySize() }
+func (s *scheme) SeedSize() int              { return s.dh.skSize() + s.pq.SeedSize() }
+func (s *scheme) EncapsulationSeedSize() int { return s.dh.skSize() + s.pq.EncapsulationSeedSize() }
+
+// concat joins the component values in the scheme's wire order.
+func (s *scheme) concat(dh, pq []byte) []byte {
+	if s.dhFirst {
+		return append(append([]byte{}, dh...), pq...)
+	}
+
+	return append(append([]byte{}, pq...), dh...)
+}
+
+// split cuts a concatenated value back into its components.
+func (s *scheme) split(b []byte, dhLen int) (dh, pq []byte) {
+	if s.dhFirst {
+		return b[:dhLen], b[dhLen:]
+	}
+
+	return b[len(b)-dhLen:], b[:len(b)-dhLen]
+}
+
+// PublicKey is a hybrid public key.
+type PublicKey struct {
+	sch *scheme
+	dh  []byte
+	pq  kem.PublicKey
+}
+
+func (k *PublicKey) Scheme() kem.Scheme { return k.sch }
+
+func (k *PublicKey) MarshalBinary() ([]byte, error) {
+	pq, err := k.pq.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return k.sch.concat(k.dh, pq), nil
+}
+
+func (k *PublicKey) Equal(other kem.PublicKey) bool {
+	o, ok := other.(*PublicKey)
+
+	return ok && o.sch == k.sch && bytes.Equal(o.dh, k.dh) && k.pq.Equal(o.pq)
+}
+
+// PrivateKey is a hybrid private key.
+type PrivateKey struct {
+	sch *scheme
+	dh  []byte
+	pq  kem.PrivateKey
+}
+
+func (k *PrivateKey) Scheme() kem.Scheme { return k.sch }
+
+func (k *PrivateKey) MarshalBinary() ([]byte, error) {
+	pq, err := k.pq.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return k.sch.concat(k.dh, pq), nil
+}
+
+func (k *PrivateKey) Equal(other kem.PrivateKey) bool {
+	o, ok := other.(*PrivateKey)
+
+	return ok && o.sch == k.sch && bytes.Equal(o.dh, k.dh) && k.pq.Equal(o.pq)
+}
+
+func (k *PrivateKey) Public() kem.PublicKey {
+	return &PublicKey{k.sch, k.sch.dh.public(k.dh), k.pq.Public()}
+}
+
+func (s *scheme) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
+	dhSK, dhPK, err := s.dh.generate(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	pqPK, pqSK, err := s.pq.GenerateKeyPair()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &PublicKey{s, dhPK, pqPK}, &PrivateKey{s, dhSK, pqSK}, nil
+}
+
+func (s *scheme) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
+	if len(seed) != s.SeedSize() {
+		panic(kem.ErrSeedSize)
+	}
+	dhSeed, pqSeed := s.split(seed, s.dh.skSize())
+	dhSK, dhPK := s.dh.derive(dhSeed)
+	pqPK, pqSK := s.pq.DeriveKeyPair(pqSeed)
+
+	return &PublicKey{s, dhPK, pqPK}, &PrivateKey{s, dhSK, pqSK}
+}
+
+func (s *scheme) Encapsulate(pk kem.PublicKey) (ct, ss []byte, err error) {
+	pub, ok := pk.(*PublicKey)
+	if !ok || pub.sch != s {
+		return nil, nil, kem.ErrTypeMismatch
+	}
+	eSK, ePK, err := s.dh.generate(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.finishEncap(pub, eSK, ePK)
+}
+
+func (s *scheme) EncapsulateDeterministically(pk kem.PublicKey, seed []byte) (ct, ss []byte, err error) {
+	if len(seed) != s.EncapsulationSeedSize() {
+		return nil, nil, kem.ErrSeedSize
+	}
+	pub, ok := pk.(*PublicKey)
+	if !ok || pub.sch != s {
+		return nil, nil, kem.ErrTypeMismatch
+	}
+	dhSeed, pqSeed := s.split(seed, s.dh.skSize())
+	eSK, ePK := s.dh.derive(dhSeed)
+	dhSS, err := s.dh.shared(eSK, pub.dh)
+	if err != nil {
+		return nil, nil, err
+	}
+	pqCT, pqSS, err := s.pq.EncapsulateDeterministically(pub.pq, pqSeed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.concat(ePK, pqCT), s.concat(dhSS, pqSS), nil
+}
+
+func (s *scheme) finishEncap(pub *PublicKey, eSK, ePK []byte) (ct, ss []byte, err error) {
+	dhSS, err := s.dh.shared(eSK, pub.dh)
+	if err != nil {
+		return nil, nil, err
+	}
+	pqCT, pqSS, err := s.pq.Encapsulate(pub.pq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.concat(ePK, pqCT), s.concat(dhSS, pqSS), nil
+}
+
+func (s *scheme) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
+	priv, ok := sk.(*PrivateKey)
+	if !ok || priv.sch != s {
+		return nil, kem.ErrTypeMismatch
+	}
+	if len(ct) != s.CiphertextSize() {
+		return nil, kem.ErrCiphertextSize
+	}
+	ctDH, ctPQ := s.split(ct, s.dh.pkSize())
+	dhSS, err := s.dh.shared(priv.dh, ctDH)
+	if err != nil {
+		return nil, err
+	}
+	pqSS, err := s.pq.Decapsulate(priv.pq, ctPQ)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.concat(dhSS, pqSS), nil
+}
+
+func (s *scheme) UnmarshalBinaryPublicKey(data []byte) (kem.PublicKey, error) {
+	if len(data) != s.PublicKeySize() {
+		return nil, kem.ErrPubKeySize
+	}
+	dh, pqRaw := s.split(data, s.dh.pkSize())
+	pq, err := s.pq.UnmarshalBinaryPublicKey(pqRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicKey{s, append([]byte{}, dh...), pq}, nil
+}
+
+func (s *scheme) UnmarshalBinaryPrivateKey(data []byte) (kem.PrivateKey, error) {
+	if len(data) != s.PrivateKeySize() {
+		return nil, kem.ErrPrivKeySize
+	}
+	dh, pqRaw := s.split(data, s.dh.skSize())
+	pq, err := s.pq.UnmarshalBinaryPrivateKey(pqRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{s, append([]byte{}, dh...), pq}, nil
+}
@@ -0,0 +1,536 @@
+// Package frodo implements the FrodoKEM core shared by the frodo640,
+// frodo976, and frodo1344 packages.
+//
+// FrodoKEM is built on the plain learning-with-errors problem over
+// unstructured matrices: slower and bulkier than module-lattice KEMs,
+// but free of ring structure for threat models that distrust it. The
+// implementation follows the round-3 specification; the public matrix A
+// is expanded either with AES-128 or with SHAKE128, giving the two
+// variants of every parameter set.
+package frodo
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/wipe"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/rand"
+)
+
+const (
+	nBar      = 8
+	mBar      = 8
+	seedASize = 16
+	zSize     = 16
+)
+
+// Params fixes one FrodoKEM parameter set and A-expansion variant.
+type Params struct {
+	Name string
+	N    int      // matrix dimension
+	D    uint     // log2 of the modulus
+	B    uint     // bits encoded per matrix entry
+	Sec  int      // byte length of seeds, hashes, and the shared key
+	CDF  []uint16 // cumulative error-distribution table
+	AES  bool     // expand A with AES-128 instead of SHAKE128
+}
+
+// Scheme wraps a parameter set as a kem.Scheme.
+type Scheme struct{ p Params }
+
+func NewScheme(p Params) *Scheme { return &Scheme{p} }
+
+func (s *Scheme) Name() string               { return s.p.Name }
+func (s *Scheme) SharedKeySize() int         { return s.p.Sec }
+func (s *Scheme) SeedSize() int              { return 2*s.p.Sec + zSize }
+func (s *Scheme) EncapsulationSeedSize() int { return s.p.Sec }
+
+func (s *Scheme) PublicKeySize() int {
+	return seedASize + s.p.N*nBar*int(s.p.D)/8
+}
+
+func (s *Scheme) PrivateKeySize() int {
+	return 2*s.p.Sec + s.PublicKeySize() + 2*s.p.N*nBar
+}
+
+func (s *Scheme) CiphertextSize() int {
+	return (mBar*s.p.N + mBar*nBar) * int(s.p.D) / 8
+}
+
+// shakeSum is the level's hash: SHAKE128 at the 640 level, SHAKE256
+// above.
+func (s *Scheme) shakeSum(out int, parts ...[]byte) []byte {
+	var h sha3.State
+	if s.p.Sec == 16 {
+		h = sha3.NewShake128()
+	} else {
+		h = sha3.NewShake256()
+	}
+	for _, m := range parts {
+		_, _ = h.Write(m)
+	}
+	buf := make([]byte, out)
+	_, _ = h.Read(buf)
+
+	return buf
+}
+
+// sample maps uniform 16-bit words to the error distribution by CDF
+// inversion, in constant time (specification, Algorithm 5).
+func (s *Scheme) sample(r []byte, count int) []uint16 {
+	out := make([]uint16, count)
+	for i := range out {
+		v := binary.LittleEndian.Uint16(r[2*i:])
+		t := v >> 1
+		var e uint16
+		for _, c := range s.p.CDF {
+			e += (c - t) >> 15 // e++ when t > c
+		}
+		sign := -(v & 1)
+		out[i] = (e ^ sign) - sign
+	}
+
+	return out
+}
+
+// expandA fills the n-by-n public matrix from seedA.
+func (s *Scheme) expandA(seedA []byte) []uint16 {
+	n := s.p.N
+	a := make([]uint16, n*n)
+	if s.p.AES {
+		blk, err := aes.NewCipher(seedA)
+		if err != nil {
+			panic(err)
+		}
+		var in, out [16]byte
+		for i := 0; i < n; i++ {
+			binary.LittleEndian.PutUint16(in[0:], uint16(i))
+			for j := 0; j < n; j += 8 {
+				binary.LittleEndian.PutUint16(in[2:], uint16(j))
+				blk.Encrypt(out[:], in[:])
+				for k := 0; k < 8; k++ {
+					a[i*n+j+k] = binary.LittleEndian.Uint16(out[2*k:])
+				}
+			}
+		}
+
+		return a
+	}
+	row := make([]byte, 2*n)
+	var pre [2]byte
+	for i := 0; i < n; i++ {
+		binary.LittleEndian.PutUint16(pre[:], uint16(i))
+		h := sha3.NewShake128()
+		_, _ = h.Write(pre[:])
+		_, _ = h.Write(seedA)
+		_, _ = h.Read(row)
+		for j := 0; j < n; j++ {
+			a[i*n+j] = binary.LittleEndian.Uint16(row[2*j:])
+		}
+	}
+
+	return a
+}
+
+// pack serializes values D bits each, most significant bit first.
+func (s *Scheme) pack(out []byte, v []uint16) {
+	d := s.p.D
+	mask := uint32(1)<<d - 1
+	var acc uint32
+	var fill uint
+	k := 0
+	for _, x := range v {
+		acc = acc<<d | uint32(x)&mask
+		fill += d
+		for fill >= 8 {
+			out[k] = byte(acc >> (fill - 8))
+			k++
+			fill -= 8
+		}
+	}
+}
+
+func (s *Scheme) unpack(in []byte, count int) []uint16 {
+	d := s.p.D
+	out := make([]uint16, count)
+	var acc uint32
+	var fill uint
+	k := 0
+	for i := range out {
+		for fill < d {
+			acc = acc<<8 | uint32(in[k])
+			k++
+			fill += 8
+		}
+		out[i] = uint16(acc >> (fill - d) & (1<<d - 1))
+		fill -= d
+	}
+
+	return out
+}
+
+// encode spreads the message bits over the high bits of a matrix, B
+// bits per entry; decode rounds back.
+func (s *Scheme) encode(mu []byte) []uint16 {
+	out := make([]uint16, mBar*nBar)
+	shift := s.p.D - s.p.B
+	bit := 0
+	for i := range out {
+		var v uint16
+		for j := uint(0); j < s.p.B; j++ {
+			v |= uint16(mu[bit/8]>>(bit%8)&1) << j
+			bit++
+		}
+		out[i] = v << shift
+	}
+
+	return out
+}
+
+func (s *Scheme) decode(m []uint16) []byte {
+	shift := s.p.D - s.p.B
+	out := make([]byte, mBar*nBar*int(s.p.B)/8)
+	bit := 0
+	for _, c := range m {
+		v := (c&(1<<s.p.D-1) + 1<<(shift-1)) >> shift
+		for j := uint(0); j < s.p.B; j++ {
+			out[bit/8] |= byte(v>>j&1) << (bit % 8)
+			bit++
+		}
+	}
+
+	return out
+}
+
+// mulAS computes A*S + E with S given transposed (nBar rows of n).
+func mulAS(a, st, e []uint16, n int) []uint16 {
+	out := make([]uint16, n*nBar)
+	for i := 0; i < n; i++ {
+		for j := 0; j < nBar; j++ {
+			acc := e[i*nBar+j]
+			for k := 0; k < n; k++ {
+				acc += a[i*n+k] * st[j*n+k]
+			}
+			out[i*nBar+j] = acc
+		}
+	}
+
+	return out
+}
+
+// mulSA computes S'*A + E' with S' given as mBar rows of n.
+func mulSA(sp, a, e []uint16, n int) []uint16 {
+	out := make([]uint16, mBar*n)
+	copy(out, e)
+	for i := 0; i < mBar; i++ {
+		for k := 0; k < n; k++ {
+			x := sp[i*n+k]
+			if x == 0 {
+				continue
+			}
+			row := a[k*n:]
+			o := out[i*n : (i+1)*n]
+			for j := range o {
+				o[j] += x * row[j]
+			}
+		}
+	}
+
+	return out
+}
+
+// mulBS computes M*S with M mBar-by-n and S given transposed.
+func mulBS(m, st []uint16, n int) []uint16 {
+	out := make([]uint16, mBar*nBar)
+	for i := 0; i < mBar; i++ {
+		for j := 0; j < nBar; j++ {
+			var acc uint16
+			for k := 0; k < n; k++ {
+				acc += m[i*n+k] * st[j*n+k]
+			}
+			out[i*nBar+j] = acc
+		}
+	}
+
+	return out
+}
+
+// mulSB computes S'*B with B n-by-nBar.
+func mulSB(sp, b []uint16, n int) []uint16 {
+	out := make([]uint16, mBar*nBar)
+	for i := 0; i < mBar; i++ {
+		for j := 0; j < nBar; j++ {
+			var acc uint16
+			for k := 0; k < n; k++ {
+				acc += sp[i*n+k] * b[k*nBar+j]
+			}
+			out[i*nBar+j] = acc
+		}
+	}
+
+	return out
+}
+
+// PublicKey is a FrodoKEM encapsulation key.
+type PublicKey struct {
+	sch *Scheme
+	pk  []byte // seedA || packed B
+}
+
+// PrivateKey is a FrodoKEM decapsulation key.
+type PrivateKey struct {
+	sch *Scheme
+	s   []byte // implicit-rejection secret
+	pub *PublicKey
+	st  []uint16 // S transposed
+	pkh []byte
+}
+
+func (pk *PublicKey) Scheme() kem.Scheme { return pk.sch }
+
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, pk.pk...), nil
+}
+
+func (pk *PublicKey) Equal(other kem.PublicKey) bool {
+	o, ok := other.(*PublicKey)
+
+	return ok && pk.sch == o.sch && subtle.ConstantTimeCompare(pk.pk, o.pk) == 1
+}
+
+func (sk *PrivateKey) Scheme() kem.Scheme { return sk.sch }
+
+func (sk *PrivateKey) Public() kem.PublicKey { return sk.pub }
+
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, sk.sch.PrivateKeySize())
+	out = append(out, sk.s...)
+	out = append(out, sk.pub.pk...)
+	for _, v := range sk.st {
+		var b [2]byte
+		binary.LittleEndian.PutUint16(b[:], v)
+		out = append(out, b[:]...)
+	}
+
+	return append(out, sk.pkh...), nil
+}
+
+func (sk *PrivateKey) Equal(other kem.PrivateKey) bool {
+	o, ok := other.(*PrivateKey)
+	if !ok || sk.sch != o.sch {
+		return false
+	}
+	a, _ := sk.MarshalBinary()
+	b, _ := o.MarshalBinary()
+
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Wipe zeroes the secret matrix and the rejection secret.
+func (sk *PrivateKey) Wipe() {
+	wipe.Bytes(sk.s)
+	for i := range sk.st {
+		sk.st[i] = 0
+	}
+}
+
+// deriveKeyPair implements key generation from the seed s||seedSE||z.
+func (s *Scheme) deriveKeyPair(seed []byte) (*PublicKey, *PrivateKey) {
+	sec := s.p.Sec
+	n := s.p.N
+	secS, seedSE, z := seed[:sec], seed[sec:2*sec], seed[2*sec:]
+	seedA := s.shakeSum(seedASize, z)
+
+	r := s.shakeSum(4*n*nBar, []byte{0x5f}, seedSE)
+	st := s.sample(r, n*nBar)
+	e := s.sample(r[2*n*nBar:], n*nBar)
+	a := s.expandA(seedA)
+	b := mulAS(a, st, e, n)
+
+	pk := &PublicKey{sch: s}
+	pk.pk = make([]byte, s.PublicKeySize())
+	copy(pk.pk, seedA)
+	s.pack(pk.pk[seedASize:], b)
+
+	sk := &PrivateKey{
+		sch: s,
+		s:   append([]byte{}, secS...),
+		pub: pk,
+		st:  st,
+		pkh: s.shakeSum(sec, pk.pk),
+	}
+
+	return pk, sk
+}
+
+func (s *Scheme) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
+	if len(seed) != s.SeedSize() {
+		panic(kem.ErrSeedSize)
+	}
+	pk, sk := s.deriveKeyPair(seed)
+
+	return pk, sk
+}
+
+func (s *Scheme) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
+	seed := make([]byte, s.SeedSize())
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	pk, sk := s.deriveKeyPair(seed)
+
+	return pk, sk, nil
+}
+
+// encrypt computes the ciphertext for message mu under pk with
+// randomness expanded from seedSE.
+func (s *Scheme) encrypt(ct []byte, pk *PublicKey, mu, seedSE []byte) {
+	n := s.p.N
+	r := s.shakeSum(4*mBar*n+2*mBar*nBar, []byte{0x96}, seedSE)
+	sp := s.sample(r, mBar*n)
+	ep := s.sample(r[2*mBar*n:], mBar*n)
+	epp := s.sample(r[4*mBar*n:], mBar*nBar)
+
+	a := s.expandA(pk.pk[:seedASize])
+	bp := mulSA(sp, a, ep, n)
+	b := s.unpack(pk.pk[seedASize:], n*nBar)
+	v := mulSB(sp, b, n)
+	c := s.encode(mu)
+	for i := range c {
+		c[i] += v[i] + epp[i]
+	}
+
+	bpLen := mBar * n * int(s.p.D) / 8
+	s.pack(ct[:bpLen], bp)
+	s.pack(ct[bpLen:], c)
+}
+
+func (s *Scheme) encapsulate(ct, ss []byte, pub kem.PublicKey, mu []byte) error {
+	pk, ok := pub.(*PublicKey)
+	if !ok || pk.sch != s {
+		return kem.ErrTypeMismatch
+	}
+	if len(ct) != s.CiphertextSize() || len(ss) != s.p.Sec {
+		return kem.ErrCiphertextSize
+	}
+	pkh := s.shakeSum(s.p.Sec, pk.pk)
+	sk := s.shakeSum(2*s.p.Sec, pkh, mu)
+	seedSE, k := sk[:s.p.Sec], sk[s.p.Sec:]
+	s.encrypt(ct, pk, mu, seedSE)
+	copy(ss, s.shakeSum(s.p.Sec, ct, k))
+
+	return nil
+}
+
+func (s *Scheme) EncapsulateTo(ct, ss []byte, pk kem.PublicKey) error {
+	mu := make([]byte, s.p.Sec)
+	if _, err := io.ReadFull(rand.Reader, mu); err != nil {
+		return err
+	}
+
+	return s.encapsulate(ct, ss, pk, mu)
+}
+
+func (s *Scheme) Encapsulate(pk kem.PublicKey) (ct, ss []byte, err error) {
+	ct = make([]byte, s.CiphertextSize())
+	ss = make([]byte, s.p.Sec)
+	if err = s.EncapsulateTo(ct, ss, pk); err != nil {
+		return nil, nil, err
+	}
+
+	return ct, ss, nil
+}
+
+func (s *Scheme) EncapsulateDeterministically(pk kem.PublicKey, seed []byte) (ct, ss []byte, err error) {
+	if len(seed) != s.EncapsulationSeedSize() {
+		return nil, nil, kem.ErrSeedSize
+	}
+	ct = make([]byte, s.CiphertextSize())
+	ss = make([]byte, s.p.Sec)
+	if err = s.encapsulate(ct, ss, pk, seed); err != nil {
+		return nil, nil, err
+	}
+
+	return ct, ss, nil
+}
+
+func (s *Scheme) DecapsulateTo(ss []byte, priv kem.PrivateKey, ct []byte) error {
+	sk, ok := priv.(*PrivateKey)
+	if !ok || sk.sch != s {
+		return kem.ErrTypeMismatch
+	}
+	if len(ct) != s.CiphertextSize() || len(ss) != s.p.Sec {
+		return kem.ErrCiphertextSize
+	}
+	n := s.p.N
+	bpLen := mBar * n * int(s.p.D) / 8
+	bp := s.unpack(ct[:bpLen], mBar*n)
+	c := s.unpack(ct[bpLen:], mBar*nBar)
+
+	w := mulBS(bp, sk.st, n)
+	m := make([]uint16, mBar*nBar)
+	for i := range m {
+		m[i] = c[i] - w[i]
+	}
+	mu := s.decode(m)
+
+	kk := s.shakeSum(2*s.p.Sec, sk.pkh, mu)
+	seedSE, k := kk[:s.p.Sec], kk[s.p.Sec:]
+	ct2 := make([]byte, s.CiphertextSize())
+	s.encrypt(ct2, sk.pub, mu, seedSE)
+
+	// Implicit rejection: fall back to the secret s on mismatch.
+	same := subtle.ConstantTimeCompare(ct, ct2)
+	kbar := make([]byte, s.p.Sec)
+	for i := range kbar {
+		kbar[i] = byte(subtle.ConstantTimeSelect(same, int(k[i]), int(sk.s[i])))
+	}
+	copy(ss, s.shakeSum(s.p.Sec, ct, kbar))
+
+	return nil
+}
+
+func (s *Scheme) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
+	ss := make([]byte, s.p.Sec)
+	if err := s.DecapsulateTo(ss, sk, ct); err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+func (s *Scheme) UnmarshalBinaryPublicKey(data []byte) (kem.PublicKey, error) {
+	if len(data) != s.PublicKeySize() {
+		return nil, kem.ErrPubKeySize
+	}
+
+	return &PublicKey{sch: s, pk: append([]byte{}, data...)}, nil
+}
+
+func (s *Scheme) UnmarshalBinaryPrivateKey(data []byte) (kem.PrivateKey, error) {
+	if len(data) != s.PrivateKeySize() {
+		return nil, kem.ErrPrivKeySize
+	}
+	sec := s.p.Sec
+	pkLen := s.PublicKeySize()
+	pub, err := s.UnmarshalBinaryPublicKey(data[sec : sec+pkLen])
+	if err != nil {
+		return nil, err
+	}
+	st := make([]uint16, s.p.N*nBar)
+	for i := range st {
+		st[i] = binary.LittleEndian.Uint16(data[sec+pkLen+2*i:])
+	}
+
+	return &PrivateKey{
+		sch: s,
+		s:   append([]byte{}, data[:sec]...),
+		pub: pub.(*PublicKey),
+		st:  st,
+		pkh: append([]byte{}, data[len(data)-sec:]...),
+	}, nil
+}
@@ -0,0 +1,615 @@
+// Package mlkem implements ML-KEM, the module-lattice key-encapsulation
+// mechanism standardized in FIPS 203, superseding the round-3 Kyber
+// parameterization.
+//
+// The three parameter sets are exposed as kem.Scheme instances:
+// MLKEM512, MLKEM768, and MLKEM1024. Key generation from a seed and
+// derandomized encapsulation, used by known-answer tests, are available
+// through the DeriveKeyPair and EncapsulateDeterministically methods of
+// the scheme. Decapsulation is implicitly rejecting: an invalid
+// ciphertext yields an unrelated shared key instead of an error. The
+// input-check steps of FIPS 203 — the modulus check on encapsulation
+// keys and the hash check on decapsulation keys — run when keys are
+// unmarshaled.
+package mlkem
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/asn1"
+	"io"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/wipe"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/rand"
+	"github.com/cloudflare/circl/schemes"
+)
+
+// Parameter sets of FIPS 203.
+var (
+	// MLKEM512 is ML-KEM-512 (security category 1).
+	MLKEM512 kem.Scheme = &scheme{name: "ML-KEM-512", k: 2, eta1: 3, du: 10, dv: 4}
+	// MLKEM768 is ML-KEM-768 (security category 3).
+	MLKEM768 kem.Scheme = &scheme{name: "ML-KEM-768", k: 3, eta1: 2, du: 10, dv: 4}
+	// MLKEM1024 is ML-KEM-1024 (security category 5).
+	MLKEM1024 kem.Scheme = &scheme{name: "ML-KEM-1024", k: 4, eta1: 2, du: 11, dv: 5}
+)
+
+func init() {
+	oidBase := asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4}
+	for i, s := range []kem.Scheme{MLKEM512, MLKEM768, MLKEM1024} {
+		oid := append(asn1.ObjectIdentifier{}, oidBase...)
+		err := schemes.Register(schemes.Entry{
+			Kind: schemes.KindKEM,
+			Name: s.Name(),
+			OID:  append(oid, i+1),
+			KEM:  s,
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+const (
+	n = 256
+	q = 3329
+	// Byte lengths shared by all parameter sets.
+	sharedKeySize = 32
+	seedSize      = 64
+	encapSeedSize = 32
+	polySize      = 384 // 256 coefficients of 12 bits
+)
+
+// zetas[i] = 17^BitRev7(i) and gammas[i] = 17^(2*BitRev7(i)+1), the
+// twiddle factors of the incomplete NTT, derived in init.
+var (
+	zetas  [128]int16
+	gammas [128]int16
+)
+
+func init() {
+	pow := func(e uint) int16 {
+		v := uint32(1)
+		for i := uint(0); i < e; i++ {
+			v = v * 17 % q
+		}
+		return int16(v)
+	}
+	for i := uint(0); i < 128; i++ {
+		br := uint(0)
+		for j := uint(0); j < 7; j++ {
+			br |= (i >> j & 1) << (6 - j)
+		}
+		zetas[i] = pow(br)
+		gammas[i] = pow(2*br + 1)
+	}
+}
+
+type poly [n]int16
+
+// fqMul returns a*b mod q, with operands in [0,q).
+func fqMul(a, b int16) int16 {
+	return int16(uint32(a) * uint32(b) % q)
+}
+
+// fqAdd and fqSub keep results in [0,q).
+func fqAdd(a, b int16) int16 {
+	c := a + b - q
+	c += (c >> 15) & q
+
+	return c
+}
+
+func fqSub(a, b int16) int16 {
+	c := a - b
+	c += (c >> 15) & q
+
+	return c
+}
+
+// ntt transforms p in place (FIPS 203, Algorithm 9).
+func (p *poly) ntt() {
+	i := 1
+	for length := 128; length >= 2; length >>= 1 {
+		for start := 0; start < n; start += 2 * length {
+			zeta := zetas[i]
+			i++
+			for j := start; j < start+length; j++ {
+				t := fqMul(zeta, p[j+length])
+				p[j+length] = fqSub(p[j], t)
+				p[j] = fqAdd(p[j], t)
+			}
+		}
+	}
+}
+
+// invNTT is the inverse transform (FIPS 203, Algorithm 10).
+func (p *poly) invNTT() {
+	i := 127
+	for length := 2; length <= 128; length <<= 1 {
+		for start := 0; start < n; start += 2 * length {
+			zeta := zetas[i]
+			i--
+			for j := start; j < start+length; j++ {
+				t := p[j]
+				p[j] = fqAdd(t, p[j+length])
+				p[j+length] = fqMul(zeta, fqSub(p[j+length], t))
+			}
+		}
+	}
+	const nInv = 3303 // 128^-1 mod q
+	for j := range p {
+		p[j] = fqMul(p[j], nInv)
+	}
+}
+
+// mulNTT sets p to the product a*b in the NTT domain (Algorithms 11-12).
+func (p *poly) mulNTT(a, b *poly) {
+	for i := 0; i < 128; i++ {
+		a0, a1 := a[2*i], a[2*i+1]
+		b0, b1 := b[2*i], b[2*i+1]
+		p[2*i] = fqAdd(fqMul(a0, b0), fqMul(fqMul(a1, b1), gammas[i]))
+		p[2*i+1] = fqAdd(fqMul(a0, b1), fqMul(a1, b0))
+	}
+}
+
+func (p *poly) add(a, b *poly) {
+	for i := range p {
+		p[i] = fqAdd(a[i], b[i])
+	}
+}
+
+func (p *poly) sub(a, b *poly) {
+	for i := range p {
+		p[i] = fqSub(a[i], b[i])
+	}
+}
+
+// compress maps each coefficient to d bits (round(2^d/q * x) mod 2^d).
+func (p *poly) compress(d uint) {
+	for i := range p {
+		p[i] = int16((uint32(p[i])<<d + q/2) / q & (1<<d - 1))
+	}
+}
+
+// decompress is the right inverse of compress.
+func (p *poly) decompress(d uint) {
+	for i := range p {
+		p[i] = int16((uint32(p[i])*q + 1<<(d-1)) >> d)
+	}
+}
+
+// pack serializes the low d bits of every coefficient (ByteEncode_d).
+func (p *poly) pack(out []byte, d uint) {
+	var acc, bits uint
+	k := 0
+	for i := range p {
+		acc |= uint(p[i]) << bits
+		bits += d
+		for bits >= 8 {
+			out[k] = byte(acc)
+			k++
+			acc >>= 8
+			bits -= 8
+		}
+	}
+}
+
+// unpack deserializes d-bit coefficients (ByteDecode_d).
+func (p *poly) unpack(in []byte, d uint) {
+	var acc, bits uint
+	k := 0
+	for i := range p {
+		for bits < d {
+			acc |= uint(in[k]) << bits
+			k++
+			bits += 8
+		}
+		p[i] = int16(acc & (1<<d - 1))
+		acc >>= d
+		bits -= d
+	}
+}
+
+// sampleNTT fills p with the uniform NTT-domain polynomial determined
+// by seed||j||i, by rejection from SHAKE128 (Algorithm 7).
+func (p *poly) sampleNTT(seed []byte, j, i byte) {
+	h := sha3.NewShake128()
+	_, _ = h.Write(seed)
+	_, _ = h.Write([]byte{j, i})
+	var buf [3]byte
+	k := 0
+	for k < n {
+		_, _ = h.Read(buf[:])
+		d1 := int16(buf[0]) | int16(buf[1]&0x0f)<<8
+		d2 := int16(buf[1]>>4) | int16(buf[2])<<4
+		if d1 < q {
+			p[k] = d1
+			k++
+		}
+		if d2 < q && k < n {
+			p[k] = d2
+			k++
+		}
+	}
+}
+
+// sampleCBD fills p from the centered binomial distribution with
+// parameter eta, keyed by PRF(s, b) (Algorithms 8 and 6).
+func (p *poly) sampleCBD(s []byte, b byte, eta int) {
+	h := sha3.NewShake256()
+	_, _ = h.Write(s)
+	_, _ = h.Write([]byte{b})
+	buf := make([]byte, 64*eta)
+	_, _ = h.Read(buf)
+	for i := 0; i < n; i++ {
+		var x, y int16
+		for j := 0; j < eta; j++ {
+			bit := 2*i*eta + j
+			x += int16(buf[bit/8] >> (bit % 8) & 1)
+			bit += eta
+			y += int16(buf[bit/8] >> (bit % 8) & 1)
+		}
+		p[i] = fqSub(x, y)
+	}
+}
+
+// scheme is one ML-KEM parameter set.
+type scheme struct {
+	name   string
+	k      int
+	eta1   int
+	du, dv uint
+}
+
+func (s *scheme) Name() string               { return s.name }
+func (s *scheme) SharedKeySize() int         { return sharedKeySize }
+func (s *scheme) SeedSize() int              { return seedSize }
+func (s *scheme) EncapsulationSeedSize() int { return encapSeedSize }
+func (s *scheme) PublicKeySize() int         { return s.k*polySize + 32 }
+func (s *scheme) PrivateKeySize() int        { return 2*s.k*polySize + 96 }
+func (s *scheme) CiphertextSize() int        { return 32 * (int(s.du)*s.k + int(s.dv)) }
+
+// PublicKey is an ML-KEM encapsulation key.
+type PublicKey struct {
+	sch *scheme
+	t   []poly // NTT domain
+	rho [32]byte
+	ek  []byte // cached encoding
+	h   [32]byte
+}
+
+// PrivateKey is an ML-KEM decapsulation key.
+type PrivateKey struct {
+	sch *scheme
+	s   []poly // NTT domain
+	pk  *PublicKey
+	z   [32]byte
+}
+
+func (pk *PublicKey) Scheme() kem.Scheme { return pk.sch }
+
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, pk.ek...), nil
+}
+
+func (pk *PublicKey) Equal(other kem.PublicKey) bool {
+	o, ok := other.(*PublicKey)
+
+	return ok && pk.sch == o.sch && bytes.Equal(pk.ek, o.ek)
+}
+
+func (sk *PrivateKey) Scheme() kem.Scheme { return sk.sch }
+
+func (sk *PrivateKey) Public() kem.PublicKey { return sk.pk }
+
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, sk.sch.PrivateKeySize())
+	buf := make([]byte, polySize)
+	for i := range sk.s {
+		sk.s[i].pack(buf, 12)
+		out = append(out, buf...)
+	}
+	out = append(out, sk.pk.ek...)
+	out = append(out, sk.pk.h[:]...)
+	out = append(out, sk.z[:]...)
+
+	return out, nil
+}
+
+func (sk *PrivateKey) Equal(other kem.PrivateKey) bool {
+	o, ok := other.(*PrivateKey)
+	if !ok || sk.sch != o.sch {
+		return false
+	}
+	a, _ := sk.MarshalBinary()
+	b, _ := o.MarshalBinary()
+
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Wipe zeroes the secret polynomial vector and the rejection secret.
+func (sk *PrivateKey) Wipe() {
+	for i := range sk.s {
+		sk.s[i] = poly{}
+	}
+	wipe.Bytes(sk.z[:])
+}
+
+// deriveKeyPair is ML-KEM.KeyGen_internal (Algorithm 16) on the seed
+// d||z.
+func (s *scheme) deriveKeyPair(seed []byte) (*PublicKey, *PrivateKey) {
+	g := sha3.New512()
+	_, _ = g.Write(seed[:32])
+	_, _ = g.Write([]byte{byte(s.k)}) // domain separation of FIPS 203
+	gOut := g.Sum(nil)
+	rho, sigma := gOut[:32], gOut[32:]
+
+	pk := &PublicKey{sch: s, t: make([]poly, s.k)}
+	sk := &PrivateKey{sch: s, s: make([]poly, s.k), pk: pk}
+	copy(pk.rho[:], rho)
+	copy(sk.z[:], seed[32:])
+
+	var e, t poly
+	nonce := byte(0)
+	for i := range sk.s {
+		sk.s[i].sampleCBD(sigma, nonce, s.eta1)
+		sk.s[i].ntt()
+		nonce++
+	}
+	var a poly
+	for i := 0; i < s.k; i++ {
+		e.sampleCBD(sigma, nonce, s.eta1)
+		e.ntt()
+		nonce++
+		pk.t[i] = e
+		for j := 0; j < s.k; j++ {
+			a.sampleNTT(rho, byte(j), byte(i))
+			t.mulNTT(&a, &sk.s[j])
+			pk.t[i].add(&pk.t[i], &t)
+		}
+	}
+
+	pk.ek = make([]byte, 0, s.PublicKeySize())
+	buf := make([]byte, polySize)
+	for i := range pk.t {
+		pk.t[i].pack(buf, 12)
+		pk.ek = append(pk.ek, buf...)
+	}
+	pk.ek = append(pk.ek, rho...)
+	h := sha3.New256()
+	_, _ = h.Write(pk.ek)
+	copy(pk.h[:], h.Sum(nil))
+
+	return pk, sk
+}
+
+func (s *scheme) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
+	if len(seed) != seedSize {
+		panic(kem.ErrSeedSize)
+	}
+	pk, sk := s.deriveKeyPair(seed)
+
+	return pk, sk
+}
+
+func (s *scheme) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
+	var seed [seedSize]byte
+	if _, err := io.ReadFull(rand.Reader, seed[:]); err != nil {
+		return nil, nil, err
+	}
+	pk, sk := s.deriveKeyPair(seed[:])
+
+	return pk, sk, nil
+}
+
+// encrypt is K-PKE.Encrypt (Algorithm 14), writing the ciphertext to ct.
+func (s *scheme) encrypt(ct []byte, pk *PublicKey, m, r []byte) {
+	y := make([]poly, s.k)
+	nonce := byte(0)
+	for i := range y {
+		y[i].sampleCBD(r, nonce, s.eta1)
+		y[i].ntt()
+		nonce++
+	}
+	var a, t, e poly
+	u := make([]poly, s.k)
+	for i := range u {
+		for j := 0; j < s.k; j++ {
+			a.sampleNTT(pk.rho[:], byte(i), byte(j)) // A^T: row i is column i
+			t.mulNTT(&a, &y[j])
+			u[i].add(&u[i], &t)
+		}
+		u[i].invNTT()
+		e.sampleCBD(r, nonce, 2)
+		nonce++
+		u[i].add(&u[i], &e)
+	}
+	var v poly
+	for j := 0; j < s.k; j++ {
+		t.mulNTT(&pk.t[j], &y[j])
+		v.add(&v, &t)
+	}
+	v.invNTT()
+	e.sampleCBD(r, nonce, 2)
+	v.add(&v, &e)
+	var mu poly
+	mu.unpack(m, 1)
+	mu.decompress(1)
+	v.add(&v, &mu)
+
+	for i := range u {
+		u[i].compress(s.du)
+		u[i].pack(ct[32*int(s.du)*i:], s.du)
+	}
+	v.compress(s.dv)
+	v.pack(ct[32*int(s.du)*s.k:], s.dv)
+}
+
+// decrypt is K-PKE.Decrypt (Algorithm 15).
+func (s *scheme) decrypt(m []byte, sk *PrivateKey, ct []byte) {
+	u := make([]poly, s.k)
+	for i := range u {
+		u[i].unpack(ct[32*int(s.du)*i:], s.du)
+		u[i].decompress(s.du)
+		u[i].ntt()
+	}
+	var v, t, w poly
+	v.unpack(ct[32*int(s.du)*s.k:], s.dv)
+	v.decompress(s.dv)
+	for j := 0; j < s.k; j++ {
+		t.mulNTT(&sk.s[j], &u[j])
+		w.add(&w, &t)
+	}
+	w.invNTT()
+	v.sub(&v, &w)
+	v.compress(1)
+	v.pack(m, 1)
+}
+
+func (s *scheme) EncapsulateTo(ct, ss []byte, pk kem.PublicKey) error {
+	var seed [encapSeedSize]byte
+	if _, err := io.ReadFull(rand.Reader, seed[:]); err != nil {
+		return err
+	}
+
+	return s.encapsulate(ct, ss, pk, seed[:])
+}
+
+func (s *scheme) Encapsulate(pk kem.PublicKey) (ct, ss []byte, err error) {
+	ct = make([]byte, s.CiphertextSize())
+	ss = make([]byte, sharedKeySize)
+	if err = s.EncapsulateTo(ct, ss, pk); err != nil {
+		return nil, nil, err
+	}
+
+	return ct, ss, nil
+}
+
+func (s *scheme) EncapsulateDeterministically(pk kem.PublicKey, seed []byte) (ct, ss []byte, err error) {
+	if len(seed) != encapSeedSize {
+		return nil, nil, kem.ErrSeedSize
+	}
+	ct = make([]byte, s.CiphertextSize())
+	ss = make([]byte, sharedKeySize)
+	if err = s.encapsulate(ct, ss, pk, seed); err != nil {
+		return nil, nil, err
+	}
+
+	return ct, ss, nil
+}
+
+// encapsulate is ML-KEM.Encaps_internal (Algorithm 17) with message m.
+func (s *scheme) encapsulate(ct, ss []byte, pub kem.PublicKey, m []byte) error {
+	pk, ok := pub.(*PublicKey)
+	if !ok || pk.sch != s {
+		return kem.ErrTypeMismatch
+	}
+	if len(ct) != s.CiphertextSize() || len(ss) != sharedKeySize {
+		return kem.ErrCiphertextSize
+	}
+	g := sha3.New512()
+	_, _ = g.Write(m)
+	_, _ = g.Write(pk.h[:])
+	kr := g.Sum(nil)
+	copy(ss, kr[:32])
+	s.encrypt(ct, pk, m, kr[32:])
+
+	return nil
+}
+
+func (s *scheme) DecapsulateTo(ss []byte, priv kem.PrivateKey, ct []byte) error {
+	sk, ok := priv.(*PrivateKey)
+	if !ok || sk.sch != s {
+		return kem.ErrTypeMismatch
+	}
+	if len(ct) != s.CiphertextSize() {
+		return kem.ErrCiphertextSize
+	}
+	if len(ss) != sharedKeySize {
+		return kem.ErrCiphertextSize
+	}
+
+	var m [32]byte
+	s.decrypt(m[:], sk, ct)
+	g := sha3.New512()
+	_, _ = g.Write(m[:])
+	_, _ = g.Write(sk.pk.h[:])
+	kr := g.Sum(nil)
+
+	// Implicit rejection: K-bar = J(z || ct).
+	j := sha3.NewShake256()
+	_, _ = j.Write(sk.z[:])
+	_, _ = j.Write(ct)
+	var kBar [sharedKeySize]byte
+	_, _ = j.Read(kBar[:])
+
+	ct2 := make([]byte, s.CiphertextSize())
+	s.encrypt(ct2, sk.pk, m[:], kr[32:])
+	same := subtle.ConstantTimeCompare(ct, ct2)
+	for i := range ss {
+		ss[i] = byte(subtle.ConstantTimeSelect(same, int(kr[i]), int(kBar[i])))
+	}
+
+	return nil
+}
+
+func (s *scheme) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
+	ss := make([]byte, sharedKeySize)
+	if err := s.DecapsulateTo(ss, sk, ct); err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+// UnmarshalBinaryPublicKey performs the modulus check of FIPS 203: every
+// encoded coefficient must already be reduced.
+func (s *scheme) UnmarshalBinaryPublicKey(data []byte) (kem.PublicKey, error) {
+	if len(data) != s.PublicKeySize() {
+		return nil, kem.ErrPubKeySize
+	}
+	pk := &PublicKey{sch: s, t: make([]poly, s.k)}
+	for i := range pk.t {
+		pk.t[i].unpack(data[polySize*i:], 12)
+		for _, c := range pk.t[i] {
+			if c >= q {
+				return nil, kem.ErrPubKey
+			}
+		}
+	}
+	copy(pk.rho[:], data[polySize*s.k:])
+	pk.ek = append([]byte{}, data...)
+	h := sha3.New256()
+	_, _ = h.Write(pk.ek)
+	copy(pk.h[:], h.Sum(nil))
+
+	return pk, nil
+}
+
+// UnmarshalBinaryPrivateKey performs the hash check of FIPS 203: the
+// embedded encapsulation key must match its stored hash.
+func (s *scheme) UnmarshalBinaryPrivateKey(data []byte) (kem.PrivateKey, error) {
+	if len(data) != s.PrivateKeySize() {
+		return nil, kem.ErrPrivKeySize
+	}
+	ekOff := polySize * s.k
+	hOff := ekOff + s.PublicKeySize()
+	pub, err := s.UnmarshalBinaryPublicKey(data[ekOff:hOff])
+	if err != nil {
+		return nil, kem.ErrPrivKey
+	}
+	pk := pub.(*PublicKey)
+	if subtle.ConstantTimeCompare(pk.h[:], data[hOff:hOff+32]) != 1 {
+		return nil, kem.ErrPrivKey
+	}
+	sk := &PrivateKey{sch: s, s: make([]poly, s.k), pk: pk}
+	for i := range sk.s {
+		sk.s[i].unpack(data[polySize*i:], 12)
+	}
+	copy(sk.z[:], data[hOff+32:])
+
+	return sk, nil
+}
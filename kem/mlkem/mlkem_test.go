@@ -0,0 +1,283 @@
+package mlkem_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/mlkem"
+)
+
+func scheme(t *testing.T, name string) kem.Scheme {
+	t.Helper()
+	switch name {
+	case "ML-KEM-512":
+		return mlkem.MLKEM512
+	case "ML-KEM-768":
+		return mlkem.MLKEM768
+	case "ML-KEM-1024":
+		return mlkem.MLKEM1024
+	}
+	t.Fatalf("unknown scheme %s", name)
+	return nil
+}
+
+func shake128(s string, l int) []byte {
+	h := sha3.NewShake128()
+	_, _ = h.Write([]byte(s))
+	out := make([]byte, l)
+	_, _ = h.Read(out)
+	return out
+}
+
+func digest(b []byte) string {
+	h := sha3.New256()
+	_, _ = h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Known-answer vectors cross-checked against an independent implementation
+// of FIPS 203. The keygen seed d||z and the encapsulation seed m for test t
+// are SHAKE128("seed-<name>-<t>") and SHAKE128("msg-<name>-<t>"); ek, dk,
+// and ct are recorded as SHA3-256 digests, the shared keys in full. ssBad is
+// the implicitly rejected shared key for the ciphertext with its first byte
+// flipped.
+var katVectors = []struct {
+	name                  string
+	t                     int
+	ek, dk, ct, ss, ssBad string
+}{
+	{
+		"ML-KEM-512", 0,
+		"13d69f831a1cd5919f203b27759bc0a3d66ec841afb157e5924d3f6044ef51b3",
+		"2e8dd74fd129c225bfd4d196d42d454f6f60c576ad9e2406b4e91468ce9bd0cb",
+		"7a5db0cffed896b53a50cacdfad756768d10fec61059db4ffdf3107f67829866",
+		"4848275bf9f05705435205e5f79f981cfd6f7efd773817f738016d90e4ad0442",
+		"fc4f51654efd1bb3f4f0bab4ff8b4bb22ea6926c57cc250653063202af3d08be",
+	},
+	{
+		"ML-KEM-512", 1,
+		"8caec17cc2a5666972f51f622148e43fdd4cc2e6a9420d2c7a01c541e70ff872",
+		"1aaa6bd25282fc66eb10a947b9ee571f284daf6fbdbb53fb8949c7a5af3cfac6",
+		"c07677488377b31e6ad488188eff0a33510f983e61850bd3d11fb7a0f9c963d7",
+		"3ca59ffcb25a55d748509437f85d24ce033fe737ce6ce3b983785ca9eb72a065",
+		"89eb17f1ee2ded328528a6af64d19b3ba938c8fd025782f3d3368eda1c66776c",
+	},
+	{
+		"ML-KEM-512", 2,
+		"9b7ceaae7595fabd930d9a7e14cc91b1172c67ffef43605a7469c827b61689e4",
+		"9b6d4b5a8c974b3033872aef98b66b863849d5a8976abda772b11aa71ecdd934",
+		"c20397a1c131e3b21d7b3280e5076fefd170b854a1c568717f444a0856f42dd4",
+		"aa3478b5a675b5ae9be6b8d4cc3b74ecd0f6cf2d4329547a29b7182c903d5a0b",
+		"0d397f539c42f303a12e71b4c1e5062711dc9d09c070d4cc3a40a5ddc0fda00d",
+	},
+	{
+		"ML-KEM-768", 0,
+		"818165f6d1bf5fff82b45513327874b5d327437779062cc70fe0c548b6f91a97",
+		"8967d0c7fa664db29d97ae2d6c91cdc09582c6625776336ce50e8d03e4a73bf1",
+		"9206aa5a4022782ccd87458b9a4f54bd8ae24d10e558afa3f0af44e572f89da5",
+		"94223d7dac35473f30b27ecdba5742bb98aafe98e06a354c21d990f3fd97f903",
+		"bf4d98800469876a9c9562dac1818e82942cf1be520803f8e70cd8f173d725d0",
+	},
+	{
+		"ML-KEM-768", 1,
+		"9af29df24acc0f74d4a55265181405e720a1a924a9c2d0c8102c8a2ff159c43b",
+		"4257939f77de1e105c54984b831f7d33b1f59b5acb3d5390412767d1cd493951",
+		"6f39298773f5f435ce9882301ddc5675f544d3829b2866cb2783ded525b9bbe0",
+		"42129942bde0c6bbeee51f04066e6133b33b7fdc0f419877e7859d4ce07106cc",
+		"ab3181db597184daf3f8b65095b5bdb0807b87bcdb766983028ac3c322b1b850",
+	},
+	{
+		"ML-KEM-768", 2,
+		"75ffc62f9611b67989248ca109a35fc9d382e766659646d88e810e9ca3be1056",
+		"70ba15848c235d552c640196016c023556220a725ceae7424d4430c83419e91b",
+		"6018c1f4c763112b0686fd4b156ff162b63f3ff8c3bd3950e04e926363e4c026",
+		"ce4857943c1194db58024e58f63f1872767e366d8a2912adfed0707ee9d3802d",
+		"7a0ec8218ec49461dfb38d99916fd5d42c046e1d5f4429d55da977b96487fa58",
+	},
+	{
+		"ML-KEM-1024", 0,
+		"53174c4e219345d6b3b501b8da06ccc8a03eb6f48a5c4a93f4aee2b55c9aa169",
+		"50aaa617c69b27315deaa088dbd9423bf788506833fcbc7360bb52264483ff26",
+		"1f676de7151fe79091abf0dffe8df00efb80297f62963d052d9b01069e01702c",
+		"7cf858572a8141220bf0fef9792871162a2d2780a7d6343eb81972ff49655d58",
+		"df278ffabc830fb3dddc84eb12383ef881f18e9c57575342bdfe19e9117bac0c",
+	},
+	{
+		"ML-KEM-1024", 1,
+		"cfe8a36369a5790db9b7cdbceeecaeb31dbfbf1ca1860e9d84438ea924120b49",
+		"247b250a12ced1f8b17b42e54306b97848cb832371e543977ad026d1c1fc5d12",
+		"70ad21f2b3e0d23bb3eda603d551a4cc33ed262ac8dee8335cf533cf01a2fe1b",
+		"1a02fc0469b96e3939af5f18417ead49c075e27874753a417a16ba67ae7e0870",
+		"ce9decc0c456dccfb210a83ecce3560c09745e70e5e08aac25025614f93826d1",
+	},
+	{
+		"ML-KEM-1024", 2,
+		"3af3a838625f4c940c996a9ec367eaebb9d9bb5acbbb6d045bb60f3a87ce1578",
+		"605c05e286320a81fb69da6c9686d22f3929f1129510f9ad1883d09aadd07361",
+		"b67998d98c299e96cd57ad6684e22573ffeb3a0229dc400a73ad974a3b1c909e",
+		"d1e19a81626bb6bbd2b6b23794fe69ed2132d55403e03cad840d1645db8f71c1",
+		"f0d5b99446260f5b058115e1dd025c3bd5d5181e567f3279961220839423202a",
+	},
+}
+
+func TestKAT(t *testing.T) {
+	for _, v := range katVectors {
+		t.Run(fmt.Sprintf("%s/%d", v.name, v.t), func(t *testing.T) {
+			sch := scheme(t, v.name)
+			seed := shake128(fmt.Sprintf("seed-%s-%d", v.name, v.t), sch.SeedSize())
+			msg := shake128(fmt.Sprintf("msg-%s-%d", v.name, v.t), sch.EncapsulationSeedSize())
+
+			pk, sk := sch.DeriveKeyPair(seed)
+			ek, err := pk.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			dk, err := sk.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if digest(ek) != v.ek {
+				t.Error("encapsulation key mismatch")
+			}
+			if digest(dk) != v.dk {
+				t.Error("decapsulation key mismatch")
+			}
+
+			ct, ss, err := sch.EncapsulateDeterministically(pk, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if digest(ct) != v.ct {
+				t.Error("ciphertext mismatch")
+			}
+			if hex.EncodeToString(ss) != v.ss {
+				t.Error("shared key mismatch")
+			}
+
+			// The shared key is the first half of G(m || H(ek)),
+			// per Algorithm 17.
+			h := sha3.New256()
+			_, _ = h.Write(ek)
+			g := sha3.New512()
+			_, _ = g.Write(msg)
+			_, _ = g.Write(h.Sum(nil))
+			if !bytes.Equal(g.Sum(nil)[:32], ss) {
+				t.Error("shared key does not match G(m || H(ek))")
+			}
+
+			ss2, err := sch.Decapsulate(sk, ct)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(ss, ss2) {
+				t.Error("decapsulation disagrees with encapsulation")
+			}
+
+			// Implicit rejection: the tampered ciphertext must yield
+			// K-bar = J(z || ct'), with z the final 32 bytes of dk.
+			bad := append([]byte{}, ct...)
+			bad[0] ^= 1
+			ssBad, err := sch.Decapsulate(sk, bad)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(ssBad) != v.ssBad {
+				t.Error("implicitly rejected key mismatch")
+			}
+			j := sha3.NewShake256()
+			_, _ = j.Write(dk[len(dk)-32:])
+			_, _ = j.Write(bad)
+			kBar := make([]byte, 32)
+			_, _ = j.Read(kBar)
+			if !bytes.Equal(ssBad, kBar) {
+				t.Error("implicit rejection does not match J(z || ct)")
+			}
+			if bytes.Equal(ssBad, ss) {
+				t.Error("tampered ciphertext yields the honest key")
+			}
+		})
+	}
+}
+
+func TestInputChecks(t *testing.T) {
+	for _, sch := range []kem.Scheme{mlkem.MLKEM512, mlkem.MLKEM768, mlkem.MLKEM1024} {
+		t.Run(sch.Name(), func(t *testing.T) {
+			pk, sk, err := sch.GenerateKeyPair()
+			if err != nil {
+				t.Fatal(err)
+			}
+			ek, err := pk.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			dk, err := sk.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Modulus check: an unreduced coefficient must be rejected.
+			bad := append([]byte{}, ek...)
+			bad[0] = 0xff
+			bad[1] |= 0x0f
+			if _, err = sch.UnmarshalBinaryPublicKey(bad); err == nil {
+				t.Error("unreduced encapsulation key accepted")
+			}
+			if _, err = sch.UnmarshalBinaryPublicKey(ek[:len(ek)-1]); err == nil {
+				t.Error("truncated encapsulation key accepted")
+			}
+
+			// Hash check: a decapsulation key whose embedded hash does
+			// not match its encapsulation key must be rejected.
+			bad = append([]byte{}, dk...)
+			bad[len(bad)-33] ^= 1 // last byte of H(ek)
+			if _, err = sch.UnmarshalBinaryPrivateKey(bad); err == nil {
+				t.Error("decapsulation key with broken hash accepted")
+			}
+			if _, err = sch.UnmarshalBinaryPrivateKey(dk[:len(dk)-1]); err == nil {
+				t.Error("truncated decapsulation key accepted")
+			}
+
+			// Valid keys roundtrip.
+			pub, err := sch.UnmarshalBinaryPublicKey(ek)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pub.Equal(pk) {
+				t.Error("encapsulation key does not roundtrip")
+			}
+			priv, err := sch.UnmarshalBinaryPrivateKey(dk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !priv.Equal(sk) {
+				t.Error("decapsulation key does not roundtrip")
+			}
+		})
+	}
+}
+
+func BenchmarkMLKEM768(b *testing.B) {
+	sch := mlkem.MLKEM768
+	pk, sk, err := sch.GenerateKeyPair()
+	if err != nil {
+		b.Fatal(err)
+	}
+	ct, _, err := sch.Encapsulate(pk)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("Encapsulate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = sch.Encapsulate(pk)
+		}
+	})
+	b.Run("Decapsulate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = sch.Decapsulate(sk, ct)
+		}
+	})
+}
@@ -0,0 +1,992 @@
+// Package sntrup761 implements Streamlined NTRU Prime sntrup761 and the
+// sntrup761x25519-sha512 hybrid used by OpenSSH 9.x.
+//
+// The implementation follows the round-3 NTRU Prime submission and
+// matches the reference code shipped in OpenSSH byte for byte on the
+// wire: the same stream encodings, the same confirmation hash, and the
+// same session-key derivation, so Go SSH stacks can interoperate with
+// OpenSSH's post-quantum key exchange.
+package sntrup761
+
+import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"io"
+
+	"github.com/cloudflare/circl/dh/x25519"
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/wipe"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/rand"
+	"github.com/cloudflare/circl/schemes"
+)
+
+const (
+	p   = 761
+	q   = 4591
+	wt  = 286
+	q12 = (q - 1) / 2
+
+	smallBytes   = (p + 3) / 4 // 191
+	pkBytes      = 1158        // Rq encoding
+	roundedBytes = 1007        // Rounded encoding
+	confirmBytes = 32
+	ctBytes      = roundedBytes + confirmBytes
+	skBytes      = 2*smallBytes + pkBytes + smallBytes + confirmBytes
+	ssBytes      = 32
+	seedBytes    = 64
+)
+
+// Scheme is the Streamlined NTRU Prime sntrup761 KEM.
+var Scheme kem.Scheme = &scheme{}
+
+// X25519 is the sntrup761x25519-sha512 hybrid: public keys and
+// ciphertexts are the sntrup761 part followed by the X25519 part, and
+// the shared key is SHA-512 of both shared secrets, exactly as in
+// OpenSSH's sntrup761x25519-sha512 key exchange.
+var X25519 kem.Scheme = &hybrid{}
+
+func init() {
+	if err := schemes.Register(schemes.Entry{
+		Kind: schemes.KindKEM, Name: Scheme.Name(), KEM: Scheme,
+	}); err != nil {
+		panic(err)
+	}
+	if err := schemes.Register(schemes.Entry{
+		Kind:    schemes.KindKEM,
+		Name:    X25519.Name(),
+		Aliases: []string{"sntrup761x25519-sha512@openssh.com"},
+		KEM:     X25519,
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// Arithmetic in Z/3 and Z/q on centered representatives, following the
+// reference implementation.
+
+func f3Freeze(x int32) int8 {
+	y := x % 3
+	y += 3 & (y >> 31)
+	y -= 3 & ((1 - y) >> 31)
+
+	return int8(y)
+}
+
+func fqFreeze(x int32) int16 {
+	x %= q
+	x += q & (x >> 31)
+	x -= q & ((q12 - x) >> 31)
+
+	return int16(x)
+}
+
+// fqRecip computes 1/a in Z/q by a fixed-length multiplication chain.
+func fqRecip(a int16) int16 {
+	ai := a
+	for i := 1; i < q-2; i++ {
+		ai = fqFreeze(int32(a) * int32(ai))
+	}
+
+	return ai
+}
+
+// int16Nonzero returns -1 if x is nonzero and 0 otherwise.
+func int16Nonzero(x int16) int {
+	u := uint32(uint16(x))
+
+	return -int((u | -u) >> 31)
+}
+
+// int16Negative returns -1 if x is negative and 0 otherwise.
+func int16Negative(x int16) int {
+	return -int(uint16(x) >> 15)
+}
+
+// Polynomial arithmetic in R = Z[x]/(x^p - x - 1).
+
+// rqMultSmall returns h*f in R/q.
+func rqMultSmall(h *[p]int16, f *[p]int8) *[p]int16 {
+	var fg [2*p - 1]int32
+	for i := 0; i < p; i++ {
+		for j := 0; j < p; j++ {
+			fg[i+j] += int32(h[i]) * int32(f[j])
+		}
+	}
+	for i := 2*p - 2; i >= p; i-- {
+		fg[i-p] += fg[i]
+		fg[i-p+1] += fg[i]
+	}
+	var out [p]int16
+	for i := range out {
+		out[i] = fqFreeze(fg[i])
+	}
+
+	return &out
+}
+
+// r3Mult returns f*g in R/3.
+func r3Mult(f, g *[p]int8) *[p]int8 {
+	var fg [2*p - 1]int32
+	for i := 0; i < p; i++ {
+		for j := 0; j < p; j++ {
+			fg[i+j] += int32(f[i]) * int32(g[j])
+		}
+	}
+	for i := 2*p - 2; i >= p; i-- {
+		fg[i-p] += fg[i]
+		fg[i-p+1] += fg[i]
+	}
+	var out [p]int8
+	for i := range out {
+		out[i] = f3Freeze(fg[i])
+	}
+
+	return &out
+}
+
+// round replaces each coefficient with the nearest multiple of 3.
+func round(c *[p]int16) {
+	for i := range c {
+		c[i] -= int16(f3Freeze(int32(c[i])))
+	}
+}
+
+// r3Recip returns 1/in in R/3 and 0, or -1 if in is not invertible,
+// using the constant-time extended-GCD of the reference code.
+func r3Recip(in *[p]int8) (*[p]int8, int) {
+	var f, g, v, r [p + 1]int8
+	r[0] = 1
+	f[0] = 1
+	f[p-1], f[p] = -1, -1
+	for i := 0; i < p; i++ {
+		g[p-1-i] = in[i]
+	}
+	delta := 1
+	for loop := 0; loop < 2*p-1; loop++ {
+		for i := p; i > 0; i-- {
+			v[i] = v[i-1]
+		}
+		v[0] = 0
+		swap := int16Negative(int16(-delta)) & int16Nonzero(int16(g[0]))
+		delta ^= swap & (delta ^ -delta)
+		delta++
+		for i := 0; i < p+1; i++ {
+			t := int8(swap) & (f[i] ^ g[i])
+			f[i] ^= t
+			g[i] ^= t
+			t = int8(swap) & (v[i] ^ r[i])
+			v[i] ^= t
+			r[i] ^= t
+		}
+		f0, g0 := int32(f[0]), int32(g[0])
+		for i := 0; i < p+1; i++ {
+			g[i] = f3Freeze(f0*int32(g[i]) - g0*int32(f[i]))
+		}
+		for i := 0; i < p+1; i++ {
+			r[i] = f3Freeze(f0*int32(r[i]) - g0*int32(v[i]))
+		}
+		for i := 0; i < p; i++ {
+			g[i] = g[i+1]
+		}
+		g[p] = 0
+	}
+	var out [p]int8
+	scale := f[0] // +-1
+	for i := 0; i < p; i++ {
+		out[i] = f3Freeze(int32(scale) * int32(v[p-1-i]))
+	}
+
+	return &out, int16Nonzero(int16(delta))
+}
+
+// rqRecip3 returns 1/(3*in) in R/q and 0, or -1 on failure.
+func rqRecip3(in *[p]int8) (*[p]int16, int) {
+	var f, g, v, r [p + 1]int16
+	r[0] = fqRecip(3)
+	f[0] = 1
+	f[p-1], f[p] = -1, -1
+	for i := 0; i < p; i++ {
+		g[p-1-i] = int16(in[i])
+	}
+	delta := 1
+	for loop := 0; loop < 2*p-1; loop++ {
+		for i := p; i > 0; i-- {
+			v[i] = v[i-1]
+		}
+		v[0] = 0
+		swap := int16Negative(int16(-delta)) & int16Nonzero(g[0])
+		delta ^= swap & (delta ^ -delta)
+		delta++
+		for i := 0; i < p+1; i++ {
+			t := int16(swap) & (f[i] ^ g[i])
+			f[i] ^= t
+			g[i] ^= t
+			t = int16(swap) & (v[i] ^ r[i])
+			v[i] ^= t
+			r[i] ^= t
+		}
+		f0, g0 := int32(f[0]), int32(g[0])
+		for i := 0; i < p+1; i++ {
+			g[i] = fqFreeze(f0*int32(g[i]) - g0*int32(f[i]))
+		}
+		for i := 0; i < p+1; i++ {
+			r[i] = fqFreeze(f0*int32(r[i]) - g0*int32(v[i]))
+		}
+		for i := 0; i < p; i++ {
+			g[i] = g[i+1]
+		}
+		g[p] = 0
+	}
+	var out [p]int16
+	scale := fqRecip(f[0])
+	for i := 0; i < p; i++ {
+		out[i] = fqFreeze(int32(scale) * int32(v[p-1-i]))
+	}
+
+	return &out, int16Nonzero(int16(delta))
+}
+
+// minmax is the branch-free compare-and-swap of the reference sort.
+func minmax(x, y *uint32) {
+	xi, yi := *x, *y
+	xy := xi ^ yi
+	c := yi - xi
+	c ^= xy & (c ^ yi ^ 0x80000000)
+	c >>= 31
+	c = -c
+	c &= xy
+	*x = xi ^ c
+	*y = yi ^ c
+}
+
+// sortUint32 is the constant-time Batcher sort from the reference
+// implementation.
+func sortUint32(x []uint32) {
+	n := len(x)
+	if n < 2 {
+		return
+	}
+	top := 1
+	for top < n-top {
+		top += top
+	}
+	for pp := top; pp > 0; pp >>= 1 {
+		for i := 0; i < n-pp; i++ {
+			if i&pp == 0 {
+				minmax(&x[i], &x[i+pp])
+			}
+		}
+		for qq := top; qq > pp; qq >>= 1 {
+			for i := 0; i < n-qq; i++ {
+				if i&pp == 0 {
+					minmax(&x[i+pp], &x[i+qq])
+				}
+			}
+		}
+	}
+}
+
+func urandom32(rnd io.Reader) uint32 {
+	var b [4]byte
+	if _, err := io.ReadFull(rnd, b[:]); err != nil {
+		panic(err)
+	}
+
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+// shortRandom draws a uniform weight-wt element of Short.
+func shortRandom(rnd io.Reader) *[p]int8 {
+	var l [p]uint32
+	for i := range l {
+		l[i] = urandom32(rnd)
+	}
+	for i := 0; i < wt; i++ {
+		l[i] &^= 1
+	}
+	for i := wt; i < p; i++ {
+		l[i] = (l[i] &^ 3) | 1
+	}
+	sortUint32(l[:])
+	var f [p]int8
+	for i := range f {
+		f[i] = int8(l[i]&3) - 1
+	}
+
+	return &f
+}
+
+// smallRandom draws a uniform element of Small.
+func smallRandom(rnd io.Reader) *[p]int8 {
+	var g [p]int8
+	for i := range g {
+		g[i] = int8(((urandom32(rnd)&0x3fffffff)*3)>>30) - 1
+	}
+
+	return &g
+}
+
+// The NTRU Prime stream encoding for sequences of integers below given
+// moduli (specification, section on Encoding).
+
+func encodeSeq(out []byte, r, m []uint16) []byte {
+	if len(r) == 0 {
+		return out
+	}
+	if len(r) == 1 {
+		rr, mm := uint32(r[0]), uint32(m[0])
+		for mm > 1 {
+			out = append(out, byte(rr))
+			rr >>= 8
+			mm = (mm + 255) >> 8
+		}
+
+		return out
+	}
+	r2 := make([]uint16, 0, (len(r)+1)/2)
+	m2 := make([]uint16, 0, (len(r)+1)/2)
+	var i int
+	for i = 0; i+1 < len(r); i += 2 {
+		m0 := uint32(m[i])
+		rr := uint32(r[i]) + uint32(r[i+1])*m0
+		mm := uint32(m[i+1]) * m0
+		for mm >= 16384 {
+			out = append(out, byte(rr))
+			rr >>= 8
+			mm = (mm + 255) >> 8
+		}
+		r2 = append(r2, uint16(rr))
+		m2 = append(m2, uint16(mm))
+	}
+	if i < len(r) {
+		r2 = append(r2, r[i])
+		m2 = append(m2, m[i])
+	}
+
+	return encodeSeq(out, r2, m2)
+}
+
+func decodeSeq(s []byte, m []uint16) []uint16 {
+	if len(m) == 0 {
+		return nil
+	}
+	if len(m) == 1 {
+		m0 := uint32(m[0])
+		switch {
+		case m0 == 1:
+			return []uint16{0}
+		case m0 <= 256:
+			return []uint16{uint16(uint32(s[0]) % m0)}
+		default:
+			return []uint16{uint16((uint32(s[0]) + uint32(s[1])<<8) % m0)}
+		}
+	}
+	n2 := (len(m) + 1) / 2
+	m2 := make([]uint16, n2)
+	bottomr := make([]uint32, n2)
+	bottomt := make([]uint32, n2)
+	idx := 0
+	var i int
+	for i = 0; i+1 < len(m); i += 2 {
+		mm := uint32(m[i]) * uint32(m[i+1])
+		switch {
+		case mm > 256*16383:
+			bottomt[i/2] = 256 * 256
+			bottomr[i/2] = uint32(s[idx]) + uint32(s[idx+1])<<8
+			idx += 2
+			m2[i/2] = uint16((((mm + 255) >> 8) + 255) >> 8)
+		case mm >= 16384:
+			bottomt[i/2] = 256
+			bottomr[i/2] = uint32(s[idx])
+			idx++
+			m2[i/2] = uint16((mm + 255) >> 8)
+		default:
+			bottomt[i/2] = 1
+			m2[i/2] = uint16(mm)
+		}
+	}
+	if i < len(m) {
+		m2[n2-1] = m[i]
+	}
+	r2 := decodeSeq(s[idx:], m2)
+	out := make([]uint16, 0, len(m))
+	for i = 0; i+1 < len(m); i += 2 {
+		r := bottomr[i/2] + bottomt[i/2]*uint32(r2[i/2])
+		r0 := r % uint32(m[i])
+		r1 := (r / uint32(m[i])) % uint32(m[i+1])
+		out = append(out, uint16(r0), uint16(r1))
+	}
+	if i < len(m) {
+		out = append(out, r2[n2-1])
+	}
+
+	return out
+}
+
+func repeatM(v uint16) []uint16 {
+	m := make([]uint16, p)
+	for i := range m {
+		m[i] = v
+	}
+
+	return m
+}
+
+func rqEncode(c *[p]int16) []byte {
+	r := make([]uint16, p)
+	for i := range r {
+		r[i] = uint16(int32(c[i]) + q12)
+	}
+
+	return encodeSeq(make([]byte, 0, pkBytes), r, repeatM(q))
+}
+
+func rqDecode(s []byte) *[p]int16 {
+	r := decodeSeq(s, repeatM(q))
+	var c [p]int16
+	for i := range c {
+		c[i] = int16(int32(r[i]) - q12)
+	}
+
+	return &c
+}
+
+func roundedEncode(c *[p]int16) []byte {
+	r := make([]uint16, p)
+	for i := range r {
+		r[i] = uint16(((int32(c[i]) + q12) * 10923) >> 15) // division by 3
+	}
+
+	return encodeSeq(make([]byte, 0, roundedBytes), r, repeatM((q+2)/3))
+}
+
+func roundedDecode(s []byte) *[p]int16 {
+	r := decodeSeq(s, repeatM((q+2)/3))
+	var c [p]int16
+	for i := range c {
+		c[i] = fqFreeze(int32(r[i])*3 - q12)
+	}
+
+	return &c
+}
+
+func smallEncode(f *[p]int8) []byte {
+	out := make([]byte, 0, smallBytes)
+	for i := 0; i+3 < p; i += 4 {
+		out = append(out, byte(f[i]+1)|byte(f[i+1]+1)<<2|
+			byte(f[i+2]+1)<<4|byte(f[i+3]+1)<<6)
+	}
+
+	return append(out, byte(f[p-1]+1))
+}
+
+func smallDecode(s []byte) *[p]int8 {
+	var f [p]int8
+	for i := 0; i+3 < p; i += 4 {
+		b := s[i/4]
+		f[i] = int8(b&3) - 1
+		f[i+1] = int8(b>>2&3) - 1
+		f[i+2] = int8(b>>4&3) - 1
+		f[i+3] = int8(b>>6&3) - 1
+	}
+	f[p-1] = int8(s[smallBytes-1]&3) - 1
+
+	return &f
+}
+
+// hashPrefix is SHA-512 over a one-byte domain prefix and the input,
+// truncated to 32 bytes.
+func hashPrefix(b byte, in ...[]byte) []byte {
+	h := sha512.New()
+	_, _ = h.Write([]byte{b})
+	for _, m := range in {
+		_, _ = h.Write(m)
+	}
+
+	return h.Sum(nil)[:32]
+}
+
+func hashConfirm(renc, cache []byte) []byte {
+	return hashPrefix(2, hashPrefix(3, renc), cache)
+}
+
+func hashSession(b byte, renc, ct []byte) []byte {
+	return hashPrefix(b, hashPrefix(3, renc), ct)
+}
+
+type scheme struct{}
+
+func (*scheme) Name() string               { return "sntrup761" }
+func (*scheme) PublicKeySize() int         { return pkBytes }
+func (*scheme) PrivateKeySize() int        { return skBytes }
+func (*scheme) CiphertextSize() int        { return ctBytes }
+func (*scheme) SharedKeySize() int         { return ssBytes }
+func (*scheme) SeedSize() int              { return seedBytes }
+func (*scheme) EncapsulationSeedSize() int { return 32 }
+
+// PublicKey is an sntrup761 encapsulation key.
+type PublicKey struct {
+	pk    []byte
+	h     *[p]int16
+	cache []byte
+}
+
+// PrivateKey is an sntrup761 decapsulation key.
+type PrivateKey struct {
+	f    *[p]int8
+	ginv *[p]int8
+	rho  []byte
+	pub  *PublicKey
+}
+
+func (pk *PublicKey) Scheme() kem.Scheme { return Scheme }
+
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, pk.pk...), nil
+}
+
+func (pk *PublicKey) Equal(other kem.PublicKey) bool {
+	o, ok := other.(*PublicKey)
+
+	return ok && subtle.ConstantTimeCompare(pk.pk, o.pk) == 1
+}
+
+func (sk *PrivateKey) Scheme() kem.Scheme { return Scheme }
+
+func (sk *PrivateKey) Public() kem.PublicKey { return sk.pub }
+
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, skBytes)
+	out = append(out, smallEncode(sk.f)...)
+	out = append(out, smallEncode(sk.ginv)...)
+	out = append(out, sk.pub.pk...)
+	out = append(out, sk.rho...)
+
+	return append(out, sk.pub.cache...), nil
+}
+
+func (sk *PrivateKey) Equal(other kem.PrivateKey) bool {
+	o, ok := other.(*PrivateKey)
+	if !ok {
+		return false
+	}
+	a, _ := sk.MarshalBinary()
+	b, _ := o.MarshalBinary()
+
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Wipe zeroes the secret polynomials and the rejection secret.
+func (sk *PrivateKey) Wipe() {
+	for i := 0; i < p; i++ {
+		sk.f[i], sk.ginv[i] = 0, 0
+	}
+	wipe.Bytes(sk.rho)
+}
+
+func deriveKeyPair(rnd io.Reader) (*PublicKey, *PrivateKey) {
+	var g, ginv *[p]int8
+	for {
+		g = smallRandom(rnd)
+		var ok int
+		ginv, ok = r3Recip(g)
+		if ok == 0 {
+			break
+		}
+	}
+	f := shortRandom(rnd)
+	finv3, _ := rqRecip3(f) // f is invertible: R/q is a field
+	h := rqMultSmall(finv3, g)
+
+	rho := make([]byte, smallBytes)
+	if _, err := io.ReadFull(rnd, rho); err != nil {
+		panic(err)
+	}
+
+	pk := &PublicKey{pk: rqEncode(h), h: h}
+	pk.cache = hashPrefix(4, pk.pk)
+	sk := &PrivateKey{f: f, ginv: ginv, rho: rho, pub: pk}
+
+	return pk, sk
+}
+
+func expandSeed(seed []byte) io.Reader {
+	h := sha3.NewShake256()
+	_, _ = h.Write(seed)
+
+	return &h
+}
+
+func (s *scheme) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
+	if len(seed) != seedBytes {
+		panic(kem.ErrSeedSize)
+	}
+	pk, sk := deriveKeyPair(expandSeed(seed))
+
+	return pk, sk
+}
+
+func (s *scheme) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
+	seed := make([]byte, seedBytes)
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	pk, sk := deriveKeyPair(expandSeed(seed))
+
+	return pk, sk, nil
+}
+
+// encrypt computes Round(h*r).
+func encrypt(pk *PublicKey, r *[p]int8) *[p]int16 {
+	c := rqMultSmall(pk.h, r)
+	round(c)
+
+	return c
+}
+
+func (s *scheme) encapsulate(ct, ss []byte, pub kem.PublicKey, r *[p]int8) error {
+	pk, ok := pub.(*PublicKey)
+	if !ok {
+		return kem.ErrTypeMismatch
+	}
+	if len(ct) != ctBytes || len(ss) != ssBytes {
+		return kem.ErrCiphertextSize
+	}
+	renc := smallEncode(r)
+	c := encrypt(pk, r)
+	copy(ct, roundedEncode(c))
+	copy(ct[roundedBytes:], hashConfirm(renc, pk.cache))
+	copy(ss, hashSession(1, renc, ct))
+
+	return nil
+}
+
+func (s *scheme) EncapsulateTo(ct, ss []byte, pk kem.PublicKey) error {
+	return s.encapsulate(ct, ss, pk, shortRandom(rand.Reader))
+}
+
+func (s *scheme) Encapsulate(pk kem.PublicKey) (ct, ss []byte, err error) {
+	ct = make([]byte, ctBytes)
+	ss = make([]byte, ssBytes)
+	if err = s.EncapsulateTo(ct, ss, pk); err != nil {
+		return nil, nil, err
+	}
+
+	return ct, ss, nil
+}
+
+func (s *scheme) EncapsulateDeterministically(pk kem.PublicKey, seed []byte) (ct, ss []byte, err error) {
+	if len(seed) != s.EncapsulationSeedSize() {
+		return nil, nil, kem.ErrSeedSize
+	}
+	ct = make([]byte, ctBytes)
+	ss = make([]byte, ssBytes)
+	if err = s.encapsulate(ct, ss, pk, shortRandom(expandSeed(seed))); err != nil {
+		return nil, nil, err
+	}
+
+	return ct, ss, nil
+}
+
+// decrypt recovers r from c, forcing a fixed weight-wt result when the
+// decoded element does not have weight wt.
+func decrypt(sk *PrivateKey, c *[p]int16) *[p]int8 {
+	cf := rqMultSmall(c, sk.f)
+	var e [p]int8
+	for i := range e {
+		e[i] = f3Freeze(int32(fqFreeze(3 * int32(cf[i]))))
+	}
+	ev := r3Mult(&e, sk.ginv)
+
+	weight := 0
+	for i := range ev {
+		weight += int(ev[i]) & 1
+	}
+	mask := int16Nonzero(int16(weight - wt)) // 0 if weight wt, else -1
+	var r [p]int8
+	for i := 0; i < wt; i++ {
+		r[i] = int8(((int(ev[i]) ^ 1) &^ mask) ^ 1)
+	}
+	for i := wt; i < p; i++ {
+		r[i] = int8(int(ev[i]) &^ mask)
+	}
+
+	return &r
+}
+
+func (s *scheme) DecapsulateTo(ss []byte, priv kem.PrivateKey, ct []byte) error {
+	sk, ok := priv.(*PrivateKey)
+	if !ok {
+		return kem.ErrTypeMismatch
+	}
+	if len(ct) != ctBytes || len(ss) != ssBytes {
+		return kem.ErrCiphertextSize
+	}
+	c := roundedDecode(ct[:roundedBytes])
+	r := decrypt(sk, c)
+	renc := smallEncode(r)
+
+	ct2 := make([]byte, ctBytes)
+	c2 := encrypt(sk.pub, r)
+	copy(ct2, roundedEncode(c2))
+	copy(ct2[roundedBytes:], hashConfirm(renc, sk.pub.cache))
+
+	// Implicit rejection: hash rho with domain 0 on mismatch.
+	same := subtle.ConstantTimeCompare(ct, ct2)
+	for i := range renc {
+		renc[i] = byte(subtle.ConstantTimeSelect(same, int(renc[i]), int(sk.rho[i])))
+	}
+	copy(ss, hashSession(byte(same), renc, ct))
+
+	return nil
+}
+
+func (s *scheme) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
+	ss := make([]byte, ssBytes)
+	if err := s.DecapsulateTo(ss, sk, ct); err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+func (s *scheme) UnmarshalBinaryPublicKey(data []byte) (kem.PublicKey, error) {
+	if len(data) != pkBytes {
+		return nil, kem.ErrPubKeySize
+	}
+	pk := &PublicKey{pk: append([]byte{}, data...)}
+	pk.h = rqDecode(pk.pk)
+	pk.cache = hashPrefix(4, pk.pk)
+
+	return pk, nil
+}
+
+func (s *scheme) UnmarshalBinaryPrivateKey(data []byte) (kem.PrivateKey, error) {
+	if len(data) != skBytes {
+		return nil, kem.ErrPrivKeySize
+	}
+	pub, err := s.UnmarshalBinaryPublicKey(data[2*smallBytes : 2*smallBytes+pkBytes])
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{
+		f:    smallDecode(data),
+		ginv: smallDecode(data[smallBytes:]),
+		rho:  append([]byte{}, data[2*smallBytes+pkBytes:2*smallBytes+pkBytes+smallBytes]...),
+		pub:  pub.(*PublicKey),
+	}, nil
+}
+
+// The sntrup761x25519-sha512 hybrid.
+
+type hybrid struct{}
+
+func (*hybrid) Name() string               { return "sntrup761x25519-sha512" }
+func (*hybrid) PublicKeySize() int         { return pkBytes + x25519.Size }
+func (*hybrid) PrivateKeySize() int        { return skBytes + x25519.Size }
+func (*hybrid) CiphertextSize() int        { return ctBytes + x25519.Size }
+func (*hybrid) SharedKeySize() int         { return sha512.Size }
+func (*hybrid) SeedSize() int              { return seedBytes + x25519.Size }
+func (*hybrid) EncapsulationSeedSize() int { return 32 + x25519.Size }
+
+// HybridPublicKey is an sntrup761x25519 encapsulation key.
+type HybridPublicKey struct {
+	ntru *PublicKey
+	dh   x25519.Key
+}
+
+// HybridPrivateKey is an sntrup761x25519 decapsulation key.
+type HybridPrivateKey struct {
+	ntru *PrivateKey
+	dh   x25519.Key
+	pub  *HybridPublicKey
+}
+
+func (pk *HybridPublicKey) Scheme() kem.Scheme { return X25519 }
+
+func (pk *HybridPublicKey) MarshalBinary() ([]byte, error) {
+	out, _ := pk.ntru.MarshalBinary()
+
+	return append(out, pk.dh[:]...), nil
+}
+
+func (pk *HybridPublicKey) Equal(other kem.PublicKey) bool {
+	o, ok := other.(*HybridPublicKey)
+
+	return ok && pk.ntru.Equal(o.ntru) &&
+		subtle.ConstantTimeCompare(pk.dh[:], o.dh[:]) == 1
+}
+
+func (sk *HybridPrivateKey) Scheme() kem.Scheme { return X25519 }
+
+func (sk *HybridPrivateKey) Public() kem.PublicKey { return sk.pub }
+
+func (sk *HybridPrivateKey) MarshalBinary() ([]byte, error) {
+	out, _ := sk.ntru.MarshalBinary()
+
+	return append(out, sk.dh[:]...), nil
+}
+
+func (sk *HybridPrivateKey) Equal(other kem.PrivateKey) bool {
+	o, ok := other.(*HybridPrivateKey)
+
+	return ok && sk.ntru.Equal(o.ntru) &&
+		subtle.ConstantTimeCompare(sk.dh[:], o.dh[:]) == 1
+}
+
+// Wipe zeroes both secret keys.
+func (sk *HybridPrivateKey) Wipe() {
+	sk.ntru.Wipe()
+	wipe.Bytes(sk.dh[:])
+}
+
+func (h *hybrid) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
+	if len(seed) != h.SeedSize() {
+		panic(kem.ErrSeedSize)
+	}
+	npk, nsk := deriveKeyPair(expandSeed(seed[:seedBytes]))
+	pk := &HybridPublicKey{ntru: npk}
+	sk := &HybridPrivateKey{ntru: nsk, pub: pk}
+	copy(sk.dh[:], seed[seedBytes:])
+	x25519.KeyGen(&pk.dh, &sk.dh)
+
+	return pk, sk
+}
+
+func (h *hybrid) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
+	seed := make([]byte, h.SeedSize())
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	pk, sk := h.DeriveKeyPair(seed)
+
+	return pk, sk, nil
+}
+
+func (h *hybrid) encapsulate(ct, ss []byte, pub kem.PublicKey, seed []byte) error {
+	pk, ok := pub.(*HybridPublicKey)
+	if !ok {
+		return kem.ErrTypeMismatch
+	}
+	if len(ct) != h.CiphertextSize() || len(ss) != sha512.Size {
+		return kem.ErrCiphertextSize
+	}
+	ssN := make([]byte, ssBytes)
+	var r *[p]int8
+	if seed == nil {
+		r = shortRandom(rand.Reader)
+	} else {
+		r = shortRandom(expandSeed(seed[:32]))
+	}
+	if err := Scheme.(*scheme).encapsulate(ct[:ctBytes], ssN, pk.ntru, r); err != nil {
+		return err
+	}
+
+	var esk, epk, ssX x25519.Key
+	if seed == nil {
+		if _, err := io.ReadFull(rand.Reader, esk[:]); err != nil {
+			return err
+		}
+	} else {
+		copy(esk[:], seed[32:])
+	}
+	x25519.KeyGen(&epk, &esk)
+	x25519.Shared(&ssX, &esk, &pk.dh)
+	copy(ct[ctBytes:], epk[:])
+
+	sum := sha512.Sum512(append(ssN, ssX[:]...))
+	copy(ss, sum[:])
+
+	return nil
+}
+
+func (h *hybrid) EncapsulateTo(ct, ss []byte, pk kem.PublicKey) error {
+	return h.encapsulate(ct, ss, pk, nil)
+}
+
+func (h *hybrid) Encapsulate(pk kem.PublicKey) (ct, ss []byte, err error) {
+	ct = make([]byte, h.CiphertextSize())
+	ss = make([]byte, sha512.Size)
+	if err = h.EncapsulateTo(ct, ss, pk); err != nil {
+		return nil, nil, err
+	}
+
+	return ct, ss, nil
+}
+
+func (h *hybrid) EncapsulateDeterministically(pk kem.PublicKey, seed []byte) (ct, ss []byte, err error) {
+	if len(seed) != h.EncapsulationSeedSize() {
+		return nil, nil, kem.ErrSeedSize
+	}
+	ct = make([]byte, h.CiphertextSize())
+	ss = make([]byte, sha512.Size)
+	if err = h.encapsulate(ct, ss, pk, seed); err != nil {
+		return nil, nil, err
+	}
+
+	return ct, ss, nil
+}
+
+func (h *hybrid) DecapsulateTo(ss []byte, priv kem.PrivateKey, ct []byte) error {
+	sk, ok := priv.(*HybridPrivateKey)
+	if !ok {
+		return kem.ErrTypeMismatch
+	}
+	if len(ct) != h.CiphertextSize() || len(ss) != sha512.Size {
+		return kem.ErrCiphertextSize
+	}
+	ssN := make([]byte, ssBytes)
+	if err := Scheme.(*scheme).DecapsulateTo(ssN, sk.ntru, ct[:ctBytes]); err != nil {
+		return err
+	}
+	var epk, ssX x25519.Key
+	copy(epk[:], ct[ctBytes:])
+	x25519.Shared(&ssX, &sk.dh, &epk)
+
+	sum := sha512.Sum512(append(ssN, ssX[:]...))
+	copy(ss, sum[:])
+
+	return nil
+}
+
+func (h *hybrid) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
+	ss := make([]byte, sha512.Size)
+	if err := h.DecapsulateTo(ss, sk, ct); err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+func (h *hybrid) UnmarshalBinaryPublicKey(data []byte) (kem.PublicKey, error) {
+	if len(data) != h.PublicKeySize() {
+		return nil, kem.ErrPubKeySize
+	}
+	npk, err := Scheme.UnmarshalBinaryPublicKey(data[:pkBytes])
+	if err != nil {
+		return nil, err
+	}
+	pk := &HybridPublicKey{ntru: npk.(*PublicKey)}
+	copy(pk.dh[:], data[pkBytes:])
+
+	return pk, nil
+}
+
+func (h *hybrid) UnmarshalBinaryPrivateKey(data []byte) (kem.PrivateKey, error) {
+	if len(data) != h.PrivateKeySize() {
+		return nil, kem.ErrPrivKeySize
+	}
+	nsk, err := Scheme.UnmarshalBinaryPrivateKey(data[:skBytes])
+	if err != nil {
+		return nil, err
+	}
+	sk := &HybridPrivateKey{ntru: nsk.(*PrivateKey)}
+	copy(sk.dh[:], data[skBytes:])
+	pk := &HybridPublicKey{ntru: sk.ntru.pub}
+	x25519.KeyGen(&pk.dh, &sk.dh)
+	sk.pub = pk
+
+	return sk, nil
+}
@@ -0,0 +1,235 @@
+// Generator of constant-time prime-field arithmetic.
+//
+// Given a prime modulus, it emits a Go package with saturated 64-bit limb
+// Montgomery arithmetic: addition, subtraction, multiplication, squaring,
+// and inversion, all running in constant time for a fixed modulus, in the
+// spirit of the fiat-crypto generated fields. New fields (e.g., for P-256,
+// P-384, or P-521) are produced by running:
+//
+//	go run ./math/fp/gen -prime <hex> -pkg fp384 -out fp384/fp_generated.go
+//
+// The generated code depends only on math/bits and never branches on
+// secrets: reductions use borrow-controlled conditional selection.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+)
+
+func main() {
+	prime := flag.String("prime", "", "prime modulus in hexadecimal")
+	pkg := flag.String("pkg", "", "name of the generated package")
+	typ := flag.String("type", "Elt", "name of the generated element type")
+	out := flag.String("out", "", "output file (default stdout)")
+	flag.Parse()
+
+	p, ok := new(big.Int).SetString(strings.TrimPrefix(*prime, "0x"), 16)
+	if !ok || !p.ProbablyPrime(64) || p.Bit(0) == 0 {
+		log.Fatal("gen: -prime must be an odd prime in hexadecimal")
+	}
+	if *pkg == "" {
+		log.Fatal("gen: -pkg is required")
+	}
+
+	code := generate(p, *pkg, *typ)
+	if *out == "" {
+		fmt.Print(code)
+		return
+	}
+	if err := os.WriteFile(*out, []byte(code), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(p *big.Int, pkg, typ string) string {
+	n := (p.BitLen() + 63) / 64
+
+	// Montgomery parameters: R = 2^(64n), RR = R^2 mod p, and
+	// m0inv = -p^-1 mod 2^64.
+	r := new(big.Int).Lsh(big.NewInt(1), uint(64*n))
+	rr := new(big.Int).Mod(new(big.Int).Mul(r, r), p)
+	b64 := new(big.Int).Lsh(big.NewInt(1), 64)
+	pInv := new(big.Int).ModInverse(new(big.Int).Mod(p, b64), b64)
+	m0inv := new(big.Int).Sub(b64, pInv)
+
+	var w strings.Builder
+	emit := func(format string, args ...interface{}) {
+		fmt.Fprintf(&w, format+"\n", args...)
+	}
+
+	emit("// Code generated by math/fp/gen. DO NOT EDIT.")
+	emit("")
+	emit("// Package %s provides arithmetic modulo", pkg)
+	emit("// p = 0x%s.", p.Text(16))
+	emit("package %s", pkg)
+	emit("")
+	emit(`import "math/bits"`)
+	emit("")
+	emit("// %s is a field element in Montgomery form, with %d limbs in", typ, n)
+	emit("// little-endian order.")
+	emit("type %s [%d]uint64", typ, n)
+	emit("")
+	emit("const numLimbs = %d", n)
+	emit("")
+	emit("var (")
+	emit("\tmodulus = %s{%s}", typ, limbs(p, n))
+	emit("\t// rr is R^2 mod p, used to enter the Montgomery domain.")
+	emit("\trr = %s{%s}", typ, limbs(rr, n))
+	emit(")")
+	emit("")
+	emit("// m0inv is -p^-1 mod 2^64.")
+	emit("const m0inv = %#x", m0inv)
+	emit(body(typ))
+
+	return w.String()
+}
+
+func limbs(x *big.Int, n int) string {
+	var parts []string
+	mask := new(big.Int).SetUint64(^uint64(0))
+	t := new(big.Int).Set(x)
+	for i := 0; i < n; i++ {
+		parts = append(parts, fmt.Sprintf("%#x", new(big.Int).And(t, mask).Uint64()))
+		t.Rsh(t, 64)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// body returns the modulus-independent routines, specialized only through
+// the constants emitted above.
+func body(typ string) string {
+	s := `
+// Add computes z = x + y mod p.
+func (z *ELT) Add(x, y *ELT) {
+	var c uint64
+	var t ELT
+	for i := range t {
+		t[i], c = add64(x[i], y[i], c)
+	}
+	z.reduce(&t, c)
+}
+
+// Sub computes z = x - y mod p.
+func (z *ELT) Sub(x, y *ELT) {
+	var b uint64
+	var t ELT
+	for i := range t {
+		t[i], b = sub64(x[i], y[i], b)
+	}
+	// Add p back if the subtraction borrowed.
+	mask := -b
+	var c uint64
+	for i := range t {
+		z[i], c = add64(t[i], modulus[i]&mask, c)
+	}
+}
+
+// Mul computes z = x * y mod p in the Montgomery domain, using the CIOS
+// method.
+func (z *ELT) Mul(x, y *ELT) {
+	var t [numLimbs + 2]uint64
+	for i := 0; i < numLimbs; i++ {
+		var c uint64
+		for j := 0; j < numLimbs; j++ {
+			t[j], c = madd(t[j], x[j], y[i], c)
+		}
+		t[numLimbs], c = add64(t[numLimbs], c, 0)
+		t[numLimbs+1] = c
+
+		m := t[0] * m0inv
+		c = 0
+		for j := 0; j < numLimbs; j++ {
+			t[j], c = madd(t[j], m, modulus[j], c)
+		}
+		t[numLimbs], c = add64(t[numLimbs], c, 0)
+		t[numLimbs+1] += c
+
+		copy(t[:], t[1:])
+		t[numLimbs+1] = 0
+	}
+
+	var u ELT
+	copy(u[:], t[:numLimbs])
+	z.reduce(&u, t[numLimbs])
+}
+
+// madd returns the low word and carry of t + a*b + c.
+func madd(t, a, b, c uint64) (lo, carry uint64) {
+	hi, lo := bits.Mul64(a, b)
+	var c1, c2 uint64
+	lo, c1 = bits.Add64(lo, t, 0)
+	lo, c2 = bits.Add64(lo, c, 0)
+	return lo, hi + c1 + c2
+}
+
+// Sqr computes z = x^2 mod p.
+func (z *ELT) Sqr(x *ELT) { z.Mul(x, x) }
+
+// Inv computes z = x^-1 mod p via Fermat's little theorem, i.e., x^(p-2).
+func (z *ELT) Inv(x *ELT) {
+	// Exponent p-2, computed limb-wise from the modulus.
+	exp := modulus
+	var b uint64
+	exp[0], b = sub64(exp[0], 2, 0)
+	for i := 1; i < numLimbs; i++ {
+		exp[i], b = sub64(exp[i], 0, b)
+	}
+
+	r := *x
+	var acc ELT
+	acc.SetOne()
+	for i := 0; i < numLimbs; i++ {
+		for bit := 0; bit < 64; bit++ {
+			var t ELT
+			t.Mul(&acc, &r)
+			ctSelect(&acc, &t, (exp[i]>>uint(bit))&1)
+			r.Sqr(&r)
+		}
+	}
+	*z = acc
+}
+
+// SetOne sets z to the multiplicative identity in the Montgomery domain.
+func (z *ELT) SetOne() {
+	var one ELT
+	one[0] = 1
+	z.toMont(&one)
+}
+
+// toMont moves x into the Montgomery domain.
+func (z *ELT) toMont(x *ELT) { z.Mul(x, &rr) }
+
+// reduce conditionally subtracts p from t, with incoming carry word, in
+// constant time. The input must be below 2p.
+func (z *ELT) reduce(t *ELT, carry uint64) {
+	var b uint64
+	var s ELT
+	for i := range s {
+		s[i], b = sub64(t[i], modulus[i], b)
+	}
+	// Use the subtracted value when the subtraction did not borrow, or
+	// when the input overflowed the limb width.
+	mask := -((1 - b) | carry)
+	for i := range z {
+		z[i] = s[i]&mask | t[i]&^mask
+	}
+}
+
+// ctSelect sets z = t if bit is 1, leaving z unchanged otherwise.
+func ctSelect(z, t *ELT, bit uint64) {
+	mask := -bit
+	for i := range z {
+		z[i] = z[i]&^mask | t[i]&mask
+	}
+}
+
+func add64(x, y, c uint64) (uint64, uint64) { return bits.Add64(x, y, c) }
+func sub64(x, y, b uint64) (uint64, uint64) { return bits.Sub64(x, y, b) }
+`
+	return strings.ReplaceAll(s, "ELT", typ)
+}
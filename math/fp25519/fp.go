@@ -0,0 +1,153 @@
+// Package fp25519 provides prime field arithmetic over GF(2^255-19).
+package fp25519
+
+import "encoding/binary"
+
+// Size in bytes of an element.
+const Size = 32
+
+// Elt is a prime field element stored in 256 little-endian bits; the
+// arithmetic keeps values only loosely reduced, so an Elt may hold any
+// representative below 2^256 until Modp maps it to its canonical value
+// in [0, p).
+type Elt [Size]byte
+
+// p is the prime modulus 2^255-19.
+var p = Elt{
+	0xed, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f,
+}
+
+// P returns the prime modulus as an element.
+func P() Elt { return p }
+
+// SetOne assigns z = 1.
+func SetOne(z *Elt) { *z = Elt{1} }
+
+// IsZero reports whether x represents zero. It is not constant time; see
+// ctIsZeroElt for the constant-time variant.
+func IsZero(x *Elt) bool {
+	t := *x
+	Modp(&t)
+	return t == Elt{}
+}
+
+// Neg calculates z = -x mod p.
+func Neg(z, x *Elt) {
+	var zero Elt
+	Sub(z, &zero, x)
+}
+
+// Inv calculates z = 1/x mod p for nonzero x, in constant time, using a
+// fixed addition chain for the exponent p-2 = 2^255-21. Run variables are
+// named after the exponent they hold: rN is x^(2^N-1).
+func Inv(z, x *Elt) {
+	x1 := *x
+	var x2, x9, x11, t, r5, r10, r50 Elt
+	Sqr(&x2, &x1)       // x^2
+	Sqr(&t, &x2)        // x^4
+	Sqr(&t, &t)         // x^8
+	Mul(&x9, &t, &x1)   // x^9
+	Mul(&x11, &x9, &x2) // x^11
+	Sqr(&t, &x11)       // x^22
+	Mul(&r5, &t, &x9)   // 2^5-1
+	t = r5
+	sqrn(&t, 5)
+	Mul(&r10, &t, &r5) // 2^10-1
+	t = r10
+	sqrn(&t, 10)
+	Mul(&t, &t, &r10) // 2^20-1
+	r20 := t
+	sqrn(&r20, 20)
+	Mul(&t, &r20, &t) // 2^40-1
+	sqrn(&t, 10)
+	Mul(&r50, &t, &r10) // 2^50-1
+	t = r50
+	sqrn(&t, 50)
+	Mul(&t, &t, &r50) // 2^100-1
+	r100 := t
+	sqrn(&r100, 100)
+	Mul(&t, &r100, &t) // 2^200-1
+	sqrn(&t, 50)
+	Mul(&t, &t, &r50) // 2^250-1
+	sqrn(&t, 5)
+	Mul(z, &t, &x11) // (2^250-1)*2^5 + 11 = 2^255-21
+}
+
+func sqrn(z *Elt, n int) {
+	for i := 0; i < n; i++ {
+		Sqr(z, z)
+	}
+}
+
+// divGeneric calculates z = x/y mod p for nonzero y.
+func divGeneric(z, x, y *Elt) {
+	var inv Elt
+	Inv(&inv, y)
+	Mul(z, x, &inv)
+}
+
+// modpGeneric reduces z to its canonical representative in [0, p), in
+// constant time.
+func modpGeneric(z *Elt) {
+	var x [4]uint64
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint64(z[8*i:])
+	}
+
+	// Fold bit 255: 2^255 = 19 mod p. Afterwards the value is at most
+	// (2^255-1) + 19 < 2p, so one conditional subtraction of p remains.
+	top := x[3] >> 63
+	x[3] &= (1 << 63) - 1
+	c := top * 19
+	for i := range x {
+		s := x[i] + c
+		c = b2u(s < c)
+		x[i] = s
+	}
+
+	// Subtract p and keep the difference unless it borrowed.
+	var y [4]uint64
+	pw := [4]uint64{^uint64(18), ^uint64(0), ^uint64(0), (1 << 63) - 1}
+	var b uint64
+	for i := range y {
+		d := x[i] - pw[i]
+		b1 := b2u(x[i] < pw[i])
+		y[i] = d - b
+		b = b1 | b2u(d < b)
+	}
+	mask := b - 1 // all-ones when the subtraction did not borrow
+	for i := range x {
+		x[i] = (x[i] &^ mask) | (y[i] & mask)
+		binary.LittleEndian.PutUint64(z[8*i:], x[i])
+	}
+}
+
+func b2u(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// cmovGeneric assigns x = y when n is 1 and leaves x untouched when n is
+// 0, in constant time.
+func cmovGeneric(x, y *Elt, n uint) {
+	mask := byte(0) - byte(n&1)
+	for i := range x {
+		x[i] = (x[i] &^ mask) | (y[i] & mask)
+	}
+}
+
+// cswapGeneric exchanges x and y when n is 1 and leaves both untouched
+// when n is 0, in constant time.
+func cswapGeneric(x, y *Elt, n uint) {
+	mask := byte(0) - byte(n&1)
+	for i := range x {
+		d := (x[i] ^ y[i]) & mask
+		x[i] ^= d
+		y[i] ^= d
+	}
+}
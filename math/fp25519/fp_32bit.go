@@ -0,0 +1,102 @@
+//go:build 386 || arm || mips || mipsle || wasm
+
+package fp25519
+
+// 32-bit limb schedules for targets whose native word is 32 bits (or, for
+// wasm, whose 64x64 multiplications are emulated). The schedules mirror
+// the 64-bit generic code but use radix-2^32 with uint64 accumulators, so
+// no double-word intrinsics are needed. fp_generic.go carries the
+// complementary build constraint.
+
+func load32(x *Elt, a *[8]uint32) {
+	for i := range a {
+		a[i] = uint32(x[4*i]) | uint32(x[4*i+1])<<8 |
+			uint32(x[4*i+2])<<16 | uint32(x[4*i+3])<<24
+	}
+}
+
+func store32(x *Elt, a *[8]uint32) {
+	for i, v := range a {
+		x[4*i] = byte(v)
+		x[4*i+1] = byte(v >> 8)
+		x[4*i+2] = byte(v >> 16)
+		x[4*i+3] = byte(v >> 24)
+	}
+}
+
+func addGeneric(z, x, y *Elt) {
+	var a, b [8]uint32
+	load32(x, &a)
+	load32(y, &b)
+	var c uint64
+	for i := range a {
+		c += uint64(a[i]) + uint64(b[i])
+		a[i] = uint32(c)
+		c >>= 32
+	}
+	// 2^256 = 38 mod p.
+	c *= 38
+	for i := range a {
+		c += uint64(a[i])
+		a[i] = uint32(c)
+		c >>= 32
+	}
+	a[0] += 38 * uint32(c)
+	store32(z, &a)
+}
+
+func subGeneric(z, x, y *Elt) {
+	var a, b [8]uint32
+	load32(x, &a)
+	load32(y, &b)
+	var br int64
+	for i := range a {
+		br += int64(a[i]) - int64(b[i])
+		a[i] = uint32(br)
+		br >>= 32
+	}
+	// br is 0 or -1; subtract 38 per wrap of 2^256.
+	br *= 38
+	for i := range a {
+		br += int64(a[i])
+		a[i] = uint32(br)
+		br >>= 32
+	}
+	a[0] -= 38 * uint32(-br)
+	store32(z, &a)
+}
+
+func mulGeneric(z, x, y *Elt) {
+	var a, b [8]uint32
+	load32(x, &a)
+	load32(y, &b)
+	// Operand-scanning product into sixteen 32-bit words.
+	var t [16]uint32
+	for i := range a {
+		var c uint64
+		for j := range b {
+			c += uint64(t[i+j]) + uint64(a[i])*uint64(b[j])
+			t[i+j] = uint32(c)
+			c >>= 32
+		}
+		t[i+8] = uint32(c)
+	}
+	// Fold the high half by 38, twice, then absorb the final carry.
+	var r [8]uint32
+	var c uint64
+	for i := range r {
+		c += uint64(t[i]) + 38*uint64(t[i+8])
+		r[i] = uint32(c)
+		c >>= 32
+	}
+	c *= 38
+	for i := range r {
+		c += uint64(r[i])
+		r[i] = uint32(c)
+		c >>= 32
+	}
+	r[0] += 38 * uint32(c)
+	store32(z, &r)
+}
+
+func sqrGeneric(z, x *Elt) { mulGeneric(z, x, x) }
@@ -0,0 +1,283 @@
+// Code generated by an internal tool based on the column products of
+// the generic implementation. Field arithmetic modulo p = 2^255-19
+// with weakly reduced (< 2^256) representatives.
+
+//go:build arm64 && !purego
+
+#include "textflag.h"
+// func fpMul(z, x, y *Elt)
+TEXT ·fpMul(SB), NOSPLIT, $0-24
+	MOVD z+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD 0(R1), R3
+	MOVD 8(R1), R4
+	MOVD 16(R1), R5
+	MOVD 24(R1), R6
+	MOVD (R2), R15
+	MUL R3, R15, R7
+	UMULH R3, R15, R8
+	MUL R4, R15, R17
+	UMULH R4, R15, R9
+	ADDS R17, R8, R8
+	MUL R5, R15, R19
+	UMULH R5, R15, R10
+	ADCS R19, R9, R9
+	MUL R6, R15, R20
+	UMULH R6, R15, R11
+	ADCS R20, R10, R10
+	ADC ZR, R11, R11
+	MOVD 8(R2), R15
+	MUL R3, R15, R16
+	UMULH R3, R15, R21
+	MUL R4, R15, R17
+	UMULH R4, R15, R22
+	MUL R5, R15, R19
+	UMULH R5, R15, R23
+	MUL R6, R15, R20
+	UMULH R6, R15, R24
+	ADDS R16, R8, R8
+	ADCS R17, R9, R9
+	ADCS R19, R10, R10
+	ADCS R20, R11, R11
+	ADC ZR, ZR, R12
+	ADDS R21, R9, R9
+	ADCS R22, R10, R10
+	ADCS R23, R11, R11
+	ADC R24, R12, R12
+	MOVD 16(R2), R15
+	MUL R3, R15, R16
+	UMULH R3, R15, R21
+	MUL R4, R15, R17
+	UMULH R4, R15, R22
+	MUL R5, R15, R19
+	UMULH R5, R15, R23
+	MUL R6, R15, R20
+	UMULH R6, R15, R24
+	ADDS R16, R9, R9
+	ADCS R17, R10, R10
+	ADCS R19, R11, R11
+	ADCS R20, R12, R12
+	ADC ZR, ZR, R13
+	ADDS R21, R10, R10
+	ADCS R22, R11, R11
+	ADCS R23, R12, R12
+	ADC R24, R13, R13
+	MOVD 24(R2), R15
+	MUL R3, R15, R16
+	UMULH R3, R15, R21
+	MUL R4, R15, R17
+	UMULH R4, R15, R22
+	MUL R5, R15, R19
+	UMULH R5, R15, R23
+	MUL R6, R15, R20
+	UMULH R6, R15, R24
+	ADDS R16, R10, R10
+	ADCS R17, R11, R11
+	ADCS R19, R12, R12
+	ADCS R20, R13, R13
+	ADC ZR, ZR, R14
+	ADDS R21, R11, R11
+	ADCS R22, R12, R12
+	ADCS R23, R13, R13
+	ADC R24, R14, R14
+	MOVD $38, R15
+	MUL R11, R15, R16
+	UMULH R11, R15, R21
+	MUL R12, R15, R17
+	UMULH R12, R15, R22
+	MUL R13, R15, R19
+	UMULH R13, R15, R23
+	MUL R14, R15, R20
+	UMULH R14, R15, R24
+	ADDS R16, R7, R7
+	ADCS R17, R8, R8
+	ADCS R19, R9, R9
+	ADCS R20, R10, R10
+	ADC ZR, ZR, R11
+	ADDS R21, R8, R8
+	ADCS R22, R9, R9
+	ADCS R23, R10, R10
+	ADC R24, R11, R11
+	MUL R11, R15, R16
+	ADDS R16, R7, R7
+	ADCS ZR, R8, R8
+	ADCS ZR, R9, R9
+	ADCS ZR, R10, R10
+	ADC ZR, ZR, R11
+	MUL R11, R15, R16
+	ADD R16, R7, R7
+	MOVD R7, 0(R0)
+	MOVD R8, 8(R0)
+	MOVD R9, 16(R0)
+	MOVD R10, 24(R0)
+	RET
+
+// func fpSqr(z, x *Elt)
+TEXT ·fpSqr(SB), NOSPLIT, $0-16
+	MOVD z+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD 0(R1), R3
+	MOVD 8(R1), R4
+	MOVD 16(R1), R5
+	MOVD 24(R1), R6
+	MOVD (R1), R15
+	MUL R3, R15, R7
+	UMULH R3, R15, R8
+	MUL R4, R15, R17
+	UMULH R4, R15, R9
+	ADDS R17, R8, R8
+	MUL R5, R15, R19
+	UMULH R5, R15, R10
+	ADCS R19, R9, R9
+	MUL R6, R15, R20
+	UMULH R6, R15, R11
+	ADCS R20, R10, R10
+	ADC ZR, R11, R11
+	MOVD 8(R1), R15
+	MUL R3, R15, R16
+	UMULH R3, R15, R21
+	MUL R4, R15, R17
+	UMULH R4, R15, R22
+	MUL R5, R15, R19
+	UMULH R5, R15, R23
+	MUL R6, R15, R20
+	UMULH R6, R15, R24
+	ADDS R16, R8, R8
+	ADCS R17, R9, R9
+	ADCS R19, R10, R10
+	ADCS R20, R11, R11
+	ADC ZR, ZR, R12
+	ADDS R21, R9, R9
+	ADCS R22, R10, R10
+	ADCS R23, R11, R11
+	ADC R24, R12, R12
+	MOVD 16(R1), R15
+	MUL R3, R15, R16
+	UMULH R3, R15, R21
+	MUL R4, R15, R17
+	UMULH R4, R15, R22
+	MUL R5, R15, R19
+	UMULH R5, R15, R23
+	MUL R6, R15, R20
+	UMULH R6, R15, R24
+	ADDS R16, R9, R9
+	ADCS R17, R10, R10
+	ADCS R19, R11, R11
+	ADCS R20, R12, R12
+	ADC ZR, ZR, R13
+	ADDS R21, R10, R10
+	ADCS R22, R11, R11
+	ADCS R23, R12, R12
+	ADC R24, R13, R13
+	MOVD 24(R1), R15
+	MUL R3, R15, R16
+	UMULH R3, R15, R21
+	MUL R4, R15, R17
+	UMULH R4, R15, R22
+	MUL R5, R15, R19
+	UMULH R5, R15, R23
+	MUL R6, R15, R20
+	UMULH R6, R15, R24
+	ADDS R16, R10, R10
+	ADCS R17, R11, R11
+	ADCS R19, R12, R12
+	ADCS R20, R13, R13
+	ADC ZR, ZR, R14
+	ADDS R21, R11, R11
+	ADCS R22, R12, R12
+	ADCS R23, R13, R13
+	ADC R24, R14, R14
+	MOVD $38, R15
+	MUL R11, R15, R16
+	UMULH R11, R15, R21
+	MUL R12, R15, R17
+	UMULH R12, R15, R22
+	MUL R13, R15, R19
+	UMULH R13, R15, R23
+	MUL R14, R15, R20
+	UMULH R14, R15, R24
+	ADDS R16, R7, R7
+	ADCS R17, R8, R8
+	ADCS R19, R9, R9
+	ADCS R20, R10, R10
+	ADC ZR, ZR, R11
+	ADDS R21, R8, R8
+	ADCS R22, R9, R9
+	ADCS R23, R10, R10
+	ADC R24, R11, R11
+	MUL R11, R15, R16
+	ADDS R16, R7, R7
+	ADCS ZR, R8, R8
+	ADCS ZR, R9, R9
+	ADCS ZR, R10, R10
+	ADC ZR, ZR, R11
+	MUL R11, R15, R16
+	ADD R16, R7, R7
+	MOVD R7, 0(R0)
+	MOVD R8, 8(R0)
+	MOVD R9, 16(R0)
+	MOVD R10, 24(R0)
+	RET
+
+// func fpAdd(z, x, y *Elt)
+TEXT ·fpAdd(SB), NOSPLIT, $0-24
+	MOVD z+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD 0(R1), R3
+	MOVD 0(R2), R7
+	MOVD 8(R1), R4
+	MOVD 8(R2), R8
+	MOVD 16(R1), R5
+	MOVD 16(R2), R9
+	MOVD 24(R1), R6
+	MOVD 24(R2), R10
+	ADDS R7, R3, R3
+	ADCS R8, R4, R4
+	ADCS R9, R5, R5
+	ADCS R10, R6, R6
+	MOVD $38, R11
+	CSEL HS, R11, ZR, R12
+	ADDS R12, R3, R3
+	ADCS ZR, R4, R4
+	ADCS ZR, R5, R5
+	ADCS ZR, R6, R6
+	CSEL HS, R11, ZR, R12
+	ADD R12, R3, R3
+	MOVD R3, 0(R0)
+	MOVD R4, 8(R0)
+	MOVD R5, 16(R0)
+	MOVD R6, 24(R0)
+	RET
+
+// func fpSub(z, x, y *Elt)
+TEXT ·fpSub(SB), NOSPLIT, $0-24
+	MOVD z+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD 0(R1), R3
+	MOVD 0(R2), R7
+	MOVD 8(R1), R4
+	MOVD 8(R2), R8
+	MOVD 16(R1), R5
+	MOVD 16(R2), R9
+	MOVD 24(R1), R6
+	MOVD 24(R2), R10
+	SUBS R7, R3, R3
+	SBCS R8, R4, R4
+	SBCS R9, R5, R5
+	SBCS R10, R6, R6
+	MOVD $38, R11
+	CSEL LO, R11, ZR, R12
+	SUBS R12, R3, R3
+	SBCS ZR, R4, R4
+	SBCS ZR, R5, R5
+	SBCS ZR, R6, R6
+	CSEL LO, R11, ZR, R12
+	SUB R12, R3, R3
+	MOVD R3, 0(R0)
+	MOVD R4, 8(R0)
+	MOVD R5, 16(R0)
+	MOVD R6, 24(R0)
+	RET
@@ -0,0 +1,33 @@
+package fp25519
+
+// InvBatch calculates z[i] = 1/z[i] for every nonzero entry of z, using
+// Montgomery's trick: one field inversion and three multiplications per
+// entry replace an inversion per entry. Zero entries are left at zero.
+// Callers converting many points to affine form, or decompressing many
+// points at once, should prefer this over per-element Inv.
+func InvBatch(z []Elt) {
+	n := len(z)
+	if n == 0 {
+		return
+	}
+	// pre[i] holds the product of the nonzero entries before index i.
+	pre := make([]Elt, n)
+	var r Elt
+	SetOne(&r)
+	for i := range z {
+		if IsZero(&z[i]) {
+			continue
+		}
+		pre[i] = r
+		Mul(&r, &r, &z[i])
+	}
+	Inv(&r, &r)
+	for i := n - 1; i >= 0; i-- {
+		if IsZero(&z[i]) {
+			continue
+		}
+		t := z[i]
+		Mul(&z[i], &r, &pre[i])
+		Mul(&r, &r, &t)
+	}
+}
@@ -0,0 +1,109 @@
+//go:build !386 && !arm && !mips && !mipsle && !wasm
+
+package fp25519
+
+// 64-bit limb schedules for the portable arithmetic, using the double-word
+// primitives of math/bits. The reduction identity is 2^256 = 38 mod p;
+// results stay below 2^256 and Modp produces the canonical value.
+// fp_32bit.go carries the complementary build constraint.
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+func load64(x *Elt, a *[4]uint64) {
+	for i := range a {
+		a[i] = binary.LittleEndian.Uint64(x[8*i:])
+	}
+}
+
+func store64(x *Elt, a *[4]uint64) {
+	for i, v := range a {
+		binary.LittleEndian.PutUint64(x[8*i:], v)
+	}
+}
+
+func addGeneric(z, x, y *Elt) {
+	var a, b [4]uint64
+	load64(x, &a)
+	load64(y, &b)
+	var c uint64
+	a[0], c = bits.Add64(a[0], b[0], 0)
+	a[1], c = bits.Add64(a[1], b[1], c)
+	a[2], c = bits.Add64(a[2], b[2], c)
+	a[3], c = bits.Add64(a[3], b[3], c)
+	// 2^256 = 38 mod p; folding the carry can carry out at most once
+	// more, and then only from a[0] through zero limbs.
+	a[0], c = bits.Add64(a[0], 38*c, 0)
+	a[1], c = bits.Add64(a[1], 0, c)
+	a[2], c = bits.Add64(a[2], 0, c)
+	a[3], c = bits.Add64(a[3], 0, c)
+	a[0] += 38 * c
+	store64(z, &a)
+}
+
+func subGeneric(z, x, y *Elt) {
+	var a, b [4]uint64
+	load64(x, &a)
+	load64(y, &b)
+	var c uint64
+	a[0], c = bits.Sub64(a[0], b[0], 0)
+	a[1], c = bits.Sub64(a[1], b[1], c)
+	a[2], c = bits.Sub64(a[2], b[2], c)
+	a[3], c = bits.Sub64(a[3], b[3], c)
+	a[0], c = bits.Sub64(a[0], 38*c, 0)
+	a[1], c = bits.Sub64(a[1], 0, c)
+	a[2], c = bits.Sub64(a[2], 0, c)
+	a[3], c = bits.Sub64(a[3], 0, c)
+	a[0] -= 38 * c
+	store64(z, &a)
+}
+
+func mulGeneric(z, x, y *Elt) {
+	var a, b [4]uint64
+	load64(x, &a)
+	load64(y, &b)
+
+	// Schoolbook product into eight limbs. The row carry cannot overflow:
+	// a[i]*b[j] + t[i+j] + carry <= (2^64-1)^2 + 2*(2^64-1) < 2^128.
+	var t [8]uint64
+	for i := range a {
+		var carry uint64
+		for j := range b {
+			hi, lo := bits.Mul64(a[i], b[j])
+			s, c1 := bits.Add64(t[i+j], lo, 0)
+			s, c2 := bits.Add64(s, carry, 0)
+			t[i+j] = s
+			carry = hi + c1 + c2
+		}
+		t[i+4] = carry
+	}
+
+	reduce512(z, &t)
+}
+
+func sqrGeneric(z, x *Elt) { mulGeneric(z, x, x) }
+
+// reduce512 folds the eight-limb product t into four limbs mod p, using
+// 2^256 = 38 mod p.
+func reduce512(z *Elt, t *[8]uint64) {
+	var r [4]uint64
+	var c, carry uint64
+	for i := 0; i < 4; i++ {
+		hi, lo := bits.Mul64(t[i+4], 38)
+		lo, c = bits.Add64(lo, carry, 0)
+		carry = hi + c
+		r[i], c = bits.Add64(t[i], lo, 0)
+		carry += c
+	}
+	// carry <= 39; fold it once more.
+	r[0], c = bits.Add64(r[0], 38*carry, 0)
+	r[1], c = bits.Add64(r[1], 0, c)
+	r[2], c = bits.Add64(r[2], 0, c)
+	r[3], c = bits.Add64(r[3], 0, c)
+	r[0] += 38 * c
+	var out Elt
+	store64(&out, &r)
+	*z = out
+}
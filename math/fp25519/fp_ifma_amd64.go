@@ -0,0 +1,58 @@
+//go:build amd64 && !purego && !tinygo
+
+package fp25519
+
+import "github.com/cloudflare/circl/internal/cpuid"
+
+// HasIFMA reports whether the vectorized Duo backend runs on AVX-512 IFMA
+// instructions, including the 128-bit forms via AVX512VL; internal/cpuid
+// applies operator overrides before this is read.
+var HasIFMA = cpuid.AVX512IFMA
+
+//go:noescape
+func mulDuoAsm(z, x, y *Duo)
+
+//go:noescape
+func sqrDuoAsm(z, x *Duo)
+
+//go:noescape
+func addDuoAsm(z, x, y *Duo)
+
+//go:noescape
+func subDuoAsm(z, x, y *Duo)
+
+// MulDuo calculates both lanes of z = x*y mod p.
+func MulDuo(z, x, y *Duo) {
+	if HasIFMA {
+		mulDuoAsm(z, x, y)
+	} else {
+		mulDuoGeneric(z, x, y)
+	}
+}
+
+// SqrDuo calculates both lanes of z = x^2 mod p.
+func SqrDuo(z, x *Duo) {
+	if HasIFMA {
+		sqrDuoAsm(z, x)
+	} else {
+		sqrDuoGeneric(z, x)
+	}
+}
+
+// AddDuo calculates both lanes of z = x+y mod p.
+func AddDuo(z, x, y *Duo) {
+	if HasIFMA {
+		addDuoAsm(z, x, y)
+	} else {
+		addDuoGeneric(z, x, y)
+	}
+}
+
+// SubDuo calculates both lanes of z = x-y mod p.
+func SubDuo(z, x, y *Duo) {
+	if HasIFMA {
+		subDuoAsm(z, x, y)
+	} else {
+		subDuoGeneric(z, x, y)
+	}
+}
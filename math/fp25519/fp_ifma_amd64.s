@@ -0,0 +1,425 @@
+// Code generated from the radix-2^52 schedule of the Duo backend; see
+// fp_ifma.go for the limb layout and bounds. All vector registers hold
+// one 52-bit limb of both lanes.
+
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// Broadcast constants: 52- and 47-bit masks, the folding factors
+// 2^260 mod p = 608 and 2^520 mod p = 369664, 19, and the limbs of 64p
+// used to keep subtraction non-negative.
+DATA ·ifmaConsts<>+0(SB)/8, $0x000fffffffffffff
+DATA ·ifmaConsts<>+8(SB)/8, $0x00007fffffffffff
+DATA ·ifmaConsts<>+16(SB)/8, $608
+DATA ·ifmaConsts<>+24(SB)/8, $369664
+DATA ·ifmaConsts<>+32(SB)/8, $19
+DATA ·ifmaConsts<>+40(SB)/8, $0x001ffffffffffb40
+DATA ·ifmaConsts<>+48(SB)/8, $0x001ffffffffffffe
+GLOBL ·ifmaConsts<>(SB), RODATA, $56
+
+// func mulDuoAsm(z, x, y *Duo)
+TEXT ·mulDuoAsm(SB), NOSPLIT, $0-24
+	MOVQ z+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	VPBROADCASTQ ·ifmaConsts<>+0(SB), X22
+	VPBROADCASTQ ·ifmaConsts<>+8(SB), X26
+	VPBROADCASTQ ·ifmaConsts<>+16(SB), X23
+	VPBROADCASTQ ·ifmaConsts<>+24(SB), X24
+	VPBROADCASTQ ·ifmaConsts<>+32(SB), X25
+	VMOVDQU64 0(SI), X0
+	VMOVDQU64 16(SI), X1
+	VMOVDQU64 32(SI), X2
+	VMOVDQU64 48(SI), X3
+	VMOVDQU64 64(SI), X4
+	VMOVDQU64 0(DX), X5
+	VMOVDQU64 16(DX), X6
+	VMOVDQU64 32(DX), X7
+	VMOVDQU64 48(DX), X8
+	VMOVDQU64 64(DX), X9
+	VPXORQ X10, X10, X10
+	VPXORQ X11, X11, X11
+	VPXORQ X12, X12, X12
+	VPXORQ X13, X13, X13
+	VPXORQ X14, X14, X14
+	VPXORQ X15, X15, X15
+	VPXORQ X16, X16, X16
+	VPXORQ X17, X17, X17
+	VPXORQ X18, X18, X18
+	VPXORQ X19, X19, X19
+	VPMADD52LUQ X0, X5, X10
+	VPMADD52HUQ X0, X5, X11
+	VPMADD52LUQ X0, X6, X11
+	VPMADD52HUQ X0, X6, X12
+	VPMADD52LUQ X0, X7, X12
+	VPMADD52HUQ X0, X7, X13
+	VPMADD52LUQ X0, X8, X13
+	VPMADD52HUQ X0, X8, X14
+	VPMADD52LUQ X0, X9, X14
+	VPMADD52HUQ X0, X9, X15
+	VPMADD52LUQ X1, X5, X11
+	VPMADD52HUQ X1, X5, X12
+	VPMADD52LUQ X1, X6, X12
+	VPMADD52HUQ X1, X6, X13
+	VPMADD52LUQ X1, X7, X13
+	VPMADD52HUQ X1, X7, X14
+	VPMADD52LUQ X1, X8, X14
+	VPMADD52HUQ X1, X8, X15
+	VPMADD52LUQ X1, X9, X15
+	VPMADD52HUQ X1, X9, X16
+	VPMADD52LUQ X2, X5, X12
+	VPMADD52HUQ X2, X5, X13
+	VPMADD52LUQ X2, X6, X13
+	VPMADD52HUQ X2, X6, X14
+	VPMADD52LUQ X2, X7, X14
+	VPMADD52HUQ X2, X7, X15
+	VPMADD52LUQ X2, X8, X15
+	VPMADD52HUQ X2, X8, X16
+	VPMADD52LUQ X2, X9, X16
+	VPMADD52HUQ X2, X9, X17
+	VPMADD52LUQ X3, X5, X13
+	VPMADD52HUQ X3, X5, X14
+	VPMADD52LUQ X3, X6, X14
+	VPMADD52HUQ X3, X6, X15
+	VPMADD52LUQ X3, X7, X15
+	VPMADD52HUQ X3, X7, X16
+	VPMADD52LUQ X3, X8, X16
+	VPMADD52HUQ X3, X8, X17
+	VPMADD52LUQ X3, X9, X17
+	VPMADD52HUQ X3, X9, X18
+	VPMADD52LUQ X4, X5, X14
+	VPMADD52HUQ X4, X5, X15
+	VPMADD52LUQ X4, X6, X15
+	VPMADD52HUQ X4, X6, X16
+	VPMADD52LUQ X4, X7, X16
+	VPMADD52HUQ X4, X7, X17
+	VPMADD52LUQ X4, X8, X17
+	VPMADD52HUQ X4, X8, X18
+	VPMADD52LUQ X4, X9, X18
+	VPMADD52HUQ X4, X9, X19
+	VPSRLQ $52, X15, X20
+	VPANDQ X22, X15, X15
+	VPADDQ X20, X16, X16
+	VPSRLQ $52, X16, X20
+	VPANDQ X22, X16, X16
+	VPADDQ X20, X17, X17
+	VPSRLQ $52, X17, X20
+	VPANDQ X22, X17, X17
+	VPADDQ X20, X18, X18
+	VPSRLQ $52, X18, X20
+	VPANDQ X22, X18, X18
+	VPADDQ X20, X19, X19
+	VPSRLQ $52, X19, X20
+	VPANDQ X22, X19, X19
+	VPMADD52LUQ X24, X20, X10
+	VPMADD52LUQ X23, X15, X10
+	VPMADD52HUQ X23, X15, X11
+	VPMADD52LUQ X23, X16, X11
+	VPMADD52HUQ X23, X16, X12
+	VPMADD52LUQ X23, X17, X12
+	VPMADD52HUQ X23, X17, X13
+	VPMADD52LUQ X23, X18, X13
+	VPMADD52HUQ X23, X18, X14
+	VPMADD52LUQ X23, X19, X14
+	VPXORQ X21, X21, X21
+	VPMADD52HUQ X23, X19, X21
+	VPMADD52LUQ X23, X21, X10
+	VPSRLQ $52, X10, X20
+	VPANDQ X22, X10, X10
+	VPADDQ X20, X11, X11
+	VPSRLQ $52, X11, X20
+	VPANDQ X22, X11, X11
+	VPADDQ X20, X12, X12
+	VPSRLQ $52, X12, X20
+	VPANDQ X22, X12, X12
+	VPADDQ X20, X13, X13
+	VPSRLQ $52, X13, X20
+	VPANDQ X22, X13, X13
+	VPADDQ X20, X14, X14
+	VPSRLQ $47, X14, X20
+	VPANDQ X26, X14, X14
+	VPMADD52LUQ X25, X20, X10
+	VPSRLQ $52, X10, X20
+	VPANDQ X22, X10, X10
+	VPADDQ X20, X11, X11
+	VPSRLQ $52, X11, X20
+	VPANDQ X22, X11, X11
+	VPADDQ X20, X12, X12
+	VPSRLQ $52, X12, X20
+	VPANDQ X22, X12, X12
+	VPADDQ X20, X13, X13
+	VPSRLQ $52, X13, X20
+	VPANDQ X22, X13, X13
+	VPADDQ X20, X14, X14
+	VMOVDQU64 X10, 0(DI)
+	VMOVDQU64 X11, 16(DI)
+	VMOVDQU64 X12, 32(DI)
+	VMOVDQU64 X13, 48(DI)
+	VMOVDQU64 X14, 64(DI)
+	RET
+
+// func sqrDuoAsm(z, x *Duo)
+TEXT ·sqrDuoAsm(SB), NOSPLIT, $0-16
+	MOVQ z+0(FP), DI
+	MOVQ x+8(FP), SI
+	VPBROADCASTQ ·ifmaConsts<>+0(SB), X22
+	VPBROADCASTQ ·ifmaConsts<>+8(SB), X26
+	VPBROADCASTQ ·ifmaConsts<>+16(SB), X23
+	VPBROADCASTQ ·ifmaConsts<>+24(SB), X24
+	VPBROADCASTQ ·ifmaConsts<>+32(SB), X25
+	VMOVDQU64 0(SI), X0
+	VMOVDQU64 16(SI), X1
+	VMOVDQU64 32(SI), X2
+	VMOVDQU64 48(SI), X3
+	VMOVDQU64 64(SI), X4
+	VMOVDQU64 0(SI), X5
+	VMOVDQU64 16(SI), X6
+	VMOVDQU64 32(SI), X7
+	VMOVDQU64 48(SI), X8
+	VMOVDQU64 64(SI), X9
+	VPXORQ X10, X10, X10
+	VPXORQ X11, X11, X11
+	VPXORQ X12, X12, X12
+	VPXORQ X13, X13, X13
+	VPXORQ X14, X14, X14
+	VPXORQ X15, X15, X15
+	VPXORQ X16, X16, X16
+	VPXORQ X17, X17, X17
+	VPXORQ X18, X18, X18
+	VPXORQ X19, X19, X19
+	VPMADD52LUQ X0, X5, X10
+	VPMADD52HUQ X0, X5, X11
+	VPMADD52LUQ X0, X6, X11
+	VPMADD52HUQ X0, X6, X12
+	VPMADD52LUQ X0, X7, X12
+	VPMADD52HUQ X0, X7, X13
+	VPMADD52LUQ X0, X8, X13
+	VPMADD52HUQ X0, X8, X14
+	VPMADD52LUQ X0, X9, X14
+	VPMADD52HUQ X0, X9, X15
+	VPMADD52LUQ X1, X5, X11
+	VPMADD52HUQ X1, X5, X12
+	VPMADD52LUQ X1, X6, X12
+	VPMADD52HUQ X1, X6, X13
+	VPMADD52LUQ X1, X7, X13
+	VPMADD52HUQ X1, X7, X14
+	VPMADD52LUQ X1, X8, X14
+	VPMADD52HUQ X1, X8, X15
+	VPMADD52LUQ X1, X9, X15
+	VPMADD52HUQ X1, X9, X16
+	VPMADD52LUQ X2, X5, X12
+	VPMADD52HUQ X2, X5, X13
+	VPMADD52LUQ X2, X6, X13
+	VPMADD52HUQ X2, X6, X14
+	VPMADD52LUQ X2, X7, X14
+	VPMADD52HUQ X2, X7, X15
+	VPMADD52LUQ X2, X8, X15
+	VPMADD52HUQ X2, X8, X16
+	VPMADD52LUQ X2, X9, X16
+	VPMADD52HUQ X2, X9, X17
+	VPMADD52LUQ X3, X5, X13
+	VPMADD52HUQ X3, X5, X14
+	VPMADD52LUQ X3, X6, X14
+	VPMADD52HUQ X3, X6, X15
+	VPMADD52LUQ X3, X7, X15
+	VPMADD52HUQ X3, X7, X16
+	VPMADD52LUQ X3, X8, X16
+	VPMADD52HUQ X3, X8, X17
+	VPMADD52LUQ X3, X9, X17
+	VPMADD52HUQ X3, X9, X18
+	VPMADD52LUQ X4, X5, X14
+	VPMADD52HUQ X4, X5, X15
+	VPMADD52LUQ X4, X6, X15
+	VPMADD52HUQ X4, X6, X16
+	VPMADD52LUQ X4, X7, X16
+	VPMADD52HUQ X4, X7, X17
+	VPMADD52LUQ X4, X8, X17
+	VPMADD52HUQ X4, X8, X18
+	VPMADD52LUQ X4, X9, X18
+	VPMADD52HUQ X4, X9, X19
+	VPSRLQ $52, X15, X20
+	VPANDQ X22, X15, X15
+	VPADDQ X20, X16, X16
+	VPSRLQ $52, X16, X20
+	VPANDQ X22, X16, X16
+	VPADDQ X20, X17, X17
+	VPSRLQ $52, X17, X20
+	VPANDQ X22, X17, X17
+	VPADDQ X20, X18, X18
+	VPSRLQ $52, X18, X20
+	VPANDQ X22, X18, X18
+	VPADDQ X20, X19, X19
+	VPSRLQ $52, X19, X20
+	VPANDQ X22, X19, X19
+	VPMADD52LUQ X24, X20, X10
+	VPMADD52LUQ X23, X15, X10
+	VPMADD52HUQ X23, X15, X11
+	VPMADD52LUQ X23, X16, X11
+	VPMADD52HUQ X23, X16, X12
+	VPMADD52LUQ X23, X17, X12
+	VPMADD52HUQ X23, X17, X13
+	VPMADD52LUQ X23, X18, X13
+	VPMADD52HUQ X23, X18, X14
+	VPMADD52LUQ X23, X19, X14
+	VPXORQ X21, X21, X21
+	VPMADD52HUQ X23, X19, X21
+	VPMADD52LUQ X23, X21, X10
+	VPSRLQ $52, X10, X20
+	VPANDQ X22, X10, X10
+	VPADDQ X20, X11, X11
+	VPSRLQ $52, X11, X20
+	VPANDQ X22, X11, X11
+	VPADDQ X20, X12, X12
+	VPSRLQ $52, X12, X20
+	VPANDQ X22, X12, X12
+	VPADDQ X20, X13, X13
+	VPSRLQ $52, X13, X20
+	VPANDQ X22, X13, X13
+	VPADDQ X20, X14, X14
+	VPSRLQ $47, X14, X20
+	VPANDQ X26, X14, X14
+	VPMADD52LUQ X25, X20, X10
+	VPSRLQ $52, X10, X20
+	VPANDQ X22, X10, X10
+	VPADDQ X20, X11, X11
+	VPSRLQ $52, X11, X20
+	VPANDQ X22, X11, X11
+	VPADDQ X20, X12, X12
+	VPSRLQ $52, X12, X20
+	VPANDQ X22, X12, X12
+	VPADDQ X20, X13, X13
+	VPSRLQ $52, X13, X20
+	VPANDQ X22, X13, X13
+	VPADDQ X20, X14, X14
+	VMOVDQU64 X10, 0(DI)
+	VMOVDQU64 X11, 16(DI)
+	VMOVDQU64 X12, 32(DI)
+	VMOVDQU64 X13, 48(DI)
+	VMOVDQU64 X14, 64(DI)
+	RET
+
+// func addDuoAsm(z, x, y *Duo)
+TEXT ·addDuoAsm(SB), NOSPLIT, $0-24
+	MOVQ z+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	VPBROADCASTQ ·ifmaConsts<>+0(SB), X22
+	VPBROADCASTQ ·ifmaConsts<>+8(SB), X26
+	VPBROADCASTQ ·ifmaConsts<>+16(SB), X23
+	VPBROADCASTQ ·ifmaConsts<>+24(SB), X24
+	VPBROADCASTQ ·ifmaConsts<>+32(SB), X25
+	VMOVDQU64 0(SI), X0
+	VMOVDQU64 0(DX), X5
+	VMOVDQU64 16(SI), X1
+	VMOVDQU64 16(DX), X6
+	VMOVDQU64 32(SI), X2
+	VMOVDQU64 32(DX), X7
+	VMOVDQU64 48(SI), X3
+	VMOVDQU64 48(DX), X8
+	VMOVDQU64 64(SI), X4
+	VMOVDQU64 64(DX), X9
+	VPADDQ X0, X5, X10
+	VPADDQ X1, X6, X11
+	VPADDQ X2, X7, X12
+	VPADDQ X3, X8, X13
+	VPADDQ X4, X9, X14
+	VPSRLQ $52, X10, X20
+	VPANDQ X22, X10, X10
+	VPADDQ X20, X11, X11
+	VPSRLQ $52, X11, X20
+	VPANDQ X22, X11, X11
+	VPADDQ X20, X12, X12
+	VPSRLQ $52, X12, X20
+	VPANDQ X22, X12, X12
+	VPADDQ X20, X13, X13
+	VPSRLQ $52, X13, X20
+	VPANDQ X22, X13, X13
+	VPADDQ X20, X14, X14
+	VPSRLQ $47, X14, X20
+	VPANDQ X26, X14, X14
+	VPMADD52LUQ X25, X20, X10
+	VPSRLQ $52, X10, X20
+	VPANDQ X22, X10, X10
+	VPADDQ X20, X11, X11
+	VPSRLQ $52, X11, X20
+	VPANDQ X22, X11, X11
+	VPADDQ X20, X12, X12
+	VPSRLQ $52, X12, X20
+	VPANDQ X22, X12, X12
+	VPADDQ X20, X13, X13
+	VPSRLQ $52, X13, X20
+	VPANDQ X22, X13, X13
+	VPADDQ X20, X14, X14
+	VMOVDQU64 X10, 0(DI)
+	VMOVDQU64 X11, 16(DI)
+	VMOVDQU64 X12, 32(DI)
+	VMOVDQU64 X13, 48(DI)
+	VMOVDQU64 X14, 64(DI)
+	RET
+
+// func subDuoAsm(z, x, y *Duo)
+TEXT ·subDuoAsm(SB), NOSPLIT, $0-24
+	MOVQ z+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	VPBROADCASTQ ·ifmaConsts<>+0(SB), X22
+	VPBROADCASTQ ·ifmaConsts<>+8(SB), X26
+	VPBROADCASTQ ·ifmaConsts<>+16(SB), X23
+	VPBROADCASTQ ·ifmaConsts<>+24(SB), X24
+	VPBROADCASTQ ·ifmaConsts<>+32(SB), X25
+	VPBROADCASTQ ·ifmaConsts<>+40(SB), X21
+	VMOVDQU64 0(SI), X0
+	VMOVDQU64 0(DX), X5
+	VMOVDQU64 16(SI), X1
+	VMOVDQU64 16(DX), X6
+	VMOVDQU64 32(SI), X2
+	VMOVDQU64 32(DX), X7
+	VMOVDQU64 48(SI), X3
+	VMOVDQU64 48(DX), X8
+	VMOVDQU64 64(SI), X4
+	VMOVDQU64 64(DX), X9
+	VPADDQ X21, X0, X0
+	VPBROADCASTQ ·ifmaConsts<>+48(SB), X21
+	VPADDQ X21, X1, X1
+	VPADDQ X21, X2, X2
+	VPADDQ X21, X3, X3
+	VPADDQ X21, X4, X4
+	VPSUBQ X5, X0, X10
+	VPSUBQ X6, X1, X11
+	VPSUBQ X7, X2, X12
+	VPSUBQ X8, X3, X13
+	VPSUBQ X9, X4, X14
+	VPSRLQ $52, X10, X20
+	VPANDQ X22, X10, X10
+	VPADDQ X20, X11, X11
+	VPSRLQ $52, X11, X20
+	VPANDQ X22, X11, X11
+	VPADDQ X20, X12, X12
+	VPSRLQ $52, X12, X20
+	VPANDQ X22, X12, X12
+	VPADDQ X20, X13, X13
+	VPSRLQ $52, X13, X20
+	VPANDQ X22, X13, X13
+	VPADDQ X20, X14, X14
+	VPSRLQ $47, X14, X20
+	VPANDQ X26, X14, X14
+	VPMADD52LUQ X25, X20, X10
+	VPSRLQ $52, X10, X20
+	VPANDQ X22, X10, X10
+	VPADDQ X20, X11, X11
+	VPSRLQ $52, X11, X20
+	VPANDQ X22, X11, X11
+	VPADDQ X20, X12, X12
+	VPSRLQ $52, X12, X20
+	VPANDQ X22, X12, X12
+	VPADDQ X20, X13, X13
+	VPSRLQ $52, X13, X20
+	VPANDQ X22, X13, X13
+	VPADDQ X20, X14, X14
+	VMOVDQU64 X10, 0(DI)
+	VMOVDQU64 X11, 16(DI)
+	VMOVDQU64 X12, 32(DI)
+	VMOVDQU64 X13, 48(DI)
+	VMOVDQU64 X14, 64(DI)
+	RET
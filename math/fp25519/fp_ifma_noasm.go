@@ -0,0 +1,19 @@
+//go:build !amd64 || purego || tinygo
+
+package fp25519
+
+// HasIFMA reports whether the vectorized Duo backend runs on AVX-512 IFMA
+// instructions.
+const HasIFMA = false
+
+// MulDuo calculates both lanes of z = x*y mod p.
+func MulDuo(z, x, y *Duo) { mulDuoGeneric(z, x, y) }
+
+// SqrDuo calculates both lanes of z = x^2 mod p.
+func SqrDuo(z, x *Duo) { sqrDuoGeneric(z, x) }
+
+// AddDuo calculates both lanes of z = x+y mod p.
+func AddDuo(z, x, y *Duo) { addDuoGeneric(z, x, y) }
+
+// SubDuo calculates both lanes of z = x-y mod p.
+func SubDuo(z, x, y *Duo) { subDuoGeneric(z, x, y) }
@@ -0,0 +1,286 @@
+// Code generated by an internal tool based on the column products of
+// the generic implementation. Field arithmetic modulo p = 2^255-19
+// with weakly reduced (< 2^256) representatives.
+
+//go:build ppc64le && !purego && !tinygo
+
+#include "textflag.h"
+// func fpMul(z, x, y *Elt)
+TEXT ·fpMul(SB), NOSPLIT, $0-24
+	MOVD z+0(FP), R3
+	MOVD x+8(FP), R4
+	MOVD y+16(FP), R5
+	MOVD 0(R4), R14
+	MOVD 8(R4), R15
+	MOVD 16(R4), R16
+	MOVD 24(R4), R17
+	MOVD 0(R5), R18
+	MULLD R14, R18, R19
+	MULHDU R14, R18, R20
+	MULLD R15, R18, R7
+	MULHDU R15, R18, R21
+	ADDC R7, R20, R20
+	MULLD R16, R18, R8
+	MULHDU R16, R18, R22
+	ADDE R8, R21, R21
+	MULLD R17, R18, R9
+	MULHDU R17, R18, R23
+	ADDE R9, R22, R22
+	ADDZE R23, R23
+	MOVD 8(R5), R18
+	MULLD R14, R18, R7
+	MULLD R15, R18, R8
+	MULLD R16, R18, R9
+	MULLD R17, R18, R10
+	ADDC R7, R20, R20
+	ADDE R8, R21, R21
+	ADDE R9, R22, R22
+	ADDE R10, R23, R23
+	ADDZE R0, R24
+	MULHDU R14, R18, R11
+	MULHDU R15, R18, R12
+	MULHDU R16, R18, R27
+	MULHDU R17, R18, R28
+	ADDC R11, R21, R21
+	ADDE R12, R22, R22
+	ADDE R27, R23, R23
+	ADDE R28, R24, R24
+	MOVD 16(R5), R18
+	MULLD R14, R18, R7
+	MULLD R15, R18, R8
+	MULLD R16, R18, R9
+	MULLD R17, R18, R10
+	ADDC R7, R21, R21
+	ADDE R8, R22, R22
+	ADDE R9, R23, R23
+	ADDE R10, R24, R24
+	ADDZE R0, R25
+	MULHDU R14, R18, R11
+	MULHDU R15, R18, R12
+	MULHDU R16, R18, R27
+	MULHDU R17, R18, R28
+	ADDC R11, R22, R22
+	ADDE R12, R23, R23
+	ADDE R27, R24, R24
+	ADDE R28, R25, R25
+	MOVD 24(R5), R18
+	MULLD R14, R18, R7
+	MULLD R15, R18, R8
+	MULLD R16, R18, R9
+	MULLD R17, R18, R10
+	ADDC R7, R22, R22
+	ADDE R8, R23, R23
+	ADDE R9, R24, R24
+	ADDE R10, R25, R25
+	ADDZE R0, R26
+	MULHDU R14, R18, R11
+	MULHDU R15, R18, R12
+	MULHDU R16, R18, R27
+	MULHDU R17, R18, R28
+	ADDC R11, R23, R23
+	ADDE R12, R24, R24
+	ADDE R27, R25, R25
+	ADDE R28, R26, R26
+	MOVD $38, R6
+	MULLD R23, R6, R7
+	MULHDU R23, R6, R11
+	MULLD R24, R6, R8
+	MULHDU R24, R6, R12
+	MULLD R25, R6, R9
+	MULHDU R25, R6, R27
+	MULLD R26, R6, R10
+	MULHDU R26, R6, R28
+	ADDC R7, R19, R19
+	ADDE R8, R20, R20
+	ADDE R9, R21, R21
+	ADDE R10, R22, R22
+	ADDZE R0, R23
+	ADDC R11, R20, R20
+	ADDE R12, R21, R21
+	ADDE R27, R22, R22
+	ADDE R28, R23, R23
+	MULLD R23, R6, R7
+	ADDC R7, R19, R19
+	ADDZE R20, R20
+	ADDZE R21, R21
+	ADDZE R22, R22
+	ADDZE R0, R23
+	MULLD R23, R6, R7
+	ADD R7, R19, R19
+	MOVD R19, 0(R3)
+	MOVD R20, 8(R3)
+	MOVD R21, 16(R3)
+	MOVD R22, 24(R3)
+	RET
+
+// func fpSqr(z, x *Elt)
+TEXT ·fpSqr(SB), NOSPLIT, $0-16
+	MOVD z+0(FP), R3
+	MOVD x+8(FP), R4
+	MOVD x+8(FP), R5
+	MOVD 0(R4), R14
+	MOVD 8(R4), R15
+	MOVD 16(R4), R16
+	MOVD 24(R4), R17
+	MOVD 0(R5), R18
+	MULLD R14, R18, R19
+	MULHDU R14, R18, R20
+	MULLD R15, R18, R7
+	MULHDU R15, R18, R21
+	ADDC R7, R20, R20
+	MULLD R16, R18, R8
+	MULHDU R16, R18, R22
+	ADDE R8, R21, R21
+	MULLD R17, R18, R9
+	MULHDU R17, R18, R23
+	ADDE R9, R22, R22
+	ADDZE R23, R23
+	MOVD 8(R5), R18
+	MULLD R14, R18, R7
+	MULLD R15, R18, R8
+	MULLD R16, R18, R9
+	MULLD R17, R18, R10
+	ADDC R7, R20, R20
+	ADDE R8, R21, R21
+	ADDE R9, R22, R22
+	ADDE R10, R23, R23
+	ADDZE R0, R24
+	MULHDU R14, R18, R11
+	MULHDU R15, R18, R12
+	MULHDU R16, R18, R27
+	MULHDU R17, R18, R28
+	ADDC R11, R21, R21
+	ADDE R12, R22, R22
+	ADDE R27, R23, R23
+	ADDE R28, R24, R24
+	MOVD 16(R5), R18
+	MULLD R14, R18, R7
+	MULLD R15, R18, R8
+	MULLD R16, R18, R9
+	MULLD R17, R18, R10
+	ADDC R7, R21, R21
+	ADDE R8, R22, R22
+	ADDE R9, R23, R23
+	ADDE R10, R24, R24
+	ADDZE R0, R25
+	MULHDU R14, R18, R11
+	MULHDU R15, R18, R12
+	MULHDU R16, R18, R27
+	MULHDU R17, R18, R28
+	ADDC R11, R22, R22
+	ADDE R12, R23, R23
+	ADDE R27, R24, R24
+	ADDE R28, R25, R25
+	MOVD 24(R5), R18
+	MULLD R14, R18, R7
+	MULLD R15, R18, R8
+	MULLD R16, R18, R9
+	MULLD R17, R18, R10
+	ADDC R7, R22, R22
+	ADDE R8, R23, R23
+	ADDE R9, R24, R24
+	ADDE R10, R25, R25
+	ADDZE R0, R26
+	MULHDU R14, R18, R11
+	MULHDU R15, R18, R12
+	MULHDU R16, R18, R27
+	MULHDU R17, R18, R28
+	ADDC R11, R23, R23
+	ADDE R12, R24, R24
+	ADDE R27, R25, R25
+	ADDE R28, R26, R26
+	MOVD $38, R6
+	MULLD R23, R6, R7
+	MULHDU R23, R6, R11
+	MULLD R24, R6, R8
+	MULHDU R24, R6, R12
+	MULLD R25, R6, R9
+	MULHDU R25, R6, R27
+	MULLD R26, R6, R10
+	MULHDU R26, R6, R28
+	ADDC R7, R19, R19
+	ADDE R8, R20, R20
+	ADDE R9, R21, R21
+	ADDE R10, R22, R22
+	ADDZE R0, R23
+	ADDC R11, R20, R20
+	ADDE R12, R21, R21
+	ADDE R27, R22, R22
+	ADDE R28, R23, R23
+	MULLD R23, R6, R7
+	ADDC R7, R19, R19
+	ADDZE R20, R20
+	ADDZE R21, R21
+	ADDZE R22, R22
+	ADDZE R0, R23
+	MULLD R23, R6, R7
+	ADD R7, R19, R19
+	MOVD R19, 0(R3)
+	MOVD R20, 8(R3)
+	MOVD R21, 16(R3)
+	MOVD R22, 24(R3)
+	RET
+
+// func fpAdd(z, x, y *Elt)
+TEXT ·fpAdd(SB), NOSPLIT, $0-24
+	MOVD z+0(FP), R3
+	MOVD x+8(FP), R4
+	MOVD y+16(FP), R5
+	MOVD 0(R4), R14
+	MOVD 0(R5), R7
+	MOVD 8(R4), R15
+	MOVD 8(R5), R8
+	MOVD 16(R4), R16
+	MOVD 16(R5), R9
+	MOVD 24(R4), R17
+	MOVD 24(R5), R10
+	ADDC R7, R14, R14
+	ADDE R8, R15, R15
+	ADDE R9, R16, R16
+	ADDE R10, R17, R17
+	ADDZE R0, R6
+	MULLD $38, R6, R6
+	ADDC R6, R14, R14
+	ADDZE R15, R15
+	ADDZE R16, R16
+	ADDZE R17, R17
+	ADDZE R0, R6
+	MULLD $38, R6, R6
+	ADD R6, R14, R14
+	MOVD R14, 0(R3)
+	MOVD R15, 8(R3)
+	MOVD R16, 16(R3)
+	MOVD R17, 24(R3)
+	RET
+
+// func fpSub(z, x, y *Elt)
+TEXT ·fpSub(SB), NOSPLIT, $0-24
+	MOVD z+0(FP), R3
+	MOVD x+8(FP), R4
+	MOVD y+16(FP), R5
+	MOVD 0(R4), R14
+	MOVD 0(R5), R7
+	MOVD 8(R4), R15
+	MOVD 8(R5), R8
+	MOVD 16(R4), R16
+	MOVD 16(R5), R9
+	MOVD 24(R4), R17
+	MOVD 24(R5), R10
+	SUBC R7, R14, R14
+	SUBE R8, R15, R15
+	SUBE R9, R16, R16
+	SUBE R10, R17, R17
+	SUBE R6, R6, R6
+	ANDCC $38, R6, R6
+	SUBC R6, R14, R14
+	SUBE R0, R15, R15
+	SUBE R0, R16, R16
+	SUBE R0, R17, R17
+	SUBE R6, R6, R6
+	ANDCC $38, R6, R6
+	SUB R6, R14, R14
+	MOVD R14, 0(R3)
+	MOVD R15, 8(R3)
+	MOVD R16, 16(R3)
+	MOVD R17, 24(R3)
+	RET
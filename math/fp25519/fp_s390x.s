@@ -0,0 +1,494 @@
+// Code generated by an internal tool based on the column products of
+// the generic implementation. Field arithmetic modulo p = 2^255-19
+// with weakly reduced (< 2^256) representatives. MULHDU assembles to
+// MLGR on the R10:R11 pair: the high half replaces the second operand
+// and the low half lands in R11.
+
+//go:build s390x && !purego && !tinygo
+
+#include "textflag.h"
+// func fpMul(z, x, y *Elt)
+TEXT ·fpMul(SB), NOSPLIT, $32-24
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD $0, R0
+	MOVD R0, R4
+	MOVD R0, R5
+	MOVD R0, R7
+	MOVD R0, R8
+	MOVD 0(R2), R3
+	MOVD R0, R12
+	MOVD 0(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R4, R11
+	ADDE R0, R6
+	MOVD R11, R4
+	MOVD R6, R12
+	MOVD 8(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R5, R11
+	ADDE R0, R6
+	MOVD R11, R5
+	MOVD R6, R12
+	MOVD 16(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R7, R11
+	ADDE R0, R6
+	MOVD R11, R7
+	MOVD R6, R12
+	MOVD 24(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R8, R11
+	ADDE R0, R6
+	MOVD R11, R8
+	MOVD R6, R12
+	MOVD R4, p0-32(SP)
+	MOVD R5, R4
+	MOVD R7, R5
+	MOVD R8, R7
+	MOVD R12, R8
+	MOVD 8(R2), R3
+	MOVD R0, R12
+	MOVD 0(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R4, R11
+	ADDE R0, R6
+	MOVD R11, R4
+	MOVD R6, R12
+	MOVD 8(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R5, R11
+	ADDE R0, R6
+	MOVD R11, R5
+	MOVD R6, R12
+	MOVD 16(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R7, R11
+	ADDE R0, R6
+	MOVD R11, R7
+	MOVD R6, R12
+	MOVD 24(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R8, R11
+	ADDE R0, R6
+	MOVD R11, R8
+	MOVD R6, R12
+	MOVD R4, p1-24(SP)
+	MOVD R5, R4
+	MOVD R7, R5
+	MOVD R8, R7
+	MOVD R12, R8
+	MOVD 16(R2), R3
+	MOVD R0, R12
+	MOVD 0(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R4, R11
+	ADDE R0, R6
+	MOVD R11, R4
+	MOVD R6, R12
+	MOVD 8(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R5, R11
+	ADDE R0, R6
+	MOVD R11, R5
+	MOVD R6, R12
+	MOVD 16(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R7, R11
+	ADDE R0, R6
+	MOVD R11, R7
+	MOVD R6, R12
+	MOVD 24(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R8, R11
+	ADDE R0, R6
+	MOVD R11, R8
+	MOVD R6, R12
+	MOVD R4, p2-16(SP)
+	MOVD R5, R4
+	MOVD R7, R5
+	MOVD R8, R7
+	MOVD R12, R8
+	MOVD 24(R2), R3
+	MOVD R0, R12
+	MOVD 0(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R4, R11
+	ADDE R0, R6
+	MOVD R11, R4
+	MOVD R6, R12
+	MOVD 8(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R5, R11
+	ADDE R0, R6
+	MOVD R11, R5
+	MOVD R6, R12
+	MOVD 16(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R7, R11
+	ADDE R0, R6
+	MOVD R11, R7
+	MOVD R6, R12
+	MOVD 24(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R8, R11
+	ADDE R0, R6
+	MOVD R11, R8
+	MOVD R6, R12
+	MOVD R4, p3-8(SP)
+	MOVD R5, R4
+	MOVD R7, R5
+	MOVD R8, R7
+	MOVD R12, R8
+	MOVD $38, R3
+	MOVD p0-32(SP), R1
+	MOVD p1-24(SP), R2
+	MOVD p2-16(SP), R9
+	MOVD p3-8(SP), R12
+	MULHDU R3, R4
+	ADDC R1, R11
+	ADDE R0, R4
+	MOVD R11, R1
+	MULHDU R3, R5
+	ADDC R4, R11
+	ADDE R0, R5
+	ADDC R2, R11
+	ADDE R0, R5
+	MOVD R11, R2
+	MULHDU R3, R7
+	ADDC R5, R11
+	ADDE R0, R7
+	ADDC R9, R11
+	ADDE R0, R7
+	MOVD R11, R9
+	MULHDU R3, R8
+	ADDC R7, R11
+	ADDE R0, R8
+	ADDC R12, R11
+	ADDE R0, R8
+	MOVD R11, R12
+	MULHDU R3, R8
+	ADDC R11, R1
+	ADDE R0, R2
+	ADDE R0, R9
+	ADDE R0, R12
+	MOVD R0, R8
+	ADDE R0, R8
+	MULLD R3, R8
+	ADD R8, R1
+	MOVD z+0(FP), R3
+	MOVD R1, 0(R3)
+	MOVD R2, 8(R3)
+	MOVD R9, 16(R3)
+	MOVD R12, 24(R3)
+	RET
+
+// func fpSqr(z, x *Elt)
+TEXT ·fpSqr(SB), NOSPLIT, $32-16
+	MOVD x+8(FP), R1
+	MOVD x+8(FP), R2
+	MOVD $0, R0
+	MOVD R0, R4
+	MOVD R0, R5
+	MOVD R0, R7
+	MOVD R0, R8
+	MOVD 0(R2), R3
+	MOVD R0, R12
+	MOVD 0(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R4, R11
+	ADDE R0, R6
+	MOVD R11, R4
+	MOVD R6, R12
+	MOVD 8(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R5, R11
+	ADDE R0, R6
+	MOVD R11, R5
+	MOVD R6, R12
+	MOVD 16(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R7, R11
+	ADDE R0, R6
+	MOVD R11, R7
+	MOVD R6, R12
+	MOVD 24(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R8, R11
+	ADDE R0, R6
+	MOVD R11, R8
+	MOVD R6, R12
+	MOVD R4, p0-32(SP)
+	MOVD R5, R4
+	MOVD R7, R5
+	MOVD R8, R7
+	MOVD R12, R8
+	MOVD 8(R2), R3
+	MOVD R0, R12
+	MOVD 0(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R4, R11
+	ADDE R0, R6
+	MOVD R11, R4
+	MOVD R6, R12
+	MOVD 8(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R5, R11
+	ADDE R0, R6
+	MOVD R11, R5
+	MOVD R6, R12
+	MOVD 16(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R7, R11
+	ADDE R0, R6
+	MOVD R11, R7
+	MOVD R6, R12
+	MOVD 24(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R8, R11
+	ADDE R0, R6
+	MOVD R11, R8
+	MOVD R6, R12
+	MOVD R4, p1-24(SP)
+	MOVD R5, R4
+	MOVD R7, R5
+	MOVD R8, R7
+	MOVD R12, R8
+	MOVD 16(R2), R3
+	MOVD R0, R12
+	MOVD 0(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R4, R11
+	ADDE R0, R6
+	MOVD R11, R4
+	MOVD R6, R12
+	MOVD 8(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R5, R11
+	ADDE R0, R6
+	MOVD R11, R5
+	MOVD R6, R12
+	MOVD 16(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R7, R11
+	ADDE R0, R6
+	MOVD R11, R7
+	MOVD R6, R12
+	MOVD 24(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R8, R11
+	ADDE R0, R6
+	MOVD R11, R8
+	MOVD R6, R12
+	MOVD R4, p2-16(SP)
+	MOVD R5, R4
+	MOVD R7, R5
+	MOVD R8, R7
+	MOVD R12, R8
+	MOVD 24(R2), R3
+	MOVD R0, R12
+	MOVD 0(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R4, R11
+	ADDE R0, R6
+	MOVD R11, R4
+	MOVD R6, R12
+	MOVD 8(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R5, R11
+	ADDE R0, R6
+	MOVD R11, R5
+	MOVD R6, R12
+	MOVD 16(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R7, R11
+	ADDE R0, R6
+	MOVD R11, R7
+	MOVD R6, R12
+	MOVD 24(R1), R6
+	MULHDU R3, R6
+	ADDC R12, R11
+	ADDE R0, R6
+	ADDC R8, R11
+	ADDE R0, R6
+	MOVD R11, R8
+	MOVD R6, R12
+	MOVD R4, p3-8(SP)
+	MOVD R5, R4
+	MOVD R7, R5
+	MOVD R8, R7
+	MOVD R12, R8
+	MOVD $38, R3
+	MOVD p0-32(SP), R1
+	MOVD p1-24(SP), R2
+	MOVD p2-16(SP), R9
+	MOVD p3-8(SP), R12
+	MULHDU R3, R4
+	ADDC R1, R11
+	ADDE R0, R4
+	MOVD R11, R1
+	MULHDU R3, R5
+	ADDC R4, R11
+	ADDE R0, R5
+	ADDC R2, R11
+	ADDE R0, R5
+	MOVD R11, R2
+	MULHDU R3, R7
+	ADDC R5, R11
+	ADDE R0, R7
+	ADDC R9, R11
+	ADDE R0, R7
+	MOVD R11, R9
+	MULHDU R3, R8
+	ADDC R7, R11
+	ADDE R0, R8
+	ADDC R12, R11
+	ADDE R0, R8
+	MOVD R11, R12
+	MULHDU R3, R8
+	ADDC R11, R1
+	ADDE R0, R2
+	ADDE R0, R9
+	ADDE R0, R12
+	MOVD R0, R8
+	ADDE R0, R8
+	MULLD R3, R8
+	ADD R8, R1
+	MOVD z+0(FP), R3
+	MOVD R1, 0(R3)
+	MOVD R2, 8(R3)
+	MOVD R9, 16(R3)
+	MOVD R12, 24(R3)
+	RET
+
+// func fpAdd(z, x, y *Elt)
+TEXT ·fpAdd(SB), NOSPLIT, $0-24
+	MOVD $0, R0
+	MOVD x+8(FP), R6
+	MOVD 0(R6), R1
+	MOVD 8(R6), R2
+	MOVD 16(R6), R3
+	MOVD 24(R6), R4
+	MOVD y+16(FP), R6
+	MOVD 0(R6), R5
+	MOVD 8(R6), R7
+	MOVD 16(R6), R8
+	MOVD 24(R6), R9
+	ADDC R5, R1
+	ADDE R7, R2
+	ADDE R8, R3
+	ADDE R9, R4
+	MOVD R0, R5
+	ADDE R0, R5
+	MULLD $38, R5
+	ADDC R5, R1
+	ADDE R0, R2
+	ADDE R0, R3
+	ADDE R0, R4
+	MOVD R0, R5
+	ADDE R0, R5
+	MULLD $38, R5
+	ADD R5, R1
+	MOVD z+0(FP), R6
+	MOVD R1, 0(R6)
+	MOVD R2, 8(R6)
+	MOVD R3, 16(R6)
+	MOVD R4, 24(R6)
+	RET
+
+// func fpSub(z, x, y *Elt)
+TEXT ·fpSub(SB), NOSPLIT, $0-24
+	MOVD $0, R0
+	MOVD x+8(FP), R6
+	MOVD 0(R6), R1
+	MOVD 8(R6), R2
+	MOVD 16(R6), R3
+	MOVD 24(R6), R4
+	MOVD y+16(FP), R6
+	MOVD 0(R6), R5
+	MOVD 8(R6), R7
+	MOVD 16(R6), R8
+	MOVD 24(R6), R9
+	SUBC R5, R1
+	SUBE R7, R2
+	SUBE R8, R3
+	SUBE R9, R4
+	MOVD R0, R5
+	SUBE R0, R5
+	AND $38, R5
+	SUBC R5, R1
+	SUBE R0, R2
+	SUBE R0, R3
+	SUBE R0, R4
+	MOVD R0, R5
+	SUBE R0, R5
+	AND $38, R5
+	SUB R5, R1
+	MOVD z+0(FP), R6
+	MOVD R1, 0(R6)
+	MOVD R2, 8(R6)
+	MOVD R3, 16(R6)
+	MOVD R4, 24(R6)
+	RET
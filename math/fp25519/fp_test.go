@@ -0,0 +1,211 @@
+package fp25519
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func randElt(t *testing.T) Elt {
+	var x Elt
+	if _, err := rand.Read(x[:]); err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func toBig(x *Elt) *big.Int {
+	var b [Size]byte
+	for i := range b {
+		b[i] = x[Size-1-i]
+	}
+	v := new(big.Int).SetBytes(b[:])
+	p := new(big.Int).Lsh(big.NewInt(1), 255)
+	p.Sub(p, big.NewInt(19))
+	return v.Mod(v, p)
+}
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInvDivNeg(t *testing.T) {
+	p := new(big.Int).Lsh(big.NewInt(1), 255)
+	p.Sub(p, big.NewInt(19))
+	for i := 0; i < 128; i++ {
+		x, y := randElt(t), randElt(t)
+		var inv, got, one Elt
+		Inv(&inv, &x)
+		Mul(&got, &x, &inv)
+		SetOne(&one)
+		Modp(&got)
+		if got != one {
+			t.Fatal("x * 1/x != 1")
+		}
+
+		Div(&got, &x, &y)
+		want := new(big.Int).ModInverse(toBig(&y), p)
+		want.Mul(want, toBig(&x)).Mod(want, p)
+		if toBig(&got).Cmp(want) != 0 {
+			t.Fatal("Div diverges from math/big")
+		}
+
+		var neg Elt
+		Neg(&neg, &x)
+		Add(&got, &neg, &x)
+		if !IsZero(&got) {
+			t.Fatal("x + (-x) != 0")
+		}
+	}
+}
+
+func TestModpEdgeCases(t *testing.T) {
+	pp := P()
+	Modp(&pp)
+	if pp != (Elt{}) {
+		t.Error("Modp(p) != 0")
+	}
+
+	// p+1 and 2^256-1 must reduce to 1 and 2^256-1 mod p.
+	pPlus1 := P()
+	pPlus1[0]++
+	Modp(&pPlus1)
+	if pPlus1 != (Elt{1}) {
+		t.Error("Modp(p+1) != 1")
+	}
+
+	var all Elt
+	for i := range all {
+		all[i] = 0xff
+	}
+	want := toBig(&all)
+	Modp(&all)
+	if toBig(&all).Cmp(want) != 0 {
+		t.Error("Modp(2^256-1) wrong")
+	}
+}
+
+func TestCmovCswap(t *testing.T) {
+	x, y := randElt(t), randElt(t)
+	x0, y0 := x, y
+	Cmov(&x, &y, 0)
+	if x != x0 {
+		t.Error("Cmov(0) moved")
+	}
+	Cmov(&x, &y, 1)
+	if x != y {
+		t.Error("Cmov(1) did not move")
+	}
+	x = x0
+	Cswap(&x, &y, 1)
+	if x != y0 || y != x0 {
+		t.Error("Cswap(1) did not swap")
+	}
+}
+
+func TestSqrtRatio(t *testing.T) {
+	for i := 0; i < 64; i++ {
+		u, v := randElt(t), randElt(t)
+		if IsZero(&v) {
+			continue
+		}
+		var z, z2, q Elt
+		isQR := SqrtRatio(&z, &u, &v)
+		Sqr(&z2, &z)
+		Div(&q, &u, &v)
+		if isQR == 0 {
+			Mul(&q, &q, &sqrtMinusOne)
+		}
+		Sub(&q, &z2, &q)
+		if !IsZero(&q) {
+			t.Fatal("SqrtRatio root does not square back")
+		}
+
+		want := 0
+		if new(big.Int).ModInverse(toBig(&v), pBig()).Int64() != -1 { // always invertible
+			r := new(big.Int).ModInverse(toBig(&v), pBig())
+			r.Mul(r, toBig(&u)).Mod(r, pBig())
+			if big.Jacobi(r, pBig()) >= 0 {
+				want = 1
+			}
+		}
+		if int(isQR) != want {
+			t.Fatal("SqrtRatio QR flag wrong")
+		}
+	}
+}
+
+func pBig() *big.Int {
+	p := new(big.Int).Lsh(big.NewInt(1), 255)
+	return p.Sub(p, big.NewInt(19))
+}
+
+func TestLegendre(t *testing.T) {
+	for i := 0; i < 64; i++ {
+		x := randElt(t)
+		if got, want := Legendre(&x), big.Jacobi(toBig(&x), pBig()); got != want {
+			t.Fatalf("Legendre = %d, want %d", got, want)
+		}
+	}
+}
+
+func TestInvBatch(t *testing.T) {
+	z := make([]Elt, 10)
+	for i := range z {
+		if i != 3 { // keep one zero entry
+			z[i] = randElt(t)
+		}
+	}
+	want := make([]Elt, len(z))
+	for i := range z {
+		if !IsZero(&z[i]) {
+			Inv(&want[i], &z[i])
+		}
+	}
+	InvBatch(z)
+	for i := range z {
+		Sub(&z[i], &z[i], &want[i])
+		if !IsZero(&z[i]) {
+			t.Fatalf("InvBatch entry %d wrong", i)
+		}
+	}
+}
+
+func TestDuo(t *testing.T) {
+	for i := 0; i < 64; i++ {
+		x0, x1, y0, y1 := randElt(t), randElt(t), randElt(t), randElt(t)
+		// The packed radix-2^52 form takes only the low 255 bits.
+		x0[31] &= 0x7f
+		x1[31] &= 0x7f
+		y0[31] &= 0x7f
+		y1[31] &= 0x7f
+		var x, y, z Duo
+		x.FromElts(&x0, &x1)
+		y.FromElts(&y0, &y1)
+
+		var g0, g1, w0, w1 Elt
+		MulDuo(&z, &x, &y)
+		z.ToElts(&g0, &g1)
+		Mul(&w0, &x0, &y0)
+		Mul(&w1, &x1, &y1)
+		for _, d := range []struct{ g, w *Elt }{{&g0, &w0}, {&g1, &w1}} {
+			Sub(d.g, d.g, d.w)
+			if !IsZero(d.g) {
+				t.Fatal("MulDuo lane diverges from Mul")
+			}
+		}
+
+		SqrDuo(&z, &x)
+		z.ToElts(&g0, &g1)
+		Sqr(&w0, &x0)
+		Sqr(&w1, &x1)
+		for _, d := range []struct{ g, w *Elt }{{&g0, &w0}, {&g1, &w1}} {
+			Sub(d.g, d.g, d.w)
+			if !IsZero(d.g) {
+				t.Fatal("SqrDuo lane diverges from Sqr")
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package fp25519
+
+// This file implements the sqrt_ratio and is_square primitives of
+// RFC 9380, Appendix F, in constant time. SqrtRatio covers both the
+// Elligator/SSWU maps and ristretto-style decoding, which need the square
+// root of a quotient together with the quadratic-residue test in a single
+// constant-time operation.
+
+var (
+	// sqrtMinusOne is 2^((p-1)/4), a square root of -1.
+	sqrtMinusOne = Elt{0xb0, 0xa0, 0x0e, 0x4a, 0x27, 0x1b, 0xee, 0xc4, 0x78, 0xe4, 0x2f, 0xad, 0x06, 0x18, 0x43, 0x2f, 0xa7, 0xd7, 0xfb, 0x3d, 0x99, 0x00, 0x4d, 0x2b, 0x0b, 0xdf, 0xc1, 0x4f, 0x80, 0x24, 0x83, 0x2b}
+	// expSqrtRatio is (p+3)/8 = 2^252 - 2.
+	expSqrtRatio = Elt{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x0f}
+)
+
+// IsSquare reports whether x is a quadratic residue, returning 1 for
+// squares (including zero) and 0 otherwise, in constant time.
+func IsSquare(x *Elt) uint {
+	var l Elt
+	powLegendre(&l, x)
+	var one Elt
+	SetOne(&one)
+	Sub(&one, &l, &one)
+	return ctIsZeroElt(&l) | ctIsZeroElt(&one)
+}
+
+// SqrtRatio sets z so that z^2 = u/v when u/v is square, returning 1, and
+// z^2 = sqrt(-1)*u/v otherwise, returning 0, all in constant time. The
+// divisor v must be nonzero.
+func SqrtRatio(z, u, v *Elt) uint {
+	var t Elt
+	Div(&t, u, v)
+
+	var s, s2, d Elt
+	powElt(&s, &t, &expSqrtRatio) // s = t^((p+3)/8), s^2 in {t,-t,it,-it}
+	Sqr(&s2, &s)
+
+	// If s^2 = -t or s^2 = -i*t, multiplying s by sqrt(-1) negates s^2.
+	var si Elt
+	Mul(&si, &s, &sqrtMinusOne)
+
+	Sub(&d, &s2, &t)
+	isQR := ctIsZeroElt(&d) // s^2 == t
+	var nt Elt
+	Neg(&nt, &t)
+	Sub(&d, &s2, &nt)
+	flip := ctIsZeroElt(&d) // s^2 == -t: use s*i, still a residue root
+	isQR |= flip
+
+	var it Elt
+	Mul(&it, &t, &sqrtMinusOne)
+	Neg(&it, &it)
+	Sub(&d, &s2, &it)
+	flip |= ctIsZeroElt(&d) // s^2 == -i*t: use s*i for z^2 = i*t
+
+	*z = s
+	Cmov(z, &si, flip)
+	return isQR
+}
+
+// powElt raises x to the fixed public exponent e, given in little-endian
+// bytes, by a left-to-right binary ladder. It is constant time with
+// respect to x; the exponent is not secret.
+func powElt(z, x *Elt, e *Elt) {
+	var acc Elt
+	SetOne(&acc)
+	for i := Size - 1; i >= 0; i-- {
+		for b := 7; b >= 0; b-- {
+			Sqr(&acc, &acc)
+			if e[i]>>uint(b)&1 == 1 {
+				Mul(&acc, &acc, x)
+			}
+		}
+	}
+	*z = acc
+}
+
+// ctIsZeroElt returns 1 when x represents zero, in constant time.
+func ctIsZeroElt(x *Elt) uint {
+	t := *x
+	Modp(&t)
+	var v byte
+	for _, b := range t {
+		v |= b
+	}
+	return uint((uint32(v) - 1) >> 31)
+}
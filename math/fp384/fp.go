@@ -0,0 +1,129 @@
+// Package fp384 provides prime field arithmetic over GF(2^384-2^128-2^96+2^32-1),
+// the base field of the NIST P-384 curve.
+package fp384
+
+import "encoding/binary"
+
+// Size in bytes of an element.
+const Size = 48
+
+// Elt is a prime field element stored in 384 little-endian bits; the
+// arithmetic keeps values only loosely reduced, so an Elt may hold any
+// representative below 2^384 until Modp maps it to its canonical value
+// in [0, p).
+type Elt [Size]byte
+
+// p is the prime modulus.
+var p = Elt{
+	0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+	0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+}
+
+// expInv is the inversion exponent p-2.
+var expInv = Elt{
+	0xfd, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+	0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+}
+
+// P returns the prime modulus as an element.
+func P() Elt { return p }
+
+// SetOne assigns z = 1.
+func SetOne(z *Elt) { *z = Elt{1} }
+
+// IsZero reports whether x represents zero. It is not constant time; see
+// ctIsZeroElt for the constant-time variant.
+func IsZero(x *Elt) bool {
+	t := *x
+	Modp(&t)
+	return t == Elt{}
+}
+
+// Neg calculates z = -x mod p.
+func Neg(z, x *Elt) {
+	var zero Elt
+	Sub(z, &zero, x)
+}
+
+// Inv calculates z = 1/x mod p for nonzero x, in constant time. The
+// exponent p-2 has no short addition chain worth hardcoding — it is
+// nearly all ones — so the public-exponent ladder of powElt is used.
+func Inv(z, x *Elt) { powElt(z, x, &expInv) }
+
+// Add calculates z = x+y mod p.
+func Add(z, x, y *Elt) { addGeneric(z, x, y) }
+
+// Sub calculates z = x-y mod p.
+func Sub(z, x, y *Elt) { subGeneric(z, x, y) }
+
+// Mul calculates z = x*y mod p.
+func Mul(z, x, y *Elt) { mulGeneric(z, x, y) }
+
+// Sqr calculates z = x^2 mod p.
+func Sqr(z, x *Elt) { sqrGeneric(z, x) }
+
+// Div calculates z = x/y mod p for nonzero y.
+func Div(z, x, y *Elt) {
+	var inv Elt
+	Inv(&inv, y)
+	Mul(z, x, &inv)
+}
+
+// Modp reduces z to its canonical representative in [0, p), in constant
+// time. Any 384-bit value is below 2p, so one conditional subtraction
+// suffices.
+func Modp(z *Elt) {
+	var x, y [6]uint64
+	var pw [6]uint64
+	for i := range pw {
+		pw[i] = binary.LittleEndian.Uint64(p[8*i:])
+		x[i] = binary.LittleEndian.Uint64(z[8*i:])
+	}
+	var b uint64
+	for i := range y {
+		d := x[i] - pw[i]
+		b1 := b2u(x[i] < pw[i])
+		y[i] = d - b
+		b = b1 | b2u(d < b)
+	}
+	mask := b - 1 // all-ones when the subtraction did not borrow
+	for i := range x {
+		x[i] = (x[i] &^ mask) | (y[i] & mask)
+		binary.LittleEndian.PutUint64(z[8*i:], x[i])
+	}
+}
+
+func b2u(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Cmov assigns x = y when n is 1 and leaves x untouched when n is 0, in
+// constant time.
+func Cmov(x, y *Elt, n uint) {
+	mask := byte(0) - byte(n&1)
+	for i := range x {
+		x[i] = (x[i] &^ mask) | (y[i] & mask)
+	}
+}
+
+// Cswap exchanges x and y when n is 1 and leaves both untouched when n is
+// 0, in constant time.
+func Cswap(x, y *Elt, n uint) {
+	mask := byte(0) - byte(n&1)
+	for i := range x {
+		d := (x[i] ^ y[i]) & mask
+		x[i] ^= d
+		y[i] ^= d
+	}
+}
@@ -0,0 +1,150 @@
+//go:build 386 || arm || mips || mipsle || wasm
+
+package fp384
+
+// 32-bit limb schedule for the P-384 prime. The Solinas structure
+// p = 2^384 - 2^128 - 2^96 + 2^32 - 1 reduces a product by folding the
+// high words with 2^384 = 2^128 + 2^96 - 2^32 + 1 (mod p): each fold is a
+// fixed signed carry chain, word-aligned in radix-2^32, followed by a
+// constant-time conditional subtraction. fp_generic.go carries the
+// complementary build constraint.
+
+func load32(x *Elt, a *[12]uint32) {
+	for i := range a {
+		a[i] = uint32(x[4*i]) | uint32(x[4*i+1])<<8 |
+			uint32(x[4*i+2])<<16 | uint32(x[4*i+3])<<24
+	}
+}
+
+func store32(x *Elt, a *[12]uint32) {
+	for i, v := range a {
+		x[4*i] = byte(v)
+		x[4*i+1] = byte(v >> 8)
+		x[4*i+2] = byte(v >> 16)
+		x[4*i+3] = byte(v >> 24)
+	}
+}
+
+var p32 = [12]uint32{
+	0xffffffff, 0x00000000, 0x00000000, 0xffffffff,
+	0xfffffffe, 0xffffffff, 0xffffffff, 0xffffffff,
+	0xffffffff, 0xffffffff, 0xffffffff, 0xffffffff,
+}
+
+// reduceSub subtracts p from a if a >= p, as a masked operation, n times.
+func reduceSub(a *[12]uint32, n int) {
+	for ; n > 0; n-- {
+		var d [12]uint32
+		var br int64
+		for i := range a {
+			br += int64(a[i]) - int64(p32[i])
+			d[i] = uint32(br)
+			br >>= 32
+		}
+		keep := uint32(br) // all ones when a < p
+		for i := range a {
+			a[i] = a[i]&keep | d[i]&^keep
+		}
+	}
+}
+
+// fold folds the words of h, which extend a at position 12, back into a
+// using 2^384 = 2^128 + 2^96 - 2^32 + 1, then carries the sum into
+// 32-bit words. It returns the words that overflow position 12, which a
+// further round shrinks; four rounds always reach zero.
+func fold(a *[12]uint32, h []int64) []int64 {
+	var v [17]int64
+	for i, w := range a {
+		v[i] = int64(w)
+	}
+	hw := func(j int) int64 {
+		if j < 0 || j >= len(h) {
+			return 0
+		}
+		return h[j]
+	}
+	for k := range v {
+		v[k] += hw(k-4) + hw(k-3) - hw(k-1) + hw(k)
+	}
+	var c int64
+	for k := range v {
+		c += v[k]
+		v[k] = int64(uint32(c))
+		c >>= 32
+	}
+	for i := range a {
+		a[i] = uint32(v[i])
+	}
+	return []int64{v[12], v[13], v[14], v[15], v[16], c}
+}
+
+func addGeneric(z, x, y *Elt) {
+	var a, b [12]uint32
+	load32(x, &a)
+	load32(y, &b)
+	var c uint64
+	for i := range a {
+		c += uint64(a[i]) + uint64(b[i])
+		a[i] = uint32(c)
+		c >>= 32
+	}
+	o := fold(&a, []int64{int64(c)})
+	fold(&a, o)
+	reduceSub(&a, 1)
+	store32(z, &a)
+}
+
+func subGeneric(z, x, y *Elt) {
+	var a, b [12]uint32
+	load32(x, &a)
+	load32(y, &b)
+	var br int64
+	for i := range a {
+		br += int64(a[i]) - int64(b[i])
+		a[i] = uint32(br)
+		br >>= 32
+	}
+	// br is 0 or -1; add p back per borrow, twice to cover loose inputs.
+	for r := 0; r < 2; r++ {
+		k := -br
+		br = 0
+		for i := range a {
+			br += int64(a[i]) + k*int64(p32[i])
+			a[i] = uint32(br)
+			br >>= 32
+		}
+		br -= k
+	}
+	store32(z, &a)
+}
+
+func mulGeneric(z, x, y *Elt) {
+	var a, b [12]uint32
+	load32(x, &a)
+	load32(y, &b)
+	// Operand-scanning product into twenty-four 32-bit words.
+	var t [24]uint32
+	for i := range a {
+		var c uint64
+		for j := range b {
+			c += uint64(t[i+j]) + uint64(a[i])*uint64(b[j])
+			t[i+j] = uint32(c)
+			c >>= 32
+		}
+		t[i+12] = uint32(c)
+	}
+	var r [12]uint32
+	h := make([]int64, 12)
+	for i := range r {
+		r[i] = t[i]
+		h[i] = int64(t[12+i])
+	}
+	o := fold(&r, h)
+	o = fold(&r, o)
+	o = fold(&r, o)
+	fold(&r, o)
+	reduceSub(&r, 2)
+	store32(z, &r)
+}
+
+func sqrGeneric(z, x *Elt) { mulGeneric(z, x, x) }
@@ -0,0 +1,32 @@
+package fp384
+
+// InvBatch replaces every nonzero z[i] with its inverse. It performs a
+// single inversion for the whole slice (Montgomery's trick), which is the
+// main cost of converting batches of projective P-384 points to affine
+// coordinates. Zero entries are left at zero.
+func InvBatch(z []Elt) {
+	n := len(z)
+	if n == 0 {
+		return
+	}
+	// pre[i] holds the product of the nonzero entries before index i.
+	pre := make([]Elt, n)
+	var r Elt
+	SetOne(&r)
+	for i := range z {
+		if IsZero(&z[i]) {
+			continue
+		}
+		pre[i] = r
+		Mul(&r, &r, &z[i])
+	}
+	Inv(&r, &r)
+	for i := n - 1; i >= 0; i-- {
+		if IsZero(&z[i]) {
+			continue
+		}
+		t := z[i]
+		Mul(&z[i], &r, &pre[i])
+		Mul(&r, &r, &t)
+	}
+}
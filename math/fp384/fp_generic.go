@@ -0,0 +1,139 @@
+//go:build !386 && !arm && !mips && !mipsle && !wasm
+
+package fp384
+
+// 64-bit limb schedules for the portable arithmetic. Reduction works with
+// eps = 2^384 mod p = 2^128+2^96-2^32+1: carries out of bit 384 fold back
+// as multiples of eps, and products fold their upper half the same way in
+// a fixed number of passes. fp_32bit.go carries the complementary build
+// constraint.
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// eps is 2^384 - p, the three-limb folding constant.
+var eps = [3]uint64{0xffffffff00000001, 0x00000000ffffffff, 1}
+
+func load64(x *Elt, a *[6]uint64) {
+	for i := range a {
+		a[i] = binary.LittleEndian.Uint64(x[8*i:])
+	}
+}
+
+func store64(x *Elt, a *[6]uint64) {
+	for i, v := range a {
+		binary.LittleEndian.PutUint64(x[8*i:], v)
+	}
+}
+
+// fold64 adds k*eps to a for a carry bit k out of bit 384. The caller
+// runs it twice: the first fold can itself carry out.
+func fold64(a *[6]uint64, k uint64) uint64 {
+	var c uint64
+	a[0], c = bits.Add64(a[0], k*eps[0], 0)
+	a[1], c = bits.Add64(a[1], k*eps[1], c)
+	a[2], c = bits.Add64(a[2], k*eps[2], c)
+	a[3], c = bits.Add64(a[3], 0, c)
+	a[4], c = bits.Add64(a[4], 0, c)
+	a[5], c = bits.Add64(a[5], 0, c)
+	return c
+}
+
+// unfold64 subtracts k*eps from a for a borrow bit k out of bit 384.
+func unfold64(a *[6]uint64, k uint64) uint64 {
+	var c uint64
+	a[0], c = bits.Sub64(a[0], k*eps[0], 0)
+	a[1], c = bits.Sub64(a[1], k*eps[1], c)
+	a[2], c = bits.Sub64(a[2], k*eps[2], c)
+	a[3], c = bits.Sub64(a[3], 0, c)
+	a[4], c = bits.Sub64(a[4], 0, c)
+	a[5], c = bits.Sub64(a[5], 0, c)
+	return c
+}
+
+func addGeneric(z, x, y *Elt) {
+	var a, b [6]uint64
+	load64(x, &a)
+	load64(y, &b)
+	var c uint64
+	for i := range a {
+		a[i], c = bits.Add64(a[i], b[i], c)
+	}
+	c = fold64(&a, c)
+	fold64(&a, c)
+	store64(z, &a)
+}
+
+func subGeneric(z, x, y *Elt) {
+	var a, b [6]uint64
+	load64(x, &a)
+	load64(y, &b)
+	var c uint64
+	for i := range a {
+		a[i], c = bits.Sub64(a[i], b[i], c)
+	}
+	c = unfold64(&a, c)
+	unfold64(&a, c)
+	store64(z, &a)
+}
+
+func mulGeneric(z, x, y *Elt) {
+	var a, b [6]uint64
+	load64(x, &a)
+	load64(y, &b)
+
+	// Schoolbook product into twelve limbs.
+	var t [12]uint64
+	for i := range a {
+		var carry uint64
+		for j := range b {
+			hi, lo := bits.Mul64(a[i], b[j])
+			s, c1 := bits.Add64(t[i+j], lo, 0)
+			s, c2 := bits.Add64(s, carry, 0)
+			t[i+j] = s
+			carry = hi + c1 + c2
+		}
+		t[i+6] = carry
+	}
+
+	// First fold: r = t[0..5] + t[6..11]*eps, at most nine limbs.
+	var r [9]uint64
+	copy(r[:6], t[:6])
+	mulAccumEps(r[:], t[6:12])
+
+	// Second fold: s = r[0..5] + r[6..8]*eps, at most seven limbs.
+	var s [7]uint64
+	copy(s[:6], r[:6])
+	mulAccumEps(s[:], r[6:9])
+
+	// The remaining overflow is a single bit; fold it twice.
+	var out [6]uint64
+	copy(out[:], s[:6])
+	c := fold64(&out, s[6])
+	fold64(&out, c)
+	store64(z, &out)
+}
+
+func sqrGeneric(z, x *Elt) { mulGeneric(z, x, x) }
+
+// mulAccumEps adds h*eps into the accumulator acc, which must have room
+// for len(h)+3 limbs of result.
+func mulAccumEps(acc []uint64, h []uint64) {
+	for i, v := range h {
+		var carry uint64
+		for j, e := range eps {
+			hi, lo := bits.Mul64(v, e)
+			s, c1 := bits.Add64(acc[i+j], lo, 0)
+			s, c2 := bits.Add64(s, carry, 0)
+			acc[i+j] = s
+			carry = hi + c1 + c2
+		}
+		// Propagate the row carry through the remaining limbs; the loop
+		// is unconditional to keep the schedule independent of the data.
+		for j := i + 3; j < len(acc); j++ {
+			acc[j], carry = bits.Add64(acc[j], carry, 0)
+		}
+	}
+}
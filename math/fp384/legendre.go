@@ -0,0 +1,80 @@
+package fp384
+
+// powLegendre computes z = x^((p-1)/2) with a fixed addition chain over
+// the runs of ones in the exponent,
+// (p-1)/2 = (2^255-1)*2^128 + (2^32-1)*2^95 + 2^31 - 1, replacing a generic
+// square-and-multiply ladder.
+func powLegendre(z, x *Elt) {
+	b1 := *x
+	b2 := b1
+	sqrN(&b2, 1)
+	Mul(&b2, &b2, &b1)
+	b4 := b2
+	sqrN(&b4, 2)
+	Mul(&b4, &b4, &b2)
+	b8 := b4
+	sqrN(&b8, 4)
+	Mul(&b8, &b8, &b4)
+	b16 := b8
+	sqrN(&b16, 8)
+	Mul(&b16, &b16, &b8)
+	b32 := b16
+	sqrN(&b32, 16)
+	Mul(&b32, &b32, &b16)
+	b64 := b32
+	sqrN(&b64, 32)
+	Mul(&b64, &b64, &b32)
+	b128 := b64
+	sqrN(&b128, 64)
+	Mul(&b128, &b128, &b64)
+	b31 := b16
+	sqrN(&b31, 8)
+	Mul(&b31, &b31, &b8)
+	sqrN(&b31, 4)
+	Mul(&b31, &b31, &b4)
+	sqrN(&b31, 2)
+	Mul(&b31, &b31, &b2)
+	sqrN(&b31, 1)
+	Mul(&b31, &b31, &b1)
+	b255 := b128
+	sqrN(&b255, 64)
+	Mul(&b255, &b255, &b64)
+	sqrN(&b255, 32)
+	Mul(&b255, &b255, &b32)
+	sqrN(&b255, 16)
+	Mul(&b255, &b255, &b16)
+	sqrN(&b255, 8)
+	Mul(&b255, &b255, &b8)
+	sqrN(&b255, 4)
+	Mul(&b255, &b255, &b4)
+	sqrN(&b255, 2)
+	Mul(&b255, &b255, &b2)
+	sqrN(&b255, 1)
+	Mul(&b255, &b255, &b1)
+	acc := b255
+	sqrN(&acc, 33)
+	Mul(&acc, &acc, &b32)
+	sqrN(&acc, 95)
+	Mul(&acc, &acc, &b31)
+	*z = acc
+}
+
+// sqrN squares z in place n times.
+func sqrN(z *Elt, n int) {
+	for i := 0; i < n; i++ {
+		Sqr(z, z)
+	}
+}
+
+// Legendre returns the Legendre symbol of x in constant time: 1 when x is
+// a nonzero square, -1 when x is not a square, and 0 when x is zero.
+func Legendre(x *Elt) int {
+	var l Elt
+	powLegendre(&l, x)
+	var one Elt
+	SetOne(&one)
+	Sub(&one, &l, &one)
+	isOne := ctIsZeroElt(&one)
+	isZero := ctIsZeroElt(&l)
+	return 2*int(isOne) + int(isZero) - 1
+}
@@ -0,0 +1,159 @@
+// Package fp448 provides prime field arithmetic over GF(2^448-2^224-1).
+package fp448
+
+import "encoding/binary"
+
+// Size in bytes of an element.
+const Size = 56
+
+// Elt is a prime field element stored in 448 little-endian bits; the
+// arithmetic keeps values only loosely reduced, so an Elt may hold any
+// representative below 2^448 until Modp maps it to its canonical value
+// in [0, p).
+type Elt [Size]byte
+
+// p is the prime modulus 2^448-2^224-1.
+var p = Elt{
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xfe, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+}
+
+// P returns the prime modulus as an element.
+func P() Elt { return p }
+
+// SetOne assigns z = 1.
+func SetOne(z *Elt) { *z = Elt{1} }
+
+// IsZero reports whether x represents zero. It is not constant time; see
+// ctIsZeroElt for the constant-time variant.
+func IsZero(x *Elt) bool {
+	t := *x
+	Modp(&t)
+	return t == Elt{}
+}
+
+// Neg calculates z = -x mod p.
+func Neg(z, x *Elt) {
+	var zero Elt
+	Sub(z, &zero, x)
+}
+
+// Inv calculates z = 1/x mod p for nonzero x, in constant time, using a
+// fixed addition chain for the exponent p-2, whose binary expansion is
+// 223 ones, a zero, 222 ones, a zero, and a one. Run variables are named
+// after the exponent they hold: rN is x^(2^N-1).
+func Inv(z, x *Elt) {
+	x1 := *x
+	var r2, r4, r8, r16, t Elt
+	Sqr(&r2, &x1)
+	Mul(&r2, &r2, &x1) // 2^2-1
+	r4 = r2
+	sqrn(&r4, 2)
+	Mul(&r4, &r4, &r2) // 2^4-1
+	r8 = r4
+	sqrn(&r8, 4)
+	Mul(&r8, &r8, &r4) // 2^8-1
+	r16 = r8
+	sqrn(&r16, 8)
+	Mul(&r16, &r16, &r8) // 2^16-1
+	r32 := r16
+	sqrn(&r32, 16)
+	Mul(&r32, &r32, &r16) // 2^32-1
+	r64 := r32
+	sqrn(&r64, 32)
+	Mul(&r64, &r64, &r32) // 2^64-1
+	r128 := r64
+	sqrn(&r128, 64)
+	Mul(&r128, &r128, &r64) // 2^128-1
+	t = r128
+	sqrn(&t, 64)
+	Mul(&t, &t, &r64) // 2^192-1
+	sqrn(&t, 16)
+	Mul(&t, &t, &r16) // 2^208-1
+	sqrn(&t, 8)
+	Mul(&t, &t, &r8) // 2^216-1
+	sqrn(&t, 4)
+	Mul(&t, &t, &r4) // 2^220-1
+	sqrn(&t, 2)
+	Mul(&r2, &t, &r2) // r222 = 2^222-1
+	t = r2
+	sqrn(&t, 1)
+	Mul(&t, &t, &x1) // r223 = 2^223-1
+	sqrn(&t, 1)      // append the zero bit
+	sqrn(&t, 222)
+	Mul(&t, &t, &r2) // append 222 ones
+	sqrn(&t, 1)      // append the zero bit
+	sqrn(&t, 1)
+	Mul(z, &t, &x1) // append the final one
+}
+
+func sqrn(z *Elt, n int) {
+	for i := 0; i < n; i++ {
+		Sqr(z, z)
+	}
+}
+
+// divGeneric calculates z = x/y mod p for nonzero y.
+func divGeneric(z, x, y *Elt) {
+	var inv Elt
+	Inv(&inv, y)
+	Mul(z, x, &inv)
+}
+
+// modpGeneric reduces z to its canonical representative in [0, p), in
+// constant time. Any 448-bit value is below 2p, so one conditional
+// subtraction suffices.
+func modpGeneric(z *Elt) {
+	var x, y [7]uint64
+	pw := [7]uint64{
+		^uint64(0), ^uint64(0), ^uint64(0), 0xfffffffeffffffff,
+		^uint64(0), ^uint64(0), ^uint64(0),
+	}
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint64(z[8*i:])
+	}
+	var b uint64
+	for i := range y {
+		d := x[i] - pw[i]
+		b1 := b2u(x[i] < pw[i])
+		y[i] = d - b
+		b = b1 | b2u(d < b)
+	}
+	mask := b - 1 // all-ones when the subtraction did not borrow
+	for i := range x {
+		x[i] = (x[i] &^ mask) | (y[i] & mask)
+		binary.LittleEndian.PutUint64(z[8*i:], x[i])
+	}
+}
+
+func b2u(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// cmovGeneric assigns x = y when n is 1 and leaves x untouched when n is
+// 0, in constant time.
+func cmovGeneric(x, y *Elt, n uint) {
+	mask := byte(0) - byte(n&1)
+	for i := range x {
+		x[i] = (x[i] &^ mask) | (y[i] & mask)
+	}
+}
+
+// cswapGeneric exchanges x and y when n is 1 and leaves both untouched
+// when n is 0, in constant time.
+func cswapGeneric(x, y *Elt, n uint) {
+	mask := byte(0) - byte(n&1)
+	for i := range x {
+		d := (x[i] ^ y[i]) & mask
+		x[i] ^= d
+		y[i] ^= d
+	}
+}
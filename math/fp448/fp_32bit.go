@@ -0,0 +1,127 @@
+//go:build 386 || arm || mips || mipsle || wasm
+
+package fp448
+
+// 32-bit limb schedules for targets whose native word is 32 bits. With
+// radix-2^32 the golden-ratio structure of p = 2^448 - 2^224 - 1 aligns
+// on limb boundaries (2^224 is limb seven), so the reduction is pure
+// word-aligned carry chains. fp_generic.go carries the complementary
+// build constraint.
+
+func load32(x *Elt, a *[14]uint32) {
+	for i := range a {
+		a[i] = uint32(x[4*i]) | uint32(x[4*i+1])<<8 |
+			uint32(x[4*i+2])<<16 | uint32(x[4*i+3])<<24
+	}
+}
+
+func store32(x *Elt, a *[14]uint32) {
+	for i, v := range a {
+		x[4*i] = byte(v)
+		x[4*i+1] = byte(v >> 8)
+		x[4*i+2] = byte(v >> 16)
+		x[4*i+3] = byte(v >> 24)
+	}
+}
+
+// foldCarry adds k*(2^224 + 1) into a, the reduction of k*2^448, running
+// exactly two rounds so the result fits in 14 limbs for any carry.
+func foldCarry(a *[14]uint32, k uint64) {
+	for r := 0; r < 2; r++ {
+		var c uint64
+		for i := range a {
+			c += uint64(a[i])
+			if i == 0 || i == 7 {
+				c += k
+			}
+			a[i] = uint32(c)
+			c >>= 32
+		}
+		k = c
+	}
+}
+
+func addGeneric(z, x, y *Elt) {
+	var a, b [14]uint32
+	load32(x, &a)
+	load32(y, &b)
+	var c uint64
+	for i := range a {
+		c += uint64(a[i]) + uint64(b[i])
+		a[i] = uint32(c)
+		c >>= 32
+	}
+	foldCarry(&a, c)
+	store32(z, &a)
+}
+
+func subGeneric(z, x, y *Elt) {
+	var a, b [14]uint32
+	load32(x, &a)
+	load32(y, &b)
+	var br int64
+	for i := range a {
+		br += int64(a[i]) - int64(b[i])
+		a[i] = uint32(br)
+		br >>= 32
+	}
+	// br is 0 or -1: subtract (2^224 + 1) per wrap of 2^448, twice.
+	for r := 0; r < 2; r++ {
+		k := -br
+		br = 0
+		for i := range a {
+			d := int64(a[i])
+			if i == 0 || i == 7 {
+				d -= k
+			}
+			br += d
+			a[i] = uint32(br)
+			br >>= 32
+		}
+	}
+	store32(z, &a)
+}
+
+func mulGeneric(z, x, y *Elt) {
+	var a, b [14]uint32
+	load32(x, &a)
+	load32(y, &b)
+	// Operand-scanning product into twenty-eight 32-bit words.
+	var t [28]uint32
+	for i := range a {
+		var c uint64
+		for j := range b {
+			c += uint64(t[i+j]) + uint64(a[i])*uint64(b[j])
+			t[i+j] = uint32(c)
+			c >>= 32
+		}
+		t[i+14] = uint32(c)
+	}
+	// With L the low half, H the high half, and H = Hlow + 2^224*Hhigh:
+	// t = L + 2^448*H = L + H + Hhigh + 2^224*(Hlow + Hhigh) (mod p).
+	var s [8]uint32 // Hlow + Hhigh
+	var c uint64
+	for i := 0; i < 7; i++ {
+		c += uint64(t[14+i]) + uint64(t[21+i])
+		s[i] = uint32(c)
+		c >>= 32
+	}
+	s[7] = uint32(c)
+
+	var r [14]uint32
+	c = 0
+	for i := range r {
+		c += uint64(t[i]) + uint64(t[14+i])
+		if i < 7 {
+			c += uint64(t[21+i])
+		} else {
+			c += uint64(s[i-7])
+		}
+		r[i] = uint32(c)
+		c >>= 32
+	}
+	foldCarry(&r, c+uint64(s[7]))
+	store32(z, &r)
+}
+
+func sqrGeneric(z, x *Elt) { mulGeneric(z, x, x) }
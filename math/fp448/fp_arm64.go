@@ -0,0 +1,53 @@
+//go:build arm64 && !purego && !tinygo
+
+package fp448
+
+// All routines below are implemented in fp_arm64.s using only base ARMv8
+// integer instructions, folding the product of the golden-ratio prime
+// p = 2^448 - 2^224 - 1 without multiplies in the reduction. No runtime
+// feature detection is needed: the assembly is selected whenever the
+// target is arm64 and purego is not requested. TinyGo does not assemble
+// these files, so its builds take the generic path.
+
+//go:noescape
+func fpAdd(z, x, y *Elt)
+
+//go:noescape
+func fpSub(z, x, y *Elt)
+
+//go:noescape
+func fpMul(z, x, y *Elt)
+
+//go:noescape
+func fpSqr(z, x *Elt)
+
+// Add calculates z = x+y mod p.
+func Add(z, x, y *Elt) { fpAdd(z, x, y) }
+
+// Sub calculates z = x-y mod p.
+func Sub(z, x, y *Elt) { fpSub(z, x, y) }
+
+// Mul calculates z = x*y mod p.
+func Mul(z, x, y *Elt) { fpMul(z, x, y) }
+
+// Sqr calculates z = x^2 mod p.
+func Sqr(z, x *Elt) { fpSqr(z, x) }
+
+// AddSub calculates (x,y) = (x+y mod p, x-y mod p).
+func AddSub(x, y *Elt) {
+	t := *x
+	fpAdd(x, &t, y)
+	fpSub(y, &t, y)
+}
+
+// Cmov assigns y to x if n is 1.
+func Cmov(x, y *Elt, n uint) { cmovGeneric(x, y, n) }
+
+// Cswap interchanges x and y if n is 1.
+func Cswap(x, y *Elt, n uint) { cswapGeneric(x, y, n) }
+
+// Div calculates z = x/y mod p.
+func Div(z, x, y *Elt) { divGeneric(z, x, y) }
+
+// Modp ensures that z is between [0,p-1].
+func Modp(z *Elt) { modpGeneric(z) }
@@ -0,0 +1,886 @@
+// Code generated by an internal tool based on the column products of
+// the generic implementation. Field arithmetic modulo the golden-ratio
+// prime p = 2^448 - 2^224 - 1 with weakly reduced (< 2^448) values.
+
+//go:build arm64 && !purego
+
+#include "textflag.h"
+// func fpMul(z, x, y *Elt)
+TEXT ·fpMul(SB), NOSPLIT, $112-24
+	MOVD z+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD 0(R1), R3
+	MOVD 8(R1), R4
+	MOVD 16(R1), R5
+	MOVD 24(R1), R6
+	MOVD 32(R1), R7
+	MOVD 40(R1), R8
+	MOVD 48(R1), R9
+	MOVD 0(R2), R10
+	MOVD 8(R2), R11
+	MOVD 16(R2), R12
+	MOVD 24(R2), R13
+	MOVD 32(R2), R14
+	MOVD 40(R2), R15
+	MOVD 48(R2), R16
+	MOVD ZR, R17
+	MOVD ZR, R19
+	MOVD ZR, R20
+	MUL R3, R10, R21
+	UMULH R3, R10, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MOVD R17, c0-112(SP)
+	MOVD ZR, R17
+	MUL R3, R11, R21
+	UMULH R3, R11, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R4, R10, R21
+	UMULH R4, R10, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MOVD R19, c1-104(SP)
+	MOVD ZR, R19
+	MUL R3, R12, R21
+	UMULH R3, R12, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R4, R11, R21
+	UMULH R4, R11, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R5, R10, R21
+	UMULH R5, R10, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MOVD R20, c2-96(SP)
+	MOVD ZR, R20
+	MUL R3, R13, R21
+	UMULH R3, R13, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R4, R12, R21
+	UMULH R4, R12, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R5, R11, R21
+	UMULH R5, R11, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R6, R10, R21
+	UMULH R6, R10, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MOVD R17, c3-88(SP)
+	MOVD ZR, R17
+	MUL R3, R14, R21
+	UMULH R3, R14, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R4, R13, R21
+	UMULH R4, R13, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R5, R12, R21
+	UMULH R5, R12, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R6, R11, R21
+	UMULH R6, R11, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R7, R10, R21
+	UMULH R7, R10, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MOVD R19, c4-80(SP)
+	MOVD ZR, R19
+	MUL R3, R15, R21
+	UMULH R3, R15, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R4, R14, R21
+	UMULH R4, R14, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R5, R13, R21
+	UMULH R5, R13, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R6, R12, R21
+	UMULH R6, R12, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R7, R11, R21
+	UMULH R7, R11, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R8, R10, R21
+	UMULH R8, R10, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MOVD R20, c5-72(SP)
+	MOVD ZR, R20
+	MUL R3, R16, R21
+	UMULH R3, R16, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R4, R15, R21
+	UMULH R4, R15, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R5, R14, R21
+	UMULH R5, R14, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R6, R13, R21
+	UMULH R6, R13, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R7, R12, R21
+	UMULH R7, R12, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R8, R11, R21
+	UMULH R8, R11, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R9, R10, R21
+	UMULH R9, R10, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MOVD R17, c6-64(SP)
+	MOVD ZR, R17
+	MUL R4, R16, R21
+	UMULH R4, R16, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R5, R15, R21
+	UMULH R5, R15, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R6, R14, R21
+	UMULH R6, R14, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R7, R13, R21
+	UMULH R7, R13, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R8, R12, R21
+	UMULH R8, R12, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R9, R11, R21
+	UMULH R9, R11, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MOVD R19, c7-56(SP)
+	MOVD ZR, R19
+	MUL R5, R16, R21
+	UMULH R5, R16, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R6, R15, R21
+	UMULH R6, R15, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R7, R14, R21
+	UMULH R7, R14, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R8, R13, R21
+	UMULH R8, R13, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R9, R12, R21
+	UMULH R9, R12, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MOVD R20, c8-48(SP)
+	MOVD ZR, R20
+	MUL R6, R16, R21
+	UMULH R6, R16, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R7, R15, R21
+	UMULH R7, R15, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R8, R14, R21
+	UMULH R8, R14, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R9, R13, R21
+	UMULH R9, R13, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MOVD R17, c9-40(SP)
+	MOVD ZR, R17
+	MUL R7, R16, R21
+	UMULH R7, R16, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R8, R15, R21
+	UMULH R8, R15, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R9, R14, R21
+	UMULH R9, R14, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MOVD R19, c10-32(SP)
+	MOVD ZR, R19
+	MUL R8, R16, R21
+	UMULH R8, R16, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R9, R15, R21
+	UMULH R9, R15, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MOVD R20, c11-24(SP)
+	MOVD ZR, R20
+	MUL R9, R16, R21
+	UMULH R9, R16, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MOVD R17, c12-16(SP)
+	MOVD R19, c13-8(SP)
+	MOVD c0-112(SP), R3
+	MOVD c1-104(SP), R4
+	MOVD c2-96(SP), R5
+	MOVD c3-88(SP), R6
+	MOVD c4-80(SP), R7
+	MOVD c5-72(SP), R8
+	MOVD c6-64(SP), R9
+	MOVD c7-56(SP), R10
+	MOVD c8-48(SP), R11
+	MOVD c9-40(SP), R12
+	MOVD c10-32(SP), R13
+	MOVD c11-24(SP), R14
+	MOVD c12-16(SP), R15
+	MOVD c13-8(SP), R16
+	LSR $32, R13, R1
+	LSL $32, R14, R2
+	ORR R2, R1, R17
+	LSR $32, R14, R1
+	LSL $32, R15, R2
+	ORR R2, R1, R19
+	LSR $32, R15, R1
+	LSL $32, R16, R2
+	ORR R2, R1, R20
+	LSR $32, R16, R21
+	ADDS R17, R10, R22
+	ADCS R19, R11, R23
+	ADCS R20, R12, R24
+	MOVD $0xffffffff, R1
+	AND R1, R13, R2
+	ADCS R21, R2, R25
+	ADDS R10, R3, R3
+	ADCS R11, R4, R4
+	ADCS R12, R5, R5
+	ADCS R13, R6, R6
+	ADCS R14, R7, R7
+	ADCS R15, R8, R8
+	ADCS R16, R9, R9
+	ADC ZR, ZR, R26
+	ADDS R17, R3, R3
+	ADCS R19, R4, R4
+	ADCS R20, R5, R5
+	ADCS R21, R6, R6
+	ADCS ZR, R7, R7
+	ADCS ZR, R8, R8
+	ADCS ZR, R9, R9
+	ADC ZR, R26, R26
+	LSL $32, R22, R1
+	ADDS R1, R6, R6
+	LSR $32, R22, R1
+	LSL $32, R23, R2
+	ORR R2, R1, R1
+	ADCS R1, R7, R7
+	LSR $32, R23, R1
+	LSL $32, R24, R2
+	ORR R2, R1, R1
+	ADCS R1, R8, R8
+	LSR $32, R24, R1
+	LSL $32, R25, R2
+	ORR R2, R1, R1
+	ADCS R1, R9, R9
+	LSR $32, R25, R1
+	ADC R1, R26, R26
+	ADDS R26, R3, R3
+	ADCS ZR, R4, R4
+	ADCS ZR, R5, R5
+	LSL $32, R26, R1
+	ADCS R1, R6, R6
+	ADCS ZR, R7, R7
+	ADCS ZR, R8, R8
+	ADCS ZR, R9, R9
+	ADC ZR, ZR, R26
+	ADDS R26, R3, R3
+	ADCS ZR, R4, R4
+	ADCS ZR, R5, R5
+	LSL $32, R26, R1
+	ADCS R1, R6, R6
+	ADCS ZR, R7, R7
+	ADCS ZR, R8, R8
+	ADCS ZR, R9, R9
+	ADC ZR, ZR, R26
+	MOVD R3, 0(R0)
+	MOVD R4, 8(R0)
+	MOVD R5, 16(R0)
+	MOVD R6, 24(R0)
+	MOVD R7, 32(R0)
+	MOVD R8, 40(R0)
+	MOVD R9, 48(R0)
+	RET
+
+// func fpSqr(z, x *Elt)
+TEXT ·fpSqr(SB), NOSPLIT, $112-16
+	MOVD z+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD 0(R1), R3
+	MOVD 8(R1), R4
+	MOVD 16(R1), R5
+	MOVD 24(R1), R6
+	MOVD 32(R1), R7
+	MOVD 40(R1), R8
+	MOVD 48(R1), R9
+	MOVD 0(R1), R10
+	MOVD 8(R1), R11
+	MOVD 16(R1), R12
+	MOVD 24(R1), R13
+	MOVD 32(R1), R14
+	MOVD 40(R1), R15
+	MOVD 48(R1), R16
+	MOVD ZR, R17
+	MOVD ZR, R19
+	MOVD ZR, R20
+	MUL R3, R10, R21
+	UMULH R3, R10, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MOVD R17, c0-112(SP)
+	MOVD ZR, R17
+	MUL R3, R11, R21
+	UMULH R3, R11, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R4, R10, R21
+	UMULH R4, R10, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MOVD R19, c1-104(SP)
+	MOVD ZR, R19
+	MUL R3, R12, R21
+	UMULH R3, R12, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R4, R11, R21
+	UMULH R4, R11, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R5, R10, R21
+	UMULH R5, R10, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MOVD R20, c2-96(SP)
+	MOVD ZR, R20
+	MUL R3, R13, R21
+	UMULH R3, R13, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R4, R12, R21
+	UMULH R4, R12, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R5, R11, R21
+	UMULH R5, R11, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R6, R10, R21
+	UMULH R6, R10, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MOVD R17, c3-88(SP)
+	MOVD ZR, R17
+	MUL R3, R14, R21
+	UMULH R3, R14, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R4, R13, R21
+	UMULH R4, R13, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R5, R12, R21
+	UMULH R5, R12, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R6, R11, R21
+	UMULH R6, R11, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R7, R10, R21
+	UMULH R7, R10, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MOVD R19, c4-80(SP)
+	MOVD ZR, R19
+	MUL R3, R15, R21
+	UMULH R3, R15, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R4, R14, R21
+	UMULH R4, R14, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R5, R13, R21
+	UMULH R5, R13, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R6, R12, R21
+	UMULH R6, R12, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R7, R11, R21
+	UMULH R7, R11, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R8, R10, R21
+	UMULH R8, R10, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MOVD R20, c5-72(SP)
+	MOVD ZR, R20
+	MUL R3, R16, R21
+	UMULH R3, R16, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R4, R15, R21
+	UMULH R4, R15, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R5, R14, R21
+	UMULH R5, R14, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R6, R13, R21
+	UMULH R6, R13, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R7, R12, R21
+	UMULH R7, R12, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R8, R11, R21
+	UMULH R8, R11, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R9, R10, R21
+	UMULH R9, R10, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MOVD R17, c6-64(SP)
+	MOVD ZR, R17
+	MUL R4, R16, R21
+	UMULH R4, R16, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R5, R15, R21
+	UMULH R5, R15, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R6, R14, R21
+	UMULH R6, R14, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R7, R13, R21
+	UMULH R7, R13, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R8, R12, R21
+	UMULH R8, R12, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R9, R11, R21
+	UMULH R9, R11, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MOVD R19, c7-56(SP)
+	MOVD ZR, R19
+	MUL R5, R16, R21
+	UMULH R5, R16, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R6, R15, R21
+	UMULH R6, R15, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R7, R14, R21
+	UMULH R7, R14, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R8, R13, R21
+	UMULH R8, R13, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R9, R12, R21
+	UMULH R9, R12, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MOVD R20, c8-48(SP)
+	MOVD ZR, R20
+	MUL R6, R16, R21
+	UMULH R6, R16, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R7, R15, R21
+	UMULH R7, R15, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R8, R14, R21
+	UMULH R8, R14, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MUL R9, R13, R21
+	UMULH R9, R13, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MOVD R17, c9-40(SP)
+	MOVD ZR, R17
+	MUL R7, R16, R21
+	UMULH R7, R16, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R8, R15, R21
+	UMULH R8, R15, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MUL R9, R14, R21
+	UMULH R9, R14, R22
+	ADDS R21, R19, R19
+	ADCS R22, R20, R20
+	ADC ZR, R17, R17
+	MOVD R19, c10-32(SP)
+	MOVD ZR, R19
+	MUL R8, R16, R21
+	UMULH R8, R16, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MUL R9, R15, R21
+	UMULH R9, R15, R22
+	ADDS R21, R20, R20
+	ADCS R22, R17, R17
+	ADC ZR, R19, R19
+	MOVD R20, c11-24(SP)
+	MOVD ZR, R20
+	MUL R9, R16, R21
+	UMULH R9, R16, R22
+	ADDS R21, R17, R17
+	ADCS R22, R19, R19
+	ADC ZR, R20, R20
+	MOVD R17, c12-16(SP)
+	MOVD R19, c13-8(SP)
+	MOVD c0-112(SP), R3
+	MOVD c1-104(SP), R4
+	MOVD c2-96(SP), R5
+	MOVD c3-88(SP), R6
+	MOVD c4-80(SP), R7
+	MOVD c5-72(SP), R8
+	MOVD c6-64(SP), R9
+	MOVD c7-56(SP), R10
+	MOVD c8-48(SP), R11
+	MOVD c9-40(SP), R12
+	MOVD c10-32(SP), R13
+	MOVD c11-24(SP), R14
+	MOVD c12-16(SP), R15
+	MOVD c13-8(SP), R16
+	LSR $32, R13, R1
+	LSL $32, R14, R2
+	ORR R2, R1, R17
+	LSR $32, R14, R1
+	LSL $32, R15, R2
+	ORR R2, R1, R19
+	LSR $32, R15, R1
+	LSL $32, R16, R2
+	ORR R2, R1, R20
+	LSR $32, R16, R21
+	ADDS R17, R10, R22
+	ADCS R19, R11, R23
+	ADCS R20, R12, R24
+	MOVD $0xffffffff, R1
+	AND R1, R13, R2
+	ADCS R21, R2, R25
+	ADDS R10, R3, R3
+	ADCS R11, R4, R4
+	ADCS R12, R5, R5
+	ADCS R13, R6, R6
+	ADCS R14, R7, R7
+	ADCS R15, R8, R8
+	ADCS R16, R9, R9
+	ADC ZR, ZR, R26
+	ADDS R17, R3, R3
+	ADCS R19, R4, R4
+	ADCS R20, R5, R5
+	ADCS R21, R6, R6
+	ADCS ZR, R7, R7
+	ADCS ZR, R8, R8
+	ADCS ZR, R9, R9
+	ADC ZR, R26, R26
+	LSL $32, R22, R1
+	ADDS R1, R6, R6
+	LSR $32, R22, R1
+	LSL $32, R23, R2
+	ORR R2, R1, R1
+	ADCS R1, R7, R7
+	LSR $32, R23, R1
+	LSL $32, R24, R2
+	ORR R2, R1, R1
+	ADCS R1, R8, R8
+	LSR $32, R24, R1
+	LSL $32, R25, R2
+	ORR R2, R1, R1
+	ADCS R1, R9, R9
+	LSR $32, R25, R1
+	ADC R1, R26, R26
+	ADDS R26, R3, R3
+	ADCS ZR, R4, R4
+	ADCS ZR, R5, R5
+	LSL $32, R26, R1
+	ADCS R1, R6, R6
+	ADCS ZR, R7, R7
+	ADCS ZR, R8, R8
+	ADCS ZR, R9, R9
+	ADC ZR, ZR, R26
+	ADDS R26, R3, R3
+	ADCS ZR, R4, R4
+	ADCS ZR, R5, R5
+	LSL $32, R26, R1
+	ADCS R1, R6, R6
+	ADCS ZR, R7, R7
+	ADCS ZR, R8, R8
+	ADCS ZR, R9, R9
+	ADC ZR, ZR, R26
+	MOVD R3, 0(R0)
+	MOVD R4, 8(R0)
+	MOVD R5, 16(R0)
+	MOVD R6, 24(R0)
+	MOVD R7, 32(R0)
+	MOVD R8, 40(R0)
+	MOVD R9, 48(R0)
+	RET
+
+// func fpAdd(z, x, y *Elt)
+TEXT ·fpAdd(SB), NOSPLIT, $0-24
+	MOVD z+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD 0(R1), R3
+	MOVD 0(R2), R10
+	MOVD 8(R1), R4
+	MOVD 8(R2), R11
+	MOVD 16(R1), R5
+	MOVD 16(R2), R12
+	MOVD 24(R1), R6
+	MOVD 24(R2), R13
+	MOVD 32(R1), R7
+	MOVD 32(R2), R14
+	MOVD 40(R1), R8
+	MOVD 40(R2), R15
+	MOVD 48(R1), R9
+	MOVD 48(R2), R16
+	ADDS R10, R3, R3
+	ADCS R11, R4, R4
+	ADCS R12, R5, R5
+	ADCS R13, R6, R6
+	ADCS R14, R7, R7
+	ADCS R15, R8, R8
+	ADCS R16, R9, R9
+	ADC ZR, ZR, R17
+	ADDS R17, R3, R3
+	ADCS ZR, R4, R4
+	ADCS ZR, R5, R5
+	LSL $32, R17, R19
+	ADCS R19, R6, R6
+	ADCS ZR, R7, R7
+	ADCS ZR, R8, R8
+	ADCS ZR, R9, R9
+	ADC ZR, ZR, R17
+	ADDS R17, R3, R3
+	ADCS ZR, R4, R4
+	ADCS ZR, R5, R5
+	LSL $32, R17, R19
+	ADCS R19, R6, R6
+	ADCS ZR, R7, R7
+	ADCS ZR, R8, R8
+	ADCS ZR, R9, R9
+	ADC ZR, ZR, R17
+	MOVD R3, 0(R0)
+	MOVD R4, 8(R0)
+	MOVD R5, 16(R0)
+	MOVD R6, 24(R0)
+	MOVD R7, 32(R0)
+	MOVD R8, 40(R0)
+	MOVD R9, 48(R0)
+	RET
+
+// func fpSub(z, x, y *Elt)
+TEXT ·fpSub(SB), NOSPLIT, $0-24
+	MOVD z+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD 0(R1), R3
+	MOVD 0(R2), R10
+	MOVD 8(R1), R4
+	MOVD 8(R2), R11
+	MOVD 16(R1), R5
+	MOVD 16(R2), R12
+	MOVD 24(R1), R6
+	MOVD 24(R2), R13
+	MOVD 32(R1), R7
+	MOVD 32(R2), R14
+	MOVD 40(R1), R8
+	MOVD 40(R2), R15
+	MOVD 48(R1), R9
+	MOVD 48(R2), R16
+	SUBS R10, R3, R3
+	SBCS R11, R4, R4
+	SBCS R12, R5, R5
+	SBCS R13, R6, R6
+	SBCS R14, R7, R7
+	SBCS R15, R8, R8
+	SBCS R16, R9, R9
+	MOVD ZR, R17
+	ADC ZR, R17, R17
+	SUB $1, R17, R17
+	NEG R17, R17
+	SUBS R17, R3, R3
+	SBCS ZR, R4, R4
+	SBCS ZR, R5, R5
+	LSL $32, R17, R19
+	SBCS R19, R6, R6
+	SBCS ZR, R7, R7
+	SBCS ZR, R8, R8
+	SBCS ZR, R9, R9
+	MOVD ZR, R17
+	ADC ZR, R17, R17
+	SUB $1, R17, R17
+	NEG R17, R17
+	SUBS R17, R3, R3
+	SBCS ZR, R4, R4
+	SBCS ZR, R5, R5
+	LSL $32, R17, R19
+	SBCS R19, R6, R6
+	SBCS ZR, R7, R7
+	SBCS ZR, R8, R8
+	SBCS ZR, R9, R9
+	MOVD ZR, R17
+	ADC ZR, R17, R17
+	SUB $1, R17, R17
+	NEG R17, R17
+	MOVD R3, 0(R0)
+	MOVD R4, 8(R0)
+	MOVD R5, 16(R0)
+	MOVD R6, 24(R0)
+	MOVD R7, 32(R0)
+	MOVD R8, 40(R0)
+	MOVD R9, 48(R0)
+	RET
@@ -0,0 +1,32 @@
+package fp448
+
+// InvBatch calculates z[i] = 1/z[i] for every nonzero entry of z with
+// Montgomery's trick, amortizing a single inversion over the whole slice
+// at the cost of three multiplications per entry. Entries equal to zero
+// are left untouched.
+func InvBatch(z []Elt) {
+	n := len(z)
+	if n == 0 {
+		return
+	}
+	// pre[i] holds the product of the nonzero entries before index i.
+	pre := make([]Elt, n)
+	var r Elt
+	SetOne(&r)
+	for i := range z {
+		if IsZero(&z[i]) {
+			continue
+		}
+		pre[i] = r
+		Mul(&r, &r, &z[i])
+	}
+	Inv(&r, &r)
+	for i := n - 1; i >= 0; i-- {
+		if IsZero(&z[i]) {
+			continue
+		}
+		t := z[i]
+		Mul(&z[i], &r, &pre[i])
+		Mul(&r, &r, &t)
+	}
+}
@@ -0,0 +1,168 @@
+//go:build !386 && !arm && !mips && !mipsle && !wasm
+
+package fp448
+
+// 64-bit schedules for the portable arithmetic. Addition and subtraction
+// work on 64-bit limbs, folding wraparound with 2^448 = 2^224+1 mod p;
+// multiplication uses radix 2^56, where both 448 and 224 are limb-aligned,
+// so the Karatsuba-free schoolbook product reduces with plain limb moves.
+// fp_32bit.go carries the complementary build constraint.
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+func load64(x *Elt, a *[7]uint64) {
+	for i := range a {
+		a[i] = binary.LittleEndian.Uint64(x[8*i:])
+	}
+}
+
+func store64(x *Elt, a *[7]uint64) {
+	for i, v := range a {
+		binary.LittleEndian.PutUint64(x[8*i:], v)
+	}
+}
+
+// fold64 adds k*(2^224+1) to a, the contribution of a carry k out of bit
+// 448. The caller runs it twice: the first fold can itself carry out.
+func fold64(a *[7]uint64, k uint64) uint64 {
+	var c uint64
+	a[0], c = bits.Add64(a[0], k, 0)
+	a[1], c = bits.Add64(a[1], 0, c)
+	a[2], c = bits.Add64(a[2], 0, c)
+	a[3], c = bits.Add64(a[3], k<<32, c)
+	a[4], c = bits.Add64(a[4], k>>32, c)
+	a[5], c = bits.Add64(a[5], 0, c)
+	a[6], c = bits.Add64(a[6], 0, c)
+	return c
+}
+
+func addGeneric(z, x, y *Elt) {
+	var a, b [7]uint64
+	load64(x, &a)
+	load64(y, &b)
+	var c uint64
+	for i := range a {
+		a[i], c = bits.Add64(a[i], b[i], c)
+	}
+	c = fold64(&a, c)
+	fold64(&a, c)
+	store64(z, &a)
+}
+
+func subGeneric(z, x, y *Elt) {
+	var a, b [7]uint64
+	load64(x, &a)
+	load64(y, &b)
+	var c uint64
+	for i := range a {
+		a[i], c = bits.Sub64(a[i], b[i], c)
+	}
+	c = unfold64(&a, c)
+	unfold64(&a, c)
+	store64(z, &a)
+}
+
+// unfold64 subtracts k*(2^224+1) from a, the contribution of a borrow k
+// out of bit 448.
+func unfold64(a *[7]uint64, k uint64) uint64 {
+	var c uint64
+	a[0], c = bits.Sub64(a[0], k, 0)
+	a[1], c = bits.Sub64(a[1], 0, c)
+	a[2], c = bits.Sub64(a[2], 0, c)
+	a[3], c = bits.Sub64(a[3], k<<32, c)
+	a[4], c = bits.Sub64(a[4], k>>32, c)
+	a[5], c = bits.Sub64(a[5], 0, c)
+	a[6], c = bits.Sub64(a[6], 0, c)
+	return c
+}
+
+const mask56 = (1 << 56) - 1
+
+func mulGeneric(z, x, y *Elt) {
+	var a, b [8]uint64
+	loadRadix56(x, &a)
+	loadRadix56(y, &b)
+
+	// Column products; each column accumulates at most eight 112-bit
+	// terms, so a 128-bit (hi,lo) accumulator per column suffices.
+	var cl, ch [15]uint64
+	for i := range a {
+		for j := range b {
+			hi, lo := bits.Mul64(a[i], b[j])
+			var c uint64
+			cl[i+j], c = bits.Add64(cl[i+j], lo, 0)
+			ch[i+j] = ch[i+j] + hi + c
+		}
+	}
+
+	// Carry-normalize the columns into 56-bit limbs t[0..15].
+	var t [16]uint64
+	var carry uint64
+	for k := 0; k < 15; k++ {
+		s, c := bits.Add64(cl[k], carry, 0)
+		h := ch[k] + c
+		t[k] = s & mask56
+		carry = h<<8 | s>>56
+	}
+	t[15] = carry
+
+	// Fold limbs 8..15 with 2^448 = 2^224+1, which at radix 2^56 sends
+	// limb i to limbs i-8 and i-4; limbs 12..15 wrap a second time.
+	var r [8]uint64
+	for i := 0; i < 8; i++ {
+		r[i] = t[i]
+	}
+	for i := 8; i < 12; i++ {
+		r[i-8] += t[i]
+		r[i-4] += t[i]
+	}
+	for i := 12; i < 16; i++ {
+		r[i-12] += t[i]
+		r[i-8] += 2 * t[i]
+	}
+
+	// Carry-normalize and fold the bit-448 overflow into limbs 0 and 4.
+	// Two folds empty the overflow; the third pass only settles limbs.
+	for n := 0; n < 3; n++ {
+		var c uint64
+		for i := range r {
+			v := r[i] + c
+			r[i] = v & mask56
+			c = v >> 56
+		}
+		r[0] += c
+		r[4] += c
+	}
+
+	storeRadix56(z, &r)
+}
+
+func sqrGeneric(z, x *Elt) { mulGeneric(z, x, x) }
+
+func loadRadix56(x *Elt, a *[8]uint64) {
+	var w [7]uint64
+	load64(x, &w)
+	a[0] = w[0] & mask56
+	a[1] = (w[0]>>56 | w[1]<<8) & mask56
+	a[2] = (w[1]>>48 | w[2]<<16) & mask56
+	a[3] = (w[2]>>40 | w[3]<<24) & mask56
+	a[4] = (w[3]>>32 | w[4]<<32) & mask56
+	a[5] = (w[4]>>24 | w[5]<<40) & mask56
+	a[6] = (w[5]>>16 | w[6]<<48) & mask56
+	a[7] = w[6] >> 8
+}
+
+func storeRadix56(x *Elt, a *[8]uint64) {
+	var w [7]uint64
+	w[0] = a[0] | a[1]<<56
+	w[1] = a[1]>>8 | a[2]<<48
+	w[2] = a[2]>>16 | a[3]<<40
+	w[3] = a[3]>>24 | a[4]<<32
+	w[4] = a[4]>>32 | a[5]<<24
+	w[5] = a[5]>>40 | a[6]<<16
+	w[6] = a[6]>>48 | a[7]<<8
+	store64(x, &w)
+}
@@ -0,0 +1,36 @@
+//go:build !arm64 || purego || tinygo
+
+package fp448
+
+// Add calculates z = x+y mod p.
+func Add(z, x, y *Elt) { addGeneric(z, x, y) }
+
+// Sub calculates z = x-y mod p.
+func Sub(z, x, y *Elt) { subGeneric(z, x, y) }
+
+// Mul calculates z = x*y mod p.
+func Mul(z, x, y *Elt) { mulGeneric(z, x, y) }
+
+// Sqr calculates z = x^2 mod p.
+func Sqr(z, x *Elt) { sqrGeneric(z, x) }
+
+// AddSub calculates (x,y) = (x+y mod p, x-y mod p).
+func AddSub(x, y *Elt) {
+	t := *x
+	addGeneric(x, &t, y)
+	subGeneric(y, &t, y)
+}
+
+// Cmov assigns x = y when n is 1 and leaves x untouched when n is 0, in
+// constant time.
+func Cmov(x, y *Elt, n uint) { cmovGeneric(x, y, n) }
+
+// Cswap exchanges x and y when n is 1 and leaves both untouched when n is
+// 0, in constant time.
+func Cswap(x, y *Elt, n uint) { cswapGeneric(x, y, n) }
+
+// Div calculates z = x/y mod p for nonzero y.
+func Div(z, x, y *Elt) { divGeneric(z, x, y) }
+
+// Modp reduces z to its canonical representative in [0, p).
+func Modp(z *Elt) { modpGeneric(z) }
@@ -0,0 +1,153 @@
+package fp448
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func pBig() *big.Int {
+	p := new(big.Int).Lsh(big.NewInt(1), 448)
+	p.Sub(p, new(big.Int).Lsh(big.NewInt(1), 224))
+	return p.Sub(p, big.NewInt(1))
+}
+
+func toBig(x *Elt) *big.Int {
+	var b [Size]byte
+	for i := range b {
+		b[i] = x[Size-1-i]
+	}
+	v := new(big.Int).SetBytes(b[:])
+	return v.Mod(v, pBig())
+}
+
+func randElt(t *testing.T) Elt {
+	var x Elt
+	if _, err := rand.Read(x[:]); err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAgainstBig(t *testing.T) {
+	p := pBig()
+	for i := 0; i < 128; i++ {
+		x, y := randElt(t), randElt(t)
+		if i == 0 {
+			for j := range x {
+				x[j], y[j] = 0xff, 0xff
+			}
+		}
+		bx, by := toBig(&x), toBig(&y)
+		for _, op := range []struct {
+			name string
+			f    func(z, x, y *Elt)
+			ref  func(z, x, y *big.Int) *big.Int
+		}{
+			{"add", Add, func(z, x, y *big.Int) *big.Int { return z.Add(x, y) }},
+			{"sub", Sub, func(z, x, y *big.Int) *big.Int { return z.Sub(x, y) }},
+			{"mul", Mul, func(z, x, y *big.Int) *big.Int { return z.Mul(x, y) }},
+		} {
+			var z Elt
+			op.f(&z, &x, &y)
+			want := op.ref(new(big.Int), bx, by)
+			want.Mod(want, p)
+			if toBig(&z).Cmp(want) != 0 {
+				t.Fatalf("%s diverges from math/big", op.name)
+			}
+		}
+
+		var z, one Elt
+		Inv(&z, &x)
+		Mul(&z, &z, &x)
+		Modp(&z)
+		SetOne(&one)
+		if z != one && !IsZero(&x) {
+			t.Fatal("x * 1/x != 1")
+		}
+	}
+}
+
+func TestModpEdgeCases(t *testing.T) {
+	pp := P()
+	Modp(&pp)
+	if pp != (Elt{}) {
+		t.Error("Modp(p) != 0")
+	}
+	// p+1 must reduce to 1.
+	b := new(big.Int).Add(pBig(), big.NewInt(1)).Bytes()
+	var got Elt
+	for i := range b {
+		got[i] = b[len(b)-1-i]
+	}
+	Modp(&got)
+	if got != (Elt{1}) {
+		t.Error("Modp(p+1) != 1")
+	}
+}
+
+func TestSqrtRatio(t *testing.T) {
+	for i := 0; i < 64; i++ {
+		u, v := randElt(t), randElt(t)
+		if IsZero(&v) {
+			continue
+		}
+		var z, z2, q Elt
+		isQR := SqrtRatio(&z, &u, &v)
+		Sqr(&z2, &z)
+		Div(&q, &u, &v)
+		if isQR == 0 {
+			Neg(&q, &q)
+		}
+		Sub(&q, &z2, &q)
+		if !IsZero(&q) {
+			t.Fatal("SqrtRatio root does not square back")
+		}
+		r := new(big.Int).ModInverse(toBig(&v), pBig())
+		r.Mul(r, toBig(&u)).Mod(r, pBig())
+		want := uint(0)
+		if big.Jacobi(r, pBig()) >= 0 {
+			want = 1
+		}
+		if isQR != want {
+			t.Fatal("SqrtRatio QR flag wrong")
+		}
+	}
+}
+
+func TestLegendre(t *testing.T) {
+	for i := 0; i < 64; i++ {
+		x := randElt(t)
+		if got, want := Legendre(&x), big.Jacobi(toBig(&x), pBig()); got != want {
+			t.Fatalf("Legendre = %d, want %d", got, want)
+		}
+	}
+}
+
+func TestInvBatch(t *testing.T) {
+	z := make([]Elt, 10)
+	for i := range z {
+		if i != 3 {
+			z[i] = randElt(t)
+		}
+	}
+	want := make([]Elt, len(z))
+	for i := range z {
+		if !IsZero(&z[i]) {
+			Inv(&want[i], &z[i])
+		}
+	}
+	InvBatch(z)
+	for i := range z {
+		Sub(&z[i], &z[i], &want[i])
+		if !IsZero(&z[i]) {
+			t.Fatalf("InvBatch entry %d wrong", i)
+		}
+	}
+}
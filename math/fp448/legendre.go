@@ -0,0 +1,67 @@
+package fp448
+
+// powLegendre computes z = x^((p-1)/2) with a fixed addition chain over
+// the runs of ones in the exponent,
+// (p-1)/2 = (2^223-1)*2^224 + 2^223 - 1, replacing a generic
+// square-and-multiply ladder.
+func powLegendre(z, x *Elt) {
+	b1 := *x
+	b2 := b1
+	sqrN(&b2, 1)
+	Mul(&b2, &b2, &b1)
+	b4 := b2
+	sqrN(&b4, 2)
+	Mul(&b4, &b4, &b2)
+	b8 := b4
+	sqrN(&b8, 4)
+	Mul(&b8, &b8, &b4)
+	b16 := b8
+	sqrN(&b16, 8)
+	Mul(&b16, &b16, &b8)
+	b32 := b16
+	sqrN(&b32, 16)
+	Mul(&b32, &b32, &b16)
+	b64 := b32
+	sqrN(&b64, 32)
+	Mul(&b64, &b64, &b32)
+	b128 := b64
+	sqrN(&b128, 64)
+	Mul(&b128, &b128, &b64)
+	b223 := b128
+	sqrN(&b223, 64)
+	Mul(&b223, &b223, &b64)
+	sqrN(&b223, 16)
+	Mul(&b223, &b223, &b16)
+	sqrN(&b223, 8)
+	Mul(&b223, &b223, &b8)
+	sqrN(&b223, 4)
+	Mul(&b223, &b223, &b4)
+	sqrN(&b223, 2)
+	Mul(&b223, &b223, &b2)
+	sqrN(&b223, 1)
+	Mul(&b223, &b223, &b1)
+	acc := b223
+	sqrN(&acc, 224)
+	Mul(&acc, &acc, &b223)
+	*z = acc
+}
+
+// sqrN squares z in place n times.
+func sqrN(z *Elt, n int) {
+	for i := 0; i < n; i++ {
+		Sqr(z, z)
+	}
+}
+
+// Legendre returns the Legendre symbol of x in constant time: 1 when x is
+// a nonzero square, -1 when x is not a square, and 0 when x is zero.
+func Legendre(x *Elt) int {
+	var l Elt
+	powLegendre(&l, x)
+	var one Elt
+	SetOne(&one)
+	Sub(&one, &l, &one)
+	isOne := ctIsZeroElt(&one)
+	isZero := ctIsZeroElt(&l)
+	return 2*int(isOne) + int(isZero) - 1
+}
@@ -0,0 +1,84 @@
+package fp448
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// SelfTest cross-checks the arithmetic backend selected at build time
+// against the portable implementation and against math/big on randomized
+// and edge-case inputs, catching miscompilations or broken assembly
+// before the field is used for real keys. Call it once at startup and
+// fail closed on error.
+func SelfTest() error {
+	p := new(big.Int).Lsh(big.NewInt(1), 448)
+	p.Sub(p, new(big.Int).Lsh(big.NewInt(1), 224))
+	p.Sub(p, big.NewInt(1))
+	return fieldSelfTest(p, Size, backendOps{
+		add: Add, sub: Sub, mul: Mul, sqr: Sqr,
+		addG: addGeneric, subG: subGeneric, mulG: mulGeneric, sqrG: sqrGeneric,
+	})
+}
+
+type backendOps struct {
+	add, sub, mul    func(z, x, y *Elt)
+	sqr              func(z, x *Elt)
+	addG, subG, mulG func(z, x, y *Elt)
+	sqrG             func(z, x *Elt)
+}
+
+func fieldSelfTest(p *big.Int, size int, ops backendOps) error {
+	toBig := func(x *Elt) *big.Int {
+		b := make([]byte, size)
+		for i := range b {
+			b[i] = x[size-1-i]
+		}
+		v := new(big.Int).SetBytes(b)
+		return v.Mod(v, p)
+	}
+	for i := 0; i < 64; i++ {
+		var x, y Elt
+		if _, err := rand.Read(x[:]); err != nil {
+			return err
+		}
+		if _, err := rand.Read(y[:]); err != nil {
+			return err
+		}
+		if i == 0 {
+			// All-ones operands exercise every carry path.
+			for j := range x {
+				x[j], y[j] = 0xff, 0xff
+			}
+		}
+		bx, by := toBig(&x), toBig(&y)
+
+		type op struct {
+			name string
+			f, g func(z, x, y *Elt)
+			ref  func(z, x, y *big.Int) *big.Int
+		}
+		for _, o := range []op{
+			{"add", ops.add, ops.addG, func(z, x, y *big.Int) *big.Int { return z.Add(x, y) }},
+			{"sub", ops.sub, ops.subG, func(z, x, y *big.Int) *big.Int { return z.Sub(x, y) }},
+			{"mul", ops.mul, ops.mulG, func(z, x, y *big.Int) *big.Int { return z.Mul(x, y) }},
+			{"sqr",
+				func(z, x, y *Elt) { ops.sqr(z, x) },
+				func(z, x, y *Elt) { ops.sqrG(z, x) },
+				func(z, x, y *big.Int) *big.Int { return z.Mul(x, x) }},
+		} {
+			var za, zg Elt
+			o.f(&za, &x, &y)
+			o.g(&zg, &x, &y)
+			want := o.ref(new(big.Int), bx, by)
+			want.Mod(want, p)
+			if toBig(&za).Cmp(want) != 0 {
+				return errors.New("fp448: self-test: " + o.name + " backend diverges from math/big")
+			}
+			if toBig(&zg).Cmp(want) != 0 {
+				return errors.New("fp448: self-test: " + o.name + " portable code diverges from math/big")
+			}
+		}
+	}
+	return nil
+}
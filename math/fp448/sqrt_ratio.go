@@ -0,0 +1,64 @@
+package fp448
+
+// This file implements the sqrt_ratio and is_square primitives of
+// RFC 9380, Appendix F, in constant time. Since p = 2^448-2^224-1 is congruent to
+// 3 mod 4, a single exponentiation yields either root: for nonsquare t,
+// t^((p+1)/4) squares to -t, which is exactly the alternative output
+// sqrt_ratio needs.
+
+var (
+	// expSqrtRatio is (p+1)/4 = 2^446 - 2^222.
+	expSqrtRatio = Elt{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xc0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x3f}
+)
+
+// IsSquare reports whether x is a quadratic residue, returning 1 for
+// squares (including zero) and 0 otherwise, in constant time.
+func IsSquare(x *Elt) uint {
+	var l Elt
+	powLegendre(&l, x)
+	var one Elt
+	SetOne(&one)
+	Sub(&one, &l, &one)
+	return ctIsZeroElt(&l) | ctIsZeroElt(&one)
+}
+
+// SqrtRatio sets z so that z^2 = u/v when u/v is square, returning 1, and
+// z^2 = -u/v otherwise, returning 0, all in constant time. The divisor v
+// must be nonzero.
+func SqrtRatio(z, u, v *Elt) uint {
+	var t Elt
+	Div(&t, u, v)
+	powElt(z, &t, &expSqrtRatio)
+	var s2, d Elt
+	Sqr(&s2, z)
+	Sub(&d, &s2, &t)
+	return ctIsZeroElt(&d)
+}
+
+// powElt raises x to the fixed public exponent e, given in little-endian
+// bytes, by a left-to-right binary ladder. It is constant time with
+// respect to x; the exponent is not secret.
+func powElt(z, x *Elt, e *Elt) {
+	var acc Elt
+	SetOne(&acc)
+	for i := Size - 1; i >= 0; i-- {
+		for b := 7; b >= 0; b-- {
+			Sqr(&acc, &acc)
+			if e[i]>>uint(b)&1 == 1 {
+				Mul(&acc, &acc, x)
+			}
+		}
+	}
+	*z = acc
+}
+
+// ctIsZeroElt returns 1 when x represents zero, in constant time.
+func ctIsZeroElt(x *Elt) uint {
+	t := *x
+	Modp(&t)
+	var v byte
+	for _, b := range t {
+		v |= b
+	}
+	return uint((uint32(v) - 1) >> 31)
+}
@@ -0,0 +1,84 @@
+// Package fp521 provides prime field arithmetic over GF(2^521-1).
+package fp521
+
+import "math/bits"
+
+// Size in bytes of an element.
+const Size = 66
+
+// Elt is a prime field element stored in little-endian order.
+type Elt [Size]byte
+
+// p is the field characteristic 2^521-1 in 64-bit limbs.
+var p = [9]uint64{
+	0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff,
+	0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff,
+	0xffffffffffffffff, 0xffffffffffffffff, 0x1ff,
+}
+
+// P returns the field characteristic.
+func P() Elt {
+	var z Elt
+	var l [9]uint64 = p
+	fromLimbs(&z, &l)
+
+	return z
+}
+
+// SetOne assigns z = 1.
+func SetOne(z *Elt) { *z = Elt{}; z[0] = 1 }
+
+// IsZero returns true if z is zero, assuming z is reduced.
+func IsZero(z *Elt) bool { return *z == Elt{} }
+
+// Cmov assigns y to x if n is 1.
+func Cmov(x, y *Elt, n uint) {
+	m := -byte(n & 1)
+	for i := range x {
+		x[i] = x[i]&^m | y[i]&m
+	}
+}
+
+// Cswap interchanges x and y if n is 1.
+func Cswap(x, y *Elt, n uint) {
+	m := -byte(n & 1)
+	for i := range x {
+		t := m & (x[i] ^ y[i])
+		x[i] ^= t
+		y[i] ^= t
+	}
+}
+
+func toLimbs(x *Elt) (l [9]uint64) {
+	for i := 0; i < Size; i++ {
+		l[i/8] |= uint64(x[i]) << (8 * (i % 8))
+	}
+
+	return l
+}
+
+func fromLimbs(z *Elt, l *[9]uint64) {
+	for i := 0; i < Size; i++ {
+		z[i] = byte(l[i/8] >> (8 * (i % 8)))
+	}
+}
+
+// fold brings s below 2^521 by adding the bits above position 521 back
+// in (2^521 = 1 mod p), then subtracts p once if needed, leaving s
+// fully reduced.
+func fold(s *[9]uint64) {
+	c := s[8] >> 9
+	s[8] &= 0x1ff
+	for i := range s {
+		s[i], c = bits.Add64(s[i], c, 0)
+	}
+	var d [9]uint64
+	var b uint64
+	for i := range s {
+		d[i], b = bits.Sub64(s[i], p[i], b)
+	}
+	m := b - 1 // all ones when s >= p
+	for i := range s {
+		s[i] = s[i]&^m | d[i]&m
+	}
+}
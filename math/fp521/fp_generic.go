@@ -0,0 +1,128 @@
+package fp521
+
+// Portable field arithmetic. The Mersenne form of the prime makes
+// reduction a shift-and-add, so the generic code stays competitive;
+// assembly for the wide-multiplier architectures can slot in behind the
+// same functions later.
+
+import "math/bits"
+
+// Add calculates z = x+y mod p.
+func Add(z, x, y *Elt) {
+	xl, yl := toLimbs(x), toLimbs(y)
+	var c uint64
+	for i := range xl {
+		xl[i], c = bits.Add64(xl[i], yl[i], c)
+	}
+	fold(&xl)
+	fromLimbs(z, &xl)
+}
+
+// Sub calculates z = x-y mod p.
+func Sub(z, x, y *Elt) {
+	xl, yl := toLimbs(x), toLimbs(y)
+	var b uint64
+	for i := range xl {
+		xl[i], b = bits.Sub64(xl[i], yl[i], b)
+	}
+	// Add p back when the subtraction borrowed.
+	m := -b
+	var c uint64
+	for i := range xl {
+		xl[i], c = bits.Add64(xl[i], p[i]&m, c)
+	}
+	fromLimbs(z, &xl)
+}
+
+// Neg calculates z = -x mod p.
+func Neg(z, x *Elt) {
+	var zero Elt
+	Sub(z, &zero, x)
+}
+
+// Mul calculates z = x*y mod p.
+func Mul(z, x, y *Elt) {
+	xl, yl := toLimbs(x), toLimbs(y)
+	var w [18]uint64
+	for i := range xl {
+		var c uint64
+		for j := range yl {
+			hi, lo := bits.Mul64(xl[i], yl[j])
+			lo, cc := bits.Add64(lo, w[i+j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			w[i+j] = lo
+			c = hi
+		}
+		w[i+9] = c
+	}
+
+	// Split at bit 521 and add the halves: 2^521 = 1 mod p.
+	var s [9]uint64
+	var c uint64
+	for i := range s {
+		hi := w[8+i]>>9 | w[9+i]<<55
+		lo := w[i]
+		if i == 8 {
+			lo = w[8] & 0x1ff
+		}
+		s[i], c = bits.Add64(lo, hi, c)
+	}
+	fold(&s)
+	fromLimbs(z, &s)
+}
+
+// Sqr calculates z = x^2 mod p.
+func Sqr(z, x *Elt) { Mul(z, x, x) }
+
+// Modp ensures that z is between 0 and p-1.
+func Modp(z *Elt) {
+	l := toLimbs(z)
+	fold(&l)
+	fromLimbs(z, &l)
+}
+
+// Inv calculates z = 1/x mod p, with Inv(0) = 0.
+func Inv(z, x *Elt) {
+	// Fermat inversion, x^(p-2); the exponent 2^521-3 is every bit set
+	// except bit 1.
+	t := *x
+	for i := 519; i >= 0; i-- {
+		Sqr(&t, &t)
+		if i != 1 {
+			Mul(&t, &t, x)
+		}
+	}
+	*z = t
+}
+
+// InvBatch replaces every nonzero z[i] with its inverse, performing a
+// single inversion for the whole slice (Montgomery's trick). Zero
+// entries are left at zero.
+func InvBatch(z []Elt) {
+	n := len(z)
+	if n == 0 {
+		return
+	}
+	// pre[i] holds the product of the nonzero entries before index i.
+	pre := make([]Elt, n)
+	var r Elt
+	SetOne(&r)
+	for i := range z {
+		if IsZero(&z[i]) {
+			continue
+		}
+		pre[i] = r
+		Mul(&r, &r, &z[i])
+	}
+	Inv(&r, &r)
+	for i := n - 1; i >= 0; i-- {
+		if IsZero(&z[i]) {
+			continue
+		}
+		t := z[i]
+		Mul(&z[i], &r, &pre[i])
+		Mul(&r, &r, &t)
+	}
+}
@@ -0,0 +1,215 @@
+// Package mlsbset provides fixed-base scalar multiplication with signed
+// comb precomputation, in the spirit of the Modified LSB-set representation
+// by Faz-Hernandez, Longa, and Sanchez (CT-RSA 2015).
+//
+// Unlike curve-specific comb code, this package works over any prime-order
+// group exposed through the group package (P-256, P-384, ristretto255, and
+// so on), trading the constant-time guarantees of specialized field code for
+// generality: table indices are secret-dependent, so it targets fixed public
+// bases such as generators and long-term public keys.
+//
+// Tables can be serialized and embedded at build time, so the one-time
+// precomputation cost is paid by the build instead of by every process.
+package mlsbset
+
+import (
+	"errors"
+
+	"github.com/cloudflare/circl/group"
+)
+
+// Table holds the comb precomputation for one base point. The parameters w
+// (window width) and v (number of sub-tables) trade table size, which is
+// v*2^(w-1) group elements, against the e = ceil(l/(w*v)) doublings per
+// multiplication, where l is the scalar bit length.
+type Table struct {
+	g       group.Group
+	w, v, e int
+	base    group.Element
+	// t[u][x] holds 2^(u*e) * B(x) * base, where B(x) is the signed window
+	// value 2^((w-1)d) + sum_m (2*x_m - 1)*2^(m*d) and d = e*v.
+	t [][]group.Element
+}
+
+// New precomputes a comb table for the given base. The width w must be at
+// least two and v at least one.
+func New(g group.Group, base group.Element, w, v int) (*Table, error) {
+	if w < 2 || v < 1 || w > 8 {
+		return nil, errors.New("mlsbset: parameters out of range")
+	}
+	l := 8 * int(g.Params().ScalarLength)
+	e := (l + w*v - 1) / (w * v)
+	d := e * v
+
+	t := &Table{g: g, w: w, v: v, e: e, base: base.Copy()}
+
+	// p[m] = 2^(m*d) * base.
+	p := make([]group.Element, w)
+	p[0] = base.Copy()
+	for m := 1; m < w; m++ {
+		p[m] = p[m-1].Copy()
+		for i := 0; i < d; i++ {
+			p[m].Dbl(p[m])
+		}
+	}
+
+	t.t = make([][]group.Element, v)
+	neg := g.NewElement()
+	for u := 0; u < v; u++ {
+		if u > 0 {
+			// Shift every power by e doublings for the next sub-table.
+			for m := range p {
+				for i := 0; i < e; i++ {
+					p[m].Dbl(p[m])
+				}
+			}
+		}
+		t.t[u] = make([]group.Element, 1<<(w-1))
+		for x := range t.t[u] {
+			ent := p[w-1].Copy()
+			for m := 0; m < w-1; m++ {
+				if x>>uint(m)&1 == 1 {
+					ent.Add(ent, p[m])
+				} else {
+					ent.Add(ent, neg.Neg(p[m]))
+				}
+			}
+			t.t[u][x] = ent
+		}
+	}
+	return t, nil
+}
+
+// ScalarMul returns k times the base point of the table.
+func (t *Table) ScalarMul(k group.Scalar) (group.Element, error) {
+	kb, err := k.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	// The signed representation requires an odd scalar: even scalars are
+	// incremented here and the base is subtracted from the result.
+	adjusted := false
+	if kb[len(kb)-1]&1 == 0 {
+		adjusted = true
+		for i := len(kb) - 1; i >= 0; i-- {
+			kb[i]++
+			if kb[i] != 0 {
+				break
+			}
+		}
+	}
+
+	d := t.e * t.v
+	lp := d * t.w
+	// Signed all-bit-set digits: s(j) = 2*bit(j+1) - 1 for j < lp-1, and
+	// s(lp-1) = 1, so that k equals the sum of s(j)*2^j for odd k.
+	sign := func(j int) int {
+		if j == lp-1 {
+			return 1
+		}
+		return 2*int(bit(kb, j+1)) - 1
+	}
+
+	q := t.g.Identity()
+	neg := t.g.NewElement()
+	for i := t.e - 1; i >= 0; i-- {
+		q.Dbl(q)
+		for u := 0; u < t.v; u++ {
+			j := u*t.e + i
+			top := sign(j + (t.w-1)*d)
+			x := 0
+			for m := 0; m < t.w-1; m++ {
+				if sign(j+m*d) == top {
+					x |= 1 << uint(m)
+				}
+			}
+			if top == 1 {
+				q.Add(q, t.t[u][x])
+			} else {
+				q.Add(q, neg.Neg(t.t[u][x]))
+			}
+		}
+	}
+	if adjusted {
+		q.Add(q, neg.Neg(t.base))
+	}
+	return q, nil
+}
+
+// bit returns bit j of the big-endian bytes b, or zero past the end.
+func bit(b []byte, j int) byte {
+	if j >= 8*len(b) {
+		return 0
+	}
+	return b[len(b)-1-j/8] >> uint(j%8) & 1
+}
+
+// MarshalBinary serializes the table parameters, the base, and every table
+// entry in compressed form, suitable for embedding with go:embed.
+func (t *Table) MarshalBinary() ([]byte, error) {
+	out := []byte{byte(t.w), byte(t.v), byte(t.e >> 8), byte(t.e)}
+	b, err := t.base.MarshalBinaryCompress()
+	if err != nil {
+		return nil, err
+	}
+	out = append(out, b...)
+	for _, sub := range t.t {
+		for _, ent := range sub {
+			b, err := ent.MarshalBinaryCompress()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, b...)
+		}
+	}
+	return out, nil
+}
+
+// UnmarshalTable reconstructs a table over the given group from the output
+// of MarshalBinary.
+func UnmarshalTable(g group.Group, data []byte) (*Table, error) {
+	if len(data) < 4 {
+		return nil, errors.New("mlsbset: truncated table")
+	}
+	t := &Table{
+		g: g,
+		w: int(data[0]),
+		v: int(data[1]),
+		e: int(data[2])<<8 | int(data[3]),
+	}
+	if t.w < 2 || t.w > 8 || t.v < 1 || t.e < 1 {
+		return nil, errors.New("mlsbset: invalid table parameters")
+	}
+	data = data[4:]
+
+	eltLen := int(g.Params().CompressedElementLength)
+	next := func() (group.Element, error) {
+		if len(data) < eltLen {
+			return nil, errors.New("mlsbset: truncated table")
+		}
+		e := g.NewElement()
+		if err := e.UnmarshalBinary(data[:eltLen]); err != nil {
+			return nil, err
+		}
+		data = data[eltLen:]
+		return e, nil
+	}
+
+	var err error
+	if t.base, err = next(); err != nil {
+		return nil, err
+	}
+	t.t = make([][]group.Element, t.v)
+	for u := range t.t {
+		t.t[u] = make([]group.Element, 1<<(t.w-1))
+		for x := range t.t[u] {
+			if t.t[u][x], err = next(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(data) != 0 {
+		return nil, errors.New("mlsbset: trailing bytes in table")
+	}
+	return t, nil
+}
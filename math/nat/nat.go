@@ -0,0 +1,281 @@
+// Package nat implements constant-time arithmetic on natural numbers of
+// fixed width, including Montgomery modular exponentiation.
+//
+// Unlike math/big, all operations take time depending only on the announced
+// size of the operands, never on their values, which makes the package
+// suitable for secret-dependent computation in RSA-based schemes such as
+// blind RSA, threshold RSA, and SRP. Numbers are attached to a Modulus,
+// which precomputes the constants needed for Montgomery reduction.
+package nat
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// Nat is a natural number of fixed limb width. The width is set when the
+// value is created and never changes; all operands of an operation must
+// share a width.
+type Nat struct {
+	limbs []uint64 // little-endian
+}
+
+// Modulus is an odd modulus with precomputed Montgomery constants.
+type Modulus struct {
+	n     Nat
+	m0inv uint64 // -n^-1 mod 2^64
+	rr    Nat    // R^2 mod n, R = 2^(64*len)
+}
+
+// NewModulus creates a modulus from its big-endian bytes, which must encode
+// an odd number greater than one.
+func NewModulus(b []byte) (*Modulus, error) {
+	m := &Modulus{n: newNat(len(b))}
+	m.n.setBytes(b)
+	if len(m.n.limbs) == 0 || m.n.limbs[0]&1 == 0 {
+		return nil, errors.New("nat: modulus must be odd")
+	}
+
+	// m0inv = -n^-1 mod 2^64, by Newton iteration on the low limb: each
+	// step doubles the bits of precision, starting from three.
+	inv := m.n.limbs[0]
+	for i := 0; i < 5; i++ {
+		inv *= 2 - m.n.limbs[0]*inv
+	}
+	m.m0inv = -inv
+
+	// rr = 2^(128*len) mod n, by doubling.
+	m.rr = newNat(8 * len(m.n.limbs))
+	m.rr.limbs[0] = 1
+	for i := 0; i < 128*len(m.n.limbs); i++ {
+		m.rr.shiftLeftMod(&m.n)
+	}
+
+	return m, nil
+}
+
+// Size returns the modulus width in bytes.
+func (m *Modulus) Size() int { return 8 * len(m.n.limbs) }
+
+// NewNat returns a zero-valued number with the width of the modulus.
+func (m *Modulus) NewNat() *Nat {
+	n := newNat(8 * len(m.n.limbs))
+	return &n
+}
+
+// SetBytes sets x to the big-endian value b reduced in width, returning an
+// error if b does not fit or is not below the modulus.
+func (x *Nat) SetBytes(b []byte, m *Modulus) error {
+	if len(b) > 8*len(m.n.limbs) {
+		return errors.New("nat: value wider than the modulus")
+	}
+	t := newNat(8 * len(m.n.limbs))
+	t.setBytes(b)
+	if t.cmpGeq(&m.n) == 1 {
+		return errors.New("nat: value not below the modulus")
+	}
+	copy(x.limbs, t.limbs)
+	return nil
+}
+
+// Bytes returns the big-endian encoding of x, zero-padded to the width of
+// the modulus.
+func (x *Nat) Bytes(m *Modulus) []byte {
+	out := make([]byte, 8*len(m.n.limbs))
+	for i, l := range x.limbs {
+		for j := 0; j < 8; j++ {
+			out[len(out)-8*i-j-1] = byte(l >> (8 * uint(j)))
+		}
+	}
+	return out
+}
+
+// Equal returns 1 if x == y and 0 otherwise, in constant time.
+func (x *Nat) Equal(y *Nat) uint64 {
+	var diff uint64
+	for i := range x.limbs {
+		diff |= x.limbs[i] ^ y.limbs[i]
+	}
+	return eqZero(diff)
+}
+
+// Add computes x = x + y mod m.
+func (x *Nat) Add(y *Nat, m *Modulus) {
+	c := x.add(y)
+	x.maybeSub(&m.n, c|x.cmpGeq(&m.n))
+}
+
+// Sub computes x = x - y mod m.
+func (x *Nat) Sub(y *Nat, m *Modulus) {
+	b := x.sub(y)
+	// Add the modulus back if the subtraction borrowed.
+	var c uint64
+	mask := -b
+	for i := range x.limbs {
+		x.limbs[i], c = bits.Add64(x.limbs[i], m.n.limbs[i]&mask, c)
+	}
+}
+
+// Mul computes x = x * y mod m in constant time.
+func (x *Nat) Mul(y *Nat, m *Modulus) {
+	// Move both operands into the Montgomery domain, multiply, and come
+	// back: (xR)(yR)R^-1 = xyR, then xyR * 1 * R^-1 = xy.
+	xm := m.NewNat()
+	copy(xm.limbs, x.limbs)
+	xm.montgomeryMul(&m.rr, m) // x*R
+	xm.montgomeryMul(y, m)     // x*y
+	copy(x.limbs, xm.limbs)
+}
+
+// Exp computes x = g^e mod m in constant time. The exponent is given in
+// big-endian bytes and is treated as secret.
+func (x *Nat) Exp(g *Nat, e []byte, m *Modulus) {
+	// Fixed 4-bit window exponentiation with constant-time table lookups.
+	gm := m.NewNat()
+	copy(gm.limbs, g.limbs)
+	gm.montgomeryMul(&m.rr, m) // to Montgomery domain
+
+	// table[i] = g^(i+1) in the Montgomery domain.
+	var table [15]*Nat
+	table[0] = m.NewNat()
+	copy(table[0].limbs, gm.limbs)
+	for i := 1; i < len(table); i++ {
+		table[i] = m.NewNat()
+		copy(table[i].limbs, table[i-1].limbs)
+		table[i].montgomeryMul(gm, m)
+	}
+
+	// acc = R mod n, the Montgomery representation of 1.
+	acc := m.NewNat()
+	acc.limbs[0] = 1
+	acc.montgomeryMul(&m.rr, m)
+
+	t := m.NewNat()
+	for _, b := range e {
+		for _, window := range []uint64{uint64(b >> 4), uint64(b & 0x0f)} {
+			for i := 0; i < 4; i++ {
+				acc.montgomeryMul(acc, m)
+			}
+			// Constant-time selection of table[window-1].
+			for i := range t.limbs {
+				t.limbs[i] = 0
+			}
+			for i := range table {
+				mask := -eqZero(window ^ uint64(i+1))
+				for j := range t.limbs {
+					t.limbs[j] |= table[i].limbs[j] & mask
+				}
+			}
+			// Multiply unless the window is zero.
+			prev := m.NewNat()
+			copy(prev.limbs, acc.limbs)
+			acc.montgomeryMul(t, m)
+			keep := -eqZero(window) // keep prev when the window is zero
+			for j := range acc.limbs {
+				acc.limbs[j] = prev.limbs[j]&keep | acc.limbs[j]&^keep
+			}
+		}
+	}
+
+	one := m.NewNat()
+	one.limbs[0] = 1
+	acc.montgomeryMul(one, m) // leave the Montgomery domain
+	copy(x.limbs, acc.limbs)
+}
+
+func newNat(bytes int) Nat {
+	return Nat{limbs: make([]uint64, (bytes+7)/8)}
+}
+
+func (x *Nat) setBytes(b []byte) {
+	for i := len(b) - 1; i >= 0; i-- {
+		pos := len(b) - 1 - i
+		x.limbs[pos/8] |= uint64(b[i]) << (8 * uint(pos%8))
+	}
+}
+
+// add computes x += y, returning the carry.
+func (x *Nat) add(y *Nat) uint64 {
+	var c uint64
+	for i := range x.limbs {
+		x.limbs[i], c = bits.Add64(x.limbs[i], y.limbs[i], c)
+	}
+	return c
+}
+
+// sub computes x -= y, returning the borrow.
+func (x *Nat) sub(y *Nat) uint64 {
+	var b uint64
+	for i := range x.limbs {
+		x.limbs[i], b = bits.Sub64(x.limbs[i], y.limbs[i], b)
+	}
+	return b
+}
+
+// cmpGeq returns 1 if x >= y, in constant time.
+func (x *Nat) cmpGeq(y *Nat) uint64 {
+	var b uint64
+	for i := range x.limbs {
+		_, b = bits.Sub64(x.limbs[i], y.limbs[i], b)
+	}
+	return 1 - b
+}
+
+// maybeSub subtracts m from x if doit is 1.
+func (x *Nat) maybeSub(m *Nat, doit uint64) {
+	mask := -doit
+	var b uint64
+	for i := range x.limbs {
+		var d uint64
+		d, b = bits.Sub64(x.limbs[i], m.limbs[i]&mask, b)
+		x.limbs[i] = d
+	}
+}
+
+// shiftLeftMod doubles x modulo m.
+func (x *Nat) shiftLeftMod(m *Nat) {
+	c := x.add(x)
+	x.maybeSub(m, c|x.cmpGeq(m))
+}
+
+// montgomeryMul computes x = x * y / R mod m, with R = 2^(64*len), using
+// the CIOS method.
+func (x *Nat) montgomeryMul(y *Nat, m *Modulus) {
+	n := len(x.limbs)
+	t := make([]uint64, n+2)
+	for i := 0; i < n; i++ {
+		var c uint64
+		for j := 0; j < n; j++ {
+			t[j], c = madd(t[j], x.limbs[j], y.limbs[i], c)
+		}
+		t[n], c = bits.Add64(t[n], c, 0)
+		t[n+1] = c
+
+		q := t[0] * m.m0inv
+		c = 0
+		for j := 0; j < n; j++ {
+			t[j], c = madd(t[j], q, m.n.limbs[j], c)
+		}
+		t[n], c = bits.Add64(t[n], c, 0)
+		t[n+1] += c
+
+		copy(t, t[1:])
+		t[n+1] = 0
+	}
+	copy(x.limbs, t[:n])
+	x.maybeSub(&m.n, t[n]|x.cmpGeq(&m.n))
+}
+
+// madd returns the low word and carry of t + a*b + c.
+func madd(t, a, b, c uint64) (lo, carry uint64) {
+	hi, lo := bits.Mul64(a, b)
+	var c1, c2 uint64
+	lo, c1 = bits.Add64(lo, t, 0)
+	lo, c2 = bits.Add64(lo, c, 0)
+	return lo, hi + c1 + c2
+}
+
+// eqZero returns 1 if x == 0 and 0 otherwise, in constant time.
+func eqZero(x uint64) uint64 {
+	return ^(x | -x) >> 63
+}
@@ -0,0 +1,83 @@
+package tower
+
+// E2 is an element a + b*u of a quadratic extension with u^2 = beta.
+type E2[E any] struct{ A, B E }
+
+// Ext2 implements Algebra over E2 elements. Its non-residue, used when
+// another floor is built on top, is an element of the extension itself
+// (for BLS12-381, 1+u); it may be left nil when this is the top floor.
+type Ext2[E any] struct {
+	f  Algebra[E]
+	nr *E2[E]
+}
+
+// NewExt2 returns the quadratic extension of base defined by u^2 = beta,
+// where beta is the non-residue of base. The optional nonResidue is the
+// constant handed to the next floor.
+func NewExt2[E any](base Algebra[E], nonResidue *E2[E]) *Ext2[E] {
+	return &Ext2[E]{f: base, nr: nonResidue}
+}
+
+func (e *Ext2[E]) Set(z, x *E2[E]) { e.f.Set(&z.A, &x.A); e.f.Set(&z.B, &x.B) }
+
+func (e *Ext2[E]) SetZero(z *E2[E]) { e.f.SetZero(&z.A); e.f.SetZero(&z.B) }
+
+func (e *Ext2[E]) SetOne(z *E2[E]) { e.f.SetOne(&z.A); e.f.SetZero(&z.B) }
+
+func (e *Ext2[E]) IsZero(x *E2[E]) bool { return e.f.IsZero(&x.A) && e.f.IsZero(&x.B) }
+
+func (e *Ext2[E]) IsEqual(x, y *E2[E]) bool {
+	return e.f.IsEqual(&x.A, &y.A) && e.f.IsEqual(&x.B, &y.B)
+}
+
+func (e *Ext2[E]) Add(z, x, y *E2[E]) { e.f.Add(&z.A, &x.A, &y.A); e.f.Add(&z.B, &x.B, &y.B) }
+
+func (e *Ext2[E]) Sub(z, x, y *E2[E]) { e.f.Sub(&z.A, &x.A, &y.A); e.f.Sub(&z.B, &x.B, &y.B) }
+
+func (e *Ext2[E]) Neg(z, x *E2[E]) { e.f.Neg(&z.A, &x.A); e.f.Neg(&z.B, &x.B) }
+
+// Mul uses Karatsuba: three base multiplications per product.
+func (e *Ext2[E]) Mul(z, x, y *E2[E]) {
+	var t0, t1, t2, sx, sy E
+	e.f.Mul(&t0, &x.A, &y.A)
+	e.f.Mul(&t1, &x.B, &y.B)
+	e.f.Add(&sx, &x.A, &x.B)
+	e.f.Add(&sy, &y.A, &y.B)
+	e.f.Mul(&t2, &sx, &sy)
+	e.f.Sub(&t2, &t2, &t0)
+	e.f.Sub(&t2, &t2, &t1)
+	e.f.MulByNonResidue(&t1, &t1)
+	e.f.Add(&z.A, &t0, &t1)
+	e.f.Set(&z.B, &t2)
+}
+
+func (e *Ext2[E]) Sqr(z, x *E2[E]) {
+	var t0, t1, ab E
+	e.f.Sqr(&t0, &x.A)
+	e.f.Sqr(&t1, &x.B)
+	e.f.Mul(&ab, &x.A, &x.B)
+	e.f.Add(&ab, &ab, &ab)
+	e.f.MulByNonResidue(&t1, &t1)
+	e.f.Add(&z.A, &t0, &t1)
+	e.f.Set(&z.B, &ab)
+}
+
+// Inv computes the inverse by the norm: 1/(a+bu) = (a-bu)/(a^2 - beta*b^2).
+func (e *Ext2[E]) Inv(z, x *E2[E]) {
+	var d, t E
+	e.f.Sqr(&d, &x.A)
+	e.f.Sqr(&t, &x.B)
+	e.f.MulByNonResidue(&t, &t)
+	e.f.Sub(&d, &d, &t)
+	e.f.Inv(&d, &d)
+	e.f.Mul(&z.A, &x.A, &d)
+	e.f.Neg(&d, &d)
+	e.f.Mul(&z.B, &x.B, &d)
+}
+
+func (e *Ext2[E]) MulByNonResidue(z, x *E2[E]) {
+	if e.nr == nil {
+		panic("tower: extension has no non-residue configured")
+	}
+	e.Mul(z, x, e.nr)
+}
@@ -0,0 +1,134 @@
+package tower
+
+// E3 is an element a + b*v + c*v^2 of a cubic extension with v^3 = xi.
+type E3[E any] struct{ A, B, C E }
+
+// Ext3 implements Algebra over E3 elements, with xi the non-residue of
+// the base. Its own non-residue is v itself, the usual choice when a
+// quadratic floor (such as fp12 over fp6) is built on top.
+type Ext3[E any] struct {
+	f Algebra[E]
+}
+
+// NewExt3 returns the cubic extension of base defined by v^3 = xi, where
+// xi is the non-residue of base.
+func NewExt3[E any](base Algebra[E]) *Ext3[E] { return &Ext3[E]{f: base} }
+
+func (e *Ext3[E]) Set(z, x *E3[E]) {
+	e.f.Set(&z.A, &x.A)
+	e.f.Set(&z.B, &x.B)
+	e.f.Set(&z.C, &x.C)
+}
+
+func (e *Ext3[E]) SetZero(z *E3[E]) { e.f.SetZero(&z.A); e.f.SetZero(&z.B); e.f.SetZero(&z.C) }
+
+func (e *Ext3[E]) SetOne(z *E3[E]) { e.f.SetOne(&z.A); e.f.SetZero(&z.B); e.f.SetZero(&z.C) }
+
+func (e *Ext3[E]) IsZero(x *E3[E]) bool {
+	return e.f.IsZero(&x.A) && e.f.IsZero(&x.B) && e.f.IsZero(&x.C)
+}
+
+func (e *Ext3[E]) IsEqual(x, y *E3[E]) bool {
+	return e.f.IsEqual(&x.A, &y.A) && e.f.IsEqual(&x.B, &y.B) && e.f.IsEqual(&x.C, &y.C)
+}
+
+func (e *Ext3[E]) Add(z, x, y *E3[E]) {
+	e.f.Add(&z.A, &x.A, &y.A)
+	e.f.Add(&z.B, &x.B, &y.B)
+	e.f.Add(&z.C, &x.C, &y.C)
+}
+
+func (e *Ext3[E]) Sub(z, x, y *E3[E]) {
+	e.f.Sub(&z.A, &x.A, &y.A)
+	e.f.Sub(&z.B, &x.B, &y.B)
+	e.f.Sub(&z.C, &x.C, &y.C)
+}
+
+func (e *Ext3[E]) Neg(z, x *E3[E]) {
+	e.f.Neg(&z.A, &x.A)
+	e.f.Neg(&z.B, &x.B)
+	e.f.Neg(&z.C, &x.C)
+}
+
+// Mul is the Karatsuba-style interpolation with six base multiplications
+// (Devegili et al., "Multiplication and Squaring on Pairing-Friendly
+// Fields", Section 4).
+func (e *Ext3[E]) Mul(z, x, y *E3[E]) {
+	var t0, t1, t2, s, t, zA, zB, zC E
+	e.f.Mul(&t0, &x.A, &y.A)
+	e.f.Mul(&t1, &x.B, &y.B)
+	e.f.Mul(&t2, &x.C, &y.C)
+
+	// zA = t0 + xi*((xB+xC)(yB+yC) - t1 - t2)
+	e.f.Add(&s, &x.B, &x.C)
+	e.f.Add(&t, &y.B, &y.C)
+	e.f.Mul(&s, &s, &t)
+	e.f.Sub(&s, &s, &t1)
+	e.f.Sub(&s, &s, &t2)
+	e.f.MulByNonResidue(&s, &s)
+	e.f.Add(&zA, &t0, &s)
+
+	// zB = (xA+xB)(yA+yB) - t0 - t1 + xi*t2
+	e.f.Add(&s, &x.A, &x.B)
+	e.f.Add(&t, &y.A, &y.B)
+	e.f.Mul(&s, &s, &t)
+	e.f.Sub(&s, &s, &t0)
+	e.f.Sub(&s, &s, &t1)
+	e.f.MulByNonResidue(&t, &t2)
+	e.f.Add(&zB, &s, &t)
+
+	// zC = (xA+xC)(yA+yC) - t0 - t2 + t1
+	e.f.Add(&s, &x.A, &x.C)
+	e.f.Add(&t, &y.A, &y.C)
+	e.f.Mul(&s, &s, &t)
+	e.f.Sub(&s, &s, &t0)
+	e.f.Sub(&s, &s, &t2)
+	e.f.Add(&zC, &s, &t1)
+
+	e.f.Set(&z.A, &zA)
+	e.f.Set(&z.B, &zB)
+	e.f.Set(&z.C, &zC)
+}
+
+func (e *Ext3[E]) Sqr(z, x *E3[E]) { e.Mul(z, x, x) }
+
+// Inv computes the inverse with the standard adjoint formulas:
+// 1/x = (t0 + t1*v + t2*v^2)/d, where t0 = a^2 - xi*b*c,
+// t1 = xi*c^2 - a*b, t2 = b^2 - a*c, and d = a*t0 + xi*(c*t1 + b*t2).
+func (e *Ext3[E]) Inv(z, x *E3[E]) {
+	var t0, t1, t2, d, t E
+	e.f.Sqr(&t0, &x.A)
+	e.f.Mul(&t, &x.B, &x.C)
+	e.f.MulByNonResidue(&t, &t)
+	e.f.Sub(&t0, &t0, &t)
+
+	e.f.Sqr(&t1, &x.C)
+	e.f.MulByNonResidue(&t1, &t1)
+	e.f.Mul(&t, &x.A, &x.B)
+	e.f.Sub(&t1, &t1, &t)
+
+	e.f.Sqr(&t2, &x.B)
+	e.f.Mul(&t, &x.A, &x.C)
+	e.f.Sub(&t2, &t2, &t)
+
+	e.f.Mul(&d, &x.C, &t1)
+	e.f.Mul(&t, &x.B, &t2)
+	e.f.Add(&d, &d, &t)
+	e.f.MulByNonResidue(&d, &d)
+	e.f.Mul(&t, &x.A, &t0)
+	e.f.Add(&d, &d, &t)
+	e.f.Inv(&d, &d)
+
+	e.f.Mul(&z.A, &t0, &d)
+	e.f.Mul(&z.B, &t1, &d)
+	e.f.Mul(&z.C, &t2, &d)
+}
+
+// MulByNonResidue multiplies by v: (a + b*v + c*v^2)*v = xi*c + a*v + b*v^2.
+func (e *Ext3[E]) MulByNonResidue(z, x *E3[E]) {
+	var t E
+	e.f.MulByNonResidue(&t, &x.C)
+	e.f.Set(&z.C, &x.B)
+	e.f.Set(&z.B, &x.A)
+	e.f.Set(&z.A, &t)
+}
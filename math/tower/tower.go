@@ -0,0 +1,37 @@
+// Package tower builds extension-field towers (fp2, fp4, fp6, fp12, ...)
+// over any base field, parameterized by the element type and by the
+// non-residues that define each floor. It factors out the arithmetic that
+// pairing curves share, so a new curve (BN254, BLS24) only supplies its
+// base field and non-residues instead of reimplementing the tower.
+//
+// A quadratic floor F[u]/(u^2 - beta) is built with NewExt2 and a cubic
+// floor F[v]/(v^3 - xi) with NewExt3; beta and xi are whatever the
+// Algebra below reports through MulByNonResidue. Floors implement Algebra
+// themselves, so they stack: the BLS12-381 tower is a quadratic extension
+// of a cubic extension of a quadratic extension of the prime field.
+//
+// Frobenius endomorphisms and cyclotomic shortcuts depend on the curve
+// constants and stay out of this package.
+package tower
+
+// Algebra is the set of operations the tower requires from a field. All
+// methods follow the receiver-free z = op(x, y) style of the ff packages,
+// and must tolerate z aliasing any operand.
+//
+// MulByNonResidue multiplies by the distinguished non-residue of the
+// field, which becomes the defining constant of the floor built on top of
+// it.
+type Algebra[E any] interface {
+	Set(z, x *E)
+	SetZero(z *E)
+	SetOne(z *E)
+	IsZero(x *E) bool
+	IsEqual(x, y *E) bool
+	Add(z, x, y *E)
+	Sub(z, x, y *E)
+	Neg(z, x *E)
+	Mul(z, x, y *E)
+	Sqr(z, x *E)
+	Inv(z, x *E)
+	MulByNonResidue(z, x *E)
+}
@@ -0,0 +1,105 @@
+package pki
+
+import (
+	"errors"
+
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/schemes"
+	"github.com/cloudflare/circl/sign"
+)
+
+// Typed layer over the raw marshaling functions: keys go in and out as
+// scheme key objects instead of (name, bytes) pairs, resolved through
+// the schemes registry. Only algorithms whose packages are linked into
+// the binary can be parsed this way; the raw functions below keep
+// working for the rest.
+
+// ErrNotLinked is returned when a parsed key's algorithm has a known
+// OID but its scheme package is not linked into the binary.
+var ErrNotLinked = errors.New("pki: algorithm not linked in")
+
+// MarshalPKIXPublicKey encodes a KEM or signature public key as
+// SubjectPublicKeyInfo DER.
+func MarshalPKIXPublicKey(key interface{}) ([]byte, error) {
+	switch k := key.(type) {
+	case sign.PublicKey:
+		raw, err := k.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return MarshalPublicKey(k.Scheme().Name(), raw)
+	case kem.PublicKey:
+		raw, err := k.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return MarshalPublicKey(k.Scheme().Name(), raw)
+	}
+
+	return nil, errors.New("pki: unsupported public key type")
+}
+
+// ParsePKIXPublicKey decodes SubjectPublicKeyInfo DER into a key of the
+// scheme registered for its OID, either a sign.PublicKey or a
+// kem.PublicKey.
+func ParsePKIXPublicKey(der []byte) (interface{}, error) {
+	name, raw, err := ParsePublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := schemes.ByName(name)
+	if !ok {
+		return nil, ErrNotLinked
+	}
+	switch e.Kind {
+	case schemes.KindKEM:
+		return e.KEM.UnmarshalBinaryPublicKey(raw)
+	case schemes.KindSignature:
+		return e.Signature.UnmarshalBinaryPublicKey(raw)
+	}
+
+	return nil, ErrNotLinked
+}
+
+// MarshalPKCS8PrivateKey encodes a KEM or signature private key as
+// PKCS#8 DER.
+func MarshalPKCS8PrivateKey(key interface{}) ([]byte, error) {
+	switch k := key.(type) {
+	case sign.PrivateKey:
+		raw, err := k.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return MarshalPrivateKey(k.Scheme().Name(), raw)
+	case kem.PrivateKey:
+		raw, err := k.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return MarshalPrivateKey(k.Scheme().Name(), raw)
+	}
+
+	return nil, errors.New("pki: unsupported private key type")
+}
+
+// ParsePKCS8PrivateKey decodes PKCS#8 DER into a key of the scheme
+// registered for its OID, either a sign.PrivateKey or a
+// kem.PrivateKey.
+func ParsePKCS8PrivateKey(der []byte) (interface{}, error) {
+	name, raw, err := ParsePrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := schemes.ByName(name)
+	if !ok {
+		return nil, ErrNotLinked
+	}
+	switch e.Kind {
+	case schemes.KindKEM:
+		return e.KEM.UnmarshalBinaryPrivateKey(raw)
+	case schemes.KindSignature:
+		return e.Signature.UnmarshalBinaryPrivateKey(raw)
+	}
+
+	return nil, ErrNotLinked
+}
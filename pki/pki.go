@@ -0,0 +1,235 @@
+// Package pki marshals keys to and from the interoperable formats used by
+// standard tooling: SubjectPublicKeyInfo (RFC 5280) for public keys,
+// PKCS#8 (RFC 5958) for private keys, and their PEM encodings. Algorithms
+// are identified by OID through a registry preloaded with the assigned and
+// draft identifiers of the algorithms CIRCL implements; scheme packages
+// can register further entries.
+//
+// The package works on raw key bytes as produced by each scheme's
+// MarshalBinary, so it has no dependency on the scheme packages
+// themselves: parsing returns the algorithm name and the raw key, and the
+// caller hands the bytes to the right scheme. A typed layer
+// (MarshalPKIXPublicKey, ParsePKCS8PrivateKey, and friends) bridges to
+// the schemes registry instead, resolving parsed keys directly to the
+// scheme instances linked into the binary.
+package pki
+
+import (
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"crypto/x509/pkix"
+)
+
+// Algorithm describes how one algorithm's keys appear in SPKI and PKCS#8.
+type Algorithm struct {
+	// Name identifies the algorithm, e.g. "Ed25519" or "ML-KEM-768".
+	Name string
+	// OID is the object identifier carried in the AlgorithmIdentifier.
+	OID asn1.ObjectIdentifier
+}
+
+var (
+	registry sync.Mutex
+	byName   = make(map[string]Algorithm)
+	byOID    = make(map[string]Algorithm)
+	// ErrUnknownAlgorithm is returned when a name or OID is not in the
+	// register.
+	ErrUnknownAlgorithm = errors.New("pki: unknown algorithm")
+	// ErrMalformed is returned when ASN.1 or PEM input does not parse as
+	// the expected structure.
+	ErrMalformed = errors.New("pki: malformed input")
+)
+
+// RegisterAlgorithm adds an algorithm to the registry. It fails when the
+// name or OID is already taken with a different counterpart.
+func RegisterAlgorithm(a Algorithm) error {
+	if a.Name == "" || len(a.OID) == 0 {
+		return errors.New("pki: algorithm needs a name and an OID")
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if prev, ok := byName[a.Name]; ok && !prev.OID.Equal(a.OID) {
+		return errors.New("pki: algorithm name already registered: " + a.Name)
+	}
+	if prev, ok := byOID[a.OID.String()]; ok && prev.Name != a.Name {
+		return errors.New("pki: OID already registered: " + a.OID.String())
+	}
+	byName[a.Name] = a
+	byOID[a.OID.String()] = a
+
+	return nil
+}
+
+// AlgorithmByName looks an algorithm up by name.
+func AlgorithmByName(name string) (Algorithm, error) {
+	registry.Lock()
+	defer registry.Unlock()
+	a, ok := byName[name]
+	if !ok {
+		return Algorithm{}, ErrUnknownAlgorithm
+	}
+
+	return a, nil
+}
+
+// Algorithms returns the names of all registered algorithms, sorted.
+func Algorithms() []string {
+	registry.Lock()
+	defer registry.Unlock()
+	names := make([]string, 0, len(byName))
+	for n := range byName {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+type pkcs8 struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// MarshalPublicKey encodes a raw public key as SubjectPublicKeyInfo DER.
+func MarshalPublicKey(name string, raw []byte) ([]byte, error) {
+	a, err := AlgorithmByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: a.OID},
+		PublicKey: asn1.BitString{Bytes: raw, BitLength: 8 * len(raw)},
+	})
+}
+
+// ParsePublicKey decodes SubjectPublicKeyInfo DER, returning the algorithm
+// name and the raw public key.
+func ParsePublicKey(der []byte) (name string, raw []byte, err error) {
+	var spki subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(rest) != 0 {
+		return "", nil, fmt.Errorf("%w: trailing bytes after SubjectPublicKeyInfo", ErrMalformed)
+	}
+	if spki.PublicKey.BitLength%8 != 0 {
+		return "", nil, fmt.Errorf("%w: public key is not a whole number of bytes", ErrMalformed)
+	}
+	registry.Lock()
+	a, ok := byOID[spki.Algorithm.Algorithm.String()]
+	registry.Unlock()
+	if !ok {
+		return "", nil, ErrUnknownAlgorithm
+	}
+
+	return a.Name, spki.PublicKey.RightAlign(), nil
+}
+
+// MarshalPrivateKey encodes a raw private key as PKCS#8 DER. Following
+// RFC 8410 and the LAMPS profiles, the PrivateKey field is an OCTET STRING
+// wrapping the raw key.
+func MarshalPrivateKey(name string, raw []byte) ([]byte, error) {
+	a, err := AlgorithmByName(name)
+	if err != nil {
+		return nil, err
+	}
+	inner, err := asn1.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(pkcs8{
+		Algorithm:  pkix.AlgorithmIdentifier{Algorithm: a.OID},
+		PrivateKey: inner,
+	})
+}
+
+// ParsePrivateKey decodes PKCS#8 DER, returning the algorithm name and the
+// raw private key.
+func ParsePrivateKey(der []byte) (name string, raw []byte, err error) {
+	var p8 pkcs8
+	rest, err := asn1.Unmarshal(der, &p8)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(rest) != 0 {
+		return "", nil, fmt.Errorf("%w: trailing bytes after PrivateKeyInfo", ErrMalformed)
+	}
+	if p8.Version != 0 {
+		return "", nil, fmt.Errorf("%w: unsupported PKCS#8 version", ErrMalformed)
+	}
+	registry.Lock()
+	a, ok := byOID[p8.Algorithm.Algorithm.String()]
+	registry.Unlock()
+	if !ok {
+		return "", nil, ErrUnknownAlgorithm
+	}
+	var inner []byte
+	rest, err = asn1.Unmarshal(p8.PrivateKey, &inner)
+	if err != nil || len(rest) != 0 {
+		return "", nil, fmt.Errorf("%w: malformed private key", ErrMalformed)
+	}
+
+	return a.Name, inner, nil
+}
+
+// PEM block types of the formats above.
+const (
+	pemPublic  = "PUBLIC KEY"
+	pemPrivate = "PRIVATE KEY"
+)
+
+// MarshalPublicKeyPEM is MarshalPublicKey with PEM armor.
+func MarshalPublicKeyPEM(name string, raw []byte) ([]byte, error) {
+	der, err := MarshalPublicKey(name, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemPublic, Bytes: der}), nil
+}
+
+// MarshalPrivateKeyPEM is MarshalPrivateKey with PEM armor.
+func MarshalPrivateKeyPEM(name string, raw []byte) ([]byte, error) {
+	der, err := MarshalPrivateKey(name, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemPrivate, Bytes: der}), nil
+}
+
+// ParsePublicKeyPEM decodes the first PEM block, which must be of type
+// "PUBLIC KEY".
+func ParsePublicKeyPEM(data []byte) (name string, raw []byte, err error) {
+	blk, _ := pem.Decode(data)
+	if blk == nil || blk.Type != pemPublic {
+		return "", nil, fmt.Errorf("%w: no PUBLIC KEY PEM block", ErrMalformed)
+	}
+
+	return ParsePublicKey(blk.Bytes)
+}
+
+// ParsePrivateKeyPEM decodes the first PEM block, which must be of type
+// "PRIVATE KEY".
+func ParsePrivateKeyPEM(data []byte) (name string, raw []byte, err error) {
+	blk, _ := pem.Decode(data)
+	if blk == nil || blk.Type != pemPrivate {
+		return "", nil, fmt.Errorf("%w: no PRIVATE KEY PEM block", ErrMalformed)
+	}
+
+	return ParsePrivateKey(blk.Bytes)
+}
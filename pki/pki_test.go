@@ -0,0 +1,165 @@
+package pki_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/cloudflare/circl/kem"
+	_ "github.com/cloudflare/circl/kem/mlkem"
+	"github.com/cloudflare/circl/pki"
+	"github.com/cloudflare/circl/schemes"
+	"github.com/cloudflare/circl/sign"
+	_ "github.com/cloudflare/circl/sign/hybrid"
+	_ "github.com/cloudflare/circl/sign/mldsa"
+)
+
+func TestRawRoundtrip(t *testing.T) {
+	raw := []byte("opaque key material")
+	der, err := pki.MarshalPublicKey("Ed25519", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, got, err := pki.ParsePublicKey(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Ed25519" || !bytes.Equal(got, raw) {
+		t.Fatal("public key does not roundtrip")
+	}
+
+	der, err = pki.MarshalPrivateKey("ML-KEM-768", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, got, err = pki.ParsePrivateKey(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "ML-KEM-768" || !bytes.Equal(got, raw) {
+		t.Fatal("private key does not roundtrip")
+	}
+
+	if _, err = pki.MarshalPublicKey("NoSuchScheme", raw); err == nil {
+		t.Fatal("unregistered algorithm accepted")
+	}
+}
+
+func TestTypedRoundtrip(t *testing.T) {
+	for _, name := range []string{"ML-DSA-44", "ML-KEM-512", "MLDSA44-Ed25519"} {
+		t.Run(name, func(t *testing.T) {
+			e, ok := schemes.ByName(name)
+			if !ok {
+				t.Fatal("scheme not registered")
+			}
+
+			var pk, sk interface {
+				MarshalBinary() ([]byte, error)
+			}
+			var err error
+			switch e.Kind {
+			case schemes.KindKEM:
+				pk, sk, err = e.KEM.GenerateKeyPair()
+			case schemes.KindSignature:
+				pk, sk, err = e.Signature.GenerateKey()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			der, err := pki.MarshalPKIXPublicKey(pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotPub, err := pki.ParsePKIXPublicKey(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			der, err = pki.MarshalPKCS8PrivateKey(sk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotPriv, err := pki.ParsePKCS8PrivateKey(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			switch e.Kind {
+			case schemes.KindKEM:
+				if !gotPub.(kem.PublicKey).Equal(pk.(kem.PublicKey)) {
+					t.Fatal("public key does not roundtrip")
+				}
+				if !gotPriv.(kem.PrivateKey).Equal(sk.(kem.PrivateKey)) {
+					t.Fatal("private key does not roundtrip")
+				}
+			case schemes.KindSignature:
+				if !gotPub.(sign.PublicKey).Equal(pk.(sign.PublicKey)) {
+					t.Fatal("public key does not roundtrip")
+				}
+				if !gotPriv.(sign.PrivateKey).Equal(sk.(sign.PrivateKey)) {
+					t.Fatal("private key does not roundtrip")
+				}
+			}
+		})
+	}
+}
+
+func TestPEM(t *testing.T) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		t.Fatal(err)
+	}
+
+	pem, err := pki.MarshalPublicKeyPEM("Ed448", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, got, err := pki.ParsePublicKeyPEM(pem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Ed448" || !bytes.Equal(got, raw) {
+		t.Fatal("public key PEM does not roundtrip")
+	}
+
+	pem, err = pki.MarshalPrivateKeyPEM("Ed448", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, got, err = pki.ParsePrivateKeyPEM(pem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Ed448" || !bytes.Equal(got, raw) {
+		t.Fatal("private key PEM does not roundtrip")
+	}
+
+	if _, _, err = pki.ParsePublicKeyPEM([]byte("not pem")); err == nil {
+		t.Fatal("garbage accepted as PEM")
+	}
+}
+
+func TestEncryptedPEM(t *testing.T) {
+	raw := make([]byte, 57)
+	if _, err := rand.Read(raw); err != nil {
+		t.Fatal(err)
+	}
+	passphrase := []byte("correct horse battery staple")
+
+	pem, err := pki.EncryptPrivateKeyPEM("Ed448", raw, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, got, err := pki.ParseEncryptedPrivateKeyPEM(pem, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Ed448" || !bytes.Equal(got, raw) {
+		t.Fatal("encrypted key does not roundtrip")
+	}
+
+	if _, _, err = pki.ParseEncryptedPrivateKeyPEM(pem, []byte("wrong")); err == nil {
+		t.Fatal("wrong passphrase accepted")
+	}
+}
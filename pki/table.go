@@ -0,0 +1,47 @@
+package pki
+
+import "encoding/asn1"
+
+// Preloaded identifiers: the RFC 8410 curdle algorithms, the NIST CSOR
+// identifiers of the FIPS 203/204/205 finals, and provisional composite
+// signature identifiers. Algorithms without any identifier yet register
+// themselves from their own packages once one exists.
+func init() {
+	for _, a := range []Algorithm{
+		// RFC 8410.
+		{"X25519", asn1.ObjectIdentifier{1, 3, 101, 110}},
+		{"X448", asn1.ObjectIdentifier{1, 3, 101, 111}},
+		{"Ed25519", asn1.ObjectIdentifier{1, 3, 101, 112}},
+		{"Ed448", asn1.ObjectIdentifier{1, 3, 101, 113}},
+		// NIST CSOR, FIPS 204 (ML-DSA).
+		{"ML-DSA-44", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 17}},
+		{"ML-DSA-65", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 18}},
+		{"ML-DSA-87", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 19}},
+		// NIST CSOR, FIPS 203 (ML-KEM).
+		{"ML-KEM-512", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 1}},
+		{"ML-KEM-768", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 2}},
+		{"ML-KEM-1024", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 3}},
+		// NIST CSOR, FIPS 205 (SLH-DSA), SHA2 and SHAKE small/fast sets.
+		{"SLH-DSA-SHA2-128s", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 20}},
+		{"SLH-DSA-SHA2-128f", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 21}},
+		{"SLH-DSA-SHA2-192s", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 22}},
+		{"SLH-DSA-SHA2-192f", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 23}},
+		{"SLH-DSA-SHA2-256s", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 24}},
+		{"SLH-DSA-SHA2-256f", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 25}},
+		{"SLH-DSA-SHAKE-128s", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 26}},
+		{"SLH-DSA-SHAKE-128f", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 27}},
+		{"SLH-DSA-SHAKE-192s", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 28}},
+		{"SLH-DSA-SHAKE-192f", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 29}},
+		{"SLH-DSA-SHAKE-256s", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 30}},
+		{"SLH-DSA-SHAKE-256f", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 31}},
+		// Composite signatures, provisional identifiers under the
+		// composite-algorithm arc of draft-ietf-lamps-pq-composite-sigs;
+		// subject to change until assignment is final.
+		{"MLDSA44-Ed25519", asn1.ObjectIdentifier{2, 16, 840, 1, 114027, 80, 8, 1, 60}},
+		{"MLDSA65-Ed25519", asn1.ObjectIdentifier{2, 16, 840, 1, 114027, 80, 8, 1, 61}},
+	} {
+		if err := RegisterAlgorithm(a); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Package rand is the library's source of randomness.
+//
+// Every function in the library that needs randomness either takes an
+// io.Reader per call or reads from Reader, the package-level source. The
+// per-call reader wins when both are possible: functions taking an
+// io.Reader fall back to Reader only when passed nil. Replacing Reader
+// redirects the whole library at once, for deterministic test runs (see
+// package drbg), for NIST-style DRBG compliance regimes, or to draw
+// entropy from an HSM. It defaults to crypto/rand.
+//
+// Reader is read without synchronization, so replace it during program
+// initialization, before other goroutines use the library.
+package rand
+
+import (
+	cryptoRand "crypto/rand"
+	"io"
+)
+
+// Reader is the library-wide source of randomness.
+var Reader io.Reader = cryptoRand.Reader
+
+// Read fills b from Reader, mirroring crypto/rand.Read.
+func Read(b []byte) (int, error) {
+	return io.ReadFull(Reader, b)
+}
+
+// Source returns r, or Reader when r is nil. Functions taking an
+// io.Reader for randomness pass it through Source, making nil select the
+// library-wide source.
+func Source(r io.Reader) io.Reader {
+	if r == nil {
+		return Reader
+	}
+
+	return r
+}
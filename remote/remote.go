@@ -0,0 +1,118 @@
+// Package remote adapts private keys held outside process memory —
+// PKCS#11 tokens, cloud KMS services, enclaves — to the sign and kem
+// interfaces. A remote key pairs the public half, which is local, with a
+// callback performing the private-key operation wherever the key lives.
+//
+// The key's Scheme method returns a thin wrapper around the real scheme
+// whose Sign or Decapsulate recognizes remote keys and invokes their
+// callback, and which delegates everything else. Protocol layers that
+// resolve the scheme through the key, like package adapters, therefore
+// work unchanged. Remote keys are not exportable: MarshalBinary fails.
+package remote
+
+import (
+	"crypto"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/adapters"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/sign"
+)
+
+// ErrNotExportable is returned by MarshalBinary on remote keys.
+var ErrNotExportable = errors.New("remote: key is not exportable")
+
+// SignFunc performs a signing operation on the remote key.
+type SignFunc func(message []byte, opts *sign.SignatureOpts) ([]byte, error)
+
+// DecapsulateFunc performs a decapsulation on the remote key.
+type DecapsulateFunc func(ct []byte) ([]byte, error)
+
+// NewSigner returns a sign.PrivateKey of the given scheme whose signing
+// operation is performed by signFn; pub is the key's public half.
+func NewSigner(scheme sign.Scheme, pub sign.PublicKey, signFn SignFunc) sign.PrivateKey {
+	return &signer{scheme: scheme, pub: pub, sign: signFn}
+}
+
+type signer struct {
+	scheme sign.Scheme
+	pub    sign.PublicKey
+	sign   SignFunc
+}
+
+func (s *signer) Scheme() sign.Scheme            { return sigScheme{s.scheme} }
+func (s *signer) Public() crypto.PublicKey       { return s.pub }
+func (s *signer) MarshalBinary() ([]byte, error) { return nil, ErrNotExportable }
+
+// Equal reports whether o is the same remote key object; two handles to
+// the same remote key are not recognized as equal.
+func (s *signer) Equal(o crypto.PrivateKey) bool { return s == o }
+
+// Sign implements crypto.Signer with the message-not-digest convention of
+// package adapters: opts must hash with crypto.Hash(0), and scheme
+// options travel in adapters.SignerOpts.
+func (s *signer) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var so *sign.SignatureOpts
+	switch o := opts.(type) {
+	case nil:
+	case adapters.SignerOpts:
+		so = &o.SignatureOpts
+	case *adapters.SignerOpts:
+		so = &o.SignatureOpts
+	default:
+		if o.HashFunc() != crypto.Hash(0) {
+			return nil, errors.New("remote: message must not be prehashed")
+		}
+	}
+
+	return s.sign(message, so)
+}
+
+// sigScheme wraps a sign.Scheme so Sign accepts remote keys.
+type sigScheme struct{ sign.Scheme }
+
+func (w sigScheme) Sign(sk sign.PrivateKey, message []byte, opts *sign.SignatureOpts) []byte {
+	if r, ok := sk.(*signer); ok {
+		sig, err := r.sign(message, opts)
+		if err != nil {
+			// The sign.Scheme interface leaves no room to return an
+			// error, matching the in-memory schemes, which only fail on
+			// misuse.
+			panic("remote: " + err.Error())
+		}
+		return sig
+	}
+
+	return w.Scheme.Sign(sk, message, opts)
+}
+
+// NewDecapsulator returns a kem.PrivateKey of the given scheme whose
+// decapsulation is performed by decapFn; pub is the key's public half.
+func NewDecapsulator(scheme kem.Scheme, pub kem.PublicKey, decapFn DecapsulateFunc) kem.PrivateKey {
+	return &decapsulator{scheme: scheme, pub: pub, decap: decapFn}
+}
+
+type decapsulator struct {
+	scheme kem.Scheme
+	pub    kem.PublicKey
+	decap  DecapsulateFunc
+}
+
+func (d *decapsulator) Scheme() kem.Scheme             { return kemScheme{d.scheme} }
+func (d *decapsulator) Public() kem.PublicKey          { return d.pub }
+func (d *decapsulator) MarshalBinary() ([]byte, error) { return nil, ErrNotExportable }
+
+// Equal reports whether o is the same remote key object.
+func (d *decapsulator) Equal(o kem.PrivateKey) bool { return d == kem.PrivateKey(o) }
+
+// kemScheme wraps a kem.Scheme so Decapsulate accepts remote keys.
+type kemScheme struct{ kem.Scheme }
+
+func (w kemScheme) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
+	if r, ok := sk.(*decapsulator); ok {
+		return r.decap(ct)
+	}
+
+	return w.Scheme.Decapsulate(sk, ct)
+}
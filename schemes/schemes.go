@@ -0,0 +1,234 @@
+// Package schemes is the top-level register of CIRCL algorithms:
+// configuration-driven applications resolve a KEM, signature scheme, or
+// group from a string, an OID, or a numeric protocol codepoint, and can
+// enumerate what is available. Scheme packages register themselves at
+// init; nothing is linked in unless its package is imported, so a binary
+// only carries the algorithms it uses.
+//
+// Names are matched case-insensitively. Codepoints are scoped per kind,
+// since e.g. TLS assigns signature schemes and named groups from separate
+// spaces.
+package schemes
+
+import (
+	"encoding/asn1"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/sign"
+)
+
+// Kind says what a registry entry is.
+type Kind int
+
+const (
+	KindKEM Kind = iota + 1
+	KindSignature
+	KindGroup
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindKEM:
+		return "kem"
+	case KindSignature:
+		return "signature"
+	case KindGroup:
+		return "group"
+	}
+
+	return "unknown"
+}
+
+// Entry describes one registered algorithm. Exactly one of KEM, Signature,
+// and Group is non-nil, matching Kind.
+type Entry struct {
+	Kind Kind
+	// Name is the canonical name; Aliases resolve to the same entry.
+	Name    string
+	Aliases []string
+	// OID is the object identifier, when one is assigned.
+	OID asn1.ObjectIdentifier
+	// Codepoint is the numeric protocol identifier (e.g. a TLS
+	// codepoint), when one is assigned; zero means none.
+	Codepoint uint
+
+	KEM       kem.Scheme
+	Signature sign.Scheme
+	Group     group.Group
+}
+
+var (
+	mu          sync.RWMutex
+	byName      = make(map[string]*Entry)
+	byOID       = make(map[string]*Entry)
+	byCodepoint = make(map[Kind]map[uint]*Entry)
+)
+
+// Register adds an entry. It fails when the entry is malformed or any of
+// its name, aliases, OID, or codepoint is already taken.
+func Register(e Entry) error {
+	ok := e.Name != "" &&
+		(e.Kind == KindKEM) == (e.KEM != nil) &&
+		(e.Kind == KindSignature) == (e.Signature != nil) &&
+		(e.Kind == KindGroup) == (e.Group != nil)
+	if !ok {
+		return errors.New("schemes: entry kind and scheme disagree")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	names := append([]string{e.Name}, e.Aliases...)
+	for _, n := range names {
+		if _, dup := byName[strings.ToLower(n)]; dup {
+			return errors.New("schemes: name already registered: " + n)
+		}
+	}
+	if len(e.OID) != 0 {
+		if _, dup := byOID[e.OID.String()]; dup {
+			return errors.New("schemes: OID already registered: " + e.OID.String())
+		}
+	}
+	if e.Codepoint != 0 {
+		if _, dup := byCodepoint[e.Kind][e.Codepoint]; dup {
+			return errors.New("schemes: codepoint already registered")
+		}
+	}
+
+	ent := &e
+	for _, n := range names {
+		byName[strings.ToLower(n)] = ent
+	}
+	if len(e.OID) != 0 {
+		byOID[e.OID.String()] = ent
+	}
+	if e.Codepoint != 0 {
+		if byCodepoint[e.Kind] == nil {
+			byCodepoint[e.Kind] = make(map[uint]*Entry)
+		}
+		byCodepoint[e.Kind][e.Codepoint] = ent
+	}
+
+	return nil
+}
+
+// ByName resolves a canonical name or alias, case-insensitively.
+func ByName(name string) (Entry, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	e, ok := byName[strings.ToLower(name)]
+	if !ok {
+		return Entry{}, false
+	}
+
+	return *e, true
+}
+
+// ByOID resolves an object identifier.
+func ByOID(oid asn1.ObjectIdentifier) (Entry, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	e, ok := byOID[oid.String()]
+	if !ok {
+		return Entry{}, false
+	}
+
+	return *e, true
+}
+
+// ByCodepoint resolves a protocol codepoint within one kind.
+func ByCodepoint(kind Kind, cp uint) (Entry, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	e, ok := byCodepoint[kind][cp]
+	if !ok {
+		return Entry{}, false
+	}
+
+	return *e, true
+}
+
+// KEMByName resolves a name to a KEM, or nil.
+func KEMByName(name string) kem.Scheme {
+	if e, ok := ByName(name); ok && e.Kind == KindKEM {
+		return e.KEM
+	}
+
+	return nil
+}
+
+// SignatureByName resolves a name to a signature scheme, or nil.
+func SignatureByName(name string) sign.Scheme {
+	if e, ok := ByName(name); ok && e.Kind == KindSignature {
+		return e.Signature
+	}
+
+	return nil
+}
+
+// KEMByOID resolves an object identifier to a KEM, or nil.
+func KEMByOID(oid asn1.ObjectIdentifier) kem.Scheme {
+	if e, ok := ByOID(oid); ok && e.Kind == KindKEM {
+		return e.KEM
+	}
+
+	return nil
+}
+
+// SignatureByOID resolves an object identifier to a signature scheme,
+// or nil.
+func SignatureByOID(oid asn1.ObjectIdentifier) sign.Scheme {
+	if e, ok := ByOID(oid); ok && e.Kind == KindSignature {
+		return e.Signature
+	}
+
+	return nil
+}
+
+// GroupByName resolves a name to a group, or nil.
+func GroupByName(name string) group.Group {
+	if e, ok := ByName(name); ok && e.Kind == KindGroup {
+		return e.Group
+	}
+
+	return nil
+}
+
+// ListSchemes returns the canonical names of the registered entries of
+// one kind, sorted. A zero kind lists everything.
+func ListSchemes(kind Kind) []string {
+	entries := All(kind)
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name
+	}
+
+	return names
+}
+
+// All enumerates the registered entries, sorted by kind then name. A zero
+// kind enumerates everything.
+func All(kind Kind) []Entry {
+	mu.RLock()
+	seen := make(map[*Entry]bool)
+	var out []Entry
+	for _, e := range byName {
+		if seen[e] || (kind != 0 && e.Kind != kind) {
+			continue
+		}
+		seen[e] = true
+		out = append(out, *e)
+	}
+	mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Name < out[j].Name
+	})
+
+	return out
+}
@@ -0,0 +1,225 @@
+package schemes_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cloudflare/circl/schemes"
+	"github.com/cloudflare/circl/sign"
+
+	// Populate the registry.
+	_ "github.com/cloudflare/circl/kem/frodo1344"
+	_ "github.com/cloudflare/circl/kem/frodo640"
+	_ "github.com/cloudflare/circl/kem/frodo976"
+	_ "github.com/cloudflare/circl/kem/hqc"
+	_ "github.com/cloudflare/circl/kem/hybrid"
+	_ "github.com/cloudflare/circl/kem/mlkem"
+	_ "github.com/cloudflare/circl/kem/sntrup761"
+	_ "github.com/cloudflare/circl/sign/hybrid"
+	_ "github.com/cloudflare/circl/sign/mldsa"
+	_ "github.com/cloudflare/circl/sign/slhdsa"
+)
+
+func TestRegistry(t *testing.T) {
+	entries := schemes.All(0)
+	if len(entries) == 0 {
+		t.Fatal("empty registry")
+	}
+	for _, e := range entries {
+		got, ok := schemes.ByName(strings.ToUpper(e.Name))
+		if !ok || got.Name != e.Name {
+			t.Errorf("%s: not resolvable by name", e.Name)
+		}
+		for _, a := range e.Aliases {
+			if got, ok = schemes.ByName(a); !ok || got.Name != e.Name {
+				t.Errorf("%s: alias %s does not resolve", e.Name, a)
+			}
+		}
+		if len(e.OID) != 0 {
+			if got, ok = schemes.ByOID(e.OID); !ok || got.Name != e.Name {
+				t.Errorf("%s: OID %v does not resolve", e.Name, e.OID)
+			}
+		}
+		if e.Codepoint != 0 {
+			if got, ok = schemes.ByCodepoint(e.Kind, e.Codepoint); !ok || got.Name != e.Name {
+				t.Errorf("%s: codepoint %d does not resolve", e.Name, e.Codepoint)
+			}
+		}
+		switch e.Kind {
+		case schemes.KindKEM:
+			if schemes.KEMByName(e.Name) == nil || schemes.SignatureByName(e.Name) != nil {
+				t.Errorf("%s: wrong kind resolution", e.Name)
+			}
+		case schemes.KindSignature:
+			if schemes.SignatureByName(e.Name) == nil || schemes.KEMByName(e.Name) != nil {
+				t.Errorf("%s: wrong kind resolution", e.Name)
+			}
+		}
+	}
+	if schemes.KEMByName("no-such-scheme") != nil {
+		t.Error("unknown name resolves")
+	}
+}
+
+func TestKEMs(t *testing.T) {
+	for _, e := range schemes.All(schemes.KindKEM) {
+		sch := e.KEM
+		t.Run(sch.Name(), func(t *testing.T) {
+			pk, sk, err := sch.GenerateKeyPair()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ppk, err := pk.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ppk) != sch.PublicKeySize() {
+				t.Fatal("wrong public key size")
+			}
+			pk2, err := sch.UnmarshalBinaryPublicKey(ppk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pk.Equal(pk2) {
+				t.Fatal("public key does not roundtrip")
+			}
+
+			psk, err := sk.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(psk) != sch.PrivateKeySize() {
+				t.Fatal("wrong private key size")
+			}
+			sk2, err := sch.UnmarshalBinaryPrivateKey(psk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sk.Equal(sk2) {
+				t.Fatal("private key does not roundtrip")
+			}
+
+			ct, ss, err := sch.Encapsulate(pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ct) != sch.CiphertextSize() || len(ss) != sch.SharedKeySize() {
+				t.Fatal("wrong ciphertext or shared key size")
+			}
+			ss2, err := sch.Decapsulate(sk2, ct)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(ss, ss2) {
+				t.Fatal("shared keys disagree")
+			}
+
+			// A tampered ciphertext must not decapsulate to the same
+			// shared key, whether the scheme rejects explicitly or
+			// implicitly.
+			ct[0] ^= 1
+			if ss2, err = sch.Decapsulate(sk, ct); err == nil && bytes.Equal(ss, ss2) {
+				t.Fatal("tampered ciphertext decapsulates to the same key")
+			}
+			ct[0] ^= 1
+
+			seed := make([]byte, sch.SeedSize())
+			dpk, dsk := sch.DeriveKeyPair(seed)
+			dpk2, dsk2 := sch.DeriveKeyPair(seed)
+			if !dpk.Equal(dpk2) || !dsk.Equal(dsk2) {
+				t.Fatal("key derivation is not deterministic")
+			}
+
+			eseed := make([]byte, sch.EncapsulationSeedSize())
+			ct, ss, err = sch.EncapsulateDeterministically(dpk, eseed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ct2, ss2, err := sch.EncapsulateDeterministically(dpk2, eseed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(ct, ct2) || !bytes.Equal(ss, ss2) {
+				t.Fatal("encapsulation is not deterministic")
+			}
+		})
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	for _, e := range schemes.All(schemes.KindSignature) {
+		sch := e.Signature
+		t.Run(sch.Name(), func(t *testing.T) {
+			// The small variants of SLH-DSA trade signing time for
+			// signature size; their roundtrips dominate the suite.
+			if testing.Short() && strings.HasPrefix(sch.Name(), "SLH-DSA") &&
+				strings.HasSuffix(sch.Name(), "s") {
+				t.Skip("slow scheme")
+			}
+
+			seed := make([]byte, sch.SeedSize())
+			pk, sk := sch.DeriveKey(seed)
+			pk2, sk2 := sch.DeriveKey(seed)
+			if !pk.Equal(pk2) || !sk.Equal(sk2) {
+				t.Fatal("key derivation is not deterministic")
+			}
+
+			ppk, err := pk.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ppk) != sch.PublicKeySize() {
+				t.Fatal("wrong public key size")
+			}
+			upk, err := sch.UnmarshalBinaryPublicKey(ppk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pk.Equal(upk) {
+				t.Fatal("public key does not roundtrip")
+			}
+
+			psk, err := sk.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(psk) != sch.PrivateKeySize() {
+				t.Fatal("wrong private key size")
+			}
+			usk, err := sch.UnmarshalBinaryPrivateKey(psk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sk.Equal(usk) {
+				t.Fatal("private key does not roundtrip")
+			}
+
+			msg := []byte("The quick brown fox jumps over the lazy dog")
+			var opts *sign.SignatureOpts
+			if sch.SupportsContext() {
+				opts = &sign.SignatureOpts{Context: "schemes test"}
+			}
+			sig := sch.Sign(sk, msg, opts)
+			if len(sig) != sch.SignatureSize() {
+				t.Fatal("wrong signature size")
+			}
+			if !sch.Verify(upk, msg, sig, opts) {
+				t.Fatal("signature does not verify")
+			}
+			if sch.Verify(upk, msg[:len(msg)-1], sig, opts) {
+				t.Fatal("signature verifies a different message")
+			}
+			sig[0] ^= 1
+			if sch.Verify(upk, msg, sig, opts) {
+				t.Fatal("corrupted signature verifies")
+			}
+			sig[0] ^= 1
+			if sch.SupportsContext() &&
+				sch.Verify(upk, msg, sig, &sign.SignatureOpts{Context: "other"}) {
+				t.Fatal("signature verifies under a different context")
+			}
+		})
+	}
+}
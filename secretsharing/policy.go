@@ -0,0 +1,252 @@
+package secretsharing
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Policy describes a monotone access structure as a tree. An inner node is
+// satisfied when at least T of its children are satisfied, so T=1 expresses
+// an OR gate and T=len(Children) an AND gate. A leaf names the party that
+// holds the shares produced for it; the same party may appear in several
+// leaves, which is how weighted sharings are expressed.
+type Policy struct {
+	// T is the number of children required to satisfy an inner node.
+	// It is ignored for leaves.
+	T uint
+	// Children is non-empty for inner nodes and nil for leaves.
+	Children []Policy
+	// Party identifies the shareholder owning a leaf.
+	Party uint
+}
+
+// IsLeaf returns true if the node has no children.
+func (p *Policy) IsLeaf() bool { return len(p.Children) == 0 }
+
+func (p *Policy) isValid() bool {
+	if p.IsLeaf() {
+		return true
+	}
+	if p.T == 0 || p.T > uint(len(p.Children)) {
+		return false
+	}
+	for i := range p.Children {
+		if !p.Children[i].isValid() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Weighted returns a t-out-of-total-weight policy in which the i-th party
+// holds weights[i] leaves. A coalition can recover the secret when the sum
+// of its weights reaches t.
+func Weighted(t uint, weights []uint) Policy {
+	var leaves []Policy
+	for party, w := range weights {
+		for j := uint(0); j < w; j++ {
+			leaves = append(leaves, Policy{Party: uint(party)})
+		}
+	}
+
+	return Policy{T: t, Children: leaves}
+}
+
+// PolicyShare is the share of a secret produced for one leaf of a policy.
+type PolicyShare struct {
+	// Party is the shareholder owning the leaf.
+	Party uint
+	// Leaf is the position of the leaf in a left-to-right traversal of the
+	// policy tree, identifying the share during recovery.
+	Leaf uint
+	// Value is the share material itself.
+	Value group.Scalar
+}
+
+// SharePolicy splits a secret according to the access structure p. Inner
+// thresholds use Shamir sharing among the children; each leaf yields one
+// PolicyShare for its party. It panics if p is malformed, i.e., an inner
+// node requires more children than it has.
+func SharePolicy(rnd io.Reader, g group.Group, p Policy, secret group.Scalar) []PolicyShare {
+	rnd = rand.Source(rnd)
+	if !p.isValid() {
+		panic("secretsharing: invalid policy")
+	}
+
+	var out []PolicyShare
+	nextLeaf := uint(0)
+	shareNode(rnd, g, &p, secret, &nextLeaf, &out)
+
+	return out
+}
+
+func shareNode(rnd io.Reader, g group.Group, p *Policy, secret group.Scalar, nextLeaf *uint, out *[]PolicyShare) {
+	if p.IsLeaf() {
+		*out = append(*out, PolicyShare{Party: p.Party, Leaf: *nextLeaf, Value: secret.Copy()})
+		*nextLeaf++
+		return
+	}
+
+	ss := New(rnd, p.T-1, secret)
+	for i := range p.Children {
+		id := g.NewScalar()
+		id.SetUint64(uint64(i) + 1)
+		shareNode(rnd, g, &p.Children[i], ss.ShareWithID(id).Value, nextLeaf, out)
+	}
+}
+
+// ErrPolicy is returned when the provided shares do not satisfy the policy.
+var ErrPolicy = errors.New("secretsharing: shares do not satisfy the policy")
+
+// RecoverPolicy rebuilds the secret from shares of an authorized coalition.
+// It returns ErrPolicy if the shares present do not satisfy p.
+func RecoverPolicy(g group.Group, p Policy, shares []PolicyShare) (group.Scalar, error) {
+	if !p.isValid() {
+		panic("secretsharing: invalid policy")
+	}
+
+	byLeaf := make(map[uint]group.Scalar)
+	for i := range shares {
+		byLeaf[shares[i].Leaf] = shares[i].Value
+	}
+
+	nextLeaf := uint(0)
+	return recoverNode(g, &p, byLeaf, &nextLeaf)
+}
+
+func recoverNode(g group.Group, p *Policy, byLeaf map[uint]group.Scalar, nextLeaf *uint) (group.Scalar, error) {
+	if p.IsLeaf() {
+		v, ok := byLeaf[*nextLeaf]
+		*nextLeaf++
+		if !ok {
+			return nil, ErrPolicy
+		}
+		return v, nil
+	}
+
+	var got []Share
+	for i := range p.Children {
+		v, err := recoverNode(g, &p.Children[i], byLeaf, nextLeaf)
+		if err != nil {
+			continue
+		}
+		id := g.NewScalar()
+		id.SetUint64(uint64(i) + 1)
+		got = append(got, Share{ID: id, Value: v})
+	}
+	if uint(len(got)) < p.T {
+		return nil, ErrPolicy
+	}
+
+	return Recover(p.T-1, got[:p.T])
+}
+
+// MarshalBinary encodes the policy tree in a left-to-right traversal, so it
+// can be stored or transmitted alongside the shares.
+func (p *Policy) MarshalBinary() ([]byte, error) {
+	var b []byte
+	p.marshal(&b)
+	return b, nil
+}
+
+func (p *Policy) marshal(b *[]byte) {
+	*b = binary.BigEndian.AppendUint32(*b, uint32(len(p.Children)))
+	if p.IsLeaf() {
+		*b = binary.BigEndian.AppendUint32(*b, uint32(p.Party))
+		return
+	}
+	*b = binary.BigEndian.AppendUint32(*b, uint32(p.T))
+	for i := range p.Children {
+		p.Children[i].marshal(b)
+	}
+}
+
+// UnmarshalBinary decodes a policy tree produced by MarshalBinary.
+func (p *Policy) UnmarshalBinary(data []byte) error {
+	rest, err := p.unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("secretsharing: trailing bytes in policy encoding")
+	}
+
+	return nil
+}
+
+func (p *Policy) unmarshal(data []byte) ([]byte, error) {
+	errShort := errors.New("secretsharing: short policy encoding")
+	if len(data) < 8 {
+		return nil, errShort
+	}
+	n := binary.BigEndian.Uint32(data)
+	if n == 0 {
+		*p = Policy{Party: uint(binary.BigEndian.Uint32(data[4:]))}
+		return data[8:], nil
+	}
+
+	*p = Policy{T: uint(binary.BigEndian.Uint32(data[4:])), Children: make([]Policy, n)}
+	data = data[8:]
+	var err error
+	for i := range p.Children {
+		if data, err = p.Children[i].unmarshal(data); err != nil {
+			return nil, err
+		}
+	}
+	if !p.isValid() {
+		return nil, errors.New("secretsharing: invalid policy encoding")
+	}
+
+	return data, nil
+}
+
+// shareFormat is the version byte leading a serialized policy share.
+const shareFormat = 1
+
+// MarshalBinary encodes the share as a format byte, the party and leaf
+// identifiers, and the share scalar.
+func (ps *PolicyShare) MarshalBinary() ([]byte, error) {
+	v, err := ps.Value.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	out := []byte{shareFormat}
+	out = binary.AppendUvarint(out, uint64(ps.Party))
+	out = binary.AppendUvarint(out, uint64(ps.Leaf))
+
+	return append(out, v...), nil
+}
+
+// UnmarshalPolicyShare decodes a share produced by
+// PolicyShare.MarshalBinary over the group g, rejecting trailing data.
+func UnmarshalPolicyShare(g group.Group, data []byte) (PolicyShare, error) {
+	errMalformed := errors.New("secretsharing: malformed policy share")
+	if len(data) == 0 || data[0] != shareFormat {
+		return PolicyShare{}, errMalformed
+	}
+	in := data[1:]
+	party, k := binary.Uvarint(in)
+	if k <= 0 {
+		return PolicyShare{}, errMalformed
+	}
+	in = in[k:]
+	leaf, k := binary.Uvarint(in)
+	if k <= 0 {
+		return PolicyShare{}, errMalformed
+	}
+	in = in[k:]
+	if len(in) != int(g.Params().ScalarLength) {
+		return PolicyShare{}, errMalformed
+	}
+	v := g.NewScalar()
+	if err := v.UnmarshalBinary(in); err != nil {
+		return PolicyShare{}, errMalformed
+	}
+
+	return PolicyShare{Party: uint(party), Leaf: uint(leaf), Value: v}, nil
+}
@@ -0,0 +1,75 @@
+package secretsharing
+
+import (
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Refresh allows a set of shareholders to proactively re-randomize their
+// shares of a secret without changing (or reconstructing) the secret itself.
+// Each shareholder acts as a dealer of a fresh t-out-of-n sharing of zero,
+// distributes one delta share to every other shareholder, and broadcasts a
+// Feldman commitment to the zero polynomial. Once a shareholder has verified
+// the deltas received from all dealers, it adds them to its share. The sum of
+// the refreshed shares still interpolates to the original secret, while any
+// set of old shares smaller than the threshold becomes useless.
+type Refresh struct {
+	ss SecretSharing
+}
+
+// NewRefresh creates a zero sharing with threshold t over the group g, to be
+// dealt by one shareholder during a refresh round. It panics if t is zero.
+func NewRefresh(rnd io.Reader, g group.Group, t uint) Refresh {
+	if t == 0 {
+		panic("secretsharing: refreshing requires a threshold above zero")
+	}
+	rnd = rand.Source(rnd)
+	return Refresh{New(rnd, t, g.NewScalar())}
+}
+
+// Share produces n delta shares of zero, one for each shareholder.
+func (r Refresh) Share(n uint) []Share { return r.ss.Share(n) }
+
+// ShareWithID produces the delta share for the shareholder with identifier id.
+func (r Refresh) ShareWithID(id group.Scalar) Share { return r.ss.ShareWithID(id) }
+
+// Commitment returns a Feldman commitment to the zero polynomial, to be
+// broadcast to all shareholders. Its first entry commits to the shared value
+// and is always the identity element of the group.
+func (r Refresh) Commitment() []group.Element { return r.ss.CommitSecret() }
+
+// VerifyRefresh returns true if the delta share s is consistent with the
+// commitment c broadcast by its dealer and c is a commitment to zero, i.e.,
+// applying the delta does not change the shared secret.
+func VerifyRefresh(t uint, s Share, c []group.Element) bool {
+	if len(c) == 0 || !c[0].IsIdentity() {
+		return false
+	}
+
+	return Verify(t, s, c)
+}
+
+// ErrRefreshID is returned when combining delta shares whose identifiers do
+// not match the share being refreshed.
+var ErrRefreshID = errors.New("secretsharing: mismatched share identifier during refresh")
+
+// Refresh adds the verified delta shares to the share s, producing the
+// re-randomized share for the next epoch. All deltas must carry the same
+// identifier as s; the caller must have verified each delta with
+// VerifyRefresh beforehand.
+func (s *Share) Refresh(deltas ...Share) error {
+	for i := range deltas {
+		if !s.ID.IsEqual(deltas[i].ID) {
+			return ErrRefreshID
+		}
+	}
+
+	for i := range deltas {
+		s.Value.Add(s.Value, deltas[i].Value)
+	}
+
+	return nil
+}
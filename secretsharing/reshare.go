@@ -0,0 +1,102 @@
+package secretsharing
+
+import (
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Reshare converts a (t,n) sharing of a secret into a (t',n') sharing held by
+// a possibly different committee, without ever reconstructing the secret.
+// Each member of the old committee deals a fresh t' sharing of its own share
+// to the new committee and broadcasts a Feldman commitment to it. A new
+// member verifies the subshare received from every dealer, and combines the
+// subshares of any t+1 dealers, weighted by the dealers' Lagrange
+// coefficients, into its share of the unchanged secret.
+type Reshare struct {
+	ss SecretSharing
+}
+
+// NewReshare prepares the sharing dealt by one old shareholder: a fresh
+// sharing of its share s with the new threshold newT.
+func NewReshare(rnd io.Reader, newT uint, s Share) Reshare {
+	rnd = rand.Source(rnd)
+	return Reshare{New(rnd, newT, s.Value)}
+}
+
+// Share produces the subshares for a new committee of n members.
+func (r Reshare) Share(n uint) []Share { return r.ss.Share(n) }
+
+// ShareWithID produces the subshare for the new member with identifier id.
+func (r Reshare) ShareWithID(id group.Scalar) Share { return r.ss.ShareWithID(id) }
+
+// Commitment returns a Feldman commitment to the dealt polynomial. Its first
+// entry is a commitment to the dealer's old share, which verifiers check
+// against the commitment of the old sharing.
+func (r Reshare) Commitment() []group.Element { return r.ss.CommitSecret() }
+
+// VerifyReshare returns true if the subshare s received from a dealer is
+// consistent with the dealer's broadcast commitment c, and c indeed extends
+// the old sharing: its first entry must equal the dealer's public share
+// oldPub, as derived from the commitment of the old sharing.
+func VerifyReshare(newT uint, s Share, c []group.Element, oldPub group.Element) bool {
+	if len(c) == 0 || !c[0].IsEqual(oldPub) {
+		return false
+	}
+
+	return Verify(newT, s, c)
+}
+
+// NewShareFromReshare combines verified subshares into the new member's share
+// of the secret. All subshares must carry the new member's identifier, and
+// dealerIDs lists the old identifiers of their dealers, in the same order.
+// Exactly oldT+1 subshares from distinct dealers are required, where oldT is
+// the threshold of the old sharing.
+func NewShareFromReshare(oldT uint, dealerIDs []group.Scalar, subshares []Share) (Share, error) {
+	if len(dealerIDs) != len(subshares) || uint(len(subshares)) != oldT+1 {
+		return Share{}, errors.New("secretsharing: resharing needs oldT+1 subshares")
+	}
+	id := subshares[0].ID
+	for i := range subshares {
+		if !subshares[i].ID.IsEqual(id) {
+			return Share{}, ErrRefreshID
+		}
+	}
+
+	value := id.Group().NewScalar()
+	for i := range subshares {
+		l, err := lagrangeAtZero(dealerIDs, i)
+		if err != nil {
+			return Share{}, err
+		}
+		l.Mul(l, subshares[i].Value)
+		value.Add(value, l)
+	}
+
+	return Share{ID: id.Copy(), Value: value}, nil
+}
+
+func lagrangeAtZero(ids []group.Scalar, i int) (group.Scalar, error) {
+	g := ids[i].Group()
+	num := g.NewScalar()
+	num.SetUint64(1)
+	den := g.NewScalar()
+	den.SetUint64(1)
+	d := g.NewScalar()
+	for j := range ids {
+		if j == i {
+			continue
+		}
+		if ids[j].IsEqual(ids[i]) {
+			return nil, errors.New("secretsharing: duplicated dealer identifier")
+		}
+		num.Mul(num, ids[j])
+		d.Sub(ids[j], ids[i])
+		den.Mul(den, d)
+	}
+	num.Mul(num, den.Inv(den))
+
+	return num, nil
+}
@@ -0,0 +1,160 @@
+// Package secretsharing provides Shamir secret sharing with Feldman
+// commitments over prime-order groups.
+//
+// A dealer splits a secret scalar into shares such that any t+1 of them
+// recover the secret, while t or fewer reveal nothing about it. Shares
+// are points on a random degree-t polynomial whose constant term is the
+// secret; recovery is Lagrange interpolation at zero. The dealer may
+// additionally publish a Feldman commitment to the polynomial, which
+// lets every shareholder verify its share without interaction.
+//
+// On top of the basic scheme, the package implements proactive share
+// refreshing (Refresh), resharing to a new committee and threshold
+// (Reshare), and sharing under monotone access structures (Policy).
+//
+// References:
+//
+//	Shamir. "How to share a secret". https://doi.org/10.1145/359168.359176
+//	Feldman. "A practical scheme for non-interactive verifiable secret
+//	sharing". https://doi.org/10.1109/SFCS.1987.4
+package secretsharing
+
+import (
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Share is one share of a secret: the evaluation of the dealer's
+// polynomial at the point ID.
+type Share struct {
+	// ID is the (nonzero) evaluation point identifying the shareholder.
+	ID group.Scalar
+	// Value is the share of the secret itself.
+	Value group.Scalar
+}
+
+// SecretSharing is a t-out-of-n sharing of a secret dealt by one party.
+// The threshold t is the degree of the underlying polynomial, so t+1
+// shares are necessary and sufficient to recover the secret.
+type SecretSharing struct {
+	g group.Group
+	// poly holds the polynomial coefficients in ascending order;
+	// poly[0] is the secret and len(poly) is t+1.
+	poly []group.Scalar
+}
+
+// New creates a sharing of the secret with threshold t over the secret's
+// group, drawing the random coefficients from rnd (the system source when
+// rnd is nil). A threshold of zero is allowed and produces the trivial
+// sharing in which every share equals the secret.
+func New(rnd io.Reader, t uint, secret group.Scalar) SecretSharing {
+	rnd = rand.Source(rnd)
+	g := secret.Group()
+	poly := make([]group.Scalar, t+1)
+	poly[0] = secret.Copy()
+	for i := 1; i < len(poly); i++ {
+		poly[i] = g.RandomScalar(rnd)
+	}
+
+	return SecretSharing{g: g, poly: poly}
+}
+
+// evaluate returns the polynomial evaluated at x, by Horner's rule.
+func (ss SecretSharing) evaluate(x group.Scalar) group.Scalar {
+	y := ss.g.NewScalar()
+	for i := len(ss.poly) - 1; i >= 0; i-- {
+		y.Mul(y, x)
+		y.Add(y, ss.poly[i])
+	}
+
+	return y
+}
+
+// Share produces n shares with identifiers 1 through n. It panics if n
+// is not greater than the threshold, as fewer shares than t+1 could
+// never recover the secret.
+func (ss SecretSharing) Share(n uint) []Share {
+	if n < uint(len(ss.poly)) {
+		panic("secretsharing: number of shares must be above the threshold")
+	}
+
+	shares := make([]Share, n)
+	for i := range shares {
+		id := ss.g.NewScalar()
+		id.SetUint64(uint64(i) + 1)
+		shares[i] = ss.ShareWithID(id)
+	}
+
+	return shares
+}
+
+// ShareWithID produces the share for the holder identified by id. It
+// panics if id is zero, since that share would be the secret itself.
+func (ss SecretSharing) ShareWithID(id group.Scalar) Share {
+	if id.IsZero() {
+		panic("secretsharing: the identifier of a share cannot be zero")
+	}
+
+	return Share{ID: id.Copy(), Value: ss.evaluate(id)}
+}
+
+// CommitSecret returns a Feldman commitment to the dealt polynomial:
+// each coefficient multiplied by the group generator, in ascending
+// order. The first entry is a commitment to the secret, and the whole
+// vector lets anyone verify a share with Verify.
+func (ss SecretSharing) CommitSecret() []group.Element {
+	c := make([]group.Element, len(ss.poly))
+	for i := range c {
+		c[i] = ss.g.NewElement().MulGen(ss.poly[i])
+	}
+
+	return c
+}
+
+// Verify returns true if the share s is consistent with the Feldman
+// commitment c of a sharing with threshold t, i.e., the share lies on
+// the committed polynomial.
+func Verify(t uint, s Share, c []group.Element) bool {
+	if uint(len(c)) != t+1 {
+		return false
+	}
+
+	g := s.ID.Group()
+	pol := g.Identity()
+	for i := len(c) - 1; i >= 0; i-- {
+		pol.Mul(pol, s.ID)
+		pol.Add(pol, c[i])
+	}
+
+	return pol.IsEqual(g.NewElement().MulGen(s.Value))
+}
+
+// Recover rebuilds the secret of a sharing with threshold t by Lagrange
+// interpolation. At least t+1 shares with distinct identifiers are
+// required; passing more is allowed provided all of them stem from the
+// same sharing.
+func Recover(t uint, shares []Share) (group.Scalar, error) {
+	if uint(len(shares)) < t+1 {
+		return nil, errors.New("secretsharing: number of shares must be above the threshold")
+	}
+
+	ids := make([]group.Scalar, len(shares))
+	for i := range shares {
+		ids[i] = shares[i].ID
+	}
+
+	secret := ids[0].Group().NewScalar()
+	for i := range shares {
+		l, err := lagrangeAtZero(ids, i)
+		if err != nil {
+			return nil, err
+		}
+		l.Mul(l, shares[i].Value)
+		secret.Add(secret, l)
+	}
+
+	return secret, nil
+}
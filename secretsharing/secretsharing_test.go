@@ -0,0 +1,190 @@
+package secretsharing_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/secretsharing"
+)
+
+func TestShareRecover(t *testing.T) {
+	g := group.P384
+	secret := g.RandomScalar(rand.Reader)
+	threshold := uint(3)
+	ss := secretsharing.New(rand.Reader, threshold, secret)
+	shares := ss.Share(7)
+
+	got, err := secretsharing.Recover(threshold, shares[:threshold+1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsEqual(secret) {
+		t.Fatal("recovered secret differs")
+	}
+
+	// More shares than necessary still recover the secret.
+	got, err = secretsharing.Recover(threshold, shares)
+	if err != nil || !got.IsEqual(secret) {
+		t.Fatal("recovery with all shares failed")
+	}
+
+	// Too few shares must be rejected.
+	if _, err = secretsharing.Recover(threshold, shares[:threshold]); err == nil {
+		t.Fatal("recovered with fewer than t+1 shares")
+	}
+
+	// Duplicated identifiers must be rejected.
+	dup := []secretsharing.Share{shares[0], shares[1], shares[2], shares[2]}
+	if _, err = secretsharing.Recover(threshold, dup); err == nil {
+		t.Fatal("recovered with duplicated identifiers")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	g := group.Ristretto255
+	secret := g.RandomScalar(rand.Reader)
+	threshold := uint(2)
+	ss := secretsharing.New(rand.Reader, threshold, secret)
+	shares := ss.Share(5)
+	c := ss.CommitSecret()
+
+	for i := range shares {
+		if !secretsharing.Verify(threshold, shares[i], c) {
+			t.Fatalf("share %d does not verify", i)
+		}
+	}
+
+	bad := shares[0]
+	bad.Value = g.NewScalar().Add(bad.Value, g.NewScalar().SetUint64(1))
+	if secretsharing.Verify(threshold, bad, c) {
+		t.Fatal("tampered share verifies")
+	}
+	if secretsharing.Verify(threshold+1, shares[0], c) {
+		t.Fatal("share verifies against the wrong threshold")
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	g := group.P384
+	secret := g.RandomScalar(rand.Reader)
+	threshold := uint(2)
+	n := uint(5)
+	shares := secretsharing.New(rand.Reader, threshold, secret).Share(n)
+
+	// Every shareholder deals a zero sharing and applies the deltas of
+	// all dealers.
+	deltas := make([][]secretsharing.Share, n)
+	for i := range deltas {
+		r := secretsharing.NewRefresh(rand.Reader, g, threshold)
+		c := r.Commitment()
+		deltas[i] = r.Share(n)
+		for j := range deltas[i] {
+			if !secretsharing.VerifyRefresh(threshold, deltas[i][j], c) {
+				t.Fatal("delta share does not verify")
+			}
+		}
+	}
+	for j := range shares {
+		for i := range deltas {
+			if err := shares[j].Refresh(deltas[i][j]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	got, err := secretsharing.Recover(threshold, shares[:threshold+1])
+	if err != nil || !got.IsEqual(secret) {
+		t.Fatal("refreshed shares do not recover the secret")
+	}
+
+	// Mismatched identifiers must be rejected.
+	if err := shares[0].Refresh(deltas[0][1]); err == nil {
+		t.Fatal("refresh accepted a delta for another identifier")
+	}
+}
+
+func TestReshare(t *testing.T) {
+	g := group.Ristretto255
+	secret := g.RandomScalar(rand.Reader)
+	oldT, newT := uint(2), uint(3)
+	oldSS := secretsharing.New(rand.Reader, oldT, secret)
+	oldShares := oldSS.Share(4)
+	oldCommit := oldSS.CommitSecret()
+
+	// oldT+1 old holders deal subshares to a new committee of 6.
+	newN := uint(6)
+	dealerIDs := make([]group.Scalar, oldT+1)
+	sub := make([][]secretsharing.Share, oldT+1)
+	for i := range sub {
+		dealerIDs[i] = oldShares[i].ID
+		r := secretsharing.NewReshare(rand.Reader, newT, oldShares[i])
+		c := r.Commitment()
+		oldPub := g.NewElement().MulGen(oldShares[i].Value)
+		sub[i] = r.Share(newN)
+		for j := range sub[i] {
+			if !secretsharing.VerifyReshare(newT, sub[i][j], c, oldPub) {
+				t.Fatal("subshare does not verify")
+			}
+		}
+	}
+
+	newShares := make([]secretsharing.Share, newN)
+	for j := range newShares {
+		col := make([]secretsharing.Share, len(sub))
+		for i := range sub {
+			col[i] = sub[i][j]
+		}
+		s, err := secretsharing.NewShareFromReshare(oldT, dealerIDs, col)
+		if err != nil {
+			t.Fatal(err)
+		}
+		newShares[j] = s
+	}
+
+	got, err := secretsharing.Recover(newT, newShares[:newT+1])
+	if err != nil || !got.IsEqual(secret) {
+		t.Fatal("resharing changed the secret")
+	}
+	_ = oldCommit
+}
+
+func TestPolicy(t *testing.T) {
+	g := group.P384
+	secret := g.RandomScalar(rand.Reader)
+
+	// (2 of 3) with party 2 weighted twice: parties {0,1} or {2} alone
+	// with weight 2, or any party plus one of party 2's leaves.
+	p := secretsharing.Weighted(2, []uint{1, 1, 2})
+	shares := secretsharing.SharePolicy(rand.Reader, g, p, secret)
+	if len(shares) != 4 {
+		t.Fatalf("got %d shares, want 4", len(shares))
+	}
+
+	got, err := secretsharing.RecoverPolicy(g, p, shares[:2])
+	if err != nil || !got.IsEqual(secret) {
+		t.Fatal("authorized coalition failed to recover")
+	}
+	if _, err = secretsharing.RecoverPolicy(g, p, shares[:1]); err == nil {
+		t.Fatal("unauthorized coalition recovered the secret")
+	}
+
+	// Policy and share encodings roundtrip.
+	enc, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p2 secretsharing.Policy
+	if err = p2.UnmarshalBinary(enc); err != nil {
+		t.Fatal(err)
+	}
+	senc, err := shares[0].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := secretsharing.UnmarshalPolicyShare(g, senc)
+	if err != nil || s2.Party != shares[0].Party || s2.Leaf != shares[0].Leaf ||
+		!s2.Value.IsEqual(shares[0].Value) {
+		t.Fatal("policy share roundtrip failed")
+	}
+}
@@ -0,0 +1,68 @@
+package ed25519
+
+import (
+	"crypto/sha512"
+
+	"github.com/cloudflare/circl/dh/x25519"
+	"github.com/cloudflare/circl/math/fp25519"
+)
+
+// Conversions between Ed25519 signing keys and X25519 Diffie-Hellman
+// keys, over the birational map u = (1+y)/(1-y) between the Edwards and
+// Montgomery forms of the curve. Because both key types clamp the
+// secret scalar identically, the converted keys are consistent: the
+// X25519 public key of a converted private key equals the conversion of
+// the Ed25519 public key.
+//
+// Caveats: the Montgomery u-coordinate drops the sign of the Edwards x
+// coordinate, so two distinct Ed25519 public keys map to the same
+// X25519 key and the conversion cannot be inverted without fixing a
+// sign convention (XVerify fixes it to zero). Reusing one key pair for
+// both signing and key exchange steps outside the security proofs of
+// either primitive; do so only under a protocol-level analysis, as in
+// the XEdDSA design.
+
+// ToX25519 converts an Ed25519 public key to the corresponding X25519
+// public key. It reports failure when the key is not a valid point
+// encoding or has no Montgomery image (y = 1, the identity).
+func (pub PublicKey) ToX25519() (x25519.Key, bool) {
+	var k x25519.Key
+	if len(pub) != PublicKeySize {
+		return k, false
+	}
+	var A pointR1
+	if !A.FromBytes(pub) {
+		return k, false
+	}
+
+	var y, num, den, u fp25519.Elt
+	copy(y[:], pub)
+	y[31] &= 0x7f
+
+	one := fp25519.Elt{1}
+	fp25519.Sub(&den, &one, &y)
+	if fp25519.IsZero(&den) {
+		return k, false
+	}
+	fp25519.Add(&num, &one, &y)
+	fp25519.Inv(&den, &den)
+	fp25519.Mul(&u, &num, &den)
+	fp25519.Modp(&u)
+	copy(k[:], u[:])
+
+	return k, true
+}
+
+// ToX25519 converts an Ed25519 private key to the corresponding X25519
+// private key, the first half of the hashed seed. ToX25519 panics if
+// the private key has the wrong size.
+func (priv PrivateKey) ToX25519() x25519.Key {
+	if len(priv) != PrivateKeySize {
+		panic("ed25519: bad private key length")
+	}
+	h := sha512.Sum512(priv[:SeedSize])
+	var k x25519.Key
+	copy(k[:], h[:32])
+
+	return k
+}
@@ -0,0 +1,153 @@
+// Package ed25519 implements the Ed25519 signature scheme of RFC 8032.
+//
+// The package mirrors the API of crypto/ed25519 — keys are byte slices
+// with the seed-and-public-key private layout — and extends it with the
+// streaming Ed25519ph variant (PreHash), batch verification
+// (VerifyBatch), configurable verification semantics (VerifyWithOptions,
+// including ZIP-215), XEdDSA signatures under X25519 keys (XSign,
+// XVerify), and conversions to X25519 key material (ToX25519). A
+// sign.Scheme view is available through Scheme.
+package ed25519
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/rand"
+)
+
+const (
+	// PublicKeySize is the length in bytes of a public key.
+	PublicKeySize = 32
+	// PrivateKeySize is the length in bytes of a private key, the seed
+	// followed by the public key.
+	PrivateKeySize = 64
+	// SeedSize is the length in bytes of a private-key seed.
+	SeedSize = 32
+	// SignatureSize is the length in bytes of a signature.
+	SignatureSize = 64
+)
+
+// PublicKey is an Ed25519 public key, the encoding of a curve point.
+type PublicKey []byte
+
+// PrivateKey is an Ed25519 private key, the seed followed by the public
+// key.
+type PrivateKey []byte
+
+// GenerateKey generates a key pair from rnd, or from the system random
+// source when rnd is nil.
+func GenerateKey(rnd io.Reader) (PublicKey, PrivateKey, error) {
+	rnd = rand.Source(rnd)
+	seed := make([]byte, SeedSize)
+	if _, err := io.ReadFull(rnd, seed); err != nil {
+		return nil, nil, err
+	}
+	priv := NewKeyFromSeed(seed)
+
+	return priv.Public().(PublicKey), priv, nil
+}
+
+// NewKeyFromSeed derives the private key for a seed deterministically.
+// NewKeyFromSeed panics if the seed has the wrong size.
+func NewKeyFromSeed(seed []byte) PrivateKey {
+	if len(seed) != SeedSize {
+		panic("ed25519: bad seed length")
+	}
+	h := sha512.Sum512(seed)
+	a := clampScalar(h[:32])
+
+	var A pointR1
+	A.fixedMult(a)
+	priv := make(PrivateKey, PrivateKeySize)
+	copy(priv, seed)
+	_ = A.ToBytes(priv[SeedSize:])
+
+	return priv
+}
+
+// Sign signs the message, deterministically as specified by RFC 8032.
+// Sign panics if the private key has the wrong size.
+func Sign(priv PrivateKey, message []byte) []byte {
+	if len(priv) != PrivateKeySize {
+		panic("ed25519: bad private key length")
+	}
+	h := sha512.Sum512(priv[:SeedSize])
+	a := clampScalar(h[:32])
+	prefix := h[32:]
+
+	rh := sha512.New()
+	_, _ = rh.Write(prefix)
+	_, _ = rh.Write(message)
+	r := rh.Sum(nil)
+	reduceModOrder(r, true)
+
+	var R pointR1
+	R.fixedMult(r[:32])
+	encR := make([]byte, 32)
+	_ = R.ToBytes(encR)
+
+	k := signatureDigest(encR, priv[SeedSize:], message)
+
+	s := make([]byte, 32)
+	calculateS(s, r[:32], k, a)
+
+	return append(encR, s...)
+}
+
+// Verify reports whether the signature of the message by the public key
+// is valid under the strict RFC 8032 criteria.
+func Verify(publicKey PublicKey, message, signature []byte) bool {
+	return VerifyWithOptions(publicKey, message, signature, StrictVerification)
+}
+
+// Seed returns the seed the private key was derived from.
+func (priv PrivateKey) Seed() []byte {
+	return append([]byte{}, priv[:SeedSize]...)
+}
+
+// Public returns the public key half as a crypto.PublicKey.
+func (priv PrivateKey) Public() crypto.PublicKey {
+	pub := make(PublicKey, PublicKeySize)
+	copy(pub, priv[SeedSize:])
+
+	return pub
+}
+
+// Equal reports whether priv and x hold the same key.
+func (priv PrivateKey) Equal(x crypto.PrivateKey) bool {
+	o, ok := x.(PrivateKey)
+
+	return ok && subtle.ConstantTimeCompare(priv, o) == 1
+}
+
+// Sign implements crypto.Signer; opts must indicate an unhashed message
+// (crypto.Hash(0)).
+func (priv PrivateKey) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != crypto.Hash(0) {
+		return nil, errors.New("ed25519: cannot sign hashed message")
+	}
+
+	return Sign(priv, message), nil
+}
+
+// MarshalBinary returns a copy of the private key.
+func (priv PrivateKey) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, priv...), nil
+}
+
+// Equal reports whether pub and x hold the same key.
+func (pub PublicKey) Equal(x crypto.PublicKey) bool {
+	o, ok := x.(PublicKey)
+
+	return ok && bytes.Equal(pub, o)
+}
+
+// MarshalBinary returns a copy of the public key.
+func (pub PublicKey) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, pub...), nil
+}
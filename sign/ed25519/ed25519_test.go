@@ -0,0 +1,256 @@
+package ed25519_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/dh/x25519"
+	"github.com/cloudflare/circl/sign/ed25519"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Test vectors from RFC 8032, Section 7.1.
+var rfc8032Vectors = []struct{ seed, pub, msg, sig string }{
+	{
+		"9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		"",
+		"e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e06522490155" +
+			"5fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b",
+	},
+	{
+		"4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+		"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		"72",
+		"92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da" +
+			"085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00",
+	},
+	{
+		"c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+		"fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+		"af82",
+		"6291d657deec24024827e69c3abe01a30ce548a284743a445e3680d7db5ac3ac" +
+			"18ff9b538d16f290ae67f760984dc6594a7c15e9716ed28dc027beceea1ec40a",
+	},
+}
+
+func TestRFC8032(t *testing.T) {
+	for i, v := range rfc8032Vectors {
+		seed := mustHex(t, v.seed)
+		msg := mustHex(t, v.msg)
+		wantPub := mustHex(t, v.pub)
+		wantSig := mustHex(t, v.sig)
+
+		priv := ed25519.NewKeyFromSeed(seed)
+		pub := priv.Public().(ed25519.PublicKey)
+		if !pub.Equal(ed25519.PublicKey(wantPub)) {
+			t.Errorf("vector %d: public key mismatch\ngot:  %x\nwant: %x", i, pub, wantPub)
+		}
+
+		sig := ed25519.Sign(priv, msg)
+		if hex.EncodeToString(sig) != hex.EncodeToString(wantSig) {
+			t.Errorf("vector %d: signature mismatch\ngot:  %x\nwant: %x", i, sig, wantSig)
+		}
+
+		if !ed25519.Verify(pub, msg, sig) {
+			t.Errorf("vector %d: signature does not verify", i)
+		}
+		sig[0] ^= 1
+		if ed25519.Verify(pub, msg, sig) {
+			t.Errorf("vector %d: corrupted signature verifies", i)
+		}
+	}
+}
+
+// Test vector from RFC 8032, Section 7.3 (Ed25519ph over "abc").
+func TestPreHashRFC8032(t *testing.T) {
+	seed := mustHex(t, "833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42")
+	wantPub := mustHex(t, "ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf")
+	wantSig := mustHex(t,
+		"98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae41"+
+			"31f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406")
+
+	priv := ed25519.NewKeyFromSeed(seed)
+	if !priv.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(wantPub)) {
+		t.Fatalf("public key mismatch")
+	}
+
+	ph := ed25519.NewPreHash("")
+	_, _ = ph.Write([]byte("abc"))
+	sig := ph.Sign(priv)
+	if hex.EncodeToString(sig) != hex.EncodeToString(wantSig) {
+		t.Fatalf("signature mismatch\ngot:  %x\nwant: %x", sig, wantSig)
+	}
+	if !ph.Verify(ed25519.PublicKey(wantPub), sig) {
+		t.Fatal("signature does not verify")
+	}
+	if ed25519.Verify(ed25519.PublicKey(wantPub), []byte("abc"), sig) {
+		t.Fatal("Ed25519ph signature verifies as plain Ed25519")
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	const n = 16
+	pubs := make([]ed25519.PublicKey, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := range pubs {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubs[i] = pub
+		msgs[i] = []byte{byte(i)}
+		sigs[i] = ed25519.Sign(priv, msgs[i])
+	}
+
+	if ok, failed := ed25519.VerifyBatch(pubs, msgs, sigs); !ok || failed != nil {
+		t.Fatalf("valid batch rejected, failed = %v", failed)
+	}
+
+	sigs[5][0] ^= 1
+	msgs[11] = []byte("tampered")
+	ok, failed := ed25519.VerifyBatch(pubs, msgs, sigs)
+	if ok {
+		t.Fatal("invalid batch accepted")
+	}
+	if len(failed) != 2 || failed[0] != 5 || failed[1] != 11 {
+		t.Fatalf("wrong failing indices: %v", failed)
+	}
+}
+
+func TestXEdDSA(t *testing.T) {
+	var pub, priv x25519.Key
+	if _, err := rand.Read(priv[:]); err != nil {
+		t.Fatal(err)
+	}
+	x25519.KeyGen(&pub, &priv)
+
+	msg := []byte("xeddsa test message")
+	sig := ed25519.XSign(&priv, msg, rand.Reader)
+	if !ed25519.XVerify(&pub, msg, sig) {
+		t.Fatal("signature does not verify")
+	}
+	if ed25519.XVerify(&pub, []byte("other message"), sig) {
+		t.Fatal("signature verifies wrong message")
+	}
+}
+
+func TestToX25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xPriv := priv.ToX25519()
+	xPub, ok := pub.ToX25519()
+	if !ok {
+		t.Fatal("public key conversion failed")
+	}
+	var want x25519.Key
+	x25519.KeyGen(&want, &xPriv)
+	if want != xPub {
+		t.Fatalf("converted keys disagree\ngot:  %x\nwant: %x", xPub, want)
+	}
+}
+
+func TestVerifyOptions(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("options test")
+	sig := ed25519.Sign(priv, msg)
+
+	if !ed25519.VerifyWithOptions(pub, msg, sig, ed25519.StrictVerification) {
+		t.Fatal("strict verification rejects a valid signature")
+	}
+	if !ed25519.VerifyWithOptions(pub, msg, sig, ed25519.ZIP215Verification) {
+		t.Fatal("ZIP-215 verification rejects a valid signature")
+	}
+
+	// A non-canonical s component must be rejected under every option
+	// combination. Adding the group order to s yields an equivalent but
+	// non-canonical scalar.
+	badS := orderPlusS(sig[32:])
+	badSig := append(append([]byte{}, sig[:32]...), badS...)
+	if ed25519.VerifyWithOptions(pub, msg, badSig, ed25519.ZIP215Verification) {
+		t.Fatal("non-canonical s accepted")
+	}
+}
+
+// orderPlusS returns s plus the group order as 32 little-endian bytes;
+// the sum fits since s is reduced and the order is below 2^253.
+func orderPlusS(s []byte) []byte {
+	order := [32]byte{
+		0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58,
+		0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10,
+	}
+	out := make([]byte, 32)
+	var c uint16
+	for i := range out {
+		c += uint16(s[i]) + uint16(order[i])
+		out[i] = byte(c)
+		c >>= 8
+	}
+	return out
+}
+
+func TestScheme(t *testing.T) {
+	sch := ed25519.Scheme()
+	if sch.Name() != "Ed25519" || sch.SupportsContext() {
+		t.Fatal("wrong scheme parameters")
+	}
+
+	seed := make([]byte, sch.SeedSize())
+	pub, priv := sch.DeriveKey(seed)
+	msg := []byte("scheme test")
+	sig := sch.Sign(priv, msg, nil)
+	if len(sig) != sch.SignatureSize() {
+		t.Fatal("wrong signature size")
+	}
+	if !sch.Verify(pub, msg, sig, nil) {
+		t.Fatal("signature does not verify")
+	}
+
+	pk, err := pub.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub2, err := sch.UnmarshalBinaryPublicKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Equal(pub2.(ed25519.PublicKey)) {
+		t.Fatal("public key does not roundtrip")
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	msg := make([]byte, 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ed25519.Sign(priv, msg)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	msg := make([]byte, 64)
+	sig := ed25519.Sign(priv, msg)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ed25519.Verify(pub, msg, sig)
+	}
+}
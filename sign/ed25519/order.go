@@ -0,0 +1,147 @@
+package ed25519
+
+import "math/bits"
+
+// This file implements arithmetic modulo the group order
+// L = 2^252 + 27742317777372353535851937790883648493 on little-endian
+// byte strings, in constant time. Values are held in four 64-bit words;
+// wide inputs are reduced by Horner folding, one conditional subtraction
+// of L per doubling, so no Barrett or Montgomery constants are needed.
+
+// ordElt is a scalar modulo L in little-endian 64-bit words.
+type ordElt [4]uint64
+
+// ordWords is the group order, derived from orderBytes.
+var ordWords ordElt
+
+func init() {
+	for i, b := range orderBytes {
+		ordWords[i/8] |= uint64(b) << (8 * (uint(i) & 7))
+	}
+}
+
+// ordCondSub subtracts L when the value is at least L, or
+// unconditionally when the carry flag of a preceding addition is set, in
+// constant time.
+func ordCondSub(w *ordElt, carry uint64) {
+	var d ordElt
+	var b uint64
+	for i := range w {
+		d[i], b = bits.Sub64(w[i], ordWords[i], b)
+	}
+	m := (b &^ carry) - 1
+	for i := range w {
+		w[i] = w[i]&^m | d[i]&m
+	}
+}
+
+// ordAdd adds x into z modulo L; the sum of the operands must be below
+// 2L, which holds whenever z is reduced and x is reduced or at most one
+// word wide.
+func ordAdd(z, x *ordElt) {
+	var c uint64
+	for i := range z {
+		z[i], c = bits.Add64(z[i], x[i], c)
+	}
+	ordCondSub(z, c)
+}
+
+// ordFold reduces a little-endian byte string of any length modulo L by
+// Horner folding.
+func ordFold(b []byte) (z ordElt) {
+	for i := len(b) - 1; i >= 0; i-- {
+		for j := 0; j < 8; j++ {
+			t := z
+			ordAdd(&z, &t)
+		}
+		ordAdd(&z, &ordElt{uint64(b[i])})
+	}
+
+	return
+}
+
+// ordMul multiplies two reduced scalars modulo L, folding the schoolbook
+// double-width product word by word.
+func ordMul(x, y *ordElt) (z ordElt) {
+	var prod [8]uint64
+	for i := 0; i < 4; i++ {
+		var c uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			var c1 uint64
+			lo, c1 = bits.Add64(lo, prod[i+j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, c, 0)
+			hi += c1
+			prod[i+j] = lo
+			c = hi
+		}
+		prod[i+4] = c
+	}
+	for i := 7; i >= 0; i-- {
+		for j := 0; j < 64; j++ {
+			t := z
+			ordAdd(&z, &t)
+		}
+		ordAdd(&z, &ordElt{prod[i]})
+	}
+
+	return
+}
+
+// bytes writes the canonical 32-byte little-endian encoding.
+func (z *ordElt) bytes(out []byte) {
+	for i := 0; i < 32; i++ {
+		out[i] = byte(z[i/8] >> (8 * (uint(i) & 7)))
+	}
+}
+
+// isLessThanOrder reports whether the 32-byte little-endian value is a
+// canonical scalar, that is, less than the group order.
+func isLessThanOrder(x []byte) bool {
+	for i := len(orderBytes) - 1; i >= 0; i-- {
+		if x[i] != orderBytes[i] {
+			return x[i] < orderBytes[i]
+		}
+	}
+
+	return false
+}
+
+// reduceModOrder reduces the little-endian value modulo the group order
+// in place, writing the result to the first 32 bytes and zeroing the
+// rest; wide selects the 64-byte hash-output form.
+func reduceModOrder(k []byte, wide bool) {
+	n := 32
+	if wide {
+		n = 64
+	}
+	z := ordFold(k[:n])
+	z.bytes(k[:32])
+	for i := 32; i < n; i++ {
+		k[i] = 0
+	}
+}
+
+// calculateS computes s = r + k*a modulo the group order; the inputs are
+// 32-byte little-endian values, a possibly unreduced (a clamped scalar).
+func calculateS(s, r, k, a []byte) {
+	rr := ordFold(r)
+	kk := ordFold(k)
+	aa := ordFold(a)
+	z := ordMul(&kk, &aa)
+	ordAdd(&z, &rr)
+	z.bytes(s)
+}
+
+// clampScalar copies the first 32 bytes and applies the RFC 8032
+// clamping: the low cofactor bits cleared and the high bit set.
+func clampScalar(b []byte) []byte {
+	a := make([]byte, 32)
+	copy(a, b[:32])
+	a[0] &= 248
+	a[31] &= 127
+	a[31] |= 64
+
+	return a
+}
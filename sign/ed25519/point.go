@@ -0,0 +1,239 @@
+package ed25519
+
+import (
+	"errors"
+
+	fp "github.com/cloudflare/circl/math/fp25519"
+)
+
+// This file implements the group of edwards25519, the twisted Edwards
+// curve -x^2 + y^2 = 1 + d x^2 y^2 with d = -121665/121666, in extended
+// homogeneous coordinates (X:Y:Z:T) with x = X/Z, y = Y/Z, T = XY/Z.
+// The unified addition and doubling formulas of Hisil, Wong, Carter, and
+// Dawson are used; for a = -1 they are complete on the curve.
+
+// pointR1 is a point in extended coordinates.
+type pointR1 struct {
+	x, y, z, t fp.Elt
+}
+
+// Curve constants and the base point, derived in init so no large
+// constant needs transcribing: d = -121665/121666, and the base point is
+// the even-x point with y = 4/5.
+var (
+	edD, edD2 fp.Elt
+	basePoint pointR1
+)
+
+func init() {
+	num := fp.Elt{0x41, 0xdb, 0x01} // 121665
+	den := fp.Elt{0x42, 0xdb, 0x01} // 121666
+	fp.Inv(&den, &den)
+	fp.Mul(&edD, &num, &den)
+	fp.Neg(&edD, &edD)
+	fp.Modp(&edD)
+	fp.Add(&edD2, &edD, &edD)
+	fp.Modp(&edD2)
+
+	four := fp.Elt{4}
+	five := fp.Elt{5}
+	fp.Inv(&five, &five)
+	var y fp.Elt
+	fp.Mul(&y, &four, &five)
+	fp.Modp(&y)
+	if !basePoint.FromBytes(y[:]) {
+		panic("ed25519: cannot decompress the base point")
+	}
+}
+
+// isLessThanP reports whether the little-endian value is canonical, that
+// is, less than the field prime.
+func isLessThanP(x *fp.Elt) bool {
+	p := fp.P()
+	for i := fp.Size - 1; i >= 0; i-- {
+		if x[i] != p[i] {
+			return x[i] < p[i]
+		}
+	}
+
+	return false
+}
+
+// SetIdentity sets the point to the neutral element.
+func (P *pointR1) SetIdentity() {
+	P.x = fp.Elt{}
+	fp.SetOne(&P.y)
+	fp.SetOne(&P.z)
+	P.t = fp.Elt{}
+}
+
+// neg negates the point in place.
+func (P *pointR1) neg() {
+	fp.Neg(&P.x, &P.x)
+	fp.Neg(&P.t, &P.t)
+}
+
+// isEqual reports whether both points are equal, comparing the
+// projective coordinates cross-multiplied.
+func (P *pointR1) isEqual(Q *pointR1) bool {
+	var l, r fp.Elt
+	fp.Mul(&l, &P.x, &Q.z)
+	fp.Mul(&r, &Q.x, &P.z)
+	fp.Sub(&l, &l, &r)
+	fp.Modp(&l)
+	ok := fp.IsZero(&l)
+	fp.Mul(&l, &P.y, &Q.z)
+	fp.Mul(&r, &Q.y, &P.z)
+	fp.Sub(&l, &l, &r)
+	fp.Modp(&l)
+
+	return ok && fp.IsZero(&l)
+}
+
+// double doubles the point in place (dbl-2008-hwcd with a = -1).
+func (P *pointR1) double() {
+	var a, b, c, e, f, g, h fp.Elt
+	fp.Sqr(&a, &P.x)
+	fp.Sqr(&b, &P.y)
+	fp.Sqr(&c, &P.z)
+	fp.Add(&c, &c, &c)
+	fp.Add(&e, &P.x, &P.y)
+	fp.Sqr(&e, &e)
+	fp.Sub(&e, &e, &a)
+	fp.Sub(&e, &e, &b)
+	fp.Sub(&g, &b, &a)
+	fp.Sub(&f, &g, &c)
+	fp.Add(&h, &a, &b)
+	fp.Neg(&h, &h)
+	fp.Mul(&P.x, &e, &f)
+	fp.Mul(&P.y, &g, &h)
+	fp.Mul(&P.t, &e, &h)
+	fp.Mul(&P.z, &f, &g)
+}
+
+// add adds Q to the point in place (add-2008-hwcd-3 with a = -1).
+func (P *pointR1) add(Q *pointR1) {
+	var a, b, c, d, e, f, g, h, t1, t2 fp.Elt
+	fp.Sub(&t1, &P.y, &P.x)
+	fp.Sub(&t2, &Q.y, &Q.x)
+	fp.Mul(&a, &t1, &t2)
+	fp.Add(&t1, &P.y, &P.x)
+	fp.Add(&t2, &Q.y, &Q.x)
+	fp.Mul(&b, &t1, &t2)
+	fp.Mul(&c, &P.t, &Q.t)
+	fp.Mul(&c, &c, &edD2)
+	fp.Mul(&d, &P.z, &Q.z)
+	fp.Add(&d, &d, &d)
+	fp.Sub(&e, &b, &a)
+	fp.Sub(&f, &d, &c)
+	fp.Add(&g, &d, &c)
+	fp.Add(&h, &b, &a)
+	fp.Mul(&P.x, &e, &f)
+	fp.Mul(&P.y, &g, &h)
+	fp.Mul(&P.t, &e, &h)
+	fp.Mul(&P.z, &f, &g)
+}
+
+// cmov assigns Q to the point when b is 1, in constant time.
+func (P *pointR1) cmov(Q *pointR1, b uint) {
+	fp.Cmov(&P.x, &Q.x, b)
+	fp.Cmov(&P.y, &Q.y, b)
+	fp.Cmov(&P.z, &Q.z, b)
+	fp.Cmov(&P.t, &Q.t, b)
+}
+
+// fixedMult sets the point to k times the base point, with the 32-byte
+// little-endian scalar k processed in constant time.
+func (P *pointR1) fixedMult(k []byte) {
+	var acc, t pointR1
+	acc.SetIdentity()
+	for i := 8*len(k) - 1; i >= 0; i-- {
+		acc.double()
+		t = acc
+		t.add(&basePoint)
+		acc.cmov(&t, uint(k[i/8]>>(uint(i)&7)&1))
+	}
+	*P = acc
+}
+
+// doubleMult sets the point to [s]B + [k]Q by interleaved double-and-add
+// in variable time; it is for verification, over public inputs only.
+func (P *pointR1) doubleMult(Q *pointR1, s, k []byte) {
+	var acc pointR1
+	acc.SetIdentity()
+	for i := 255; i >= 0; i-- {
+		acc.double()
+		if s[i/8]>>(uint(i)&7)&1 == 1 {
+			acc.add(&basePoint)
+		}
+		if k[i/8]>>(uint(i)&7)&1 == 1 {
+			acc.add(Q)
+		}
+	}
+	*P = acc
+}
+
+// FromBytes decompresses a canonical RFC 8032 point encoding.
+func (P *pointR1) FromBytes(b []byte) bool {
+	return P.fromBytes(b, true)
+}
+
+// fromBytes decompresses a point encoding; when canonical is false,
+// y coordinates at or above the field prime are accepted and implicitly
+// reduced, as ZIP-215 verification requires.
+func (P *pointR1) fromBytes(b []byte, canonical bool) bool {
+	if len(b) != PublicKeySize {
+		return false
+	}
+	sign := b[31] >> 7
+	var y fp.Elt
+	copy(y[:], b)
+	y[31] &= 0x7f
+	if canonical && !isLessThanP(&y) {
+		return false
+	}
+
+	// x^2 = (y^2-1)/(d y^2+1); the divisor is never zero as d is not a
+	// square.
+	var u, v, x fp.Elt
+	one := fp.Elt{1}
+	fp.Sqr(&u, &y)
+	fp.Mul(&v, &u, &edD)
+	fp.Sub(&u, &u, &one)
+	fp.Add(&v, &v, &one)
+	if fp.SqrtRatio(&x, &u, &v) != 1 {
+		return false
+	}
+	fp.Modp(&x)
+	if fp.IsZero(&x) && sign == 1 {
+		return false
+	}
+	if x[0]&1 != sign {
+		fp.Neg(&x, &x)
+		fp.Modp(&x)
+	}
+
+	P.x = x
+	P.y = y
+	fp.SetOne(&P.z)
+	fp.Mul(&P.t, &x, &y)
+
+	return true
+}
+
+// ToBytes writes the canonical RFC 8032 encoding of the point.
+func (P *pointR1) ToBytes(b []byte) error {
+	if len(b) != PublicKeySize {
+		return errors.New("ed25519: bad encoding length")
+	}
+	var zi, x, y fp.Elt
+	fp.Inv(&zi, &P.z)
+	fp.Mul(&x, &P.x, &zi)
+	fp.Modp(&x)
+	fp.Mul(&y, &P.y, &zi)
+	fp.Modp(&y)
+	copy(b, y[:])
+	b[31] |= (x[0] & 1) << 7
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package ed25519
+
+import (
+	"github.com/cloudflare/circl/sign"
+)
+
+// Scheme returns the sign.Scheme view of Ed25519. Plain Ed25519 does not
+// take a context string; Sign and Verify panic when one is supplied.
+func Scheme() sign.Scheme { return edScheme{} }
+
+// Scheme returns the sign.Scheme view of Ed25519.
+func (PrivateKey) Scheme() sign.Scheme { return Scheme() }
+
+// Scheme returns the sign.Scheme view of Ed25519.
+func (PublicKey) Scheme() sign.Scheme { return Scheme() }
+
+type edScheme struct{}
+
+func (edScheme) Name() string          { return "Ed25519" }
+func (edScheme) PublicKeySize() int    { return PublicKeySize }
+func (edScheme) PrivateKeySize() int   { return PrivateKeySize }
+func (edScheme) SignatureSize() int    { return SignatureSize }
+func (edScheme) SeedSize() int         { return SeedSize }
+func (edScheme) SupportsContext() bool { return false }
+
+func (edScheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	pub, priv, err := GenerateKey(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pub, priv, nil
+}
+
+func (edScheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	if len(seed) != SeedSize {
+		panic(sign.ErrSeedSize)
+	}
+	priv := NewKeyFromSeed(seed)
+
+	return priv.Public().(PublicKey), priv
+}
+
+func (edScheme) Sign(sk sign.PrivateKey, message []byte, opts *sign.SignatureOpts) []byte {
+	priv, ok := sk.(PrivateKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	if opts != nil && opts.Context != "" {
+		panic(sign.ErrContextNotSupported)
+	}
+
+	return Sign(priv, message)
+}
+
+func (edScheme) Verify(pk sign.PublicKey, message, signature []byte, opts *sign.SignatureOpts) bool {
+	pub, ok := pk.(PublicKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	if opts != nil && opts.Context != "" {
+		panic(sign.ErrContextNotSupported)
+	}
+
+	return Verify(pub, message, signature)
+}
+
+func (edScheme) UnmarshalBinaryPublicKey(data []byte) (sign.PublicKey, error) {
+	if len(data) != PublicKeySize {
+		return nil, sign.ErrPubKeySize
+	}
+
+	return PublicKey(append([]byte{}, data...)), nil
+}
+
+func (edScheme) UnmarshalBinaryPrivateKey(data []byte) (sign.PrivateKey, error) {
+	if len(data) != PrivateKeySize {
+		return nil, sign.ErrPrivKeySize
+	}
+
+	return PrivateKey(append([]byte{}, data...)), nil
+}
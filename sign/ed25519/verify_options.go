@@ -0,0 +1,76 @@
+package ed25519
+
+// VerifyOptions pins the exact acceptance criteria used to verify a
+// signature. Implementations of Ed25519 disagree on edge cases —
+// whether the verification equation is multiplied by the cofactor and
+// whether non-canonical point encodings are accepted — and for
+// consensus-critical applications those disagreements are the
+// difference between agreement and a fork. The zero value is the strict
+// RFC 8032 behavior used by Verify.
+//
+// Signatures whose scalar component is not fully reduced (s >= order)
+// are rejected under every option combination, as both RFC 8032 and
+// ZIP-215 require.
+type VerifyOptions struct {
+	// Cofactored multiplies both sides of the verification equation
+	// by the cofactor 8, accepting signatures that differ from a
+	// valid one by a small-torsion component. Cofactored verification
+	// is compatible with batch verification; cofactorless is not.
+	Cofactored bool
+
+	// AllowNonCanonicalA accepts public keys whose y coordinate is
+	// not fully reduced modulo the field prime.
+	AllowNonCanonicalA bool
+
+	// AllowNonCanonicalR accepts signatures whose R component is not
+	// fully reduced modulo the field prime.
+	AllowNonCanonicalR bool
+}
+
+var (
+	// StrictVerification is the RFC 8032 behavior: cofactorless,
+	// canonical encodings only. It is what Verify applies.
+	StrictVerification = VerifyOptions{}
+
+	// ZIP215Verification is the ZIP-215 behavior: cofactored, with
+	// non-canonical A and R encodings accepted. It makes individual
+	// verification agree exactly with batch verification.
+	ZIP215Verification = VerifyOptions{
+		Cofactored:         true,
+		AllowNonCanonicalA: true,
+		AllowNonCanonicalR: true,
+	}
+)
+
+// VerifyWithOptions reports whether the signature of the message by the
+// public key is valid under the acceptance criteria selected by opts.
+// VerifyWithOptions with StrictVerification is equivalent to Verify.
+func VerifyWithOptions(publicKey PublicKey, message, signature []byte, opts VerifyOptions) bool {
+	if len(publicKey) != PublicKeySize || len(signature) != SignatureSize ||
+		!isLessThanOrder(signature[32:]) {
+		return false
+	}
+
+	var A, R pointR1
+	if !A.fromBytes(publicKey, !opts.AllowNonCanonicalA) ||
+		!R.fromBytes(signature[:32], !opts.AllowNonCanonicalR) {
+		return false
+	}
+
+	// The digest is taken over the encodings as transmitted, not
+	// over re-canonicalized ones.
+	k := signatureDigest(signature[:32], publicKey, message)
+
+	A.neg()
+	var Q pointR1
+	Q.doubleMult(&A, signature[32:], k) // Q = [s]B - [k]A
+
+	if opts.Cofactored {
+		for i := 0; i < 3; i++ {
+			Q.double()
+			R.double()
+		}
+	}
+
+	return Q.isEqual(&R)
+}
@@ -0,0 +1,142 @@
+package ed25519
+
+import (
+	"crypto/sha512"
+	"io"
+
+	"github.com/cloudflare/circl/dh/x25519"
+	"github.com/cloudflare/circl/math/fp25519"
+	"github.com/cloudflare/circl/rand"
+)
+
+// This file implements the XEdDSA construction used by the Signal
+// protocol, which lets a party holding only a Montgomery-form X25519
+// identity key produce and verify Ed25519-compatible signatures without
+// carrying separate Edwards key material.
+//
+// The Montgomery private scalar is mapped to the Edwards key pair whose
+// public point has sign bit zero, negating the scalar modulo the group
+// order when necessary, so the signer's Edwards public key is fully
+// determined by the Montgomery u-coordinate and verifiers never see the
+// dropped sign bit.
+
+// orderBytes is the group order in little-endian form.
+var orderBytes = [32]byte{
+	0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58,
+	0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10,
+}
+
+// negModOrder replaces a reduced scalar with its negative modulo the
+// group order.
+func negModOrder(a []byte) {
+	nonzero := byte(0)
+	for _, b := range a {
+		nonzero |= b
+	}
+	if nonzero == 0 {
+		return
+	}
+	borrow := uint16(0)
+	for i := range a {
+		d := uint16(orderBytes[i]) - uint16(a[i]) - borrow
+		a[i] = byte(d)
+		borrow = d >> 15
+	}
+}
+
+// xedKeyPair derives the Edwards key pair for a Montgomery private key:
+// the clamped, reduced scalar and the encoding of the public point with
+// the sign bit forced to zero.
+func xedKeyPair(priv *x25519.Key) (a []byte, encA []byte) {
+	a = clampScalar(priv[:])
+	reduceModOrder(a, false)
+
+	var A pointR1
+	A.fixedMult(a)
+	encA = make([]byte, PublicKeySize)
+	_ = A.ToBytes(encA)
+	if encA[31]>>7 == 1 {
+		negModOrder(a)
+		encA[31] &= 0x7f
+	}
+
+	return
+}
+
+// montToEdwards converts a Montgomery u-coordinate to the Edwards
+// public key encoding with sign bit zero, via y = (u-1)/(u+1). It
+// reports failure for u = -1, which has no affine Edwards image.
+func montToEdwards(pub *x25519.Key) (PublicKey, bool) {
+	var u, num, den, y fp25519.Elt
+	copy(u[:], pub[:])
+	u[31] &= 0x7f
+	fp25519.Modp(&u)
+
+	one := fp25519.Elt{1}
+	fp25519.Add(&den, &u, &one)
+	if fp25519.IsZero(&den) {
+		return nil, false
+	}
+	fp25519.Sub(&num, &u, &one)
+	fp25519.Inv(&den, &den)
+	fp25519.Mul(&y, &num, &den)
+	fp25519.Modp(&y)
+
+	return PublicKey(y[:]), true
+}
+
+// XSign signs the message with a Montgomery-form X25519 private key,
+// producing a signature that XVerify accepts under the corresponding
+// X25519 public key. The signature also verifies as a plain Ed25519
+// signature under the Edwards public key determined by the Montgomery
+// key. Unlike Ed25519, XEdDSA signing is randomized: rnd supplies the
+// nonce randomness and defaults to the system random source when nil.
+func XSign(priv *x25519.Key, message []byte, rnd io.Reader) []byte {
+	if rnd == nil {
+		rnd = rand.Reader
+	}
+	var z [64]byte
+	if _, err := io.ReadFull(rnd, z[:]); err != nil {
+		panic(err)
+	}
+	a, encA := xedKeyPair(priv)
+
+	// r = hash1(a || M || Z), with the hash1 prefix from the XEdDSA
+	// specification pinning the hash index.
+	rh := sha512.New()
+	prefix := [32]byte{0: 0xfe}
+	for i := 1; i < 32; i++ {
+		prefix[i] = 0xff
+	}
+	_, _ = rh.Write(prefix[:])
+	_, _ = rh.Write(a)
+	_, _ = rh.Write(message)
+	_, _ = rh.Write(z[:])
+	r := rh.Sum(nil)
+	reduceModOrder(r, true)
+
+	var R pointR1
+	R.fixedMult(r[:32])
+	encR := make([]byte, 32)
+	_ = R.ToBytes(encR)
+
+	k := signatureDigest(encR, encA, message)
+
+	s := make([]byte, 32)
+	calculateS(s, r[:32], k, a)
+
+	return append(encR, s...)
+}
+
+// XVerify reports whether the signature of the message by the
+// Montgomery-form X25519 public key is valid.
+func XVerify(pub *x25519.Key, message, signature []byte) bool {
+	edPub, ok := montToEdwards(pub)
+	if !ok {
+		return false
+	}
+
+	return Verify(edPub, message, signature)
+}
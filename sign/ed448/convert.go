@@ -0,0 +1,75 @@
+package ed448
+
+import (
+	"github.com/cloudflare/circl/dh/x448"
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/math/fp448"
+)
+
+// Conversions between Ed448 signing keys and X448 Diffie-Hellman keys,
+// over the 4-isogeny u = y^2/x^2 from edwards448 to curve448 defined in
+// RFC 7748. Because both key types clamp the secret scalar identically,
+// the converted keys are consistent: the X448 public key of a converted
+// private key equals the conversion of the Ed448 public key.
+//
+// Caveats: the Montgomery u-coordinate drops the sign of the Edwards x
+// coordinate, so two distinct Ed448 public keys map to the same X448
+// key and the conversion cannot be inverted without fixing a sign
+// convention. Reusing one key pair for both signing and key exchange
+// steps outside the security proofs of either primitive; do so only
+// under a protocol-level analysis.
+
+// ToX448 converts an Ed448 public key to the corresponding X448 public
+// key. Substituting the curve equation, u = y^2*(d*y^2-1)/(y^2-1),
+// which needs no square root. It reports failure when the key is not a
+// valid point encoding or has no Montgomery image (the two points with
+// x = 0).
+func (pub PublicKey) ToX448() (x448.Key, bool) {
+	var k x448.Key
+	if len(pub) != PublicKeySize {
+		return k, false
+	}
+	var A pointR1
+	if !A.FromBytes(pub) {
+		return k, false
+	}
+
+	var y, y2, num, den, u fp448.Elt
+	copy(y[:], pub[:fp448.Size])
+
+	var d fp448.Elt
+	fp448.Neg(&d, &fp448.Elt{0xa9, 0x98}) // d = -39081
+
+	one := fp448.Elt{1}
+	fp448.Sqr(&y2, &y)
+	fp448.Sub(&den, &y2, &one)
+	if fp448.IsZero(&den) {
+		return k, false
+	}
+	fp448.Mul(&num, &d, &y2)
+	fp448.Sub(&num, &num, &one)
+	fp448.Mul(&num, &num, &y2)
+	fp448.Inv(&den, &den)
+	fp448.Mul(&u, &num, &den)
+	fp448.Modp(&u)
+	copy(k[:], u[:])
+
+	return k, true
+}
+
+// ToX448 converts an Ed448 private key to the corresponding X448
+// private key, the first half of the hashed seed. ToX448 panics if the
+// private key has the wrong size.
+func (priv PrivateKey) ToX448() x448.Key {
+	if len(priv) != PrivateKeySize {
+		panic("ed448: bad private key length")
+	}
+	h := make([]byte, 114)
+	hh := sha3.NewShake256()
+	_, _ = hh.Write(priv[:SeedSize])
+	_, _ = hh.Read(h)
+	var k x448.Key
+	copy(k[:], h[:x448.Size])
+
+	return k
+}
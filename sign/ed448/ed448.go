@@ -0,0 +1,192 @@
+// Package ed448 implements the Ed448 signature scheme of RFC 8032.
+//
+// The package mirrors the API of crypto/ed25519 — keys are byte slices
+// with the seed-and-public-key private layout — and extends it with the
+// streaming Ed448ph variant (PreHash), batch verification (VerifyBatch),
+// and conversions to X448 key material (ToX448). Plain Ed448 takes a
+// context string of at most 255 bytes, which may be empty; a sign.Scheme
+// view is available through Scheme.
+package ed448
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/rand"
+)
+
+const (
+	// PublicKeySize is the length in bytes of a public key.
+	PublicKeySize = 57
+	// PrivateKeySize is the length in bytes of a private key, the seed
+	// followed by the public key.
+	PrivateKeySize = 114
+	// SeedSize is the length in bytes of a private-key seed.
+	SeedSize = 57
+	// SignatureSize is the length in bytes of a signature.
+	SignatureSize = 114
+)
+
+// PublicKey is an Ed448 public key, the encoding of a curve point.
+type PublicKey []byte
+
+// PrivateKey is an Ed448 private key, the seed followed by the public
+// key.
+type PrivateKey []byte
+
+// GenerateKey generates a key pair from rnd, or from the system random
+// source when rnd is nil.
+func GenerateKey(rnd io.Reader) (PublicKey, PrivateKey, error) {
+	rnd = rand.Source(rnd)
+	seed := make([]byte, SeedSize)
+	if _, err := io.ReadFull(rnd, seed); err != nil {
+		return nil, nil, err
+	}
+	priv := NewKeyFromSeed(seed)
+
+	return priv.Public().(PublicKey), priv, nil
+}
+
+// NewKeyFromSeed derives the private key for a seed deterministically.
+// NewKeyFromSeed panics if the seed has the wrong size.
+func NewKeyFromSeed(seed []byte) PrivateKey {
+	if len(seed) != SeedSize {
+		panic("ed448: bad seed length")
+	}
+	h := make([]byte, 114)
+	hh := sha3.NewShake256()
+	_, _ = hh.Write(seed)
+	_, _ = hh.Read(h)
+	a := clampScalar(h[:57])
+
+	var A pointR1
+	A.fixedMult(a)
+	priv := make(PrivateKey, PrivateKeySize)
+	copy(priv, seed)
+	_ = A.ToBytes(priv[SeedSize:])
+
+	return priv
+}
+
+// dom4 returns the domain-separation string of RFC 8032 for plain Ed448
+// signatures with the given context.
+func dom4(ctx string) []byte {
+	d := append([]byte("SigEd448"), 0x00, byte(len(ctx)))
+
+	return append(d, ctx...)
+}
+
+// Sign signs the message bound to the context string, deterministically
+// as specified by RFC 8032. Sign panics if the private key or the
+// context has the wrong size.
+func Sign(priv PrivateKey, message []byte, ctx string) []byte {
+	if len(priv) != PrivateKeySize {
+		panic("ed448: bad private key length")
+	}
+	if len(ctx) > ContextMaxSize {
+		panic("ed448: bad context length")
+	}
+	dom := dom4(ctx)
+
+	h := make([]byte, 114)
+	hh := sha3.NewShake256()
+	_, _ = hh.Write(priv[:SeedSize])
+	_, _ = hh.Read(h)
+	a := clampScalar(h[:57])
+	prefix := h[57:]
+
+	r := make([]byte, 114)
+	rh := sha3.NewShake256()
+	_, _ = rh.Write(dom)
+	_, _ = rh.Write(prefix)
+	_, _ = rh.Write(message)
+	_, _ = rh.Read(r)
+	reduceModOrder(r)
+
+	var R pointR1
+	R.fixedMult(r[:57])
+	encR := make([]byte, 57)
+	_ = R.ToBytes(encR)
+
+	k := signatureDigest(encR, priv[SeedSize:], message, ctx)
+
+	s := make([]byte, 57)
+	calculateS(s, r[:57], k, a)
+
+	return append(encR, s...)
+}
+
+// Verify reports whether the signature of the message bound to the
+// context string by the public key is valid.
+func Verify(publicKey PublicKey, message, signature []byte, ctx string) bool {
+	if len(publicKey) != PublicKeySize || len(signature) != SignatureSize ||
+		len(ctx) > ContextMaxSize || !isLessThanOrder(signature[57:]) {
+		return false
+	}
+
+	var A pointR1
+	if !A.FromBytes(publicKey) {
+		return false
+	}
+
+	k := signatureDigest(signature[:57], publicKey, message, ctx)
+
+	A.neg()
+	var Q pointR1
+	Q.doubleMult(&A, signature[57:], k) // Q = [s]B - [k]A
+	enc := make([]byte, 57)
+	_ = Q.ToBytes(enc)
+
+	return subtle.ConstantTimeCompare(enc, signature[:57]) == 1
+}
+
+// Seed returns the seed the private key was derived from.
+func (priv PrivateKey) Seed() []byte {
+	return append([]byte{}, priv[:SeedSize]...)
+}
+
+// Public returns the public key half as a crypto.PublicKey.
+func (priv PrivateKey) Public() crypto.PublicKey {
+	pub := make(PublicKey, PublicKeySize)
+	copy(pub, priv[SeedSize:])
+
+	return pub
+}
+
+// Equal reports whether priv and x hold the same key.
+func (priv PrivateKey) Equal(x crypto.PrivateKey) bool {
+	o, ok := x.(PrivateKey)
+
+	return ok && subtle.ConstantTimeCompare(priv, o) == 1
+}
+
+// Sign implements crypto.Signer with the empty context; opts must
+// indicate an unhashed message (crypto.Hash(0)).
+func (priv PrivateKey) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != crypto.Hash(0) {
+		return nil, errors.New("ed448: cannot sign hashed message")
+	}
+
+	return Sign(priv, message, ""), nil
+}
+
+// MarshalBinary returns a copy of the private key.
+func (priv PrivateKey) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, priv...), nil
+}
+
+// Equal reports whether pub and x hold the same key.
+func (pub PublicKey) Equal(x crypto.PublicKey) bool {
+	o, ok := x.(PublicKey)
+
+	return ok && bytes.Equal(pub, o)
+}
+
+// MarshalBinary returns a copy of the public key.
+func (pub PublicKey) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, pub...), nil
+}
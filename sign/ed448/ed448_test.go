@@ -0,0 +1,218 @@
+package ed448_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/dh/x448"
+	"github.com/cloudflare/circl/sign/ed448"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Test vectors from RFC 8032, Section 7.4.
+var rfc8032Vectors = []struct{ seed, pub, msg, ctx, sig string }{
+	{
+		"6c82a562cb808d10d632be89c8513ebf6c929f34ddfa8c9f63c9960ef6e348a3" +
+			"528c8a3fcc2f044e39a3fc5b94492f8f032e7549a20098f95b",
+		"5fd7449b59b461fd2ce787ec616ad46a1da1342485a70e1f8a0ea75d80e96778" +
+			"edf124769b46c7061bd6783df1e50f6cd1fa1abeafe8256180",
+		"",
+		"",
+		"533a37f6bbe457251f023c0d88f976ae2dfb504a843e34d2074fd823d41a591f" +
+			"2b233f034f628281f2fd7a22ddd47d7828c59bd0a21bfd3980ff0d2028d4b18a" +
+			"9df63e006c5d1c2d345b925d8dc00b4104852db99ac5c7cdda8530a113a0f4db" +
+			"b61149f05a7363268c71d95808ff2e652600",
+	},
+	{
+		"c4eab05d357007c632f3dbb48489924d552b08fe0c353a0d4a1f00acda2c463a" +
+			"fbea67c5e8d2877c5e3bc397a659949ef8021e954e0a12274e",
+		"43ba28f430cdff456ae531545f7ecd0ac834a55d9358c0372bfa0c6c6798c086" +
+			"6aea01eb00742802b8438ea4cb82169c235160627b4c3a9480",
+		"03",
+		"",
+		"26b8f91727bd62897af15e41eb43c377efb9c610d48f2335cb0bd0087810f435" +
+			"2541b143c4b981b7e18f62de8ccdf633fc1bf037ab7cd779805e0dbcc0aae1cb" +
+			"cee1afb2e027df36bc04dcecbf154336c19f0af7e0a6472905e799f1953d2a0f" +
+			"f3348ab21aa4adafd1d234441cf807c03a00",
+	},
+	{
+		"c4eab05d357007c632f3dbb48489924d552b08fe0c353a0d4a1f00acda2c463a" +
+			"fbea67c5e8d2877c5e3bc397a659949ef8021e954e0a12274e",
+		"43ba28f430cdff456ae531545f7ecd0ac834a55d9358c0372bfa0c6c6798c086" +
+			"6aea01eb00742802b8438ea4cb82169c235160627b4c3a9480",
+		"03",
+		"foo",
+		"d4f8f6131770dd46f40867d6fd5d5055de43541f8c5e35abbcd001b32a89f7d2" +
+			"151f7647f11d8ca2ae279fb842d607217fce6e042f6815ea000c85741de5c8da" +
+			"1144a6a1aba7f96de42505d7a7298524fda538fccbbb754f578c1cad10d54d0d" +
+			"5428407e85dcbc98a49155c13764e66c3c00",
+	},
+}
+
+func TestRFC8032(t *testing.T) {
+	for i, v := range rfc8032Vectors {
+		seed := mustHex(t, v.seed)
+		msg := mustHex(t, v.msg)
+		wantPub := mustHex(t, v.pub)
+		wantSig := mustHex(t, v.sig)
+		ctx := v.ctx
+
+		priv := ed448.NewKeyFromSeed(seed)
+		pub := priv.Public().(ed448.PublicKey)
+		if !pub.Equal(ed448.PublicKey(wantPub)) {
+			t.Errorf("vector %d: public key mismatch\ngot:  %x\nwant: %x", i, pub, wantPub)
+		}
+
+		sig := ed448.Sign(priv, msg, ctx)
+		if hex.EncodeToString(sig) != hex.EncodeToString(wantSig) {
+			t.Errorf("vector %d: signature mismatch\ngot:  %x\nwant: %x", i, sig, wantSig)
+		}
+
+		if !ed448.Verify(pub, msg, sig, ctx) {
+			t.Errorf("vector %d: signature does not verify", i)
+		}
+		sig[0] ^= 1
+		if ed448.Verify(pub, msg, sig, ctx) {
+			t.Errorf("vector %d: corrupted signature verifies", i)
+		}
+	}
+}
+
+// Test vector from RFC 8032, Section 7.5 (Ed448ph over "abc").
+func TestPreHashRFC8032(t *testing.T) {
+	seed := mustHex(t,
+		"833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42"+
+			"ef7822e0d5104127dc05d6dbefde69e3ab2cec7c867c6e2c49")
+	wantPub := mustHex(t,
+		"259b71c19f83ef77a7abd26524cbdb3161b590a48f7d17de3ee0ba9c52beb743"+
+			"c09428a131d6b1b57303d90d8132c276d5ed3d5d01c0f53880")
+	wantSig := mustHex(t,
+		"822f6901f7480f3d5f562c592994d9693602875614483256505600bbc281ae38"+
+			"1f54d6bce2ea911574932f52a4e6cadd78769375ec3ffd1b801a0d9b3f4030cd"+
+			"433964b6457ea39476511214f97469b57dd32dbc560a9a94d00bff07620464a3"+
+			"ad203df7dc7ce360c3cd3696d9d9fab90f00")
+
+	priv := ed448.NewKeyFromSeed(seed)
+	if !priv.Public().(ed448.PublicKey).Equal(ed448.PublicKey(wantPub)) {
+		t.Fatalf("public key mismatch")
+	}
+
+	ph := ed448.NewPreHash("")
+	_, _ = ph.Write([]byte("abc"))
+	sig := ph.Sign(priv)
+	if hex.EncodeToString(sig) != hex.EncodeToString(wantSig) {
+		t.Fatalf("signature mismatch\ngot:  %x\nwant: %x", sig, wantSig)
+	}
+	if !ph.Verify(ed448.PublicKey(wantPub), sig) {
+		t.Fatal("signature does not verify")
+	}
+	if ed448.Verify(ed448.PublicKey(wantPub), []byte("abc"), sig, "") {
+		t.Fatal("Ed448ph signature verifies as plain Ed448")
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	const n = 8
+	const ctx = "batch test"
+	pubs := make([]ed448.PublicKey, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := range pubs {
+		pub, priv, err := ed448.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubs[i] = pub
+		msgs[i] = []byte{byte(i)}
+		sigs[i] = ed448.Sign(priv, msgs[i], ctx)
+	}
+
+	if ok, failed := ed448.VerifyBatch(pubs, msgs, sigs, ctx); !ok || failed != nil {
+		t.Fatalf("valid batch rejected, failed = %v", failed)
+	}
+
+	sigs[2][0] ^= 1
+	msgs[6] = []byte("tampered")
+	ok, failed := ed448.VerifyBatch(pubs, msgs, sigs, ctx)
+	if ok {
+		t.Fatal("invalid batch accepted")
+	}
+	if len(failed) != 2 || failed[0] != 2 || failed[1] != 6 {
+		t.Fatalf("wrong failing indices: %v", failed)
+	}
+}
+
+func TestToX448(t *testing.T) {
+	pub, priv, err := ed448.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xPriv := priv.ToX448()
+	xPub, ok := pub.ToX448()
+	if !ok {
+		t.Fatal("public key conversion failed")
+	}
+	var want x448.Key
+	x448.KeyGen(&want, &xPriv)
+	if want != xPub {
+		t.Fatalf("converted keys disagree\ngot:  %x\nwant: %x", xPub, want)
+	}
+}
+
+func TestScheme(t *testing.T) {
+	sch := ed448.Scheme()
+	if sch.Name() != "Ed448" || !sch.SupportsContext() {
+		t.Fatal("wrong scheme parameters")
+	}
+
+	seed := make([]byte, sch.SeedSize())
+	pub, priv := sch.DeriveKey(seed)
+	msg := []byte("scheme test")
+	sig := sch.Sign(priv, msg, nil)
+	if len(sig) != sch.SignatureSize() {
+		t.Fatal("wrong signature size")
+	}
+	if !sch.Verify(pub, msg, sig, nil) {
+		t.Fatal("signature does not verify")
+	}
+
+	pk, err := pub.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub2, err := sch.UnmarshalBinaryPublicKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Equal(pub2.(ed448.PublicKey)) {
+		t.Fatal("public key does not roundtrip")
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	_, priv, _ := ed448.GenerateKey(rand.Reader)
+	msg := make([]byte, 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ed448.Sign(priv, msg, "")
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	pub, priv, _ := ed448.GenerateKey(rand.Reader)
+	msg := make([]byte, 64)
+	sig := ed448.Sign(priv, msg, "")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ed448.Verify(pub, msg, sig, "")
+	}
+}
@@ -0,0 +1,158 @@
+package ed448
+
+import "math/bits"
+
+// This file implements arithmetic modulo the group order
+// L = 2^446 - 13818066809895115352007386748515426880336692474882178609894547503885
+// on little-endian byte strings, in constant time. Values are held in
+// seven 64-bit words; wide inputs are reduced by Horner folding, one
+// conditional subtraction of L per doubling, so no Barrett or Montgomery
+// constants are needed.
+
+// ordElt is a scalar modulo L in little-endian 64-bit words.
+type ordElt [7]uint64
+
+// orderBytes is the canonical 57-byte little-endian encoding of L.
+var orderBytes = [57]byte{
+	0xf3, 0x44, 0x58, 0xab, 0x92, 0xc2, 0x78, 0x23,
+	0x55, 0x8f, 0xc5, 0x8d, 0x72, 0xc2, 0x6c, 0x21,
+	0x90, 0x36, 0xd6, 0xae, 0x49, 0xdb, 0x4e, 0xc4,
+	0xe9, 0x23, 0xca, 0x7c, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x3f,
+	0x00,
+}
+
+// ordWords is the group order, derived from orderBytes.
+var ordWords ordElt
+
+func init() {
+	for i, b := range orderBytes[:56] {
+		ordWords[i/8] |= uint64(b) << (8 * (uint(i) & 7))
+	}
+}
+
+// ordCondSub subtracts L when the value is at least L, or
+// unconditionally when the carry flag of a preceding addition is set, in
+// constant time.
+func ordCondSub(w *ordElt, carry uint64) {
+	var d ordElt
+	var b uint64
+	for i := range w {
+		d[i], b = bits.Sub64(w[i], ordWords[i], b)
+	}
+	m := (b &^ carry) - 1
+	for i := range w {
+		w[i] = w[i]&^m | d[i]&m
+	}
+}
+
+// ordAdd adds x into z modulo L; the sum of the operands must be below
+// 2L, which holds whenever z is reduced and x is reduced or at most one
+// word wide.
+func ordAdd(z, x *ordElt) {
+	var c uint64
+	for i := range z {
+		z[i], c = bits.Add64(z[i], x[i], c)
+	}
+	ordCondSub(z, c)
+}
+
+// ordFold reduces a little-endian byte string of any length modulo L by
+// Horner folding.
+func ordFold(b []byte) (z ordElt) {
+	for i := len(b) - 1; i >= 0; i-- {
+		for j := 0; j < 8; j++ {
+			t := z
+			ordAdd(&z, &t)
+		}
+		ordAdd(&z, &ordElt{uint64(b[i])})
+	}
+
+	return
+}
+
+// ordMul multiplies two reduced scalars modulo L, folding the schoolbook
+// double-width product word by word.
+func ordMul(x, y *ordElt) (z ordElt) {
+	var prod [14]uint64
+	for i := 0; i < 7; i++ {
+		var c uint64
+		for j := 0; j < 7; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			var c1 uint64
+			lo, c1 = bits.Add64(lo, prod[i+j], 0)
+			hi += c1
+			lo, c1 = bits.Add64(lo, c, 0)
+			hi += c1
+			prod[i+j] = lo
+			c = hi
+		}
+		prod[i+7] = c
+	}
+	for i := 13; i >= 0; i-- {
+		for j := 0; j < 64; j++ {
+			t := z
+			ordAdd(&z, &t)
+		}
+		ordAdd(&z, &ordElt{prod[i]})
+	}
+
+	return
+}
+
+// bytes writes the canonical 57-byte little-endian encoding.
+func (z *ordElt) bytes(out []byte) {
+	for i := 0; i < 56; i++ {
+		out[i] = byte(z[i/8] >> (8 * (uint(i) & 7)))
+	}
+	out[56] = 0
+}
+
+// isLessThanOrder reports whether the 57-byte little-endian value is a
+// canonical scalar, that is, less than the group order.
+func isLessThanOrder(x []byte) bool {
+	for i := len(orderBytes) - 1; i >= 0; i-- {
+		if x[i] != orderBytes[i] {
+			return x[i] < orderBytes[i]
+		}
+	}
+
+	return false
+}
+
+// reduceModOrder reduces the 114-byte little-endian hash output modulo
+// the group order in place, writing the result to the first 57 bytes and
+// zeroing the rest.
+func reduceModOrder(k []byte) {
+	z := ordFold(k[:114])
+	z.bytes(k[:57])
+	for i := 57; i < 114; i++ {
+		k[i] = 0
+	}
+}
+
+// calculateS computes s = r + k*a modulo the group order; the inputs are
+// 57-byte little-endian values, a possibly unreduced (a clamped scalar).
+func calculateS(s, r, k, a []byte) {
+	rr := ordFold(r)
+	kk := ordFold(k)
+	aa := ordFold(a)
+	z := ordMul(&kk, &aa)
+	ordAdd(&z, &rr)
+	z.bytes(s)
+}
+
+// clampScalar copies the first 57 bytes and applies the RFC 8032
+// clamping: the low cofactor bits cleared, the high bit of the prior
+// byte set, and the final byte zeroed.
+func clampScalar(b []byte) []byte {
+	a := make([]byte, 57)
+	copy(a, b[:57])
+	a[0] &= 252
+	a[55] |= 128
+	a[56] = 0
+
+	return a
+}
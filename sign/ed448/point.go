@@ -0,0 +1,230 @@
+package ed448
+
+import (
+	"errors"
+
+	fp "github.com/cloudflare/circl/math/fp448"
+)
+
+// This file implements the group of edwards448, the untwisted Edwards
+// curve x^2 + y^2 = 1 + d x^2 y^2 with d = -39081, in extended
+// homogeneous coordinates (X:Y:Z:T) with x = X/Z, y = Y/Z, T = XY/Z.
+// The unified formulas of Hisil, Wong, Carter, and Dawson are used; as
+// d is not a square they are complete on the curve.
+
+// pointR1 is a point in extended coordinates.
+type pointR1 struct {
+	x, y, z, t fp.Elt
+}
+
+// The curve constant and the base point of RFC 8032. The base point is
+// stored compressed and decompressed in init, so only its y coordinate
+// and the sign of x need transcribing.
+var (
+	edD       fp.Elt
+	basePoint pointR1
+)
+
+var baseEnc = [PublicKeySize]byte{
+	0x14, 0xfa, 0x30, 0xf2, 0x5b, 0x79, 0x08, 0x98,
+	0xad, 0xc8, 0xd7, 0x4e, 0x2c, 0x13, 0xbd, 0xfd,
+	0xc4, 0x39, 0x7c, 0xe6, 0x1c, 0xff, 0xd3, 0x3a,
+	0xd7, 0xc2, 0xa0, 0x05, 0x1e, 0x9c, 0x78, 0x87,
+	0x40, 0x98, 0xa3, 0x6c, 0x73, 0x73, 0xea, 0x4b,
+	0x62, 0xc7, 0xc9, 0x56, 0x37, 0x20, 0x76, 0x88,
+	0x24, 0xbc, 0xb6, 0x6e, 0x71, 0x46, 0x3f, 0x69,
+	0x00,
+}
+
+func init() {
+	fp.Neg(&edD, &fp.Elt{0xa9, 0x98}) // d = -39081
+	fp.Modp(&edD)
+	if !basePoint.FromBytes(baseEnc[:]) {
+		panic("ed448: cannot decompress the base point")
+	}
+}
+
+// isLessThanP reports whether the little-endian value is canonical, that
+// is, less than the field prime.
+func isLessThanP(x *fp.Elt) bool {
+	p := fp.P()
+	for i := fp.Size - 1; i >= 0; i-- {
+		if x[i] != p[i] {
+			return x[i] < p[i]
+		}
+	}
+
+	return false
+}
+
+// SetIdentity sets the point to the neutral element.
+func (P *pointR1) SetIdentity() {
+	P.x = fp.Elt{}
+	fp.SetOne(&P.y)
+	fp.SetOne(&P.z)
+	P.t = fp.Elt{}
+}
+
+// neg negates the point in place.
+func (P *pointR1) neg() {
+	fp.Neg(&P.x, &P.x)
+	fp.Neg(&P.t, &P.t)
+}
+
+// isEqual reports whether both points are equal, comparing the
+// projective coordinates cross-multiplied.
+func (P *pointR1) isEqual(Q *pointR1) bool {
+	var l, r fp.Elt
+	fp.Mul(&l, &P.x, &Q.z)
+	fp.Mul(&r, &Q.x, &P.z)
+	fp.Sub(&l, &l, &r)
+	fp.Modp(&l)
+	ok := fp.IsZero(&l)
+	fp.Mul(&l, &P.y, &Q.z)
+	fp.Mul(&r, &Q.y, &P.z)
+	fp.Sub(&l, &l, &r)
+	fp.Modp(&l)
+
+	return ok && fp.IsZero(&l)
+}
+
+// double doubles the point in place (dbl-2008-hwcd with a = 1).
+func (P *pointR1) double() {
+	var a, b, c, e, f, g, h fp.Elt
+	fp.Sqr(&a, &P.x)
+	fp.Sqr(&b, &P.y)
+	fp.Sqr(&c, &P.z)
+	fp.Add(&c, &c, &c)
+	fp.Add(&e, &P.x, &P.y)
+	fp.Sqr(&e, &e)
+	fp.Sub(&e, &e, &a)
+	fp.Sub(&e, &e, &b)
+	fp.Add(&g, &a, &b)
+	fp.Sub(&f, &g, &c)
+	fp.Sub(&h, &a, &b)
+	fp.Mul(&P.x, &e, &f)
+	fp.Mul(&P.y, &g, &h)
+	fp.Mul(&P.t, &e, &h)
+	fp.Mul(&P.z, &f, &g)
+}
+
+// add adds Q to the point in place (add-2008-hwcd with a = 1).
+func (P *pointR1) add(Q *pointR1) {
+	var a, b, c, d, e, f, g, h, t1, t2 fp.Elt
+	fp.Mul(&a, &P.x, &Q.x)
+	fp.Mul(&b, &P.y, &Q.y)
+	fp.Mul(&c, &P.t, &Q.t)
+	fp.Mul(&c, &c, &edD)
+	fp.Mul(&d, &P.z, &Q.z)
+	fp.Add(&t1, &P.x, &P.y)
+	fp.Add(&t2, &Q.x, &Q.y)
+	fp.Mul(&e, &t1, &t2)
+	fp.Sub(&e, &e, &a)
+	fp.Sub(&e, &e, &b)
+	fp.Sub(&f, &d, &c)
+	fp.Add(&g, &d, &c)
+	fp.Sub(&h, &b, &a)
+	fp.Mul(&P.x, &e, &f)
+	fp.Mul(&P.y, &g, &h)
+	fp.Mul(&P.t, &e, &h)
+	fp.Mul(&P.z, &f, &g)
+}
+
+// cmov assigns Q to the point when b is 1, in constant time.
+func (P *pointR1) cmov(Q *pointR1, b uint) {
+	fp.Cmov(&P.x, &Q.x, b)
+	fp.Cmov(&P.y, &Q.y, b)
+	fp.Cmov(&P.z, &Q.z, b)
+	fp.Cmov(&P.t, &Q.t, b)
+}
+
+// fixedMult sets the point to k times the base point, with the 57-byte
+// little-endian scalar k processed in constant time.
+func (P *pointR1) fixedMult(k []byte) {
+	var acc, t pointR1
+	acc.SetIdentity()
+	for i := 8*len(k) - 1; i >= 0; i-- {
+		acc.double()
+		t = acc
+		t.add(&basePoint)
+		acc.cmov(&t, uint(k[i/8]>>(uint(i)&7)&1))
+	}
+	*P = acc
+}
+
+// doubleMult sets the point to [s]B + [k]Q by interleaved double-and-add
+// in variable time; it is for verification, over public inputs only.
+func (P *pointR1) doubleMult(Q *pointR1, s, k []byte) {
+	var acc pointR1
+	acc.SetIdentity()
+	for i := 455; i >= 0; i-- {
+		acc.double()
+		if s[i/8]>>(uint(i)&7)&1 == 1 {
+			acc.add(&basePoint)
+		}
+		if k[i/8]>>(uint(i)&7)&1 == 1 {
+			acc.add(Q)
+		}
+	}
+	*P = acc
+}
+
+// FromBytes decompresses a canonical RFC 8032 point encoding.
+func (P *pointR1) FromBytes(b []byte) bool {
+	if len(b) != PublicKeySize {
+		return false
+	}
+	sign := b[56] >> 7
+	if b[56]&0x7f != 0 {
+		return false
+	}
+	var y fp.Elt
+	copy(y[:], b[:fp.Size])
+	if !isLessThanP(&y) {
+		return false
+	}
+
+	// x^2 = (y^2-1)/(d y^2-1); the divisor is never zero as d is not a
+	// square.
+	var u, v, x fp.Elt
+	one := fp.Elt{1}
+	fp.Sqr(&u, &y)
+	fp.Mul(&v, &u, &edD)
+	fp.Sub(&u, &u, &one)
+	fp.Sub(&v, &v, &one)
+	if fp.SqrtRatio(&x, &u, &v) != 1 {
+		return false
+	}
+	fp.Modp(&x)
+	if fp.IsZero(&x) && sign == 1 {
+		return false
+	}
+	if x[0]&1 != sign {
+		fp.Neg(&x, &x)
+		fp.Modp(&x)
+	}
+
+	P.x = x
+	P.y = y
+	fp.SetOne(&P.z)
+	fp.Mul(&P.t, &x, &y)
+
+	return true
+}
+
+// ToBytes writes the canonical RFC 8032 encoding of the point.
+func (P *pointR1) ToBytes(b []byte) error {
+	if len(b) != PublicKeySize {
+		return errors.New("ed448: bad encoding length")
+	}
+	var zi, x, y fp.Elt
+	fp.Inv(&zi, &P.z)
+	fp.Mul(&x, &P.x, &zi)
+	fp.Modp(&x)
+	fp.Mul(&y, &P.y, &zi)
+	fp.Modp(&y)
+	copy(b, y[:])
+	b[56] = (x[0] & 1) << 7
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package ed448
+
+import (
+	"crypto/subtle"
+
+	"github.com/cloudflare/circl/internal/sha3"
+)
+
+// phSize is the SHAKE256 output length prehashing a message, per
+// RFC 8032.
+const phSize = 64
+
+// ContextMaxSize is the maximum length in bytes of a context string.
+const ContextMaxSize = 255
+
+// PreHash streams a message through SHAKE256 for the Ed448ph variant,
+// so large inputs can be signed and verified without buffering the
+// whole message in memory. Feed the message through Write (PreHash
+// implements io.Writer), then call Sign or Verify; neither consumes the
+// state, so the same stream can be signed and then verified, or Reset
+// and reused for the next message.
+//
+// Ed448ph signatures are domain-separated from plain Ed448 ones: a
+// signature produced here never verifies under Verify and vice versa.
+type PreHash struct {
+	h   sha3.State
+	ctx string
+}
+
+// NewPreHash returns a streaming Ed448ph signer/verifier bound to the
+// given context string, which must be at most 255 bytes long and may be
+// empty. NewPreHash panics otherwise.
+func NewPreHash(ctx string) *PreHash {
+	if len(ctx) > ContextMaxSize {
+		panic("ed448: bad context length")
+	}
+
+	return &PreHash{h: sha3.NewShake256(), ctx: ctx}
+}
+
+// Write absorbs the next chunk of the message. It never returns an
+// error.
+func (p *PreHash) Write(b []byte) (int, error) { return p.h.Write(b) }
+
+// Reset discards the message absorbed so far, keeping the context.
+func (p *PreHash) Reset() { p.h.Reset() }
+
+// sum snapshots the prehash without consuming the streaming state.
+func (p *PreHash) sum() []byte {
+	st := p.h.Clone()
+	ph := make([]byte, phSize)
+	_, _ = st.Read(ph)
+
+	return ph
+}
+
+func (p *PreHash) dom() []byte {
+	d := append([]byte("SigEd448"), 0x01, byte(len(p.ctx)))
+
+	return append(d, p.ctx...)
+}
+
+// Sign produces an Ed448ph signature over the message absorbed so far.
+// Sign panics if the private key has the wrong size.
+func (p *PreHash) Sign(priv PrivateKey) []byte {
+	if len(priv) != PrivateKeySize {
+		panic("ed448: bad private key length")
+	}
+	ph := p.sum()
+	dom := p.dom()
+
+	h := make([]byte, 114)
+	hh := sha3.NewShake256()
+	_, _ = hh.Write(priv[:SeedSize])
+	_, _ = hh.Read(h)
+	a := clampScalar(h[:57])
+	prefix := h[57:]
+
+	r := make([]byte, 114)
+	rh := sha3.NewShake256()
+	_, _ = rh.Write(dom)
+	_, _ = rh.Write(prefix)
+	_, _ = rh.Write(ph)
+	_, _ = rh.Read(r)
+	reduceModOrder(r)
+
+	var R pointR1
+	R.fixedMult(r[:57])
+	encR := make([]byte, 57)
+	_ = R.ToBytes(encR)
+
+	k := make([]byte, 114)
+	kh := sha3.NewShake256()
+	_, _ = kh.Write(dom)
+	_, _ = kh.Write(encR)
+	_, _ = kh.Write(priv[SeedSize:])
+	_, _ = kh.Write(ph)
+	_, _ = kh.Read(k)
+	reduceModOrder(k)
+
+	s := make([]byte, 57)
+	calculateS(s, r[:57], k[:57], a)
+
+	return append(encR, s...)
+}
+
+// Verify reports whether signature is a valid Ed448ph signature by the
+// public key over the message absorbed so far.
+func (p *PreHash) Verify(pub PublicKey, signature []byte) bool {
+	if len(pub) != PublicKeySize || len(signature) != SignatureSize ||
+		!isLessThanOrder(signature[57:]) {
+		return false
+	}
+	var A pointR1
+	if !A.FromBytes(pub) {
+		return false
+	}
+	ph := p.sum()
+
+	k := make([]byte, 114)
+	kh := sha3.NewShake256()
+	_, _ = kh.Write(p.dom())
+	_, _ = kh.Write(signature[:57])
+	_, _ = kh.Write(pub)
+	_, _ = kh.Write(ph)
+	_, _ = kh.Read(k)
+	reduceModOrder(k)
+
+	A.neg()
+	var Q pointR1
+	Q.doubleMult(&A, signature[57:], k[:57])
+	enc := make([]byte, 57)
+	_ = Q.ToBytes(enc)
+
+	return subtle.ConstantTimeCompare(enc, signature[:57]) == 1
+}
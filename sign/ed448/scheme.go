@@ -0,0 +1,84 @@
+package ed448
+
+import (
+	"github.com/cloudflare/circl/sign"
+)
+
+// Scheme returns the sign.Scheme view of Ed448. The context string of a
+// sign.SignatureOpts is passed through to Sign and Verify.
+func Scheme() sign.Scheme { return edScheme{} }
+
+// Scheme returns the sign.Scheme view of Ed448.
+func (PrivateKey) Scheme() sign.Scheme { return Scheme() }
+
+// Scheme returns the sign.Scheme view of Ed448.
+func (PublicKey) Scheme() sign.Scheme { return Scheme() }
+
+type edScheme struct{}
+
+func (edScheme) Name() string          { return "Ed448" }
+func (edScheme) PublicKeySize() int    { return PublicKeySize }
+func (edScheme) PrivateKeySize() int   { return PrivateKeySize }
+func (edScheme) SignatureSize() int    { return SignatureSize }
+func (edScheme) SeedSize() int         { return SeedSize }
+func (edScheme) SupportsContext() bool { return true }
+
+func (edScheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	pub, priv, err := GenerateKey(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pub, priv, nil
+}
+
+func (edScheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	if len(seed) != SeedSize {
+		panic(sign.ErrSeedSize)
+	}
+	priv := NewKeyFromSeed(seed)
+
+	return priv.Public().(PublicKey), priv
+}
+
+func (edScheme) Sign(sk sign.PrivateKey, message []byte, opts *sign.SignatureOpts) []byte {
+	priv, ok := sk.(PrivateKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	ctx := ""
+	if opts != nil {
+		ctx = opts.Context
+	}
+
+	return Sign(priv, message, ctx)
+}
+
+func (edScheme) Verify(pk sign.PublicKey, message, signature []byte, opts *sign.SignatureOpts) bool {
+	pub, ok := pk.(PublicKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	ctx := ""
+	if opts != nil {
+		ctx = opts.Context
+	}
+
+	return Verify(pub, message, signature, ctx)
+}
+
+func (edScheme) UnmarshalBinaryPublicKey(data []byte) (sign.PublicKey, error) {
+	if len(data) != PublicKeySize {
+		return nil, sign.ErrPubKeySize
+	}
+
+	return PublicKey(append([]byte{}, data...)), nil
+}
+
+func (edScheme) UnmarshalBinaryPrivateKey(data []byte) (sign.PrivateKey, error) {
+	if len(data) != PrivateKeySize {
+		return nil, sign.ErrPrivKeySize
+	}
+
+	return PrivateKey(append([]byte{}, data...)), nil
+}
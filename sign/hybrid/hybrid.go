@@ -0,0 +1,225 @@
+// Package hybrid provides PQ/T composite signature schemes.
+//
+// A composite key pairs a post-quantum signature key with a classical
+// one, and a composite signature carries one signature from each
+// component over the same message; verification succeeds only if both
+// components verify. All encodings are plain concatenations of the
+// fixed-size component encodings, so composite keys and signatures drop
+// into certificate fields without any framing of their own.
+package hybrid
+
+import (
+	"crypto"
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/schemes"
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/ed25519"
+	"github.com/cloudflare/circl/sign/mldsa"
+)
+
+type scheme struct {
+	name     string
+	pq, trad sign.Scheme
+}
+
+// MLDSA44Ed25519 and MLDSA65Ed25519 pair ML-DSA with Ed25519 at the
+// two security levels early PQ/T adopters deploy.
+var (
+	MLDSA44Ed25519 sign.Scheme = &scheme{"MLDSA44-Ed25519", mldsa.MLDSA44, ed25519.Scheme()}
+	MLDSA65Ed25519 sign.Scheme = &scheme{"MLDSA65-Ed25519", mldsa.MLDSA65, ed25519.Scheme()}
+)
+
+// New combines a post-quantum and a traditional signature scheme into a
+// composite scheme with the given name.
+func New(name string, pq, trad sign.Scheme) sign.Scheme {
+	return &scheme{name, pq, trad}
+}
+
+func init() {
+	// Provisional identifiers under the composite-algorithm arc of
+	// draft-ietf-lamps-pq-composite-sigs, matching package pki; subject
+	// to change until assignment is final.
+	oidBase := asn1.ObjectIdentifier{2, 16, 840, 1, 114027, 80, 8, 1}
+	for i, s := range []sign.Scheme{MLDSA44Ed25519, MLDSA65Ed25519} {
+		oid := append(asn1.ObjectIdentifier{}, oidBase...)
+		if err := schemes.Register(schemes.Entry{
+			Kind: schemes.KindSignature, Name: s.Name(),
+			OID: append(oid, 60+i), Signature: s,
+		}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (s *scheme) Name() string          { return s.name }
+func (s *scheme) PublicKeySize() int    { return s.pq.PublicKeySize() + s.trad.PublicKeySize() }
+func (s *scheme) PrivateKeySize() int   { return s.pq.PrivateKeySize() + s.trad.PrivateKeySize() }
+func (s *scheme) SignatureSize() int    { return s.pq.SignatureSize() + s.trad.SignatureSize() }
+func (s *scheme) SeedSize() int         { return s.pq.SeedSize() + s.trad.SeedSize() }
+func (s *scheme) SupportsContext() bool { return false }
+
+// PublicKey is a composite verification key.
+type PublicKey struct {
+	sch  *scheme
+	pq   sign.PublicKey
+	trad sign.PublicKey
+}
+
+// PrivateKey is a composite signing key.
+type PrivateKey struct {
+	sch  *scheme
+	pq   sign.PrivateKey
+	trad sign.PrivateKey
+}
+
+func (pk *PublicKey) Scheme() sign.Scheme { return pk.sch }
+
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	a, err := pk.pq.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b, err := pk.trad.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(a, b...), nil
+}
+
+func (pk *PublicKey) Equal(other crypto.PublicKey) bool {
+	o, ok := other.(*PublicKey)
+
+	return ok && pk.sch == o.sch && pk.pq.Equal(o.pq) && pk.trad.Equal(o.trad)
+}
+
+func (sk *PrivateKey) Scheme() sign.Scheme { return sk.sch }
+
+func (sk *PrivateKey) Public() crypto.PublicKey {
+	return &PublicKey{
+		sch:  sk.sch,
+		pq:   sk.pq.Public().(sign.PublicKey),
+		trad: sk.trad.Public().(sign.PublicKey),
+	}
+}
+
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	a, err := sk.pq.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b, err := sk.trad.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(a, b...), nil
+}
+
+func (sk *PrivateKey) Equal(other crypto.PrivateKey) bool {
+	o, ok := other.(*PrivateKey)
+
+	return ok && sk.sch == o.sch && sk.pq.Equal(o.pq) && sk.trad.Equal(o.trad)
+}
+
+// Sign implements crypto.Signer; the message is signed directly by both
+// components.
+func (sk *PrivateKey) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		return nil, errors.New("hybrid: cannot sign pre-hashed message")
+	}
+
+	return sk.sch.Sign(sk, message, nil), nil
+}
+
+func (s *scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	pqPub, pqPriv, err := s.pq.GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	tPub, tPriv, err := s.trad.GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &PublicKey{s, pqPub, tPub}, &PrivateKey{s, pqPriv, tPriv}, nil
+}
+
+func (s *scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	if len(seed) != s.SeedSize() {
+		panic(sign.ErrSeedSize)
+	}
+	pqPub, pqPriv := s.pq.DeriveKey(seed[:s.pq.SeedSize()])
+	tPub, tPriv := s.trad.DeriveKey(seed[s.pq.SeedSize():])
+
+	return &PublicKey{s, pqPub, tPub}, &PrivateKey{s, pqPriv, tPriv}
+}
+
+func (s *scheme) Sign(priv sign.PrivateKey, message []byte, opts *sign.SignatureOpts) []byte {
+	sk, ok := priv.(*PrivateKey)
+	if !ok || sk.sch != s {
+		panic(sign.ErrTypeMismatch)
+	}
+	if opts != nil && opts.Context != "" {
+		panic(sign.ErrContextNotSupported)
+	}
+	sig := s.pq.Sign(sk.pq, message, nil)
+
+	return append(sig, s.trad.Sign(sk.trad, message, nil)...)
+}
+
+func (s *scheme) Verify(pub sign.PublicKey, message, signature []byte, opts *sign.SignatureOpts) bool {
+	pk, ok := pub.(*PublicKey)
+	if !ok || pk.sch != s {
+		panic(sign.ErrTypeMismatch)
+	}
+	if opts != nil && opts.Context != "" {
+		panic(sign.ErrContextNotSupported)
+	}
+	if len(signature) != s.SignatureSize() {
+		return false
+	}
+	// Both components must verify; evaluate both unconditionally.
+	cut := s.pq.SignatureSize()
+	okPQ := s.pq.Verify(pk.pq, message, signature[:cut], nil)
+	okTrad := s.trad.Verify(pk.trad, message, signature[cut:], nil)
+
+	return okPQ && okTrad
+}
+
+func (s *scheme) UnmarshalBinaryPublicKey(data []byte) (sign.PublicKey, error) {
+	if len(data) != s.PublicKeySize() {
+		return nil, sign.ErrPubKeySize
+	}
+	cut := s.pq.PublicKeySize()
+	pqPub, err := s.pq.UnmarshalBinaryPublicKey(data[:cut])
+	if err != nil {
+		return nil, err
+	}
+	tPub, err := s.trad.UnmarshalBinaryPublicKey(data[cut:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicKey{s, pqPub, tPub}, nil
+}
+
+func (s *scheme) UnmarshalBinaryPrivateKey(data []byte) (sign.PrivateKey, error) {
+	if len(data) != s.PrivateKeySize() {
+		return nil, sign.ErrPrivKeySize
+	}
+	cut := s.pq.PrivateKeySize()
+	pqPriv, err := s.pq.UnmarshalBinaryPrivateKey(data[:cut])
+	if err != nil {
+		return nil, err
+	}
+	tPriv, err := s.trad.UnmarshalBinaryPrivateKey(data[cut:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{s, pqPriv, tPriv}, nil
+}
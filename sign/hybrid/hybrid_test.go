@@ -0,0 +1,233 @@
+package hybrid_test
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/hybrid"
+)
+
+func schemes() []sign.Scheme {
+	return []sign.Scheme{hybrid.MLDSA44Ed25519, hybrid.MLDSA65Ed25519}
+}
+
+func TestRoundtrip(t *testing.T) {
+	for _, sch := range schemes() {
+		t.Run(sch.Name(), func(t *testing.T) {
+			pub, priv, err := sch.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg := []byte("composite message")
+			sig := sch.Sign(priv, msg, nil)
+			if len(sig) != sch.SignatureSize() {
+				t.Errorf("signature size %d, want %d", len(sig), sch.SignatureSize())
+			}
+			if !sch.Verify(pub, msg, sig, nil) {
+				t.Fatal("valid signature rejected")
+			}
+			if sch.Verify(pub, []byte("other message"), sig, nil) {
+				t.Fatal("signature accepted for a different message")
+			}
+
+			// The crypto.Signer path must agree and reject pre-hashing.
+			sig2, err := priv.(crypto.Signer).Sign(nil, msg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sch.Verify(pub, msg, sig2, nil) {
+				t.Fatal("crypto.Signer signature rejected")
+			}
+			if _, err = priv.(crypto.Signer).Sign(nil, msg, crypto.SHA256); err == nil {
+				t.Fatal("pre-hashed signing not rejected")
+			}
+		})
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	for _, sch := range schemes() {
+		t.Run(sch.Name(), func(t *testing.T) {
+			seed := make([]byte, sch.SeedSize())
+			for i := range seed {
+				seed[i] = byte(i)
+			}
+			pub, priv := sch.DeriveKey(seed)
+			pub2, priv2 := sch.DeriveKey(seed)
+			if !pub.Equal(pub2) || !priv.Equal(priv2) {
+				t.Fatal("derivation is not deterministic")
+			}
+			if !priv.Public().(sign.PublicKey).Equal(pub) {
+				t.Fatal("Public() disagrees with the derived public key")
+			}
+
+			defer func() {
+				if recover() == nil {
+					t.Fatal("short seed not rejected")
+				}
+			}()
+			sch.DeriveKey(seed[:len(seed)-1])
+		})
+	}
+}
+
+// TestComponentTamper checks that a composite signature fails whenever
+// either component is broken: verification must not degrade to the
+// stronger (or weaker) component alone.
+func TestComponentTamper(t *testing.T) {
+	for _, sch := range schemes() {
+		t.Run(sch.Name(), func(t *testing.T) {
+			pub, priv, err := sch.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg := []byte("composite message")
+			sig := sch.Sign(priv, msg, nil)
+
+			// The PQ component occupies the first cut bytes.
+			cut := len(sig) - ed25519SigSize
+			for _, i := range []int{0, cut / 2, cut - 1, cut, len(sig) - 1} {
+				bad := append([]byte{}, sig...)
+				bad[i] ^= 1
+				if sch.Verify(pub, msg, bad, nil) {
+					t.Errorf("signature with byte %d flipped accepted", i)
+				}
+			}
+
+			// Splicing the PQ half over one message with the classical
+			// half over another must fail both ways.
+			other := sch.Sign(priv, []byte("other message"), nil)
+			if sch.Verify(pub, msg, append(sig[:cut:cut], other[cut:]...), nil) {
+				t.Error("spliced classical component accepted")
+			}
+			if sch.Verify(pub, msg, append(other[:cut:cut], sig[cut:]...), nil) {
+				t.Error("spliced PQ component accepted")
+			}
+		})
+	}
+}
+
+// ed25519SigSize is the classical component's signature length in both
+// registered composites.
+const ed25519SigSize = 64
+
+func TestSignatureSize(t *testing.T) {
+	for _, sch := range schemes() {
+		t.Run(sch.Name(), func(t *testing.T) {
+			pub, priv, err := sch.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg := []byte("composite message")
+			sig := sch.Sign(priv, msg, nil)
+
+			// The encoding is a bare concatenation, so the length check is
+			// the only framing; off-by-one lengths must be rejected, not
+			// shift the component split.
+			if sch.Verify(pub, msg, sig[:len(sig)-1], nil) {
+				t.Error("truncated signature accepted")
+			}
+			if sch.Verify(pub, msg, append(append([]byte{}, sig...), 0), nil) {
+				t.Error("extended signature accepted")
+			}
+			if sch.Verify(pub, msg, sig[1:], nil) {
+				t.Error("shifted signature accepted")
+			}
+			if sch.Verify(pub, msg, nil, nil) {
+				t.Error("empty signature accepted")
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	for _, sch := range schemes() {
+		t.Run(sch.Name(), func(t *testing.T) {
+			pub, priv, err := sch.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			pk, err := pub.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pk) != sch.PublicKeySize() {
+				t.Errorf("public key size %d, want %d", len(pk), sch.PublicKeySize())
+			}
+			pub2, err := sch.UnmarshalBinaryPublicKey(pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pub2.Equal(pub) {
+				t.Error("public key does not roundtrip")
+			}
+
+			sk, err := priv.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sk) != sch.PrivateKeySize() {
+				t.Errorf("private key size %d, want %d", len(sk), sch.PrivateKeySize())
+			}
+			priv2, err := sch.UnmarshalBinaryPrivateKey(sk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !priv2.Equal(priv) {
+				t.Error("private key does not roundtrip")
+			}
+
+			// The restored key must produce signatures the original public
+			// key accepts.
+			msg := []byte("composite message")
+			if !sch.Verify(pub, msg, sch.Sign(priv2, msg, nil), nil) {
+				t.Error("restored key signs invalidly")
+			}
+
+			if _, err = sch.UnmarshalBinaryPublicKey(pk[:len(pk)-1]); err == nil {
+				t.Error("truncated public key accepted")
+			}
+			if _, err = sch.UnmarshalBinaryPrivateKey(sk[:len(sk)-1]); err == nil {
+				t.Error("truncated private key accepted")
+			}
+		})
+	}
+}
+
+func TestContextUnsupported(t *testing.T) {
+	sch := hybrid.MLDSA44Ed25519
+	if sch.SupportsContext() {
+		t.Fatal("composite schemes do not support contexts")
+	}
+	_, priv, err := sch.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("signing with a context did not panic")
+		}
+	}()
+	sch.Sign(priv, []byte("msg"), &sign.SignatureOpts{Context: "ctx"})
+}
+
+func BenchmarkMLDSA44Ed25519(b *testing.B) {
+	sch := hybrid.MLDSA44Ed25519
+	pub, priv, err := sch.GenerateKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+	msg := []byte("composite message")
+	sig := sch.Sign(priv, msg, nil)
+	b.Run("Sign", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = sch.Sign(priv, msg, nil)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = sch.Verify(pub, msg, sig, nil)
+		}
+	})
+}
@@ -0,0 +1,607 @@
+// Package lms implements the LMS and HSS stateful hash-based signature
+// schemes of RFC 8554 over SHA-256.
+//
+// As with every stateful scheme, a one-time-key index must never be
+// reused. Private keys carry a StateKeeper that durably records the
+// advanced index before Sign releases a signature; Volatile opts out
+// for tests and for deployments that accept the risk.
+//
+// Verification is stateless and needs no key object: firmware-update
+// verifiers can call Verify directly on the serialized public key, so
+// the signing machinery never has to be linked in or trusted on the
+// verifying side.
+package lms
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/internal/wipe"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Parameter sets: an LMS tree height paired with an LM-OTS Winternitz
+// width (RFC 8554, sections 4.1 and 5.1). Wider W means smaller
+// signatures and slower signing.
+var (
+	H5W4  = &Params{name: "LMS_SHA256_M32_H5/W4", lmsType: 5, otsType: 3, h: 5, w: 4, p: 67, ls: 4}
+	H5W8  = &Params{name: "LMS_SHA256_M32_H5/W8", lmsType: 5, otsType: 4, h: 5, w: 8, p: 34, ls: 0}
+	H10W4 = &Params{name: "LMS_SHA256_M32_H10/W4", lmsType: 6, otsType: 3, h: 10, w: 4, p: 67, ls: 4}
+	H10W8 = &Params{name: "LMS_SHA256_M32_H10/W8", lmsType: 6, otsType: 4, h: 10, w: 8, p: 34, ls: 0}
+	H15W8 = &Params{name: "LMS_SHA256_M32_H15/W8", lmsType: 7, otsType: 4, h: 15, w: 8, p: 34, ls: 0}
+)
+
+var paramsByType = map[uint64]*Params{}
+
+func init() {
+	for _, p := range []*Params{H5W4, H5W8, H10W4, H10W8, H15W8} {
+		paramsByType[uint64(p.lmsType)<<32|uint64(p.otsType)] = p
+	}
+}
+
+// A StateKeeper durably records the next unused one-time-key index.
+// Sign calls Advance before it releases a signature; once Advance
+// returns, a crash must not resurrect a lower index. An error from
+// Advance aborts signing.
+type StateKeeper interface {
+	Advance(nextIndex uint64) error
+}
+
+type volatileState struct{}
+
+func (volatileState) Advance(uint64) error { return nil }
+
+// Volatile is a StateKeeper that persists nothing. A key restored from
+// an old copy of its serialization will reuse one-time keys; use it
+// only where that risk is acceptable.
+var Volatile StateKeeper = volatileState{}
+
+const (
+	n     = 32 // SHA-256 output
+	iSize = 16 // key-pair identifier
+
+	// Domain-separation constants of RFC 8554.
+	dPblc = 0x8080
+	dMesg = 0x8181
+	dLeaf = 0x8282
+	dIntr = 0x8383
+)
+
+// Params describes one LMS level: tree and one-time-signature types.
+type Params struct {
+	name    string
+	lmsType uint32
+	otsType uint32
+	h       int // tree height
+	w       int // Winternitz width in bits
+	p       int // chains per one-time signature
+	ls      int // checksum left shift
+}
+
+// Name returns the parameter-set name.
+func (p *Params) Name() string { return p.name }
+
+func (p *Params) lmsSigSize() int { return 4 + (4 + n + p.p*n) + 4 + p.h*n }
+
+func (p *Params) lmsPubSize() int { return 8 + iSize + n }
+
+func hashParts(parts ...[]byte) []byte {
+	h := sha256.New()
+	for _, m := range parts {
+		_, _ = h.Write(m)
+	}
+
+	return h.Sum(nil)
+}
+
+func u32(v uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+
+	return b[:]
+}
+
+func u16(v uint16) []byte {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], v)
+
+	return b[:]
+}
+
+// coef extracts the i-th w-bit digit of s (RFC 8554, section 3.1.3).
+func (p *Params) coef(s []byte, i int) int {
+	b := s[i*p.w/8]
+
+	return int(b>>(8-p.w-i*p.w%8)) & (1<<p.w - 1)
+}
+
+// digits returns the p base-2^w digits of q plus its checksum
+// (RFC 8554, section 4.4).
+func (p *Params) digits(q []byte) []int {
+	u := 8 * n / p.w
+	out := make([]int, 0, p.p)
+	for i := 0; i < u; i++ {
+		out = append(out, p.coef(q, i))
+	}
+	var csum uint16
+	for _, d := range out {
+		csum += uint16(1<<p.w - 1 - d)
+	}
+	csum <<= p.ls
+	ck := u16(csum)
+	for i := 0; len(out) < p.p; i++ {
+		out = append(out, p.coef(ck, i))
+	}
+
+	return out
+}
+
+// otsChainSK derives the start of chain i of one-time key q
+// (RFC 8554, Appendix A).
+func otsChainSK(id []byte, q uint32, i int, seed []byte) []byte {
+	return hashParts(id, u32(q), u16(uint16(i)), []byte{0xff}, seed)
+}
+
+// otsChain iterates the hash chain from step start for count steps.
+func (p *Params) otsChain(x []byte, id []byte, q uint32, i, start, count int) []byte {
+	out := x
+	for j := start; j < start+count; j++ {
+		out = hashParts(id, u32(q), u16(uint16(i)), []byte{byte(j)}, out)
+	}
+
+	return out
+}
+
+// otsPublic computes the one-time public key K of slot q.
+func (p *Params) otsPublic(id []byte, q uint32, seed []byte) []byte {
+	h := sha256.New()
+	_, _ = h.Write(id)
+	_, _ = h.Write(u32(q))
+	_, _ = h.Write(u16(dPblc))
+	for i := 0; i < p.p; i++ {
+		sk := otsChainSK(id, q, i, seed)
+		_, _ = h.Write(p.otsChain(sk, id, q, i, 0, 1<<p.w-1))
+	}
+
+	return h.Sum(nil)
+}
+
+// otsSign writes type || C || y into sig.
+func (p *Params) otsSign(sig, message, id []byte, q uint32, seed []byte) {
+	binary.BigEndian.PutUint32(sig, p.otsType)
+	c := hashParts(id, u32(q), u16(0xfffd), []byte{0xff}, seed)
+	copy(sig[4:], c)
+	digest := hashParts(id, u32(q), u16(dMesg), c, message)
+	for i, d := range p.digits(digest) {
+		sk := otsChainSK(id, q, i, seed)
+		copy(sig[4+n+i*n:], p.otsChain(sk, id, q, i, 0, d))
+	}
+}
+
+// otsPKFromSig recomputes the one-time public key candidate
+// (RFC 8554, Algorithm 4b).
+func (p *Params) otsPKFromSig(sig, message, id []byte, q uint32) []byte {
+	c := sig[4 : 4+n]
+	digest := hashParts(id, u32(q), u16(dMesg), c, message)
+	h := sha256.New()
+	_, _ = h.Write(id)
+	_, _ = h.Write(u32(q))
+	_, _ = h.Write(u16(dPblc))
+	for i, d := range p.digits(digest) {
+		y := sig[4+n+i*n : 4+n+(i+1)*n]
+		_, _ = h.Write(p.otsChain(y, id, q, i, d, 1<<p.w-1-d))
+	}
+
+	return h.Sum(nil)
+}
+
+// tree caches all 2^(h+1) nodes of one LMS tree, indexed as in
+// RFC 8554: the root is node 1 and leaf q is node 2^h+q.
+type tree struct {
+	nodes [][]byte
+}
+
+func (p *Params) buildTree(id, seed []byte) *tree {
+	t := &tree{nodes: make([][]byte, 2<<p.h)}
+	for q := 0; q < 1<<p.h; q++ {
+		r := 1<<p.h + q
+		k := p.otsPublic(id, uint32(q), seed)
+		t.nodes[r] = hashParts(id, u32(uint32(r)), u16(dLeaf), k)
+	}
+	for r := 1<<p.h - 1; r >= 1; r-- {
+		t.nodes[r] = hashParts(id, u32(uint32(r)), u16(dIntr), t.nodes[2*r], t.nodes[2*r+1])
+	}
+
+	return t
+}
+
+// lmsKey is one LMS instance inside an HSS hierarchy.
+type lmsKey struct {
+	par  *Params
+	id   []byte
+	seed []byte
+	t    *tree
+}
+
+func (k *lmsKey) publicBytes() []byte {
+	out := make([]byte, 0, k.par.lmsPubSize())
+	out = append(out, u32(k.par.lmsType)...)
+	out = append(out, u32(k.par.otsType)...)
+	out = append(out, k.id...)
+
+	return append(out, k.t.nodes[1]...)
+}
+
+func (k *lmsKey) sign(sig, message []byte, q uint32) {
+	p := k.par
+	binary.BigEndian.PutUint32(sig, q)
+	p.otsSign(sig[4:], message, k.id, q, k.seed)
+	off := 4 + 4 + n + p.p*n
+	binary.BigEndian.PutUint32(sig[off:], p.lmsType)
+	off += 4
+	r := 1<<p.h + int(q)
+	for z := 0; z < p.h; z++ {
+		copy(sig[off:], k.t.nodes[r>>z^1])
+		off += n
+	}
+}
+
+// lmsVerify checks one LMS signature against a serialized LMS public
+// key (RFC 8554, Algorithm 6).
+func lmsVerify(pub, message, sig []byte) bool {
+	if len(pub) < 8+iSize+n {
+		return false
+	}
+	p := paramsByType[uint64(binary.BigEndian.Uint32(pub))<<32|
+		uint64(binary.BigEndian.Uint32(pub[4:]))]
+	if p == nil || len(pub) != p.lmsPubSize() || len(sig) != p.lmsSigSize() {
+		return false
+	}
+	id := pub[8 : 8+iSize]
+	root := pub[8+iSize:]
+	q := binary.BigEndian.Uint32(sig)
+	if q >= 1<<p.h {
+		return false
+	}
+	otsSig := sig[4 : 4+4+n+p.p*n]
+	if binary.BigEndian.Uint32(otsSig) != p.otsType {
+		return false
+	}
+	off := 4 + len(otsSig)
+	if binary.BigEndian.Uint32(sig[off:]) != p.lmsType {
+		return false
+	}
+	off += 4
+
+	kc := p.otsPKFromSig(otsSig, message, id, q)
+	r := 1<<p.h + int(q)
+	node := hashParts(id, u32(uint32(r)), u16(dLeaf), kc)
+	for ; r > 1; r >>= 1 {
+		sib := sig[off : off+n]
+		if r&1 == 0 {
+			node = hashParts(id, u32(uint32(r/2)), u16(dIntr), node, sib)
+		} else {
+			node = hashParts(id, u32(uint32(r/2)), u16(dIntr), sib, node)
+		}
+		off += n
+	}
+
+	return bytes.Equal(node, root)
+}
+
+// PublicKey is an HSS verification key.
+type PublicKey struct {
+	levels int
+	lmsPub []byte // serialized root LMS public key
+}
+
+// PrivateKey is an HSS signing key. It is not safe for concurrent use.
+type PrivateKey struct {
+	params []*Params
+	seed   []byte // master seed; all level keys derive from it
+	idx    uint64 // next unused global index
+	pub    *PublicKey
+	keeper StateKeeper
+	keys   []*lmsKey // cached key per level
+	paths  []uint64  // which instance each cached key is
+}
+
+// MaxSignatures returns the total capacity: the product of the level
+// tree sizes.
+func (sk *PrivateKey) MaxSignatures() uint64 {
+	total := 0
+	for _, p := range sk.params {
+		total += p.h
+	}
+
+	return 1 << total
+}
+
+// Remaining returns how many signatures the key can still issue.
+func (sk *PrivateKey) Remaining() uint64 { return sk.MaxSignatures() - sk.idx }
+
+// Public returns the corresponding public key.
+func (sk *PrivateKey) Public() *PublicKey { return sk.pub }
+
+// shift returns how many index bits lie below level i.
+func (sk *PrivateKey) shift(i int) int {
+	s := 0
+	for _, p := range sk.params[i+1:] {
+		s += p.h
+	}
+
+	return s
+}
+
+// levelKey returns the LMS instance of level i on the path of the
+// global index, building its tree if the cache holds another instance.
+func (sk *PrivateKey) levelKey(i int, idx uint64) *lmsKey {
+	p := sk.params[i]
+	path := idx >> (sk.shift(i) + p.h)
+	if sk.keys[i] != nil && sk.paths[i] == path {
+		return sk.keys[i]
+	}
+	var pb [8]byte
+	binary.BigEndian.PutUint64(pb[:], path)
+	seed := hashParts(sk.seed, []byte{byte(i), 0}, pb[:])
+	id := hashParts(sk.seed, []byte{byte(i), 1}, pb[:])[:iSize]
+	k := &lmsKey{par: p, id: id, seed: seed}
+	k.t = p.buildTree(id, seed)
+	sk.keys[i] = k
+	sk.paths[i] = path
+
+	return k
+}
+
+// GenerateKey creates an HSS key pair with the given parameter set per
+// level, top first. rnd may be nil for the library-wide source; keeper
+// persists the signature counter (see StateKeeper and Volatile).
+func GenerateKey(levels []*Params, rnd io.Reader, keeper StateKeeper) (*PublicKey, *PrivateKey, error) {
+	seed := make([]byte, n)
+	if _, err := io.ReadFull(rand.Source(rnd), seed); err != nil {
+		return nil, nil, err
+	}
+
+	return DeriveKey(levels, seed, keeper)
+}
+
+// DeriveKey creates an HSS key pair from a 32-byte master seed.
+func DeriveKey(levels []*Params, seed []byte, keeper StateKeeper) (*PublicKey, *PrivateKey, error) {
+	if len(levels) == 0 || len(levels) > 8 {
+		return nil, nil, errors.New("lms: between 1 and 8 levels required")
+	}
+	if len(seed) != n {
+		return nil, nil, errors.New("lms: seed must be 32 bytes")
+	}
+	if keeper == nil {
+		return nil, nil, errors.New("lms: nil StateKeeper; use lms.Volatile to opt out of persistence")
+	}
+	sk := &PrivateKey{
+		params: append([]*Params{}, levels...),
+		seed:   append([]byte{}, seed...),
+		keeper: keeper,
+		keys:   make([]*lmsKey, len(levels)),
+		paths:  make([]uint64, len(levels)),
+	}
+	root := sk.levelKey(0, 0)
+	pub := make([]byte, 0, 4+levels[0].lmsPubSize())
+	pub = append(pub, u32(uint32(len(levels)))...)
+	pub = append(pub, root.publicBytes()...)
+	sk.pub = &PublicKey{levels: len(levels), lmsPub: pub[4:]}
+
+	return sk.pub, sk, nil
+}
+
+// MarshalBinary returns the RFC 8554 HSS public key encoding.
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	return append(u32(uint32(pk.levels)), pk.lmsPub...), nil
+}
+
+// UnmarshalPublicKey decodes an HSS public key.
+func UnmarshalPublicKey(data []byte) (*PublicKey, error) {
+	if len(data) < 12 {
+		return nil, errors.New("lms: malformed public key")
+	}
+	levels := int(binary.BigEndian.Uint32(data))
+	p := paramsByType[uint64(binary.BigEndian.Uint32(data[4:]))<<32|
+		uint64(binary.BigEndian.Uint32(data[8:]))]
+	if levels < 1 || levels > 8 || p == nil || len(data) != 4+p.lmsPubSize() {
+		return nil, errors.New("lms: malformed public key")
+	}
+
+	return &PublicKey{levels: levels, lmsPub: append([]byte{}, data[4:]...)}, nil
+}
+
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	return pk.levels == other.levels && bytes.Equal(pk.lmsPub, other.lmsPub)
+}
+
+// MarshalBinary encodes the private key including its next index and
+// per-level types. Restoring an old encoding reuses one-time keys;
+// keep exactly one live copy.
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	out := u32(uint32(len(sk.params)))
+	for _, p := range sk.params {
+		out = append(out, u32(p.lmsType)...)
+		out = append(out, u32(p.otsType)...)
+	}
+	var idx [8]byte
+	binary.BigEndian.PutUint64(idx[:], sk.idx)
+	out = append(out, idx[:]...)
+
+	return append(out, sk.seed...), nil
+}
+
+// UnmarshalPrivateKey decodes a private key, resuming at its stored
+// index. keeper persists the counter from here on.
+func UnmarshalPrivateKey(data []byte, keeper StateKeeper) (*PrivateKey, error) {
+	if len(data) < 4 {
+		return nil, errors.New("lms: malformed private key")
+	}
+	levels := int(binary.BigEndian.Uint32(data))
+	if levels < 1 || levels > 8 || len(data) != 4+8*levels+8+n {
+		return nil, errors.New("lms: malformed private key")
+	}
+	params := make([]*Params, levels)
+	for i := range params {
+		p := paramsByType[uint64(binary.BigEndian.Uint32(data[4+8*i:]))<<32|
+			uint64(binary.BigEndian.Uint32(data[8+8*i:]))]
+		if p == nil {
+			return nil, errors.New("lms: unknown parameter type")
+		}
+		params[i] = p
+	}
+	idx := binary.BigEndian.Uint64(data[4+8*levels:])
+	seed := data[4+8*levels+8:]
+	_, sk, err := DeriveKey(params, seed, keeper)
+	if err != nil {
+		return nil, err
+	}
+	sk.idx = idx
+
+	return sk, nil
+}
+
+func (sk *PrivateKey) Equal(other *PrivateKey) bool {
+	a, _ := sk.MarshalBinary()
+	b, _ := other.MarshalBinary()
+
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Wipe zeroes the master seed and the cached per-level seeds.
+func (sk *PrivateKey) Wipe() {
+	wipe.Bytes(sk.seed)
+	for _, k := range sk.keys {
+		if k != nil {
+			wipe.Bytes(k.seed)
+		}
+	}
+}
+
+// SignatureSize returns the length of signatures issued by this key.
+func (sk *PrivateKey) SignatureSize() int {
+	size := 4
+	for i, p := range sk.params {
+		size += p.lmsSigSize()
+		if i > 0 {
+			size += p.lmsPubSize()
+		}
+	}
+
+	return size
+}
+
+// ErrExhausted is returned when every one-time key has been used.
+var ErrExhausted = errors.New("lms: private key exhausted")
+
+// Sign signs message with the next one-time key. The index is advanced
+// and persisted through the key's StateKeeper before the signature is
+// computed, so a crash cannot lead to reuse.
+func (sk *PrivateKey) Sign(message []byte) ([]byte, error) {
+	if sk.idx >= sk.MaxSignatures() {
+		return nil, ErrExhausted
+	}
+	idx := sk.idx
+	sk.idx++
+	if err := sk.keeper.Advance(sk.idx); err != nil {
+		sk.idx = idx
+
+		return nil, err
+	}
+
+	sig := append([]byte{}, u32(uint32(len(sk.params)-1))...)
+	for i := 0; i < len(sk.params)-1; i++ {
+		parent := sk.levelKey(i, idx)
+		child := sk.levelKey(i+1, idx)
+		childPub := child.publicBytes()
+		part := make([]byte, sk.params[i].lmsSigSize())
+		q := uint32(idx >> sk.shift(i) & (1<<sk.params[i].h - 1))
+		parent.sign(part, childPub, q)
+		sig = append(sig, part...)
+		sig = append(sig, childPub...)
+	}
+	last := len(sk.params) - 1
+	bottom := sk.levelKey(last, idx)
+	part := make([]byte, sk.params[last].lmsSigSize())
+	bottom.sign(part, message, uint32(idx&(1<<sk.params[last].h-1)))
+
+	return append(sig, part...), nil
+}
+
+// Verify reports whether signature is valid for message under pk.
+func (pk *PublicKey) Verify(message, signature []byte) bool {
+	pub, _ := pk.MarshalBinary()
+
+	return Verify(pub, message, signature)
+}
+
+// Verify checks an HSS signature against a serialized HSS public key
+// (RFC 8554, section 6.3). It is stateless and self-contained, for
+// verifiers that never sign.
+func Verify(publicKey, message, signature []byte) bool {
+	if len(publicKey) < 12 || len(signature) < 4 {
+		return false
+	}
+	levels := int(binary.BigEndian.Uint32(publicKey))
+	if levels < 1 || levels > 8 ||
+		int(binary.BigEndian.Uint32(signature)) != levels-1 {
+		return false
+	}
+	pub := publicKey[4:]
+	sig := signature[4:]
+	for i := 0; i < levels-1; i++ {
+		p := sigParams(sig)
+		if p == nil || len(sig) < p.lmsSigSize()+p.lmsPubSize() {
+			return false
+		}
+		part := sig[:p.lmsSigSize()]
+		sig = sig[p.lmsSigSize():]
+		childPub := sig[:8+iSize+n]
+		cp := paramsByType[uint64(binary.BigEndian.Uint32(childPub))<<32|
+			uint64(binary.BigEndian.Uint32(childPub[4:]))]
+		if cp == nil {
+			return false
+		}
+		childPub = sig[:cp.lmsPubSize()]
+		sig = sig[cp.lmsPubSize():]
+		if !lmsVerify(pub, childPub, part) {
+			return false
+		}
+		pub = childPub
+	}
+	p := sigParams(sig)
+	if p == nil || len(sig) != p.lmsSigSize() {
+		return false
+	}
+
+	return lmsVerify(pub, message, sig)
+}
+
+// sigParams reads the parameter set out of an LMS signature, which
+// names its OTS type up front and its tree type after the OTS body.
+func sigParams(sig []byte) *Params {
+	if len(sig) < 8 {
+		return nil
+	}
+	otsType := binary.BigEndian.Uint32(sig[4:])
+	for _, p := range paramsByType {
+		if p.otsType != otsType {
+			continue
+		}
+		off := 4 + 4 + n + p.p*n
+		if len(sig) < off+4 {
+			continue
+		}
+		lmsType := binary.BigEndian.Uint32(sig[off:])
+		if q := paramsByType[uint64(lmsType)<<32|uint64(otsType)]; q != nil {
+			return q
+		}
+	}
+
+	return nil
+}
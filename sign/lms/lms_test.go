@@ -0,0 +1,187 @@
+package lms_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/cloudflare/circl/sign/lms"
+)
+
+func TestRoundtrip(t *testing.T) {
+	for _, levels := range [][]*lms.Params{
+		{lms.H5W4},
+		{lms.H5W8},
+		{lms.H10W8},
+		{lms.H5W4, lms.H5W8},
+	} {
+		name := ""
+		for _, p := range levels {
+			name += "/" + p.Name()
+		}
+		t.Run(name[1:], func(t *testing.T) {
+			seed := make([]byte, 32)
+			pk, sk, err := lms.DeriveKey(levels, seed, lms.Volatile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pk2, sk2, err := lms.DeriveKey(levels, seed, lms.Volatile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pk.Equal(pk2) || !sk.Equal(sk2) {
+				t.Fatal("key derivation is not deterministic")
+			}
+
+			msg := []byte("stateful hash-based signature")
+			sig, err := sk.Sign(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sig) != sk.SignatureSize() {
+				t.Fatal("wrong signature size")
+			}
+			if !pk.Verify(msg, sig) {
+				t.Fatal("signature does not verify")
+			}
+			if pk.Verify(msg[:len(msg)-1], sig) {
+				t.Fatal("signature verifies a different message")
+			}
+			sig[len(sig)-1] ^= 1
+			if pk.Verify(msg, sig) {
+				t.Fatal("corrupted signature verifies")
+			}
+			sig[len(sig)-1] ^= 1
+
+			// The stateless verifier must agree.
+			pub, err := pk.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !lms.Verify(pub, msg, sig) {
+				t.Fatal("stateless verification fails")
+			}
+
+			sig2, err := sk.Sign(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Equal(sig, sig2) {
+				t.Fatal("one-time key reused")
+			}
+			if !pk.Verify(msg, sig2) {
+				t.Fatal("second signature does not verify")
+			}
+			if sk.Remaining() != sk.MaxSignatures()-2 {
+				t.Fatal("wrong remaining count")
+			}
+		})
+	}
+}
+
+func TestExhaustion(t *testing.T) {
+	pk, sk, err := lms.GenerateKey([]*lms.Params{lms.H5W8}, nil, lms.Volatile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("drain")
+	for i := uint64(0); i < sk.MaxSignatures(); i++ {
+		sig, err := sk.Sign(msg)
+		if err != nil {
+			t.Fatalf("signature %d: %v", i, err)
+		}
+		if !pk.Verify(msg, sig) {
+			t.Fatalf("signature %d does not verify", i)
+		}
+	}
+	if _, err = sk.Sign(msg); !errors.Is(err, lms.ErrExhausted) {
+		t.Fatalf("exhausted key signs, err = %v", err)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	levels := []*lms.Params{lms.H5W4, lms.H5W8}
+	pk, sk, err := lms.GenerateKey(levels, nil, lms.Volatile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("marshal")
+	if _, err = sk.Sign(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	ppk, err := pk.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	upk, err := lms.UnmarshalPublicKey(ppk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Equal(upk) {
+		t.Fatal("public key does not roundtrip")
+	}
+
+	psk, err := sk.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	usk, err := lms.UnmarshalPrivateKey(psk, lms.Volatile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sk.Equal(usk) {
+		t.Fatal("private key does not roundtrip")
+	}
+
+	// The restored key continues from the recorded index.
+	sig, err := usk.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !upk.Verify(msg, sig) {
+		t.Fatal("signature of restored key does not verify")
+	}
+}
+
+// recordingKeeper counts Advance calls and can simulate storage failure.
+type recordingKeeper struct {
+	last uint64
+	fail bool
+}
+
+func (k *recordingKeeper) Advance(next uint64) error {
+	if k.fail {
+		return errors.New("storage failure")
+	}
+	k.last = next
+	return nil
+}
+
+func TestStateKeeper(t *testing.T) {
+	keeper := &recordingKeeper{}
+	pk, sk, err := lms.GenerateKey([]*lms.Params{lms.H5W4}, nil, keeper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("keeper")
+	if _, err = sk.Sign(msg); err != nil {
+		t.Fatal(err)
+	}
+	if keeper.last != 1 {
+		t.Fatalf("index not advanced, keeper at %d", keeper.last)
+	}
+
+	keeper.fail = true
+	if _, err = sk.Sign(msg); err == nil {
+		t.Fatal("signing succeeds although the state cannot be persisted")
+	}
+	keeper.fail = false
+	sig, err := sk.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Verify(msg, sig) {
+		t.Fatal("signature after keeper failure does not verify")
+	}
+}
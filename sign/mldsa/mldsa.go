@@ -0,0 +1,1005 @@
+// Package mldsa implements ML-DSA, the module-lattice signature scheme
+// standardized in FIPS 204, superseding the round-3 Dilithium
+// parameterization.
+//
+// The three parameter sets are exposed as sign.Scheme instances:
+// MLDSA44, MLDSA65, and MLDSA87. Signing is hedged by default, mixing
+// 32 fresh random bytes into the commitment as FIPS 204 recommends; the
+// deterministic variant is available by passing a nil randomness source
+// to PrivateKey.Sign, which also makes private keys usable as a
+// crypto.Signer. Context strings up to 255 bytes are supported through
+// sign.SignatureOpts and are domain-separated from the message as the
+// standard prescribes.
+package mldsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/wipe"
+	"github.com/cloudflare/circl/rand"
+	"github.com/cloudflare/circl/schemes"
+	"github.com/cloudflare/circl/sign"
+)
+
+// Parameter sets of FIPS 204.
+var (
+	// MLDSA44 is ML-DSA-44 (security category 2).
+	MLDSA44 sign.Scheme = &scheme{
+		name: "ML-DSA-44", k: 4, l: 4, eta: 2, tau: 39, omega: 80,
+		gamma1Bits: 17, gamma2: (q - 1) / 88, cTildeSize: 32,
+	}
+	// MLDSA65 is ML-DSA-65 (security category 3).
+	MLDSA65 sign.Scheme = &scheme{
+		name: "ML-DSA-65", k: 6, l: 5, eta: 4, tau: 49, omega: 55,
+		gamma1Bits: 19, gamma2: (q - 1) / 32, cTildeSize: 48,
+	}
+	// MLDSA87 is ML-DSA-87 (security category 5).
+	MLDSA87 sign.Scheme = &scheme{
+		name: "ML-DSA-87", k: 8, l: 7, eta: 2, tau: 60, omega: 75,
+		gamma1Bits: 19, gamma2: (q - 1) / 32, cTildeSize: 64,
+	}
+)
+
+func init() {
+	oidBase := asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3}
+	for i, s := range []sign.Scheme{MLDSA44, MLDSA65, MLDSA87} {
+		oid := append(asn1.ObjectIdentifier{}, oidBase...)
+		err := schemes.Register(schemes.Entry{
+			Kind:      schemes.KindSignature,
+			Name:      s.Name(),
+			OID:       append(oid, 17+i),
+			Signature: s,
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+const (
+	n        = 256
+	q        = 8380417
+	d        = 13 // dropped bits of t
+	seedSize = 32 // xi
+	trSize   = 64
+	rndSize  = 32
+)
+
+// zetas[i] = 1753^BitRev8(i) mod q, the twiddle factors of the complete
+// 256-point NTT, derived in init.
+var zetas [n]int32
+
+func init() {
+	for i := uint(0); i < n; i++ {
+		br := uint(0)
+		for j := uint(0); j < 8; j++ {
+			br |= (i >> j & 1) << (7 - j)
+		}
+		v := uint64(1)
+		for j := uint(0); j < br; j++ {
+			v = v * 1753 % q
+		}
+		zetas[i] = int32(v)
+	}
+}
+
+type poly [n]int32
+
+// Coefficients are kept reduced in [0,q).
+func fqMul(a, b int32) int32 { return int32(uint64(a) * uint64(b) % q) }
+
+func fqAdd(a, b int32) int32 {
+	c := a + b - q
+	c += (c >> 31) & q
+
+	return c
+}
+
+func fqSub(a, b int32) int32 {
+	c := a - b
+	c += (c >> 31) & q
+
+	return c
+}
+
+// centered maps a reduced coefficient to its representative in
+// (-q/2, q/2] and abs returns its absolute value, both in constant time.
+func centered(a int32) int32 {
+	a -= (((q-1)/2 - a) >> 31) & q
+
+	return a
+}
+
+func abs(a int32) int32 {
+	m := a >> 31
+
+	return (a + m) ^ m
+}
+
+func (p *poly) ntt() {
+	k := 0
+	for length := 128; length >= 1; length >>= 1 {
+		for start := 0; start < n; start += 2 * length {
+			k++
+			zeta := zetas[k]
+			for j := start; j < start+length; j++ {
+				t := fqMul(zeta, p[j+length])
+				p[j+length] = fqSub(p[j], t)
+				p[j] = fqAdd(p[j], t)
+			}
+		}
+	}
+}
+
+func (p *poly) invNTT() {
+	k := n - 1
+	for length := 1; length <= 128; length <<= 1 {
+		for start := 0; start < n; start += 2 * length {
+			zeta := zetas[k]
+			k--
+			for j := start; j < start+length; j++ {
+				t := p[j]
+				p[j] = fqAdd(t, p[j+length])
+				p[j+length] = fqMul(zeta, fqSub(p[j+length], t))
+			}
+		}
+	}
+	const nInv = 8347681 // 256^-1 mod q
+	for j := range p {
+		p[j] = fqMul(p[j], nInv)
+	}
+}
+
+func (p *poly) mulNTT(a, b *poly) {
+	for i := range p {
+		p[i] = fqMul(a[i], b[i])
+	}
+}
+
+func (p *poly) add(a, b *poly) {
+	for i := range p {
+		p[i] = fqAdd(a[i], b[i])
+	}
+}
+
+func (p *poly) sub(a, b *poly) {
+	for i := range p {
+		p[i] = fqSub(a[i], b[i])
+	}
+}
+
+// normExceeds reports whether the infinity norm of p reaches bound.
+func (p *poly) normExceeds(bound int32) bool {
+	var acc int32
+	for _, c := range p {
+		acc |= (bound - abs(centered(c)) - 1) >> 31 & 1
+	}
+
+	return acc != 0
+}
+
+// power2Round splits every coefficient r into r1*2^d + r0 with r0 in
+// (-2^(d-1), 2^(d-1)] (FIPS 204, Algorithm 35).
+func power2Round(r int32) (r1, r0 int32) {
+	r0 = r & (1<<d - 1)
+	r0 -= ((1<<(d-1) - r0) >> 31) & (1 << d)
+
+	return (r - r0) >> d, r0
+}
+
+// decompose splits r relative to 2*gamma2 (FIPS 204, Algorithm 36).
+func decompose(r, gamma2 int32) (r1, r0 int32) {
+	alpha := 2 * gamma2
+	r0 = r % alpha
+	r0 -= ((alpha/2 - r0) >> 31) & alpha
+	if r-r0 == q-1 {
+		return 0, r0 - 1
+	}
+
+	return (r - r0) / alpha, r0
+}
+
+// makeHint and useHint carry the high bits across the c*t0 perturbation
+// (FIPS 204, Algorithms 39 and 40).
+func makeHint(z, r, gamma2 int32) int32 {
+	r1, _ := decompose(r, gamma2)
+	v1, _ := decompose(fqAdd(r, z), gamma2)
+	if r1 != v1 {
+		return 1
+	}
+
+	return 0
+}
+
+func useHint(h, r, gamma2 int32) int32 {
+	m := (q - 1) / (2 * gamma2)
+	r1, r0 := decompose(r, gamma2)
+	if h == 0 {
+		return r1
+	}
+	if r0 > 0 {
+		return (r1 + 1) % m
+	}
+
+	return (r1 - 1 + m) % m
+}
+
+// pack serializes the low bits of every coefficient; unpack reverses it.
+func (p *poly) pack(out []byte, bits uint) {
+	var acc uint64
+	var fill uint
+	k := 0
+	for i := range p {
+		acc |= uint64(p[i]) << fill
+		fill += bits
+		for fill >= 8 {
+			out[k] = byte(acc)
+			k++
+			acc >>= 8
+			fill -= 8
+		}
+	}
+}
+
+func (p *poly) unpack(in []byte, bits uint) {
+	var acc uint64
+	var fill uint
+	k := 0
+	for i := range p {
+		for fill < bits {
+			acc |= uint64(in[k]) << fill
+			k++
+			fill += 8
+		}
+		p[i] = int32(acc & (1<<bits - 1))
+		acc >>= bits
+		fill -= bits
+	}
+}
+
+// packOffset stores off-p[i] in bits bits, for coefficients bounded by
+// off; unpackOffset reverses it without validating the range.
+func (p *poly) packOffset(out []byte, off int32, bits uint) {
+	var t poly
+	for i := range p {
+		t[i] = off - centered(p[i])
+	}
+	t.pack(out, bits)
+}
+
+func (p *poly) unpackOffset(in []byte, off int32, bits uint) {
+	p.unpack(in, bits)
+	for i := range p {
+		c := off - p[i]
+		c += (c >> 31) & q
+		p[i] = c
+	}
+}
+
+// sampleNTT fills p with a uniform NTT-domain polynomial from
+// SHAKE128(rho||s||r), by 23-bit rejection (FIPS 204, Algorithm 30).
+func (p *poly) sampleNTT(rho []byte, s, r byte) {
+	h := sha3.NewShake128()
+	_, _ = h.Write(rho)
+	_, _ = h.Write([]byte{s, r})
+	var buf [3]byte
+	k := 0
+	for k < n {
+		_, _ = h.Read(buf[:])
+		t := int32(buf[0]) | int32(buf[1])<<8 | int32(buf[2]&0x7f)<<16
+		if t < q {
+			p[k] = t
+			k++
+		}
+	}
+}
+
+// sampleBounded fills p with coefficients in [-eta, eta] from
+// SHAKE256(rho||r) by half-byte rejection (FIPS 204, Algorithm 31).
+func (p *poly) sampleBounded(rho []byte, r uint16, eta int32) {
+	h := sha3.NewShake256()
+	_, _ = h.Write(rho)
+	_, _ = h.Write([]byte{byte(r), byte(r >> 8)})
+	var buf [1]byte
+	k := 0
+	for k < n {
+		_, _ = h.Read(buf[:])
+		for _, t := range []int32{int32(buf[0] & 0x0f), int32(buf[0] >> 4)} {
+			if k == n {
+				break
+			}
+			switch eta {
+			case 2:
+				if t < 15 {
+					p[k] = fqSub(2, t%5)
+					k++
+				}
+			case 4:
+				if t < 9 {
+					p[k] = fqSub(4, t)
+					k++
+				}
+			}
+		}
+	}
+}
+
+// sampleMask fills p with coefficients in (-gamma1, gamma1] from
+// SHAKE256(rho||kappa) (FIPS 204, Algorithm 34).
+func (p *poly) sampleMask(rho []byte, kappa uint16, gamma1Bits uint) {
+	h := sha3.NewShake256()
+	_, _ = h.Write(rho)
+	_, _ = h.Write([]byte{byte(kappa), byte(kappa >> 8)})
+	buf := make([]byte, 32*(gamma1Bits+1))
+	_, _ = h.Read(buf)
+	p.unpack(buf, gamma1Bits+1)
+	for i := range p {
+		c := int32(1)<<gamma1Bits - p[i]
+		c += (c >> 31) & q
+		p[i] = c
+	}
+}
+
+// sampleInBall derives the challenge polynomial with tau coefficients
+// in {-1,1} from the commitment hash (FIPS 204, Algorithm 29).
+func (p *poly) sampleInBall(cTilde []byte, tau int) {
+	h := sha3.NewShake256()
+	_, _ = h.Write(cTilde)
+	var signs [8]byte
+	_, _ = h.Read(signs[:])
+	*p = poly{}
+	var buf [1]byte
+	for i := n - tau; i < n; i++ {
+		j := n
+		for j > i {
+			_, _ = h.Read(buf[:])
+			j = int(buf[0])
+		}
+		p[i] = p[j]
+		bit := i - (n - tau)
+		if signs[bit/8]>>(bit%8)&1 == 1 {
+			p[j] = q - 1
+		} else {
+			p[j] = 1
+		}
+	}
+}
+
+// scheme is one ML-DSA parameter set.
+type scheme struct {
+	name       string
+	k, l       int
+	eta        int32
+	tau        int
+	omega      int
+	gamma1Bits uint
+	gamma2     int32
+	cTildeSize int
+}
+
+func (s *scheme) Name() string          { return s.name }
+func (s *scheme) SeedSize() int         { return seedSize }
+func (s *scheme) SupportsContext() bool { return true }
+
+func (s *scheme) etaBits() uint {
+	if s.eta == 2 {
+		return 3
+	}
+
+	return 4
+}
+
+func (s *scheme) beta() int32 { return s.eta * int32(s.tau) }
+
+func (s *scheme) PublicKeySize() int { return 32 + 32*10*s.k }
+
+func (s *scheme) PrivateKeySize() int {
+	return 128 + 32*(int(s.etaBits())*(s.k+s.l)+d*s.k)
+}
+
+func (s *scheme) SignatureSize() int {
+	return s.cTildeSize + 32*int(s.gamma1Bits+1)*s.l + s.omega + s.k
+}
+
+// PublicKey is an ML-DSA verification key.
+type PublicKey struct {
+	sch *scheme
+	rho [32]byte
+	t1  []poly
+	pk  []byte // cached encoding
+	tr  [trSize]byte
+}
+
+// PrivateKey is an ML-DSA signing key.
+type PrivateKey struct {
+	sch    *scheme
+	rho    [32]byte
+	key    [32]byte
+	tr     [trSize]byte
+	s1, s2 []poly // NTT domain
+	t0     []poly // NTT domain
+	pub    *PublicKey
+}
+
+func (pk *PublicKey) Scheme() sign.Scheme { return pk.sch }
+
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, pk.pk...), nil
+}
+
+func (pk *PublicKey) Equal(other crypto.PublicKey) bool {
+	o, ok := other.(*PublicKey)
+
+	return ok && pk.sch == o.sch && bytes.Equal(pk.pk, o.pk)
+}
+
+func (sk *PrivateKey) Scheme() sign.Scheme { return sk.sch }
+
+func (sk *PrivateKey) Public() crypto.PublicKey { return sk.pub }
+
+func (sk *PrivateKey) Equal(other crypto.PrivateKey) bool {
+	o, ok := other.(*PrivateKey)
+	if !ok || sk.sch != o.sch {
+		return false
+	}
+	a, _ := sk.MarshalBinary()
+	b, _ := o.MarshalBinary()
+
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	s := sk.sch
+	out := make([]byte, 0, s.PrivateKeySize())
+	out = append(out, sk.rho[:]...)
+	out = append(out, sk.key[:]...)
+	out = append(out, sk.tr[:]...)
+	buf := make([]byte, 32*s.etaBits())
+	var t poly
+	for i := range sk.s1 {
+		t = sk.s1[i]
+		t.invNTT()
+		t.packOffset(buf, s.eta, s.etaBits())
+		out = append(out, buf...)
+	}
+	for i := range sk.s2 {
+		t = sk.s2[i]
+		t.invNTT()
+		t.packOffset(buf, s.eta, s.etaBits())
+		out = append(out, buf...)
+	}
+	buf = make([]byte, 32*d)
+	for i := range sk.t0 {
+		t = sk.t0[i]
+		t.invNTT()
+		t.packOffset(buf, 1<<(d-1), d)
+		out = append(out, buf...)
+	}
+
+	return out, nil
+}
+
+// Wipe zeroes the secret vectors and seeds.
+func (sk *PrivateKey) Wipe() {
+	for i := range sk.s1 {
+		sk.s1[i] = poly{}
+	}
+	for i := range sk.s2 {
+		sk.s2[i] = poly{}
+	}
+	wipe.Bytes(sk.key[:])
+}
+
+// expandA derives the public matrix in the NTT domain from rho.
+func (s *scheme) expandA(rho []byte) [][]poly {
+	a := make([][]poly, s.k)
+	for r := range a {
+		a[r] = make([]poly, s.l)
+		for c := range a[r] {
+			a[r][c].sampleNTT(rho, byte(c), byte(r))
+		}
+	}
+
+	return a
+}
+
+// deriveKey is ML-DSA.KeyGen_internal (FIPS 204, Algorithm 6).
+func (s *scheme) deriveKey(xi []byte) (*PublicKey, *PrivateKey) {
+	h := sha3.NewShake256()
+	_, _ = h.Write(xi)
+	_, _ = h.Write([]byte{byte(s.k), byte(s.l)})
+	var seeds [128]byte
+	_, _ = h.Read(seeds[:])
+	rho, rhoPrime, key := seeds[:32], seeds[32:96], seeds[96:]
+
+	pk := &PublicKey{sch: s, t1: make([]poly, s.k)}
+	sk := &PrivateKey{
+		sch: s, pub: pk,
+		s1: make([]poly, s.l), s2: make([]poly, s.k), t0: make([]poly, s.k),
+	}
+	copy(pk.rho[:], rho)
+	copy(sk.rho[:], rho)
+	copy(sk.key[:], key)
+
+	for i := range sk.s1 {
+		sk.s1[i].sampleBounded(rhoPrime, uint16(i), s.eta)
+		sk.s1[i].ntt()
+	}
+	for i := range sk.s2 {
+		sk.s2[i].sampleBounded(rhoPrime, uint16(s.l+i), s.eta)
+	}
+
+	a := s.expandA(rho)
+	var t, acc poly
+	for i := 0; i < s.k; i++ {
+		acc = poly{}
+		for j := 0; j < s.l; j++ {
+			t.mulNTT(&a[i][j], &sk.s1[j])
+			acc.add(&acc, &t)
+		}
+		acc.invNTT()
+		acc.add(&acc, &sk.s2[i])
+		for c := range acc {
+			r1, r0 := power2Round(acc[c])
+			pk.t1[i][c] = r1
+			r0 += (r0 >> 31) & q
+			sk.t0[i][c] = r0
+		}
+		sk.s2[i].ntt()
+		sk.t0[i].ntt()
+	}
+
+	pk.pk = make([]byte, 0, s.PublicKeySize())
+	pk.pk = append(pk.pk, rho...)
+	buf := make([]byte, 320)
+	for i := range pk.t1 {
+		pk.t1[i].pack(buf, 10)
+		pk.pk = append(pk.pk, buf...)
+	}
+	tr := sha3.NewShake256()
+	_, _ = tr.Write(pk.pk)
+	_, _ = tr.Read(pk.tr[:])
+	sk.tr = pk.tr
+
+	return pk, sk
+}
+
+func (s *scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	if len(seed) != seedSize {
+		panic(sign.ErrSeedSize)
+	}
+	pk, sk := s.deriveKey(seed)
+
+	return pk, sk
+}
+
+func (s *scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	var xi [seedSize]byte
+	if _, err := io.ReadFull(rand.Reader, xi[:]); err != nil {
+		return nil, nil, err
+	}
+	pk, sk := s.deriveKey(xi[:])
+
+	return pk, sk, nil
+}
+
+// messageRepresentative hashes the domain-separated message to mu
+// (FIPS 204, Algorithm 2 step M' and Algorithm 7 step 6).
+func (s *scheme) messageRepresentative(mu *[64]byte, tr, context, message []byte) {
+	h := sha3.NewShake256()
+	_, _ = h.Write(tr)
+	_, _ = h.Write([]byte{0, byte(len(context))})
+	_, _ = h.Write(context)
+	_, _ = h.Write(message)
+	_, _ = h.Read(mu[:])
+}
+
+// signMu is ML-DSA.Sign_internal (FIPS 204, Algorithm 7) from mu on.
+func (s *scheme) signMu(sig []byte, sk *PrivateKey, mu *[64]byte, rnd *[rndSize]byte) {
+	a := s.expandA(sk.rho[:])
+
+	var rhoPrime [64]byte
+	h := sha3.NewShake256()
+	_, _ = h.Write(sk.key[:])
+	_, _ = h.Write(rnd[:])
+	_, _ = h.Write(mu[:])
+	_, _ = h.Read(rhoPrime[:])
+
+	beta := s.beta()
+	gamma1 := int32(1) << s.gamma1Bits
+	y := make([]poly, s.l)
+	yHat := make([]poly, s.l)
+	w := make([]poly, s.k)
+	w1 := make([]poly, s.k)
+	var c, t, acc poly
+	w1Bits := uint(4)
+	if s.gamma2 == (q-1)/88 {
+		w1Bits = 6
+	}
+	w1Packed := make([]byte, 32*w1Bits*uint(s.k))
+
+	for kappa := 0; ; kappa += s.l {
+		for i := range y {
+			y[i].sampleMask(rhoPrime[:], uint16(kappa+i), s.gamma1Bits)
+			yHat[i] = y[i]
+			yHat[i].ntt()
+		}
+		for i := range w {
+			acc = poly{}
+			for j := 0; j < s.l; j++ {
+				t.mulNTT(&a[i][j], &yHat[j])
+				acc.add(&acc, &t)
+			}
+			acc.invNTT()
+			w[i] = acc
+			for cc := range acc {
+				w1[i][cc], _ = decompose(acc[cc], s.gamma2)
+			}
+			w1[i].pack(w1Packed[32*w1Bits*uint(i):], w1Bits)
+		}
+
+		cTilde := sig[:s.cTildeSize]
+		ch := sha3.NewShake256()
+		_, _ = ch.Write(mu[:])
+		_, _ = ch.Write(w1Packed)
+		_, _ = ch.Read(cTilde)
+		c.sampleInBall(cTilde, s.tau)
+		c.ntt()
+
+		// z = y + c*s1, rejected unless small.
+		z := make([]poly, s.l)
+		ok := true
+		for i := range z {
+			t.mulNTT(&c, &sk.s1[i])
+			t.invNTT()
+			z[i].add(&y[i], &t)
+			if z[i].normExceeds(gamma1 - beta) {
+				ok = false
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		// r0 = LowBits(w - c*s2), rejected unless small.
+		wcs2 := make([]poly, s.k)
+		for i := range wcs2 {
+			t.mulNTT(&c, &sk.s2[i])
+			t.invNTT()
+			wcs2[i].sub(&w[i], &t)
+			for _, cc := range wcs2[i] {
+				_, r0 := decompose(cc, s.gamma2)
+				if abs(r0) >= s.gamma2-beta {
+					ok = false
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		// Hints, rejected when c*t0 is large or they outnumber omega.
+		hintOnes := 0
+		hints := make([]poly, s.k)
+		for i := range hints {
+			t.mulNTT(&c, &sk.t0[i])
+			t.invNTT()
+			if t.normExceeds(s.gamma2) {
+				ok = false
+
+				break
+			}
+			for cc := range t {
+				// h = MakeHint(-c*t0, w - c*s2 + c*t0).
+				hints[i][cc] = makeHint(fqSub(0, t[cc]), fqAdd(wcs2[i][cc], t[cc]), s.gamma2)
+				hintOnes += int(hints[i][cc])
+			}
+		}
+		if !ok || hintOnes > s.omega {
+			continue
+		}
+
+		zOff := s.cTildeSize
+		zBytes := 32 * int(s.gamma1Bits+1)
+		for i := range z {
+			z[i].packOffset(sig[zOff+zBytes*i:], gamma1, s.gamma1Bits+1)
+		}
+		s.packHints(sig[zOff+zBytes*s.l:], hints)
+
+		return
+	}
+}
+
+// packHints is HintBitPack (FIPS 204, Algorithm 20).
+func (s *scheme) packHints(out []byte, hints []poly) {
+	for i := range out[:s.omega+s.k] {
+		out[i] = 0
+	}
+	idx := 0
+	for i := range hints {
+		for j, h := range hints[i] {
+			if h != 0 {
+				out[idx] = byte(j)
+				idx++
+			}
+		}
+		out[s.omega+i] = byte(idx)
+	}
+}
+
+// unpackHints is HintBitUnpack (FIPS 204, Algorithm 21), rejecting
+// malformed encodings so signatures stay non-malleable.
+func (s *scheme) unpackHints(in []byte) ([]poly, bool) {
+	hints := make([]poly, s.k)
+	idx := 0
+	for i := range hints {
+		end := int(in[s.omega+i])
+		if end < idx || end > s.omega {
+			return nil, false
+		}
+		for start := idx; idx < end; idx++ {
+			// Positions within a polynomial must strictly increase.
+			if idx > start && in[idx] <= in[idx-1] {
+				return nil, false
+			}
+			hints[i][in[idx]] = 1
+		}
+	}
+	for j := idx; j < s.omega; j++ {
+		if in[j] != 0 {
+			return nil, false
+		}
+	}
+
+	return hints, true
+}
+
+func (s *scheme) Sign(sk sign.PrivateKey, message []byte, opts *sign.SignatureOpts) []byte {
+	priv, ok := sk.(*PrivateKey)
+	if !ok || priv.sch != s {
+		panic(sign.ErrTypeMismatch)
+	}
+	var context []byte
+	if opts != nil {
+		context = []byte(opts.Context)
+	}
+	sig := make([]byte, s.SignatureSize())
+	if err := priv.signTo(sig, rand.Reader, message, context); err != nil {
+		panic(err)
+	}
+
+	return sig
+}
+
+// SignTo implements sign.SignerTo, writing a hedged signature into sig.
+func (s *scheme) SignTo(sig []byte, sk sign.PrivateKey, message []byte, opts *sign.SignatureOpts) {
+	priv, ok := sk.(*PrivateKey)
+	if !ok || priv.sch != s {
+		panic(sign.ErrTypeMismatch)
+	}
+	var context []byte
+	if opts != nil {
+		context = []byte(opts.Context)
+	}
+	if err := priv.signTo(sig, rand.Reader, message, context); err != nil {
+		panic(err)
+	}
+}
+
+// SignerOpts carries the options of crypto.Signer signing. The zero
+// value requests an empty context; the message is never prehashed.
+type SignerOpts struct {
+	// Context is the domain-separation string, at most 255 bytes.
+	Context string
+}
+
+// HashFunc returns zero, as ML-DSA signs the message itself.
+func (SignerOpts) HashFunc() crypto.Hash { return crypto.Hash(0) }
+
+// Sign implements crypto.Signer. The signature is hedged with 32 bytes
+// from rnd; a nil rnd selects the deterministic variant of FIPS 204.
+// opts may be a SignerOpts to pass a context string.
+func (sk *PrivateKey) Sign(rnd io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var context []byte
+	switch o := opts.(type) {
+	case SignerOpts:
+		context = []byte(o.Context)
+	case *SignerOpts:
+		context = []byte(o.Context)
+	default:
+		if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+			return nil, errors.New("mldsa: message must not be prehashed")
+		}
+	}
+	sig := make([]byte, sk.sch.SignatureSize())
+	if err := sk.signTo(sig, rnd, message, context); err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+func (sk *PrivateKey) signTo(sig []byte, rnd io.Reader, message, context []byte) error {
+	s := sk.sch
+	if len(sig) != s.SignatureSize() {
+		return errors.New("mldsa: signature buffer of wrong size")
+	}
+	if len(context) > 255 {
+		return errors.New("mldsa: context longer than 255 bytes")
+	}
+	var rb [rndSize]byte
+	if rnd != nil {
+		if _, err := io.ReadFull(rnd, rb[:]); err != nil {
+			return err
+		}
+	}
+	var mu [64]byte
+	s.messageRepresentative(&mu, sk.tr[:], context, message)
+	s.signMu(sig, sk, &mu, &rb)
+
+	return nil
+}
+
+func (s *scheme) Verify(pk sign.PublicKey, message, signature []byte, opts *sign.SignatureOpts) bool {
+	pub, ok := pk.(*PublicKey)
+	if !ok || pub.sch != s {
+		panic(sign.ErrTypeMismatch)
+	}
+	var context []byte
+	if opts != nil {
+		context = []byte(opts.Context)
+	}
+	if len(context) > 255 || len(signature) != s.SignatureSize() {
+		return false
+	}
+
+	cTilde := signature[:s.cTildeSize]
+	zOff := s.cTildeSize
+	zBytes := 32 * int(s.gamma1Bits+1)
+	gamma1 := int32(1) << s.gamma1Bits
+	z := make([]poly, s.l)
+	for i := range z {
+		z[i].unpackOffset(signature[zOff+zBytes*i:], gamma1, s.gamma1Bits+1)
+		if z[i].normExceeds(gamma1 - s.beta()) {
+			return false
+		}
+	}
+	hints, ok := s.unpackHints(signature[zOff+zBytes*s.l:])
+	if !ok {
+		return false
+	}
+
+	var mu [64]byte
+	s.messageRepresentative(&mu, pub.tr[:], context, message)
+
+	var c poly
+	c.sampleInBall(cTilde, s.tau)
+	c.ntt()
+
+	a := s.expandA(pub.rho[:])
+	var t, acc, t1 poly
+	w1Bits := uint(4)
+	if s.gamma2 == (q-1)/88 {
+		w1Bits = 6
+	}
+	w1Packed := make([]byte, 32*w1Bits*uint(s.k))
+	for i := 0; i < s.k; i++ {
+		acc = poly{}
+		for j := 0; j < s.l; j++ {
+			zHat := z[j]
+			zHat.ntt()
+			t.mulNTT(&a[i][j], &zHat)
+			acc.add(&acc, &t)
+		}
+		t1 = pub.t1[i]
+		for cc := range t1 {
+			t1[cc] = int32(uint64(t1[cc]) << d % q)
+		}
+		t1.ntt()
+		t.mulNTT(&c, &t1)
+		acc.sub(&acc, &t)
+		acc.invNTT()
+		var w1 poly
+		for cc := range acc {
+			w1[cc] = useHint(hints[i][cc], acc[cc], s.gamma2)
+		}
+		w1.pack(w1Packed[32*w1Bits*uint(i):], w1Bits)
+	}
+
+	cTilde2 := make([]byte, s.cTildeSize)
+	ch := sha3.NewShake256()
+	_, _ = ch.Write(mu[:])
+	_, _ = ch.Write(w1Packed)
+	_, _ = ch.Read(cTilde2)
+
+	return bytes.Equal(cTilde, cTilde2)
+}
+
+func (s *scheme) UnmarshalBinaryPublicKey(data []byte) (sign.PublicKey, error) {
+	if len(data) != s.PublicKeySize() {
+		return nil, sign.ErrPubKeySize
+	}
+	pk := &PublicKey{sch: s, t1: make([]poly, s.k)}
+	copy(pk.rho[:], data)
+	for i := range pk.t1 {
+		pk.t1[i].unpack(data[32+320*i:], 10)
+	}
+	pk.pk = append([]byte{}, data...)
+	tr := sha3.NewShake256()
+	_, _ = tr.Write(pk.pk)
+	_, _ = tr.Read(pk.tr[:])
+
+	return pk, nil
+}
+
+func (s *scheme) UnmarshalBinaryPrivateKey(data []byte) (sign.PrivateKey, error) {
+	if len(data) != s.PrivateKeySize() {
+		return nil, sign.ErrPrivKeySize
+	}
+	sk := &PrivateKey{
+		sch: s,
+		s1:  make([]poly, s.l), s2: make([]poly, s.k), t0: make([]poly, s.k),
+	}
+	copy(sk.rho[:], data)
+	copy(sk.key[:], data[32:])
+	copy(sk.tr[:], data[64:])
+	off := 128
+	etaBytes := 32 * int(s.etaBits())
+	for i := range sk.s1 {
+		sk.s1[i].unpackOffset(data[off:], s.eta, s.etaBits())
+		sk.s1[i].ntt()
+		off += etaBytes
+	}
+	for i := range sk.s2 {
+		sk.s2[i].unpackOffset(data[off:], s.eta, s.etaBits())
+		sk.s2[i].ntt()
+		off += etaBytes
+	}
+	t0 := make([]poly, s.k)
+	for i := range t0 {
+		t0[i].unpackOffset(data[off:], 1<<(d-1), d)
+		sk.t0[i] = t0[i]
+		sk.t0[i].ntt()
+		off += 32 * d
+	}
+
+	// Rebuild the public key: t1 from A*s1 + s2 via t0.
+	pk := &PublicKey{sch: s, t1: make([]poly, s.k)}
+	copy(pk.rho[:], sk.rho[:])
+	a := s.expandA(sk.rho[:])
+	var t, acc, s2 poly
+	for i := 0; i < s.k; i++ {
+		acc = poly{}
+		for j := 0; j < s.l; j++ {
+			t.mulNTT(&a[i][j], &sk.s1[j])
+			acc.add(&acc, &t)
+		}
+		s2 = sk.s2[i]
+		s2.invNTT()
+		acc.invNTT()
+		acc.add(&acc, &s2)
+		acc.sub(&acc, &t0[i])
+		for c := range acc {
+			pk.t1[i][c] = acc[c] >> d
+		}
+	}
+	pk.pk = make([]byte, 0, s.PublicKeySize())
+	pk.pk = append(pk.pk, sk.rho[:]...)
+	buf := make([]byte, 320)
+	for i := range pk.t1 {
+		pk.t1[i].pack(buf, 10)
+		pk.pk = append(pk.pk, buf...)
+	}
+	tr := sha3.NewShake256()
+	_, _ = tr.Write(pk.pk)
+	_, _ = tr.Read(pk.tr[:])
+	sk.pub = pk
+
+	return sk, nil
+}
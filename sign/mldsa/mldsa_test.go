@@ -0,0 +1,247 @@
+package mldsa_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/mldsa"
+)
+
+func shake128(s string, l int) []byte {
+	h := sha3.NewShake128()
+	_, _ = h.Write([]byte(s))
+	out := make([]byte, l)
+	_, _ = h.Read(out)
+	return out
+}
+
+func digest(b []byte) string {
+	h := sha3.New256()
+	_, _ = h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Known-answer vectors cross-checked against an independent implementation
+// of FIPS 204. The keygen seed xi for test t is SHAKE128("seed-<name>-<t>");
+// the message is "message <t>", signed deterministically, with context ""
+// for t=0 and "context" for t=1. Keys and signatures are recorded as
+// SHA3-256 digests.
+var katVectors = []struct {
+	name        string
+	t           int
+	pk, sk, sig string
+}{
+	{
+		"ML-DSA-44", 0,
+		"d27921957eb515029a62a53e7055763dcafc5e12cd6f180d832dd2f28c64ee13",
+		"424225cfba2d7dfbf5d7187cdd81b4631346a0a26d4fe8a0003f566bfda2d9fd",
+		"3b00cc2be7c25fad9ddac58108b5c67a940e19917f8a5c29ab96e2d9bed8a160",
+	},
+	{
+		"ML-DSA-44", 1,
+		"bbeffe38b0bf8856bd2f254d0f5ef29db4939001509faab9f1690151c7d9cd62",
+		"4c30a877f80f617aa443fdae75aeaf8f1a24c806299f77bb144b2fd97e094a2d",
+		"a40c55cf04ffbe17a43f18d822baa9e605e4f33ce1090da8f7c3c28b6ba7e4d3",
+	},
+	{
+		"ML-DSA-65", 0,
+		"c572aa2d7902ab9bdd6b7d9b2efcaf5688986ed91e4c98c84c4a01ab1b5f34a6",
+		"5fe3e126e5bb5b0d2edf8bdffc26b66f82feda32ec7db40cacbc8fdcc8a70981",
+		"4ad012cdee266292ed608365643443a760c22c281bae7f218fdfee51d17c365b",
+	},
+	{
+		"ML-DSA-65", 1,
+		"1d1adf018cc348b3ca8aaa3ed3af08686529ff6a3d4f582b1cd1e855249a5f2d",
+		"8f62d091d4bfb7affcd4a3ed3459bdda7db3ef217be33ffd3b3737405026a215",
+		"046f75a5aabb9f3b54f6fcdfdef304dc143276b5f27a7c9e42430d8fd9217a80",
+	},
+	{
+		"ML-DSA-87", 0,
+		"af10d711fdbcd7b529403e46331fae4414cedda5c0a509359f88cae761a84b7c",
+		"16704ae6fe86aa4a66517d16e1528fe05a3035bd2e958319d38fbb49ef7c66fb",
+		"4d93691d7bb4e377f7a8244d38cb89bbba53882478d5e571bdfa34e9b44eda18",
+	},
+	{
+		"ML-DSA-87", 1,
+		"4f0c7e442cd45dd70d3dc211d54b2f3b1e9e30e2873de259a19310def109b294",
+		"c33c01439510317637ec864cf48adf6d78b1dcf46039793f623b5366b24d0933",
+		"f6a8a4096e889a632b305de78be2dbbbf4814f4b02389b02df0b638731135f65",
+	},
+}
+
+func schemes() []sign.Scheme {
+	return []sign.Scheme{mldsa.MLDSA44, mldsa.MLDSA65, mldsa.MLDSA87}
+}
+
+func schemeByName(t *testing.T, name string) sign.Scheme {
+	t.Helper()
+	for _, s := range schemes() {
+		if s.Name() == name {
+			return s
+		}
+	}
+	t.Fatalf("unknown scheme %s", name)
+	return nil
+}
+
+func TestKAT(t *testing.T) {
+	for _, v := range katVectors {
+		t.Run(fmt.Sprintf("%s/%d", v.name, v.t), func(t *testing.T) {
+			sch := schemeByName(t, v.name)
+			seed := shake128(fmt.Sprintf("seed-%s-%d", v.name, v.t), sch.SeedSize())
+			msg := []byte(fmt.Sprintf("message %d", v.t))
+			ctx := ""
+			if v.t == 1 {
+				ctx = "context"
+			}
+
+			pub, priv := sch.DeriveKey(seed)
+			pk, err := pub.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sk, err := priv.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if digest(pk) != v.pk {
+				t.Error("public key mismatch")
+			}
+			if digest(sk) != v.sk {
+				t.Error("private key mismatch")
+			}
+
+			// Deterministic signing: a nil randomness source.
+			sig, err := priv.(*mldsa.PrivateKey).Sign(nil, msg, mldsa.SignerOpts{Context: ctx})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if digest(sig) != v.sig {
+				t.Error("signature mismatch")
+			}
+
+			opts := &sign.SignatureOpts{Context: ctx}
+			if !sch.Verify(pub, msg, sig, opts) {
+				t.Error("signature does not verify")
+			}
+			if sch.Verify(pub, append([]byte{}, append(msg, 'x')...), sig, opts) {
+				t.Error("signature verifies a different message")
+			}
+			if sch.Verify(pub, msg, sig, &sign.SignatureOpts{Context: ctx + "x"}) {
+				t.Error("signature verifies under a different context")
+			}
+			for _, i := range []int{0, len(sig) / 2, len(sig) - 1} {
+				bad := append([]byte{}, sig...)
+				bad[i] ^= 1
+				if sch.Verify(pub, msg, bad, opts) {
+					t.Errorf("tampered signature (byte %d) verifies", i)
+				}
+			}
+		})
+	}
+}
+
+func TestHedged(t *testing.T) {
+	for _, sch := range schemes() {
+		t.Run(sch.Name(), func(t *testing.T) {
+			pub, priv, err := sch.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg := []byte("hedged")
+			sig1 := sch.Sign(priv, msg, nil)
+			sig2 := sch.Sign(priv, msg, nil)
+			if bytes.Equal(sig1, sig2) {
+				t.Error("hedged signatures repeat")
+			}
+			if !sch.Verify(pub, msg, sig1, nil) || !sch.Verify(pub, msg, sig2, nil) {
+				t.Error("hedged signature does not verify")
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	for _, sch := range schemes() {
+		t.Run(sch.Name(), func(t *testing.T) {
+			pub, priv, err := sch.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			pk, err := pub.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			pub2, err := sch.UnmarshalBinaryPublicKey(pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pub2.Equal(pub) {
+				t.Error("public key does not roundtrip")
+			}
+
+			sk, err := priv.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			priv2, err := sch.UnmarshalBinaryPrivateKey(sk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !priv2.Equal(priv) {
+				t.Error("private key does not roundtrip")
+			}
+			// The rebuilt public key must match the original.
+			if !priv2.Public().(*mldsa.PublicKey).Equal(pub) {
+				t.Error("restored private key has a different public key")
+			}
+
+			if _, err = sch.UnmarshalBinaryPublicKey(pk[:len(pk)-1]); err == nil {
+				t.Error("truncated public key accepted")
+			}
+			if _, err = sch.UnmarshalBinaryPrivateKey(sk[:len(sk)-1]); err == nil {
+				t.Error("truncated private key accepted")
+			}
+		})
+	}
+}
+
+func TestLongContext(t *testing.T) {
+	_, priv, err := mldsa.MLDSA44.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := make([]byte, 256)
+	if _, err = rand.Read(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = priv.(*mldsa.PrivateKey).Sign(nil, []byte("msg"),
+		mldsa.SignerOpts{Context: string(ctx)}); err == nil {
+		t.Error("context longer than 255 bytes accepted")
+	}
+}
+
+func BenchmarkMLDSA65(b *testing.B) {
+	sch := mldsa.MLDSA65
+	pub, priv, err := sch.GenerateKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+	msg := []byte("benchmark message")
+	sig := sch.Sign(priv, msg, nil)
+	b.Run("Sign", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = sch.Sign(priv, msg, nil)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = sch.Verify(pub, msg, sig, nil)
+		}
+	})
+}
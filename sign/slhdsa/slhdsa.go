@@ -0,0 +1,944 @@
+// Package slhdsa implements SLH-DSA, the stateless hash-based signature
+// scheme standardized in FIPS 205, for users who need conservative
+// post-quantum signatures whose security rests only on the hash
+// function.
+//
+// All twelve parameter sets are exposed as sign.Scheme instances, named
+// after the hash family (SHA2 or SHAKE), the security level, and the
+// small-signature (s) or fast-signing (f) trade-off. Signing is hedged
+// by default; a nil randomness source passed to PrivateKey.Sign selects
+// the deterministic variant. Context strings up to 255 bytes are
+// supported through sign.SignatureOpts.
+//
+// Messages need not be held in memory at once: NewSigner and
+// NewVerifier return io.Writers that consume the message as a stream.
+// The verifier runs in constant memory; the signer buffers, since the
+// randomized message digest of FIPS 205 depends on the whole message.
+package slhdsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/wipe"
+	"github.com/cloudflare/circl/rand"
+	"github.com/cloudflare/circl/schemes"
+	"github.com/cloudflare/circl/sign"
+)
+
+// Parameter sets of FIPS 205, Table 2.
+var (
+	SHA2128s  sign.Scheme = &scheme{name: "SLH-DSA-SHA2-128s", sha2: true, n: 16, h: 63, d: 7, hp: 9, a: 12, k: 14, m: 30}
+	SHA2128f  sign.Scheme = &scheme{name: "SLH-DSA-SHA2-128f", sha2: true, n: 16, h: 66, d: 22, hp: 3, a: 6, k: 33, m: 34}
+	SHA2192s  sign.Scheme = &scheme{name: "SLH-DSA-SHA2-192s", sha2: true, n: 24, h: 63, d: 7, hp: 9, a: 14, k: 17, m: 39}
+	SHA2192f  sign.Scheme = &scheme{name: "SLH-DSA-SHA2-192f", sha2: true, n: 24, h: 66, d: 22, hp: 3, a: 8, k: 33, m: 42}
+	SHA2256s  sign.Scheme = &scheme{name: "SLH-DSA-SHA2-256s", sha2: true, n: 32, h: 64, d: 8, hp: 8, a: 14, k: 22, m: 47}
+	SHA2256f  sign.Scheme = &scheme{name: "SLH-DSA-SHA2-256f", sha2: true, n: 32, h: 68, d: 17, hp: 4, a: 9, k: 35, m: 49}
+	SHAKE128s sign.Scheme = &scheme{name: "SLH-DSA-SHAKE-128s", n: 16, h: 63, d: 7, hp: 9, a: 12, k: 14, m: 30}
+	SHAKE128f sign.Scheme = &scheme{name: "SLH-DSA-SHAKE-128f", n: 16, h: 66, d: 22, hp: 3, a: 6, k: 33, m: 34}
+	SHAKE192s sign.Scheme = &scheme{name: "SLH-DSA-SHAKE-192s", n: 24, h: 63, d: 7, hp: 9, a: 14, k: 17, m: 39}
+	SHAKE192f sign.Scheme = &scheme{name: "SLH-DSA-SHAKE-192f", n: 24, h: 66, d: 22, hp: 3, a: 8, k: 33, m: 42}
+	SHAKE256s sign.Scheme = &scheme{name: "SLH-DSA-SHAKE-256s", n: 32, h: 64, d: 8, hp: 8, a: 14, k: 22, m: 47}
+	SHAKE256f sign.Scheme = &scheme{name: "SLH-DSA-SHAKE-256f", n: 32, h: 68, d: 17, hp: 4, a: 9, k: 35, m: 49}
+)
+
+// Schemes lists every parameter set, in the order of FIPS 205 Table 2.
+var Schemes = []sign.Scheme{
+	SHA2128s, SHA2128f, SHA2192s, SHA2192f, SHA2256s, SHA2256f,
+	SHAKE128s, SHAKE128f, SHAKE192s, SHAKE192f, SHAKE256s, SHAKE256f,
+}
+
+func init() {
+	oidBase := asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3}
+	for i, s := range Schemes {
+		oid := append(asn1.ObjectIdentifier{}, oidBase...)
+		err := schemes.Register(schemes.Entry{
+			Kind:      schemes.KindSignature,
+			Name:      s.Name(),
+			OID:       append(oid, 20+i),
+			Signature: s,
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// WOTS+ uses the Winternitz parameter w=16 throughout FIPS 205.
+const (
+	wotsW    = 16
+	wotsLen2 = 3
+)
+
+// scheme is one SLH-DSA parameter set.
+type scheme struct {
+	name string
+	sha2 bool // SHA2 family; SHAKE otherwise
+	n    int  // hash output and seed length
+	h    int  // total hypertree height
+	d    int  // hypertree layers
+	hp   int  // height of each XMSS subtree (h' = h/d)
+	a    int  // height of each FORS tree
+	k    int  // number of FORS trees
+	m    int  // message-digest length
+}
+
+func (s *scheme) Name() string          { return s.name }
+func (s *scheme) SupportsContext() bool { return true }
+func (s *scheme) PublicKeySize() int    { return 2 * s.n }
+func (s *scheme) PrivateKeySize() int   { return 4 * s.n }
+func (s *scheme) SeedSize() int         { return 3 * s.n }
+
+func (s *scheme) wotsLen() int { return 2*s.n + wotsLen2 }
+
+func (s *scheme) SignatureSize() int {
+	return s.n * (1 + s.k*(1+s.a) + s.h + s.d*s.wotsLen())
+}
+
+// Hypertree addresses (FIPS 205, section 4.2).
+const (
+	addrWOTSHash = iota
+	addrWOTSPK
+	addrTree
+	addrFORSTree
+	addrFORSRoots
+	addrWOTSPRF
+	addrFORSPRF
+)
+
+type address [32]byte
+
+func (a *address) setLayer(l uint32)      { binary.BigEndian.PutUint32(a[0:], l) }
+func (a *address) setTree(t uint64)       { binary.BigEndian.PutUint64(a[8:], t) }
+func (a *address) setType(t uint32)       { binary.BigEndian.PutUint32(a[16:], t) }
+func (a *address) setKeyPair(i uint32)    { binary.BigEndian.PutUint32(a[20:], i) }
+func (a *address) setChain(i uint32)      { binary.BigEndian.PutUint32(a[24:], i) }
+func (a *address) setHash(i uint32)       { binary.BigEndian.PutUint32(a[28:], i) }
+func (a *address) setTreeHeight(i uint32) { a.setChain(i) }
+func (a *address) setTreeIndex(i uint32)  { a.setHash(i) }
+func (a *address) keyPair() uint32        { return binary.BigEndian.Uint32(a[20:]) }
+
+// compressed is the 22-byte form used by the SHA2 family.
+func (a *address) compressed() []byte {
+	c := make([]byte, 0, 22)
+	c = append(c, a[3])
+	c = append(c, a[8:16]...)
+	c = append(c, a[19])
+
+	return append(c, a[20:32]...)
+}
+
+// The tweakable hash functions of FIPS 205, section 10. The SHAKE
+// family instantiates everything with SHAKE256; the SHA2 family uses
+// SHA-256, padded to the block size, with SHA-512 for the wide
+// functions at security categories 3 and 5.
+
+func (s *scheme) shakeSum(out int, parts ...[]byte) []byte {
+	h := sha3.NewShake256()
+	for _, p := range parts {
+		_, _ = h.Write(p)
+	}
+	buf := make([]byte, out)
+	_, _ = h.Read(buf)
+
+	return buf
+}
+
+// sha2Wide returns the hash used by H, T, H_msg, and PRF_msg, together
+// with its block size.
+func (s *scheme) sha2Wide() (func() hash.Hash, int) {
+	if s.n == 16 {
+		return sha256.New, 64
+	}
+
+	return sha512.New, 128
+}
+
+func (s *scheme) sha2Trunc(newHash func() hash.Hash, pad int, adrs *address, parts ...[]byte) []byte {
+	h := newHash()
+	_, _ = h.Write(parts[0]) // pkseed
+	_, _ = h.Write(make([]byte, pad-s.n))
+	_, _ = h.Write(adrs.compressed())
+	for _, p := range parts[1:] {
+		_, _ = h.Write(p)
+	}
+
+	return h.Sum(nil)[:s.n]
+}
+
+// prf derives a WOTS+ or FORS secret value.
+func (s *scheme) prf(pkseed, skseed []byte, adrs *address) []byte {
+	if s.sha2 {
+		return s.sha2Trunc(sha256.New, 64, adrs, pkseed, skseed)
+	}
+
+	return s.shakeSum(s.n, pkseed, adrs[:], skseed)
+}
+
+// f hashes a single n-byte block.
+func (s *scheme) f(pkseed []byte, adrs *address, m []byte) []byte {
+	if s.sha2 {
+		return s.sha2Trunc(sha256.New, 64, adrs, pkseed, m)
+	}
+
+	return s.shakeSum(s.n, pkseed, adrs[:], m)
+}
+
+// h2 hashes two sibling nodes.
+func (s *scheme) h2(pkseed []byte, adrs *address, left, right []byte) []byte {
+	if s.sha2 {
+		nh, bs := s.sha2Wide()
+
+		return s.sha2Trunc(nh, bs, adrs, pkseed, left, right)
+	}
+
+	return s.shakeSum(s.n, pkseed, adrs[:], left, right)
+}
+
+// t compresses an arbitrary number of n-byte blocks.
+func (s *scheme) t(pkseed []byte, adrs *address, m []byte) []byte {
+	if s.sha2 {
+		nh, bs := s.sha2Wide()
+
+		return s.sha2Trunc(nh, bs, adrs, pkseed, m)
+	}
+
+	return s.shakeSum(s.n, pkseed, adrs[:], m)
+}
+
+// prfMsg derives the signature randomizer R.
+func (s *scheme) prfMsg(skprf, optrand []byte, msg ...[]byte) []byte {
+	if s.sha2 {
+		nh, _ := s.sha2Wide()
+		mac := hmac.New(nh, skprf)
+		_, _ = mac.Write(optrand)
+		for _, p := range msg {
+			_, _ = mac.Write(p)
+		}
+
+		return mac.Sum(nil)[:s.n]
+	}
+	parts := append([][]byte{skprf, optrand}, msg...)
+
+	return s.shakeSum(s.n, parts...)
+}
+
+// hMsgStream returns a streaming message digester: feed the message
+// after R, pkseed, and pkroot are known, then finish to m bytes.
+type msgDigester interface {
+	io.Writer
+	digest() []byte
+}
+
+type shakeDigester struct {
+	st sha3.State
+	m  int
+}
+
+func (d *shakeDigester) Write(p []byte) (int, error) { return d.st.Write(p) }
+
+func (d *shakeDigester) digest() []byte {
+	out := make([]byte, d.m)
+	_, _ = d.st.Read(out)
+
+	return out
+}
+
+type sha2Digester struct {
+	inner   hash.Hash
+	newHash func() hash.Hash
+	prefix  []byte // R || pkseed
+	m       int
+}
+
+func (d *sha2Digester) Write(p []byte) (int, error) { return d.inner.Write(p) }
+
+func (d *sha2Digester) digest() []byte {
+	// MGF1(R || pkseed || inner, m).
+	seed := append(append([]byte{}, d.prefix...), d.inner.Sum(nil)...)
+	out := make([]byte, 0, d.m)
+	var ctr [4]byte
+	for i := uint32(0); len(out) < d.m; i++ {
+		binary.BigEndian.PutUint32(ctr[:], i)
+		h := d.newHash()
+		_, _ = h.Write(seed)
+		_, _ = h.Write(ctr[:])
+		out = append(out, h.Sum(nil)...)
+	}
+
+	return out[:d.m]
+}
+
+func (s *scheme) hMsgStream(r, pkseed, pkroot []byte) msgDigester {
+	if s.sha2 {
+		nh, _ := s.sha2Wide()
+		d := &sha2Digester{inner: nh(), newHash: nh, m: s.m}
+		d.prefix = append(append([]byte{}, r...), pkseed...)
+		_, _ = d.inner.Write(r)
+		_, _ = d.inner.Write(pkseed)
+		_, _ = d.inner.Write(pkroot)
+
+		return d
+	}
+	d := &shakeDigester{st: sha3.NewShake256(), m: s.m}
+	_, _ = d.st.Write(r)
+	_, _ = d.st.Write(pkseed)
+	_, _ = d.st.Write(pkroot)
+
+	return d
+}
+
+// WOTS+ one-time signatures (FIPS 205, section 5).
+
+// chain applies f count times starting at step start.
+func (s *scheme) chain(x []byte, start, count int, pkseed []byte, adrs *address) []byte {
+	out := append([]byte{}, x...)
+	for j := start; j < start+count; j++ {
+		adrs.setHash(uint32(j))
+		out = s.f(pkseed, adrs, out)
+	}
+
+	return out
+}
+
+// baseW expands an n-byte value into base-16 digits plus its checksum.
+func (s *scheme) baseW(m []byte) []uint8 {
+	digits := make([]uint8, 0, s.wotsLen())
+	for _, b := range m {
+		digits = append(digits, b>>4, b&0x0f)
+	}
+	csum := 0
+	for _, d := range digits {
+		csum += wotsW - 1 - int(d)
+	}
+	// The checksum fits in wotsLen2 base-16 digits, big-endian.
+	digits = append(digits, uint8(csum>>8&0x0f), uint8(csum>>4&0x0f), uint8(csum&0x0f))
+
+	return digits
+}
+
+func (s *scheme) wotsPKGen(skseed, pkseed []byte, adrs *address) []byte {
+	skAdrs := *adrs
+	skAdrs.setType(addrWOTSPRF)
+	skAdrs.setKeyPair(adrs.keyPair())
+	tmp := make([]byte, 0, s.wotsLen()*s.n)
+	for i := 0; i < s.wotsLen(); i++ {
+		skAdrs.setChain(uint32(i))
+		sk := s.prf(pkseed, skseed, &skAdrs)
+		adrs.setChain(uint32(i))
+		tmp = append(tmp, s.chain(sk, 0, wotsW-1, pkseed, adrs)...)
+	}
+	pkAdrs := *adrs
+	pkAdrs.setType(addrWOTSPK)
+	pkAdrs.setKeyPair(adrs.keyPair())
+	pkAdrs.setTreeHeight(0)
+	pkAdrs.setTreeIndex(0)
+
+	return s.t(pkseed, &pkAdrs, tmp)
+}
+
+func (s *scheme) wotsSign(sig, m, skseed, pkseed []byte, adrs *address) {
+	digits := s.baseW(m)
+	skAdrs := *adrs
+	skAdrs.setType(addrWOTSPRF)
+	skAdrs.setKeyPair(adrs.keyPair())
+	for i, d := range digits {
+		skAdrs.setChain(uint32(i))
+		sk := s.prf(pkseed, skseed, &skAdrs)
+		adrs.setChain(uint32(i))
+		copy(sig[i*s.n:], s.chain(sk, 0, int(d), pkseed, adrs))
+	}
+}
+
+func (s *scheme) wotsPKFromSig(sig, m, pkseed []byte, adrs *address) []byte {
+	digits := s.baseW(m)
+	tmp := make([]byte, 0, s.wotsLen()*s.n)
+	for i, d := range digits {
+		adrs.setChain(uint32(i))
+		tmp = append(tmp, s.chain(sig[i*s.n:(i+1)*s.n], int(d), wotsW-1-int(d), pkseed, adrs)...)
+	}
+	pkAdrs := *adrs
+	pkAdrs.setType(addrWOTSPK)
+	pkAdrs.setKeyPair(adrs.keyPair())
+	pkAdrs.setTreeHeight(0)
+	pkAdrs.setTreeIndex(0)
+
+	return s.t(pkseed, &pkAdrs, tmp)
+}
+
+// XMSS subtrees (FIPS 205, section 6).
+
+func (s *scheme) xmssNode(skseed, pkseed []byte, i, z uint32, adrs *address) []byte {
+	if z == 0 {
+		leafAdrs := *adrs
+		leafAdrs.setType(addrWOTSHash)
+		leafAdrs.setKeyPair(i)
+
+		return s.wotsPKGen(skseed, pkseed, &leafAdrs)
+	}
+	left := s.xmssNode(skseed, pkseed, 2*i, z-1, adrs)
+	right := s.xmssNode(skseed, pkseed, 2*i+1, z-1, adrs)
+	nodeAdrs := *adrs
+	nodeAdrs.setType(addrTree)
+	nodeAdrs.setTreeHeight(z)
+	nodeAdrs.setTreeIndex(i)
+
+	return s.h2(pkseed, &nodeAdrs, left, right)
+}
+
+func (s *scheme) xmssSign(sig, m, skseed, pkseed []byte, idx uint32, adrs *address) {
+	wotsAdrs := *adrs
+	wotsAdrs.setType(addrWOTSHash)
+	wotsAdrs.setKeyPair(idx)
+	s.wotsSign(sig, m, skseed, pkseed, &wotsAdrs)
+	auth := sig[s.wotsLen()*s.n:]
+	for j := 0; j < s.hp; j++ {
+		copy(auth[j*s.n:], s.xmssNode(skseed, pkseed, idx>>j^1, uint32(j), adrs))
+	}
+}
+
+func (s *scheme) xmssPKFromSig(idx uint32, sig, m, pkseed []byte, adrs *address) []byte {
+	wotsAdrs := *adrs
+	wotsAdrs.setType(addrWOTSHash)
+	wotsAdrs.setKeyPair(idx)
+	node := s.wotsPKFromSig(sig, m, pkseed, &wotsAdrs)
+	auth := sig[s.wotsLen()*s.n:]
+	nodeAdrs := *adrs
+	nodeAdrs.setType(addrTree)
+	for j := 0; j < s.hp; j++ {
+		nodeAdrs.setTreeHeight(uint32(j + 1))
+		nodeAdrs.setTreeIndex(idx >> (j + 1))
+		sib := auth[j*s.n : (j+1)*s.n]
+		if idx>>j&1 == 0 {
+			node = s.h2(pkseed, &nodeAdrs, node, sib)
+		} else {
+			node = s.h2(pkseed, &nodeAdrs, sib, node)
+		}
+	}
+
+	return node
+}
+
+// The hypertree (FIPS 205, section 7).
+
+func (s *scheme) xmssSigSize() int { return (s.wotsLen() + s.hp) * s.n }
+
+func (s *scheme) htSign(sig, m, skseed, pkseed []byte, idxTree uint64, idxLeaf uint32) {
+	var adrs address
+	root := append([]byte{}, m...)
+	for j := 0; j < s.d; j++ {
+		adrs = address{}
+		adrs.setLayer(uint32(j))
+		adrs.setTree(idxTree)
+		part := sig[j*s.xmssSigSize() : (j+1)*s.xmssSigSize()]
+		s.xmssSign(part, root, skseed, pkseed, idxLeaf, &adrs)
+		if j < s.d-1 {
+			root = s.xmssPKFromSig(idxLeaf, part, root, pkseed, &adrs)
+			idxLeaf = uint32(idxTree & (1<<s.hp - 1))
+			idxTree >>= s.hp
+		}
+	}
+}
+
+func (s *scheme) htVerify(m, sig, pkseed []byte, idxTree uint64, idxLeaf uint32, pkroot []byte) bool {
+	var adrs address
+	node := append([]byte{}, m...)
+	for j := 0; j < s.d; j++ {
+		adrs = address{}
+		adrs.setLayer(uint32(j))
+		adrs.setTree(idxTree)
+		part := sig[j*s.xmssSigSize() : (j+1)*s.xmssSigSize()]
+		node = s.xmssPKFromSig(idxLeaf, part, node, pkseed, &adrs)
+		idxLeaf = uint32(idxTree & (1<<s.hp - 1))
+		idxTree >>= s.hp
+	}
+
+	return bytes.Equal(node, pkroot)
+}
+
+// FORS few-time signatures (FIPS 205, section 8).
+
+func (s *scheme) forsSKGen(skseed, pkseed []byte, adrs *address, idx uint32) []byte {
+	skAdrs := *adrs
+	skAdrs.setType(addrFORSPRF)
+	skAdrs.setKeyPair(adrs.keyPair())
+	skAdrs.setTreeHeight(0)
+	skAdrs.setTreeIndex(idx)
+
+	return s.prf(pkseed, skseed, &skAdrs)
+}
+
+func (s *scheme) forsNode(skseed, pkseed []byte, i, z uint32, adrs *address) []byte {
+	if z == 0 {
+		sk := s.forsSKGen(skseed, pkseed, adrs, i)
+		adrs.setTreeHeight(0)
+		adrs.setTreeIndex(i)
+
+		return s.f(pkseed, adrs, sk)
+	}
+	left := s.forsNode(skseed, pkseed, 2*i, z-1, adrs)
+	right := s.forsNode(skseed, pkseed, 2*i+1, z-1, adrs)
+	adrs.setTreeHeight(z)
+	adrs.setTreeIndex(i)
+
+	return s.h2(pkseed, adrs, left, right)
+}
+
+// forsIndices splits the message digest into k a-bit indices.
+func (s *scheme) forsIndices(md []byte) []uint32 {
+	idx := make([]uint32, s.k)
+	off := 0
+	for i := range idx {
+		var v uint32
+		for j := 0; j < s.a; j++ {
+			v = v<<1 | uint32(md[off/8]>>(7-off%8)&1)
+			off++
+		}
+		idx[i] = v
+	}
+
+	return idx
+}
+
+func (s *scheme) forsSign(sig, md, skseed, pkseed []byte, adrs *address) {
+	indices := s.forsIndices(md)
+	step := (1 + s.a) * s.n
+	for i, idx := range indices {
+		gidx := uint32(i)<<s.a + idx
+		part := sig[i*step:]
+		copy(part, s.forsSKGen(skseed, pkseed, adrs, gidx))
+		for j := 0; j < s.a; j++ {
+			copy(part[(1+j)*s.n:], s.forsNode(skseed, pkseed, gidx>>j^1, uint32(j), adrs))
+		}
+	}
+}
+
+func (s *scheme) forsPKFromSig(sig, md, pkseed []byte, adrs *address) []byte {
+	indices := s.forsIndices(md)
+	step := (1 + s.a) * s.n
+	roots := make([]byte, 0, s.k*s.n)
+	for i, idx := range indices {
+		gidx := uint32(i)<<s.a + idx
+		part := sig[i*step:]
+		adrs.setTreeHeight(0)
+		adrs.setTreeIndex(gidx)
+		node := s.f(pkseed, adrs, part[:s.n])
+		for j := 0; j < s.a; j++ {
+			adrs.setTreeHeight(uint32(j + 1))
+			adrs.setTreeIndex(gidx >> (j + 1))
+			sib := part[(1+j)*s.n : (2+j)*s.n]
+			if gidx>>j&1 == 0 {
+				node = s.h2(pkseed, adrs, node, sib)
+			} else {
+				node = s.h2(pkseed, adrs, sib, node)
+			}
+		}
+		roots = append(roots, node...)
+	}
+	pkAdrs := *adrs
+	pkAdrs.setType(addrFORSRoots)
+	pkAdrs.setKeyPair(adrs.keyPair())
+	pkAdrs.setTreeHeight(0)
+	pkAdrs.setTreeIndex(0)
+
+	return s.t(pkseed, &pkAdrs, roots)
+}
+
+// Keys.
+
+// PublicKey is an SLH-DSA verification key: pkseed || pkroot.
+type PublicKey struct {
+	sch  *scheme
+	seed []byte
+	root []byte
+}
+
+// PrivateKey is an SLH-DSA signing key: skseed || skprf || pkseed ||
+// pkroot.
+type PrivateKey struct {
+	sch    *scheme
+	skseed []byte
+	skprf  []byte
+	pub    *PublicKey
+}
+
+func (pk *PublicKey) Scheme() sign.Scheme { return pk.sch }
+
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	return append(append([]byte{}, pk.seed...), pk.root...), nil
+}
+
+func (pk *PublicKey) Equal(other crypto.PublicKey) bool {
+	o, ok := other.(*PublicKey)
+
+	return ok && pk.sch == o.sch &&
+		bytes.Equal(pk.seed, o.seed) && bytes.Equal(pk.root, o.root)
+}
+
+func (sk *PrivateKey) Scheme() sign.Scheme { return sk.sch }
+
+func (sk *PrivateKey) Public() crypto.PublicKey { return sk.pub }
+
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	out := append(append([]byte{}, sk.skseed...), sk.skprf...)
+	out = append(out, sk.pub.seed...)
+
+	return append(out, sk.pub.root...), nil
+}
+
+func (sk *PrivateKey) Equal(other crypto.PrivateKey) bool {
+	o, ok := other.(*PrivateKey)
+	if !ok || sk.sch != o.sch {
+		return false
+	}
+	a, _ := sk.MarshalBinary()
+	b, _ := o.MarshalBinary()
+
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Wipe zeroes the secret seeds.
+func (sk *PrivateKey) Wipe() {
+	wipe.Bytes(sk.skseed)
+	wipe.Bytes(sk.skprf)
+}
+
+// deriveKey is SLH-DSA key generation from the seed skseed || skprf ||
+// pkseed (FIPS 205, Algorithm 18).
+func (s *scheme) deriveKey(seed []byte) (*PublicKey, *PrivateKey) {
+	n := s.n
+	pk := &PublicKey{sch: s, seed: append([]byte{}, seed[2*n:3*n]...)}
+	sk := &PrivateKey{
+		sch:    s,
+		skseed: append([]byte{}, seed[:n]...),
+		skprf:  append([]byte{}, seed[n:2*n]...),
+		pub:    pk,
+	}
+	var adrs address
+	adrs.setLayer(uint32(s.d - 1))
+	pk.root = s.xmssNode(sk.skseed, pk.seed, 0, uint32(s.hp), &adrs)
+
+	return pk, sk
+}
+
+func (s *scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	if len(seed) != s.SeedSize() {
+		panic(sign.ErrSeedSize)
+	}
+	pk, sk := s.deriveKey(seed)
+
+	return pk, sk
+}
+
+func (s *scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	seed := make([]byte, s.SeedSize())
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	pk, sk := s.deriveKey(seed)
+
+	return pk, sk, nil
+}
+
+func (s *scheme) UnmarshalBinaryPublicKey(data []byte) (sign.PublicKey, error) {
+	if len(data) != s.PublicKeySize() {
+		return nil, sign.ErrPubKeySize
+	}
+
+	return &PublicKey{
+		sch:  s,
+		seed: append([]byte{}, data[:s.n]...),
+		root: append([]byte{}, data[s.n:]...),
+	}, nil
+}
+
+func (s *scheme) UnmarshalBinaryPrivateKey(data []byte) (sign.PrivateKey, error) {
+	if len(data) != s.PrivateKeySize() {
+		return nil, sign.ErrPrivKeySize
+	}
+	n := s.n
+	pk := &PublicKey{
+		sch:  s,
+		seed: append([]byte{}, data[2*n:3*n]...),
+		root: append([]byte{}, data[3*n:]...),
+	}
+
+	return &PrivateKey{
+		sch:    s,
+		skseed: append([]byte{}, data[:n]...),
+		skprf:  append([]byte{}, data[n:2*n]...),
+		pub:    pk,
+	}, nil
+}
+
+// Signing and verification.
+
+// digestSplit parses the message digest into the FORS message, the
+// hypertree index, and the leaf index (FIPS 205, Algorithm 19).
+func (s *scheme) digestSplit(digest []byte) (md []byte, idxTree uint64, idxLeaf uint32) {
+	mdLen := (s.k*s.a + 7) / 8
+	treeLen := (s.h - s.hp + 7) / 8
+	leafLen := (s.hp + 7) / 8
+	md = digest[:mdLen]
+	for _, b := range digest[mdLen : mdLen+treeLen] {
+		idxTree = idxTree<<8 | uint64(b)
+	}
+	if s.h-s.hp < 64 {
+		idxTree &= 1<<(s.h-s.hp) - 1
+	}
+	for _, b := range digest[mdLen+treeLen : mdLen+treeLen+leafLen] {
+		idxLeaf = idxLeaf<<8 | uint32(b)
+	}
+	idxLeaf &= 1<<s.hp - 1
+
+	return
+}
+
+// signDigest completes a signature once R and the message digest are
+// fixed; sig[:n] must already hold R.
+func (s *scheme) signDigest(sig, digest []byte, sk *PrivateKey) {
+	md, idxTree, idxLeaf := s.digestSplit(digest)
+	var adrs address
+	adrs.setTree(idxTree)
+	adrs.setType(addrFORSTree)
+	adrs.setKeyPair(idxLeaf)
+
+	forsSize := s.k * (1 + s.a) * s.n
+	forsSig := sig[s.n : s.n+forsSize]
+	s.forsSign(forsSig, md, sk.skseed, sk.pub.seed, &adrs)
+	forsPK := s.forsPKFromSig(forsSig, md, sk.pub.seed, &adrs)
+	s.htSign(sig[s.n+forsSize:], forsPK, sk.skseed, sk.pub.seed, idxTree, idxLeaf)
+}
+
+func (s *scheme) verifyDigest(sig, digest []byte, pk *PublicKey) bool {
+	md, idxTree, idxLeaf := s.digestSplit(digest)
+	var adrs address
+	adrs.setTree(idxTree)
+	adrs.setType(addrFORSTree)
+	adrs.setKeyPair(idxLeaf)
+
+	forsSize := s.k * (1 + s.a) * s.n
+	forsPK := s.forsPKFromSig(sig[s.n:s.n+forsSize], md, pk.seed, &adrs)
+
+	return s.htVerify(forsPK, sig[s.n+forsSize:], pk.seed, idxTree, idxLeaf, pk.root)
+}
+
+// msgPrefix is the pure-message domain separator of FIPS 205: a zero
+// byte, the context length, and the context.
+func msgPrefix(context []byte) []byte {
+	return append([]byte{0, byte(len(context))}, context...)
+}
+
+func (sk *PrivateKey) signTo(sig []byte, rnd io.Reader, message, context []byte) error {
+	s := sk.sch
+	if len(sig) != s.SignatureSize() {
+		return errors.New("slhdsa: signature buffer of wrong size")
+	}
+	if len(context) > 255 {
+		return errors.New("slhdsa: context longer than 255 bytes")
+	}
+	// The deterministic variant uses pkseed as the optional randomness.
+	optrand := append([]byte{}, sk.pub.seed...)
+	if rnd != nil {
+		if _, err := io.ReadFull(rnd, optrand); err != nil {
+			return err
+		}
+	}
+	prefix := msgPrefix(context)
+	r := s.prfMsg(sk.skprf, optrand, prefix, message)
+	copy(sig, r)
+	hm := s.hMsgStream(r, sk.pub.seed, sk.pub.root)
+	_, _ = hm.Write(prefix)
+	_, _ = hm.Write(message)
+	s.signDigest(sig, hm.digest(), sk)
+
+	return nil
+}
+
+// SignerOpts carries the options of crypto.Signer signing. The zero
+// value requests an empty context; the message is never prehashed.
+type SignerOpts struct {
+	// Context is the domain-separation string, at most 255 bytes.
+	Context string
+}
+
+// HashFunc returns zero, as SLH-DSA signs the message itself.
+func (SignerOpts) HashFunc() crypto.Hash { return crypto.Hash(0) }
+
+// Sign implements crypto.Signer. The signature is hedged with n bytes
+// from rnd; a nil rnd selects the deterministic variant of FIPS 205.
+// opts may be a SignerOpts to pass a context string.
+func (sk *PrivateKey) Sign(rnd io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var context []byte
+	switch o := opts.(type) {
+	case SignerOpts:
+		context = []byte(o.Context)
+	case *SignerOpts:
+		context = []byte(o.Context)
+	default:
+		if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+			return nil, errors.New("slhdsa: message must not be prehashed")
+		}
+	}
+	sig := make([]byte, sk.sch.SignatureSize())
+	if err := sk.signTo(sig, rnd, message, context); err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+func (s *scheme) Sign(sk sign.PrivateKey, message []byte, opts *sign.SignatureOpts) []byte {
+	priv, ok := sk.(*PrivateKey)
+	if !ok || priv.sch != s {
+		panic(sign.ErrTypeMismatch)
+	}
+	var context []byte
+	if opts != nil {
+		context = []byte(opts.Context)
+	}
+	sig := make([]byte, s.SignatureSize())
+	if err := priv.signTo(sig, rand.Reader, message, context); err != nil {
+		panic(err)
+	}
+
+	return sig
+}
+
+// SignTo implements sign.SignerTo, writing a hedged signature into sig.
+func (s *scheme) SignTo(sig []byte, sk sign.PrivateKey, message []byte, opts *sign.SignatureOpts) {
+	priv, ok := sk.(*PrivateKey)
+	if !ok || priv.sch != s {
+		panic(sign.ErrTypeMismatch)
+	}
+	var context []byte
+	if opts != nil {
+		context = []byte(opts.Context)
+	}
+	if err := priv.signTo(sig, rand.Reader, message, context); err != nil {
+		panic(err)
+	}
+}
+
+func (s *scheme) Verify(pk sign.PublicKey, message, signature []byte, opts *sign.SignatureOpts) bool {
+	pub, ok := pk.(*PublicKey)
+	if !ok || pub.sch != s {
+		panic(sign.ErrTypeMismatch)
+	}
+	var context []byte
+	if opts != nil {
+		context = []byte(opts.Context)
+	}
+	if len(context) > 255 || len(signature) != s.SignatureSize() {
+		return false
+	}
+	prefix := msgPrefix(context)
+	hm := s.hMsgStream(signature[:s.n], pub.seed, pub.root)
+	_, _ = hm.Write(prefix)
+	_, _ = hm.Write(message)
+
+	return s.verifyDigest(signature, hm.digest(), pub)
+}
+
+// Streaming.
+
+// Signer signs a message supplied as a stream. It buffers the message:
+// the randomizer R of FIPS 205 depends on the whole message, so signing
+// cannot start before the stream ends.
+type Signer struct {
+	sk      *PrivateKey
+	context []byte
+	buf     bytes.Buffer
+}
+
+// NewSigner returns a streaming signer for sk. opts may be nil.
+func NewSigner(sk sign.PrivateKey, opts *sign.SignatureOpts) (*Signer, error) {
+	priv, ok := sk.(*PrivateKey)
+	if !ok {
+		return nil, sign.ErrTypeMismatch
+	}
+	var context []byte
+	if opts != nil {
+		context = []byte(opts.Context)
+	}
+	if len(context) > 255 {
+		return nil, errors.New("slhdsa: context longer than 255 bytes")
+	}
+
+	return &Signer{sk: priv, context: context}, nil
+}
+
+// Write appends message bytes; it never fails.
+func (sg *Signer) Write(p []byte) (int, error) { return sg.buf.Write(p) }
+
+// Sign signs the accumulated message, hedged with randomness from rnd;
+// a nil rnd selects the deterministic variant. The signer may be reused
+// by writing the next message afterwards.
+func (sg *Signer) Sign(rnd io.Reader) ([]byte, error) {
+	sig := make([]byte, sg.sk.sch.SignatureSize())
+	err := sg.sk.signTo(sig, rnd, sg.buf.Bytes(), sg.context)
+	sg.buf.Reset()
+	if err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// Verifier checks a signature over a message supplied as a stream, in
+// constant memory: the message is hashed as it is written.
+type Verifier struct {
+	sch *scheme
+	pk  *PublicKey
+	sig []byte
+	hm  msgDigester
+}
+
+// NewVerifier returns a streaming verifier of signature for pk. opts
+// may be nil. The signature must be complete up front, since its
+// randomizer seeds the message digest.
+func NewVerifier(pk sign.PublicKey, signature []byte, opts *sign.SignatureOpts) (*Verifier, error) {
+	pub, ok := pk.(*PublicKey)
+	if !ok {
+		return nil, sign.ErrTypeMismatch
+	}
+	s := pub.sch
+	var context []byte
+	if opts != nil {
+		context = []byte(opts.Context)
+	}
+	if len(context) > 255 {
+		return nil, errors.New("slhdsa: context longer than 255 bytes")
+	}
+	if len(signature) != s.SignatureSize() {
+		return nil, errors.New("slhdsa: signature of wrong size")
+	}
+	v := &Verifier{
+		sch: s,
+		pk:  pub,
+		sig: append([]byte{}, signature...),
+		hm:  s.hMsgStream(signature[:s.n], pub.seed, pub.root),
+	}
+	_, _ = v.hm.Write(msgPrefix(context))
+
+	return v, nil
+}
+
+// Write feeds message bytes; it never fails.
+func (v *Verifier) Write(p []byte) (int, error) { return v.hm.Write(p) }
+
+// Verify reports whether the signature is valid for the streamed
+// message. The verifier is spent afterwards.
+func (v *Verifier) Verify() bool {
+	return v.sch.verifyDigest(v.sig, v.hm.digest(), v.pk)
+}
@@ -0,0 +1,253 @@
+package slhdsa_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/slhdsa"
+)
+
+func shake128(s string, l int) []byte {
+	h := sha3.NewShake128()
+	_, _ = h.Write([]byte(s))
+	out := make([]byte, l)
+	_, _ = h.Read(out)
+	return out
+}
+
+func digest(b []byte) string {
+	h := sha3.New256()
+	_, _ = h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Known-answer vectors cross-checked against an independent
+// implementation of FIPS 205. The keygen seed for test t is
+// SHAKE128("seed-<name>-<t>"); the message is "message <t>", signed
+// deterministically, with context "" for t=0 and "context" for t=1.
+// Keys and signatures are recorded as SHA3-256 digests. The slow
+// small-signature set is skipped in short mode.
+var katVectors = []struct {
+	name        string
+	t           int
+	slow        bool
+	pk, sk, sig string
+}{
+	{
+		"SLH-DSA-SHA2-128f", 0, false,
+		"1e473aeb13cad3975d2c51124163f1d2dc3ec66c5ea8eb9d2d181badbd7316fe",
+		"a316b200af90c0061c51bbcd7404b174bc923c495f9e62687487a3caa7133bea",
+		"a59be8703705818dccc5d6fb7ab1f0667dd5b3111f323577d996cbeccc87e5a3",
+	},
+	{
+		"SLH-DSA-SHA2-128f", 1, false,
+		"60316871c0c98161d9e5cc8a8bcdf87da713d9f973b3a1afffedc2a3c1075bc9",
+		"d3f0ef090e12bc0e639982f46bb841a00f38873cdf01f8f8efcc227999271c51",
+		"b378bedc831f4e1e216c6249f455aaa8a11ed568656b9a9940774fe3c4f43bbd",
+	},
+	{
+		"SLH-DSA-SHAKE-128f", 0, false,
+		"bf2b2064d581562d9a2d80e64302f308825cb727b4b6ff9d81976444f2deab23",
+		"999d701bf4422b682afc0f450bffb7580cab258d8f7649eecc172eee0cab1e6e",
+		"fe3033c71ace000116fbaa547ed4376c392e55004e0286cd1e3738c03a8a941f",
+	},
+	{
+		"SLH-DSA-SHAKE-128f", 1, false,
+		"bbb513e27c6cbd85df7053f1b9a174a2af8ac26e4b5e5a47763c88e8b310337d",
+		"7bc5afe6712daa6727c6e28eab96339046dc4305fcabd90ac1c5e75c3c255847",
+		"659276b8068bfb7f494fd85de068ffbca3b4a8a322a422fc63c394bf96ee7bb8",
+	},
+	{
+		"SLH-DSA-SHAKE-128s", 0, true,
+		"4d6b02ff08e535091e4e3106737456b4df44e5326dfcbd0b29d10fb5545caab7",
+		"46cea8519d1915747ce9d0c392e82db0da804f4e03488120804f5bfd79766396",
+		"ff7007daa11830a005fd45b03eb4e737ce74eda1c0d1120105860734859ad41c",
+	},
+}
+
+func schemeByName(t *testing.T, name string) sign.Scheme {
+	t.Helper()
+	for _, s := range slhdsa.Schemes {
+		if s.Name() == name {
+			return s
+		}
+	}
+	t.Fatalf("unknown scheme %s", name)
+	return nil
+}
+
+func TestKAT(t *testing.T) {
+	for _, v := range katVectors {
+		t.Run(fmt.Sprintf("%s/%d", v.name, v.t), func(t *testing.T) {
+			if v.slow && testing.Short() {
+				t.Skip("slow parameter set")
+			}
+			sch := schemeByName(t, v.name)
+			seed := shake128(fmt.Sprintf("seed-%s-%d", v.name, v.t), sch.SeedSize())
+			msg := []byte(fmt.Sprintf("message %d", v.t))
+			ctx := ""
+			if v.t == 1 {
+				ctx = "context"
+			}
+
+			pub, priv := sch.DeriveKey(seed)
+			pk, err := pub.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sk, err := priv.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if digest(pk) != v.pk {
+				t.Error("public key mismatch")
+			}
+			if digest(sk) != v.sk {
+				t.Error("private key mismatch")
+			}
+
+			// Deterministic signing: a nil randomness source.
+			sig, err := priv.(*slhdsa.PrivateKey).Sign(nil, msg, slhdsa.SignerOpts{Context: ctx})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if digest(sig) != v.sig {
+				t.Error("signature mismatch")
+			}
+
+			opts := &sign.SignatureOpts{Context: ctx}
+			if !sch.Verify(pub, msg, sig, opts) {
+				t.Error("signature does not verify")
+			}
+			if sch.Verify(pub, append([]byte{}, append(msg, 'x')...), sig, opts) {
+				t.Error("signature verifies a different message")
+			}
+			if sch.Verify(pub, msg, sig, &sign.SignatureOpts{Context: ctx + "x"}) {
+				t.Error("signature verifies under a different context")
+			}
+			for _, i := range []int{0, len(sig) / 2, len(sig) - 1} {
+				bad := append([]byte{}, sig...)
+				bad[i] ^= 1
+				if sch.Verify(pub, msg, bad, opts) {
+					t.Errorf("tampered signature (byte %d) verifies", i)
+				}
+			}
+		})
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	sch := slhdsa.SHAKE128f
+	pub, priv, err := sch.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &sign.SignatureOpts{Context: "stream"}
+
+	sg, err := slhdsa.NewSigner(priv, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = sg.Write([]byte("chunked "))
+	_, _ = sg.Write([]byte("message"))
+	sig, err := sg.Sign(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("chunked message")
+	if !sch.Verify(pub, msg, sig, opts) {
+		t.Error("streamed signature does not verify")
+	}
+	// The deterministic streamed signature must equal the one-shot one.
+	oneShot, err := priv.(*slhdsa.PrivateKey).Sign(nil, msg, slhdsa.SignerOpts{Context: "stream"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest(sig) != digest(oneShot) {
+		t.Error("streamed and one-shot signatures differ")
+	}
+
+	v, err := slhdsa.NewVerifier(pub, sig, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = v.Write([]byte("chunked"))
+	_, _ = v.Write([]byte(" message"))
+	if !v.Verify() {
+		t.Error("streamed verification fails")
+	}
+
+	v, err = slhdsa.NewVerifier(pub, sig, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = v.Write([]byte("another message"))
+	if v.Verify() {
+		t.Error("streamed verification accepts a different message")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	sch := slhdsa.SHA2128f
+	pub, priv, err := sch.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := pub.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub2, err := sch.UnmarshalBinaryPublicKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub2.Equal(pub) {
+		t.Error("public key does not roundtrip")
+	}
+
+	sk, err := priv.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv2, err := sch.UnmarshalBinaryPrivateKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !priv2.Equal(priv) {
+		t.Error("private key does not roundtrip")
+	}
+	if !priv2.Public().(*slhdsa.PublicKey).Equal(pub) {
+		t.Error("restored private key has a different public key")
+	}
+
+	if _, err = sch.UnmarshalBinaryPublicKey(pk[:len(pk)-1]); err == nil {
+		t.Error("truncated public key accepted")
+	}
+	if _, err = sch.UnmarshalBinaryPrivateKey(sk[:len(sk)-1]); err == nil {
+		t.Error("truncated private key accepted")
+	}
+}
+
+func BenchmarkSHAKE128f(b *testing.B) {
+	sch := slhdsa.SHAKE128f
+	pub, priv, err := sch.GenerateKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+	msg := []byte("benchmark message")
+	sig := sch.Sign(priv, msg, nil)
+	b.Run("Sign", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = sch.Sign(priv, msg, nil)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = sch.Verify(pub, msg, sig, nil)
+		}
+	})
+}
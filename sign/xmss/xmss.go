@@ -0,0 +1,595 @@
+// Package xmss implements the XMSS and XMSS^MT stateful hash-based
+// signature schemes of RFC 8391, with the SHA-256 and SHAKE parameter
+// sets at the 256-bit security level.
+//
+// XMSS signatures are one-time at the leaf level: reusing a leaf index
+// forfeits security. Every private key therefore carries a StateKeeper,
+// which must durably record the advanced index before Sign releases a
+// signature. Callers integrate their storage by implementing the
+// interface; Volatile opts out for tests and for deployments that
+// accept the risk.
+//
+// Key generation computes the (top) Merkle tree, which takes time
+// exponential in the tree height: the _10_256 and the multi-tree sets
+// are practical everywhere, while the _16_256 and _20_256 single-tree
+// sets are only suitable where minutes-long key generation is
+// acceptable.
+package xmss
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/wipe"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Parameter sets of RFC 8391, sections 5.3 and 5.4 (n=32). Multi-tree
+// sets split the height over d layers, trading signature size for fast
+// key generation and signing.
+var (
+	SHA2h10    = &Params{name: "XMSS-SHA2_10_256", oid: 0x01, sha2: true, h: 10, d: 1}
+	SHA2h16    = &Params{name: "XMSS-SHA2_16_256", oid: 0x02, sha2: true, h: 16, d: 1}
+	SHA2h20    = &Params{name: "XMSS-SHA2_20_256", oid: 0x03, sha2: true, h: 20, d: 1}
+	SHAKEh10   = &Params{name: "XMSS-SHAKE_10_256", oid: 0x07, h: 10, d: 1}
+	SHAKEh16   = &Params{name: "XMSS-SHAKE_16_256", oid: 0x08, h: 16, d: 1}
+	SHAKEh20   = &Params{name: "XMSS-SHAKE_20_256", oid: 0x09, h: 20, d: 1}
+	SHA2h20d2  = &Params{name: "XMSSMT-SHA2_20/2_256", oid: 0x01, mt: true, sha2: true, h: 20, d: 2}
+	SHA2h20d4  = &Params{name: "XMSSMT-SHA2_20/4_256", oid: 0x02, mt: true, sha2: true, h: 20, d: 4}
+	SHAKEh20d2 = &Params{name: "XMSSMT-SHAKE_20/2_256", oid: 0x09, mt: true, h: 20, d: 2}
+	SHAKEh20d4 = &Params{name: "XMSSMT-SHAKE_20/4_256", oid: 0x0a, mt: true, h: 20, d: 4}
+)
+
+// ParamSets lists every parameter set of this package.
+var ParamSets = []*Params{
+	SHA2h10, SHA2h16, SHA2h20, SHAKEh10, SHAKEh16, SHAKEh20,
+	SHA2h20d2, SHA2h20d4, SHAKEh20d2, SHAKEh20d4,
+}
+
+// A StateKeeper durably records the next unused one-time-key index.
+// Sign calls Advance before it releases a signature; once Advance
+// returns, a crash must not resurrect a lower index. An error from
+// Advance aborts signing.
+type StateKeeper interface {
+	Advance(nextIndex uint64) error
+}
+
+type volatileState struct{}
+
+func (volatileState) Advance(uint64) error { return nil }
+
+// Volatile is a StateKeeper that persists nothing. A key restored from
+// an old copy of its serialization will reuse one-time keys; use it
+// only where that risk is acceptable.
+var Volatile StateKeeper = volatileState{}
+
+const (
+	n        = 32 // hash and seed length
+	wotsW    = 16
+	wotsLen  = 2*n + 3
+	seedSize = 3 * n
+)
+
+// Params describes an XMSS or XMSS^MT parameter set.
+type Params struct {
+	name string
+	oid  uint32
+	mt   bool
+	sha2 bool // SHA-256; SHAKE128 otherwise
+	h    int  // total tree height
+	d    int  // layers; 1 for single-tree XMSS
+}
+
+// Name returns the RFC 8391 name of the parameter set.
+func (p *Params) Name() string { return p.name }
+
+// MaxSignatures returns how many signatures a key can issue: 2^h.
+func (p *Params) MaxSignatures() uint64 { return 1 << p.h }
+
+func (p *Params) hl() int { return p.h / p.d } // height per layer
+
+func (p *Params) idxSize() int {
+	if p.mt {
+		return (p.h + 7) / 8
+	}
+
+	return 4
+}
+
+// PublicKeySize returns the encoding length: OID, root, and seed.
+func (p *Params) PublicKeySize() int { return 4 + 2*n }
+
+// PrivateKeySize returns the encoding length: OID, index, and the four
+// n-byte values.
+func (p *Params) PrivateKeySize() int { return 4 + 8 + 4*n }
+
+func (p *Params) SignatureSize() int {
+	return p.idxSize() + n + p.d*wotsLen*n + p.h*n
+}
+
+// Hash addresses (RFC 8391, section 2.5).
+const (
+	addrOTS = iota
+	addrLTree
+	addrTree
+)
+
+type address [8]uint32
+
+func (a *address) setLayer(l uint32)      { a[0] = l }
+func (a *address) setTree(t uint64)       { a[1], a[2] = uint32(t>>32), uint32(t) }
+func (a *address) setType(t uint32)       { a[3] = t; a[4], a[5], a[6], a[7] = 0, 0, 0, 0 }
+func (a *address) setOTS(i uint32)        { a[4] = i }
+func (a *address) setChain(i uint32)      { a[5] = i }
+func (a *address) setHash(i uint32)       { a[6] = i }
+func (a *address) setLTree(i uint32)      { a[4] = i }
+func (a *address) setTreeHeight(i uint32) { a[5] = i }
+func (a *address) setTreeIndex(i uint32)  { a[6] = i }
+func (a *address) setKeyAndMask(i uint32) { a[7] = i }
+
+func (a *address) bytes() []byte {
+	out := make([]byte, 32)
+	for i, w := range a {
+		binary.BigEndian.PutUint32(out[4*i:], w)
+	}
+
+	return out
+}
+
+// The keyed hash functions of RFC 8391, section 5.1, domain-separated
+// by a 32-byte prefix: 0 for F, 1 for H, 2 for H_msg, 3 for PRF, and 4
+// for the PRF of SP 800-208 that derives WOTS+ secrets.
+func (p *Params) keyedHash(domain byte, parts ...[]byte) []byte {
+	var prefix [n]byte
+	prefix[n-1] = domain
+	if p.sha2 {
+		h := sha256.New()
+		_, _ = h.Write(prefix[:])
+		for _, m := range parts {
+			_, _ = h.Write(m)
+		}
+
+		return h.Sum(nil)
+	}
+	h := sha3.NewShake128()
+	_, _ = h.Write(prefix[:])
+	for _, m := range parts {
+		_, _ = h.Write(m)
+	}
+	out := make([]byte, n)
+	_, _ = h.Read(out)
+
+	return out
+}
+
+func (p *Params) prf(key []byte, adrs *address) []byte {
+	return p.keyedHash(3, key, adrs.bytes())
+}
+
+// chain walks the WOTS+ function chain with fresh key and bitmask per
+// step (RFC 8391, Algorithm 2).
+func (p *Params) chain(x []byte, start, steps int, seed []byte, adrs *address) []byte {
+	out := append([]byte{}, x...)
+	for j := start; j < start+steps; j++ {
+		adrs.setHash(uint32(j))
+		adrs.setKeyAndMask(0)
+		key := p.prf(seed, adrs)
+		adrs.setKeyAndMask(1)
+		bm := p.prf(seed, adrs)
+		for i := range out {
+			out[i] ^= bm[i]
+		}
+		out = p.keyedHash(0, key, out)
+	}
+
+	return out
+}
+
+// baseW expands an n-byte value into base-16 digits plus its checksum.
+func baseW(m []byte) []uint8 {
+	digits := make([]uint8, 0, wotsLen)
+	for _, b := range m {
+		digits = append(digits, b>>4, b&0x0f)
+	}
+	csum := 0
+	for _, d := range digits {
+		csum += wotsW - 1 - int(d)
+	}
+	csum <<= 4 // left-align in the three checksum digits
+	digits = append(digits, uint8(csum>>8&0x0f), uint8(csum>>4&0x0f), uint8(csum&0x0f))
+
+	return digits
+}
+
+// wotsSK derives the i-th chain start of the one-time key at adrs
+// (SP 800-208, Algorithm 10').
+func (p *Params) wotsSK(skseed, pkseed []byte, adrs *address, i int) []byte {
+	skAdrs := *adrs
+	skAdrs.setChain(uint32(i))
+	skAdrs.setHash(0)
+	skAdrs.setKeyAndMask(0)
+
+	return p.keyedHash(4, skseed, pkseed, skAdrs.bytes())
+}
+
+func (p *Params) wotsPKGen(skseed, pkseed []byte, adrs *address) [][]byte {
+	pk := make([][]byte, wotsLen)
+	for i := range pk {
+		sk := p.wotsSK(skseed, pkseed, adrs, i)
+		adrs.setChain(uint32(i))
+		pk[i] = p.chain(sk, 0, wotsW-1, pkseed, adrs)
+	}
+
+	return pk
+}
+
+func (p *Params) wotsSign(sig []byte, m, skseed, pkseed []byte, adrs *address) {
+	for i, d := range baseW(m) {
+		sk := p.wotsSK(skseed, pkseed, adrs, i)
+		adrs.setChain(uint32(i))
+		copy(sig[i*n:], p.chain(sk, 0, int(d), pkseed, adrs))
+	}
+}
+
+func (p *Params) wotsPKFromSig(sig, m, pkseed []byte, adrs *address) [][]byte {
+	pk := make([][]byte, wotsLen)
+	for i, d := range baseW(m) {
+		adrs.setChain(uint32(i))
+		pk[i] = p.chain(sig[i*n:(i+1)*n], int(d), wotsW-1-int(d), pkseed, adrs)
+	}
+
+	return pk
+}
+
+// randHash is RAND_HASH (RFC 8391, Algorithm 7): a keyed, bitmasked
+// compression of two nodes.
+func (p *Params) randHash(left, right, seed []byte, adrs *address) []byte {
+	adrs.setKeyAndMask(0)
+	key := p.prf(seed, adrs)
+	adrs.setKeyAndMask(1)
+	bm0 := p.prf(seed, adrs)
+	adrs.setKeyAndMask(2)
+	bm1 := p.prf(seed, adrs)
+	buf := make([]byte, 2*n)
+	for i := 0; i < n; i++ {
+		buf[i] = left[i] ^ bm0[i]
+		buf[n+i] = right[i] ^ bm1[i]
+	}
+
+	return p.keyedHash(1, key, buf)
+}
+
+// ltree compresses a WOTS+ public key into a leaf (RFC 8391,
+// Algorithm 8).
+func (p *Params) ltree(pk [][]byte, seed []byte, adrs *address) []byte {
+	adrs.setTreeHeight(0)
+	l := len(pk)
+	for l > 1 {
+		for i := 0; i < l/2; i++ {
+			adrs.setTreeIndex(uint32(i))
+			pk[i] = p.randHash(pk[2*i], pk[2*i+1], seed, adrs)
+		}
+		if l&1 == 1 {
+			pk[l/2] = pk[l-1]
+		}
+		l = (l + 1) / 2
+		adrs.setTreeHeight(adrs[5] + 1)
+	}
+
+	return pk[0]
+}
+
+// tree caches every node of one XMSS subtree: nodes[z][i] is the node
+// at height z. Signing reads authentication paths straight out of it.
+type tree struct {
+	idx   uint64 // which subtree of its layer
+	nodes [][][]byte
+}
+
+// buildTree computes the whole subtree at (layer, treeIdx).
+func (p *Params) buildTree(skseed, pkseed []byte, layer uint32, treeIdx uint64) *tree {
+	hl := p.hl()
+	t := &tree{idx: treeIdx, nodes: make([][][]byte, hl+1)}
+	t.nodes[0] = make([][]byte, 1<<hl)
+	for i := range t.nodes[0] {
+		var adrs address
+		adrs.setLayer(layer)
+		adrs.setTree(treeIdx)
+		adrs.setType(addrOTS)
+		adrs.setOTS(uint32(i))
+		wpk := p.wotsPKGen(skseed, pkseed, &adrs)
+		adrs.setType(addrLTree)
+		adrs.setLTree(uint32(i))
+		t.nodes[0][i] = p.ltree(wpk, pkseed, &adrs)
+	}
+	for z := 1; z <= hl; z++ {
+		t.nodes[z] = make([][]byte, 1<<(hl-z))
+		for i := range t.nodes[z] {
+			var adrs address
+			adrs.setLayer(layer)
+			adrs.setTree(treeIdx)
+			adrs.setType(addrTree)
+			adrs.setTreeHeight(uint32(z))
+			adrs.setTreeIndex(uint32(i))
+			t.nodes[z][i] = p.randHash(t.nodes[z-1][2*i], t.nodes[z-1][2*i+1], pkseed, &adrs)
+		}
+	}
+
+	return t
+}
+
+func (t *tree) root() []byte { return t.nodes[len(t.nodes)-1][0] }
+
+// PublicKey is an XMSS verification key.
+type PublicKey struct {
+	par  *Params
+	root []byte
+	seed []byte
+}
+
+// PrivateKey is an XMSS signing key. It is not safe for concurrent use.
+type PrivateKey struct {
+	par    *Params
+	idx    uint64 // next unused leaf
+	skseed []byte
+	skprf  []byte
+	pub    *PublicKey
+	keeper StateKeeper
+	trees  []*tree // cached subtree per layer
+}
+
+// Params returns the key's parameter set.
+func (pk *PublicKey) Params() *Params { return pk.par }
+
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 4, pk.par.PublicKeySize())
+	binary.BigEndian.PutUint32(out, pk.par.oid)
+	out = append(out, pk.root...)
+
+	return append(out, pk.seed...), nil
+}
+
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	return pk.par == other.par &&
+		bytes.Equal(pk.root, other.root) && bytes.Equal(pk.seed, other.seed)
+}
+
+// Params returns the key's parameter set.
+func (sk *PrivateKey) Params() *Params { return sk.par }
+
+// Public returns the corresponding public key.
+func (sk *PrivateKey) Public() *PublicKey { return sk.pub }
+
+// Remaining returns how many signatures the key can still issue.
+func (sk *PrivateKey) Remaining() uint64 { return sk.par.MaxSignatures() - sk.idx }
+
+// MarshalBinary encodes the key including its next index. Restoring an
+// old encoding reuses one-time keys; keep exactly one live copy.
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 12, sk.par.PrivateKeySize())
+	binary.BigEndian.PutUint32(out, sk.par.oid)
+	binary.BigEndian.PutUint64(out[4:], sk.idx)
+	out = append(out, sk.skseed...)
+	out = append(out, sk.skprf...)
+	out = append(out, sk.pub.seed...)
+
+	return append(out, sk.pub.root...), nil
+}
+
+func (sk *PrivateKey) Equal(other *PrivateKey) bool {
+	if sk.par != other.par {
+		return false
+	}
+	a, _ := sk.MarshalBinary()
+	b, _ := other.MarshalBinary()
+
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Wipe zeroes the secret seeds.
+func (sk *PrivateKey) Wipe() {
+	wipe.Bytes(sk.skseed)
+	wipe.Bytes(sk.skprf)
+}
+
+// GenerateKey creates a key pair for p, reading the seeds from rnd (the
+// library-wide source when nil). keeper persists the signature counter;
+// see StateKeeper and Volatile.
+func (p *Params) GenerateKey(rnd io.Reader, keeper StateKeeper) (*PublicKey, *PrivateKey, error) {
+	seed := make([]byte, seedSize)
+	if _, err := io.ReadFull(rand.Source(rnd), seed); err != nil {
+		return nil, nil, err
+	}
+
+	return p.deriveKey(seed, keeper)
+}
+
+// DeriveKey creates a key pair from a seed of SeedSize bytes: the WOTS+
+// seed, the PRF key, and the public seed.
+func (p *Params) DeriveKey(seed []byte, keeper StateKeeper) (*PublicKey, *PrivateKey, error) {
+	if len(seed) != seedSize {
+		return nil, nil, errors.New("xmss: seed must be 96 bytes")
+	}
+
+	return p.deriveKey(seed, keeper)
+}
+
+// SeedSize returns the DeriveKey seed length.
+func (p *Params) SeedSize() int { return seedSize }
+
+func (p *Params) deriveKey(seed []byte, keeper StateKeeper) (*PublicKey, *PrivateKey, error) {
+	if keeper == nil {
+		return nil, nil, errors.New("xmss: nil StateKeeper; use xmss.Volatile to opt out of persistence")
+	}
+	pk := &PublicKey{par: p, seed: append([]byte{}, seed[2*n:]...)}
+	sk := &PrivateKey{
+		par:    p,
+		skseed: append([]byte{}, seed[:n]...),
+		skprf:  append([]byte{}, seed[n:2*n]...),
+		pub:    pk,
+		keeper: keeper,
+		trees:  make([]*tree, p.d),
+	}
+	top := p.buildTree(sk.skseed, pk.seed, uint32(p.d-1), 0)
+	sk.trees[p.d-1] = top
+	pk.root = append([]byte{}, top.root()...)
+
+	return pk, sk, nil
+}
+
+// UnmarshalPublicKey decodes a public key for p.
+func (p *Params) UnmarshalPublicKey(data []byte) (*PublicKey, error) {
+	if len(data) != p.PublicKeySize() || binary.BigEndian.Uint32(data) != p.oid {
+		return nil, errors.New("xmss: malformed public key")
+	}
+
+	return &PublicKey{
+		par:  p,
+		root: append([]byte{}, data[4:4+n]...),
+		seed: append([]byte{}, data[4+n:]...),
+	}, nil
+}
+
+// UnmarshalPrivateKey decodes a private key for p, resuming at its
+// stored index. keeper persists the counter from here on.
+func (p *Params) UnmarshalPrivateKey(data []byte, keeper StateKeeper) (*PrivateKey, error) {
+	if len(data) != p.PrivateKeySize() || binary.BigEndian.Uint32(data) != p.oid {
+		return nil, errors.New("xmss: malformed private key")
+	}
+	if keeper == nil {
+		return nil, errors.New("xmss: nil StateKeeper; use xmss.Volatile to opt out of persistence")
+	}
+	sk := &PrivateKey{
+		par:    p,
+		idx:    binary.BigEndian.Uint64(data[4:]),
+		skseed: append([]byte{}, data[12:12+n]...),
+		skprf:  append([]byte{}, data[12+n:12+2*n]...),
+		pub: &PublicKey{
+			par:  p,
+			seed: append([]byte{}, data[12+2*n:12+3*n]...),
+			root: append([]byte{}, data[12+3*n:]...),
+		},
+		keeper: keeper,
+		trees:  make([]*tree, p.d),
+	}
+
+	return sk, nil
+}
+
+// ErrExhausted is returned when every one-time key has been used.
+var ErrExhausted = errors.New("xmss: private key exhausted")
+
+// Sign signs message with the next one-time key. The index is advanced
+// and persisted through the key's StateKeeper before the signature is
+// computed, so a crash cannot lead to reuse.
+func (sk *PrivateKey) Sign(message []byte) ([]byte, error) {
+	p := sk.par
+	if sk.idx >= p.MaxSignatures() {
+		return nil, ErrExhausted
+	}
+	idx := sk.idx
+	sk.idx++
+	if err := sk.keeper.Advance(sk.idx); err != nil {
+		sk.idx = idx
+
+		return nil, err
+	}
+
+	var idxB [n]byte
+	binary.BigEndian.PutUint64(idxB[n-8:], idx)
+	r := sk.par.keyedHash(3, sk.skprf, idxB[:])
+	digest := p.keyedHash(2, append(append(append([]byte{}, r...), sk.pub.root...), idxB[:]...), message)
+
+	sig := make([]byte, p.SignatureSize())
+	for i, b := 0, idx; i < p.idxSize(); i++ {
+		sig[p.idxSize()-1-i] = byte(b)
+		b >>= 8
+	}
+	copy(sig[p.idxSize():], r)
+
+	hl := p.hl()
+	off := p.idxSize() + n
+	msg := digest
+	rem := idx
+	for layer := 0; layer < p.d; layer++ {
+		leaf := uint32(rem & (1<<hl - 1))
+		rem >>= hl
+		t := sk.trees[layer]
+		if t == nil || t.idx != rem {
+			t = p.buildTree(sk.skseed, sk.pub.seed, uint32(layer), rem)
+			sk.trees[layer] = t
+		}
+		var adrs address
+		adrs.setLayer(uint32(layer))
+		adrs.setTree(rem)
+		adrs.setType(addrOTS)
+		adrs.setOTS(leaf)
+		p.wotsSign(sig[off:], msg, sk.skseed, sk.pub.seed, &adrs)
+		off += wotsLen * n
+		for z := 0; z < hl; z++ {
+			copy(sig[off:], t.nodes[z][leaf>>z^1])
+			off += n
+		}
+		msg = t.root()
+	}
+
+	return sig, nil
+}
+
+// Verify reports whether signature is valid for message under pk.
+func (pk *PublicKey) Verify(message, signature []byte) bool {
+	p := pk.par
+	if len(signature) != p.SignatureSize() {
+		return false
+	}
+	var idx uint64
+	for _, b := range signature[:p.idxSize()] {
+		idx = idx<<8 | uint64(b)
+	}
+	if idx >= p.MaxSignatures() {
+		return false
+	}
+	r := signature[p.idxSize() : p.idxSize()+n]
+
+	var idxB [n]byte
+	binary.BigEndian.PutUint64(idxB[n-8:], idx)
+	digest := p.keyedHash(2, append(append(append([]byte{}, r...), pk.root...), idxB[:]...), message)
+
+	hl := p.hl()
+	off := p.idxSize() + n
+	node := digest
+	rem := idx
+	for layer := 0; layer < p.d; layer++ {
+		leaf := uint32(rem & (1<<hl - 1))
+		rem >>= hl
+		var adrs address
+		adrs.setLayer(uint32(layer))
+		adrs.setTree(rem)
+		adrs.setType(addrOTS)
+		adrs.setOTS(leaf)
+		wpk := p.wotsPKFromSig(signature[off:], node, pk.seed, &adrs)
+		off += wotsLen * n
+		adrs.setType(addrLTree)
+		adrs.setLTree(leaf)
+		node = p.ltree(wpk, pk.seed, &adrs)
+		adrs.setType(addrTree)
+		for z := 0; z < hl; z++ {
+			adrs.setTreeHeight(uint32(z + 1))
+			adrs.setTreeIndex(leaf >> (z + 1))
+			sib := signature[off : off+n]
+			if leaf>>z&1 == 0 {
+				node = p.randHash(node, sib, pk.seed, &adrs)
+			} else {
+				node = p.randHash(sib, node, pk.seed, &adrs)
+			}
+			off += n
+		}
+	}
+
+	return bytes.Equal(node, pk.root)
+}
@@ -0,0 +1,154 @@
+package xmss_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/cloudflare/circl/sign/xmss"
+)
+
+// testSets keeps the suite fast: key generation of the taller
+// single-tree sets builds the full tree up front.
+var testSets = []*xmss.Params{xmss.SHA2h10, xmss.SHA2h20d4, xmss.SHAKEh20d4}
+
+func TestRoundtrip(t *testing.T) {
+	for _, p := range testSets {
+		t.Run(p.Name(), func(t *testing.T) {
+			seed := make([]byte, p.SeedSize())
+			pk, sk, err := p.DeriveKey(seed, xmss.Volatile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pk2, sk2, err := p.DeriveKey(seed, xmss.Volatile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pk.Equal(pk2) || !sk.Equal(sk2) {
+				t.Fatal("key derivation is not deterministic")
+			}
+
+			msg := []byte("stateful hash-based signature")
+			sig, err := sk.Sign(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sig) != p.SignatureSize() {
+				t.Fatal("wrong signature size")
+			}
+			if !pk.Verify(msg, sig) {
+				t.Fatal("signature does not verify")
+			}
+			if pk.Verify(msg[:len(msg)-1], sig) {
+				t.Fatal("signature verifies a different message")
+			}
+			sig[0] ^= 1
+			if pk.Verify(msg, sig) {
+				t.Fatal("corrupted signature verifies")
+			}
+			sig[0] ^= 1
+
+			// The next signature uses a fresh one-time key.
+			sig2, err := sk.Sign(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Equal(sig, sig2) {
+				t.Fatal("one-time key reused")
+			}
+			if !pk.Verify(msg, sig2) {
+				t.Fatal("second signature does not verify")
+			}
+			if sk.Remaining() != p.MaxSignatures()-2 {
+				t.Fatal("wrong remaining count")
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	p := xmss.SHA2h20d4
+	pk, sk, err := p.GenerateKey(nil, xmss.Volatile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("marshal")
+	if _, err = sk.Sign(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	ppk, err := pk.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	upk, err := p.UnmarshalPublicKey(ppk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Equal(upk) {
+		t.Fatal("public key does not roundtrip")
+	}
+
+	psk, err := sk.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	usk, err := p.UnmarshalPrivateKey(psk, xmss.Volatile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sk.Equal(usk) {
+		t.Fatal("private key does not roundtrip")
+	}
+
+	// The restored key continues from the recorded index.
+	sig, err := usk.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !upk.Verify(msg, sig) {
+		t.Fatal("signature of restored key does not verify")
+	}
+}
+
+// recordingKeeper counts Advance calls and can simulate storage failure.
+type recordingKeeper struct {
+	last uint64
+	fail bool
+}
+
+func (k *recordingKeeper) Advance(next uint64) error {
+	if k.fail {
+		return errors.New("storage failure")
+	}
+	k.last = next
+	return nil
+}
+
+func TestStateKeeper(t *testing.T) {
+	keeper := &recordingKeeper{}
+	pk, sk, err := xmss.SHA2h20d4.GenerateKey(nil, keeper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("keeper")
+	if _, err = sk.Sign(msg); err != nil {
+		t.Fatal(err)
+	}
+	if keeper.last != 1 {
+		t.Fatalf("index not advanced, keeper at %d", keeper.last)
+	}
+
+	keeper.fail = true
+	if _, err = sk.Sign(msg); err == nil {
+		t.Fatal("signing succeeds although the state cannot be persisted")
+	}
+	keeper.fail = false
+	sig, err := sk.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Verify(msg, sig) {
+		t.Fatal("signature after keeper failure does not verify")
+	}
+}
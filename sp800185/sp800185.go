@@ -0,0 +1,165 @@
+// Package sp800185 implements TupleHash and ParallelHash, the SHA-3 derived
+// functions specified in NIST SP 800-185.
+//
+// TupleHash hashes a sequence of byte strings unambiguously: hashing the
+// tuple ("ab", "c") gives a different result than ("a", "bc"). ParallelHash
+// hashes large inputs in independent blocks, so the block hashes can be,
+// and here are, computed concurrently. Both come in a 128- and a 256-bit
+// security strength, and in fixed-output and XOF variants, built on cSHAKE
+// like the rest of the SP 800-185 family.
+package sp800185
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/cloudflare/circl/internal/sha3"
+)
+
+// TupleHash128 hashes the tuple with TupleHash128, writing len(output)
+// bytes. The customization string s may be nil.
+func TupleHash128(tuple [][]byte, output, s []byte) { tupleHash(tuple, output, s, 128, false) }
+
+// TupleHash256 hashes the tuple with TupleHash256.
+func TupleHash256(tuple [][]byte, output, s []byte) { tupleHash(tuple, output, s, 256, false) }
+
+// TupleHashXOF128 is the arbitrary-length variant of TupleHash128. Unlike
+// TupleHash128, the output length does not influence the output.
+func TupleHashXOF128(tuple [][]byte, output, s []byte) { tupleHash(tuple, output, s, 128, true) }
+
+// TupleHashXOF256 is the arbitrary-length variant of TupleHash256.
+func TupleHashXOF256(tuple [][]byte, output, s []byte) { tupleHash(tuple, output, s, 256, true) }
+
+func tupleHash(tuple [][]byte, output, s []byte, strength int, xofMode bool) {
+	state := newCShake([]byte("TupleHash"), s, strength)
+	for _, x := range tuple {
+		_, _ = state.Write(encodeString(x))
+	}
+	if xofMode {
+		_, _ = state.Write(rightEncode(0))
+	} else {
+		_, _ = state.Write(rightEncode(uint64(len(output)) * 8))
+	}
+	_, _ = state.Read(output)
+}
+
+// ParallelHash128 hashes x in independent blocks of blockSize bytes,
+// writing len(output) bytes. The customization string s may be nil.
+func ParallelHash128(x []byte, blockSize int, output, s []byte) {
+	parallelHash(x, blockSize, output, s, 128, false)
+}
+
+// ParallelHash256 hashes x in independent blocks with 256-bit strength.
+func ParallelHash256(x []byte, blockSize int, output, s []byte) {
+	parallelHash(x, blockSize, output, s, 256, false)
+}
+
+// ParallelHashXOF128 is the arbitrary-length variant of ParallelHash128.
+func ParallelHashXOF128(x []byte, blockSize int, output, s []byte) {
+	parallelHash(x, blockSize, output, s, 128, true)
+}
+
+// ParallelHashXOF256 is the arbitrary-length variant of ParallelHash256.
+func ParallelHashXOF256(x []byte, blockSize int, output, s []byte) {
+	parallelHash(x, blockSize, output, s, 256, true)
+}
+
+func parallelHash(x []byte, blockSize int, output, s []byte, strength int, xofMode bool) {
+	if blockSize <= 0 {
+		panic("sp800185: block size must be positive")
+	}
+	blocks := (len(x) + blockSize - 1) / blockSize
+	if len(x) == 0 {
+		blocks = 0
+	}
+
+	// Hash the blocks concurrently; each chaining value is independent.
+	cvLen := strength / 4 // 2*strength bits
+	cvs := make([]byte, blocks*cvLen)
+	workers := runtime.GOMAXPROCS(0)
+	if workers > blocks {
+		workers = blocks
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < blocks; i += workers {
+				end := (i + 1) * blockSize
+				if end > len(x) {
+					end = len(x)
+				}
+				inner := newCShake(nil, nil, strength)
+				_, _ = inner.Write(x[i*blockSize : end])
+				_, _ = inner.Read(cvs[i*cvLen : (i+1)*cvLen])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	state := newCShake([]byte("ParallelHash"), s, strength)
+	_, _ = state.Write(leftEncode(uint64(blockSize)))
+	_, _ = state.Write(cvs)
+	_, _ = state.Write(rightEncode(uint64(blocks)))
+	if xofMode {
+		_, _ = state.Write(rightEncode(0))
+	} else {
+		_, _ = state.Write(rightEncode(uint64(len(output)) * 8))
+	}
+	_, _ = state.Read(output)
+}
+
+// newCShake returns a cSHAKE instance of the given strength. A nil function
+// name and customization string yield plain SHAKE, as SP 800-185 requires
+// for the inner hashes of ParallelHash.
+func newCShake(n, s []byte, strength int) sha3.State {
+	if strength == 128 {
+		if len(n) == 0 && len(s) == 0 {
+			return sha3.NewShake128()
+		}
+		return sha3.NewCShake128(n, s)
+	}
+	if len(n) == 0 && len(s) == 0 {
+		return sha3.NewShake256()
+	}
+	return sha3.NewCShake256(n, s)
+}
+
+// leftEncode returns left_encode(v) of SP 800-185: the big-endian encoding
+// of v preceded by its byte length.
+func leftEncode(v uint64) []byte {
+	n := 1
+	for vv := v; vv >= 0x100; vv >>= 8 {
+		n++
+	}
+	out := make([]byte, n+1)
+	out[0] = byte(n)
+	for i := n; i > 0; i-- {
+		out[i] = byte(v)
+		v >>= 8
+	}
+	return out
+}
+
+// rightEncode returns right_encode(v): the big-endian encoding of v
+// followed by its byte length.
+func rightEncode(v uint64) []byte {
+	n := 1
+	for vv := v; vv >= 0x100; vv >>= 8 {
+		n++
+	}
+	out := make([]byte, n+1)
+	out[n] = byte(n)
+	for i := n - 1; i >= 0; i-- {
+		out[i] = byte(v)
+		v >>= 8
+	}
+	return out
+}
+
+// encodeString returns encode_string(x): the bit length of x left-encoded,
+// followed by x.
+func encodeString(x []byte) []byte {
+	return append(leftEncode(uint64(len(x))*8), x...)
+}
@@ -0,0 +1,139 @@
+package sp800185_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/sp800185"
+)
+
+// Sample inputs of the NIST SP 800-185 example vectors: the tuple
+// (000102, 101112131415), its three-element extension, and the 24-byte
+// counter pattern hashed in 8-byte blocks.
+var (
+	tuple2 = [][]byte{{0x00, 0x01, 0x02}, {0x10, 0x11, 0x12, 0x13, 0x14, 0x15}}
+	tuple3 = append(tuple2, []byte{0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28})
+	pData  = []byte{
+		0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+		0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17,
+		0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27,
+	}
+)
+
+func check(t *testing.T, name string, got []byte, want string) {
+	t.Helper()
+	if hex.EncodeToString(got) != want {
+		t.Errorf("%s mismatch\ngot:  %x\nwant: %s", name, got, want)
+	}
+}
+
+func TestTupleHash(t *testing.T) {
+	out := make([]byte, 32)
+	sp800185.TupleHash128(tuple2, out, nil)
+	check(t, "TupleHash128 sample 1", out,
+		"c5d8786c1afb9b82111ab34b65b2c0048fa64e6d48e263264ce1707d3ffc8ed1")
+	sp800185.TupleHash128(tuple2, out, []byte("My Tuple App"))
+	check(t, "TupleHash128 sample 2", out,
+		"75cdb20ff4db1154e841d758e24160c54bae86eb8c13e7f5f40eb35588e96dfb")
+	sp800185.TupleHash128(tuple3, out, []byte("My Tuple App"))
+	check(t, "TupleHash128 sample 3", out,
+		"e60f202c89a2631eda8d4c588ca5fd07f39e5151998deccf973adb3804bb6e84")
+
+	out = make([]byte, 64)
+	sp800185.TupleHash256(tuple2, out, nil)
+	check(t, "TupleHash256 sample 1", out,
+		"cfb7058caca5e668f81a12a20a2195ce97a925f1dba3e7449a56f82201ec6073"+
+			"11ac2696b1ab5ea2352df1423bde7bd4bb78c9aed1a853c78672f9eb23bbe194")
+	sp800185.TupleHash256(tuple2, out, []byte("My Tuple App"))
+	check(t, "TupleHash256 sample 2", out,
+		"147c2191d5ed7efd98dbd96d7ab5a11692576f5fe2a5065f3e33de6bba9f3aa1"+
+			"c4e9a068a289c61c95aab30aee1e410b0b607de3620e24a4e3bf9852a1d4367e")
+	sp800185.TupleHash256(tuple3, out, []byte("My Tuple App"))
+	check(t, "TupleHash256 sample 3", out,
+		"45000be63f9b6bfd89f54717670f69a9bc763591a4f05c50d68891a744bcc6e7"+
+			"d6d5b5e82c018da999ed35b0bb49c9678e526abd8e85c13ed254021db9e790ce")
+}
+
+func TestParallelHash(t *testing.T) {
+	out := make([]byte, 32)
+	sp800185.ParallelHash128(pData, 8, out, nil)
+	check(t, "ParallelHash128 sample 1", out,
+		"ba8dc1d1d979331d3f813603c67f72609ab5e44b94a0b8f9af46514454a2b4f5")
+	sp800185.ParallelHash128(pData, 8, out, []byte("Parallel Data"))
+	check(t, "ParallelHash128 sample 2", out,
+		"fc484dcb3f84dceedc353438151bee58157d6efed0445a81f165e495795b7206")
+
+	out = make([]byte, 64)
+	sp800185.ParallelHash256(pData, 8, out, nil)
+	check(t, "ParallelHash256 sample 1", out,
+		"bc1ef124da34495e948ead207dd9842235da432d2bbc54b4c110e64c45110553"+
+			"1b7f2a3e0ce055c02805e7c2de1fb746af97a1dd01f43b824e31b87612410429")
+	sp800185.ParallelHash256(pData, 8, out, []byte("Parallel Data"))
+	check(t, "ParallelHash256 sample 2", out,
+		"cdf15289b54f6212b4bc270528b49526006dd9b54e2b6add1ef6900dda3963bb"+
+			"33a72491f236969ca8afaea29c682d47a393c065b38e29fae651a2091c833110")
+}
+
+// The tuple encoding must be unambiguous: moving a byte across element
+// boundaries changes the digest.
+func TestTupleBoundaries(t *testing.T) {
+	a := make([]byte, 32)
+	b := make([]byte, 32)
+	sp800185.TupleHash128([][]byte{[]byte("ab"), []byte("c")}, a, nil)
+	sp800185.TupleHash128([][]byte{[]byte("a"), []byte("bc")}, b, nil)
+	if bytes.Equal(a, b) {
+		t.Fatal("tuple boundaries do not separate")
+	}
+}
+
+func TestXOFVariants(t *testing.T) {
+	// In XOF mode shorter outputs are prefixes of longer ones; in fixed
+	// mode the length is bound into the digest, so they are not.
+	short := make([]byte, 16)
+	long := make([]byte, 32)
+	sp800185.TupleHashXOF128(tuple2, short, nil)
+	sp800185.TupleHashXOF128(tuple2, long, nil)
+	if !bytes.Equal(short, long[:16]) {
+		t.Fatal("TupleHashXOF128 is not an XOF")
+	}
+	sp800185.TupleHash128(tuple2, short, nil)
+	sp800185.TupleHash128(tuple2, long, nil)
+	if bytes.Equal(short, long[:16]) {
+		t.Fatal("TupleHash128 does not bind the output length")
+	}
+
+	sp800185.ParallelHashXOF128(pData, 8, short, nil)
+	sp800185.ParallelHashXOF128(pData, 8, long, nil)
+	if !bytes.Equal(short, long[:16]) {
+		t.Fatal("ParallelHashXOF128 is not an XOF")
+	}
+	sp800185.ParallelHashXOF256(pData, 8, short, nil)
+	sp800185.ParallelHashXOF256(pData, 8, long, nil)
+	if !bytes.Equal(short, long[:16]) {
+		t.Fatal("ParallelHashXOF256 is not an XOF")
+	}
+}
+
+// ParallelHash must give the same digest regardless of how the input
+// divides into blocks relative to the worker count.
+func TestParallelHashBlocking(t *testing.T) {
+	msg := make([]byte, 1000)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+	want := make([]byte, 32)
+	sp800185.ParallelHash128(msg, 1000, want, nil) // single block
+	for _, b := range []int{1, 7, 128, 999, 2000} {
+		got := make([]byte, 32)
+		sp800185.ParallelHash128(msg, b, got, nil)
+		if bytes.Equal(got, want) {
+			t.Fatalf("block size %d: digest ignores the block size", b)
+		}
+		again := make([]byte, 32)
+		sp800185.ParallelHash128(msg, b, again, nil)
+		if !bytes.Equal(got, again) {
+			t.Fatalf("block size %d: digest is not deterministic", b)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package tlsutil
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/schemes"
+)
+
+// algorithmIdentifier is an X.509 AlgorithmIdentifier; parameters, if
+// present, are ignored.
+type algorithmIdentifier struct {
+	OID    asn1.ObjectIdentifier
+	Params asn1.RawValue `asn1:"optional"`
+}
+
+// VerifyCertificateSignature checks that parent signed child, for
+// signature algorithms registered in the schemes registry — which is how
+// ML-DSA, SLH-DSA, or composite certificates verify even though
+// crypto/x509 does not know their algorithms. The parent's public key is
+// read from its SubjectPublicKeyInfo, so it may also be of an algorithm
+// crypto/x509 cannot parse.
+func VerifyCertificateSignature(child, parent *x509.Certificate) error {
+	// The outer Certificate SEQUENCE: tbsCertificate, signatureAlgorithm,
+	// signatureValue. crypto/x509 exposes RawTBSCertificate and Signature
+	// but not the algorithm of unknown schemes, so walk the raw DER.
+	var seq asn1.RawValue
+	if rest, err := asn1.Unmarshal(child.Raw, &seq); err != nil || len(rest) != 0 {
+		return ErrMalformedCertificate
+	}
+	var tbs, algRaw asn1.RawValue
+	in, err := unmarshalRaw(seq.Bytes, &tbs)
+	if err != nil {
+		return err
+	}
+	if in, err = unmarshalRaw(in, &algRaw); err != nil {
+		return err
+	}
+	var alg algorithmIdentifier
+	if _, err = asn1.Unmarshal(algRaw.FullBytes, &alg); err != nil {
+		return ErrMalformedCertificate
+	}
+	var sigVal asn1.BitString
+	if _, err = asn1.Unmarshal(in, &sigVal); err != nil {
+		return ErrMalformedCertificate
+	}
+
+	e, ok := schemes.ByOID(alg.OID)
+	if !ok || e.Kind != schemes.KindSignature {
+		return fmt.Errorf("%w: %v", ErrUnknownOID, alg.OID)
+	}
+	var spki struct {
+		Algorithm asn1.RawValue
+		PublicKey asn1.BitString
+	}
+	if _, err = asn1.Unmarshal(parent.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return ErrMalformedCertificate
+	}
+	pk, err := e.Signature.UnmarshalBinaryPublicKey(spki.PublicKey.RightAlign())
+	if err != nil {
+		return err
+	}
+	if !e.Signature.Verify(pk, tbs.FullBytes, sigVal.RightAlign(), nil) {
+		return ErrSignatureInvalid
+	}
+
+	return nil
+}
+
+func unmarshalRaw(in []byte, out *asn1.RawValue) ([]byte, error) {
+	rest, err := asn1.Unmarshal(in, out)
+	if err != nil {
+		return nil, ErrMalformedCertificate
+	}
+
+	return rest, nil
+}
+
+// VerifyChainSignatures checks every adjacent signature of a chain ordered
+// leaf first, the last certificate being self-signed or anchored
+// elsewhere. Name constraints, expiry, and usage are not checked here;
+// this covers only what crypto/x509 refuses to do for unknown algorithms.
+func VerifyChainSignatures(chain []*x509.Certificate) error {
+	if len(chain) == 0 {
+		return errors.New("tlsutil: empty chain")
+	}
+	for i := 0; i < len(chain)-1; i++ {
+		if err := VerifyCertificateSignature(chain[i], chain[i+1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PeerCertificateVerifier returns a callback for the
+// VerifyPeerCertificate hook of a tls.Config, verifying the presented
+// chain's signatures with registered schemes. Callers still need to pin
+// or otherwise validate the root, and to check expiry and names.
+func PeerCertificateVerifier() func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		chain := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			c, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			chain[i] = c
+		}
+
+		return VerifyChainSignatures(chain)
+	}
+}
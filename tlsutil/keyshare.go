@@ -0,0 +1,140 @@
+// Package tlsutil helps experimental TLS stacks adopt CIRCL algorithms.
+// It provides the KeyShareEntry wire format together with KEM-style share
+// generation and acceptance — the shape the hybrid key-exchange design
+// uses, where the client share is a KEM public key and the server share a
+// ciphertext — and certificate signature verification that is not limited
+// to crypto/x509's fixed algorithm set. Algorithms are resolved through
+// the schemes registry, by TLS codepoint for groups and by OID for
+// certificate signatures, so a stack imports the scheme packages it wants
+// and this package picks them up.
+package tlsutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/rand"
+	"github.com/cloudflare/circl/schemes"
+)
+
+// Sentinel errors, so TLS stacks can branch on the failure cause.
+var (
+	// ErrTruncated is returned when a wire structure ends early.
+	ErrTruncated = errors.New("tlsutil: truncated KeyShareEntry")
+	// ErrUnknownCodepoint is returned when no registered KEM carries the
+	// requested TLS group codepoint.
+	ErrUnknownCodepoint = errors.New("tlsutil: unknown group codepoint")
+	// ErrGroupMismatch is returned when a reply share names a different
+	// group than the request.
+	ErrGroupMismatch = errors.New("tlsutil: server share is for a different group")
+	// ErrMalformedCertificate is returned when certificate DER cannot be
+	// walked.
+	ErrMalformedCertificate = errors.New("tlsutil: malformed certificate")
+	// ErrUnknownOID is returned when no registered signature scheme
+	// carries a certificate's algorithm OID.
+	ErrUnknownOID = errors.New("tlsutil: unregistered signature algorithm")
+	// ErrSignatureInvalid is returned when a certificate signature does
+	// not verify.
+	ErrSignatureInvalid = errors.New("tlsutil: certificate signature invalid")
+)
+
+// KeyShare is one KeyShareEntry of the TLS key_share extension.
+type KeyShare struct {
+	// Group is the TLS codepoint of the named group.
+	Group uint16
+	// Data is the opaque key_exchange field.
+	Data []byte
+}
+
+// Marshal encodes the entry in wire format: group, length, data.
+func (ks *KeyShare) Marshal() []byte {
+	out := binary.BigEndian.AppendUint16(nil, ks.Group)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(ks.Data)))
+
+	return append(out, ks.Data...)
+}
+
+// ParseKeyShare decodes one KeyShareEntry and returns the remaining bytes,
+// so a key_share list can be read by repeated calls.
+func ParseKeyShare(in []byte) (KeyShare, []byte, error) {
+	if len(in) < 4 {
+		return KeyShare{}, nil, ErrTruncated
+	}
+	n := int(binary.BigEndian.Uint16(in[2:]))
+	if len(in)-4 < n {
+		return KeyShare{}, nil, ErrTruncated
+	}
+
+	return KeyShare{
+		Group: binary.BigEndian.Uint16(in),
+		Data:  append([]byte{}, in[4:4+n]...),
+	}, in[4+n:], nil
+}
+
+// ClientShare is the client's state between sending its share and
+// receiving the server's.
+type ClientShare struct {
+	group  uint16
+	finish func(serverData []byte) ([]byte, error)
+}
+
+// GenerateKeyShare creates the client-side share for a group codepoint,
+// resolved as a KEM in the schemes registry: the share carries the public
+// key, and Finish on the returned state decapsulates the server's reply.
+func GenerateKeyShare(group uint16) (*ClientShare, KeyShare, error) {
+	e, ok := schemes.ByCodepoint(schemes.KindKEM, uint(group))
+	if !ok {
+		return nil, KeyShare{}, ErrUnknownCodepoint
+	}
+	pk, sk, err := e.KEM.GenerateKeyPair()
+	if err != nil {
+		return nil, KeyShare{}, err
+	}
+	pub, err := pk.MarshalBinary()
+	if err != nil {
+		return nil, KeyShare{}, err
+	}
+	cs := &ClientShare{
+		group: group,
+		finish: func(ct []byte) ([]byte, error) {
+			return e.KEM.Decapsulate(sk, ct)
+		},
+	}
+
+	return cs, KeyShare{Group: group, Data: pub}, nil
+}
+
+// Finish consumes the server's share and returns the shared secret.
+func (cs *ClientShare) Finish(server KeyShare) ([]byte, error) {
+	if server.Group != cs.group {
+		return nil, ErrGroupMismatch
+	}
+
+	return cs.finish(server.Data)
+}
+
+// AcceptKeyShare is the server side: it encapsulates against the client's
+// share and returns the reply share and the shared secret. A nil rnd uses
+// the library-wide source of package rand.
+func AcceptKeyShare(rnd io.Reader, client KeyShare) (KeyShare, []byte, error) {
+	rnd = rand.Source(rnd)
+	e, ok := schemes.ByCodepoint(schemes.KindKEM, uint(client.Group))
+	if !ok {
+		return KeyShare{}, nil, ErrUnknownCodepoint
+	}
+	pk, err := e.KEM.UnmarshalBinaryPublicKey(client.Data)
+	if err != nil {
+		return KeyShare{}, nil, err
+	}
+	seed := make([]byte, e.KEM.EncapsulationSeedSize())
+	if _, err = io.ReadFull(rnd, seed); err != nil {
+		return KeyShare{}, nil, err
+	}
+	ct, ss, err := e.KEM.EncapsulateDeterministically(pk, seed)
+	if err != nil {
+		return KeyShare{}, nil, err
+	}
+
+	return KeyShare{Group: client.Group, Data: ct}, ss, nil
+}
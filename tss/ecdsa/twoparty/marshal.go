@@ -0,0 +1,325 @@
+package twoparty
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/internal/conv"
+)
+
+// msgFormat is the version byte leading every serialized message.
+const msgFormat = 1
+
+var errMalformedMsg = errors.New("twoparty: malformed message")
+
+func appendChunk(out, b []byte) []byte {
+	out = binary.AppendUvarint(out, uint64(len(b)))
+
+	return append(out, b...)
+}
+
+func readChunk(in []byte) ([]byte, []byte, error) {
+	n, k := binary.Uvarint(in)
+	if k <= 0 || uint64(len(in)-k) < n {
+		return nil, nil, errMalformedMsg
+	}
+	in = in[k:]
+
+	return append([]byte{}, in[:n]...), in[n:], nil
+}
+
+// appendInt encodes a non-negative big integer as a length-prefixed
+// minimal magnitude.
+func appendInt(out []byte, v *big.Int) []byte {
+	return appendChunk(out, v.Bytes())
+}
+
+func readInt(in []byte) (*big.Int, []byte, error) {
+	b, in, err := readChunk(in)
+	if err != nil || (len(b) != 0 && b[0] == 0) {
+		return nil, nil, errMalformedMsg
+	}
+
+	return new(big.Int).SetBytes(b), in, nil
+}
+
+func appendElement(out []byte, e group.Element) ([]byte, error) {
+	data, err := e.MarshalBinaryCompress()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(out, data...), nil
+}
+
+func readElement(in []byte) (group.Element, []byte, error) {
+	ne := int(ecGroup.Params().CompressedElementLength)
+	if len(in) < ne {
+		return nil, nil, errMalformedMsg
+	}
+	e := ecGroup.NewElement()
+	if err := e.UnmarshalBinary(in[:ne]); err != nil {
+		return nil, nil, errMalformedMsg
+	}
+
+	return e, in[ne:], nil
+}
+
+func appendProof(out []byte, p proof) ([]byte, error) {
+	out, err := appendElement(out, p.R)
+	if err != nil {
+		return nil, err
+	}
+	z, err := p.Z.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(out, z...), nil
+}
+
+func readProof(in []byte) (proof, []byte, error) {
+	r, in, err := readElement(in)
+	if err != nil {
+		return proof{}, nil, err
+	}
+	ns := int(ecGroup.Params().ScalarLength)
+	if len(in) < ns {
+		return proof{}, nil, errMalformedMsg
+	}
+	z := ecGroup.NewScalar()
+	if err := z.UnmarshalBinary(in[:ns]); err != nil {
+		return proof{}, nil, errMalformedMsg
+	}
+
+	return proof{R: r, Z: z}, in[ns:], nil
+}
+
+func start() []byte { return []byte{msgFormat} }
+
+func finish(in []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	if len(in) != 0 {
+		return errMalformedMsg
+	}
+
+	return nil
+}
+
+func open(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != msgFormat {
+		return nil, errMalformedMsg
+	}
+
+	return data[1:], nil
+}
+
+// MarshalBinary encodes the message with a leading format byte.
+func (m *KeyGen1) MarshalBinary() ([]byte, error) {
+	return appendChunk(start(), m.Commitment), nil
+}
+
+// UnmarshalBinary decodes a message produced by MarshalBinary.
+func (m *KeyGen1) UnmarshalBinary(data []byte) error {
+	in, err := open(data)
+	if err != nil {
+		return err
+	}
+	m.Commitment, in, err = readChunk(in)
+
+	return finish(in, err)
+}
+
+// MarshalBinary encodes the message with a leading format byte.
+func (m *KeyGen2) MarshalBinary() ([]byte, error) {
+	out, err := appendElement(start(), m.Q2)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendProof(out, m.Proof)
+}
+
+// UnmarshalBinary decodes a message produced by MarshalBinary.
+func (m *KeyGen2) UnmarshalBinary(data []byte) error {
+	in, err := open(data)
+	if err != nil {
+		return err
+	}
+	if m.Q2, in, err = readElement(in); err != nil {
+		return err
+	}
+	m.Proof, in, err = readProof(in)
+
+	return finish(in, err)
+}
+
+// MarshalBinary encodes the message with a leading format byte.
+func (m *KeyGen3) MarshalBinary() ([]byte, error) {
+	out, err := appendElement(start(), m.Q1)
+	if err != nil {
+		return nil, err
+	}
+	if out, err = appendProof(out, m.Proof); err != nil {
+		return nil, err
+	}
+	out = appendChunk(out, m.Nonce)
+	out = appendInt(out, m.PaillierN)
+
+	return appendInt(out, m.EncryptedX), nil
+}
+
+// UnmarshalBinary decodes a message produced by MarshalBinary.
+func (m *KeyGen3) UnmarshalBinary(data []byte) error {
+	in, err := open(data)
+	if err != nil {
+		return err
+	}
+	if m.Q1, in, err = readElement(in); err != nil {
+		return err
+	}
+	if m.Proof, in, err = readProof(in); err != nil {
+		return err
+	}
+	if m.Nonce, in, err = readChunk(in); err != nil {
+		return err
+	}
+	if m.PaillierN, in, err = readInt(in); err != nil {
+		return err
+	}
+	m.EncryptedX, in, err = readInt(in)
+
+	return finish(in, err)
+}
+
+// MarshalBinary encodes the message with a leading format byte.
+func (m *Sign1) MarshalBinary() ([]byte, error) {
+	return appendChunk(start(), m.Commitment), nil
+}
+
+// UnmarshalBinary decodes a message produced by MarshalBinary.
+func (m *Sign1) UnmarshalBinary(data []byte) error {
+	in, err := open(data)
+	if err != nil {
+		return err
+	}
+	m.Commitment, in, err = readChunk(in)
+
+	return finish(in, err)
+}
+
+// MarshalBinary encodes the message with a leading format byte.
+func (m *Sign2) MarshalBinary() ([]byte, error) {
+	out, err := appendElement(start(), m.R2)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendProof(out, m.Proof)
+}
+
+// UnmarshalBinary decodes a message produced by MarshalBinary.
+func (m *Sign2) UnmarshalBinary(data []byte) error {
+	in, err := open(data)
+	if err != nil {
+		return err
+	}
+	if m.R2, in, err = readElement(in); err != nil {
+		return err
+	}
+	m.Proof, in, err = readProof(in)
+
+	return finish(in, err)
+}
+
+// MarshalBinary encodes the message with a leading format byte.
+func (m *Sign3) MarshalBinary() ([]byte, error) {
+	out, err := appendElement(start(), m.R1)
+	if err != nil {
+		return nil, err
+	}
+	if out, err = appendProof(out, m.Proof); err != nil {
+		return nil, err
+	}
+
+	return appendChunk(out, m.Nonce), nil
+}
+
+// UnmarshalBinary decodes a message produced by MarshalBinary.
+func (m *Sign3) UnmarshalBinary(data []byte) error {
+	in, err := open(data)
+	if err != nil {
+		return err
+	}
+	if m.R1, in, err = readElement(in); err != nil {
+		return err
+	}
+	if m.Proof, in, err = readProof(in); err != nil {
+		return err
+	}
+	m.Nonce, in, err = readChunk(in)
+
+	return finish(in, err)
+}
+
+// MarshalBinary encodes the message with a leading format byte.
+func (m *Sign4) MarshalBinary() ([]byte, error) {
+	return appendInt(start(), m.C3), nil
+}
+
+// UnmarshalBinary decodes a message produced by MarshalBinary.
+func (m *Sign4) UnmarshalBinary(data []byte) error {
+	in, err := open(data)
+	if err != nil {
+		return err
+	}
+	m.C3, in, err = readInt(in)
+
+	return finish(in, err)
+}
+
+// MarshalText encodes the message in base64.
+func (m *KeyGen1) MarshalText() ([]byte, error) { return conv.MarshalText(m) }
+
+// UnmarshalText decodes a message produced by MarshalText.
+func (m *KeyGen1) UnmarshalText(text []byte) error { return conv.UnmarshalText(m, text) }
+
+// MarshalText encodes the message in base64.
+func (m *KeyGen2) MarshalText() ([]byte, error) { return conv.MarshalText(m) }
+
+// UnmarshalText decodes a message produced by MarshalText.
+func (m *KeyGen2) UnmarshalText(text []byte) error { return conv.UnmarshalText(m, text) }
+
+// MarshalText encodes the message in base64.
+func (m *KeyGen3) MarshalText() ([]byte, error) { return conv.MarshalText(m) }
+
+// UnmarshalText decodes a message produced by MarshalText.
+func (m *KeyGen3) UnmarshalText(text []byte) error { return conv.UnmarshalText(m, text) }
+
+// MarshalText encodes the message in base64.
+func (m *Sign1) MarshalText() ([]byte, error) { return conv.MarshalText(m) }
+
+// UnmarshalText decodes a message produced by MarshalText.
+func (m *Sign1) UnmarshalText(text []byte) error { return conv.UnmarshalText(m, text) }
+
+// MarshalText encodes the message in base64.
+func (m *Sign2) MarshalText() ([]byte, error) { return conv.MarshalText(m) }
+
+// UnmarshalText decodes a message produced by MarshalText.
+func (m *Sign2) UnmarshalText(text []byte) error { return conv.UnmarshalText(m, text) }
+
+// MarshalText encodes the message in base64.
+func (m *Sign3) MarshalText() ([]byte, error) { return conv.MarshalText(m) }
+
+// UnmarshalText decodes a message produced by MarshalText.
+func (m *Sign3) UnmarshalText(text []byte) error { return conv.UnmarshalText(m, text) }
+
+// MarshalText encodes the message in base64.
+func (m *Sign4) MarshalText() ([]byte, error) { return conv.MarshalText(m) }
+
+// UnmarshalText decodes a message produced by MarshalText.
+func (m *Sign4) UnmarshalText(text []byte) error { return conv.UnmarshalText(m, text) }
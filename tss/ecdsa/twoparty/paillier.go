@@ -0,0 +1,77 @@
+package twoparty
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// paillierKey is an additively homomorphic Paillier key pair, used by party 1
+// to hand party 2 an encryption of its signing-key share.
+type paillierKey struct {
+	N      *big.Int // modulus
+	n2     *big.Int // N^2
+	lambda *big.Int // lcm(p-1, q-1)
+	mu     *big.Int // lambda^-1 mod N
+}
+
+func newPaillierKey(rnd io.Reader, bits int) (*paillierKey, error) {
+	p, err := rand.Prime(rnd, bits/2)
+	if err != nil {
+		return nil, err
+	}
+	q, err := rand.Prime(rnd, bits/2)
+	if err != nil {
+		return nil, err
+	}
+	if p.Cmp(q) == 0 {
+		return nil, errors.New("twoparty: repeated Paillier prime")
+	}
+
+	one := big.NewInt(1)
+	k := &paillierKey{N: new(big.Int).Mul(p, q)}
+	k.n2 = new(big.Int).Mul(k.N, k.N)
+	pm1 := new(big.Int).Sub(p, one)
+	qm1 := new(big.Int).Sub(q, one)
+	gcd := new(big.Int).GCD(nil, nil, pm1, qm1)
+	k.lambda = new(big.Int).Div(new(big.Int).Mul(pm1, qm1), gcd)
+	k.mu = new(big.Int).ModInverse(k.lambda, k.N)
+	if k.mu == nil {
+		return nil, errors.New("twoparty: degenerate Paillier key")
+	}
+
+	return k, nil
+}
+
+// encrypt returns (1+N)^m * r^N mod N^2 for a fresh random r.
+func (k *paillierKey) encrypt(rnd io.Reader, m *big.Int) (*big.Int, error) {
+	r, err := rand.Int(rnd, k.N)
+	if err != nil {
+		return nil, err
+	}
+	if r.Sign() == 0 {
+		r.SetInt64(1)
+	}
+
+	gm := new(big.Int).Add(big.NewInt(1), k.N)
+	gm.Exp(gm, m, k.n2)
+	rn := new(big.Int).Exp(r, k.N, k.n2)
+	return gm.Mul(gm, rn).Mod(gm, k.n2), nil
+}
+
+func (k *paillierKey) decrypt(c *big.Int) *big.Int {
+	u := new(big.Int).Exp(c, k.lambda, k.n2)
+	u.Sub(u, big.NewInt(1)).Div(u, k.N)
+	return u.Mul(u, k.mu).Mod(u, k.N)
+}
+
+// addCiphertexts homomorphically adds the plaintexts of two ciphertexts.
+func addCiphertexts(n2, a, b *big.Int) *big.Int {
+	return new(big.Int).Mod(new(big.Int).Mul(a, b), n2)
+}
+
+// mulCiphertext homomorphically multiplies a plaintext by the scalar s.
+func mulCiphertext(n2, c, s *big.Int) *big.Int {
+	return new(big.Int).Exp(c, s, n2)
+}
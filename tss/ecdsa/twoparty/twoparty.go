@@ -0,0 +1,403 @@
+// Package twoparty implements two-party ECDSA signing in the style of
+// Lindell (CRYPTO 2017), specialized for 2-of-2 deployments such as
+// split-key wallets and custody services.
+//
+// The signing key x = x1*x2 is multiplicatively shared between party 1 and
+// party 2, and neither party ever learns the full key. During key generation
+// party 1 additionally hands party 2 a Paillier encryption of x1, which lets
+// party 2 finish most of the signature homomorphically; party 1 completes
+// and verifies the signature. Both phases use commit-and-open together with
+// Schnorr proofs of knowledge, so a malicious counterpart cannot bias the
+// public key or the nonce.
+//
+// The package does not implement the zero-knowledge proofs of Lindell's
+// section 6 — that the Paillier modulus is well formed and that the
+// transferred ciphertext encrypts the discrete logarithm of Q1. Party 2
+// therefore checks only the modulus size and parity and the ciphertext
+// range, which protects against a semi-honest party 1 but not against an
+// actively malicious one, who could choose a malformed key or ciphertext to
+// learn bits of party 2's share across signing sessions. Deploy party 1
+// only where it is trusted to run the protocol honestly.
+//
+// Signatures are standard ECDSA over P-256 and verify with crypto/ecdsa.
+package twoparty
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/hardening"
+	"github.com/cloudflare/circl/rand"
+)
+
+var (
+	ecGroup = group.P256
+	// ErrProtocol is returned when a received message fails verification.
+	ErrProtocol = errors.New("twoparty: message verification failed")
+)
+
+// paillierBits is the size of party 1's Paillier modulus. It leaves ample
+// headroom over the |q^2 + q^3| plaintexts arising during signing.
+const paillierBits = 2048
+
+func orderP256() *big.Int { return elliptic.P256().Params().N }
+
+func scalarToInt(s group.Scalar) *big.Int {
+	data, err := s.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return new(big.Int).SetBytes(data)
+}
+
+func intToScalar(v *big.Int) group.Scalar {
+	r := new(big.Int).Mod(v, orderP256())
+	buf := r.FillBytes(make([]byte, ecGroup.Params().ScalarLength))
+	s := ecGroup.NewScalar()
+	if err := s.UnmarshalBinary(buf); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// xCoordinate returns the affine x-coordinate of e reduced modulo the group
+// order, as used for the r component of an ECDSA signature.
+func xCoordinate(e group.Element) *big.Int {
+	data, err := e.MarshalBinaryCompress()
+	if err != nil {
+		panic(err)
+	}
+	x := new(big.Int).SetBytes(data[1:])
+	return x.Mod(x, orderP256())
+}
+
+// proof is a non-interactive Schnorr proof of knowledge of the discrete
+// logarithm of Q.
+type proof struct {
+	R group.Element
+	Z group.Scalar
+}
+
+func prove(rnd io.Reader, x group.Scalar, Q group.Element, context string) proof {
+	k := ecGroup.RandomScalar(rnd)
+	R := ecGroup.NewElement().MulGen(k)
+	e := challenge(Q, R, context)
+	z := ecGroup.NewScalar()
+	z.Mul(e, x)
+	z.Add(z, k)
+	return proof{R: R, Z: z}
+}
+
+func (p proof) verify(Q group.Element, context string) bool {
+	e := challenge(Q, p.R, context)
+	lhs := ecGroup.NewElement().MulGen(p.Z)
+	rhs := ecGroup.NewElement().Mul(Q, e)
+	rhs.Add(rhs, p.R)
+	return lhs.IsEqual(rhs)
+}
+
+func challenge(Q, R group.Element, context string) group.Scalar {
+	h := sha256.New()
+	_, _ = h.Write([]byte("circl-2p-ecdsa" + context))
+	qb, _ := Q.MarshalBinaryCompress()
+	rb, _ := R.MarshalBinaryCompress()
+	_, _ = h.Write(qb)
+	_, _ = h.Write(rb)
+	return ecGroup.HashToScalar(h.Sum(nil), []byte(context))
+}
+
+func commit(Q group.Element, p proof, nonce []byte) []byte {
+	h := sha256.New()
+	qb, _ := Q.MarshalBinaryCompress()
+	rb, _ := p.R.MarshalBinaryCompress()
+	zb, _ := p.Z.MarshalBinary()
+	_, _ = h.Write(qb)
+	_, _ = h.Write(rb)
+	_, _ = h.Write(zb)
+	_, _ = h.Write(nonce)
+	return h.Sum(nil)
+}
+
+// KeyGen1 is party 1's first message: a commitment to its public share.
+type KeyGen1 struct{ Commitment []byte }
+
+// KeyGen2 is party 2's reply, revealing its public share with a proof.
+type KeyGen2 struct {
+	Q2    group.Element
+	Proof proof
+}
+
+// KeyGen3 opens party 1's commitment and transfers the encrypted key share.
+type KeyGen3 struct {
+	Q1         group.Element
+	Proof      proof
+	Nonce      []byte
+	PaillierN  *big.Int
+	EncryptedX *big.Int // Paillier encryption of x1
+}
+
+// Party1 holds party 1's long-term state.
+type Party1 struct {
+	x1       group.Scalar
+	q1       group.Element
+	pk       group.Element
+	paillier *paillierKey
+	nonce    []byte
+	proof    proof
+}
+
+// Party2 holds party 2's long-term state.
+type Party2 struct {
+	x2         group.Scalar
+	pk         group.Element
+	com        []byte
+	paillierN  *big.Int
+	encryptedX *big.Int
+}
+
+// NewParty1 samples party 1's key share and produces its first message.
+func NewParty1(rnd io.Reader) (*Party1, *KeyGen1, error) {
+	rnd = rand.Source(rnd)
+	paillier, err := newPaillierKey(rnd, paillierBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &Party1{x1: ecGroup.RandomNonZeroScalar(rnd), paillier: paillier}
+	p.q1 = hardening.MulGen(ecGroup, p.x1, rnd)
+	p.proof = prove(rnd, p.x1, p.q1, "keygen-p1")
+	p.nonce = make([]byte, 32)
+	if _, err := io.ReadFull(rnd, p.nonce); err != nil {
+		return nil, nil, err
+	}
+	return p, &KeyGen1{Commitment: commit(p.q1, p.proof, p.nonce)}, nil
+}
+
+// NewParty2 samples party 2's key share and answers party 1's commitment.
+func NewParty2(rnd io.Reader, msg1 *KeyGen1) (*Party2, *KeyGen2, error) {
+	rnd = rand.Source(rnd)
+	p := &Party2{x2: ecGroup.RandomNonZeroScalar(rnd), com: msg1.Commitment}
+	q2 := hardening.MulGen(ecGroup, p.x2, rnd)
+	return p, &KeyGen2{Q2: q2, Proof: prove(rnd, p.x2, q2, "keygen-p2")}, nil
+}
+
+// KeyGenFinish1 processes party 2's reply and opens the commitment. After
+// this call party 1's public key is available via PublicKey.
+func (p *Party1) KeyGenFinish1(rnd io.Reader, msg2 *KeyGen2) (*KeyGen3, error) {
+	rnd = rand.Source(rnd)
+	if !msg2.Proof.verify(msg2.Q2, "keygen-p2") {
+		return nil, ErrProtocol
+	}
+	p.pk = ecGroup.NewElement().Mul(msg2.Q2, p.x1)
+
+	encX, err := p.paillier.encrypt(rnd, scalarToInt(p.x1))
+	if err != nil {
+		return nil, err
+	}
+	return &KeyGen3{
+		Q1: p.q1, Proof: p.proof, Nonce: p.nonce,
+		PaillierN: p.paillier.N, EncryptedX: encX,
+	}, nil
+}
+
+// KeyGenFinish2 verifies the opened commitment and stores the encrypted key
+// share. After this call party 2's public key is available via PublicKey.
+//
+// The Paillier modulus and ciphertext receive only sanity checks, not the
+// zero-knowledge proofs a maliciously secure instantiation requires; see the
+// package documentation.
+func (p *Party2) KeyGenFinish2(msg3 *KeyGen3) error {
+	expected := commit(msg3.Q1, msg3.Proof, msg3.Nonce)
+	if subtle.ConstantTimeCompare(expected, p.com) != 1 ||
+		!msg3.Proof.verify(msg3.Q1, "keygen-p1") {
+		return ErrProtocol
+	}
+	if err := checkPaillierTransfer(msg3.PaillierN, msg3.EncryptedX); err != nil {
+		return err
+	}
+	p.pk = ecGroup.NewElement().Mul(msg3.Q1, p.x2)
+	p.paillierN = msg3.PaillierN
+	p.encryptedX = msg3.EncryptedX
+	return nil
+}
+
+// checkPaillierTransfer sanity-checks the key material party 1 transfers:
+// the modulus must be an odd composite of the expected size with no small
+// factors, large enough that the homomorphic evaluation in SignRound4 cannot
+// wrap around, and the ciphertext must be a unit in the right range.
+func checkPaillierTransfer(n, encX *big.Int) error {
+	if n == nil || encX == nil || n.BitLen() < paillierBits-1 || n.Bit(0) == 0 {
+		return ErrProtocol
+	}
+	if n.ProbablyPrime(20) {
+		return ErrProtocol
+	}
+	smallPrimes := []int64{3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47}
+	r := new(big.Int)
+	for _, s := range smallPrimes {
+		if r.Mod(n, big.NewInt(s)).Sign() == 0 {
+			return ErrProtocol
+		}
+	}
+	n2 := new(big.Int).Mul(n, n)
+	if encX.Sign() <= 0 || encX.Cmp(n2) >= 0 ||
+		new(big.Int).GCD(nil, nil, encX, n).Cmp(big.NewInt(1)) != 0 {
+		return ErrProtocol
+	}
+	return nil
+}
+
+// PublicKey returns the joint public key Q = x1*x2*G.
+func (p *Party1) PublicKey() group.Element { return p.pk.Copy() }
+
+// PublicKey returns the joint public key Q = x1*x2*G.
+func (p *Party2) PublicKey() group.Element { return p.pk.Copy() }
+
+// Sign1 is party 1's first signing message: a commitment to its nonce share.
+type Sign1 struct{ Commitment []byte }
+
+// Sign2 is party 2's nonce share with a proof of knowledge.
+type Sign2 struct {
+	R2    group.Element
+	Proof proof
+}
+
+// Sign3 opens party 1's nonce commitment.
+type Sign3 struct {
+	R1    group.Element
+	Proof proof
+	Nonce []byte
+}
+
+// Sign4 carries party 2's homomorphically evaluated partial signature.
+type Sign4 struct{ C3 *big.Int }
+
+// SignSession1 is party 1's per-signature state.
+type SignSession1 struct {
+	k1    group.Scalar
+	r1    group.Element
+	proof proof
+	nonce []byte
+	r2    group.Element
+}
+
+// SignSession2 is party 2's per-signature state.
+type SignSession2 struct {
+	k2  group.Scalar
+	com []byte
+}
+
+// SignInit starts a signature by committing to party 1's nonce share.
+func (p *Party1) SignInit(rnd io.Reader) (*SignSession1, *Sign1, error) {
+	rnd = rand.Source(rnd)
+	st := &SignSession1{k1: ecGroup.RandomNonZeroScalar(rnd)}
+	st.r1 = ecGroup.NewElement().MulGen(st.k1)
+	st.proof = prove(rnd, st.k1, st.r1, "sign-p1")
+	st.nonce = make([]byte, 32)
+	if _, err := io.ReadFull(rnd, st.nonce); err != nil {
+		return nil, nil, err
+	}
+	return st, &Sign1{Commitment: commit(st.r1, st.proof, st.nonce)}, nil
+}
+
+// SignRound2 answers with party 2's nonce share.
+func (p *Party2) SignRound2(rnd io.Reader, msg1 *Sign1) (*SignSession2, *Sign2, error) {
+	rnd = rand.Source(rnd)
+	st := &SignSession2{k2: ecGroup.RandomNonZeroScalar(rnd), com: msg1.Commitment}
+	r2 := ecGroup.NewElement().MulGen(st.k2)
+	return st, &Sign2{R2: r2, Proof: prove(rnd, st.k2, r2, "sign-p2")}, nil
+}
+
+// SignRound3 verifies party 2's nonce share and opens the commitment.
+func (p *Party1) SignRound3(st *SignSession1, msg2 *Sign2) (*Sign3, error) {
+	if !msg2.Proof.verify(msg2.R2, "sign-p2") {
+		return nil, ErrProtocol
+	}
+	st.r2 = msg2.R2
+	return &Sign3{R1: st.r1, Proof: st.proof, Nonce: st.nonce}, nil
+}
+
+// SignRound4 computes party 2's partial signature over the message digest.
+// The digest must be the full hash of the message, e.g. a SHA-256 output.
+func (p *Party2) SignRound4(rnd io.Reader, st *SignSession2, msg3 *Sign3, digest []byte) (*Sign4, error) {
+	rnd = rand.Source(rnd)
+	expected := commit(msg3.R1, msg3.Proof, msg3.Nonce)
+	if subtle.ConstantTimeCompare(expected, st.com) != 1 ||
+		!msg3.Proof.verify(msg3.R1, "sign-p1") {
+		return nil, ErrProtocol
+	}
+
+	q := orderP256()
+	n2 := new(big.Int).Mul(p.paillierN, p.paillierN)
+	r := xCoordinate(ecGroup.NewElement().Mul(msg3.R1, st.k2))
+	m := new(big.Int).Mod(new(big.Int).SetBytes(digest), q)
+
+	k2Inv := scalarToInt(ecGroup.NewScalar().Inv(st.k2))
+	// c3 = Enc(k2^-1*m + rho*q) (+) (k2^-1 * r * x2) (*) Enc(x1).
+	// The mask rho is drawn from [0, q^2) so that rho*q statistically hides
+	// the non-reduced partial signature, which can approach q^2, inside the
+	// Paillier plaintext.
+	rho, err := randInt(rnd, new(big.Int).Mul(q, q))
+	if err != nil {
+		return nil, err
+	}
+	t1 := new(big.Int).Mul(k2Inv, m)
+	t1.Mod(t1, q).Add(t1, new(big.Int).Mul(rho, q))
+	key := &paillierKey{N: p.paillierN, n2: n2}
+	c1, err := key.encrypt(rnd, t1)
+	if err != nil {
+		return nil, err
+	}
+	t2 := new(big.Int).Mul(k2Inv, r)
+	t2.Mul(t2, scalarToInt(p.x2)).Mod(t2, q)
+	c2 := mulCiphertext(n2, p.encryptedX, t2)
+
+	return &Sign4{C3: addCiphertexts(n2, c1, c2)}, nil
+}
+
+// SignFinish decrypts the partial signature, completes it, and verifies the
+// result against the joint public key before releasing it.
+func (p *Party1) SignFinish(st *SignSession1, msg4 *Sign4, digest []byte) (r, s *big.Int, err error) {
+	q := orderP256()
+	sp := p.paillier.decrypt(msg4.C3)
+	sp.Mod(sp, q)
+	k1Inv := scalarToInt(ecGroup.NewScalar().Inv(st.k1))
+	s = sp.Mul(sp, k1Inv).Mod(sp, q)
+	if minus := new(big.Int).Sub(q, s); minus.Cmp(s) < 0 {
+		s = minus
+	}
+	r = xCoordinate(ecGroup.NewElement().Mul(st.r2, st.k1))
+	if r.Sign() == 0 || s.Sign() == 0 || !verifyECDSA(p.pk, digest, r, s) {
+		return nil, nil, ErrProtocol
+	}
+	return r, s, nil
+}
+
+func verifyECDSA(pk group.Element, digest []byte, r, s *big.Int) bool {
+	q := orderP256()
+	m := new(big.Int).Mod(new(big.Int).SetBytes(digest), q)
+	sInv := new(big.Int).ModInverse(s, q)
+	if sInv == nil {
+		return false
+	}
+	u1 := intToScalar(new(big.Int).Mul(m, sInv))
+	u2 := intToScalar(new(big.Int).Mul(r, sInv))
+	R := ecGroup.NewElement().MulGen(u1)
+	R.Add(R, ecGroup.NewElement().Mul(pk, u2))
+	if R.IsIdentity() {
+		return false
+	}
+	return xCoordinate(R).Cmp(r) == 0
+}
+
+func randInt(rnd io.Reader, max *big.Int) (*big.Int, error) {
+	buf := make([]byte, (max.BitLen()+7)/8)
+	if _, err := io.ReadFull(rnd, buf); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(buf), max), nil
+}
@@ -0,0 +1,258 @@
+package twoparty_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/tss/ecdsa/twoparty"
+)
+
+// keygen runs the key-generation phase once and caches the result; the
+// 2048-bit Paillier key makes it too slow to repeat per test.
+var keygen = sync.OnceValues(func() (parties struct {
+	p1 *twoparty.Party1
+	p2 *twoparty.Party2
+}, err error,
+) {
+	p1, msg1, err := twoparty.NewParty1(rand.Reader)
+	if err != nil {
+		return parties, err
+	}
+	p2, msg2, err := twoparty.NewParty2(rand.Reader, msg1)
+	if err != nil {
+		return parties, err
+	}
+	msg3, err := p1.KeyGenFinish1(rand.Reader, msg2)
+	if err != nil {
+		return parties, err
+	}
+	if err = p2.KeyGenFinish2(msg3); err != nil {
+		return parties, err
+	}
+	parties.p1, parties.p2 = p1, p2
+	return parties, nil
+})
+
+func setup(t *testing.T) (*twoparty.Party1, *twoparty.Party2) {
+	t.Helper()
+	parties, err := keygen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parties.p1, parties.p2
+}
+
+// sign runs the signing phase for a digest, marshaling every message through
+// its wire format, and optionally tampering with one of them.
+func sign(t *testing.T, p1 *twoparty.Party1, p2 *twoparty.Party2, digest []byte) (r, s *big.Int, err error) {
+	t.Helper()
+	st1, msg1, err := p1.SignInit(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	st2, msg2, err := p2.SignRound2(rand.Reader, roundtrip(t, msg1, new(twoparty.Sign1)))
+	if err != nil {
+		return nil, nil, err
+	}
+	msg3, err := p1.SignRound3(st1, roundtrip(t, msg2, new(twoparty.Sign2)))
+	if err != nil {
+		return nil, nil, err
+	}
+	msg4, err := p2.SignRound4(rand.Reader, st2, roundtrip(t, msg3, new(twoparty.Sign3)), digest)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p1.SignFinish(st1, roundtrip(t, msg4, new(twoparty.Sign4)), digest)
+}
+
+type wire interface {
+	MarshalBinary() ([]byte, error)
+}
+
+func roundtrip[M interface {
+	wire
+	UnmarshalBinary([]byte) error
+}](t *testing.T, in wire, out M) M {
+	t.Helper()
+	data, err := in.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = out.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func toECDSA(t *testing.T, pk group.Element) *ecdsa.PublicKey {
+	t.Helper()
+	data, err := pk.MarshalBinaryCompress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y := elliptic.UnmarshalCompressed(elliptic.P256(), data)
+	if x == nil {
+		t.Fatal("public key is not a P-256 point")
+	}
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+}
+
+func TestSign(t *testing.T) {
+	p1, p2 := setup(t)
+	if !p1.PublicKey().IsEqual(p2.PublicKey()) {
+		t.Fatal("parties disagree on the public key")
+	}
+	pk := toECDSA(t, p1.PublicKey())
+
+	for _, msg := range []string{"transfer 1", "transfer 2"} {
+		digest := sha256.Sum256([]byte(msg))
+		r, s, err := sign(t, p1, p2, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ecdsa.Verify(pk, digest[:], r, s) {
+			t.Fatal("signature does not verify with crypto/ecdsa")
+		}
+	}
+}
+
+func TestKeyGenTamper(t *testing.T) {
+	p1, msg1, err := twoparty.NewParty1(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, msg2, err := twoparty.NewParty2(rand.Reader, msg1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A share whose proof belongs to a different key must abort party 1.
+	_, otherMsg2, err := twoparty.NewParty2(rand.Reader, msg1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mixed := *msg2
+	mixed.Q2 = otherMsg2.Q2
+	if _, err = p1.KeyGenFinish1(rand.Reader, &mixed); err == nil {
+		t.Fatal("party 1 accepts a share with a mismatched proof")
+	}
+
+	msg3, err := p1.KeyGenFinish1(rand.Reader, msg2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Opening a different share than committed must abort party 2.
+	forged := *msg3
+	forged.Nonce = append([]byte{}, msg3.Nonce...)
+	forged.Nonce[0] ^= 1
+	if p2.KeyGenFinish2(&forged) == nil {
+		t.Fatal("party 2 accepts a broken commitment opening")
+	}
+
+	if err = p2.KeyGenFinish2(msg3); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPaillierValidation(t *testing.T) {
+	p1, msg1, err := twoparty.NewParty1(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, msg2, err := twoparty.NewParty2(rand.Reader, msg1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg3, err := p1.KeyGenFinish1(rand.Reader, msg2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The commitment does not cover the Paillier transfer, so a tampered
+	// modulus or ciphertext must be caught by validation.
+	n2 := new(big.Int).Mul(msg3.PaillierN, msg3.PaillierN)
+	for name, forge := range map[string]func(*twoparty.KeyGen3){
+		"undersized modulus": func(m *twoparty.KeyGen3) {
+			m.PaillierN = new(big.Int).Rsh(m.PaillierN, 512)
+		},
+		"even modulus": func(m *twoparty.KeyGen3) {
+			m.PaillierN = new(big.Int).Sub(m.PaillierN, big.NewInt(1))
+		},
+		"modulus with small factor": func(m *twoparty.KeyGen3) {
+			three := big.NewInt(3)
+			n := new(big.Int).Sub(m.PaillierN, new(big.Int).Mod(m.PaillierN, three))
+			m.PaillierN = n.Add(n, three) // next multiple of 3, still odd or even; force odd
+			if m.PaillierN.Bit(0) == 0 {
+				m.PaillierN.Add(m.PaillierN, three)
+			}
+		},
+		"zero ciphertext":      func(m *twoparty.KeyGen3) { m.EncryptedX = new(big.Int) },
+		"oversized ciphertext": func(m *twoparty.KeyGen3) { m.EncryptedX = new(big.Int).Add(n2, big.NewInt(1)) },
+		"non-unit ciphertext":  func(m *twoparty.KeyGen3) { m.EncryptedX = new(big.Int).Set(m.PaillierN) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			p2, _, err := twoparty.NewParty2(rand.Reader, msg1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			forged := *msg3
+			forge(&forged)
+			if p2.KeyGenFinish2(&forged) == nil {
+				t.Fatal("malformed Paillier transfer accepted")
+			}
+		})
+	}
+}
+
+func TestSignTamper(t *testing.T) {
+	p1, p2 := setup(t)
+	digest := sha256.Sum256([]byte("tamper"))
+
+	st1, msg1, err := p1.SignInit(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st2, msg2, err := p2.SignRound2(rand.Reader, msg1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg3, err := p1.SignRound3(st1, msg2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A broken commitment opening must abort party 2.
+	forged := *msg3
+	forged.Nonce = append([]byte{}, msg3.Nonce...)
+	forged.Nonce[0] ^= 1
+	if _, err = p2.SignRound4(rand.Reader, st2, &forged, digest[:]); err == nil {
+		t.Fatal("party 2 accepts a broken nonce opening")
+	}
+
+	msg4, err := p2.SignRound4(rand.Reader, st2, msg3, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A corrupted partial signature must not be released by party 1.
+	badC3 := &twoparty.Sign4{C3: new(big.Int).Add(msg4.C3, big.NewInt(1))}
+	if _, _, err = p1.SignFinish(st1, badC3, digest[:]); err == nil {
+		t.Fatal("party 1 releases a corrupted signature")
+	}
+
+	// The honest run still completes.
+	r, s, err := p1.SignFinish(st1, msg4, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ecdsa.Verify(toECDSA(t, p1.PublicKey()), digest[:], r, s) {
+		t.Fatal("signature does not verify")
+	}
+}
@@ -0,0 +1,202 @@
+// Package runner provides a transport-agnostic state machine for executing
+// round-based threshold protocols, such as distributed key generation and
+// threshold signing. A protocol is expressed as a chain of rounds; the
+// runner tracks round numbers, validates senders, buffers messages that
+// arrive one round early, and enforces deadlines, so that integrators only
+// implement message delivery between parties.
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PartyID identifies a protocol participant. Identifiers must be unique
+// within a session but carry no other meaning to the runner.
+type PartyID uint32
+
+// Broadcast is the destination of messages addressed to every participant.
+const Broadcast PartyID = 0xffffffff
+
+// Message is the unit of communication between parties. The runner fills in
+// the routing fields; the payload encoding is owned by the protocol rounds.
+type Message struct {
+	SessionID []byte
+	Round     uint16
+	From      PartyID
+	To        PartyID
+	Payload   []byte
+}
+
+// Round is one step of a protocol, implemented by the protocol package.
+type Round interface {
+	// Start returns the messages this party sends in the round. It is
+	// called once, when the round becomes current.
+	Start() ([]Message, error)
+	// Receive processes one incoming message of this round. Returning an
+	// error aborts the session.
+	Receive(msg Message) error
+	// Complete reports whether every message expected in this round has
+	// been received.
+	Complete() bool
+	// Next returns the following round, or nil when the protocol is done.
+	Next() (Round, error)
+}
+
+// ErrAborted is returned by all methods after a session has been aborted,
+// wrapped around the cause.
+var ErrAborted = errors.New("runner: session aborted")
+
+// ErrDeadline is the abort cause when the session deadline passes before a
+// round completes.
+var ErrDeadline = errors.New("runner: deadline exceeded")
+
+// Session drives one execution of a protocol for one party.
+type Session struct {
+	id       []byte
+	self     PartyID
+	parties  map[PartyID]bool
+	round    Round
+	number   uint16
+	buffered []Message
+	deadline time.Time
+	now      func() time.Time
+	abortErr error
+	done     bool
+}
+
+// New creates a session for the given party. The parties slice lists every
+// participant, including self. A zero deadline disables timeouts.
+func New(sessionID []byte, self PartyID, parties []PartyID, first Round, deadline time.Time) (*Session, error) {
+	s := &Session{
+		id:       sessionID,
+		self:     self,
+		parties:  make(map[PartyID]bool, len(parties)),
+		round:    first,
+		number:   1,
+		deadline: deadline,
+		now:      time.Now,
+	}
+	for _, p := range parties {
+		if p == Broadcast || s.parties[p] {
+			return nil, fmt.Errorf("runner: invalid or duplicated party %d", p)
+		}
+		s.parties[p] = true
+	}
+	if !s.parties[self] {
+		return nil, errors.New("runner: self is not a participant")
+	}
+
+	return s, nil
+}
+
+// Start begins the first round and returns the messages to deliver.
+func (s *Session) Start() ([]Message, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	return s.stamp(s.round.Start())
+}
+
+// Deliver hands an incoming message to the session. It returns the messages
+// of the next round once the current round completes, which may be empty.
+// Messages one round ahead are buffered; anything else aborts the session.
+func (s *Session) Deliver(msg Message) ([]Message, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case string(msg.SessionID) != string(s.id):
+		return nil, s.abort(errors.New("runner: message for another session"))
+	case !s.parties[msg.From] || msg.From == s.self:
+		return nil, s.abort(fmt.Errorf("runner: message from unexpected party %d", msg.From))
+	case msg.To != Broadcast && msg.To != s.self:
+		return nil, s.abort(errors.New("runner: misrouted message"))
+	case msg.Round == s.number+1:
+		s.buffered = append(s.buffered, msg)
+		return nil, nil
+	case msg.Round != s.number:
+		return nil, s.abort(fmt.Errorf("runner: message for round %d during round %d", msg.Round, s.number))
+	}
+
+	if err := s.round.Receive(msg); err != nil {
+		return nil, s.abort(err)
+	}
+	if !s.round.Complete() {
+		return nil, nil
+	}
+
+	next, err := s.round.Next()
+	if err != nil {
+		return nil, s.abort(err)
+	}
+	if next == nil {
+		s.done = true
+		return nil, nil
+	}
+	s.round = next
+	s.number++
+
+	out, err := s.stamp(s.round.Start())
+	if err != nil {
+		return nil, err
+	}
+	buffered := s.buffered
+	s.buffered = nil
+	for _, m := range buffered {
+		more, err := s.Deliver(m)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, more...)
+	}
+
+	return out, nil
+}
+
+// Done reports whether the protocol has run to completion.
+func (s *Session) Done() bool { return s.done }
+
+// RoundNumber returns the current round number, starting at 1.
+func (s *Session) RoundNumber() uint16 { return s.number }
+
+// Abort terminates the session with the given cause, for instance when the
+// transport detects a disconnected party.
+func (s *Session) Abort(cause error) { _ = s.abort(cause) }
+
+// Err returns the abort cause, or nil while the session is healthy.
+func (s *Session) Err() error { return s.abortErr }
+
+func (s *Session) check() error {
+	if s.abortErr != nil {
+		return s.abortErr
+	}
+	if s.done {
+		return errors.New("runner: session already completed")
+	}
+	if !s.deadline.IsZero() && s.now().After(s.deadline) {
+		return s.abort(ErrDeadline)
+	}
+	return nil
+}
+
+func (s *Session) abort(cause error) error {
+	if s.abortErr == nil {
+		s.abortErr = fmt.Errorf("%w: %w", ErrAborted, cause)
+	}
+	return s.abortErr
+}
+
+func (s *Session) stamp(msgs []Message, err error) ([]Message, error) {
+	if err != nil {
+		return nil, s.abort(err)
+	}
+	for i := range msgs {
+		msgs[i].SessionID = s.id
+		msgs[i].Round = s.number
+		msgs[i].From = s.self
+	}
+	return msgs, nil
+}
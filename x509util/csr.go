@@ -0,0 +1,172 @@
+package x509util
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/rand"
+	"github.com/cloudflare/circl/schemes"
+	"github.com/cloudflare/circl/sign"
+)
+
+// CreateCertificateRequest creates a CSR signed with the private key,
+// following the calling convention of x509.CreateCertificateRequest.
+// The subject key of a CSR is the signing key itself, so the key's
+// scheme must be registered with an OID. Template fields carrying
+// key-dependent values, like SignatureAlgorithm, are ignored.
+func CreateCertificateRequest(rnd io.Reader, template *x509.CertificateRequest,
+	priv sign.PrivateKey,
+) ([]byte, error) {
+	rnd = rand.Source(rnd)
+	ent, ok := schemes.ByName(priv.Scheme().Name())
+	if !ok || len(ent.OID) == 0 {
+		return nil, errors.New("x509util: signing scheme has no registered OID")
+	}
+	pub, ok := priv.Public().(sign.PublicKey)
+	if !ok {
+		return nil, errors.New("x509util: private key has no scheme public key")
+	}
+	pubRaw, err := pub.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	// As in CreateCertificate, crypto/x509 assembles the request around
+	// a throwaway Ed25519 key and the key-dependent parts are spliced.
+	_, edPriv, err := ed25519.GenerateKey(rnd)
+	if err != nil {
+		return nil, err
+	}
+	tpl := *template
+	tpl.SignatureAlgorithm = x509.PureEd25519
+	placeholder, err := x509.CreateCertificateRequest(rnd, &tpl, edPriv)
+	if err != nil {
+		return nil, err
+	}
+
+	algDER, err := asn1.Marshal(algorithmIdentifier{OID: ent.OID})
+	if err != nil {
+		return nil, err
+	}
+	spkiDER, err := asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: algorithmIdentifier{OID: ent.OID},
+		PublicKey: asn1.BitString{Bytes: pubRaw, BitLength: 8 * len(pubRaw)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cri, err := patchCRI(placeholder, spkiDER)
+	if err != nil {
+		return nil, err
+	}
+	sig := ent.Signature.Sign(priv, cri, nil)
+
+	return assemble(cri, algDER, sig)
+}
+
+// patchCRI extracts the CertificationRequestInfo of a DER CSR and
+// replaces its SubjectPublicKeyInfo.
+func patchCRI(csr, spkiDER []byte) ([]byte, error) {
+	var seq asn1.RawValue
+	if rest, err := asn1.Unmarshal(csr, &seq); err != nil || len(rest) != 0 {
+		return nil, errors.New("x509util: malformed placeholder request")
+	}
+	var cri asn1.RawValue
+	if _, err := asn1.Unmarshal(seq.Bytes, &cri); err != nil {
+		return nil, errors.New("x509util: malformed placeholder request")
+	}
+
+	// CertificationRequestInfo: version, subject, subjectPKInfo,
+	// attributes [0].
+	var elems []asn1.RawValue
+	for in := cri.Bytes; len(in) != 0; {
+		var e asn1.RawValue
+		rest, err := asn1.Unmarshal(in, &e)
+		if err != nil {
+			return nil, errors.New("x509util: malformed CertificationRequestInfo")
+		}
+		elems = append(elems, e)
+		in = rest
+	}
+	if len(elems) < 3 {
+		return nil, errors.New("x509util: malformed CertificationRequestInfo")
+	}
+	elems[2].FullBytes = spkiDER
+
+	var body []byte
+	for _, e := range elems {
+		body = append(body, e.FullBytes...)
+	}
+
+	return asn1.Marshal(asn1.RawValue{
+		Class: asn1.ClassUniversal, Tag: asn1.TagSequence, IsCompound: true,
+		Bytes: body,
+	})
+}
+
+// RequestPublicKey returns the CSR's subject public key as a key of its
+// registered scheme, for requests whose algorithm crypto/x509 leaves
+// unparsed.
+func RequestPublicKey(csr *x509.CertificateRequest) (sign.PublicKey, error) {
+	var spki struct {
+		Algorithm struct {
+			OID    asn1.ObjectIdentifier
+			Params asn1.RawValue `asn1:"optional"`
+		}
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(csr.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return nil, errors.New("x509util: malformed SubjectPublicKeyInfo")
+	}
+	e, ok := schemes.ByOID(spki.Algorithm.OID)
+	if !ok || e.Kind != schemes.KindSignature {
+		return nil, errors.New("x509util: no registered signature scheme for OID " +
+			spki.Algorithm.OID.String())
+	}
+
+	return e.Signature.UnmarshalBinaryPublicKey(spki.PublicKey.RightAlign())
+}
+
+// CheckCertificateRequestSignature verifies the CSR's self-signature
+// with the registered scheme of its signature algorithm, the
+// proof-of-possession check that x509.CertificateRequest.CheckSignature
+// cannot do for unknown algorithms.
+func CheckCertificateRequestSignature(csr *x509.CertificateRequest) error {
+	var seq asn1.RawValue
+	if rest, err := asn1.Unmarshal(csr.Raw, &seq); err != nil || len(rest) != 0 {
+		return errors.New("x509util: malformed request")
+	}
+	var cri asn1.RawValue
+	in, err := asn1.Unmarshal(seq.Bytes, &cri)
+	if err != nil {
+		return errors.New("x509util: malformed request")
+	}
+	var alg struct {
+		OID    asn1.ObjectIdentifier
+		Params asn1.RawValue `asn1:"optional"`
+	}
+	if _, err := asn1.Unmarshal(in, &alg); err != nil {
+		return errors.New("x509util: malformed signature algorithm")
+	}
+	e, ok := schemes.ByOID(alg.OID)
+	if !ok || e.Kind != schemes.KindSignature {
+		return errors.New("x509util: no registered signature scheme for OID " +
+			alg.OID.String())
+	}
+	pub, err := RequestPublicKey(csr)
+	if err != nil {
+		return err
+	}
+	if pub.Scheme() != e.Signature {
+		return errors.New("x509util: subject key and signature schemes differ")
+	}
+	if !e.Signature.Verify(pub, csr.RawTBSCertificateRequest, csr.Signature, nil) {
+		return errors.New("x509util: invalid request signature")
+	}
+
+	return nil
+}
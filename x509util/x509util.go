@@ -0,0 +1,191 @@
+// Package x509util creates X.509 certificates for algorithms outside
+// crypto/x509's fixed set, such as ML-DSA, SLH-DSA, or composite
+// signatures. Both the subject key and the signing scheme are resolved
+// through the schemes registry by OID, so a PQ PKI can be built from any
+// registered scheme; the companion verification helpers live in package
+// tlsutil.
+//
+// crypto/x509 cannot be asked to emit an unknown AlgorithmIdentifier, so
+// CreateCertificate lets it build the certificate around a placeholder
+// Ed25519 key and then splices the registered OIDs, the real subject key,
+// and the real signature into the DER. The result is byte-for-byte what
+// crypto/x509 would produce if it knew the algorithms.
+package x509util
+
+import (
+	"crypto/ed25519"
+	"crypto/sha1" // SHA-1 only derives key identifiers, per RFC 5280
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/rand"
+	"github.com/cloudflare/circl/schemes"
+	"github.com/cloudflare/circl/sign"
+)
+
+type algorithmIdentifier struct {
+	OID asn1.ObjectIdentifier
+}
+
+type subjectPublicKeyInfo struct {
+	Algorithm algorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// CreateCertificate creates a certificate from the template, signed by
+// the parent's key, following the calling convention of
+// x509.CreateCertificate: pub is the subject's public key and priv the
+// issuer's private key; pass the template as its own parent for a
+// self-signed certificate. Both keys' schemes must be registered with an
+// OID. Template fields carrying key-dependent values, like
+// SignatureAlgorithm, are ignored.
+func CreateCertificate(rnd io.Reader, template, parent *x509.Certificate,
+	pub sign.PublicKey, priv sign.PrivateKey,
+) ([]byte, error) {
+	rnd = rand.Source(rnd)
+	pubEnt, ok := schemes.ByName(pub.Scheme().Name())
+	if !ok || len(pubEnt.OID) == 0 {
+		return nil, errors.New("x509util: subject key scheme has no registered OID")
+	}
+	sigEnt, ok := schemes.ByName(priv.Scheme().Name())
+	if !ok || len(sigEnt.OID) == 0 {
+		return nil, errors.New("x509util: signing scheme has no registered OID")
+	}
+
+	// Let crypto/x509 assemble the TBS structure around a throwaway
+	// Ed25519 key; everything key-dependent in it is replaced below.
+	edPub, edPriv, err := ed25519.GenerateKey(rnd)
+	if err != nil {
+		return nil, err
+	}
+	pubRaw, err := pub.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	tpl := *template
+	tpl.SignatureAlgorithm = x509.PureEd25519
+	if tpl.SubjectKeyId == nil && tpl.IsCA {
+		// crypto/x509 would derive this from the placeholder key.
+		skid := sha1.Sum(pubRaw)
+		tpl.SubjectKeyId = skid[:]
+	}
+	par := &tpl
+	if parent != template {
+		// crypto/x509 insists the signer matches the parent's key, so the
+		// parent temporarily carries the placeholder key too.
+		p := *parent
+		p.PublicKeyAlgorithm = x509.Ed25519
+		p.PublicKey = edPub
+		par = &p
+	}
+	placeholder, err := x509.CreateCertificate(rnd, &tpl, par, edPub, edPriv)
+	if err != nil {
+		return nil, err
+	}
+
+	algDER, err := asn1.Marshal(algorithmIdentifier{OID: sigEnt.OID})
+	if err != nil {
+		return nil, err
+	}
+	spkiDER, err := asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: algorithmIdentifier{OID: pubEnt.OID},
+		PublicKey: asn1.BitString{Bytes: pubRaw, BitLength: 8 * len(pubRaw)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tbs, err := patchTBS(placeholder, algDER, spkiDER)
+	if err != nil {
+		return nil, err
+	}
+	sig := sigEnt.Signature.Sign(priv, tbs, nil)
+
+	return assemble(tbs, algDER, sig)
+}
+
+// patchTBS extracts the TBSCertificate of a DER certificate and replaces
+// its inner signature algorithm and SubjectPublicKeyInfo.
+func patchTBS(cert, algDER, spkiDER []byte) ([]byte, error) {
+	var seq asn1.RawValue
+	if rest, err := asn1.Unmarshal(cert, &seq); err != nil || len(rest) != 0 {
+		return nil, errors.New("x509util: malformed placeholder certificate")
+	}
+	var tbs asn1.RawValue
+	if _, err := asn1.Unmarshal(seq.Bytes, &tbs); err != nil {
+		return nil, errors.New("x509util: malformed placeholder certificate")
+	}
+
+	// TBSCertificate: [0] version optional, serialNumber, signature,
+	// issuer, validity, subject, subjectPublicKeyInfo, ...
+	var elems []asn1.RawValue
+	for in := tbs.Bytes; len(in) != 0; {
+		var e asn1.RawValue
+		rest, err := asn1.Unmarshal(in, &e)
+		if err != nil {
+			return nil, errors.New("x509util: malformed TBSCertificate")
+		}
+		elems = append(elems, e)
+		in = rest
+	}
+	sigIdx := 1
+	if len(elems) != 0 && elems[0].Class == asn1.ClassContextSpecific {
+		sigIdx = 2
+	}
+	if len(elems) < sigIdx+5 {
+		return nil, errors.New("x509util: malformed TBSCertificate")
+	}
+	elems[sigIdx].FullBytes = algDER
+	elems[sigIdx+4].FullBytes = spkiDER
+
+	var body []byte
+	for _, e := range elems {
+		body = append(body, e.FullBytes...)
+	}
+
+	return asn1.Marshal(asn1.RawValue{
+		Class: asn1.ClassUniversal, Tag: asn1.TagSequence, IsCompound: true,
+		Bytes: body,
+	})
+}
+
+// assemble builds the outer Certificate SEQUENCE.
+func assemble(tbs, algDER, sig []byte) ([]byte, error) {
+	sigDER, err := asn1.Marshal(asn1.BitString{Bytes: sig, BitLength: 8 * len(sig)})
+	if err != nil {
+		return nil, err
+	}
+	body := append(append(append([]byte{}, tbs...), algDER...), sigDER...)
+
+	return asn1.Marshal(asn1.RawValue{
+		Class: asn1.ClassUniversal, Tag: asn1.TagSequence, IsCompound: true,
+		Bytes: body,
+	})
+}
+
+// PublicKey returns the certificate's subject public key as a key of its
+// registered scheme, for certificates whose algorithm crypto/x509 leaves
+// unparsed.
+func PublicKey(cert *x509.Certificate) (sign.PublicKey, error) {
+	// A permissive mirror of subjectPublicKeyInfo: parameters, if
+	// present, are ignored.
+	var spki struct {
+		Algorithm struct {
+			OID    asn1.ObjectIdentifier
+			Params asn1.RawValue `asn1:"optional"`
+		}
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(cert.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return nil, errors.New("x509util: malformed SubjectPublicKeyInfo")
+	}
+	e, ok := schemes.ByOID(spki.Algorithm.OID)
+	if !ok || e.Kind != schemes.KindSignature {
+		return nil, errors.New("x509util: no registered signature scheme for OID " +
+			spki.Algorithm.OID.String())
+	}
+
+	return e.Signature.UnmarshalBinaryPublicKey(spki.PublicKey.RightAlign())
+}
@@ -0,0 +1,260 @@
+// Package blake3 implements the BLAKE3 hash function in XOF mode, following
+// the reference specification. Both the plain and the keyed variants are
+// provided. The implementation processes input serially; the tree structure
+// of BLAKE3 is respected, so outputs are interoperable with other
+// implementations.
+package blake3
+
+import "encoding/binary"
+
+const (
+	blockSize = 64
+	chunkSize = 1024
+
+	flagChunkStart = 1 << 0
+	flagChunkEnd   = 1 << 1
+	flagParent     = 1 << 2
+	flagRoot       = 1 << 3
+	flagKeyedHash  = 1 << 4
+)
+
+var iv = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+	0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var msgPermutation = [16]int{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8}
+
+// State is a BLAKE3 instance in XOF mode. It absorbs the input written to it
+// and, after the first Read, squeezes arbitrary-length output.
+type State struct {
+	key   [8]uint32
+	flags uint32
+
+	cv       [8]uint32
+	block    [blockSize]byte
+	blockLen int
+	blocks   int
+	chunks   uint64 // chunks fully processed
+
+	stack [][8]uint32 // chaining values of completed subtrees
+
+	out       output
+	squeezing bool
+	offset    int
+	buf       [blockSize]byte
+}
+
+// New returns a BLAKE3 XOF in plain hashing mode.
+func New() *State {
+	s := &State{key: iv}
+	s.Reset()
+	return s
+}
+
+// NewKeyed returns a BLAKE3 XOF in keyed mode with a 32-byte key.
+func NewKeyed(key *[32]byte) *State {
+	s := &State{flags: flagKeyedHash}
+	for i := range s.key {
+		s.key[i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+	s.Reset()
+	return s
+}
+
+// Reset restores the initial state, keeping the key and mode.
+func (s *State) Reset() {
+	s.cv = s.key
+	s.block = [blockSize]byte{}
+	s.blockLen = 0
+	s.blocks = 0
+	s.chunks = 0
+	s.stack = s.stack[:0]
+	s.squeezing = false
+	s.offset = 0
+}
+
+// Clone returns a copy of the state.
+func (s *State) Clone() *State {
+	c := *s
+	c.stack = append([][8]uint32{}, s.stack...)
+	return &c
+}
+
+// Write absorbs more input. It panics if called after Read.
+func (s *State) Write(p []byte) (int, error) {
+	if s.squeezing {
+		panic("blake3: Write after Read")
+	}
+	written := len(p)
+	for len(p) > 0 {
+		if s.blocks*blockSize+s.blockLen == chunkSize {
+			// The chunk is full and more input follows, so it is not the
+			// final chunk: close it and merge its chaining value.
+			s.compressBlock(flagChunkEnd)
+			s.pushChunk()
+		} else if s.blockLen == blockSize {
+			s.compressBlock(0)
+		}
+		n := copy(s.block[s.blockLen:], p)
+		s.blockLen += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// compressBlock folds the buffered block into the chunk's chaining value.
+func (s *State) compressBlock(extraFlags uint32) {
+	flags := s.flags | extraFlags
+	if s.blocks == 0 {
+		flags |= flagChunkStart
+	}
+	var m [16]uint32
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint32(s.block[4*i:])
+	}
+	st := compress(&s.cv, &m, s.chunks, uint32(s.blockLen), flags)
+	copy(s.cv[:], st[:8])
+	s.blocks++
+	s.blockLen = 0
+	s.block = [blockSize]byte{}
+}
+
+// pushChunk finishes the current chunk (whose last block was just
+// compressed with the chunk-end flag by the caller) and merges subtrees.
+func (s *State) pushChunk() {
+	cv := s.cv
+	total := s.chunks + 1
+	for total&1 == 0 {
+		left := s.stack[len(s.stack)-1]
+		s.stack = s.stack[:len(s.stack)-1]
+		cv = parentCV(&s.key, &left, &cv, s.flags)
+		total >>= 1
+	}
+	s.stack = append(s.stack, cv)
+	s.chunks++
+	s.cv = s.key
+	s.blocks = 0
+}
+
+// output is the root node, from which any amount of output is squeezed.
+type output struct {
+	cv       [8]uint32
+	block    [16]uint32
+	blockLen uint32
+	counter  uint64
+	flags    uint32
+}
+
+func (s *State) finalize() {
+	// Close the current chunk with the chunk-end flag on its last block.
+	var m [16]uint32
+	blockLen := uint32(s.blockLen)
+	flags := s.flags | flagChunkEnd
+	if s.blocks == 0 {
+		flags |= flagChunkStart
+	}
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint32(s.block[4*i:])
+	}
+
+	if len(s.stack) == 0 {
+		// Single-chunk message: the chunk's last block is the root.
+		s.out = output{cv: s.cv, block: m, blockLen: blockLen, flags: flags}
+	} else {
+		st := compress(&s.cv, &m, s.chunks, blockLen, flags)
+		var cv [8]uint32
+		copy(cv[:], st[:8])
+		// Merge the remaining subtrees; the top parent is the root.
+		for i := len(s.stack) - 1; i > 0; i-- {
+			cv = parentCV(&s.key, &s.stack[i], &cv, s.flags)
+		}
+		s.out = parentOutput(&s.key, &s.stack[0], &cv, s.flags)
+	}
+	s.squeezing = true
+	s.offset = blockSize // force a refill on first Read
+}
+
+// Read squeezes output. The first call finalizes the absorbing phase.
+func (s *State) Read(p []byte) (int, error) {
+	if !s.squeezing {
+		s.finalize()
+	}
+	read := len(p)
+	for len(p) > 0 {
+		if s.offset == blockSize {
+			st := compress(&s.out.cv, &s.out.block, s.out.counter, s.out.blockLen, s.out.flags|flagRoot)
+			for i, w := range st {
+				binary.LittleEndian.PutUint32(s.buf[4*i:], w)
+			}
+			s.out.counter++
+			s.offset = 0
+		}
+		n := copy(p, s.buf[s.offset:])
+		s.offset += n
+		p = p[n:]
+	}
+	return read, nil
+}
+
+func parentCV(key, left, right *[8]uint32, flags uint32) [8]uint32 {
+	o := parentOutput(key, left, right, flags)
+	st := compress(&o.cv, &o.block, 0, blockSize, o.flags)
+	var cv [8]uint32
+	copy(cv[:], st[:8])
+	return cv
+}
+
+func parentOutput(key, left, right *[8]uint32, flags uint32) output {
+	var m [16]uint32
+	copy(m[:8], left[:])
+	copy(m[8:], right[:])
+	return output{cv: *key, block: m, blockLen: blockSize, flags: flags | flagParent}
+}
+
+// compress is the BLAKE3 compression function, returning all sixteen output
+// words so callers can squeeze 64 bytes per invocation.
+func compress(cv *[8]uint32, m *[16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	v := [16]uint32{
+		cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+		iv[0], iv[1], iv[2], iv[3],
+		uint32(counter), uint32(counter >> 32), blockLen, flags,
+	}
+	block := *m
+	for round := 0; ; round++ {
+		g(&v, 0, 4, 8, 12, block[0], block[1])
+		g(&v, 1, 5, 9, 13, block[2], block[3])
+		g(&v, 2, 6, 10, 14, block[4], block[5])
+		g(&v, 3, 7, 11, 15, block[6], block[7])
+		g(&v, 0, 5, 10, 15, block[8], block[9])
+		g(&v, 1, 6, 11, 12, block[10], block[11])
+		g(&v, 2, 7, 8, 13, block[12], block[13])
+		g(&v, 3, 4, 9, 14, block[14], block[15])
+		if round == 6 {
+			break
+		}
+		var next [16]uint32
+		for i := range next {
+			next[i] = block[msgPermutation[i]]
+		}
+		block = next
+	}
+	for i := 0; i < 8; i++ {
+		v[i] ^= v[i+8]
+		v[i+8] ^= cv[i]
+	}
+	return v
+}
+
+func g(v *[16]uint32, a, b, c, d int, mx, my uint32) {
+	v[a] = v[a] + v[b] + mx
+	v[d] = rotr(v[d]^v[a], 16)
+	v[c] = v[c] + v[d]
+	v[b] = rotr(v[b]^v[c], 12)
+	v[a] = v[a] + v[b] + my
+	v[d] = rotr(v[d]^v[a], 8)
+	v[c] = v[c] + v[d]
+	v[b] = rotr(v[b]^v[c], 7)
+}
+
+func rotr(x uint32, n uint) uint32 { return x>>n | x<<(32-n) }
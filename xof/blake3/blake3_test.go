@@ -0,0 +1,93 @@
+package blake3_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/xof/blake3"
+)
+
+// Known answers from the official BLAKE3 test vectors.
+func TestVectors(t *testing.T) {
+	for _, v := range []struct{ msg, want string }{
+		{"", "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{"abc", "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85"},
+	} {
+		h := blake3.New()
+		_, _ = h.Write([]byte(v.msg))
+		got := make([]byte, 32)
+		_, _ = h.Read(got)
+		want, err := hex.DecodeString(v.want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%q mismatch\ngot:  %x\nwant: %s", v.msg, got, v.want)
+		}
+	}
+}
+
+func TestKeyed(t *testing.T) {
+	var key [32]byte
+	copy(key[:], "0123456789abcdef0123456789abcdef")
+	a := make([]byte, 32)
+	b := make([]byte, 32)
+
+	h := blake3.NewKeyed(&key)
+	_, _ = h.Write([]byte("msg"))
+	_, _ = h.Read(a)
+	h = blake3.NewKeyed(&key)
+	_, _ = h.Write([]byte("msg"))
+	_, _ = h.Read(b)
+	if !bytes.Equal(a, b) {
+		t.Fatal("keyed mode is not deterministic")
+	}
+
+	h = blake3.New()
+	_, _ = h.Write([]byte("msg"))
+	_, _ = h.Read(b)
+	if bytes.Equal(a, b) {
+		t.Fatal("keyed mode matches the unkeyed one")
+	}
+}
+
+// Longer outputs extend shorter ones, and the digest must not depend on
+// how the input is split across Write calls, in particular around the
+// 1 KiB chunk boundaries of the tree mode.
+func TestXOFAndBoundaries(t *testing.T) {
+	msg := make([]byte, 3*1024+11)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+
+	h := blake3.New()
+	_, _ = h.Write(msg)
+	long := make([]byte, 1024)
+	_, _ = h.Read(long)
+
+	for _, split := range []int{1, 1023, 1024, 1025, 2048} {
+		h = blake3.New()
+		for rest := msg; len(rest) > 0; {
+			n := split
+			if n > len(rest) {
+				n = len(rest)
+			}
+			_, _ = h.Write(rest[:n])
+			rest = rest[n:]
+		}
+		got := make([]byte, 32)
+		_, _ = h.Read(got)
+		if !bytes.Equal(got, long[:32]) {
+			t.Errorf("split %d: digest differs", split)
+		}
+	}
+
+	h = blake3.New()
+	_, _ = h.Write(msg)
+	short := make([]byte, 64)
+	_, _ = h.Read(short)
+	if !bytes.Equal(short, long[:64]) {
+		t.Fatal("shorter output is not a prefix of the longer one")
+	}
+}
@@ -0,0 +1,152 @@
+// Package k12 implements the KangarooTwelve XOF, as specified in
+// draft-irtf-cfrg-kangarootwelve-10.
+//
+// KangarooTwelve splits its input into chunks of 8 KiB and hashes each chunk
+// with TurboSHAKE128 into a chaining value; the chaining values are absorbed
+// into a final TurboSHAKE128 node. The tree structure makes the chunk
+// hashes independent, so large inputs can be processed in parallel, while
+// short inputs (at most one chunk) pay no tree overhead.
+package k12
+
+import (
+	"github.com/cloudflare/circl/internal/sha3"
+)
+
+const chunkSize = 8192
+
+// State is an instance of KangarooTwelve. It absorbs the input written to it
+// and, after the first Read, squeezes arbitrary-length output.
+type State struct {
+	context []byte // customization string, absorbed at the end
+
+	stalk sha3.State  // final node
+	leaf  *sha3.State // current leaf, nil within the first chunk
+
+	// written is the total number of input bytes absorbed so far.
+	written int
+	// chunk is the number of bytes absorbed into the current chunk.
+	chunk int
+	// chunks is the number of complete chunks absorbed so far.
+	chunks int
+	// squeezing is set once Read has been called.
+	squeezing bool
+}
+
+// NewDraft10 creates a new instance of KangarooTwelve with the given
+// customization string.
+func NewDraft10(c []byte) State {
+	var s State
+	s.context = append([]byte{}, c...)
+	s.stalk = sha3.NewTurboShake128(0x07)
+	return s
+}
+
+// Reset restores the state to its initial value, keeping the customization
+// string.
+func (s *State) Reset() {
+	s.stalk = sha3.NewTurboShake128(0x07)
+	s.leaf = nil
+	s.written = 0
+	s.chunk = 0
+	s.chunks = 0
+	s.squeezing = false
+}
+
+// Clone returns a copy of the state in its current state.
+func (s *State) Clone() State {
+	c := *s
+	c.context = append([]byte{}, s.context...)
+	c.stalk = s.stalk.Clone()
+	if s.leaf != nil {
+		l := s.leaf.Clone()
+		c.leaf = &l
+	}
+	return c
+}
+
+// Write absorbs more input. It panics if called after Read.
+func (s *State) Write(p []byte) (int, error) {
+	if s.squeezing {
+		panic("k12: Write after Read")
+	}
+	written := len(p)
+	for len(p) > 0 {
+		if s.chunk == chunkSize {
+			s.nextChunk()
+		}
+		n := chunkSize - s.chunk
+		if n > len(p) {
+			n = len(p)
+		}
+		if s.chunks == 0 {
+			// The first chunk goes directly into the final node.
+			_, _ = s.stalk.Write(p[:n])
+		} else {
+			_, _ = s.leaf.Write(p[:n])
+		}
+		s.chunk += n
+		s.written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// nextChunk closes the current chunk and opens a new leaf.
+func (s *State) nextChunk() {
+	if s.chunks == 0 {
+		// The tree turned out to have more than one node: frame the first
+		// chunk inside the final node.
+		_, _ = s.stalk.Write([]byte{0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})
+	} else {
+		s.closeLeaf()
+	}
+	l := sha3.NewTurboShake128(0x0b)
+	s.leaf = &l
+	s.chunk = 0
+	s.chunks++
+}
+
+// closeLeaf squeezes the 32-byte chaining value of the current leaf into the
+// final node.
+func (s *State) closeLeaf() {
+	var cv [32]byte
+	_, _ = s.leaf.Read(cv[:])
+	_, _ = s.stalk.Write(cv[:])
+}
+
+// Read squeezes output. The first call finalizes the absorbing phase.
+func (s *State) Read(p []byte) (int, error) {
+	if !s.squeezing {
+		s.finalize()
+	}
+	return s.stalk.Read(p)
+}
+
+func (s *State) finalize() {
+	// The customization string and its length are part of the input.
+	_, _ = s.Write(s.context)
+	_, _ = s.Write(lengthEncode(len(s.context)))
+	s.squeezing = true
+
+	if s.chunks == 0 {
+		// Single-node case: the final node absorbed everything.
+		return
+	}
+
+	// Multi-node case: close the last leaf and frame the final node.
+	s.closeLeaf()
+	_, _ = s.stalk.Write(lengthEncode(s.chunks))
+	_, _ = s.stalk.Write([]byte{0xff, 0xff})
+	s.stalk.SwitchDS(0x06)
+}
+
+// lengthEncode returns the length_encode() of x: its big-endian encoding
+// without leading zeros, followed by the byte count of that encoding.
+func lengthEncode(x int) []byte {
+	var enc []byte
+	for x > 0 {
+		enc = append([]byte{byte(x)}, enc...)
+		x >>= 8
+	}
+	return append(enc, byte(len(enc)))
+}
@@ -0,0 +1,109 @@
+package k12_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/xof/k12"
+)
+
+// ptn returns the cycling byte pattern 00, 01, ..., fa used by the
+// KangarooTwelve test vectors.
+func ptn(n int) []byte {
+	p := make([]byte, n)
+	for i := range p {
+		p[i] = byte(i % 0xfb)
+	}
+	return p
+}
+
+// Test vectors from draft-irtf-cfrg-kangarootwelve.
+func TestVectors(t *testing.T) {
+	for _, v := range []struct {
+		msgLen int
+		want   string
+	}{
+		{0, "1ac2d450fc3b4205d19da7bfca1b37513c0803577ac7167f06fe2ce1f0ef39e5"},
+		{17, "6bf75fa2239198db4772e36478f8e19b0f371205f6a9a93a273f51df37122888"},
+		{17 * 17, "0c315ebcdedbf61426de7dcf8fb725d1e74675d7f5327a5067f367b108ecb67c"},
+	} {
+		s := k12.NewDraft10(nil)
+		_, _ = s.Write(ptn(v.msgLen))
+		got := make([]byte, 32)
+		_, _ = s.Read(got)
+		want, err := hex.DecodeString(v.want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("message length %d mismatch\ngot:  %x\nwant: %s",
+				v.msgLen, got, v.want)
+		}
+	}
+}
+
+func TestCustomization(t *testing.T) {
+	a := make([]byte, 32)
+	b := make([]byte, 32)
+	s := k12.NewDraft10(nil)
+	_, _ = s.Write([]byte("msg"))
+	_, _ = s.Read(a)
+	s = k12.NewDraft10([]byte("custom"))
+	_, _ = s.Write([]byte("msg"))
+	_, _ = s.Read(b)
+	if bytes.Equal(a, b) {
+		t.Fatal("customization string is ignored")
+	}
+}
+
+// The digest must not depend on how the input is split across Write
+// calls, in particular around the 8 KiB chunk boundaries of the tree
+// mode.
+func TestWriteBoundaries(t *testing.T) {
+	msg := ptn(3 * 8192)
+	want := make([]byte, 32)
+	s := k12.NewDraft10(nil)
+	_, _ = s.Write(msg)
+	_, _ = s.Read(want)
+
+	for _, split := range []int{1, 8191, 8192, 8193, 16384} {
+		s = k12.NewDraft10(nil)
+		for rest := msg; len(rest) > 0; {
+			n := split
+			if n > len(rest) {
+				n = len(rest)
+			}
+			_, _ = s.Write(rest[:n])
+			rest = rest[n:]
+		}
+		got := make([]byte, 32)
+		_, _ = s.Read(got)
+		if !bytes.Equal(got, want) {
+			t.Errorf("split %d: digest differs", split)
+		}
+	}
+}
+
+func TestCloneAndReset(t *testing.T) {
+	msg := ptn(10000)
+	s := k12.NewDraft10([]byte("c"))
+	_, _ = s.Write(msg[:9000])
+	c := s.Clone()
+	_, _ = s.Write(msg[9000:])
+	_, _ = c.Write(msg[9000:])
+	a := make([]byte, 32)
+	b := make([]byte, 32)
+	_, _ = s.Read(a)
+	_, _ = c.Read(b)
+	if !bytes.Equal(a, b) {
+		t.Fatal("clone diverges from original")
+	}
+
+	s.Reset()
+	_, _ = s.Write(msg)
+	_, _ = s.Read(b)
+	if !bytes.Equal(a, b) {
+		t.Fatal("reset state differs from a fresh one")
+	}
+}
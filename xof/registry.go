@@ -0,0 +1,68 @@
+package xof
+
+import (
+	"fmt"
+	"io"
+)
+
+// maxID is the highest identifier currently registered.
+const maxID = BLAKE3
+
+// IsValid returns true if the identifier names a registered XOF.
+func (x ID) IsValid() bool { return x >= SHAKE128 && x <= maxID }
+
+// String returns the name of the XOF.
+func (x ID) String() string {
+	switch x {
+	case SHAKE128:
+		return "SHAKE128"
+	case SHAKE256:
+		return "SHAKE256"
+	case BLAKE2XB:
+		return "BLAKE2XB"
+	case BLAKE2XS:
+		return "BLAKE2XS"
+	case K12D10:
+		return "KangarooTwelve"
+	case TurboSHAKE128:
+		return "TurboSHAKE128"
+	case TurboSHAKE256:
+		return "TurboSHAKE256"
+	case BLAKE3:
+		return "BLAKE3"
+	default:
+		return fmt.Sprintf("XOF(%d)", uint(x))
+	}
+}
+
+// Lookup resolves an identifier received as data, e.g., from a protocol
+// message, into a fresh XOF. Unlike ID.New, it reports unknown identifiers
+// as an error instead of panicking.
+func Lookup(id ID) (XOF, error) {
+	if !id.IsValid() {
+		return nil, fmt.Errorf("xof: unknown identifier %d", uint(id))
+	}
+	return id.New(), nil
+}
+
+// Squeeze copies n bytes of output from x to w without materializing the
+// whole output in memory, returning the number of bytes written.
+func Squeeze(w io.Writer, x XOF, n int64) (int64, error) {
+	var buf [4096]byte
+	var written int64
+	for written < n {
+		chunk := n - written
+		if chunk > int64(len(buf)) {
+			chunk = int64(len(buf))
+		}
+		if _, err := io.ReadFull(x, buf[:chunk]); err != nil {
+			return written, err
+		}
+		m, err := w.Write(buf[:chunk])
+		written += int64(m)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
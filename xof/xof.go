@@ -0,0 +1,114 @@
+// Package xof provides an interface for eXtendable-Output Functions.
+package xof
+
+import (
+	"io"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/xof/blake3"
+	"github.com/cloudflare/circl/xof/k12"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+)
+
+// XOF defines the interface to hash functions that support arbitrary-length
+// output.
+type XOF interface {
+	// Write absorbs more data into the hash's state. It panics if called
+	// after Read.
+	io.Writer
+
+	// Read reads more output from the hash. It returns io.EOF if the limit
+	// has been reached.
+	io.Reader
+
+	// Clone returns a copy of the XOF in its current state.
+	Clone() XOF
+
+	// Reset restores the XOF to its initial state and discards all data
+	// appended by Write.
+	Reset()
+}
+
+type ID uint
+
+const (
+	// SHAKE128 is the SHAKE128 XOF with 128 bits of security.
+	SHAKE128 ID = iota + 1
+	// SHAKE256 is the SHAKE256 XOF with 256 bits of security.
+	SHAKE256
+	// BLAKE2XB is the BLAKE2XB XOF with 128 bits of security.
+	BLAKE2XB
+	// BLAKE2XS is the BLAKE2XS XOF with 128 bits of security.
+	BLAKE2XS
+	// K12D10 is the KangarooTwelve XOF with 10 bytes of customization
+	// string and 128 bits of security.
+	K12D10
+	// TurboSHAKE128 is the TurboSHAKE XOF with domain separation byte 0x1f
+	// and 128 bits of security.
+	TurboSHAKE128
+	// TurboSHAKE256 is the TurboSHAKE XOF with domain separation byte 0x1f
+	// and 256 bits of security.
+	TurboSHAKE256
+	// BLAKE3 is the BLAKE3 hash in XOF mode with 128 bits of security.
+	// The keyed mode is available from the xof/blake3 package.
+	BLAKE3
+)
+
+// New creates the XOF identified by the receiver. It panics if the
+// identifier is unknown.
+func (x ID) New() XOF {
+	switch x {
+	case SHAKE128:
+		s := sha3.NewShake128()
+		return shakeBody{&s}
+	case SHAKE256:
+		s := sha3.NewShake256()
+		return shakeBody{&s}
+	case BLAKE2XB:
+		x, _ := blake2b.NewXOF(blake2b.OutputLengthUnknown, nil)
+		return blake2xb{x}
+	case BLAKE2XS:
+		x, _ := blake2s.NewXOF(blake2s.OutputLengthUnknown, nil)
+		return blake2xs{x}
+	case K12D10:
+		x := k12.NewDraft10(nil)
+		return k12Body{&x}
+	case TurboSHAKE128:
+		s := sha3.NewTurboShake128(0x1f)
+		return shakeBody{&s}
+	case TurboSHAKE256:
+		s := sha3.NewTurboShake256(0x1f)
+		return shakeBody{&s}
+	case BLAKE3:
+		return blake3Body{blake3.New()}
+	default:
+		panic("crypto: requested unavailable XOF function")
+	}
+}
+
+type shakeBody struct{ *sha3.State }
+
+func (s shakeBody) Clone() XOF {
+	c := s.State.Clone()
+	return shakeBody{&c}
+}
+
+type blake2xb struct{ blake2b.XOF }
+
+func (s blake2xb) Clone() XOF { return blake2xb{s.XOF.Clone()} }
+
+type blake2xs struct{ blake2s.XOF }
+
+func (s blake2xs) Clone() XOF { return blake2xs{s.XOF.Clone()} }
+
+type blake3Body struct{ *blake3.State }
+
+func (s blake3Body) Clone() XOF { return blake3Body{s.State.Clone()} }
+
+type k12Body struct{ *k12.State }
+
+func (s k12Body) Clone() XOF {
+	x := s.State.Clone()
+	return k12Body{&x}
+}
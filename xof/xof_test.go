@@ -0,0 +1,102 @@
+package xof_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/xof"
+)
+
+var allIDs = []xof.ID{
+	xof.SHAKE128, xof.SHAKE256, xof.BLAKE2XB, xof.BLAKE2XS,
+	xof.K12D10, xof.TurboSHAKE128, xof.TurboSHAKE256, xof.BLAKE3,
+}
+
+// SHAKE outputs over "abc" from FIPS 202 (cross-checked against the
+// Python hashlib implementation).
+var shakeVectors = []struct {
+	id   xof.ID
+	want string
+}{
+	{xof.SHAKE128, "5881092dd818bf5cf8a3ddb793fbcba74097d5c526a6d35f97b83351940f2cc8"},
+	{xof.SHAKE256, "483366601360a8771c6863080cc4114d8db44530f8f1e1ee4f94ea37e78b5739"},
+}
+
+func TestSHAKEVectors(t *testing.T) {
+	for _, v := range shakeVectors {
+		x := v.id.New()
+		_, _ = x.Write([]byte("abc"))
+		got := make([]byte, 32)
+		_, _ = x.Read(got)
+		want, err := hex.DecodeString(v.want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%v mismatch\ngot:  %x\nwant: %s", v.id, got, v.want)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	for _, id := range allIDs {
+		if !id.IsValid() {
+			t.Errorf("%v: not valid", id)
+		}
+		if x, err := xof.Lookup(id); err != nil || x == nil {
+			t.Errorf("%v: lookup failed: %v", id, err)
+		}
+	}
+	if xof.ID(0).IsValid() {
+		t.Error("zero ID is valid")
+	}
+	if _, err := xof.Lookup(xof.ID(0)); err == nil {
+		t.Error("zero ID resolves")
+	}
+}
+
+func TestCloneAndReset(t *testing.T) {
+	msg := []byte("The quick brown fox jumps over the lazy dog")
+	for _, id := range allIDs {
+		x := id.New()
+		_, _ = x.Write(msg[:20])
+		c := x.Clone()
+		_, _ = x.Write(msg[20:])
+		_, _ = c.Write(msg[20:])
+		a := make([]byte, 32)
+		b := make([]byte, 32)
+		_, _ = x.Read(a)
+		_, _ = c.Read(b)
+		if !bytes.Equal(a, b) {
+			t.Errorf("%v: clone diverges from original", id)
+		}
+
+		x.Reset()
+		_, _ = x.Write(msg)
+		_, _ = x.Read(b)
+		if !bytes.Equal(a, b) {
+			t.Errorf("%v: reset state differs from a fresh one", id)
+		}
+	}
+}
+
+func TestSqueeze(t *testing.T) {
+	for _, id := range allIDs {
+		x := id.New()
+		_, _ = x.Write([]byte("squeeze"))
+		var buf bytes.Buffer
+		n, err := xof.Squeeze(&buf, x, 1000)
+		if err != nil || n != 1000 || buf.Len() != 1000 {
+			t.Errorf("%v: squeeze failed: n=%d err=%v", id, n, err)
+		}
+
+		x = id.New()
+		_, _ = x.Write([]byte("squeeze"))
+		direct := make([]byte, 1000)
+		_, _ = x.Read(direct)
+		if !bytes.Equal(buf.Bytes(), direct) {
+			t.Errorf("%v: squeeze output differs from direct reads", id)
+		}
+	}
+}
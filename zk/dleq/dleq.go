@@ -0,0 +1,163 @@
+// Package dleq provides zero-knowledge proofs of discrete-logarithm
+// equality, also known as Chaum-Pedersen proofs.
+//
+// A proof shows that for pairs of elements (A, B) the prover knows a scalar
+// k such that B = k*A, and that the same k links every pair, without
+// revealing k. Proofs are made non-interactive with the Fiat-Shamir
+// transform. The package is generic over the group.Group interface and is
+// used as a building block by verifiable OPRFs, VRFs, and threshold
+// decryption.
+package dleq
+
+import (
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Params configures provers and verifiers. Both sides must agree on the
+// group, the hash used for the Fiat-Shamir challenge, and the domain
+// separation tag.
+type Params struct {
+	G   group.Group
+	H   crypto.Hash
+	DST []byte
+}
+
+// Proof is a non-interactive proof of discrete-logarithm equality across one
+// or more pairs of group elements.
+type Proof struct {
+	C group.Scalar
+	S group.Scalar
+}
+
+// Prover creates proofs under a fixed set of parameters.
+type Prover struct{ Params }
+
+// Verifier checks proofs under a fixed set of parameters.
+type Verifier struct{ Params }
+
+// Prove returns a proof that B[i] = k*A[i] for all i. The two slices must
+// have the same, non-zero length.
+func (p Prover) Prove(rnd io.Reader, k group.Scalar, A, B []group.Element) (*Proof, error) {
+	rnd = rand.Source(rnd)
+	return p.ProveWithRandomness(k, A, B, p.G.RandomScalar(rnd))
+}
+
+// ProveWithRandomness is deterministic given the commitment randomness r,
+// which must be sampled uniformly and never reused across proofs.
+func (p Prover) ProveWithRandomness(k group.Scalar, A, B []group.Element, r group.Scalar) (*Proof, error) {
+	if len(A) == 0 || len(A) != len(B) {
+		return nil, errors.New("dleq: mismatched statement")
+	}
+
+	T := make([]group.Element, len(A))
+	for i := range A {
+		T[i] = p.G.NewElement().Mul(A[i], r)
+	}
+	c, err := p.challenge(A, B, T)
+	if err != nil {
+		return nil, err
+	}
+	s := p.G.NewScalar()
+	s.Mul(c, k)
+	s.Sub(r, s)
+
+	return &Proof{C: c, S: s}, nil
+}
+
+// Verify checks a proof that the pairs (A[i], B[i]) share a discrete
+// logarithm.
+func (v Verifier) Verify(A, B []group.Element, proof *Proof) bool {
+	if len(A) == 0 || len(A) != len(B) {
+		return false
+	}
+
+	T := make([]group.Element, len(A))
+	for i := range A {
+		T[i] = v.G.NewElement().Mul(A[i], proof.S)
+		T[i].Add(T[i], v.G.NewElement().Mul(B[i], proof.C))
+	}
+	c, err := v.challenge(A, B, T)
+	if err != nil {
+		return false
+	}
+
+	return proof.C.IsEqual(c)
+}
+
+// VerifyBatch checks several independent proofs over the same parameters.
+// It accepts the batch only if every proof is valid; a single invalid proof
+// rejects the whole batch without identifying it. The statements slice
+// pairs A[i] with B[i] for proof i.
+func (v Verifier) VerifyBatch(A, B [][]group.Element, proofs []*Proof) bool {
+	if len(A) != len(B) || len(A) != len(proofs) {
+		return false
+	}
+
+	ok := len(proofs) > 0
+	for i := range proofs {
+		ok = v.Verify(A[i], B[i], proofs[i]) && ok
+	}
+	return ok
+}
+
+func (p Params) challenge(A, B, T []group.Element) (group.Scalar, error) {
+	h := p.H.New()
+	_, _ = h.Write(p.DST)
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(A)))
+	_, _ = h.Write(n[:])
+	for _, list := range [][]group.Element{A, B, T} {
+		for i := range list {
+			data, err := list[i].MarshalBinaryCompress()
+			if err != nil {
+				return nil, err
+			}
+			_, _ = h.Write(data)
+		}
+	}
+
+	return p.G.HashToScalar(h.Sum(nil), p.DST), nil
+}
+
+// proofFormat is the version byte leading a serialized proof.
+const proofFormat = 1
+
+// MarshalBinary encodes the proof as a format byte followed by the two
+// scalars.
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	c, err := p.C.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	s, err := p.S.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append([]byte{proofFormat}, c...), s...), nil
+}
+
+// UnmarshalProof decodes a proof produced by Proof.MarshalBinary over the
+// parameters' group, rejecting trailing data.
+func (p Params) UnmarshalProof(data []byte) (*Proof, error) {
+	errMalformed := errors.New("dleq: malformed proof")
+	ns := int(p.G.Params().ScalarLength)
+	if len(data) != 1+2*ns || data[0] != proofFormat {
+		return nil, errMalformed
+	}
+	out := &Proof{C: p.G.NewScalar(), S: p.G.NewScalar()}
+	if err := out.C.UnmarshalBinary(data[1 : 1+ns]); err != nil {
+		return nil, errMalformed
+	}
+	if err := out.S.UnmarshalBinary(data[1+ns:]); err != nil {
+		return nil, errMalformed
+	}
+
+	return out, nil
+}
@@ -0,0 +1,104 @@
+package dleq_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	_ "crypto/sha256"
+	"testing"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/zk/dleq"
+)
+
+func testParams(g group.Group) dleq.Params {
+	return dleq.Params{G: g, H: crypto.SHA256, DST: []byte("dleq test")}
+}
+
+func TestProveVerify(t *testing.T) {
+	for name, g := range map[string]group.Group{
+		"P256":         group.P256,
+		"Ristretto255": group.Ristretto255,
+	} {
+		g := g
+		t.Run(name, func(t *testing.T) {
+			params := testParams(g)
+			prover := dleq.Prover{Params: params}
+			verifier := dleq.Verifier{Params: params}
+
+			k := g.RandomScalar(rand.Reader)
+			A := []group.Element{
+				g.HashToElement([]byte("base one"), params.DST),
+				g.HashToElement([]byte("base two"), params.DST),
+			}
+			B := make([]group.Element, len(A))
+			for i := range A {
+				B[i] = g.NewElement().Mul(A[i], k)
+			}
+
+			proof, err := prover.Prove(rand.Reader, k, A, B)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !verifier.Verify(A, B, proof) {
+				t.Fatal("valid proof rejected")
+			}
+
+			// A pair that does not share the discrete logarithm.
+			wrong := g.NewElement().Mul(A[1], g.RandomScalar(rand.Reader))
+			if verifier.Verify(A, []group.Element{B[0], wrong}, proof) {
+				t.Fatal("proof accepted for a false statement")
+			}
+
+			// A verifier with a different tag must reject.
+			other := dleq.Verifier{Params: dleq.Params{G: g, H: crypto.SHA256, DST: []byte("other")}}
+			if other.Verify(A, B, proof) {
+				t.Fatal("proof accepted under a different domain separation tag")
+			}
+
+			enc, err := proof.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			dec, err := params.UnmarshalProof(enc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !verifier.Verify(A, B, dec) {
+				t.Fatal("proof does not roundtrip")
+			}
+			if _, err = params.UnmarshalProof(enc[:len(enc)-1]); err == nil {
+				t.Fatal("truncated proof accepted")
+			}
+		})
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	params := testParams(group.Ristretto255)
+	g := params.G
+	prover := dleq.Prover{Params: params}
+	verifier := dleq.Verifier{Params: params}
+
+	const n = 4
+	A := make([][]group.Element, n)
+	B := make([][]group.Element, n)
+	proofs := make([]*dleq.Proof, n)
+	for i := range proofs {
+		k := g.RandomScalar(rand.Reader)
+		A[i] = []group.Element{g.HashToElement([]byte{byte(i)}, params.DST)}
+		B[i] = []group.Element{g.NewElement().Mul(A[i][0], k)}
+		var err error
+		proofs[i], err = prover.Prove(rand.Reader, k, A[i], B[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !verifier.VerifyBatch(A, B, proofs) {
+		t.Fatal("valid batch rejected")
+	}
+
+	proofs[2], proofs[3] = proofs[3], proofs[2]
+	if verifier.VerifyBatch(A, B, proofs) {
+		t.Fatal("batch with swapped proofs accepted")
+	}
+}
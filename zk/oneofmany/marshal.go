@@ -0,0 +1,103 @@
+package oneofmany
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/internal/conv"
+)
+
+// proofFormat is the version byte leading a serialized proof.
+const proofFormat = 1
+
+// maxRounds bounds ceil(log2 N) on decode, limiting allocations.
+const maxRounds = 64
+
+var errMalformedProof = errors.New("oneofmany: malformed proof")
+
+// MarshalBinary encodes the proof as a format byte, the number of rounds
+// m = ceil(log2 N), the 4m compressed elements, and the 3m+1 scalars.
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	m := len(p.CL)
+	if len(p.CA) != m || len(p.CB) != m || len(p.CD) != m ||
+		len(p.F) != m || len(p.ZA) != m || len(p.ZB) != m {
+		return nil, errMalformedProof
+	}
+	out := binary.AppendUvarint([]byte{proofFormat}, uint64(m))
+	for _, list := range [][]group.Element{p.CL, p.CA, p.CB, p.CD} {
+		for _, e := range list {
+			data, err := e.MarshalBinaryCompress()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, data...)
+		}
+	}
+	for _, list := range [][]group.Scalar{p.F, p.ZA, p.ZB, {p.ZD}} {
+		for _, s := range list {
+			data, err := s.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, data...)
+		}
+	}
+
+	return out, nil
+}
+
+// UnmarshalBinary decodes a proof produced by MarshalBinary, rejecting
+// trailing data.
+func (p *Proof) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != proofFormat {
+		return errMalformedProof
+	}
+	m64, k := binary.Uvarint(data[1:])
+	if k <= 0 || m64 == 0 || m64 > maxRounds {
+		return errMalformedProof
+	}
+	m := int(m64)
+	in := data[1+k:]
+	ne := int(Group.Params().CompressedElementLength)
+	ns := int(Group.Params().ScalarLength)
+	if len(in) != 4*m*ne+(3*m+1)*ns {
+		return errMalformedProof
+	}
+	elems := make([][]group.Element, 4)
+	for i := range elems {
+		elems[i] = make([]group.Element, m)
+		for j := range elems[i] {
+			elems[i][j] = Group.NewElement()
+			if err := elems[i][j].UnmarshalBinary(in[:ne]); err != nil {
+				return errMalformedProof
+			}
+			in = in[ne:]
+		}
+	}
+	scalars := make([][]group.Scalar, 3)
+	for i := range scalars {
+		scalars[i] = make([]group.Scalar, m)
+		for j := range scalars[i] {
+			scalars[i][j] = Group.NewScalar()
+			if err := scalars[i][j].UnmarshalBinary(in[:ns]); err != nil {
+				return errMalformedProof
+			}
+			in = in[ns:]
+		}
+	}
+	zd := Group.NewScalar()
+	if err := zd.UnmarshalBinary(in); err != nil {
+		return errMalformedProof
+	}
+	p.CL, p.CA, p.CB, p.CD = elems[0], elems[1], elems[2], elems[3]
+	p.F, p.ZA, p.ZB, p.ZD = scalars[0], scalars[1], scalars[2], zd
+
+	return nil
+}
+
+// MarshalText encodes the proof in base64.
+func (p *Proof) MarshalText() ([]byte, error) { return conv.MarshalText(p) }
+
+// UnmarshalText decodes a proof produced by MarshalText.
+func (p *Proof) UnmarshalText(text []byte) error { return conv.UnmarshalText(p, text) }
@@ -0,0 +1,288 @@
+// Package oneofmany implements one-of-many proofs of knowledge in the style
+// of Groth and Kohlweiss (EUROCRYPT 2015) over the ristretto255 group.
+//
+// Given a public list of Pedersen commitments, the prover shows knowledge of
+// the opening of one commitment to zero, without revealing which one. Proof
+// size and verification cost grow logarithmically in the list length, which
+// makes the proof suitable for set membership and ring-signature style
+// constructions: committing to pk_i - pk yields a proof that pk is one of
+// the listed public keys.
+package oneofmany
+
+import (
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Group is the prime-order group over which proofs are built.
+var Group = group.Ristretto255
+
+// Proof is a non-interactive one-of-many proof. For a list of N
+// commitments, it contains 4*ceil(log2 N) elements and 3*ceil(log2 N)+1
+// scalars.
+type Proof struct {
+	CL, CA, CB, CD []group.Element
+	F, ZA, ZB      []group.Scalar
+	ZD             group.Scalar
+}
+
+// Commit returns the Pedersen commitment m*H + r*G used by this package,
+// where H is derived from the domain separation tag.
+func Commit(m, r group.Scalar, dst []byte) group.Element {
+	c := Group.NewElement().MulGen(r)
+	c.Add(c, Group.NewElement().Mul(generatorH(dst), m))
+	return c
+}
+
+func generatorH(dst []byte) group.Element {
+	return Group.HashToElement([]byte("oneofmany-generator-h"), dst)
+}
+
+// Prove shows that list[index] is a commitment to zero with blinding r. The
+// list must be non-empty; it is implicitly padded to a power of two by
+// repeating its first entry.
+func Prove(rnd io.Reader, list []group.Element, index int, r group.Scalar, dst []byte) (*Proof, error) {
+	rnd = rand.Source(rnd)
+	if len(list) == 0 || index < 0 || index >= len(list) {
+		return nil, errors.New("oneofmany: index out of range")
+	}
+	list, n := pad(list)
+	N := 1 << n
+	H := generatorH(dst)
+
+	bits := make([]uint, n)
+	for j := 0; j < n; j++ {
+		bits[j] = uint(index>>j) & 1
+	}
+
+	rj := make([]group.Scalar, n)
+	aj := make([]group.Scalar, n)
+	sj := make([]group.Scalar, n)
+	tj := make([]group.Scalar, n)
+	rhoK := make([]group.Scalar, n)
+	p := &Proof{
+		CL: make([]group.Element, n), CA: make([]group.Element, n),
+		CB: make([]group.Element, n), CD: make([]group.Element, n),
+		F: make([]group.Scalar, n), ZA: make([]group.Scalar, n),
+		ZB: make([]group.Scalar, n),
+	}
+
+	for j := 0; j < n; j++ {
+		rj[j] = Group.RandomScalar(rnd)
+		aj[j] = Group.RandomScalar(rnd)
+		sj[j] = Group.RandomScalar(rnd)
+		tj[j] = Group.RandomScalar(rnd)
+		rhoK[j] = Group.RandomScalar(rnd)
+
+		lj := Group.NewScalar()
+		lj.SetUint64(uint64(bits[j]))
+		p.CL[j] = commitTo(H, lj, rj[j])
+		p.CA[j] = commitTo(H, aj[j], sj[j])
+		la := Group.NewScalar()
+		la.Mul(lj, aj[j])
+		p.CB[j] = commitTo(H, la, tj[j])
+	}
+
+	// coeffs[i][k] is the coefficient of x^k in P_i(x) = prod_j f_{j,i_j},
+	// where f_{j,1} = l_j*x + a_j and f_{j,0} = (1-l_j)*x - a_j. The leading
+	// coefficient of P_i is 1 exactly when i equals the secret index.
+	coeffs := polyCoefficients(N, n, bits, aj)
+
+	for k := 0; k < n; k++ {
+		d := Group.NewElement().MulGen(rhoK[k])
+		for i := 0; i < N; i++ {
+			d.Add(d, Group.NewElement().Mul(list[i], coeffs[i][k]))
+		}
+		p.CD[k] = d
+	}
+
+	x, err := challenge(list, p, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	for j := 0; j < n; j++ {
+		lj := Group.NewScalar()
+		lj.SetUint64(uint64(bits[j]))
+		p.F[j] = Group.NewScalar()
+		p.F[j].Mul(lj, x)
+		p.F[j].Add(p.F[j], aj[j])
+
+		p.ZA[j] = Group.NewScalar()
+		p.ZA[j].Mul(rj[j], x)
+		p.ZA[j].Add(p.ZA[j], sj[j])
+
+		xmf := Group.NewScalar()
+		xmf.Sub(x, p.F[j])
+		p.ZB[j] = Group.NewScalar()
+		p.ZB[j].Mul(rj[j], xmf)
+		p.ZB[j].Add(p.ZB[j], tj[j])
+	}
+
+	// zd = r*x^n - sum_k rho_k*x^k.
+	xk := Group.NewScalar()
+	xk.SetUint64(1)
+	p.ZD = Group.NewScalar()
+	for k := 0; k < n; k++ {
+		t := Group.NewScalar()
+		t.Mul(rhoK[k], xk)
+		p.ZD.Sub(p.ZD, t)
+		xk.Mul(xk, x)
+	}
+	t := Group.NewScalar()
+	t.Mul(r, xk)
+	p.ZD.Add(p.ZD, t)
+
+	return p, nil
+}
+
+// Verify checks a proof that some commitment in the list opens to zero.
+func Verify(list []group.Element, p *Proof, dst []byte) bool {
+	if len(list) == 0 {
+		return false
+	}
+	list, n := pad(list)
+	N := 1 << n
+	if len(p.CL) != n || len(p.CA) != n || len(p.CB) != n || len(p.CD) != n ||
+		len(p.F) != n || len(p.ZA) != n || len(p.ZB) != n || p.ZD == nil {
+		return false
+	}
+	H := generatorH(dst)
+
+	x, err := challenge(list, p, dst)
+	if err != nil {
+		return false
+	}
+
+	for j := 0; j < n; j++ {
+		// CL[j]^x * CA[j] == Com(f_j, za_j)
+		lhs := Group.NewElement().Mul(p.CL[j], x)
+		lhs.Add(lhs, p.CA[j])
+		if !lhs.IsEqual(commitTo(H, p.F[j], p.ZA[j])) {
+			return false
+		}
+		// CL[j]^(x-f_j) * CB[j] == Com(0, zb_j)
+		xmf := Group.NewScalar()
+		xmf.Sub(x, p.F[j])
+		lhs = Group.NewElement().Mul(p.CL[j], xmf)
+		lhs.Add(lhs, p.CB[j])
+		if !lhs.IsEqual(Group.NewElement().MulGen(p.ZB[j])) {
+			return false
+		}
+	}
+
+	// prod_i c_i^(prod_j f_{j,i_j}) * prod_k CD[k]^(-x^k) == Com(0, zd)
+	acc := Group.Identity()
+	for i := 0; i < N; i++ {
+		e := Group.NewScalar()
+		e.SetUint64(1)
+		for j := 0; j < n; j++ {
+			if (i>>j)&1 == 1 {
+				e.Mul(e, p.F[j])
+			} else {
+				xmf := Group.NewScalar()
+				xmf.Sub(x, p.F[j])
+				e.Mul(e, xmf)
+			}
+		}
+		acc.Add(acc, Group.NewElement().Mul(list[i], e))
+	}
+	xk := Group.NewScalar()
+	xk.SetUint64(1)
+	for k := 0; k < n; k++ {
+		neg := Group.NewScalar()
+		neg.Sub(neg, xk)
+		acc.Add(acc, Group.NewElement().Mul(p.CD[k], neg))
+		xk.Mul(xk, x)
+	}
+
+	return acc.IsEqual(Group.NewElement().MulGen(p.ZD))
+}
+
+func commitTo(H group.Element, m, r group.Scalar) group.Element {
+	c := Group.NewElement().MulGen(r)
+	c.Add(c, Group.NewElement().Mul(H, m))
+	return c
+}
+
+func pad(list []group.Element) ([]group.Element, int) {
+	n := 0
+	for (1 << n) < len(list) {
+		n++
+	}
+	if n == 0 {
+		n = 1
+	}
+	padded := make([]group.Element, 1<<n)
+	copy(padded, list)
+	for i := len(list); i < len(padded); i++ {
+		padded[i] = list[0]
+	}
+	return padded, n
+}
+
+// polyCoefficients expands P_i(x) for every i, returning the first n
+// coefficients of each (the leading coefficient is handled separately by
+// the verification equation).
+func polyCoefficients(N, n int, bits []uint, aj []group.Scalar) [][]group.Scalar {
+	coeffs := make([][]group.Scalar, N)
+	for i := 0; i < N; i++ {
+		// Start with the constant polynomial 1.
+		c := []group.Scalar{one()}
+		for j := 0; j < n; j++ {
+			var c0, c1 group.Scalar
+			if (i>>j)&1 == 1 {
+				c0 = aj[j].Copy()
+				c1 = Group.NewScalar()
+				c1.SetUint64(uint64(bits[j]))
+			} else {
+				c0 = Group.NewScalar()
+				c0.Sub(c0, aj[j])
+				c1 = Group.NewScalar()
+				c1.SetUint64(uint64(1 - bits[j]))
+			}
+			c = mulLinear(c, c0, c1)
+		}
+		coeffs[i] = c[:n]
+	}
+	return coeffs
+}
+
+func one() group.Scalar {
+	s := Group.NewScalar()
+	s.SetUint64(1)
+	return s
+}
+
+// mulLinear multiplies the polynomial c by (c1*x + c0).
+func mulLinear(c []group.Scalar, c0, c1 group.Scalar) []group.Scalar {
+	out := make([]group.Scalar, len(c)+1)
+	for k := range out {
+		out[k] = Group.NewScalar()
+	}
+	for k := range c {
+		t := Group.NewScalar()
+		t.Mul(c[k], c0)
+		out[k].Add(out[k], t)
+		t.Mul(c[k], c1)
+		out[k+1].Add(out[k+1], t)
+	}
+	return out
+}
+
+func challenge(list []group.Element, p *Proof, dst []byte) (group.Scalar, error) {
+	var buf []byte
+	for _, set := range [][]group.Element{list, p.CL, p.CA, p.CB, p.CD} {
+		for i := range set {
+			data, err := set[i].MarshalBinaryCompress()
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, data...)
+		}
+	}
+	return Group.HashToScalar(buf, dst), nil
+}
@@ -0,0 +1,95 @@
+package oneofmany_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/zk/oneofmany"
+)
+
+var testDST = []byte("oneofmany test")
+
+// newList returns n commitments of which the one at index opens to zero,
+// along with its blinding.
+func newList(t *testing.T, n, index int) ([]group.Element, group.Scalar) {
+	t.Helper()
+	g := oneofmany.Group
+	list := make([]group.Element, n)
+	var r group.Scalar
+	for i := range list {
+		ri := g.RandomScalar(rand.Reader)
+		m := g.RandomNonZeroScalar(rand.Reader)
+		if i == index {
+			m = g.NewScalar()
+			r = ri
+		}
+		list[i] = oneofmany.Commit(m, ri, testDST)
+	}
+	return list, r
+}
+
+func TestProveVerify(t *testing.T) {
+	for _, tc := range []struct{ n, index int }{
+		{1, 0}, {2, 1}, {4, 0}, {5, 3}, {8, 7}, {13, 12},
+	} {
+		list, r := newList(t, tc.n, tc.index)
+		proof, err := oneofmany.Prove(rand.Reader, list, tc.index, r, testDST)
+		if err != nil {
+			t.Fatalf("n=%d index=%d: %v", tc.n, tc.index, err)
+		}
+		if !oneofmany.Verify(list, proof, testDST) {
+			t.Fatalf("n=%d index=%d: valid proof rejected", tc.n, tc.index)
+		}
+		if oneofmany.Verify(list, proof, []byte("other dst")) {
+			t.Fatalf("n=%d index=%d: proof accepted under a different tag", tc.n, tc.index)
+		}
+
+		// A list where no commitment opens to zero must reject.
+		other, _ := newList(t, tc.n, (tc.index+1)%tc.n)
+		if oneofmany.Verify(other, proof, testDST) {
+			t.Fatalf("n=%d index=%d: proof accepted for a different list", tc.n, tc.index)
+		}
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	list, r := newList(t, 4, 2)
+	if _, err := oneofmany.Prove(rand.Reader, nil, 0, r, testDST); err == nil {
+		t.Fatal("empty list accepted")
+	}
+	if _, err := oneofmany.Prove(rand.Reader, list, 4, r, testDST); err == nil {
+		t.Fatal("out-of-range index accepted")
+	}
+
+	// Proving with the wrong index yields a proof that does not verify.
+	proof, err := oneofmany.Prove(rand.Reader, list, 1, r, testDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oneofmany.Verify(list, proof, testDST) {
+		t.Fatal("proof for a wrong index verifies")
+	}
+}
+
+func TestProofMarshal(t *testing.T) {
+	list, r := newList(t, 8, 5)
+	proof, err := oneofmany.Prove(rand.Reader, list, 5, r, testDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := proof.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec oneofmany.Proof
+	if err = dec.UnmarshalBinary(enc); err != nil {
+		t.Fatal(err)
+	}
+	if !oneofmany.Verify(list, &dec, testDST) {
+		t.Fatal("proof does not roundtrip")
+	}
+	if dec.UnmarshalBinary(enc[:len(enc)-1]) == nil {
+		t.Fatal("truncated proof accepted")
+	}
+}
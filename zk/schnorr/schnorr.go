@@ -0,0 +1,149 @@
+// Package schnorr provides Schnorr zero-knowledge proofs of knowledge of a
+// discrete logarithm, following RFC 8235.
+//
+// The prover shows knowledge of x such that X = x*G, where G is the group
+// generator, without revealing x. Both the interactive three-move protocol
+// and its non-interactive Fiat-Shamir variant are provided. The proof binds
+// an identifier of the prover and optional session information, which
+// prevents replaying proofs across parties or protocol runs; this is the
+// form used for key-ownership proofs in distributed key generation and
+// registration flows.
+package schnorr
+
+import (
+	"crypto"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Params configures provers and verifiers, which must agree on the group,
+// the challenge hash, and the domain separation tag.
+type Params struct {
+	G   group.Group
+	H   crypto.Hash
+	DST []byte
+}
+
+// Proof is a non-interactive proof of knowledge of a discrete logarithm.
+type Proof struct {
+	V group.Element // commitment
+	R group.Scalar  // response
+}
+
+// Commitment is the first message of the interactive protocol.
+type Commitment struct {
+	v group.Scalar
+	V group.Element
+}
+
+// Commit starts an interactive proof, returning the prover's state together
+// with the commitment V to send to the verifier.
+func (p Params) Commit(rnd io.Reader) *Commitment {
+	rnd = rand.Source(rnd)
+	v := p.G.RandomScalar(rnd)
+	return &Commitment{v: v, V: p.G.NewElement().MulGen(v)}
+}
+
+// Respond answers the verifier's challenge c for the secret x, completing
+// the interactive protocol.
+func (cm *Commitment) Respond(x, c group.Scalar) group.Scalar {
+	r := x.Group().NewScalar()
+	r.Mul(c, x)
+	r.Sub(cm.v, r)
+	return r
+}
+
+// VerifyInteractive checks the transcript (V, c, r) of an interactive run
+// against the public key X.
+func (p Params) VerifyInteractive(X, V group.Element, c, r group.Scalar) bool {
+	lhs := p.G.NewElement().MulGen(r)
+	lhs.Add(lhs, p.G.NewElement().Mul(X, c))
+	return lhs.IsEqual(V)
+}
+
+// Prove returns a non-interactive proof of knowledge of x, where X = x*G.
+// The proverID identifies the party producing the proof and otherInfo binds
+// optional session context; both may be nil by mutual agreement, and the
+// verifier must use identical values.
+func (p Params) Prove(rnd io.Reader, x group.Scalar, X group.Element, proverID, otherInfo []byte) (*Proof, error) {
+	rnd = rand.Source(rnd)
+	if !p.G.NewElement().MulGen(x).IsEqual(X) {
+		return nil, errors.New("schnorr: public key does not match secret")
+	}
+
+	cm := p.Commit(rnd)
+	c, err := p.challenge(X, cm.V, proverID, otherInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Proof{V: cm.V, R: cm.Respond(x, c)}, nil
+}
+
+// Verify checks a non-interactive proof for the public key X.
+func (p Params) Verify(X group.Element, proof *Proof, proverID, otherInfo []byte) bool {
+	c, err := p.challenge(X, proof.V, proverID, otherInfo)
+	if err != nil {
+		return false
+	}
+
+	return p.VerifyInteractive(X, proof.V, c, proof.R)
+}
+
+// challenge computes c = H(G || V || X || proverID || otherInfo) as in
+// RFC 8235, Section 2.3, mapped to a scalar of the group.
+func (p Params) challenge(X, V group.Element, proverID, otherInfo []byte) (group.Scalar, error) {
+	h := p.H.New()
+	for _, e := range []group.Element{p.G.Generator(), V, X} {
+		data, err := e.MarshalBinaryCompress()
+		if err != nil {
+			return nil, err
+		}
+		_, _ = h.Write(data)
+	}
+	_, _ = h.Write(proverID)
+	_, _ = h.Write(otherInfo)
+
+	return p.G.HashToScalar(h.Sum(nil), p.DST), nil
+}
+
+// proofFormat is the version byte leading a serialized proof.
+const proofFormat = 1
+
+// MarshalBinary encodes the proof as a format byte, the compressed
+// commitment, and the response scalar.
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	v, err := p.V.MarshalBinaryCompress()
+	if err != nil {
+		return nil, err
+	}
+	r, err := p.R.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append([]byte{proofFormat}, v...), r...), nil
+}
+
+// UnmarshalProof decodes a proof produced by Proof.MarshalBinary over the
+// parameters' group, rejecting trailing data.
+func (p Params) UnmarshalProof(data []byte) (*Proof, error) {
+	errMalformed := errors.New("schnorr: malformed proof")
+	ne := int(p.G.Params().CompressedElementLength)
+	ns := int(p.G.Params().ScalarLength)
+	if len(data) != 1+ne+ns || data[0] != proofFormat {
+		return nil, errMalformed
+	}
+	out := &Proof{V: p.G.NewElement(), R: p.G.NewScalar()}
+	if err := out.V.UnmarshalBinary(data[1 : 1+ne]); err != nil {
+		return nil, errMalformed
+	}
+	if err := out.R.UnmarshalBinary(data[1+ne:]); err != nil {
+		return nil, errMalformed
+	}
+
+	return out, nil
+}
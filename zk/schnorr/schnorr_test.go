@@ -0,0 +1,86 @@
+package schnorr_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	_ "crypto/sha256"
+	"testing"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/zk/schnorr"
+)
+
+func testParams(g group.Group) schnorr.Params {
+	return schnorr.Params{G: g, H: crypto.SHA256, DST: []byte("schnorr test")}
+}
+
+func TestProveVerify(t *testing.T) {
+	for name, g := range map[string]group.Group{
+		"P256":         group.P256,
+		"Ristretto255": group.Ristretto255,
+	} {
+		g := g
+		t.Run(name, func(t *testing.T) {
+			params := testParams(g)
+			x := g.RandomScalar(rand.Reader)
+			X := g.NewElement().MulGen(x)
+			id := []byte("alice")
+			info := []byte("session 1")
+
+			proof, err := params.Prove(rand.Reader, x, X, id, info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !params.Verify(X, proof, id, info) {
+				t.Fatal("valid proof rejected")
+			}
+			if params.Verify(X, proof, []byte("bob"), info) {
+				t.Fatal("proof accepted for a different prover identity")
+			}
+			if params.Verify(X, proof, id, []byte("session 2")) {
+				t.Fatal("proof accepted for a different session")
+			}
+			other := g.NewElement().MulGen(g.RandomScalar(rand.Reader))
+			if params.Verify(other, proof, id, info) {
+				t.Fatal("proof accepted for a different public key")
+			}
+
+			// A mismatched secret is rejected before proving.
+			if _, err = params.Prove(rand.Reader, g.RandomScalar(rand.Reader), X, id, info); err == nil {
+				t.Fatal("proof produced for a wrong secret")
+			}
+
+			enc, err := proof.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			dec, err := params.UnmarshalProof(enc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !params.Verify(X, dec, id, info) {
+				t.Fatal("proof does not roundtrip")
+			}
+			if _, err = params.UnmarshalProof(append(enc, 0)); err == nil {
+				t.Fatal("proof with trailing data accepted")
+			}
+		})
+	}
+}
+
+func TestInteractive(t *testing.T) {
+	params := testParams(group.Ristretto255)
+	g := params.G
+	x := g.RandomScalar(rand.Reader)
+	X := g.NewElement().MulGen(x)
+
+	cm := params.Commit(rand.Reader)
+	c := g.RandomScalar(rand.Reader)
+	r := cm.Respond(x, c)
+	if !params.VerifyInteractive(X, cm.V, c, r) {
+		t.Fatal("valid transcript rejected")
+	}
+	if params.VerifyInteractive(X, cm.V, g.RandomScalar(rand.Reader), r) {
+		t.Fatal("transcript accepted under a different challenge")
+	}
+}
@@ -0,0 +1,93 @@
+package sigma
+
+import (
+	"io"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/rand"
+)
+
+// Dlog is a sigma protocol proving knowledge of x such that X = x*G, the
+// basic Schnorr statement, usable as a leaf of And and Or compositions.
+// Witness may be nil on the verifier's side.
+type Dlog struct {
+	G       group.Group
+	X       group.Element
+	Witness group.Scalar
+	DST     []byte
+}
+
+// Label returns the statement description bound into challenges.
+func (d *Dlog) Label() []byte {
+	data, err := d.X.MarshalBinaryCompress()
+	if err != nil {
+		panic(err)
+	}
+	return append([]byte("sigma-dlog"), data...)
+}
+
+// ChallengeSize returns the scalar length of the group.
+func (d *Dlog) ChallengeSize() int { return int(d.G.Params().ScalarLength) }
+
+// Commit samples the nonce and returns T = t*G as commitment.
+func (d *Dlog) Commit(rnd io.Reader) ([]byte, []byte, error) {
+	rnd = rand.Source(rnd)
+	t := d.G.RandomScalar(rnd)
+	state, err := t.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	com, err := d.G.NewElement().MulGen(t).MarshalBinaryCompress()
+	if err != nil {
+		return nil, nil, err
+	}
+	return state, com, nil
+}
+
+// Respond returns z = t - c*x.
+func (d *Dlog) Respond(state, challenge []byte) ([]byte, error) {
+	t := d.G.NewScalar()
+	if err := t.UnmarshalBinary(state); err != nil {
+		return nil, err
+	}
+	z := d.G.NewScalar()
+	z.Mul(d.challengeScalar(challenge), d.Witness)
+	z.Sub(t, z)
+	return z.MarshalBinary()
+}
+
+// Verify checks that z*G + c*X equals the commitment.
+func (d *Dlog) Verify(commitment, challenge, response []byte) bool {
+	T := d.G.NewElement()
+	if err := T.UnmarshalBinary(commitment); err != nil {
+		return false
+	}
+	z := d.G.NewScalar()
+	if err := z.UnmarshalBinary(response); err != nil {
+		return false
+	}
+	lhs := d.G.NewElement().MulGen(z)
+	lhs.Add(lhs, d.G.NewElement().Mul(d.X, d.challengeScalar(challenge)))
+	return lhs.IsEqual(T)
+}
+
+// Simulate draws a random response and solves for the commitment.
+func (d *Dlog) Simulate(rnd io.Reader, challenge []byte) ([]byte, []byte, error) {
+	rnd = rand.Source(rnd)
+	z := d.G.RandomScalar(rnd)
+	T := d.G.NewElement().MulGen(z)
+	T.Add(T, d.G.NewElement().Mul(d.X, d.challengeScalar(challenge)))
+	com, err := T.MarshalBinaryCompress()
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := z.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	return com, resp, nil
+}
+
+func (d *Dlog) challengeScalar(challenge []byte) group.Scalar {
+	return d.G.HashToScalar(challenge, d.DST)
+}
@@ -0,0 +1,61 @@
+package sigma
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/cloudflare/circl/internal/conv"
+)
+
+// proofFormat is the version byte leading a serialized proof.
+const proofFormat = 1
+
+var errMalformedProof = errors.New("sigma: malformed proof")
+
+// MarshalBinary encodes the proof as a format byte followed by the
+// length-prefixed commitment and response.
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	out := []byte{proofFormat}
+	out = binary.AppendUvarint(out, uint64(len(p.Commitment)))
+	out = append(out, p.Commitment...)
+	out = binary.AppendUvarint(out, uint64(len(p.Response)))
+	out = append(out, p.Response...)
+
+	return out, nil
+}
+
+// UnmarshalBinary decodes a proof produced by MarshalBinary, rejecting
+// trailing data.
+func (p *Proof) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != proofFormat {
+		return errMalformedProof
+	}
+	in := data[1:]
+	com, in, err := readChunk(in)
+	if err != nil {
+		return err
+	}
+	resp, in, err := readChunk(in)
+	if err != nil || len(in) != 0 {
+		return errMalformedProof
+	}
+	p.Commitment, p.Response = com, resp
+
+	return nil
+}
+
+func readChunk(in []byte) ([]byte, []byte, error) {
+	n, k := binary.Uvarint(in)
+	if k <= 0 || uint64(len(in)-k) < n {
+		return nil, nil, errMalformedProof
+	}
+	in = in[k:]
+
+	return append([]byte{}, in[:n]...), in[n:], nil
+}
+
+// MarshalText encodes the proof in base64.
+func (p *Proof) MarshalText() ([]byte, error) { return conv.MarshalText(p) }
+
+// UnmarshalText decodes a proof produced by MarshalText.
+func (p *Proof) UnmarshalText(text []byte) error { return conv.UnmarshalText(p, text) }
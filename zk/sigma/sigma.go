@@ -0,0 +1,363 @@
+// Package sigma provides a small framework for composing sigma protocols.
+//
+// A sigma protocol is a three-move proof of knowledge: the prover sends a
+// commitment, the verifier a random challenge, and the prover a response.
+// Any protocol implementing the Protocol interface, and exposing a simulator
+// for its honest-verifier zero knowledge, can be combined with And and Or
+// composition: an AND proof shares one challenge among all branches, while
+// an OR proof splits the challenge so that all but the known branch can be
+// simulated. Composed protocols are again Protocols, so arbitrary monotone
+// formulas over statements (e.g., "I know the key for pk1 OR pk2") can be
+// assembled and then made non-interactive with the Fiat-Shamir transform.
+package sigma
+
+import (
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/rand"
+)
+
+// Protocol is one sigma protocol for a fixed public statement. Messages are
+// opaque byte strings owned by the implementation; challenges are uniform
+// byte strings of length ChallengeSize.
+type Protocol interface {
+	// Label returns a unique description of the statement being proven,
+	// bound into Fiat-Shamir challenges.
+	Label() []byte
+	// ChallengeSize returns the byte length of challenges.
+	ChallengeSize() int
+	// Commit samples the prover's randomness, returning its secret state
+	// and the commitment message.
+	Commit(rnd io.Reader) (state, commitment []byte, err error)
+	// Respond answers a challenge using the state from Commit and the
+	// prover's witness.
+	Respond(state, challenge []byte) (response []byte, err error)
+	// Verify checks a transcript.
+	Verify(commitment, challenge, response []byte) bool
+	// Simulate produces an accepting transcript for a given challenge
+	// without the witness, as guaranteed by honest-verifier zero knowledge.
+	Simulate(rnd io.Reader, challenge []byte) (commitment, response []byte, err error)
+}
+
+// ErrCompose is returned when composed branches disagree on parameters.
+var ErrCompose = errors.New("sigma: branches have different challenge sizes")
+
+// And returns the conjunction of the given protocols: a proof is valid only
+// if every branch verifies under the shared challenge. The prover must know
+// the witnesses of all branches.
+func And(branches ...Protocol) (Protocol, error) {
+	if err := sameChallenge(branches); err != nil {
+		return nil, err
+	}
+	return &andProto{branches}, nil
+}
+
+// Or returns the disjunction of the given protocols. The prover must know
+// the witness of the branch at index known; the remaining branches are
+// simulated. Verifiers learn nothing about which branch was real.
+func Or(known int, branches ...Protocol) (Protocol, error) {
+	if err := sameChallenge(branches); err != nil {
+		return nil, err
+	}
+	if known < 0 || known >= len(branches) {
+		return nil, errors.New("sigma: known branch out of range")
+	}
+	return &orProto{branches, known}, nil
+}
+
+func sameChallenge(branches []Protocol) error {
+	if len(branches) == 0 {
+		return errors.New("sigma: empty composition")
+	}
+	for i := range branches {
+		if branches[i].ChallengeSize() != branches[0].ChallengeSize() {
+			return ErrCompose
+		}
+	}
+	return nil
+}
+
+type andProto struct{ branches []Protocol }
+
+func (p *andProto) Label() []byte      { return composeLabel("and", p.branches) }
+func (p *andProto) ChallengeSize() int { return p.branches[0].ChallengeSize() }
+
+func (p *andProto) Commit(rnd io.Reader) ([]byte, []byte, error) {
+	var st, com []byte
+	for i := range p.branches {
+		s, c, err := p.branches[i].Commit(rnd)
+		if err != nil {
+			return nil, nil, err
+		}
+		st = appendChunk(st, s)
+		com = appendChunk(com, c)
+	}
+	return st, com, nil
+}
+
+func (p *andProto) Respond(state, challenge []byte) ([]byte, error) {
+	var resp []byte
+	for i := range p.branches {
+		var s []byte
+		var err error
+		s, state, err = splitChunk(state)
+		if err != nil {
+			return nil, err
+		}
+		r, err := p.branches[i].Respond(s, challenge)
+		if err != nil {
+			return nil, err
+		}
+		resp = appendChunk(resp, r)
+	}
+	return resp, nil
+}
+
+func (p *andProto) Verify(commitment, challenge, response []byte) bool {
+	for i := range p.branches {
+		var c, r []byte
+		var err error
+		if c, commitment, err = splitChunk(commitment); err != nil {
+			return false
+		}
+		if r, response, err = splitChunk(response); err != nil {
+			return false
+		}
+		if !p.branches[i].Verify(c, challenge, r) {
+			return false
+		}
+	}
+	return len(commitment) == 0 && len(response) == 0
+}
+
+func (p *andProto) Simulate(rnd io.Reader, challenge []byte) ([]byte, []byte, error) {
+	var com, resp []byte
+	for i := range p.branches {
+		c, r, err := p.branches[i].Simulate(rnd, challenge)
+		if err != nil {
+			return nil, nil, err
+		}
+		com = appendChunk(com, c)
+		resp = appendChunk(resp, r)
+	}
+	return com, resp, nil
+}
+
+type orProto struct {
+	branches []Protocol
+	known    int
+}
+
+func (p *orProto) Label() []byte      { return composeLabel("or", p.branches) }
+func (p *orProto) ChallengeSize() int { return p.branches[0].ChallengeSize() }
+
+// orState carries the real branch's state plus the simulated transcripts.
+type orState struct {
+	realState  []byte
+	challenges [][]byte
+	responses  [][]byte
+}
+
+func (p *orProto) Commit(rnd io.Reader) ([]byte, []byte, error) {
+	st := orState{challenges: make([][]byte, len(p.branches)), responses: make([][]byte, len(p.branches))}
+	var com []byte
+	for i := range p.branches {
+		if i == p.known {
+			s, c, err := p.branches[i].Commit(rnd)
+			if err != nil {
+				return nil, nil, err
+			}
+			st.realState = s
+			com = appendChunk(com, c)
+			continue
+		}
+		ci := make([]byte, p.ChallengeSize())
+		if _, err := io.ReadFull(rnd, ci); err != nil {
+			return nil, nil, err
+		}
+		c, r, err := p.branches[i].Simulate(rnd, ci)
+		if err != nil {
+			return nil, nil, err
+		}
+		st.challenges[i] = ci
+		st.responses[i] = r
+		com = appendChunk(com, c)
+	}
+
+	var enc []byte
+	enc = appendChunk(enc, st.realState)
+	for i := range p.branches {
+		enc = appendChunk(enc, st.challenges[i])
+		enc = appendChunk(enc, st.responses[i])
+	}
+	return enc, com, nil
+}
+
+func (p *orProto) Respond(state, challenge []byte) ([]byte, error) {
+	realState, state, err := splitChunk(state)
+	if err != nil {
+		return nil, err
+	}
+	challenges := make([][]byte, len(p.branches))
+	responses := make([][]byte, len(p.branches))
+	for i := range p.branches {
+		if challenges[i], state, err = splitChunk(state); err != nil {
+			return nil, err
+		}
+		if responses[i], state, err = splitChunk(state); err != nil {
+			return nil, err
+		}
+	}
+
+	// The real branch answers the challenge that makes all branch
+	// challenges XOR to the verifier's challenge.
+	ck := make([]byte, len(challenge))
+	copy(ck, challenge)
+	for i := range p.branches {
+		if i != p.known {
+			xorInto(ck, challenges[i])
+		}
+	}
+	challenges[p.known] = ck
+	if responses[p.known], err = p.branches[p.known].Respond(realState, ck); err != nil {
+		return nil, err
+	}
+
+	var resp []byte
+	for i := range p.branches {
+		resp = appendChunk(resp, challenges[i])
+		resp = appendChunk(resp, responses[i])
+	}
+	return resp, nil
+}
+
+func (p *orProto) Verify(commitment, challenge, response []byte) bool {
+	acc := make([]byte, p.ChallengeSize())
+	for i := range p.branches {
+		var com, ci, ri []byte
+		var err error
+		if com, commitment, err = splitChunk(commitment); err != nil {
+			return false
+		}
+		if ci, response, err = splitChunk(response); err != nil {
+			return false
+		}
+		if ri, response, err = splitChunk(response); err != nil {
+			return false
+		}
+		if len(ci) != len(acc) || !p.branches[i].Verify(com, ci, ri) {
+			return false
+		}
+		xorInto(acc, ci)
+	}
+	if len(commitment) != 0 || len(response) != 0 {
+		return false
+	}
+	xorInto(acc, challenge)
+	for _, b := range acc {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *orProto) Simulate(rnd io.Reader, challenge []byte) ([]byte, []byte, error) {
+	challenges := make([][]byte, len(p.branches))
+	last := make([]byte, len(challenge))
+	copy(last, challenge)
+	for i := 0; i < len(p.branches)-1; i++ {
+		challenges[i] = make([]byte, p.ChallengeSize())
+		if _, err := io.ReadFull(rnd, challenges[i]); err != nil {
+			return nil, nil, err
+		}
+		xorInto(last, challenges[i])
+	}
+	challenges[len(p.branches)-1] = last
+
+	var com, resp []byte
+	for i := range p.branches {
+		c, r, err := p.branches[i].Simulate(rnd, challenges[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		com = appendChunk(com, c)
+		resp = appendChunk(resp, challenges[i])
+		resp = appendChunk(resp, r)
+	}
+	return com, resp, nil
+}
+
+// Proof is a non-interactive proof produced by Prove.
+type Proof struct {
+	Commitment []byte
+	Response   []byte
+}
+
+// Prove runs the protocol non-interactively, deriving the challenge with the
+// Fiat-Shamir transform over the statement label and the commitment.
+func Prove(rnd io.Reader, p Protocol, h crypto.Hash, dst []byte) (*Proof, error) {
+	rnd = rand.Source(rnd)
+	state, com, err := p.Commit(rnd)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.Respond(state, fiatShamir(p, h, dst, com))
+	if err != nil {
+		return nil, err
+	}
+	return &Proof{Commitment: com, Response: resp}, nil
+}
+
+// Verify checks a non-interactive proof.
+func Verify(p Protocol, h crypto.Hash, dst []byte, proof *Proof) bool {
+	return p.Verify(proof.Commitment, fiatShamir(p, h, dst, proof.Commitment), proof.Response)
+}
+
+// fiatShamir derives a challenge by expanding the hash output until the
+// challenge length is reached.
+func fiatShamir(p Protocol, h crypto.Hash, dst, commitment []byte) []byte {
+	out := make([]byte, 0, p.ChallengeSize())
+	for counter := uint32(0); len(out) < p.ChallengeSize(); counter++ {
+		hh := h.New()
+		_, _ = hh.Write(dst)
+		_, _ = hh.Write(p.Label())
+		_, _ = hh.Write(commitment)
+		_ = binary.Write(hh, binary.BigEndian, counter)
+		out = append(out, hh.Sum(nil)...)
+	}
+	return out[:p.ChallengeSize()]
+}
+
+func composeLabel(op string, branches []Protocol) []byte {
+	label := []byte("sigma-" + op)
+	for i := range branches {
+		label = appendChunk(label, branches[i].Label())
+	}
+	return label
+}
+
+func appendChunk(dst, chunk []byte) []byte {
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(chunk)))
+	return append(dst, chunk...)
+}
+
+func splitChunk(data []byte) (chunk, rest []byte, err error) {
+	if len(data) < 4 {
+		return nil, nil, errors.New("sigma: malformed message")
+	}
+	n := binary.BigEndian.Uint32(data)
+	if uint64(len(data)-4) < uint64(n) {
+		return nil, nil, errors.New("sigma: malformed message")
+	}
+	return data[4 : 4+n], data[4+n:], nil
+}
+
+func xorInto(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
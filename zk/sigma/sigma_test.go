@@ -0,0 +1,157 @@
+package sigma_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	_ "crypto/sha256"
+	"testing"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/zk/sigma"
+)
+
+var (
+	testGroup = group.Ristretto255
+	testHash  = crypto.SHA256
+	testDST   = []byte("sigma test")
+)
+
+// newDlog returns a statement X = x*G with its witness, and the same
+// statement without the witness for the verifier's side.
+func newDlog(t *testing.T) (prover, verifier *sigma.Dlog) {
+	t.Helper()
+	x := testGroup.RandomScalar(rand.Reader)
+	X := testGroup.NewElement().MulGen(x)
+	prover = &sigma.Dlog{G: testGroup, X: X, Witness: x, DST: testDST}
+	verifier = &sigma.Dlog{G: testGroup, X: X, DST: testDST}
+	return
+}
+
+func TestDlog(t *testing.T) {
+	p, v := newDlog(t)
+	proof, err := sigma.Prove(rand.Reader, p, testHash, testDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sigma.Verify(v, testHash, testDST, proof) {
+		t.Fatal("valid proof rejected")
+	}
+	if sigma.Verify(v, testHash, []byte("other dst"), proof) {
+		t.Fatal("proof accepted under a different domain separation tag")
+	}
+
+	other, _ := newDlog(t)
+	if sigma.Verify(other, testHash, testDST, proof) {
+		t.Fatal("proof accepted for a different statement")
+	}
+}
+
+func TestAnd(t *testing.T) {
+	p1, v1 := newDlog(t)
+	p2, v2 := newDlog(t)
+
+	prover, err := sigma.And(p1, p2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := sigma.And(v1, v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proof, err := sigma.Prove(rand.Reader, prover, testHash, testDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sigma.Verify(verifier, testHash, testDST, proof) {
+		t.Fatal("valid conjunction rejected")
+	}
+
+	// A conjunction over a different second statement must reject.
+	_, v3 := newDlog(t)
+	wrong, err := sigma.And(v1, v3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sigma.Verify(wrong, testHash, testDST, proof) {
+		t.Fatal("conjunction accepted for a different statement")
+	}
+}
+
+func TestOr(t *testing.T) {
+	// The prover knows only the witness of the first branch; the second
+	// statement is one nobody knows a witness for.
+	p1, v1 := newDlog(t)
+	fake := &sigma.Dlog{
+		G:   testGroup,
+		X:   testGroup.RandomElement(rand.Reader),
+		DST: testDST,
+	}
+
+	for known, branches := range map[int][]sigma.Protocol{
+		0: {p1, fake},
+		1: {fake, p1},
+	} {
+		prover, err := sigma.Or(known, branches...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proof, err := sigma.Prove(rand.Reader, prover, testHash, testDST)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vBranches := make([]sigma.Protocol, len(branches))
+		for i, b := range branches {
+			if b == p1 {
+				vBranches[i] = v1
+			} else {
+				vBranches[i] = b
+			}
+		}
+		verifier, err := sigma.Or(0, vBranches...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sigma.Verify(verifier, testHash, testDST, proof) {
+			t.Fatalf("valid disjunction rejected (known branch %d)", known)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	_, v := newDlog(t)
+	challenge := make([]byte, v.ChallengeSize())
+	if _, err := rand.Read(challenge); err != nil {
+		t.Fatal(err)
+	}
+	com, resp, err := v.Simulate(rand.Reader, challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Verify(com, challenge, resp) {
+		t.Fatal("simulated transcript rejected")
+	}
+}
+
+func TestProofMarshal(t *testing.T) {
+	p, v := newDlog(t)
+	proof, err := sigma.Prove(rand.Reader, p, testHash, testDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := proof.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec sigma.Proof
+	if err = dec.UnmarshalBinary(enc); err != nil {
+		t.Fatal(err)
+	}
+	if !sigma.Verify(v, testHash, testDST, &dec) {
+		t.Fatal("proof does not roundtrip")
+	}
+	if dec.UnmarshalBinary(enc[:len(enc)-1]) == nil {
+		t.Fatal("truncated proof accepted")
+	}
+}